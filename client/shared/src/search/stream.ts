@@ -114,6 +114,10 @@ export interface MatchedSymbol {
     containerName: string
     kind: SymbolKind
     line: number
+    /** Only set for symbols read from precise code intelligence indexes. */
+    fullyQualifiedName?: string
+    package?: string
+    documentation?: string
 }
 
 type MarkdownText = string
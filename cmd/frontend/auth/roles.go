@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+// SyncRolesFromGroups assigns roles to the user based on the groups they belong
+// to, as reported by an authentication provider. roleGroups maps the name of a
+// role to the groups whose members are assigned the role. Roles previously
+// assigned to the user by the same source that their groups no longer entitle
+// them to are removed, including all of them if no role groups are configured.
+//
+// 🚨 SECURITY: The caller must ensure that groups were received from the
+// authentication provider identified by source.
+func SyncRolesFromGroups(ctx context.Context, db database.DB, userID int32, source string, roleGroups map[string][]string, groups map[string]bool) error {
+	var roles []string
+	for role, roleGroups := range roleGroups {
+		for _, group := range roleGroups {
+			if groups[group] {
+				roles = append(roles, role)
+				break
+			}
+		}
+	}
+	sort.Strings(roles)
+
+	return db.Roles().SetUserRolesForSource(ctx, userID, source, roles)
+}
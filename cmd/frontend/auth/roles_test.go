@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+func TestSyncRolesFromGroups(t *testing.T) {
+	ctx := context.Background()
+
+	roleGroups := map[string][]string{
+		"batch-changes-admin":     {"managers"},
+		"code-insights-editor":    {"analysts", "managers"},
+		"executor-secret-manager": {"security"},
+	}
+
+	t.Run("assigns roles of the user's groups", func(t *testing.T) {
+		roles := database.NewMockRoleStore()
+		db := database.NewMockDB()
+		db.RolesFunc.SetDefaultReturn(roles)
+
+		err := SyncRolesFromGroups(ctx, db, 1, "saml:idp", roleGroups, map[string]bool{"managers": true, "engineers": true})
+		require.NoError(t, err)
+
+		mockrequire.CalledOnce(t, roles.SetUserRolesForSourceFunc)
+		call := roles.SetUserRolesForSourceFunc.History()[0]
+		assert.Equal(t, int32(1), call.Arg1)
+		assert.Equal(t, "saml:idp", call.Arg2)
+		assert.Equal(t, []string{"batch-changes-admin", "code-insights-editor"}, call.Arg3)
+	})
+
+	t.Run("removes roles if the user has no matching groups", func(t *testing.T) {
+		roles := database.NewMockRoleStore()
+		db := database.NewMockDB()
+		db.RolesFunc.SetDefaultReturn(roles)
+
+		err := SyncRolesFromGroups(ctx, db, 1, "saml:idp", roleGroups, nil)
+		require.NoError(t, err)
+
+		mockrequire.CalledOnce(t, roles.SetUserRolesForSourceFunc)
+		assert.Empty(t, roles.SetUserRolesForSourceFunc.History()[0].Arg3)
+	})
+
+	t.Run("removes roles without role groups", func(t *testing.T) {
+		roles := database.NewMockRoleStore()
+		db := database.NewMockDB()
+		db.RolesFunc.SetDefaultReturn(roles)
+
+		err := SyncRolesFromGroups(ctx, db, 1, "saml:idp", nil, map[string]bool{"managers": true})
+		require.NoError(t, err)
+
+		mockrequire.CalledOnce(t, roles.SetUserRolesForSourceFunc)
+		call := roles.SetUserRolesForSourceFunc.History()[0]
+		assert.Equal(t, "saml:idp", call.Arg2)
+		assert.Empty(t, call.Arg3)
+	})
+}
@@ -78,8 +78,11 @@ func (s *repos) Get(ctx context.Context, repo api.RepoID) (_ *types.Repo, err er
 	return s.store.Get(ctx, repo)
 }
 
-// GetByName retrieves the repository with the given name. It will lazy sync a repo
-// not yet present in the database under certain conditions. See repos.Syncer.SyncRepo.
+// GetByName retrieves the repository with the given name. If no repository has
+// that name but one was renamed away from it, the renamed repository is
+// returned. The name of a virtual repository resolves to its parent repository.
+// It will lazy sync a repo not yet present in the database under certain
+// conditions. See repos.Syncer.SyncRepo.
 func (s *repos) GetByName(ctx context.Context, name api.RepoName) (_ *types.Repo, err error) {
 	if Mocks.Repos.GetByName != nil {
 		return Mocks.Repos.GetByName(ctx, name)
@@ -97,6 +100,21 @@ func (s *repos) GetByName(ctx context.Context, name api.RepoName) (_ *types.Repo
 		return nil, err
 	}
 
+	// The repo may have been renamed on the code host since the name was last
+	// used, in which case we resolve it to the repo under its current name.
+	if id, herr := s.db.RepoNameHistory().GetRepoIDByPreviousName(ctx, name); herr == nil {
+		return s.store.Get(ctx, id)
+	} else if !errcode.IsNotFound(herr) {
+		return nil, herr
+	}
+
+	// Virtual repositories share the tree of their parent repository.
+	if vr, verr := s.db.VirtualRepos().GetByName(ctx, name); verr == nil {
+		return s.store.Get(ctx, vr.Parent.ID)
+	} else if !errcode.IsNotFound(verr) {
+		return nil, verr
+	}
+
 	if errcode.IsNotFound(err) && !envvar.SourcegraphDotComMode() {
 		// The repo doesn't exist and we're not on sourcegraph.com, we should not lazy
 		// clone it.
@@ -46,6 +46,27 @@ func TestReposService_Get(t *testing.T) {
 	require.Equal(t, wantRepo, repo)
 }
 
+func TestReposService_GetByName_Renamed(t *testing.T) {
+	t.Parallel()
+
+	wantRepo := &types.Repo{ID: 1, Name: "github.com/u/new"}
+
+	repoStore := database.NewMockRepoStore()
+	repoStore.GetByNameFunc.SetDefaultReturn(nil, &database.RepoNotFoundErr{Name: "github.com/u/old"})
+	repoStore.GetFunc.SetDefaultReturn(wantRepo, nil)
+	historyStore := database.NewMockRepoNameHistoryStore()
+	historyStore.GetRepoIDByPreviousNameFunc.SetDefaultReturn(wantRepo.ID, nil)
+	db := database.NewMockDB()
+	db.RepoNameHistoryFunc.SetDefaultReturn(historyStore)
+	s := &repos{db: db, store: repoStore}
+
+	repo, err := s.GetByName(context.Background(), "github.com/u/old")
+	require.NoError(t, err)
+	mockrequire.CalledOnceWith(t, historyStore.GetRepoIDByPreviousNameFunc, mockrequire.Values(mockrequire.Skip, api.RepoName("github.com/u/old")))
+	mockrequire.CalledOnceWith(t, repoStore.GetFunc, mockrequire.Values(mockrequire.Skip, wantRepo.ID))
+	require.Equal(t, wantRepo, repo)
+}
+
 func TestReposService_List(t *testing.T) {
 	t.Parallel()
 
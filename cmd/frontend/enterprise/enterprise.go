@@ -27,8 +27,16 @@ type Services struct {
 	BatchesChangesFileExistsHandler http.Handler
 	BatchesChangesFileUploadHandler http.Handler
 
+	// Explicit permissions Services
+	ExplicitPermissionsBulkImportUploadHandler http.Handler
+	ExplicitPermissionsBulkImportGetHandler    http.Handler
+
+	// SCIM Services
+	SCIMHandler http.Handler
+
 	GitHubSyncWebhook           webhooks.Registerer
 	NewCodeIntelUploadHandler   NewCodeIntelUploadHandler
+	CodeIntelCacheHandler       http.Handler
 	RankingService              RankingService
 	NewExecutorProxyHandler     NewExecutorProxyHandler
 	NewGitHubAppSetupHandler    NewGitHubAppSetupHandler
@@ -73,19 +81,23 @@ type NewComputeStreamHandler func() http.Handler
 // DefaultServices creates a new Services value that has default implementations for all services.
 func DefaultServices() Services {
 	return Services{
-		GitHubSyncWebhook:               &emptyWebhookHandler{name: "github sync webhook"},
-		BatchesGitHubWebhook:            &emptyWebhookHandler{name: "batches github webhook"},
-		BatchesGitLabWebhook:            &emptyWebhookHandler{name: "batches gitlab webhook"},
-		BatchesBitbucketServerWebhook:   &emptyWebhookHandler{name: "batches bitbucket server webhook"},
-		BatchesBitbucketCloudWebhook:    &emptyWebhookHandler{name: "batches bitbucket cloud webhook"},
-		BatchesChangesFileGetHandler:    makeNotFoundHandler("batches file get handler"),
-		BatchesChangesFileExistsHandler: makeNotFoundHandler("batches file exists handler"),
-		BatchesChangesFileUploadHandler: makeNotFoundHandler("batches file upload handler"),
-		NewCodeIntelUploadHandler:       func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
-		RankingService:                  stubRankingService{},
-		NewExecutorProxyHandler:         func() http.Handler { return makeNotFoundHandler("executor proxy") },
-		NewGitHubAppSetupHandler:        func() http.Handler { return makeNotFoundHandler("Sourcegraph GitHub App setup") },
-		NewComputeStreamHandler:         func() http.Handler { return makeNotFoundHandler("compute streaming endpoint") },
+		GitHubSyncWebhook:                          &emptyWebhookHandler{name: "github sync webhook"},
+		BatchesGitHubWebhook:                       &emptyWebhookHandler{name: "batches github webhook"},
+		BatchesGitLabWebhook:                       &emptyWebhookHandler{name: "batches gitlab webhook"},
+		BatchesBitbucketServerWebhook:              &emptyWebhookHandler{name: "batches bitbucket server webhook"},
+		BatchesBitbucketCloudWebhook:               &emptyWebhookHandler{name: "batches bitbucket cloud webhook"},
+		BatchesChangesFileGetHandler:               makeNotFoundHandler("batches file get handler"),
+		BatchesChangesFileExistsHandler:            makeNotFoundHandler("batches file exists handler"),
+		BatchesChangesFileUploadHandler:            makeNotFoundHandler("batches file upload handler"),
+		ExplicitPermissionsBulkImportUploadHandler: makeNotFoundHandler("explicit permissions bulk import upload handler"),
+		ExplicitPermissionsBulkImportGetHandler:    makeNotFoundHandler("explicit permissions bulk import get handler"),
+		SCIMHandler:                                makeNotFoundHandler("SCIM API"),
+		NewCodeIntelUploadHandler:                  func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
+		CodeIntelCacheHandler:                      makeNotFoundHandler("code intel cache handler"),
+		RankingService:                             stubRankingService{},
+		NewExecutorProxyHandler:                    func() http.Handler { return makeNotFoundHandler("executor proxy") },
+		NewGitHubAppSetupHandler:                   func() http.Handler { return makeNotFoundHandler("Sourcegraph GitHub App setup") },
+		NewComputeStreamHandler:                    func() http.Handler { return makeNotFoundHandler("compute streaming endpoint") },
 	}
 }
 
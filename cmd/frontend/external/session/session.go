@@ -10,4 +10,5 @@ var (
 	SetData                 = session.SetData
 	GetData                 = session.GetData
 	InvalidateSessionsByIDs = session.InvalidateSessionsByIDs
+	SessionIDFromContext    = session.SessionIDFromContext
 )
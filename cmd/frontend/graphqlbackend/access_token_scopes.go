@@ -0,0 +1,229 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+// scopeAnyToken is used for fields that can be resolved with an access token of
+// any scope.
+const scopeAnyToken = ""
+
+// scopeSettingsRead is used for fields that only read settings. It is not an
+// access token scope itself: settings determine how searches run and how code
+// is displayed, so they can be read with any of settingsReadScopes.
+const scopeSettingsRead = "settings:read"
+
+var settingsReadScopes = []string{authz.ScopeSearchRead, authz.ScopeRepoRead, authz.ScopeSettingsWrite}
+
+// typeScopes are the access token scopes required to resolve the fields of the
+// given types, wherever they appear in a query. Fields of types that aren't
+// listed can be resolved with an access token of any scope, since they can only
+// be reached through fields that are checked themselves.
+var typeScopes = map[string]string{
+	"Query":    authz.ScopeUserAll,
+	"Mutation": authz.ScopeUserAll,
+
+	// Users are reachable from many places, for example as the author of a
+	// commit, but only their public profile is readable without user:all.
+	"User": authz.ScopeUserAll,
+
+	"BatchChange":                      authz.ScopeBatchesWrite,
+	"BatchChangeConnection":            authz.ScopeBatchesWrite,
+	"BatchChangesCodeHost":             authz.ScopeBatchesWrite,
+	"BatchChangesCodeHostConnection":   authz.ScopeBatchesWrite,
+	"BatchChangesCredential":           authz.ScopeBatchesWrite,
+	"BatchSpec":                        authz.ScopeBatchesWrite,
+	"BatchSpecConnection":              authz.ScopeBatchesWrite,
+	"BatchSpecWorkspaceConnection":     authz.ScopeBatchesWrite,
+	"BatchSpecWorkspaceFile":           authz.ScopeBatchesWrite,
+	"BatchSpecWorkspaceFileConnection": authz.ScopeBatchesWrite,
+	"BulkOperation":                    authz.ScopeBatchesWrite,
+	"BulkOperationConnection":          authz.ScopeBatchesWrite,
+	"ChangesetConnection":              authz.ScopeBatchesWrite,
+	"ChangesetEvent":                   authz.ScopeBatchesWrite,
+	"ChangesetEventConnection":         authz.ScopeBatchesWrite,
+	"ChangesetSpecConnection":          authz.ScopeBatchesWrite,
+	"ChangesetsStats":                  authz.ScopeBatchesWrite,
+	"ExternalChangeset":                authz.ScopeBatchesWrite,
+	"GlobalChangesetsStats":            authz.ScopeBatchesWrite,
+	"HiddenBatchSpecWorkspace":         authz.ScopeBatchesWrite,
+	"HiddenChangesetSpec":              authz.ScopeBatchesWrite,
+	"HiddenExternalChangeset":          authz.ScopeBatchesWrite,
+	"RepoChangesetsStats":              authz.ScopeBatchesWrite,
+	"VisibleBatchSpecWorkspace":        authz.ScopeBatchesWrite,
+	"VisibleChangesetSpec":             authz.ScopeBatchesWrite,
+}
+
+// fieldScopes are the access token scopes required to resolve individual
+// fields, keyed by "Type.field". They take precedence over typeScopes.
+var fieldScopes = map[string]string{
+	"Query.__schema":    scopeAnyToken,
+	"Query.__type":      scopeAnyToken,
+	"Query.currentUser": scopeAnyToken,
+
+	"Query.search":                    authz.ScopeSearchRead,
+	"Query.parseSearchQuery":          authz.ScopeSearchRead,
+	"Query.compute":                   authz.ScopeSearchRead,
+	"Query.searchContexts":            authz.ScopeSearchRead,
+	"Query.searchContextBySpec":       authz.ScopeSearchRead,
+	"Query.autoDefinedSearchContexts": authz.ScopeSearchRead,
+	"Query.isSearchContextAvailable":  authz.ScopeSearchRead,
+
+	"Query.repository":         authz.ScopeRepoRead,
+	"Query.repositoryRedirect": authz.ScopeRepoRead,
+	"Query.repositories":       authz.ScopeRepoRead,
+	"Query.highlightCode":      authz.ScopeRepoRead,
+
+	"Query.batchChange":                   authz.ScopeBatchesWrite,
+	"Query.batchChanges":                  authz.ScopeBatchesWrite,
+	"Query.batchSpecs":                    authz.ScopeBatchesWrite,
+	"Query.globalChangesetsStats":         authz.ScopeBatchesWrite,
+	"Query.batchChangesCodeHosts":         authz.ScopeBatchesWrite,
+	"Query.availableBulkOperations":       authz.ScopeBatchesWrite,
+	"Query.checkBatchChangesCredential":   authz.ScopeBatchesWrite,
+	"Query.resolveWorkspacesForBatchSpec": authz.ScopeBatchesWrite,
+	"Query.maxUnlicensedChangesets":       authz.ScopeBatchesWrite,
+
+	"Query.settingsSubject": scopeSettingsRead,
+	"Query.viewerSettings":  scopeSettingsRead,
+
+	"Mutation.createChangesetSpec":                authz.ScopeBatchesWrite,
+	"Mutation.syncChangeset":                      authz.ScopeBatchesWrite,
+	"Mutation.reenqueueChangeset":                 authz.ScopeBatchesWrite,
+	"Mutation.createBatchChange":                  authz.ScopeBatchesWrite,
+	"Mutation.createBatchSpec":                    authz.ScopeBatchesWrite,
+	"Mutation.createEmptyBatchChange":             authz.ScopeBatchesWrite,
+	"Mutation.upsertEmptyBatchChange":             authz.ScopeBatchesWrite,
+	"Mutation.createBatchSpecFromRaw":             authz.ScopeBatchesWrite,
+	"Mutation.replaceBatchSpecInput":              authz.ScopeBatchesWrite,
+	"Mutation.upsertBatchSpecInput":               authz.ScopeBatchesWrite,
+	"Mutation.deleteBatchSpec":                    authz.ScopeBatchesWrite,
+	"Mutation.executeBatchSpec":                   authz.ScopeBatchesWrite,
+	"Mutation.applyBatchChange":                   authz.ScopeBatchesWrite,
+	"Mutation.closeBatchChange":                   authz.ScopeBatchesWrite,
+	"Mutation.moveBatchChange":                    authz.ScopeBatchesWrite,
+	"Mutation.deleteBatchChange":                  authz.ScopeBatchesWrite,
+	"Mutation.detachChangesets":                   authz.ScopeBatchesWrite,
+	"Mutation.createChangesetComments":            authz.ScopeBatchesWrite,
+	"Mutation.reenqueueChangesets":                authz.ScopeBatchesWrite,
+	"Mutation.mergeChangesets":                    authz.ScopeBatchesWrite,
+	"Mutation.closeChangesets":                    authz.ScopeBatchesWrite,
+	"Mutation.publishChangesets":                  authz.ScopeBatchesWrite,
+	"Mutation.cancelBatchSpecExecution":           authz.ScopeBatchesWrite,
+	"Mutation.cancelBatchSpecWorkspaceExecution":  authz.ScopeBatchesWrite,
+	"Mutation.retryBatchSpecWorkspaceExecution":   authz.ScopeBatchesWrite,
+	"Mutation.retryBatchSpecExecution":            authz.ScopeBatchesWrite,
+	"Mutation.enqueueBatchSpecWorkspaceExecution": authz.ScopeBatchesWrite,
+	"Mutation.toggleBatchSpecAutoApply":           authz.ScopeBatchesWrite,
+
+	"Mutation.settingsMutation":      authz.ScopeSettingsWrite,
+	"Mutation.configurationMutation": authz.ScopeSettingsWrite,
+
+	"User.id":            scopeAnyToken,
+	"User.databaseID":    scopeAnyToken,
+	"User.username":      scopeAnyToken,
+	"User.displayName":   scopeAnyToken,
+	"User.avatarURL":     scopeAnyToken,
+	"User.url":           scopeAnyToken,
+	"User.namespaceName": scopeAnyToken,
+
+	"User.latestSettings":       scopeSettingsRead,
+	"User.settingsCascade":      scopeSettingsRead,
+	"User.configurationCascade": scopeSettingsRead,
+	"User.settingsURL":          scopeSettingsRead,
+	"User.viewerCanAdminister":  scopeSettingsRead,
+
+	"Org.latestSettings":       scopeSettingsRead,
+	"Org.settingsCascade":      scopeSettingsRead,
+	"Org.configurationCascade": scopeSettingsRead,
+
+	"Site.latestSettings":       scopeSettingsRead,
+	"Site.settingsCascade":      scopeSettingsRead,
+	"Site.configurationCascade": scopeSettingsRead,
+	"Site.configuration":        authz.ScopeUserAll,
+}
+
+// nodeKindScopes are the access token scopes required to look up nodes of the
+// given kinds with the node field. Other kinds require authz.ScopeUserAll.
+var nodeKindScopes = map[string]string{
+	"Repository":        authz.ScopeRepoRead,
+	"VirtualRepository": authz.ScopeRepoRead,
+	"SearchContext":     authz.ScopeSearchRead,
+
+	"BatchChange":            authz.ScopeBatchesWrite,
+	"BatchChangesCredential": authz.ScopeBatchesWrite,
+	"BatchSpec":              authz.ScopeBatchesWrite,
+	"BatchSpecWorkspace":     authz.ScopeBatchesWrite,
+	"BatchSpecWorkspaceFile": authz.ScopeBatchesWrite,
+	"BulkOperation":          authz.ScopeBatchesWrite,
+	"Changeset":              authz.ScopeBatchesWrite,
+	"ChangesetEvent":         authz.ScopeBatchesWrite,
+	"ChangesetSpec":          authz.ScopeBatchesWrite,
+}
+
+// requiredFieldScope returns the access token scope required to resolve the
+// given field, or scopeAnyToken if it can be resolved with an access token of
+// any scope.
+func requiredFieldScope(typeName, fieldName string, args map[string]any) string {
+	if typeName == "Query" && fieldName == "node" {
+		id, _ := args["id"].(string)
+		if scope, ok := nodeKindScopes[relay.UnmarshalKind(graphql.ID(id))]; ok {
+			return scope
+		}
+		return authz.ScopeUserAll
+	}
+
+	if scope, ok := fieldScopes[typeName+"."+fieldName]; ok {
+		return scope
+	}
+	if scope, ok := typeScopes[typeName]; ok {
+		return scope
+	}
+	return scopeAnyToken
+}
+
+// checkFieldScope returns an *authz.ErrScopeRequired if the actor in the
+// context is authenticated with an access token that doesn't grant the scope
+// required to resolve the given field.
+//
+// 🚨 SECURITY: This is called by requestTracer before every field is resolved,
+// at any depth of the query, so that access tokens can only be used for the
+// fields their scopes grant.
+func checkFieldScope(ctx context.Context, typeName, fieldName string, args map[string]any) error {
+	if actor.FromContext(ctx).AccessTokenScopes == nil {
+		// Not authenticated with an access token, so there is nothing to check.
+		return nil
+	}
+
+	switch scope := requiredFieldScope(typeName, fieldName, args); scope {
+	case scopeAnyToken:
+		return nil
+	case scopeSettingsRead:
+		return authz.CheckAnyScope(ctx, settingsReadScopes...)
+	default:
+		return authz.CheckScope(ctx, scope)
+	}
+}
+
+// scopeRequiredContext is returned by requestTracer for fields that the access
+// token of the actor doesn't grant. graphql-go doesn't call the resolver of a
+// field whose context is done, and reports the error of the context instead.
+type scopeRequiredContext struct {
+	context.Context
+	err error
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+func (c scopeRequiredContext) Done() <-chan struct{} { return closedChan }
+func (c scopeRequiredContext) Err() error            { return c.err }
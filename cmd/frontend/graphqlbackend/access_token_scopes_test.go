@@ -0,0 +1,130 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRequiredFieldScope(t *testing.T) {
+	tests := []struct {
+		typeName  string
+		fieldName string
+		args      map[string]any
+		want      string
+	}{
+		{typeName: "Query", fieldName: "currentUser", want: scopeAnyToken},
+		{typeName: "Query", fieldName: "search", want: authz.ScopeSearchRead},
+		{typeName: "Query", fieldName: "site", want: authz.ScopeUserAll},
+		{typeName: "Mutation", fieldName: "applyBatchChange", want: authz.ScopeBatchesWrite},
+		{typeName: "Mutation", fieldName: "updateSiteConfiguration", want: authz.ScopeUserAll},
+
+		// Nested fields are checked wherever they appear.
+		{typeName: "User", fieldName: "username", want: scopeAnyToken},
+		{typeName: "User", fieldName: "emails", want: authz.ScopeUserAll},
+		{typeName: "User", fieldName: "latestSettings", want: scopeSettingsRead},
+		{typeName: "Query", fieldName: "viewerSettings", want: scopeSettingsRead},
+		{typeName: "Mutation", fieldName: "settingsMutation", want: authz.ScopeSettingsWrite},
+		{typeName: "BatchChange", fieldName: "name", want: authz.ScopeBatchesWrite},
+		{typeName: "Repository", fieldName: "name", want: scopeAnyToken},
+
+		// Nodes are checked by kind.
+		{typeName: "Query", fieldName: "node", args: map[string]any{"id": string(relay.MarshalID("Repository", 1))}, want: authz.ScopeRepoRead},
+		{typeName: "Query", fieldName: "node", args: map[string]any{"id": string(relay.MarshalID("BatchChange", 1))}, want: authz.ScopeBatchesWrite},
+		{typeName: "Query", fieldName: "node", args: map[string]any{"id": string(relay.MarshalID("AccessToken", 1))}, want: authz.ScopeUserAll},
+		{typeName: "Query", fieldName: "node", want: authz.ScopeUserAll},
+	}
+
+	for _, test := range tests {
+		if have := requiredFieldScope(test.typeName, test.fieldName, test.args); have != test.want {
+			t.Errorf("%s.%s(%v): want scope %q, have %q", test.typeName, test.fieldName, test.args, test.want, have)
+		}
+	}
+}
+
+func TestAccessTokenScopes(t *testing.T) {
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, Username: "alice"}, nil)
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	schema := mustParseGraphQLSchema(t, db)
+
+	withScopes := func(scopes ...string) context.Context {
+		return actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: scopes})
+	}
+
+	RunTests(t, []*Test{
+		{
+			Label:          "public fields of the current user",
+			Context:        withScopes(authz.ScopeSearchRead),
+			Schema:         schema,
+			Query:          `{ currentUser { username } }`,
+			ExpectedResult: `{"currentUser": {"username": "alice"}}`,
+		},
+		{
+			Label:          "nested field requiring another scope",
+			Context:        withScopes(authz.ScopeSearchRead),
+			Schema:         schema,
+			Query:          `{ currentUser { username updatedAt } }`,
+			ExpectedResult: `{"currentUser": {"username": "alice", "updatedAt": null}}`,
+			ExpectedErrors: []*gqlerrors.QueryError{{
+				Message: `access token does not have the scope "user:all" required for this action`,
+			}},
+		},
+		{
+			Label:          "settings readable with a read scope",
+			Context:        withScopes(authz.ScopeSearchRead),
+			Schema:         schema,
+			Query:          `{ currentUser { settingsURL } }`,
+			ExpectedResult: `{"currentUser": {"settingsURL": "/users/alice/settings"}}`,
+		},
+		{
+			Label:          "settings not readable without a read scope",
+			Context:        withScopes(authz.ScopeBatchesWrite),
+			Schema:         schema,
+			Query:          `{ currentUser { username settingsURL } }`,
+			ExpectedResult: `{"currentUser": {"username": "alice", "settingsURL": null}}`,
+			ExpectedErrors: []*gqlerrors.QueryError{{
+				Message: `access token does not have any of the scopes "search:read", "repo:read", "settings:write" required for this action`,
+			}},
+		},
+		{
+			Label:          "nested field granted by the scope",
+			Context:        withScopes(authz.ScopeSettingsWrite),
+			Schema:         schema,
+			Query:          `{ currentUser { settingsURL } }`,
+			ExpectedResult: `{"currentUser": {"settingsURL": "/users/alice/settings"}}`,
+		},
+		{
+			Label:          "node of a kind requiring another scope",
+			Context:        withScopes(authz.ScopeSearchRead),
+			Schema:         schema,
+			Query:          `{ node(id: "VXNlcjox") { id } }`,
+			ExpectedResult: `{"node": null}`,
+			ExpectedErrors: []*gqlerrors.QueryError{{
+				Message: `access token does not have the scope "user:all" required for this action`,
+			}},
+		},
+		{
+			Label:          "user:all grants everything",
+			Context:        withScopes(authz.ScopeUserAll),
+			Schema:         schema,
+			Query:          `{ currentUser { username settingsURL } }`,
+			ExpectedResult: `{"currentUser": {"username": "alice", "settingsURL": "/users/alice/settings"}}`,
+		},
+		{
+			Label:          "not authenticated with an access token",
+			Context:        actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:         schema,
+			Query:          `{ currentUser { username settingsURL } }`,
+			ExpectedResult: `{"currentUser": {"username": "alice", "settingsURL": "/users/alice/settings"}}`,
+		},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 
@@ -17,28 +18,54 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User          graphql.ID
+	Scopes        []string
+	Note          string
+	ExpiresInDays *int32
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
-	// 🚨 SECURITY: Creating access tokens for any user by site admins is not
-	// allowed on Sourcegraph.com. This check is mostly the defense for a
-	// misconfiguration of the site configuration.
-	if envvar.SourcegraphDotComMode() && conf.AccessTokensAllow() == conf.AccessTokensAdmin {
-		return nil, errors.Errorf("access token configuration value %q is disabled on Sourcegraph.com", conf.AccessTokensAllow())
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := UnmarshalUserID(args.User)
+	// 🚨 SECURITY: Check that the current user may create an access token with these scopes for
+	// the user.
+	if err := r.checkCanCreateAccessToken(ctx, userID, args.Scopes); err != nil {
+		return nil, err
+	}
+	expiresAt, err := accessTokenExpiresAt(args.ExpiresInDays)
 	if err != nil {
 		return nil, err
 	}
 
+	id, token, err := r.db.AccessTokens().Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID, expiresAt)
+
+	if conf.CanSendEmail() {
+		if err := backend.UserEmails.SendUserEmailOnFieldUpdate(ctx, r.logger, r.db, userID, "created an access token"); err != nil {
+			r.logger.Warn("Failed to send email to inform user of access token creation", log.Error(err))
+		}
+	}
+
+	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
+}
+
+// checkCanCreateAccessToken returns an error if the current user may not create an access token
+// with the given scopes for the user. The scopes are sorted in place.
+func (r *schemaResolver) checkCanCreateAccessToken(ctx context.Context, userID int32, scopes []string) error {
+	// 🚨 SECURITY: Creating access tokens for any user by site admins is not
+	// allowed on Sourcegraph.com. This check is mostly the defense for a
+	// misconfiguration of the site configuration.
+	if envvar.SourcegraphDotComMode() && conf.AccessTokensAllow() == conf.AccessTokensAdmin {
+		return errors.Errorf("access token configuration value %q is disabled on Sourcegraph.com", conf.AccessTokensAllow())
+	}
+
 	switch conf.AccessTokensAllow() {
 	case conf.AccessTokensAll:
 		// 🚨 SECURITY: Only the current logged in user should be able to create a token
@@ -46,56 +73,124 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		// then use the token to impersonate a user and gain access to their private
 		// code.
 		if err := auth.CheckSameUser(ctx, userID); err != nil {
-			return nil, err
+			return err
 		}
 	case conf.AccessTokensAdmin:
 		// 🚨 SECURITY: The site has opted in to only allow site admins to create access
 		// tokens. In this case, they can create a token for any user.
 		if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
-			return nil, errors.New("Access token creation has been restricted to admin users. Contact an admin user to create a new access token.")
+			return errors.New("Access token creation has been restricted to admin users. Contact an admin user to create a new access token.")
 		}
 	case conf.AccessTokensNone:
 	default:
-		return nil, errors.New("Access token creation is disabled. Contact an admin user to enable.")
+		return errors.New("Access token creation is disabled. Contact an admin user to enable.")
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	if len(scopes) == 0 {
+		return errors.Errorf("access tokens must have at least one scope (valid scopes: %q)", authz.AllScopes)
+	}
+	var hasUserAllScope, hasSudoScope bool
 	seenScope := map[string]struct{}{}
-	sort.Strings(args.Scopes)
-	for _, scope := range args.Scopes {
+	sort.Strings(scopes)
+	for _, scope := range scopes {
 		switch scope {
 		case authz.ScopeUserAll:
 			hasUserAllScope = true
+		case authz.ScopeSearchRead, authz.ScopeRepoRead, authz.ScopeCodeIntelUpload, authz.ScopeBatchesWrite, authz.ScopeSettingsWrite:
+		case authz.ScopeSCIMProvision:
+			// 🚨 SECURITY: Only site admins may create a token with the "scim:provision" scope.
+			if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+				return err
+			}
 		case authz.ScopeSiteAdminSudo:
+			hasSudoScope = true
 			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
 			if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
-				return nil, err
+				return err
 			} else if envvar.SourcegraphDotComMode() {
-				return nil, errors.Errorf("creation of access tokens with scope %q is disabled on Sourcegraph.com", authz.ScopeSiteAdminSudo)
+				return errors.Errorf("creation of access tokens with scope %q is disabled on Sourcegraph.com", authz.ScopeSiteAdminSudo)
 			}
 		default:
-			return nil, errors.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
+			return errors.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
 		}
 
 		if _, seen := seenScope[scope]; seen {
-			return nil, errors.Errorf("access token scope %q may not be specified multiple times", scope)
+			return errors.Errorf("access token scope %q may not be specified multiple times", scope)
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, errors.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	if hasSudoScope && !hasUserAllScope {
+		return errors.Errorf("access tokens with scope %q must also have scope %q", authz.ScopeSiteAdminSudo, authz.ScopeUserAll)
 	}
 
-	id, token, err := r.db.AccessTokens().Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
+	return nil
+}
+
+// accessTokenExpiresAt returns the expiry date of a new access token that expires in the given
+// number of days, enforcing the maximum lifetime configured for access tokens. A nil result means
+// that the access token never expires.
+func accessTokenExpiresAt(expiresInDays *int32) (*time.Time, error) {
+	maxLifetime := conf.AccessTokensMaxLifetime()
+	if expiresInDays == nil {
+		if maxLifetime == 0 {
+			return nil, nil
+		}
+		expiresAt := time.Now().Add(maxLifetime)
+		return &expiresAt, nil
+	}
+
+	if *expiresInDays < 1 {
+		return nil, errors.New("access tokens must expire in at least 1 day")
+	}
+	lifetime := time.Duration(*expiresInDays) * 24 * time.Hour
+	if maxLifetime != 0 && lifetime > maxLifetime {
+		return nil, errors.Errorf("access tokens may not expire in more than %d days", int(maxLifetime/(24*time.Hour)))
+	}
+	expiresAt := time.Now().Add(lifetime)
+	return &expiresAt, nil
+}
+
+type rotateAccessTokenInput struct {
+	ID            graphql.ID
+	ExpiresInDays *int32
+}
+
+func (r *schemaResolver) RotateAccessToken(ctx context.Context, args *rotateAccessTokenInput) (*createAccessTokenResult, error) {
+	accessTokenID, err := unmarshalAccessTokenID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := r.db.AccessTokens().GetByID(ctx, accessTokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can rotate a user's access token, and only if
+	// they could create the replacement token.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, accessToken.SubjectUserID); err != nil {
+		return nil, err
+	}
+	if err := r.checkCanCreateAccessToken(ctx, accessToken.SubjectUserID, accessToken.Scopes); err != nil {
+		return nil, err
+	}
+	expiresAt, err := accessTokenExpiresAt(args.ExpiresInDays)
+	if err != nil {
+		return nil, err
+	}
+
+	id, token, err := r.db.AccessTokens().Rotate(ctx, accessToken.ID, actor.FromContext(ctx).UID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
 
 	if conf.CanSendEmail() {
-		if err := backend.UserEmails.SendUserEmailOnFieldUpdate(ctx, r.logger, r.db, userID, "created an access token"); err != nil {
-			r.logger.Warn("Failed to send email to inform user of access token creation", log.Error(err))
+		if err := backend.UserEmails.SendUserEmailOnFieldUpdate(ctx, r.logger, r.db, accessToken.SubjectUserID, "rotated an access token"); err != nil {
+			r.logger.Warn("Failed to send email to inform user of access token rotation", log.Error(err))
 		}
 	}
 
-	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
+	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, nil
 }
 
 type createAccessTokenResult struct {
@@ -165,6 +260,7 @@ func (r *schemaResolver) DeleteAccessToken(ctx context.Context, args *deleteAcce
 
 func (r *siteResolver) AccessTokens(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
+	UnusedSince *gqlutil.DateTime
 }) (*accessTokenConnectionResolver, error) {
 	// 🚨 SECURITY: Only site admins can list all access tokens. This is safe as the
 	// token values themselves are not stored in our database.
@@ -173,6 +269,9 @@ func (r *siteResolver) AccessTokens(ctx context.Context, args *struct {
 	}
 
 	var opt database.AccessTokensListOptions
+	if args.UnusedSince != nil {
+		opt.UnusedSince = &args.UnusedSince.Time
+	}
 	args.ConnectionArgs.Set(&opt.LimitOffset)
 	return &accessTokenConnectionResolver{db: r.db, opt: opt}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 	gqlerrors "github.com/graph-gophers/graphql-go/errors"
@@ -25,7 +26,7 @@ import (
 func TestMutation_CreateAccessToken(t *testing.T) {
 	newMockAccessTokens := func(t *testing.T, wantCreatorUserID int32, wantScopes []string) database.AccessTokenStore {
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.CreateFunc.SetDefaultHook(func(_ context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (int64, string, error) {
+		accessTokens.CreateFunc.SetDefaultHook(func(_ context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, _ *time.Time) (int64, string, error) {
 			if want := int32(1); subjectUserID != want {
 				t.Errorf("got %v, want %v", subjectUserID, want)
 			}
@@ -78,6 +79,39 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		})
 	})
 
+	t.Run("authenticated as user, using fine-grained scopes", func(t *testing.T) {
+		accessTokens := newMockAccessTokens(t, 1, []string{authz.ScopeRepoRead, authz.ScopeSearchRead})
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: false}, nil)
+
+		db := database.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+		db.UsersFunc.SetDefaultReturn(users)
+
+		RunTests(t, []*Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  mustParseGraphQLSchema(t, db),
+				Query: `
+				mutation {
+					createAccessToken(user: "` + uid1GQLID + `", scopes: ["search:read", "repo:read"], note: "n") {
+						id
+						token
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"createAccessToken": {
+						"id": "QWNjZXNzVG9rZW46MQ==",
+						"token": "t"
+					}
+				}
+			`,
+			},
+		})
+	})
+
 	t.Run("authenticated as user, using invalid scopes", func(t *testing.T) {
 		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
 		db := database.NewMockDB()
@@ -90,26 +124,31 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
-	t.Run("authenticated as user, using site-admin-only scopes", func(t *testing.T) {
-		users := database.NewMockUserStore()
-		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: false}, nil)
-
-		db := database.NewMockDB()
-		db.UsersFunc.SetDefaultReturn(users)
-
-		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
-		result, err := newSchemaResolver(db, gitserver.NewClient(db)).CreateAccessToken(ctx, &createAccessTokenInput{
-			User:   uid1GQLID,
-			Scopes: []string{authz.ScopeUserAll, authz.ScopeSiteAdminSudo},
-			Note:   "n",
+	for _, scopes := range [][]string{
+		{authz.ScopeUserAll, authz.ScopeSiteAdminSudo},
+		{authz.ScopeSCIMProvision},
+	} {
+		t.Run(fmt.Sprintf("authenticated as user, using site-admin-only scopes %q", scopes), func(t *testing.T) {
+			users := database.NewMockUserStore()
+			users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: false}, nil)
+
+			db := database.NewMockDB()
+			db.UsersFunc.SetDefaultReturn(users)
+
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+			result, err := newSchemaResolver(db, gitserver.NewClient(db)).CreateAccessToken(ctx, &createAccessTokenInput{
+				User:   uid1GQLID,
+				Scopes: scopes,
+				Note:   "n",
+			})
+			if want := auth.ErrMustBeSiteAdmin; err != want {
+				t.Errorf("got err %v, want %v", err, want)
+			}
+			if result != nil {
+				t.Errorf("got result %v, want nil", result)
+			}
 		})
-		if want := auth.ErrMustBeSiteAdmin; err != want {
-			t.Errorf("got err %v, want %v", err, want)
-		}
-		if result != nil {
-			t.Errorf("got result %v, want nil", result)
-		}
-	})
+	}
 
 	t.Run("authenticated as site admin, using site-admin-only scopes", func(t *testing.T) {
 		accessTokens := newMockAccessTokens(t, 1, []string{authz.ScopeSiteAdminSudo, authz.ScopeUserAll})
@@ -419,3 +458,118 @@ func TestMutation_DeleteAccessToken(t *testing.T) {
 		}
 	})
 }
+
+func TestAccessTokenExpiresAt(t *testing.T) {
+	days := func(n int32) *int32 { return &n }
+
+	for _, tc := range []struct {
+		name            string
+		maxLifetimeDays int
+		expiresInDays   *int32
+		wantDays        int
+		wantErr         bool
+	}{
+		{name: "no expiry"},
+		{name: "expiry", expiresInDays: days(30), wantDays: 30},
+		{name: "invalid expiry", expiresInDays: days(0), wantErr: true},
+		{name: "max lifetime default", maxLifetimeDays: 90, wantDays: 90},
+		{name: "within max lifetime", maxLifetimeDays: 90, expiresInDays: days(30), wantDays: 30},
+		{name: "exceeds max lifetime", maxLifetimeDays: 90, expiresInDays: days(91), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf.Get().AuthAccessTokens = &schema.AuthAccessTokens{MaxLifetimeDays: tc.maxLifetimeDays}
+			defer func() { conf.Get().AuthAccessTokens = nil }()
+
+			expiresAt, err := accessTokenExpiresAt(tc.expiresInDays)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, but there was none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantDays == 0 {
+				if expiresAt != nil {
+					t.Fatalf("got expiry %v, want none", expiresAt)
+				}
+				return
+			}
+			want := time.Now().Add(time.Duration(tc.wantDays) * 24 * time.Hour)
+			if expiresAt == nil || expiresAt.Sub(want).Abs() > time.Minute {
+				t.Fatalf("got expiry %v, want %v", expiresAt, want)
+			}
+		})
+	}
+}
+
+// 🚨 SECURITY: This tests that users can't rotate tokens of other users.
+func TestMutation_RotateAccessToken(t *testing.T) {
+	newMockAccessTokens := func() *database.MockAccessTokenStore {
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.GetByIDFunc.SetDefaultReturn(&database.AccessToken{ID: 1, SubjectUserID: 2, Scopes: []string{authz.ScopeUserAll}}, nil)
+		accessTokens.RotateFunc.SetDefaultReturn(2, "t", nil)
+		return accessTokens
+	}
+
+	token1GQLID := graphql.ID("QWNjZXNzVG9rZW46MQ==")
+
+	t.Run("authenticated as user", func(t *testing.T) {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 2}, nil)
+		accessTokens := newMockAccessTokens()
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		RunTests(t, []*Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+				Schema:  mustParseGraphQLSchema(t, db),
+				Query: `
+				mutation {
+					rotateAccessToken(id: "` + string(token1GQLID) + `") {
+						id
+						token
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"rotateAccessToken": {
+						"id": "QWNjZXNzVG9rZW46Mg==",
+						"token": "t"
+					}
+				}
+			`,
+			},
+		})
+		if len(accessTokens.RotateFunc.History()) != 1 {
+			t.Errorf("got %d calls to Rotate, want 1", len(accessTokens.RotateFunc.History()))
+		}
+	})
+
+	t.Run("authenticated as different non-site-admin user", func(t *testing.T) {
+		const differentNonSiteAdminUID = 456
+
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: differentNonSiteAdminUID}, nil)
+		accessTokens := newMockAccessTokens()
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: differentNonSiteAdminUID})
+		result, err := newSchemaResolver(db, gitserver.NewClient(db)).RotateAccessToken(ctx, &rotateAccessTokenInput{ID: token1GQLID})
+		if err == nil {
+			t.Error("Expected error, but there was none")
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+		if len(accessTokens.RotateFunc.History()) != 0 {
+			t.Error("Rotate was called, want no calls")
+		}
+	})
+}
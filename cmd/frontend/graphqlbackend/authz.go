@@ -17,6 +17,7 @@ type AuthzResolver interface {
 	ScheduleRepositoryPermissionsSync(ctx context.Context, args *RepositoryIDArgs) (*EmptyResponse, error)
 	ScheduleUserPermissionsSync(ctx context.Context, args *UserPermissionsSyncArgs) (*EmptyResponse, error)
 	SetSubRepositoryPermissionsForUsers(ctx context.Context, args *SubRepoPermsArgs) (*EmptyResponse, error)
+	SetSubRepositoryPathRules(ctx context.Context, args *SubRepoPathRulesArgs) (*EmptyResponse, error)
 	SetRepositoryPermissionsForBitbucketProject(ctx context.Context, args *RepoPermsBitbucketProjectArgs) (*EmptyResponse, error)
 
 	// Queries
@@ -26,9 +27,11 @@ type AuthzResolver interface {
 	BitbucketProjectPermissionJobs(ctx context.Context, args *BitbucketProjectPermissionJobsArgs) (BitbucketProjectsPermissionJobsResolver, error)
 	AuthzProviderTypes(ctx context.Context) ([]string, error)
 	PermissionsSyncJobs(ctx context.Context, args *PermissionsSyncJobsArgs) (PermissionsSyncJobsConnection, error)
+	PermissionsSyncSimulation(ctx context.Context, args *PermissionsSyncSimulationArgs) (PermissionsSyncSimulationResolver, error)
 
 	// Helpers
 	RepositoryPermissionsInfo(ctx context.Context, repoID graphql.ID) (PermissionsInfoResolver, error)
+	RepositorySubRepositoryPathRules(ctx context.Context, repoID graphql.ID) ([]*SubRepositoryPathRuleResolver, error)
 	UserPermissionsInfo(ctx context.Context, userID graphql.ID) (PermissionsInfoResolver, error)
 
 	// Node types
@@ -69,6 +72,12 @@ type SubRepoPermsArgs struct {
 	}
 }
 
+type SubRepoPathRulesArgs struct {
+	Repository graphql.ID
+	Subject    graphql.ID
+	Paths      []string
+}
+
 type AuthorizedRepoArgs struct {
 	Username *string
 	Email    *string
@@ -152,3 +161,24 @@ type PermissionsProviderStateResolver interface {
 	Status() string
 	Message() string
 }
+
+type PermissionsSyncSimulationArgs struct {
+	User       graphql.ID
+	Repository graphql.ID
+}
+
+type PermissionsSyncSimulationResolver interface {
+	ProviderType() *string
+	ProviderID() *string
+	AccountID() *string
+	UserPermissionsGrantAccess() *bool
+	RepositoryPermissionsGrantAccess() *bool
+	Decisions() []PermissionsSyncDecisionResolver
+	Errors() []string
+}
+
+type PermissionsSyncDecisionResolver interface {
+	Source() string
+	Effect() string
+	Reason() string
+}
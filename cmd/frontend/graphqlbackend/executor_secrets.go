@@ -281,5 +281,7 @@ func checkNamespaceAccess(ctx context.Context, db database.DB, namespaceUserID,
 		return auth.CheckOrgAccessOrSiteAdmin(ctx, db, namespaceOrgID)
 	}
 
-	return auth.CheckCurrentUserIsSiteAdmin(ctx, db)
+	// Global secrets can be managed by site admins and users with the executor
+	// secret manager role.
+	return auth.CheckCurrentUserPermission(ctx, db, auth.PermissionExecutorSecretsManage)
 }
@@ -269,6 +269,20 @@ func (r *GitTreeEntryResolver) CodeIntelInfo(ctx context.Context) (resolverstubs
 	})
 }
 
+func (r *GitTreeEntryResolver) CodeIntelCoverage(ctx context.Context, args *struct{ Depth int32 }) (resolverstubs.CodeIntelDirectoryCoverageResolver, error) {
+	repo, err := r.commit.repoResolver.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return EnterpriseResolvers.codeIntelResolver.GitTreeCodeIntelCoverage(ctx, &resolverstubs.GitTreeCodeIntelCoverageArgs{
+		Repo:   repo,
+		Commit: string(r.Commit().OID()),
+		Path:   r.Path(),
+		Depth:  args.Depth,
+	})
+}
+
 func (r *GitTreeEntryResolver) LocalCodeIntel(ctx context.Context) (*JSONValue, error) {
 	repo, err := r.commit.repoResolver.repo(ctx)
 	if err != nil {
@@ -152,7 +152,14 @@ func (t *requestTracer) TraceQuery(ctx context.Context, queryString string, oper
 func (requestTracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, args map[string]any) (context.Context, trace.TraceFieldFinishFunc) {
 	// We don't call into t.OpenTracingTracer.TraceField since it generates too many spans which is really hard to read.
 	start := time.Now()
-	return ctx, func(err *gqlerrors.QueryError) {
+
+	// 🚨 SECURITY: Access tokens may only be used for the fields their scopes grant.
+	fieldCtx := ctx
+	if err := checkFieldScope(ctx, typeName, fieldName, args); err != nil {
+		fieldCtx = scopeRequiredContext{Context: ctx, err: err}
+	}
+
+	return fieldCtx, func(err *gqlerrors.QueryError) {
 		isErrStr := strconv.FormatBool(err != nil)
 		graphqlFieldHistogram.WithLabelValues(
 			prometheusTypeName(typeName),
@@ -583,6 +590,9 @@ func newSchemaResolver(db database.DB, gitserverClient gitserver.Client) *schema
 		"User": func(ctx context.Context, id graphql.ID) (Node, error) {
 			return UserByID(ctx, db, id)
 		},
+		"UserSession": func(ctx context.Context, id graphql.ID) (Node, error) {
+			return userSessionByID(ctx, db, id)
+		},
 		"Org": func(ctx context.Context, id graphql.ID) (Node, error) {
 			return OrgByID(ctx, db, id)
 		},
@@ -176,6 +176,11 @@ func (r *NodeResolver) ToUser() (*UserResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToUserSession() (*userSessionResolver, bool) {
+	n, ok := r.Node.(*userSessionResolver)
+	return n, ok
+}
+
 func (r *NodeResolver) ToOrg() (*OrgResolver, bool) {
 	n, ok := r.Node.(*OrgResolver)
 	return n, ok
@@ -0,0 +1,146 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type repositoryHistoryArgs struct {
+	graphqlutil.ConnectionArgs
+	After  *string
+	Events *[]string
+	Since  *gqlutil.DateTime
+}
+
+func (r *externalServiceResolver) RepositoryHistory(ctx context.Context, args *repositoryHistoryArgs) (*repositoryHistoryEventConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may see the repository history of an external service.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opts := database.RepoNameHistoryListOptions{
+		ExternalServiceID: r.externalService.ID,
+		LimitOffset:       &database.LimitOffset{Limit: 50},
+	}
+	if args.First != nil {
+		opts.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		offset, err := strconv.Atoi(*args.After)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing the after cursor")
+		}
+		opts.Offset = offset
+	}
+	if args.Events != nil {
+		for _, e := range *args.Events {
+			opts.Events = append(opts.Events, database.RepoNameHistoryEventType(strings.ToLower(e)))
+		}
+	}
+	if args.Since != nil {
+		since := args.Since.Time
+		opts.Since = &since
+	}
+
+	return &repositoryHistoryEventConnectionResolver{db: r.db, opts: opts}, nil
+}
+
+type repositoryHistoryEventConnectionResolver struct {
+	db   database.DB
+	opts database.RepoNameHistoryListOptions
+
+	once       sync.Once
+	events     []*database.RepoNameHistoryEvent
+	totalCount int
+	err        error
+}
+
+func (r *repositoryHistoryEventConnectionResolver) compute(ctx context.Context) ([]*database.RepoNameHistoryEvent, int, error) {
+	r.once.Do(func() {
+		store := r.db.RepoNameHistory()
+		r.events, r.err = store.List(ctx, r.opts)
+		if r.err != nil {
+			return
+		}
+		r.totalCount, r.err = store.Count(ctx, r.opts)
+	})
+	return r.events, r.totalCount, r.err
+}
+
+func (r *repositoryHistoryEventConnectionResolver) Nodes(ctx context.Context) ([]*repositoryHistoryEventResolver, error) {
+	events, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := gitserver.NewClient(r.db)
+	nodes := make([]*repositoryHistoryEventResolver, 0, len(events))
+	for _, e := range events {
+		nodes = append(nodes, &repositoryHistoryEventResolver{db: r.db, gitserverClient: client, event: e})
+	}
+	return nodes, nil
+}
+
+func (r *repositoryHistoryEventConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	_, totalCount, err := r.compute(ctx)
+	return int32(totalCount), err
+}
+
+func (r *repositoryHistoryEventConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	events, totalCount, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if next := r.opts.Offset + len(events); next < totalCount {
+		return graphqlutil.NextPageCursor(strconv.Itoa(next)), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+type repositoryHistoryEventResolver struct {
+	db              database.DB
+	gitserverClient gitserver.Client
+	event           *database.RepoNameHistoryEvent
+}
+
+func (r *repositoryHistoryEventResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := r.db.Repos().Get(ctx, r.event.RepoID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, r.gitserverClient, repo), nil
+}
+
+func (r *repositoryHistoryEventResolver) Event() string {
+	return strings.ToUpper(string(r.event.Event))
+}
+
+func (r *repositoryHistoryEventResolver) FromName() string {
+	return string(r.event.FromName)
+}
+
+func (r *repositoryHistoryEventResolver) ToName() *string {
+	if r.event.ToName == "" {
+		return nil
+	}
+	name := string(r.event.ToName)
+	return &name
+}
+
+func (r *repositoryHistoryEventResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.event.CreatedAt}
+}
@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -99,6 +100,9 @@ func (r *schemaResolver) Repositories(args *repositoryArgs) (*repositoryConnecti
 		return nil, err
 	}
 
+	// Virtual repositories are listed with their parent repository.
+	opt.IncludeVirtualRepos = true
+
 	return &repositoryConnectionResolver{
 		db:         r.db,
 		logger:     r.logger.Scoped("repositoryConnectionResolver", "resolves connections to a repository"),
@@ -128,9 +132,10 @@ type repositoryConnectionResolver struct {
 	notIndexed bool
 
 	// cache results because they are used by multiple fields
-	once  sync.Once
-	repos []*types.Repo
-	err   error
+	once         sync.Once
+	repos        []*types.Repo
+	virtualRepos map[api.RepoID][]*types.VirtualRepo
+	err          error
 }
 
 func (r *repositoryConnectionResolver) compute(ctx context.Context) ([]*types.Repo, error) {
@@ -212,11 +217,56 @@ func (r *repositoryConnectionResolver) compute(ctx context.Context) ([]*types.Re
 				opt2.Offset += opt2.Limit
 			}
 		}
+
+		r.virtualRepos, r.err = r.listVirtualRepos(ctx)
 	})
 
 	return r.repos, r.err
 }
 
+// listVirtualRepos returns the virtual repositories of the listed repositories
+// that match the name filters of the connection, by parent repository.
+func (r *repositoryConnectionResolver) listVirtualRepos(ctx context.Context) (map[api.RepoID][]*types.VirtualRepo, error) {
+	if !r.opt.IncludeVirtualRepos || len(r.repos) == 0 {
+		return nil, nil
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(r.repos))
+	for _, repo := range r.repos {
+		repoIDs = append(repoIDs, repo.ID)
+	}
+	vrs, err := r.db.VirtualRepos().List(ctx, database.VirtualReposListOptions{RepoIDs: repoIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	virtualRepos := make(map[api.RepoID][]*types.VirtualRepo, len(vrs))
+	for _, vr := range vrs {
+		if r.matchesNames(vr.Name) {
+			virtualRepos[vr.Parent.ID] = append(virtualRepos[vr.Parent.ID], vr)
+		}
+	}
+	return virtualRepos, nil
+}
+
+// matchesNames returns whether the given name matches the Query and Names
+// options of the connection, the same way the repository store does.
+func (r *repositoryConnectionResolver) matchesNames(name api.RepoName) bool {
+	lower := strings.ToLower(string(name))
+	if r.opt.Query != "" && !strings.Contains(lower, strings.ToLower(r.opt.Query)) {
+		return false
+	}
+	if len(r.opt.Names) == 0 {
+		return true
+	}
+	for _, n := range r.opt.Names {
+		if strings.ToLower(n) == lower {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *repositoryConnectionResolver) Nodes(ctx context.Context) ([]*RepositoryResolver, error) {
 	repos, err := r.compute(ctx)
 	if err != nil {
@@ -229,7 +279,14 @@ func (r *repositoryConnectionResolver) Nodes(ctx context.Context) ([]*Repository
 			break
 		}
 
-		resolvers = append(resolvers, NewRepositoryResolver(r.db, client, repo))
+		// A repository may only be listed because one of its virtual
+		// repositories matches.
+		if !r.opt.IncludeVirtualRepos || r.matchesNames(repo.Name) {
+			resolvers = append(resolvers, NewRepositoryResolver(r.db, client, repo))
+		}
+		for _, vr := range r.virtualRepos[repo.ID] {
+			resolvers = append(resolvers, NewVirtualRepositoryResolver(r.db, client, repo, vr))
+		}
 	}
 	return resolvers, nil
 }
@@ -275,8 +332,21 @@ func (r *repositoryConnectionResolver) TotalCount(ctx context.Context, args *Tot
 	}
 
 	i32ptr := func(v int32) *int32 { return &v }
-	count, err := r.db.Repos().Count(ctx, r.opt)
-	return i32ptr(int32(count)), err
+	opt := r.opt
+	opt.IncludeVirtualRepos = false
+	count, err := r.db.Repos().Count(ctx, opt)
+	if err != nil || !r.opt.IncludeVirtualRepos {
+		return i32ptr(int32(count)), err
+	}
+
+	// Virtual repositories are counted by name only, since they share every
+	// other attribute with their parent repository.
+	names := make([]api.RepoName, 0, len(r.opt.Names))
+	for _, name := range r.opt.Names {
+		names = append(names, api.RepoName(name))
+	}
+	virtualCount, err := r.db.VirtualRepos().Count(ctx, database.VirtualReposListOptions{Names: names, Query: r.opt.Query})
+	return i32ptr(int32(count + virtualCount)), err
 }
 
 func (r *repositoryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
@@ -54,6 +54,7 @@ func TestRepositories(t *testing.T) {
 
 	db := database.NewMockDB()
 	db.ReposFunc.SetDefaultReturn(repos)
+	db.VirtualReposFunc.SetDefaultReturn(database.NewMockVirtualReposStore())
 	db.UsersFunc.SetDefaultReturn(users)
 
 	schema := mustParseGraphQLSchema(t, db)
@@ -308,6 +309,74 @@ func TestRepositories(t *testing.T) {
 	})
 }
 
+func TestRepositories_VirtualRepos(t *testing.T) {
+	mono := &types.Repo{ID: 1, Name: "github.com/org/mono"}
+	payments := &types.VirtualRepo{Name: "github.com/org/mono/services/payments", Parent: types.MinimalRepo{ID: mono.ID, Name: mono.Name}, PathPrefix: "services/payments"}
+	billing := &types.VirtualRepo{Name: "github.com/org/mono/services/billing", Parent: types.MinimalRepo{ID: mono.ID, Name: mono.Name}, PathPrefix: "services/billing"}
+
+	repos := database.NewMockRepoStore()
+	repos.ListFunc.SetDefaultHook(func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+		if !opt.IncludeVirtualRepos {
+			t.Errorf("expected virtual repos to be included in the listing")
+		}
+		return []*types.Repo{mono}, nil
+	})
+	virtualRepos := database.NewMockVirtualReposStore()
+	virtualRepos.ListFunc.SetDefaultHook(func(ctx context.Context, opts database.VirtualReposListOptions) ([]*types.VirtualRepo, error) {
+		return []*types.VirtualRepo{billing, payments}, nil
+	})
+
+	db := database.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+	db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
+	db.UsersFunc.SetDefaultReturn(database.NewMockUserStore())
+
+	schema := mustParseGraphQLSchema(t, db)
+
+	RunTests(t, []*Test{
+		{
+			Schema: schema,
+			Query: `
+				{
+					repositories(first: 10) {
+						nodes { name url }
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repositories": {
+						"nodes": [
+							{ "name": "github.com/org/mono", "url": "/github.com/org/mono" },
+							{ "name": "github.com/org/mono/services/billing", "url": "/github.com/org/mono/services/billing" },
+							{ "name": "github.com/org/mono/services/payments", "url": "/github.com/org/mono/services/payments" }
+						]
+					}
+				}
+			`,
+		},
+		{
+			Schema: schema,
+			Query: `
+				{
+					repositories(first: 10, query: "payments") {
+						nodes { name }
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repositories": {
+						"nodes": [
+							{ "name": "github.com/org/mono/services/payments" }
+						]
+					}
+				}
+			`,
+		},
+	})
+}
+
 func TestRepositories_CursorPagination(t *testing.T) {
 	mockRepos := []*types.Repo{
 		{ID: 0, Name: "repo1"},
@@ -318,6 +387,7 @@ func TestRepositories_CursorPagination(t *testing.T) {
 	repos := database.NewMockRepoStore()
 	db := database.NewMockDB()
 	db.ReposFunc.SetDefaultReturn(repos)
+	db.VirtualReposFunc.SetDefaultReturn(database.NewMockVirtualReposStore())
 
 	buildQuery := func(first int, after string) string {
 		var args []string
@@ -52,6 +52,11 @@ type RepositoryResolver struct {
 	defaultBranchOnce sync.Once
 	defaultBranch     *GitRefResolver
 	defaultBranchErr  error
+
+	// virtualName is the name of the virtual repository this resolver presents,
+	// if any. It only changes the name and URL of the repository; everything
+	// else is resolved from the parent repository whose tree it shares.
+	virtualName api.RepoName
 }
 
 func NewRepositoryResolver(db database.DB, client gitserver.Client, repo *types.Repo) *RepositoryResolver {
@@ -104,6 +109,9 @@ func (r *RepositoryResolver) RepoName() api.RepoName {
 }
 
 func (r *RepositoryResolver) Name() string {
+	if r.virtualName != "" {
+		return string(r.virtualName)
+	}
 	return string(r.RepoMatch.Name)
 }
 
@@ -280,6 +288,9 @@ func (r *RepositoryResolver) URL() string {
 }
 
 func (r *RepositoryResolver) url() *url.URL {
+	if r.virtualName != "" {
+		return &url.URL{Path: "/" + string(r.virtualName)}
+	}
 	return r.RepoMatch.URL()
 }
 
@@ -418,6 +429,10 @@ func (r *RepositoryResolver) PermissionsInfo(ctx context.Context) (PermissionsIn
 	return EnterpriseResolvers.authzResolver.RepositoryPermissionsInfo(ctx, r.ID())
 }
 
+func (r *RepositoryResolver) SubRepositoryPathRules(ctx context.Context) ([]*SubRepositoryPathRuleResolver, error) {
+	return EnterpriseResolvers.authzResolver.RepositorySubRepositoryPathRules(ctx, r.ID())
+}
+
 func (r *schemaResolver) AddPhabricatorRepo(ctx context.Context, args *struct {
 	Callsign string
 	Name     *string
@@ -0,0 +1,134 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func (r *schemaResolver) Roles(ctx context.Context) ([]*roleResolver, error) {
+	// 🚨 SECURITY: Only site admins can list roles.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	roles, err := r.db.Roles().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toRoleResolvers(roles), nil
+}
+
+func (r *UserResolver) Roles(ctx context.Context) ([]*roleResolver, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to see the roles of the user.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	roles, err := r.db.Roles().ListForUser(ctx, r.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toRoleResolvers(roles), nil
+}
+
+type userRoleArgs struct {
+	User graphql.ID
+	Role graphql.ID
+}
+
+func (r *schemaResolver) AssignRoleToUser(ctx context.Context, args *userRoleArgs) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can assign roles.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	userID, roleID, err := unmarshalUserRoleArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the role exists so that we return a meaningful error.
+	if _, err := r.db.Roles().GetByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+	if err := r.db.Roles().AssignToUser(ctx, userID, roleID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) RemoveRoleFromUser(ctx context.Context, args *userRoleArgs) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can remove roles.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	userID, roleID, err := unmarshalUserRoleArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.Roles().RemoveFromUser(ctx, userID, roleID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func unmarshalUserRoleArgs(args *userRoleArgs) (userID, roleID int32, err error) {
+	userID, err = UnmarshalUserID(args.User)
+	if err != nil {
+		return 0, 0, err
+	}
+	roleID, err = UnmarshalRoleID(args.Role)
+	if err != nil {
+		return 0, 0, err
+	}
+	return userID, roleID, nil
+}
+
+const roleIDKind = "Role"
+
+func MarshalRoleID(id int32) graphql.ID {
+	return relay.MarshalID(roleIDKind, id)
+}
+
+func UnmarshalRoleID(id graphql.ID) (roleID int32, err error) {
+	err = relay.UnmarshalSpec(id, &roleID)
+	return
+}
+
+func toRoleResolvers(roles []*types.Role) []*roleResolver {
+	resolvers := make([]*roleResolver, 0, len(roles))
+	for _, role := range roles {
+		resolvers = append(resolvers, &roleResolver{role: role})
+	}
+	return resolvers
+}
+
+type roleResolver struct {
+	role *types.Role
+}
+
+func (r *roleResolver) ID() graphql.ID {
+	return MarshalRoleID(r.role.ID)
+}
+
+func (r *roleResolver) Name() string {
+	return r.role.Name
+}
+
+func (r *roleResolver) Permissions() []string {
+	return r.role.Permissions
+}
+
+func (r *roleResolver) System() bool {
+	return r.role.System
+}
+
+func (r *roleResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.role.CreatedAt}
+}
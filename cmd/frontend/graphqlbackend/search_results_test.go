@@ -92,6 +92,9 @@ func TestSearchResults(t *testing.T) {
 			return []types.MinimalRepo{{ID: 1, Name: "repo"}}, nil
 		})
 		db.ReposFunc.SetDefaultReturn(repos)
+		virtualRepos := database.NewMockVirtualReposStore()
+		virtualRepos.GetByNameFunc.SetDefaultReturn(nil, &database.VirtualRepoNotFoundErr{})
+		db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
 
 		for _, v := range searchVersions {
 			testCallResults(t, `repo:r repo:p`, v, []string{"repo:repo"})
@@ -294,6 +297,9 @@ func TestSearchResultsHydration(t *testing.T) {
 	})
 	repos.CountFunc.SetDefaultReturn(0, nil)
 	db.ReposFunc.SetDefaultReturn(repos)
+	virtualRepos := database.NewMockVirtualReposStore()
+	virtualRepos.GetByNameFunc.SetDefaultReturn(nil, &database.VirtualRepoNotFoundErr{})
+	db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
 
 	zoektRepo := &zoekt.RepoListEntry{
 		Repository: zoekt.Repository{
@@ -560,6 +566,9 @@ func TestEvaluateAnd(t *testing.T) {
 			})
 			repos.CountFunc.SetDefaultReturn(len(minimalRepos), nil)
 			db.ReposFunc.SetDefaultReturn(repos)
+			virtualRepos := database.NewMockVirtualReposStore()
+			virtualRepos.GetByNameFunc.SetDefaultReturn(nil, &database.VirtualRepoNotFoundErr{})
+			db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
 
 			literalPatternType := "literal"
 			cli := client.NewSearchClient(logtest.Scoped(t), db, z, nil)
@@ -662,6 +671,9 @@ func TestSubRepoFiltering(t *testing.T) {
 
 			db := database.NewMockDB()
 			db.ReposFunc.SetDefaultReturn(repos)
+			virtualRepos := database.NewMockVirtualReposStore()
+			virtualRepos.GetByNameFunc.SetDefaultReturn(nil, &database.VirtualRepoNotFoundErr{})
+			db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
 			db.EventLogsFunc.SetDefaultHook(func() database.EventLogStore {
 				return database.NewMockEventLogStore()
 			})
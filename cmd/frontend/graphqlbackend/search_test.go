@@ -110,6 +110,9 @@ func TestSearch(t *testing.T) {
 
 			db := database.NewMockDB()
 			db.ReposFunc.SetDefaultReturn(repos)
+			virtualRepos := database.NewMockVirtualReposStore()
+			virtualRepos.GetByNameFunc.SetDefaultReturn(nil, &database.VirtualRepoNotFoundErr{})
+			db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
 			db.ExternalServicesFunc.SetDefaultReturn(ext)
 			db.PhabricatorFunc.SetDefaultReturn(phabricator)
 
@@ -333,6 +336,9 @@ func BenchmarkSearchResults(b *testing.B) {
 	repos.ListMinimalReposFunc.SetDefaultReturn(minimalRepos, nil)
 	repos.CountFunc.SetDefaultReturn(len(minimalRepos), nil)
 	db.ReposFunc.SetDefaultReturn(repos)
+	virtualRepos := database.NewMockVirtualReposStore()
+	virtualRepos.GetByNameFunc.SetDefaultReturn(nil, &database.VirtualRepoNotFoundErr{})
+	db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
 
 	b.ResetTimer()
 	b.ReportAllocs()
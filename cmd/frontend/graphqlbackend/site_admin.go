@@ -116,6 +116,11 @@ func (r *schemaResolver) DeleteUsers(ctx context.Context, args *struct {
 			return nil, err
 		}
 	} else {
+		// Soft deleted users can be restored, so sign them out of all of their sessions
+		// instead of relying on the sessions being deleted with the users.
+		if err := session.InvalidateSessionsByIDs(ctx, r.db, ids); err != nil {
+			return nil, err
+		}
 		if err := r.db.Users().DeleteList(ctx, ids); err != nil {
 			return nil, err
 		}
@@ -73,6 +73,8 @@ func TestDeleteUser(t *testing.T) {
 		return &types.User{ID: id, Username: "alice"}, nil
 	})
 
+	userSessions := database.NewMockUserSessionStore()
+
 	userEmails := database.NewMockUserEmailsStore()
 	userEmails.ListByUserFunc.SetDefaultReturn([]*database.UserEmail{{Email: "alice@example.com"}}, nil)
 
@@ -117,6 +119,7 @@ func TestDeleteUser(t *testing.T) {
 	db.UserEmailsFunc.SetDefaultReturn(userEmails)
 	db.UserExternalAccountsFunc.SetDefaultReturn(externalAccounts)
 	db.AuthzFunc.SetDefaultReturn(authzStore)
+	db.UserSessionsFunc.SetDefaultReturn(userSessions)
 
 	tests := []struct {
 		name     string
@@ -0,0 +1,53 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// SubRepositoryPathRuleResolver resolves the sub-repository path rules of a user,
+// an organization or a role on a repository.
+type SubRepositoryPathRuleResolver struct {
+	db   database.DB
+	rule *types.SubRepoPathRule
+}
+
+func NewSubRepositoryPathRuleResolver(db database.DB, rule *types.SubRepoPathRule) *SubRepositoryPathRuleResolver {
+	return &SubRepositoryPathRuleResolver{db: db, rule: rule}
+}
+
+func (r *SubRepositoryPathRuleResolver) User(ctx context.Context) (*UserResolver, error) {
+	if r.rule.UserID == 0 {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, r.db, r.rule.UserID)
+}
+
+func (r *SubRepositoryPathRuleResolver) Organization(ctx context.Context) (*OrgResolver, error) {
+	if r.rule.OrgID == 0 {
+		return nil, nil
+	}
+	return OrgByIDInt32(ctx, r.db, r.rule.OrgID)
+}
+
+func (r *SubRepositoryPathRuleResolver) Role(ctx context.Context) (*roleResolver, error) {
+	if r.rule.RoleID == 0 {
+		return nil, nil
+	}
+	role, err := r.db.Roles().GetByID(ctx, r.rule.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	return &roleResolver{role: role}, nil
+}
+
+func (r *SubRepositoryPathRuleResolver) Paths() []string {
+	return r.rule.Paths
+}
+
+func (r *SubRepositoryPathRuleResolver) UpdatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.rule.UpdatedAt}
+}
@@ -131,3 +131,24 @@ func (r symbolResolver) URL(ctx context.Context) (string, error) { return r.Loca
 func (r symbolResolver) CanonicalURL() string { return r.Location().CanonicalURL() }
 
 func (r symbolResolver) FileLocal() bool { return r.Symbol.FileLimited }
+
+func (r symbolResolver) FullyQualifiedName() *string {
+	if r.Symbol.FullyQualifiedName == "" {
+		return nil
+	}
+	return &r.Symbol.FullyQualifiedName
+}
+
+func (r symbolResolver) Package() *string {
+	if r.Symbol.Package == "" {
+		return nil
+	}
+	return &r.Symbol.Package
+}
+
+func (r symbolResolver) Documentation() *string {
+	if r.Symbol.Documentation == "" {
+		return nil
+	}
+	return &r.Symbol.Documentation
+}
@@ -3,8 +3,16 @@ package graphqlbackend
 import (
 	"context"
 
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -34,3 +42,124 @@ type sessionResolver struct {
 }
 
 func (r *sessionResolver) CanSignOut() bool { return r.canSignOut }
+
+// userSessionResolver resolves a recorded session of a user, as opposed to sessionResolver, which
+// resolves the session of the current request.
+type userSessionResolver struct {
+	session *types.UserSession
+	current bool
+}
+
+func userSessionByID(ctx context.Context, db database.DB, id graphql.ID) (*userSessionResolver, error) {
+	sessionID, err := unmarshalUserSessionID(id)
+	if err != nil {
+		return nil, err
+	}
+	s, err := db.UserSessions().GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins may view the sessions of a user.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, db, s.UserID); err != nil {
+		return nil, err
+	}
+	return newUserSessionResolver(ctx, s), nil
+}
+
+func newUserSessionResolver(ctx context.Context, s *types.UserSession) *userSessionResolver {
+	return &userSessionResolver{
+		session: s,
+		current: s.SessionID == session.SessionIDFromContext(ctx),
+	}
+}
+
+func marshalUserSessionID(id int32) graphql.ID { return relay.MarshalID("UserSession", id) }
+
+func unmarshalUserSessionID(id graphql.ID) (sessionID int32, err error) {
+	err = relay.UnmarshalSpec(id, &sessionID)
+	return
+}
+
+func (r *userSessionResolver) ID() graphql.ID { return marshalUserSessionID(r.session.ID) }
+
+func (r *userSessionResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.session.CreatedAt}
+}
+
+func (r *userSessionResolver) LastSeenAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.session.LastSeenAt}
+}
+
+func (r *userSessionResolver) ExpiresAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.session.ExpiresAt}
+}
+
+func (r *userSessionResolver) IPAddress() *string {
+	if r.session.IPAddress == "" {
+		return nil
+	}
+	return &r.session.IPAddress
+}
+
+func (r *userSessionResolver) UserAgent() *string {
+	if r.session.UserAgent == "" {
+		return nil
+	}
+	return &r.session.UserAgent
+}
+
+func (r *userSessionResolver) AuthProvider() *string {
+	if r.session.AuthProvider == "" {
+		return nil
+	}
+	return &r.session.AuthProvider
+}
+
+func (r *userSessionResolver) Current() bool { return r.current }
+
+func (r *UserResolver) Sessions(ctx context.Context) ([]*userSessionResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins may view the sessions of a user.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := r.db.UserSessions().ListByUser(ctx, r.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*userSessionResolver, 0, len(sessions))
+	for _, s := range sessions {
+		resolvers = append(resolvers, newUserSessionResolver(ctx, s))
+	}
+	return resolvers, nil
+}
+
+func (r *schemaResolver) RevokeSession(ctx context.Context, args *struct {
+	Session graphql.ID
+}) (*EmptyResponse, error) {
+	s, err := userSessionByID(ctx, r.db, args.Session)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.UserSessions().Revoke(ctx, s.session.ID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) RevokeAllSessions(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins may sign a user out of all sessions.
+	if err := auth.CheckSiteAdminOrSameUser(ctx, r.db, userID); err != nil {
+		return nil, err
+	}
+	if err := session.InvalidateSessionsByIDs(ctx, r.db, []int32{userID}); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
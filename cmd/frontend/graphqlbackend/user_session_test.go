@@ -0,0 +1,67 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// 🚨 SECURITY: This tests that users can't revoke the sessions of other users.
+func TestMutation_RevokeSession(t *testing.T) {
+	newDB := func() (*database.MockDB, *database.MockUserSessionStore) {
+		users := database.NewMockUserStore()
+		users.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id, SiteAdmin: id == 3}, nil
+		})
+		users.GetByCurrentAuthUserFunc.SetDefaultHook(func(ctx context.Context) (*types.User, error) {
+			a := actor.FromContext(ctx)
+			return &types.User{ID: a.UID, SiteAdmin: a.UID == 3}, nil
+		})
+
+		userSessions := database.NewMockUserSessionStore()
+		userSessions.GetByIDFunc.SetDefaultReturn(&types.UserSession{ID: 1, UserID: 1, SessionID: "s"}, nil)
+
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.UserSessionsFunc.SetDefaultReturn(userSessions)
+		return db, userSessions
+	}
+
+	const sessionGQLID = "VXNlclNlc3Npb246MQ=="
+
+	for _, tc := range []struct {
+		name    string
+		uid     int32
+		wantErr bool
+	}{
+		{name: "owner", uid: 1},
+		{name: "site admin", uid: 3},
+		{name: "other user", uid: 2, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, userSessions := newDB()
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: tc.uid})
+
+			_, err := newSchemaResolver(db, nil).RevokeSession(ctx, &struct{ Session graphql.ID }{Session: sessionGQLID})
+			if tc.wantErr {
+				if !errors.HasType(err, &auth.InsufficientAuthorizationError{}) {
+					t.Fatalf("got error %v, want InsufficientAuthorizationError", err)
+				}
+				mockassert.NotCalled(t, userSessions.RevokeFunc)
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				mockassert.CalledOnceWith(t, userSessions.RevokeFunc, mockassert.Values(mockassert.Skip, int32(1)))
+			}
+		})
+	}
+}
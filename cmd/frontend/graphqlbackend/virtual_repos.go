@@ -0,0 +1,124 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// NewVirtualRepositoryResolver returns a resolver that presents the given
+// virtual repository of parent as a repository of its own.
+func NewVirtualRepositoryResolver(db database.DB, client gitserver.Client, parent *types.Repo, vr *types.VirtualRepo) *RepositoryResolver {
+	resolver := NewRepositoryResolver(db, client, parent)
+	resolver.virtualName = vr.Name
+	return resolver
+}
+
+func (r *RepositoryResolver) VirtualRepositories(ctx context.Context) ([]*virtualRepositoryResolver, error) {
+	vrs, err := r.db.VirtualRepos().List(ctx, database.VirtualReposListOptions{RepoIDs: []api.RepoID{r.IDInt32()}})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*virtualRepositoryResolver, 0, len(vrs))
+	for _, vr := range vrs {
+		resolvers = append(resolvers, &virtualRepositoryResolver{db: r.db, parent: r, vr: vr})
+	}
+	return resolvers, nil
+}
+
+func (r *schemaResolver) CreateVirtualRepository(ctx context.Context, args *struct {
+	Repo       graphql.ID
+	Name       string
+	PathPrefix string
+}) (*virtualRepositoryResolver, error) {
+	// 🚨 SECURITY: Only site admins can create virtual repositories.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	vr := &types.VirtualRepo{
+		Name:       api.RepoName(args.Name),
+		Parent:     types.MinimalRepo{ID: repoID},
+		PathPrefix: args.PathPrefix,
+	}
+	if err := r.db.VirtualRepos().Create(ctx, vr); err != nil {
+		return nil, err
+	}
+	return &virtualRepositoryResolver{db: r.db, vr: vr}, nil
+}
+
+func (r *schemaResolver) DeleteVirtualRepository(ctx context.Context, args *struct {
+	VirtualRepository graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can delete virtual repositories.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	id, err := UnmarshalVirtualRepositoryID(args.VirtualRepository)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.VirtualRepos().Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+const VirtualRepositoryIDKind = "VirtualRepository"
+
+func MarshalVirtualRepositoryID(id int32) graphql.ID {
+	return relay.MarshalID(VirtualRepositoryIDKind, id)
+}
+
+func UnmarshalVirtualRepositoryID(id graphql.ID) (vrID int32, err error) {
+	err = relay.UnmarshalSpec(id, &vrID)
+	return
+}
+
+type virtualRepositoryResolver struct {
+	db     database.DB
+	parent *RepositoryResolver
+	vr     *types.VirtualRepo
+}
+
+func (r *virtualRepositoryResolver) ID() graphql.ID {
+	return MarshalVirtualRepositoryID(r.vr.ID)
+}
+
+func (r *virtualRepositoryResolver) Name() string {
+	return string(r.vr.Name)
+}
+
+func (r *virtualRepositoryResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	if r.parent != nil {
+		return r.parent, nil
+	}
+	repo, err := r.db.Repos().Get(ctx, r.vr.Parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, gitserver.NewClient(r.db), repo), nil
+}
+
+func (r *virtualRepositoryResolver) PathPrefix() string {
+	return r.vr.PathPrefix
+}
+
+func (r *virtualRepositoryResolver) CreatedAt() gqlutil.DateTime {
+	return gqlutil.DateTime{Time: r.vr.CreatedAt}
+}
@@ -44,7 +44,7 @@ func serveSignOutHandler(db database.DB) http.HandlerFunc {
 			log15.Error("serveSignOutHandler", "err", err)
 		}
 
-		if err = session.SetActor(w, r, nil, 0, time.Time{}); err != nil {
+		if err = session.SetActor(w, r, nil, 0, time.Time{}, ""); err != nil {
 			logSignOutEvent(r, db, database.SecurityEventNameSignOutFailed, err)
 			log15.Error("serveSignOutHandler", "err", err)
 		}
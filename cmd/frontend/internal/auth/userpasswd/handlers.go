@@ -210,7 +210,7 @@ func handleSignUp(logger log.Logger, db database.DB, w http.ResponseWriter, r *h
 
 	// Write the session cookie
 	a := &actor.Actor{UID: usr.ID}
-	if err := session.SetActor(w, r, a, 0, usr.CreatedAt); err != nil {
+	if err := session.SetActor(w, r, a, 0, usr.CreatedAt, providerType); err != nil {
 		httpLogError(logger.Error, w, "Could not create new user session", http.StatusInternalServerError, log.Error(err))
 	}
 
@@ -326,7 +326,7 @@ func HandleSignIn(logger log.Logger, db database.DB, store LockoutStore) http.Ha
 		actor := actor.Actor{
 			UID: user.ID,
 		}
-		if err := session.SetActor(w, r, &actor, 0, user.CreatedAt); err != nil {
+		if err := session.SetActor(w, r, &actor, 0, user.CreatedAt, providerType); err != nil {
 			httpLogError(logger.Error, w, "Could not create new user session", http.StatusInternalServerError, log.Error(err))
 			return
 		}
@@ -328,16 +328,19 @@ func makeExternalAPI(db database.DB, schema *graphql.Schema, enterprise enterpri
 		schema,
 		rateLimiter,
 		&httpapi.Handlers{
-			GitHubSyncWebhook:               enterprise.GitHubSyncWebhook,
-			BatchesGitHubWebhook:            enterprise.BatchesGitHubWebhook,
-			BatchesGitLabWebhook:            enterprise.BatchesGitLabWebhook,
-			BatchesBitbucketServerWebhook:   enterprise.BatchesBitbucketServerWebhook,
-			BatchesBitbucketCloudWebhook:    enterprise.BatchesBitbucketCloudWebhook,
-			BatchesChangesFileGetHandler:    enterprise.BatchesChangesFileGetHandler,
-			BatchesChangesFileExistsHandler: enterprise.BatchesChangesFileExistsHandler,
-			BatchesChangesFileUploadHandler: enterprise.BatchesChangesFileUploadHandler,
-			NewCodeIntelUploadHandler:       enterprise.NewCodeIntelUploadHandler,
-			NewComputeStreamHandler:         enterprise.NewComputeStreamHandler,
+			GitHubSyncWebhook:                          enterprise.GitHubSyncWebhook,
+			BatchesGitHubWebhook:                       enterprise.BatchesGitHubWebhook,
+			BatchesGitLabWebhook:                       enterprise.BatchesGitLabWebhook,
+			BatchesBitbucketServerWebhook:              enterprise.BatchesBitbucketServerWebhook,
+			BatchesBitbucketCloudWebhook:               enterprise.BatchesBitbucketCloudWebhook,
+			BatchesChangesFileGetHandler:               enterprise.BatchesChangesFileGetHandler,
+			BatchesChangesFileExistsHandler:            enterprise.BatchesChangesFileExistsHandler,
+			BatchesChangesFileUploadHandler:            enterprise.BatchesChangesFileUploadHandler,
+			ExplicitPermissionsBulkImportUploadHandler: enterprise.ExplicitPermissionsBulkImportUploadHandler,
+			ExplicitPermissionsBulkImportGetHandler:    enterprise.ExplicitPermissionsBulkImportGetHandler,
+			SCIMHandler:                                enterprise.SCIMHandler,
+			NewCodeIntelUploadHandler:                  enterprise.NewCodeIntelUploadHandler,
+			NewComputeStreamHandler:                    enterprise.NewComputeStreamHandler,
 		},
 		enterprise.NewExecutorProxyHandler,
 		enterprise.NewGitHubAppSetupHandler,
@@ -71,15 +71,24 @@ func AccessTokenAuthMiddleware(db database.DB, logger log.Logger, next http.Hand
 			// Validate access token.
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
-			// is allowed to do.
-			var requiredScope string
+			// is allowed to do. Sudo tokens need the sudo scope, other tokens are restricted to
+			// their scopes by the handlers through the actor.
+			var subjectUserID int32
+			var scopes []string
+			var err error
 			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
+				subjectUserID, scopes, err = db.AccessTokens().LookupScopes(r.Context(), token)
 			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+				subjectUserID, err = db.AccessTokens().Lookup(r.Context(), token, authz.ScopeSiteAdminSudo)
 			}
-			subjectUserID, err := db.AccessTokens().Lookup(r.Context(), token, requiredScope)
 			if err != nil {
+				var scopeErr *authz.ErrScopeRequired
+				if errors.As(err, &scopeErr) {
+					logger.Warn("access token used without the required scope", log.Error(err))
+					http.Error(w, scopeErr.Error(), http.StatusForbidden)
+					return
+				}
+
 				if err == database.ErrAccessTokenNotFound || errors.HasType(err, database.InvalidTokenError{}) {
 					logger.Error(
 						"invalid access token",
@@ -247,6 +256,7 @@ func AccessTokenAuthMiddleware(db database.DB, logger log.Logger, next http.Hand
 					&actor.Actor{
 						UID:                 actorUserID,
 						SourcegraphOperator: sourcegraphOperator,
+						AccessTokenScopes:   scopes,
 					},
 				),
 			)
@@ -255,3 +265,17 @@ func AccessTokenAuthMiddleware(db database.DB, logger log.Logger, next http.Hand
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequireScope returns a handler that responds with 403 Forbidden if the request
+// was authenticated with an access token that doesn't grant the given scope.
+func RequireScope(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 🚨 SECURITY: Access tokens may only be used for the routes their scopes grant.
+		if err := authz.CheckScope(r.Context(), scope); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -81,7 +81,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token badbad")
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultReturn(0, database.InvalidTokenError{})
+		accessTokens.LookupScopesFunc.SetDefaultReturn(0, nil, database.InvalidTokenError{})
 		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 		securityEventLogs := database.NewMockSecurityEventLogsStore()
@@ -93,7 +93,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		db.SecurityEventLogsFunc.SetDefaultReturn(securityEventLogs)
 
 		checkHTTPResponse(t, db, req, http.StatusUnauthorized, "Invalid access token.\n")
-		mockrequire.Called(t, accessTokens.LookupFunc)
+		mockrequire.Called(t, accessTokens.LookupScopesFunc)
 		mockrequire.Called(t, securityEventLogs.LogEventFunc)
 	})
 
@@ -103,19 +103,16 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req.Header.Set("Authorization", headerValue)
 
 			accessTokens := database.NewMockAccessTokenStore()
-			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			})
 			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 			checkHTTPResponse(t, db, req, http.StatusOK, "user 123")
-			mockrequire.Called(t, accessTokens.LookupFunc)
+			mockrequire.Called(t, accessTokens.LookupScopesFunc)
 		})
 	}
 
@@ -126,19 +123,16 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 
 		accessTokens := database.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeUserAll; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return 123, []string{authz.ScopeUserAll}, nil
 		})
 		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 		checkHTTPResponse(t, db, req, http.StatusOK, "user 123")
-		mockrequire.Called(t, accessTokens.LookupFunc)
+		mockrequire.Called(t, accessTokens.LookupScopesFunc)
 	})
 
 	// Test that an access token overwrites the actor set by a prior auth middleware.
@@ -159,19 +153,16 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 
 			accessTokens := database.NewMockAccessTokenStore()
-			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			})
 			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 			checkHTTPResponse(t, db, req, http.StatusOK, "user 123")
-			mockrequire.Called(t, accessTokens.LookupFunc)
+			mockrequire.Called(t, accessTokens.LookupScopesFunc)
 		})
 	}
 
@@ -350,4 +341,75 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		mockrequire.Called(t, users.GetByIDFunc)
 		mockrequire.Called(t, users.GetByUsernameFunc)
 	})
+
+	t.Run("sudo token without sudo scope", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
+
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.LookupFunc.SetDefaultReturn(0, &authz.ErrScopeRequired{Scope: authz.ScopeSiteAdminSudo})
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		checkHTTPResponse(t, db, req, http.StatusForbidden, "access token does not have the scope \"site-admin:sudo\" required for this action\n")
+		mockrequire.Called(t, accessTokens.LookupFunc)
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	accessTokens := database.NewMockAccessTokenStore()
+	accessTokens.LookupScopesFunc.SetDefaultReturn(123, []string{authz.ScopeSearchRead}, nil)
+	db := database.NewMockDB()
+	db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+	db.UserExternalAccountsFunc.SetDefaultReturn(database.NewMockUserExternalAccountsStore())
+
+	newHandler := func(scope string) http.Handler {
+		return AccessTokenAuthMiddleware(db, logtest.NoOp(t), RequireScope(scope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "user %v", actor.FromContext(r.Context()).UID)
+		})))
+	}
+
+	for _, tc := range []struct {
+		name           string
+		scope          string
+		token          bool
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:           "granted scope",
+			scope:          authz.ScopeSearchRead,
+			token:          true,
+			wantStatusCode: http.StatusOK,
+			wantBody:       "user 123",
+		},
+		{
+			name:           "missing scope",
+			scope:          authz.ScopeCodeIntelUpload,
+			token:          true,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "access token does not have the scope \"codeintel:upload\" required for this action\n",
+		},
+		{
+			name:           "no access token",
+			scope:          authz.ScopeCodeIntelUpload,
+			wantStatusCode: http.StatusOK,
+			wantBody:       "user 0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tc.token {
+				req.Header.Set("Authorization", "token abcdef")
+			}
+
+			rr := httptest.NewRecorder()
+			newHandler(tc.scope).ServeHTTP(rr, req)
+			if rr.Code != tc.wantStatusCode {
+				t.Errorf("got response status %d, want %d", rr.Code, tc.wantStatusCode)
+			}
+			if got := rr.Body.String(); got != tc.wantBody {
+				t.Errorf("got response body %q, want %q", got, tc.wantBody)
+			}
+		})
+	}
 }
@@ -28,6 +28,7 @@ import (
 	registry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry/api"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -39,16 +40,19 @@ import (
 )
 
 type Handlers struct {
-	GitHubSyncWebhook               webhooks.Registerer
-	BatchesGitHubWebhook            webhooks.Registerer
-	BatchesGitLabWebhook            webhooks.RegistererHandler
-	BatchesBitbucketServerWebhook   webhooks.RegistererHandler
-	BatchesBitbucketCloudWebhook    webhooks.RegistererHandler
-	BatchesChangesFileGetHandler    http.Handler
-	BatchesChangesFileExistsHandler http.Handler
-	BatchesChangesFileUploadHandler http.Handler
-	NewCodeIntelUploadHandler       enterprise.NewCodeIntelUploadHandler
-	NewComputeStreamHandler         enterprise.NewComputeStreamHandler
+	GitHubSyncWebhook                          webhooks.Registerer
+	BatchesGitHubWebhook                       webhooks.Registerer
+	BatchesGitLabWebhook                       webhooks.RegistererHandler
+	BatchesBitbucketServerWebhook              webhooks.RegistererHandler
+	BatchesBitbucketCloudWebhook               webhooks.RegistererHandler
+	BatchesChangesFileGetHandler               http.Handler
+	BatchesChangesFileExistsHandler            http.Handler
+	BatchesChangesFileUploadHandler            http.Handler
+	ExplicitPermissionsBulkImportUploadHandler http.Handler
+	ExplicitPermissionsBulkImportGetHandler    http.Handler
+	SCIMHandler                                http.Handler
+	NewCodeIntelUploadHandler                  enterprise.NewCodeIntelUploadHandler
+	NewComputeStreamHandler                    enterprise.NewComputeStreamHandler
 }
 
 // NewHandler returns a new API handler that uses the provided API
@@ -80,8 +84,8 @@ func NewHandler(
 	})
 
 	// Set handlers for the installed routes.
-	m.Get(apirouter.RepoShield).Handler(trace.Route(handler(serveRepoShield(db))))
-	m.Get(apirouter.RepoRefresh).Handler(trace.Route(handler(serveRepoRefresh(db))))
+	m.Get(apirouter.RepoShield).Handler(trace.Route(RequireScope(authz.ScopeRepoRead, handler(serveRepoShield(db)))))
+	m.Get(apirouter.RepoRefresh).Handler(trace.Route(RequireScope(authz.ScopeRepoRead, handler(serveRepoRefresh(db)))))
 
 	webhookMiddleware := webhooks.NewLogMiddleware(
 		db.WebhookLogs(keyring.Default().WebhookLogKey),
@@ -111,31 +115,38 @@ func NewHandler(
 	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(webhookMiddleware.Logger(handlers.BatchesBitbucketServerWebhook)))
 	m.Get(apirouter.BitbucketCloudWebhooks).Handler(trace.Route(webhookMiddleware.Logger(handlers.BatchesBitbucketCloudWebhook)))
 
-	m.Get(apirouter.BatchesFileGet).Handler(trace.Route(handlers.BatchesChangesFileGetHandler))
-	m.Get(apirouter.BatchesFileExists).Handler(trace.Route(handlers.BatchesChangesFileExistsHandler))
-	m.Get(apirouter.BatchesFileUpload).Handler(trace.Route(handlers.BatchesChangesFileUploadHandler))
-	m.Get(apirouter.LSIFUpload).Handler(trace.Route(handlers.NewCodeIntelUploadHandler(true)))
-	m.Get(apirouter.ComputeStream).Handler(trace.Route(handlers.NewComputeStreamHandler()))
+	m.Get(apirouter.BatchesFileGet).Handler(trace.Route(RequireScope(authz.ScopeBatchesWrite, handlers.BatchesChangesFileGetHandler)))
+	m.Get(apirouter.BatchesFileExists).Handler(trace.Route(RequireScope(authz.ScopeBatchesWrite, handlers.BatchesChangesFileExistsHandler)))
+	m.Get(apirouter.BatchesFileUpload).Handler(trace.Route(RequireScope(authz.ScopeBatchesWrite, handlers.BatchesChangesFileUploadHandler)))
+	m.Get(apirouter.ExplicitPermissionsBulkImportUpload).Handler(trace.Route(RequireScope(authz.ScopeUserAll, handlers.ExplicitPermissionsBulkImportUploadHandler)))
+	m.Get(apirouter.ExplicitPermissionsBulkImportGet).Handler(trace.Route(RequireScope(authz.ScopeUserAll, handlers.ExplicitPermissionsBulkImportGetHandler)))
+	m.Get(apirouter.LSIFUpload).Handler(trace.Route(RequireScope(authz.ScopeCodeIntelUpload, handlers.NewCodeIntelUploadHandler(true))))
+
+	// 🚨 SECURITY: This handler checks the scope of the access token itself, because
+	// identity providers authenticate with the "Bearer" scheme.
+	m.Get(apirouter.SCIM).Handler(trace.Route(handlers.SCIMHandler))
+	m.Get(apirouter.ComputeStream).Handler(trace.Route(RequireScope(authz.ScopeSearchRead, handlers.NewComputeStreamHandler())))
 
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.Route(http.HandlerFunc(updatecheck.HandlerWithLog(logger))))
 	}
 
+	// The scopes of access tokens are checked per field by the GraphQL schema.
 	m.Get(apirouter.GraphQL).Handler(trace.Route(handler(serveGraphQL(logger, schema, rateLimiter, false))))
 
-	m.Get(apirouter.SearchStream).Handler(trace.Route(frontendsearch.StreamHandler(db)))
+	m.Get(apirouter.SearchStream).Handler(trace.Route(RequireScope(authz.ScopeSearchRead, frontendsearch.StreamHandler(db))))
 
 	// Return the minimum src-cli version that's compatible with this instance
 	m.Get(apirouter.SrcCli).Handler(trace.Route(newSrcCliVersionHandler(logger)))
 
 	gsClient := gitserver.NewClient(db)
-	m.Get(apirouter.GitBlameStream).Handler(trace.Route(handleStreamBlame(logger, db, gsClient)))
+	m.Get(apirouter.GitBlameStream).Handler(trace.Route(RequireScope(authz.ScopeRepoRead, handleStreamBlame(logger, db, gsClient))))
 
 	// Set up the src-cli version cache handler (this will effectively be a
 	// no-op anywhere other than dot-com).
 	m.Get(apirouter.SrcCliVersionCache).Handler(trace.Route(releasecache.NewHandler(logger)))
 
-	m.Get(apirouter.Registry).Handler(trace.Route(handler(registry.HandleRegistry(db))))
+	m.Get(apirouter.Registry).Handler(trace.Route(RequireScope(authz.ScopeUserAll, handler(registry.HandleRegistry(db)))))
 
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("API no route: %s %s from %s", r.Method, r.URL, r.Referer())
@@ -34,6 +34,11 @@ const (
 	BatchesFileExists = "batches.file.exists"
 	BatchesFileUpload = "batches.file.upload"
 
+	ExplicitPermissionsBulkImportUpload = "explicit-permissions.bulk-import.upload"
+	ExplicitPermissionsBulkImportGet    = "explicit-permissions.bulk-import.get"
+
+	SCIM = "scim"
+
 	ExternalURL            = "internal.app-url"
 	SendEmail              = "internal.send-email"
 	GitInfoRefs            = "internal.git.info-refs"
@@ -67,7 +72,10 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/files/batch-changes/{spec}/{file}").Methods("GET").Name(BatchesFileGet)
 	base.Path("/files/batch-changes/{spec}/{file}").Methods("HEAD").Name(BatchesFileExists)
 	base.Path("/files/batch-changes/{spec}").Methods("POST").Name(BatchesFileUpload)
+	base.Path("/explicit-permissions/import").Methods("POST").Name(ExplicitPermissionsBulkImportUpload)
+	base.Path("/explicit-permissions/import/{id:[0-9]+}").Methods("GET").Name(ExplicitPermissionsBulkImportGet)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
+	base.Path("/scim/v2/{rest:.*}").Name(SCIM)
 	base.Path("/search/stream").Methods("GET").Name(SearchStream)
 	base.Path("/compute/stream").Methods("GET", "POST").Name(ComputeStream)
 	base.Path("/blame/" + routevar.Repo + routevar.RepoRevSuffix + "/stream/{Path:.*}").Methods("GET").Name(GitBlameStream)
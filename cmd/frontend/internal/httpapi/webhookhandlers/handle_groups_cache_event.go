@@ -0,0 +1,69 @@
+package webhookhandlers
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v43/github"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// handleGitHubGroupsCacheEvent handles github events that change the members or repositories of an
+// organization or team, and invalidates the cached groups of the authz providers of the code host
+// the event was sent by, so that a user removed from a team does not keep access until the cache
+// expires.
+func handleGitHubGroupsCacheEvent(ctx context.Context, db database.DB, urn extsvc.CodeHostBaseURL, payload any) error {
+	if !conf.ExperimentalFeatures().EnablePermissionsWebhooks {
+		return nil
+	}
+	if globals.PermissionsUserMapping().Enabled {
+		return nil
+	}
+
+	log15.Debug("handleGitHubGroupsCacheEvent: Got github event", "type", fmt.Sprintf("%T", payload))
+
+	var org, team string
+	switch e := payload.(type) {
+	case *gh.MembershipEvent:
+		org, team = e.GetOrg().GetLogin(), e.GetTeam().GetSlug()
+	case *gh.TeamEvent:
+		org, team = e.GetOrg().GetLogin(), e.GetTeam().GetSlug()
+	case *gh.TeamAddEvent:
+		org, team = e.GetOrg().GetLogin(), e.GetTeam().GetSlug()
+	case *gh.OrganizationEvent:
+		org = e.GetOrganization().GetLogin()
+	case *gh.RepositoryEvent:
+		// Repositories of users are not cached.
+		org = e.GetOrg().GetLogin()
+	default:
+		return errors.Errorf("incorrect event type sent to github event handler: %T", payload)
+	}
+	if org == "" {
+		return nil
+	}
+
+	invalidateGroupsCache(urn, org, team)
+	return nil
+}
+
+// invalidateGroupsCache invalidates the cached group of every authz provider of the code host that
+// caches groups.
+func invalidateGroupsCache(urn extsvc.CodeHostBaseURL, org, team string) {
+	_, providers := authz.GetProviders()
+	for _, p := range providers {
+		if p.ServiceType() != extsvc.TypeGitHub || p.ServiceID() != urn.String() {
+			continue
+		}
+		if invalidator, ok := p.(authz.GroupsCacheInvalidator); ok {
+			log15.Debug("invalidateGroupsCache: Invalidating cached group", "provider", p.URN(), "org", org, "team", team)
+			invalidator.InvalidateGroupsCache(org, team)
+		}
+	}
+}
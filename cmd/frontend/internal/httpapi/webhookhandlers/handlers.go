@@ -3,6 +3,7 @@ package webhookhandlers
 import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 )
 
 func Init(w *webhooks.WebhookRouter) {
@@ -10,15 +11,20 @@ func Init(w *webhooks.WebhookRouter) {
 	// for event types
 
 	// Repository events
-	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{}), "public")
-	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{}), "repository")
+	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{}), extsvc.KindGitHub, "public")
+	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), extsvc.KindGitHub, "repository")
 
 	// Member refers to repository collaborators, and has both users and repos
-	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{}), "member")
-	w.Register(handleGitHubUserAuthzEvent(w.DB, authz.FetchPermsOptions{}), "member")
+	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{}), extsvc.KindGitHub, "member")
+	w.Register(handleGitHubUserAuthzEvent(w.DB, authz.FetchPermsOptions{}), extsvc.KindGitHub, "member")
 
 	// Events that touch cached permissions in authz/github.Provider implementation
-	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), "team_add")
-	w.Register(handleGitHubUserAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), "organisation")
-	w.Register(handleGitHubUserAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), "membership")
+	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), extsvc.KindGitHub, "team_add")
+	w.Register(handleGitHubRepoAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), extsvc.KindGitHub, "team")
+	w.Register(handleGitHubUserAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), extsvc.KindGitHub, "organization")
+	w.Register(handleGitHubUserAuthzEvent(w.DB, authz.FetchPermsOptions{InvalidateCaches: true}), extsvc.KindGitHub, "membership")
+
+	// Invalidate the cached groups affected by the events above right away, so that
+	// syncs of other users and repos do not use stale group members.
+	w.Register(handleGitHubGroupsCacheEvent, extsvc.KindGitHub, "membership", "team", "team_add", "organization", "repository")
 }
@@ -0,0 +1,86 @@
+package webhookhandlers
+
+import (
+	"context"
+	"testing"
+
+	gh "github.com/google/go-github/v43/github"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type groupsCacheProvider struct {
+	authz.Provider
+	serviceID   string
+	invalidated [][2]string
+}
+
+func (p *groupsCacheProvider) ServiceType() string { return extsvc.TypeGitHub }
+func (p *groupsCacheProvider) ServiceID() string   { return p.serviceID }
+func (p *groupsCacheProvider) URN() string         { return "extsvc:github:1" }
+
+func (p *groupsCacheProvider) InvalidateGroupsCache(org, team string) {
+	p.invalidated = append(p.invalidated, [2]string{org, team})
+}
+
+func TestInit_GroupsCacheEvents(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{EnablePermissionsWebhooks: true},
+	}})
+	defer conf.Mock(nil)
+
+	urn, err := extsvc.NewCodeHostBaseURL("https://github.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &groupsCacheProvider{serviceID: urn.String()}
+	authz.SetProviders(false, []authz.Provider{provider})
+	defer authz.SetProviders(true, nil)
+
+	db := database.NewMockDB()
+	db.UserExternalAccountsFunc.SetDefaultReturn(database.NewMockUserExternalAccountsStore())
+
+	router := &webhooks.WebhookRouter{DB: db}
+	Init(router)
+
+	org := &gh.Organization{Login: gh.String("sourcegraph")}
+	team := &gh.Team{Slug: gh.String("engineering")}
+	for _, tc := range []struct {
+		eventType string
+		event     any
+		want      [2]string
+	}{
+		{
+			eventType: "membership",
+			event:     &gh.MembershipEvent{Org: org, Team: team, Member: &gh.User{ID: gh.Int64(1)}},
+			want:      [2]string{"sourcegraph", "engineering"},
+		},
+		{
+			eventType: "team",
+			event:     &gh.TeamEvent{Org: org, Team: team},
+			want:      [2]string{"sourcegraph", "engineering"},
+		},
+	} {
+		t.Run(tc.eventType, func(t *testing.T) {
+			provider.invalidated = nil
+			if err := router.Dispatch(context.Background(), tc.eventType, extsvc.KindGitHub, urn, tc.event); err != nil {
+				t.Fatal(err)
+			}
+			if len(provider.invalidated) != 1 || provider.invalidated[0] != tc.want {
+				t.Fatalf("want cached group %v to be invalidated, got %v", tc.want, provider.invalidated)
+			}
+		})
+	}
+
+	t.Run("not registered for other code hosts", func(t *testing.T) {
+		err := router.Dispatch(context.Background(), "membership", extsvc.KindGitLab, urn, &gh.MembershipEvent{})
+		if err == nil {
+			t.Fatal("expected error dispatching event of unknown code host kind")
+		}
+	})
+}
@@ -435,6 +435,10 @@ func fromSymbolMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Searc
 			ContainerName: sym.Symbol.Parent,
 			Kind:          kindString,
 			Line:          int32(sym.Symbol.Line),
+
+			FullyQualifiedName: sym.Symbol.FullyQualifiedName,
+			Package:            sym.Symbol.Package,
+			Documentation:      sym.Symbol.Documentation,
 		})
 	}
 
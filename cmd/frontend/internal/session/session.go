@@ -3,6 +3,8 @@ package session
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -22,7 +24,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/redispool"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 
 	"github.com/inconshreveable/log15"
@@ -66,6 +70,12 @@ type sessionInfo struct {
 	LastActive    time.Time     `json:"lastActive"`
 	ExpiryPeriod  time.Duration `json:"expiryPeriod"`
 	UserCreatedAt time.Time     `json:"userCreatedAt"`
+
+	// SessionID identifies the session in the user_sessions table. It is empty
+	// for sessions created before sessions were recorded.
+	SessionID string `json:"sessionID,omitempty"`
+	// AuthProvider is the type of the auth provider the user signed in with.
+	AuthProvider string `json:"authProvider,omitempty"`
 }
 
 // SetSessionStore sets the backing store used for storing sessions on the server. It should be called exactly once.
@@ -228,10 +238,11 @@ func GetData(r *http.Request, key string, value any) error {
 }
 
 // SetActor sets the actor in the session, or removes it if actor == nil. If no session exists, a
-// new session is created.
+// new session is created. authProvider is the type of the auth provider the actor signed in with,
+// and is shown to the user when listing their sessions.
 //
 // If expiryPeriod is 0, the default expiry period is used.
-func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration, userCreatedAt time.Time) error {
+func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration, userCreatedAt time.Time, authProvider string) error {
 	var value *sessionInfo
 	if actor != nil {
 		if expiryPeriod == 0 {
@@ -243,11 +254,40 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 		}
 		auth.RemoveSignOutCookieIfSet(r, w)
 
-		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now(), UserCreatedAt: userCreatedAt}
+		sessionID, err := newSessionID()
+		if err != nil {
+			return err
+		}
+		value = &sessionInfo{
+			Actor:         actor,
+			ExpiryPeriod:  expiryPeriod,
+			LastActive:    time.Now(),
+			UserCreatedAt: userCreatedAt,
+			SessionID:     sessionID,
+			AuthProvider:  authProvider,
+		}
 	}
 	return SetData(w, r, "actor", value)
 }
 
+// newSessionID returns a new random session ID.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "generating session ID")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type sessionIDKey struct{}
+
+// SessionIDFromContext returns the ID of the session the request was authenticated with, or the
+// empty string if the request was not authenticated by a session cookie.
+func SessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
+
 func hasSessionCookie(r *http.Request) bool {
 	c, _ := r.Cookie(cookieName)
 	return c != nil
@@ -278,8 +318,7 @@ func deleteSession(w http.ResponseWriter, r *http.Request) error {
 // InvalidateSessionCurrentUser invalidates all sessions for the current user.
 func InvalidateSessionCurrentUser(w http.ResponseWriter, r *http.Request, db database.DB) error {
 	a := actor.FromContext(r.Context())
-	err := db.Users().InvalidateSessionsByID(r.Context(), a.UID)
-	if err != nil {
+	if err := InvalidateSessionsByIDs(r.Context(), db, []int32{a.UID}); err != nil {
 		return err
 	}
 
@@ -289,9 +328,12 @@ func InvalidateSessionCurrentUser(w http.ResponseWriter, r *http.Request, db dat
 	return deleteSession(w, r)
 }
 
-// Bulk "InvalidateSessionsByID" action.
+// InvalidateSessionsByIDs invalidates all sessions of the given users, and marks them as revoked.
 func InvalidateSessionsByIDs(ctx context.Context, db database.DB, ids []int32) error {
-	return db.Users().InvalidateSessionsByIDs(ctx, ids)
+	if err := db.Users().InvalidateSessionsByIDs(ctx, ids); err != nil {
+		return err
+	}
+	return db.UserSessions().RevokeByUserIDs(ctx, ids)
 }
 
 // CookieMiddleware is an http.Handler middleware that authenticates
@@ -434,6 +476,43 @@ func authenticateByCookie(logger log.Logger, db database.DB, r *http.Request, w
 			return ctx
 		}
 
+		// Sessions from before sessions were recorded do not have an ID yet.
+		if info.SessionID == "" {
+			if info.SessionID, err = newSessionID(); err != nil {
+				logger.Error("error generating session ID", log.Error(err))
+				return ctx
+			}
+			if err := SetData(w, r, "actor", info); err != nil {
+				logger.Error("error setting session ID", log.Error(err))
+				return ctx
+			}
+		}
+
+		// Check that the session has not been revoked, and record it if this is the first
+		// request made with it.
+		userSession, err := db.UserSessions().GetBySessionID(ctx, info.SessionID)
+		if errcode.IsNotFound(err) {
+			userSession, err = db.UserSessions().Create(ctx, &types.UserSession{
+				UserID:       usr.ID,
+				SessionID:    info.SessionID,
+				AuthProvider: info.AuthProvider,
+				IPAddress:    clientIP(ctx),
+				UserAgent:    r.UserAgent(),
+				ExpiresAt:    info.LastActive.Add(info.ExpiryPeriod),
+			})
+		}
+		if err != nil {
+			// As above, don't delete the session on what might be an ephemeral DB error.
+			logger.Error("error looking up session", log.Error(err))
+			span.SetError(err)
+			return ctx
+		}
+		if userSession.RevokedAt != nil || userSession.UserID != usr.ID {
+			span.SetAttributes(attribute.Bool("revoked", true))
+			_ = deleteSession(w, r)
+			return ctx
+		}
+
 		// Renew session
 		if time.Since(info.LastActive) > 5*time.Minute {
 			info.LastActive = time.Now()
@@ -441,12 +520,29 @@ func authenticateByCookie(logger log.Logger, db database.DB, r *http.Request, w
 				logger.Error("error renewing session", log.Error(err))
 				return ctx
 			}
+			if err := db.UserSessions().Touch(ctx, info.SessionID, clientIP(ctx), r.UserAgent(), info.LastActive.Add(info.ExpiryPeriod)); err != nil {
+				logger.Warn("error recording session activity", log.Error(err))
+			}
 		}
 
 		span.SetAttributes(attribute.Bool("authenticated", true))
 		info.Actor.FromSessionCookie = true
+		ctx = context.WithValue(ctx, sessionIDKey{}, info.SessionID)
 		return actor.WithActor(ctx, info.Actor)
 	}
 
 	return ctx
 }
+
+// clientIP returns the IP address of the client that made the request, preferring the originating
+// IP address if the request was forwarded by a proxy.
+func clientIP(ctx context.Context) string {
+	client := requestclient.FromContext(ctx)
+	if client == nil {
+		return ""
+	}
+	if client.ForwardedFor != "" {
+		return strings.TrimSpace(strings.Split(client.ForwardedFor, ",")[0])
+	}
+	return client.IP
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -34,11 +35,12 @@ func TestSetActorDeleteSession(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(newMockUserSessionStore())
 
 	// Start new session
 	w := httptest.NewRecorder()
 	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
-	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, 24*time.Hour, userCreatedAt); err != nil {
+	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, 24*time.Hour, userCreatedAt, "builtin"); err != nil {
 		t.Fatal(err)
 	}
 	var authCookies []*http.Cookie
@@ -155,11 +157,12 @@ func TestSessionExpiry(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(newMockUserSessionStore())
 
 	// Start new session
 	w := httptest.NewRecorder()
 	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
-	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Second, userCreatedAt); err != nil {
+	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Second, userCreatedAt, "builtin"); err != nil {
 		t.Fatal(err)
 	}
 	var authCookies []*http.Cookie
@@ -202,11 +205,12 @@ func TestManualSessionExpiry(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(newMockUserSessionStore())
 
 	// Start new session
 	w := httptest.NewRecorder()
 	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
-	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, time.Now()); err != nil {
+	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, time.Now(), "builtin"); err != nil {
 		t.Fatal(err)
 	}
 	var authCookies []*http.Cookie
@@ -251,12 +255,13 @@ func TestCookieMiddleware(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(newMockUserSessionStore())
 
 	// Start new sessions for all actors
 	authedReqs := make([]*http.Request, len(actors))
 	for i, actr := range actors {
 		w := httptest.NewRecorder()
-		if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, userCreatedAt); err != nil {
+		if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, userCreatedAt, "builtin"); err != nil {
 			t.Fatal(err)
 		}
 
@@ -308,6 +313,129 @@ func TestCookieMiddleware(t *testing.T) {
 	}
 }
 
+func TestRevokedSession(t *testing.T) {
+	logger := logtest.Scoped(t)
+
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	userCreatedAt := time.Now()
+
+	users := database.NewStrictMockUserStore()
+	users.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, CreatedAt: userCreatedAt}, nil
+	})
+	userSessions := newMockUserSessionStore()
+
+	db := database.NewStrictMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(userSessions)
+
+	// Start new session
+	w := httptest.NewRecorder()
+	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
+	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, userCreatedAt, "builtin"); err != nil {
+		t.Fatal(err)
+	}
+	authedReq := httptest.NewRequest("GET", "/", nil)
+	authedReq.Header.Set("User-Agent", "test-agent")
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Expires.After(time.Now()) || cookie.MaxAge > 0 {
+			authedReq.AddCookie(cookie)
+		}
+	}
+
+	// The first request records the session.
+	ctx := authenticateByCookie(logger, db, authedReq, httptest.NewRecorder())
+	if gotActor := actor.FromContext(ctx); !reflect.DeepEqual(gotActor, actr) {
+		t.Fatalf("didn't find actor %v != %v", gotActor, actr)
+	}
+	sessions, _ := userSessions.ListByUser(ctx, actr.UID)
+	if len(sessions) != 1 {
+		t.Fatalf("expected exactly 1 recorded session, got %d", len(sessions))
+	}
+	if have, want := sessions[0].SessionID, SessionIDFromContext(ctx); have != want {
+		t.Errorf("wrong session ID in context: have %q, want %q", want, have)
+	}
+	if have, want := sessions[0].AuthProvider, "builtin"; have != want {
+		t.Errorf("wrong auth provider: have %q, want %q", have, want)
+	}
+	if have, want := sessions[0].UserAgent, "test-agent"; have != want {
+		t.Errorf("wrong user agent: have %q, want %q", have, want)
+	}
+
+	// Once revoked, the session can no longer be used.
+	if err := userSessions.Revoke(ctx, sessions[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	if gotActor := actor.FromContext(authenticateByCookie(logger, db, authedReq, rr)); !reflect.DeepEqual(gotActor, &actor.Actor{}) {
+		t.Errorf("session wasn't revoked, found actor %+v", gotActor)
+	}
+	checkCookieDeleted(t, rr.Result())
+}
+
+// newMockUserSessionStore returns a mock UserSessionStore that keeps sessions in memory.
+func newMockUserSessionStore() *database.MockUserSessionStore {
+	var (
+		mu       sync.Mutex
+		sessions []*types.UserSession
+	)
+	find := func(match func(*types.UserSession) bool) *types.UserSession {
+		for _, s := range sessions {
+			if match(s) {
+				return s
+			}
+		}
+		return nil
+	}
+
+	store := database.NewStrictMockUserSessionStore()
+	store.CreateFunc.SetDefaultHook(func(ctx context.Context, session *types.UserSession) (*types.UserSession, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if s := find(func(s *types.UserSession) bool { return s.SessionID == session.SessionID }); s != nil {
+			return s, nil
+		}
+		created := *session
+		created.ID = int32(len(sessions) + 1)
+		created.CreatedAt = time.Now()
+		created.LastSeenAt = created.CreatedAt
+		sessions = append(sessions, &created)
+		return &created, nil
+	})
+	store.GetBySessionIDFunc.SetDefaultHook(func(ctx context.Context, sessionID string) (*types.UserSession, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if s := find(func(s *types.UserSession) bool { return s.SessionID == sessionID }); s != nil {
+			return s, nil
+		}
+		return nil, &database.UserSessionNotFoundErr{}
+	})
+	store.ListByUserFunc.SetDefaultHook(func(ctx context.Context, userID int32) ([]*types.UserSession, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var active []*types.UserSession
+		for _, s := range sessions {
+			if s.UserID == userID && s.RevokedAt == nil {
+				active = append(active, s)
+			}
+		}
+		return active, nil
+	})
+	store.TouchFunc.SetDefaultReturn(nil)
+	store.RevokeFunc.SetDefaultHook(func(ctx context.Context, id int32) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if s := find(func(s *types.UserSession) bool { return s.ID == id }); s != nil {
+			now := time.Now()
+			s.RevokedAt = &now
+		}
+		return nil
+	})
+	return store
+}
+
 // sessionCookie returns the session cookie from the header of the given request.
 func sessionCookie(r *http.Request) string {
 	c, err := r.Cookie(cookieName)
@@ -376,12 +504,13 @@ func TestMismatchedUserCreationFails(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(newMockUserSessionStore())
 
 	// Start a new session for the user with ID 1. Their creation time
 	// will be recorded into the session store.
 	w := httptest.NewRecorder()
 	actr := &actor.Actor{UID: 1, FromSessionCookie: true}
-	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, user.CreatedAt); err != nil {
+	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour, user.CreatedAt, "builtin"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -436,6 +565,7 @@ func TestOldUserSessionSucceeds(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSessionsFunc.SetDefaultReturn(newMockUserSessionStore())
 
 	// Start a new session for the user with ID 1. Their creation time will not be
 	// be recorded into the session store.
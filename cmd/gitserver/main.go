@@ -42,6 +42,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodproxy"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/npm"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/pypi"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/rubygems"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
@@ -507,7 +508,11 @@ func getVCSSyncer(
 		if err != nil {
 			return nil, err
 		}
-		cli := npm.NewHTTPClient(urn, c.Registry, c.Credentials, httpcli.ExternalDoer)
+		doer, err := repos.NewPackagesDoer(httpcli.ExternalClientFactory, c.Certificate, "", c.Registry)
+		if err != nil {
+			return nil, err
+		}
+		cli := npm.NewHTTPClient(urn, c.Registry, c.Credentials, doer)
 		return server.NewNpmPackagesSyncer(c, depsSvc, cli), nil
 	case extsvc.TypeGoModules:
 		var c schema.GoModulesConnection
@@ -515,7 +520,11 @@ func getVCSSyncer(
 		if err != nil {
 			return nil, err
 		}
-		cli := gomodproxy.NewClient(urn, c.Urls, httpcli.ExternalDoer)
+		doer, err := repos.NewPackagesDoer(httpcli.ExternalClientFactory, c.Certificate, c.Credentials, c.Urls...)
+		if err != nil {
+			return nil, err
+		}
+		cli := gomodproxy.NewClient(urn, c.Urls, doer)
 		return server.NewGoModulesSyncer(&c, depsSvc, cli), nil
 	case extsvc.TypePythonPackages:
 		var c schema.PythonPackagesConnection
@@ -523,7 +532,11 @@ func getVCSSyncer(
 		if err != nil {
 			return nil, err
 		}
-		cli := pypi.NewClient(urn, c.Urls, httpcli.ExternalDoer)
+		doer, err := repos.NewPackagesDoer(httpcli.ExternalClientFactory, c.Certificate, c.Credentials, c.Urls...)
+		if err != nil {
+			return nil, err
+		}
+		cli := pypi.NewClient(urn, c.Urls, doer)
 		return server.NewPythonPackagesSyncer(&c, depsSvc, cli), nil
 	case extsvc.TypeRustPackages:
 		var c schema.RustPackagesConnection
@@ -531,7 +544,11 @@ func getVCSSyncer(
 		if err != nil {
 			return nil, err
 		}
-		cli := crates.NewClient(urn, httpcli.ExternalDoer)
+		doer, err := repos.NewPackagesDoer(httpcli.ExternalClientFactory, c.Certificate, c.Credentials, crates.RegistryURL(c.Registry))
+		if err != nil {
+			return nil, err
+		}
+		cli := crates.NewClient(urn, doer)
 		return server.NewRustPackagesSyncer(&c, depsSvc, cli), nil
 	case extsvc.TypeRubyPackages:
 		var c schema.RubyPackagesConnection
@@ -539,8 +556,24 @@ func getVCSSyncer(
 		if err != nil {
 			return nil, err
 		}
-		cli := rubygems.NewClient(urn, c.Repository, httpcli.ExternalDoer)
+		doer, err := repos.NewPackagesDoer(httpcli.ExternalClientFactory, c.Certificate, c.Credentials, c.Repository)
+		if err != nil {
+			return nil, err
+		}
+		cli := rubygems.NewClient(urn, c.Repository, doer)
 		return server.NewRubyPackagesSyncer(&c, depsSvc, cli), nil
+	case extsvc.TypeNuGetPackages:
+		var c schema.NuGetPackagesConnection
+		urn, err := extractOptions(&c)
+		if err != nil {
+			return nil, err
+		}
+		doer, err := repos.NewPackagesDoer(httpcli.ExternalClientFactory, c.Certificate, "")
+		if err != nil {
+			return nil, err
+		}
+		cli := nuget.NewClient(urn, c.Registry, c.Credentials, doer)
+		return server.NewNuGetPackagesSyncer(&c, depsSvc, cli), nil
 	}
 	return &server.GitRepoSyncer{}, nil
 }
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func NewNuGetPackagesSyncer(
+	connection *schema.NuGetPackagesConnection,
+	svc *dependencies.Service,
+	client *nuget.Client,
+) VCSSyncer {
+	return &vcsPackagesSyncer{
+		logger:      log.Scoped("NuGetPackagesSyncer", "sync NuGet packages"),
+		typ:         "nuget_packages",
+		scheme:      dependencies.NuGetPackagesScheme,
+		placeholder: reposource.NewNuGetVersionedPackage("sourcegraph.placeholder", "0.0.0"),
+		svc:         svc,
+		configDeps:  connection.Dependencies,
+		source:      &nugetDependencySource{client: client},
+	}
+}
+
+type nugetDependencySource struct {
+	client *nuget.Client
+}
+
+func (nugetDependencySource) ParseVersionedPackageFromNameAndVersion(name reposource.PackageName, version string) (reposource.VersionedPackage, error) {
+	return reposource.ParseNuGetVersionedPackage(string(name) + "@" + version)
+}
+
+func (nugetDependencySource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParseNuGetVersionedPackage(dep)
+}
+
+func (nugetDependencySource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParseNuGetPackageFromName(name)
+}
+
+func (nugetDependencySource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParseNuGetPackageFromRepoName(repoName)
+}
+
+func (s *nugetDependencySource) Download(ctx context.Context, dir string, dep reposource.VersionedPackage) error {
+	pkg, packageURL, err := s.client.GetPackageContents(ctx, dep)
+	if err != nil {
+		return errors.Wrapf(err, "error downloading NuGet package with URL '%s'", packageURL)
+	}
+	defer pkg.Close()
+
+	if err = unpackNuGetPackage(pkg, dir); err != nil {
+		return errors.Wrapf(err, "failed to unzip NuGet package from URL %s", packageURL)
+	}
+
+	return nil
+}
+
+// unpackNuGetPackage unpacks the given .nupkg archive into workDir, skipping
+// the files that only describe the archive itself, files that aren't valid and
+// files that are potentially malicious.
+func unpackNuGetPackage(pkg io.Reader, workDir string) error {
+	pkgBytes, err := io.ReadAll(pkg)
+	if err != nil {
+		return err
+	}
+
+	opts := unpack.Opts{
+		SkipInvalid:    true,
+		SkipDuplicates: true,
+		Filter: func(p string, file fs.FileInfo) bool {
+			if isNuGetPackagingFile(p) {
+				return false
+			}
+
+			const sizeLimit = 15 * 1024 * 1024
+			if file.Size() >= sizeLimit {
+				return false
+			}
+
+			_, malicious := isPotentiallyMaliciousFilepathInArchive(p, workDir)
+			return !malicious
+		},
+	}
+
+	return unpack.Zip(bytes.NewReader(pkgBytes), int64(len(pkgBytes)), workDir, opts)
+}
+
+// isNuGetPackagingFile returns true for the Open Packaging Conventions files
+// and the signature that every .nupkg archive contains next to its contents.
+func isNuGetPackagingFile(p string) bool {
+	p = strings.TrimPrefix(path.Clean(p), "/")
+	return p == "[Content_Types].xml" ||
+		p == ".signature.p7s" ||
+		strings.HasPrefix(p, "_rels/") ||
+		strings.HasPrefix(p, "package/")
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnpackNuGetPackage(t *testing.T) {
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for _, f := range []fileInfo{
+		{path: "Newtonsoft.Json.nuspec", contents: []byte("<package/>")},
+		{path: "lib/net6.0/Newtonsoft.Json.xml", contents: []byte("<doc/>")},
+		{path: "src/JsonConvert.cs", contents: []byte("class JsonConvert {}")},
+		{path: "[Content_Types].xml", contents: []byte("<Types/>")},
+		{path: "_rels/.rels", contents: []byte("<Relationships/>")},
+		{path: "package/services/metadata/core-properties/1.psmdcp", contents: []byte("<coreProperties/>")},
+		{path: ".signature.p7s", contents: []byte("signature")},
+	} {
+		fw, err := zw.Create(f.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = fw.Write(f.contents); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := t.TempDir()
+	if err := unpackNuGetPackage(&zipBuf, tmp); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	if err := filepath.Walk(tmp, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		got = append(got, strings.TrimPrefix(path, tmp))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+
+	want := []string{"/Newtonsoft.Json.nuspec", "/lib/net6.0/Newtonsoft.Json.xml", "/src/JsonConvert.cs"}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("-want,+got\n%s", d)
+	}
+}
@@ -40,12 +40,14 @@ func NewRustPackagesSyncer(
 		placeholder: placeholder,
 		svc:         svc,
 		configDeps:  connection.Dependencies,
-		source:      &rustDependencySource{client: client},
+		source:      &rustDependencySource{client: client, registry: crates.RegistryURL(connection.Registry)},
 	}
 }
 
 type rustDependencySource struct {
 	client *crates.Client
+	// registry is the URL from which crates are downloaded.
+	registry string
 }
 
 func (rustDependencySource) ParseVersionedPackageFromNameAndVersion(name reposource.PackageName, version string) (reposource.VersionedPackage, error) {
@@ -65,7 +67,7 @@ func (rustDependencySource) ParsePackageFromRepoName(repoName api.RepoName) (rep
 }
 
 func (s *rustDependencySource) Download(ctx context.Context, dir string, dep reposource.VersionedPackage) error {
-	packageURL := fmt.Sprintf("https://static.crates.io/crates/%s/%s-%s.crate", dep.PackageSyntax(), dep.PackageSyntax(), dep.PackageVersion())
+	packageURL := fmt.Sprintf("%s/%s/%s-%s.crate", s.registry, dep.PackageSyntax(), dep.PackageSyntax(), dep.PackageVersion())
 
 	pkg, err := s.client.Get(ctx, packageURL)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	otlog "github.com/opentracing/opentracing-go/log"
@@ -17,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/repos"
@@ -60,6 +62,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-lookup", trace.WithRouteName("repo-lookup", s.handleRepoLookup))
 	mux.HandleFunc("/enqueue-repo-update", trace.WithRouteName("enqueue-repo-update", s.handleEnqueueRepoUpdate))
 	mux.HandleFunc("/sync-external-service", trace.WithRouteName("sync-external-service", s.handleExternalServiceSync))
+	mux.HandleFunc("/dry-run-external-service", trace.WithRouteName("dry-run-external-service", s.handleExternalServiceDryRun))
 	mux.HandleFunc("/enqueue-changeset-sync", trace.WithRouteName("enqueue-changeset-sync", s.handleEnqueueChangesetSync))
 	mux.HandleFunc("/schedule-perms-sync", trace.WithRouteName("schedule-perms-sync", s.handleSchedulePermsSync))
 	return mux
@@ -213,6 +216,79 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 	s.respond(w, http.StatusOK, &protocol.ExternalServiceSyncResult{})
 }
 
+func (s *Server) handleExternalServiceDryRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req protocol.ExternalServiceDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logger := s.Logger.With(log.Int64("ExternalServiceID", req.ExternalServiceID))
+
+	svc := &types.ExternalService{
+		Kind:   req.Kind,
+		Config: extsvc.NewUnencryptedConfig(req.Config),
+	}
+	if req.ExternalServiceID != 0 {
+		old, err := s.ExternalServiceStore().GetByID(ctx, req.ExternalServiceID)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				s.respond(w, http.StatusNotFound, err)
+			} else {
+				s.respond(w, http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		svc = old.Clone()
+		svc.Config = extsvc.NewUnencryptedConfig(req.Config)
+		if err := svc.UnredactConfig(ctx, old); err != nil {
+			s.respond(w, http.StatusBadRequest, err)
+			return
+		}
+	} else if _, ok := extsvc.ParseServiceKind(svc.Kind); !ok {
+		s.respond(w, http.StatusBadRequest, errors.Errorf("unknown external service kind %q", req.Kind))
+		return
+	}
+
+	diff, err := s.Syncer.DryRunExternalService(ctx, svc)
+	if ctx.Err() != nil {
+		// client is gone
+		return
+	}
+
+	result := &protocol.ExternalServiceDryRunResult{
+		Added:    repoNames(diff.Added),
+		Modified: repoNames(diff.Modified.Repos()),
+		Deleted:  repoNames(diff.Deleted),
+	}
+	if err != nil {
+		logger.Warn("server.external-service-dry-run", log.Error(err))
+		if errcode.IsUnauthorized(err) {
+			s.respond(w, http.StatusUnauthorized, err)
+			return
+		}
+		if errcode.IsForbidden(err) {
+			s.respond(w, http.StatusForbidden, err)
+			return
+		}
+		result.Error = err.Error()
+	}
+
+	s.respond(w, http.StatusOK, result)
+}
+
+// repoNames returns the sorted names of the given repos.
+func repoNames(rs types.Repos) []api.RepoName {
+	names := make([]api.RepoName, 0, len(rs))
+	for _, r := range rs {
+		names = append(names, r.Name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
 func (s *Server) respond(w http.ResponseWriter, code int, v any) {
 	switch val := v.(type) {
 	case error:
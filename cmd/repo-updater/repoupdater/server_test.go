@@ -23,11 +23,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/repos"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
@@ -35,6 +37,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/types/typestest"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestServer_handleRepoLookup(t *testing.T) {
@@ -871,3 +874,159 @@ func (t testSource) WithAuthenticator(a auth.Authenticator) (repos.Source, error
 func (t testSource) ValidateAuthenticator(ctx context.Context) error {
 	return t.fn()
 }
+
+func TestServer_handleExternalServiceDryRun(t *testing.T) {
+	// The config of the dry run is redacted, like the configs the UI edits.
+	const config = `{"url": "https://github.com", "token": "REDACTED", "repos": ["org/foo", "org/baz"]}`
+	svc := &types.ExternalService{
+		ID:     1,
+		Kind:   extsvc.KindGitHub,
+		Config: extsvc.NewUnencryptedConfig(`{"url": "https://github.com", "token": "secret", "repos": ["org/foo", "org/bar", "org/qux"]}`),
+	}
+
+	newRepo := func(name string, id api.RepoID, sources ...string) *types.Repo {
+		r := &types.Repo{
+			ID:          id,
+			Name:        api.RepoName("github.com/" + name),
+			Description: name,
+			Metadata:    &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceID:   "https://github.com/",
+				ServiceType: extsvc.TypeGitHub,
+			},
+			Sources: map[string]*types.SourceInfo{},
+		}
+		for _, urn := range sources {
+			r.Sources[urn] = &types.SourceInfo{ID: urn}
+		}
+		return r
+	}
+	otherURN := extsvc.URN(extsvc.KindGitHub, 2)
+	stored := types.Repos{
+		newRepo("org/foo", 1, svc.URN()),
+		newRepo("org/bar", 2, svc.URN()),
+		// Also synced by another external service, so it must not be deleted.
+		newRepo("org/qux", 3, svc.URN(), otherURN),
+	}
+
+	externalServices := database.NewMockExternalServiceStore()
+	externalServices.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int64) (*types.ExternalService, error) {
+		if id != svc.ID {
+			return nil, &errcode.Mock{Message: "external service not found", IsNotFound: true}
+		}
+		return svc, nil
+	})
+	repoStore := database.NewMockRepoStore()
+	repoStore.ListFunc.SetDefaultHook(func(_ context.Context, opts database.ReposListOptions) (rs []*types.Repo, _ error) {
+		if len(opts.ExternalServiceIDs) > 0 {
+			return stored.Clone(), nil
+		}
+		for _, r := range stored.Clone() {
+			if string(r.Name) == opts.Names[0] {
+				rs = append(rs, r)
+			}
+		}
+		return rs, nil
+	})
+	store := repos.NewMockStore()
+	store.ExternalServiceStoreFunc.SetDefaultReturn(externalServices)
+	store.RepoStoreFunc.SetDefaultReturn(repoStore)
+
+	// The source yields the repos listed in the config it is created from, so
+	// the result reflects the config of the dry run rather than the stored one.
+	sourcer := func(ctx context.Context, svc *types.ExternalService) (repos.Source, error) {
+		rawConfig, err := svc.Config.Decrypt(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var c schema.GitHubConnection
+		if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+			return nil, err
+		}
+		if c.Token != "secret" {
+			return nil, errors.Errorf("config was not unredacted: token %q", c.Token)
+		}
+
+		var rs []*types.Repo
+		for _, name := range c.Repos {
+			r := newRepo(name, 0)
+			r.Description = "sourced " + name
+			rs = append(rs, r)
+		}
+		return repos.NewFakeSource(svc, nil, rs...), nil
+	}
+
+	s := &Server{
+		Logger: logtest.Scoped(t),
+		Store:  store,
+		Syncer: &repos.Syncer{
+			Logger:  logtest.Scoped(t),
+			Store:   store,
+			Sourcer: sourcer,
+		},
+	}
+
+	dryRun := func(t *testing.T, req protocol.ExternalServiceDryRunRequest) (int, *protocol.ExternalServiceDryRunResult) {
+		t.Helper()
+
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		s.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/dry-run-external-service", bytes.NewReader(body)))
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+
+		var result protocol.ExternalServiceDryRunResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, &result
+	}
+
+	t.Run("existing external service", func(t *testing.T) {
+		_, have := dryRun(t, protocol.ExternalServiceDryRunRequest{ExternalServiceID: svc.ID, Config: config})
+		want := &protocol.ExternalServiceDryRunResult{
+			Added:    []api.RepoName{"github.com/org/baz"},
+			Modified: []api.RepoName{"github.com/org/foo"},
+			Deleted:  []api.RepoName{"github.com/org/bar"},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("unexpected result (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("new external service", func(t *testing.T) {
+		_, have := dryRun(t, protocol.ExternalServiceDryRunRequest{Kind: extsvc.KindGitHub, Config: strings.Replace(config, "REDACTED", "secret", 1)})
+		want := &protocol.ExternalServiceDryRunResult{
+			Added:    []api.RepoName{"github.com/org/baz"},
+			Modified: []api.RepoName{"github.com/org/foo"},
+			Deleted:  []api.RepoName{},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("unexpected result (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("unknown external service", func(t *testing.T) {
+		if code, _ := dryRun(t, protocol.ExternalServiceDryRunRequest{ExternalServiceID: 42, Config: config}); code != http.StatusNotFound {
+			t.Fatalf("want status code %d, got %d", http.StatusNotFound, code)
+		}
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		if code, _ := dryRun(t, protocol.ExternalServiceDryRunRequest{Kind: "NOPE", Config: config}); code != http.StatusBadRequest {
+			t.Fatalf("want status code %d, got %d", http.StatusBadRequest, code)
+		}
+	})
+
+	// Nothing must have been persisted.
+	if len(repoStore.CreateFunc.History()) > 0 || len(repoStore.DeleteFunc.History()) > 0 ||
+		len(store.UpdateExternalServiceRepoFunc.History()) > 0 || len(store.DeleteExternalServiceRepoFunc.History()) > 0 {
+		t.Fatal("dry run modified the store")
+	}
+}
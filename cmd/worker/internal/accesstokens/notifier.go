@@ -0,0 +1,137 @@
+package accesstokens
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type expiryNotifier struct{}
+
+var _ job.Job = &expiryNotifier{}
+
+func NewExpiryNotifier() job.Job {
+	return &expiryNotifier{}
+}
+
+func (j *expiryNotifier) Description() string {
+	return "accesstokens.ExpiryNotifier emails users whose access tokens are about to expire."
+}
+
+func (j *expiryNotifier) Config() []env.Config {
+	return nil
+}
+
+func (j *expiryNotifier) Routines(startupCtx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDBWithLogger(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(context.Background(), 1*time.Hour, &handler{
+			db:     db,
+			logger: logger,
+		}),
+	}, nil
+}
+
+type handler struct {
+	db     database.DB
+	logger log.Logger
+}
+
+var _ goroutine.Handler = &handler{}
+var _ goroutine.ErrorHandler = &handler{}
+
+func (h *handler) Handle(ctx context.Context) error {
+	if !conf.CanSendEmail() {
+		h.logger.Debug("Email is not configured. Skipping access token expiry notifications.")
+		return nil
+	}
+
+	tokens, err := h.db.AccessTokens().ListExpiring(ctx, conf.AccessTokensExpiryNotificationPeriod())
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, token := range tokens {
+		if err := h.notify(ctx, token); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "notifying subject of access token %d", token.ID))
+			continue
+		}
+		if err := h.db.AccessTokens().MarkExpiryNotified(ctx, token.ID); err != nil {
+			errs = errors.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (h *handler) notify(ctx context.Context, token *database.AccessToken) error {
+	email, _, err := h.db.UserEmails().GetPrimaryEmail(ctx, token.SubjectUserID)
+	if err != nil {
+		return err
+	}
+	user, err := h.db.Users().GetByID(ctx, token.SubjectUserID)
+	if err != nil {
+		return err
+	}
+	externalURL, err := url.Parse(conf.ExternalURL())
+	if err != nil {
+		return err
+	}
+
+	return txemail.Send(ctx, "access_token_expiry", txemail.Message{
+		To:       []string{email},
+		Template: expiryEmailTemplate,
+		Data: struct {
+			Username  string
+			Note      string
+			ExpiresAt string
+			URL       string
+			Host      string
+		}{
+			Username:  user.Username,
+			Note:      token.Note,
+			ExpiresAt: token.ExpiresAt.UTC().Format(time.RFC1123),
+			URL:       externalURL.ResolveReference(&url.URL{Path: path.Join("/users", user.Username, "settings/tokens")}).String(),
+			Host:      externalURL.Host,
+		},
+	})
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error notifying users of expiring access tokens", log.Error(err))
+}
+
+var expiryEmailTemplate = txemail.MustValidate(txtypes.Templates{
+	Subject: `Your Sourcegraph access token is about to expire ({{.Host}})`,
+	Text: `
+The access token "{{.Note}}" for the user {{.Username}} on Sourcegraph ({{.Host}}) expires on {{.ExpiresAt}}.
+
+To keep using it, rotate the access token or create a new one at:
+
+  {{.URL}}
+`,
+	HTML: `
+<p>
+The access token <strong>{{.Note}}</strong> for the user <strong>{{.Username}}</strong> on Sourcegraph ({{.Host}}) expires on {{.ExpiresAt}}.
+</p>
+
+<p>To keep using it, <a href="{{.URL}}">rotate the access token or create a new one</a>.</p>
+`,
+})
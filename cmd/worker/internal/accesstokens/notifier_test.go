@@ -0,0 +1,87 @@
+package accesstokens
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestHandler(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	newDB := func() (*database.MockDB, *database.MockAccessTokenStore) {
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.ListExpiringFunc.SetDefaultReturn([]*database.AccessToken{{ID: 1, SubjectUserID: 2, Note: "n", ExpiresAt: &expiresAt}}, nil)
+		userEmails := database.NewMockUserEmailsStore()
+		userEmails.GetPrimaryEmailFunc.SetDefaultReturn("u@example.com", true, nil)
+		users := database.NewMockUserStore()
+		users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 2, Username: "u"}, nil)
+
+		db := database.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+		db.UserEmailsFunc.SetDefaultReturn(userEmails)
+		db.UsersFunc.SetDefaultReturn(users)
+		return db, accessTokens
+	}
+
+	var sent []txemail.Message
+	txemail.MockSend = func(_ context.Context, message txemail.Message) error {
+		sent = append(sent, message)
+		return nil
+	}
+	t.Cleanup(func() { txemail.MockSend = nil })
+
+	t.Run("email disabled", func(t *testing.T) {
+		conf.Mock(&conf.Unified{})
+		t.Cleanup(func() { conf.Mock(nil) })
+
+		db, accessTokens := newDB()
+		h := &handler{db: db, logger: logtest.Scoped(t)}
+
+		assert.Nil(t, h.Handle(context.Background()))
+		mockassert.NotCalled(t, accessTokens.ListExpiringFunc)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{EmailSmtp: &schema.SMTPServerConfig{}}})
+		t.Cleanup(func() { conf.Mock(nil) })
+
+		want := errors.New("error")
+		db, accessTokens := newDB()
+		accessTokens.ListExpiringFunc.SetDefaultReturn(nil, want)
+		h := &handler{db: db, logger: logtest.Scoped(t)}
+
+		assert.ErrorIs(t, h.Handle(context.Background()), want)
+		mockassert.NotCalled(t, accessTokens.MarkExpiryNotifiedFunc)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			EmailSmtp:   &schema.SMTPServerConfig{},
+			ExternalURL: "https://sourcegraph.example.com",
+		}})
+		t.Cleanup(func() { conf.Mock(nil) })
+		sent = nil
+
+		db, accessTokens := newDB()
+		h := &handler{db: db, logger: logtest.Scoped(t)}
+
+		assert.Nil(t, h.Handle(context.Background()))
+		mockassert.CalledOnceWith(t, accessTokens.ListExpiringFunc, mockassert.Values(mockassert.Skip, 7*24*time.Hour))
+		mockassert.CalledOnceWith(t, accessTokens.MarkExpiryNotifiedFunc, mockassert.Values(mockassert.Skip, int64(1)))
+		if assert.Len(t, sent, 1) {
+			assert.Equal(t, []string{"u@example.com"}, sent[0].To)
+		}
+	})
+}
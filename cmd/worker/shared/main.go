@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/accesstokens"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/gitserver"
@@ -41,13 +42,14 @@ func Start(logger log.Logger, additionalJobs map[string]job.Job, registerEnterpr
 	registerMigrators := oobmigration.ComposeRegisterMigratorsFuncs(migrations.RegisterOSSMigrators, registerEnterpriseMigrators)
 
 	builtins := map[string]job.Job{
-		"webhook-log-janitor":       webhooks.NewJanitor(),
-		"out-of-band-migrations":    workermigrations.NewMigrator(registerMigrators),
-		"codeintel-crates-syncer":   codeintel.NewCratesSyncerJob(),
-		"gitserver-metrics":         gitserver.NewMetricsJob(),
-		"record-encrypter":          encryption.NewRecordEncrypterJob(),
-		"repo-statistics-compactor": repostatistics.NewCompactor(),
-		"zoekt-repos-updater":       zoektrepos.NewUpdater(),
+		"webhook-log-janitor":          webhooks.NewJanitor(),
+		"out-of-band-migrations":       workermigrations.NewMigrator(registerMigrators),
+		"codeintel-crates-syncer":      codeintel.NewCratesSyncerJob(),
+		"gitserver-metrics":            gitserver.NewMetricsJob(),
+		"record-encrypter":             encryption.NewRecordEncrypterJob(),
+		"repo-statistics-compactor":    repostatistics.NewCompactor(),
+		"zoekt-repos-updater":          zoektrepos.NewUpdater(),
+		"access-token-expiry-notifier": accesstokens.NewExpiryNotifier(),
 	}
 
 	jobs := map[string]job.Job{}
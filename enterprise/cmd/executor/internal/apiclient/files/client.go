@@ -76,3 +76,18 @@ func (c *Client) Get(ctx context.Context, bucket string, key string) (content io
 	}
 	return body, nil
 }
+
+func (c *Client) Upload(ctx context.Context, bucket string, key string, r io.Reader) (err error) {
+	ctx, _, endObservation := c.operations.upload.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
+		otlog.String("bucket", bucket),
+		otlog.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	req, err := c.client.NewRequest(http.MethodPut, fmt.Sprintf("%s/%s", bucket, key), r)
+	if err != nil {
+		return err
+	}
+
+	return c.client.DoAndDrop(ctx, req)
+}
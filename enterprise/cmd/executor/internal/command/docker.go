@@ -36,7 +36,7 @@ func formatRawOrDockerCommand(spec CommandSpec, dir string, options Options) com
 		Command: flatten(
 			"docker", "run", "--rm",
 			dockerResourceFlags(options.ResourceOptions),
-			dockerVolumeFlags(hostDir),
+			dockerVolumeFlags(hostDir, spec.Mounts),
 			dockerWorkingdirectoryFlags(spec.Dir),
 			dockerEnvFlags(spec.Env),
 			dockerEntrypointFlags(),
@@ -59,8 +59,14 @@ func dockerResourceFlags(options ResourceOptions) []string {
 	return flags
 }
 
-func dockerVolumeFlags(wd string) []string {
-	return []string{"-v", wd + ":/data"}
+func dockerVolumeFlags(wd string, mounts []Mount) []string {
+	flags := make([]string, 0, 2+2*len(mounts))
+	flags = append(flags, "-v", wd+":/data")
+	for _, mount := range mounts {
+		flags = append(flags, "-v", filepath.Join(wd, mount.Source)+":"+mount.Target)
+	}
+
+	return flags
 }
 
 func dockerWorkingdirectoryFlags(dir string) []string {
@@ -142,3 +142,35 @@ func TestFormatRawOrDockerCommandDockerScriptWithDockerHostMountPath(t *testing.
 		t.Errorf("unexpected command (-want +got):\n%s", diff)
 	}
 }
+
+func TestFormatRawOrDockerCommandDockerScriptWithMounts(t *testing.T) {
+	actual := formatRawOrDockerCommand(
+		CommandSpec{
+			Image:      "alpine:latest",
+			ScriptPath: "myscript.sh",
+			Dir:        "subdir",
+			Mounts: []Mount{
+				{Source: ".sourcegraph-executor/caches/0/0", Target: "/root/go/pkg/mod"},
+			},
+			Operation: makeTestOperation(),
+		},
+		"/proj/src",
+		Options{},
+	)
+
+	expected := command{
+		Command: []string{
+			"docker", "run", "--rm",
+			"-v", "/proj/src:/data",
+			"-v", "/proj/src/.sourcegraph-executor/caches/0/0:/root/go/pkg/mod",
+			"-w", "/data/subdir",
+			"--entrypoint",
+			"/bin/sh",
+			"alpine:latest",
+			"/data/.sourcegraph-executor/myscript.sh",
+		},
+	}
+	if diff := cmp.Diff(expected, actual, commandComparer); diff != "" {
+		t.Errorf("unexpected command (-want +got):\n%s", diff)
+	}
+}
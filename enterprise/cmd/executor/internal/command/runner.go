@@ -33,9 +33,20 @@ type CommandSpec struct {
 	Command    []string
 	Dir        string
 	Env        []string
+	Mounts     []Mount
 	Operation  *observation.Operation
 }
 
+// Mount describes a directory within the workspace that is additionally mounted
+// into the container of a docker command.
+type Mount struct {
+	// Source is the path of the directory relative to the workspace root.
+	Source string
+
+	// Target is the absolute path at which the directory is mounted in the container.
+	Target string
+}
+
 type Options struct {
 	// ExecutorName is a unique identifier for the requesting executor.
 	ExecutorName string
@@ -99,6 +99,15 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job executor.Jo
 	}
 	defer workspace.Remove(ctx, h.options.KeepWorkspaces)
 
+	// Persist the dependency caches of successful jobs. This is deferred before the
+	// VM teardown below so that it only runs once the steps can no longer write to
+	// the workspace.
+	defer func() {
+		if err == nil {
+			workspace.SaveCaches(ctx)
+		}
+	}()
+
 	vmNameSuffix, err := uuid.NewRandom()
 	if err != nil {
 		return err
@@ -138,6 +147,11 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job executor.Jo
 		}
 	}()
 
+	var mounts []command.Mount
+	for _, cache := range workspace.Caches() {
+		mounts = append(mounts, cache.Mounts...)
+	}
+
 	// Invoke each docker step sequentially
 	for i, dockerStep := range job.DockerSteps {
 		var key string
@@ -152,6 +166,7 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job executor.Jo
 			ScriptPath: workspace.ScriptFilenames()[i],
 			Dir:        dockerStep.Dir,
 			Env:        dockerStep.Env,
+			Mounts:     mounts,
 			Operation:  h.operations.Exec,
 		}
 
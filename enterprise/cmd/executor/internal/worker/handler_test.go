@@ -276,3 +276,100 @@ func TestHandle_WorkspaceFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, workspace.ScriptPreamble+"\n\nyarn\ninstall\n", string(dockerScriptFile2Content))
 }
+
+func TestHandle_Caches(t *testing.T) {
+	job := executor.Job{
+		ID:             42,
+		Commit:         "deadbeef",
+		RepositoryName: "linux",
+		VirtualMachineFiles: map[string]executor.VirtualMachineFile{
+			"go.sum": {Content: []byte("<go.sum payload>")},
+		},
+		DockerSteps: []executor.DockerStep{
+			{
+				Image:    "go",
+				Commands: []string{"go", "mod", "download"},
+			},
+		},
+		Caches: []executor.Cache{
+			{
+				Bucket:   "codeintel-caches",
+				Key:      "50/go",
+				KeyFiles: []string{"go.sum"},
+				Paths:    []string{"/root/go/pkg/mod"},
+			},
+		},
+	}
+
+	var archive bytes.Buffer
+	filesStore := NewMockFilesStore()
+	filesStore.ExistsFunc.SetDefaultHook(func(ctx context.Context, bucket, key string) (bool, error) {
+		return archive.Len() > 0, nil
+	})
+	filesStore.GetFunc.SetDefaultHook(func(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(archive.Bytes())), nil
+	})
+	filesStore.UploadFunc.SetDefaultHook(func(ctx context.Context, bucket, key string, r io.Reader) error {
+		_, err := io.Copy(&archive, r)
+		return err
+	})
+
+	// handle runs the job in a fresh workspace, invoking the given hook for each
+	// docker step with the path of the first cache mount on the host.
+	handle := func(hook func(cacheDir string)) {
+		testDir := t.TempDir()
+		workspace.MakeTempDirectory = func(string) (string, error) { return testDir, nil }
+		t.Cleanup(func() {
+			workspace.MakeTempDirectory = workspace.MakeTemporaryDirectory
+		})
+
+		runner := NewMockRunner()
+		runner.RunFunc.SetDefaultHook(func(ctx context.Context, spec command.CommandSpec) error {
+			require.Len(t, spec.Mounts, 1)
+			assert.Equal(t, "/root/go/pkg/mod", spec.Mounts[0].Target)
+			hook(filepath.Join(testDir, spec.Mounts[0].Source))
+			return nil
+		})
+
+		h := &handler{
+			store:      NewMockStore[executor.Job](),
+			filesStore: filesStore,
+			nameSet:    janitor.NewNameSet(),
+			options:    Options{},
+			operations: command.NewOperations(&observation.TestContext),
+			runnerFactory: func(dir string, logger command.Logger, options command.Options, operations *command.Operations) command.Runner {
+				if dir == "" {
+					return NewMockRunner()
+				}
+
+				return runner
+			},
+		}
+
+		if err := h.Handle(context.Background(), logtest.Scoped(t), job); err != nil {
+			t.Fatalf("unexpected error handling record: %s", err)
+		}
+	}
+
+	// Nothing to restore on the first run: the step populates the cache, which is then saved
+	handle(func(cacheDir string) {
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "module.zip"), []byte("<module payload>"), os.ModePerm))
+	})
+
+	uploadHistory := filesStore.UploadFunc.History()
+	require.Len(t, uploadHistory, 1)
+	assert.Equal(t, "codeintel-caches", uploadHistory[0].Arg1)
+	assert.Regexp(t, `^50/go/[0-9a-f]{64}\.tar\.gz$`, uploadHistory[0].Arg2)
+
+	// The second run restores the cache and does not upload it again
+	handle(func(cacheDir string) {
+		contents, err := os.ReadFile(filepath.Join(cacheDir, "module.zip"))
+		require.NoError(t, err)
+		assert.Equal(t, "<module payload>", string(contents))
+	})
+
+	getHistory := filesStore.GetFunc.History()
+	require.Len(t, getHistory, 1)
+	assert.Equal(t, uploadHistory[0].Arg2, getHistory[0].Arg2)
+	assert.Len(t, filesStore.UploadFunc.History(), 1)
+}
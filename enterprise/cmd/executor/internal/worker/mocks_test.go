@@ -1441,6 +1441,9 @@ type MockFilesStore struct {
 	// GetFunc is an instance of a mock function object controlling the
 	// behavior of the method Get.
 	GetFunc *FilesStoreGetFunc
+	// UploadFunc is an instance of a mock function object controlling the
+	// behavior of the method Upload.
+	UploadFunc *FilesStoreUploadFunc
 }
 
 // NewMockFilesStore creates a new mock of the FilesStore interface. All
@@ -1457,6 +1460,11 @@ func NewMockFilesStore() *MockFilesStore {
 				return
 			},
 		},
+		UploadFunc: &FilesStoreUploadFunc{
+			defaultHook: func(context.Context, string, string, io.Reader) (r0 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -1474,6 +1482,11 @@ func NewStrictMockFilesStore() *MockFilesStore {
 				panic("unexpected invocation of MockFilesStore.Get")
 			},
 		},
+		UploadFunc: &FilesStoreUploadFunc{
+			defaultHook: func(context.Context, string, string, io.Reader) error {
+				panic("unexpected invocation of MockFilesStore.Upload")
+			},
+		},
 	}
 }
 
@@ -1487,6 +1500,9 @@ func NewMockFilesStoreFrom(i store.FilesStore) *MockFilesStore {
 		GetFunc: &FilesStoreGetFunc{
 			defaultHook: i.Get,
 		},
+		UploadFunc: &FilesStoreUploadFunc{
+			defaultHook: i.Upload,
+		},
 	}
 }
 
@@ -1709,3 +1725,113 @@ func (c FilesStoreGetFuncCall) Args() []interface{} {
 func (c FilesStoreGetFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// FilesStoreUploadFunc describes the behavior when the Upload method of the
+// parent MockFilesStore instance is invoked.
+type FilesStoreUploadFunc struct {
+	defaultHook func(context.Context, string, string, io.Reader) error
+	hooks       []func(context.Context, string, string, io.Reader) error
+	history     []FilesStoreUploadFuncCall
+	mutex       sync.Mutex
+}
+
+// Upload delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockFilesStore) Upload(v0 context.Context, v1 string, v2 string, v3 io.Reader) error {
+	r0 := m.UploadFunc.nextHook()(v0, v1, v2, v3)
+	m.UploadFunc.appendCall(FilesStoreUploadFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Upload method of the
+// parent MockFilesStore instance is invoked and the hook queue is empty.
+func (f *FilesStoreUploadFunc) SetDefaultHook(hook func(context.Context, string, string, io.Reader) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Upload method of the parent MockFilesStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *FilesStoreUploadFunc) PushHook(hook func(context.Context, string, string, io.Reader) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *FilesStoreUploadFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, string, string, io.Reader) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *FilesStoreUploadFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, string, string, io.Reader) error {
+		return r0
+	})
+}
+
+func (f *FilesStoreUploadFunc) nextHook() func(context.Context, string, string, io.Reader) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *FilesStoreUploadFunc) appendCall(r0 FilesStoreUploadFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of FilesStoreUploadFuncCall objects describing
+// the invocations of this function.
+func (f *FilesStoreUploadFunc) History() []FilesStoreUploadFuncCall {
+	f.mutex.Lock()
+	history := make([]FilesStoreUploadFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// FilesStoreUploadFuncCall is an object that describes an invocation of
+// method Upload on an instance of MockFilesStore.
+type FilesStoreUploadFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 io.Reader
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c FilesStoreUploadFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c FilesStoreUploadFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
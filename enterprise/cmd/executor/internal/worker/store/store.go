@@ -72,4 +72,6 @@ type FilesStore interface {
 	Exists(ctx context.Context, bucket string, key string) (bool, error)
 	// Get retrieves the file.
 	Get(ctx context.Context, bucket string, key string) (io.ReadCloser, error)
+	// Upload writes the file.
+	Upload(ctx context.Context, bucket string, key string, r io.Reader) error
 }
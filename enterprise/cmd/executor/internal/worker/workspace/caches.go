@@ -0,0 +1,292 @@
+package workspace
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/worker/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// cachesPath is the location relative to the workspace root into which dependency
+// caches are restored before the job's steps are invoked.
+var cachesPath = filepath.Join(command.ScriptsPath, "caches")
+
+// Cache is a dependency cache that has been prepared within the workspace.
+type Cache struct {
+	// Bucket is the bucket in the files store holding the cache archive.
+	Bucket string
+
+	// Key is the key of the cache archive in the files store.
+	Key string
+
+	// Dir is the directory relative to the workspace root holding the cache contents.
+	Dir string
+
+	// Mounts map the subdirectories of Dir to their location in the step containers.
+	Mounts []command.Mount
+
+	// Restored is true if the cache archive existed and was extracted into Dir.
+	Restored bool
+}
+
+// prepareCaches creates a directory for each cache configured for the given job and
+// restores the matching archive from the files store into it, if one exists. Cache
+// failures are logged but never fail the job; a missing cache only costs time.
+func prepareCaches(
+	ctx context.Context,
+	filesStore store.FilesStore,
+	job executor.Job,
+	workspaceDir string,
+	logger command.Logger,
+) ([]Cache, error) {
+	// Bail out early if nothing to do, we don't need to spawn an empty log group.
+	if len(job.Caches) == 0 {
+		return nil, nil
+	}
+
+	handle := logger.Log("setup.fs.caches", nil)
+	defer func() {
+		// We always finish this with exit code 0, a cache miss doesn't fail the job.
+		handle.Finalize(0)
+		_ = handle.Close()
+	}()
+
+	caches := make([]Cache, 0, len(job.Caches))
+	for i, jobCache := range job.Caches {
+		hash, err := hashKeyFiles(filepath.Join(workspaceDir, job.RepositoryDirectory), jobCache.KeyFiles)
+		if err != nil {
+			return nil, err
+		}
+
+		cache := Cache{
+			Bucket: jobCache.Bucket,
+			Key:    fmt.Sprintf("%s/%s.tar.gz", jobCache.Key, hash),
+			Dir:    filepath.Join(cachesPath, strconv.Itoa(i)),
+		}
+		for j, path := range jobCache.Paths {
+			cache.Mounts = append(cache.Mounts, command.Mount{
+				Source: filepath.Join(cache.Dir, strconv.Itoa(j)),
+				Target: path,
+			})
+		}
+		if err := makeCacheDirs(workspaceDir, cache); err != nil {
+			return nil, err
+		}
+
+		if filesStore != nil {
+			restored, err := restoreCache(ctx, filesStore, workspaceDir, cache)
+			if err != nil {
+				fmt.Fprintf(handle, "Failed to restore cache %s: %s\n", cache.Key, err)
+
+				// Start from a clean slate rather than a partially extracted archive.
+				if err := os.RemoveAll(filepath.Join(workspaceDir, cache.Dir)); err != nil {
+					return nil, err
+				}
+				if err := makeCacheDirs(workspaceDir, cache); err != nil {
+					return nil, err
+				}
+			} else if restored {
+				fmt.Fprintf(handle, "Restored cache %s\n", cache.Key)
+			} else {
+				fmt.Fprintf(handle, "No cache found for %s\n", cache.Key)
+			}
+			cache.Restored = restored
+		}
+
+		caches = append(caches, cache)
+	}
+
+	return caches, nil
+}
+
+// saveCaches archives the contents of each cache that was not restored from the files
+// store and uploads it under the cache's key. Failures are logged to the given handle
+// but are not returned.
+func saveCaches(ctx context.Context, filesStore store.FilesStore, workspaceDir string, caches []Cache, handle command.LogEntry) {
+	for _, cache := range caches {
+		if cache.Restored {
+			fmt.Fprintf(handle, "Cache %s is up to date\n", cache.Key)
+			continue
+		}
+
+		if err := saveCache(ctx, filesStore, workspaceDir, cache); err != nil {
+			fmt.Fprintf(handle, "Failed to save cache %s: %s\n", cache.Key, err)
+			continue
+		}
+
+		fmt.Fprintf(handle, "Saved cache %s\n", cache.Key)
+	}
+}
+
+// hashKeyFiles returns a hex-encoded hash of the names and contents of the given key
+// files, relative to the given root. Missing files contribute only their name.
+func hashKeyFiles(root string, keyFiles []string) (string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, keyFile := range keyFiles {
+		path := filepath.Join(root, keyFile)
+		if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return "", errors.Errorf("refusing to read cache key file outside of working directory: %q", keyFile)
+		}
+
+		_, _ = io.WriteString(h, keyFile)
+		_, _ = h.Write([]byte{0})
+
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+		_, _ = h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func makeCacheDirs(workspaceDir string, cache Cache) error {
+	for _, mount := range cache.Mounts {
+		if err := os.MkdirAll(filepath.Join(workspaceDir, mount.Source), os.ModePerm); err != nil {
+			return errors.Wrap(err, "creating cache path")
+		}
+	}
+
+	return nil
+}
+
+func restoreCache(ctx context.Context, filesStore store.FilesStore, workspaceDir string, cache Cache) (bool, error) {
+	exists, err := filesStore.Exists(ctx, cache.Bucket, cache.Key)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	rc, err := filesStore.Get(ctx, cache.Bucket, cache.Key)
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	if err := unpack.Tgz(rc, filepath.Join(workspaceDir, cache.Dir), unpack.Opts{SkipInvalid: true}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func saveCache(ctx context.Context, filesStore store.FilesStore, workspaceDir string, cache Cache) error {
+	f, err := MakeTempFile("cache")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	if err := writeTgz(f, filepath.Join(workspaceDir, cache.Dir)); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return filesStore.Upload(ctx, cache.Bucket, cache.Key, f)
+}
+
+// writeTgz writes a gzipped tarball of the contents of the given directory to w.
+// Only directories, regular files, and symbolic links are archived.
+func writeTgz(w io.Writer, dir string) (err error) {
+	gzw := gzip.NewWriter(w)
+	defer func() {
+		if closeErr := gzw.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	tw := tar.NewWriter(gzw)
+	defer func() {
+		if closeErr := tw.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch mode := info.Mode(); {
+		case mode.IsDir(), mode.IsRegular():
+		case mode&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
@@ -0,0 +1,287 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+)
+
+func TestHashKeyFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"go.sum":             "v1",
+		"web/package-lock":   "v1",
+		"unrelated/file.txt": "v1",
+	})
+
+	hash := func(root string, keyFiles ...string) string {
+		t.Helper()
+		h, err := hashKeyFiles(root, keyFiles)
+		if err != nil {
+			t.Fatalf("unexpected error hashing key files: %s", err)
+		}
+		return h
+	}
+
+	original := hash(root, "go.sum", "web/package-lock")
+	if h := hash(root, "go.sum", "web/package-lock"); h != original {
+		t.Errorf("expected stable hash. want=%s have=%s", original, h)
+	}
+	if h := hash(root, "web/package-lock", "go.sum"); h == original {
+		t.Errorf("expected key file order to change the hash")
+	}
+	if h := hash(root, "go.sum", "web/package-lock", "missing.lock"); h == original {
+		t.Errorf("expected missing key file name to change the hash")
+	}
+
+	// Files not used as keys do not affect the hash
+	writeTestFiles(t, root, map[string]string{"unrelated/file.txt": "v2"})
+	if h := hash(root, "go.sum", "web/package-lock"); h != original {
+		t.Errorf("expected unrelated file to leave hash unchanged. want=%s have=%s", original, h)
+	}
+
+	// Key file contents affect the hash
+	writeTestFiles(t, root, map[string]string{"go.sum": "v2"})
+	if h := hash(root, "go.sum", "web/package-lock"); h == original {
+		t.Errorf("expected key file contents to change the hash")
+	}
+
+	// Relative roots hash identically to their absolute counterparts
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relativeRoot, err := filepath.Rel(wd, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := hash(relativeRoot, "go.sum"), hash(root, "go.sum"); have != want {
+		t.Errorf("unexpected hash for relative root. want=%s have=%s", want, have)
+	}
+}
+
+func TestHashKeyFilesPathEscape(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "repo")
+	writeTestFiles(t, dir, map[string]string{
+		"repo/go.sum":       "",
+		"repo-other/secret": "hunter2",
+		"secret":            "hunter2",
+	})
+
+	for _, keyFile := range []string{
+		"../secret",
+		"sub/../../secret",
+		"../repo-other/secret",
+		".",
+	} {
+		if _, err := hashKeyFiles(root, []string{"go.sum", keyFile}); err == nil {
+			t.Errorf("expected error hashing key file %q", keyFile)
+		} else if !strings.Contains(err.Error(), "outside of working directory") {
+			t.Errorf("unexpected error hashing key file %q: %s", keyFile, err)
+		}
+	}
+}
+
+func TestWriteTgzRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"0/a.txt":         "a",
+		"0/nested/b.txt":  "b",
+		"1/c.txt":         "c",
+		"1/empty/.keep":   "",
+		"1/nested/d/e.go": "package e",
+	})
+	if err := os.Symlink("a.txt", filepath.Join(src, "0", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeTgz(&buf, src); err != nil {
+		t.Fatalf("unexpected error writing archive: %s", err)
+	}
+
+	dst := t.TempDir()
+	if err := unpack.Tgz(&buf, dst, unpack.Opts{}); err != nil {
+		t.Fatalf("unexpected error unpacking archive: %s", err)
+	}
+
+	if diff := cmp.Diff(readTestFiles(t, src), readTestFiles(t, dst)); diff != "" {
+		t.Errorf("unexpected contents (-want +got):\n%s", diff)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "0", "link")); err != nil {
+		t.Errorf("unexpected error reading symlink: %s", err)
+	} else if target != "a.txt" {
+		t.Errorf("unexpected symlink target. want=%q have=%q", "a.txt", target)
+	}
+}
+
+func TestPrepareCaches(t *testing.T) {
+	workspaceDir := t.TempDir()
+	writeTestFiles(t, workspaceDir, map[string]string{"repository/go.sum": "v1"})
+
+	job := executor.Job{
+		RepositoryDirectory: "repository",
+		Caches: []executor.Cache{
+			{Bucket: "caches", Key: "restored", KeyFiles: []string{"go.sum"}, Paths: []string{"/root/go"}},
+			{Bucket: "caches", Key: "missing", KeyFiles: []string{"go.sum"}, Paths: []string{"/root/.npm"}},
+			{Bucket: "caches", Key: "corrupt", KeyFiles: []string{"go.sum"}, Paths: []string{"/root/.cargo"}},
+		},
+	}
+
+	hash, err := hashKeyFiles(filepath.Join(workspaceDir, "repository"), []string{"go.sum"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archiveDir := t.TempDir()
+	writeTestFiles(t, archiveDir, map[string]string{"0/pkg/mod.txt": "cached"})
+	var archive bytes.Buffer
+	if err := writeTgz(&archive, archiveDir); err != nil {
+		t.Fatal(err)
+	}
+
+	filesStore := &memoryFilesStore{files: map[string][]byte{
+		"caches/restored/" + hash + ".tar.gz": archive.Bytes(),
+		"caches/corrupt/" + hash + ".tar.gz":  []byte("not an archive"),
+	}}
+
+	var logs bytes.Buffer
+	caches, err := prepareCaches(context.Background(), filesStore, job, workspaceDir, command.NewWriterLogger(&logs))
+	if err != nil {
+		t.Fatalf("unexpected error preparing caches: %s", err)
+	}
+
+	expectedCaches := []Cache{
+		{
+			Bucket:   "caches",
+			Key:      "restored/" + hash + ".tar.gz",
+			Dir:      filepath.Join(cachesPath, "0"),
+			Mounts:   []command.Mount{{Source: filepath.Join(cachesPath, "0", "0"), Target: "/root/go"}},
+			Restored: true,
+		},
+		{
+			Bucket: "caches",
+			Key:    "missing/" + hash + ".tar.gz",
+			Dir:    filepath.Join(cachesPath, "1"),
+			Mounts: []command.Mount{{Source: filepath.Join(cachesPath, "1", "0"), Target: "/root/.npm"}},
+		},
+		{
+			Bucket: "caches",
+			Key:    "corrupt/" + hash + ".tar.gz",
+			Dir:    filepath.Join(cachesPath, "2"),
+			Mounts: []command.Mount{{Source: filepath.Join(cachesPath, "2", "0"), Target: "/root/.cargo"}},
+		},
+	}
+	if diff := cmp.Diff(expectedCaches, caches); diff != "" {
+		t.Fatalf("unexpected caches (-want +got):\n%s", diff)
+	}
+
+	// The restored cache is extracted, the missing and corrupt caches start out empty
+	expectedFiles := map[string]string{"0/0/pkg/mod.txt": "cached"}
+	if diff := cmp.Diff(expectedFiles, readTestFiles(t, filepath.Join(workspaceDir, cachesPath))); diff != "" {
+		t.Errorf("unexpected cache contents (-want +got):\n%s", diff)
+	}
+	for _, cache := range caches {
+		for _, mount := range cache.Mounts {
+			if info, err := os.Stat(filepath.Join(workspaceDir, mount.Source)); err != nil || !info.IsDir() {
+				t.Errorf("expected cache mount %s to be a directory", mount.Source)
+			}
+		}
+	}
+	if !strings.Contains(logs.String(), "Failed to restore cache corrupt/") {
+		t.Errorf("expected failed restore to be logged, have %q", logs.String())
+	}
+
+	// Only caches that were not restored are saved
+	writeTestFiles(t, filepath.Join(workspaceDir, cachesPath), map[string]string{"1/0/npm.txt": "fresh"})
+	saveCaches(context.Background(), filesStore, workspaceDir, caches, command.NewWriterLogger(&logs).Log("teardown.fs.caches", nil))
+
+	if diff := cmp.Diff([]string{"caches/missing/" + hash + ".tar.gz", "caches/corrupt/" + hash + ".tar.gz"}, filesStore.uploaded); diff != "" {
+		t.Errorf("unexpected uploads (-want +got):\n%s", diff)
+	}
+
+	saved := t.TempDir()
+	if err := unpack.Tgz(bytes.NewReader(filesStore.files["caches/missing/"+hash+".tar.gz"]), saved, unpack.Opts{}); err != nil {
+		t.Fatalf("unexpected error unpacking saved cache: %s", err)
+	}
+	if diff := cmp.Diff(map[string]string{"0/npm.txt": "fresh"}, readTestFiles(t, saved)); diff != "" {
+		t.Errorf("unexpected saved cache contents (-want +got):\n%s", diff)
+	}
+}
+
+type memoryFilesStore struct {
+	files    map[string][]byte
+	uploaded []string
+}
+
+func (s *memoryFilesStore) Exists(_ context.Context, bucket, key string) (bool, error) {
+	_, ok := s.files[bucket+"/"+key]
+	return ok, nil
+}
+
+func (s *memoryFilesStore) Get(_ context.Context, bucket, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.files[bucket+"/"+key])), nil
+}
+
+func (s *memoryFilesStore) Upload(_ context.Context, bucket, key string, r io.Reader) error {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.files[bucket+"/"+key] = contents
+	s.uploaded = append(s.uploaded, bucket+"/"+key)
+	return nil
+}
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for path, contents := range files {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTestFiles returns the contents of the regular files under root keyed by their
+// slash-separated path relative to root.
+func readTestFiles(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	files := map[string]string{}
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(contents)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
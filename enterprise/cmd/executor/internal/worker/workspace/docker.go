@@ -40,10 +40,18 @@ func NewDockerWorkspace(
 		return nil, err
 	}
 
+	caches, err := prepareCaches(ctx, filesStore, job, workspaceDir, logger)
+	if err != nil {
+		_ = os.RemoveAll(workspaceDir)
+		return nil, err
+	}
+
 	return &dockerWorkspace{
 		path:            workspaceDir,
 		scriptFilenames: scriptPaths,
 		workspaceDir:    workspaceDir,
+		filesStore:      filesStore,
+		caches:          caches,
 		logger:          logger,
 	}, nil
 }
@@ -52,6 +60,8 @@ type dockerWorkspace struct {
 	path            string
 	scriptFilenames []string
 	workspaceDir    string
+	filesStore      store.FilesStore
+	caches          []Cache
 	logger          command.Logger
 }
 
@@ -63,6 +73,25 @@ func (w dockerWorkspace) ScriptFilenames() []string {
 	return w.scriptFilenames
 }
 
+func (w dockerWorkspace) Caches() []Cache {
+	return w.caches
+}
+
+func (w dockerWorkspace) SaveCaches(ctx context.Context) {
+	if w.filesStore == nil || len(w.caches) == 0 {
+		return
+	}
+
+	handle := w.logger.Log("teardown.fs.caches", nil)
+	defer func() {
+		// We always finish this with exit code 0, failing to save a cache doesn't fail the job.
+		handle.Finalize(0)
+		handle.Close()
+	}()
+
+	saveCaches(ctx, w.filesStore, w.workspaceDir, w.caches, handle)
+}
+
 func (w dockerWorkspace) Remove(ctx context.Context, keepWorkspace bool) {
 	handle := w.logger.Log("teardown.fs", nil)
 	defer func() {
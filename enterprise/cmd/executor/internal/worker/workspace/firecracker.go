@@ -66,10 +66,17 @@ func NewFirecrackerWorkspace(
 		return nil, err
 	}
 
+	caches, err := prepareCaches(ctx, filesStore, job, tmpMountDir, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	return &firecrackerWorkspace{
 		scriptFilenames: scriptPaths,
 		blockDeviceFile: blockDeviceFile,
 		blockDevice:     blockDevice,
+		filesStore:      filesStore,
+		caches:          caches,
 		logger:          logger,
 	}, err
 }
@@ -78,6 +85,8 @@ type firecrackerWorkspace struct {
 	scriptFilenames []string
 	blockDeviceFile string
 	blockDevice     string
+	filesStore      store.FilesStore
+	caches          []Cache
 	logger          command.Logger
 }
 
@@ -89,6 +98,40 @@ func (w firecrackerWorkspace) ScriptFilenames() []string {
 	return w.scriptFilenames
 }
 
+func (w firecrackerWorkspace) Caches() []Cache {
+	return w.caches
+}
+
+func (w firecrackerWorkspace) SaveCaches(ctx context.Context) {
+	if w.filesStore == nil || len(w.caches) == 0 {
+		return
+	}
+
+	handle := w.logger.Log("teardown.fs.caches", nil)
+	defer func() {
+		// We always finish this with exit code 0, failing to save a cache doesn't fail the job.
+		handle.Finalize(0)
+		handle.Close()
+	}()
+
+	// Remount the workspace (the VM has been torn down at this point) so that we can
+	// read the cache contents written by the job's steps.
+	mountDir, err := mountLoopDevice(ctx, w.blockDevice, handle)
+	if err != nil {
+		fmt.Fprintf(handle, "Failed to mount workspace device %q: %s\n", w.blockDevice, err)
+		return
+	}
+	defer func() {
+		if err := syscall.Unmount(mountDir, 0); err != nil {
+			fmt.Fprintf(handle, "stderr: Failed to unmount workspace device: %s\n", err)
+			return
+		}
+		_ = os.RemoveAll(mountDir)
+	}()
+
+	saveCaches(ctx, w.filesStore, mountDir, w.caches, handle)
+}
+
 func (w firecrackerWorkspace) Remove(ctx context.Context, keepWorkspace bool) {
 	handle := w.logger.Log("teardown.fs", nil)
 	defer func() {
@@ -14,6 +14,12 @@ type Workspace interface {
 	Path() string
 	// ScriptFilenames holds the ordered set of script filenames to be invoked.
 	ScriptFilenames() []string
+	// Caches holds the dependency caches prepared in the workspace, in the order in
+	// which they are configured on the job.
+	Caches() []Cache
+	// SaveCaches uploads the contents of the dependency caches that could not be
+	// restored from the files store. Failures are logged but do not fail the job.
+	SaveCaches(ctx context.Context)
 	// Remove cleans up the workspace post execution. If keep workspace is true,
 	// the implementation will only clean up additional resources, while keeping
 	// the workspace contents on disk for debugging purposes.
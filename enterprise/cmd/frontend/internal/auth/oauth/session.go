@@ -131,14 +131,15 @@ func SessionIssuer(logger log.Logger, db database.DB, s SessionIssuerHelper, ses
 			Timestamp: time.Now(),
 		})
 
-		if err := session.SetActor(w, r, actr, expiryDuration, user.CreatedAt); err != nil { // TODO: test session expiration
+		sessionData := s.SessionData(token)
+		if err := session.SetActor(w, r, actr, expiryDuration, user.CreatedAt, sessionData.ID.Type); err != nil { // TODO: test session expiration
 			span.SetError(err)
 			logger.Error("OAuth failed: could not initiate session.", log.Error(err))
 			http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: could not initiate session.", http.StatusInternalServerError)
 			return
 		}
 
-		if err := session.SetData(w, r, sessionKey, s.SessionData(token)); err != nil {
+		if err := session.SetData(w, r, sessionKey, sessionData); err != nil {
 			// It's not fatal if this fails. It just means we won't be able to sign the user out of
 			// the OP.
 			span.AddEvent(err.Error()) // do not set error
@@ -117,13 +117,16 @@ func validateConfig(c conftypes.SiteConfigQuerier) (problems conf.Problems) {
 		}
 	}
 
-	seen := map[schema.OpenIDConnectAuthProvider]int{}
+	seen := map[string]int{}
 	for i, p := range c.SiteConfig().AuthProviders {
 		if p.Openidconnect != nil {
-			if j, ok := seen[*p.Openidconnect]; ok {
+			// we can ignore errors: converting to JSON must work, as we parsed from JSON before
+			bytes, _ := json.Marshal(*p.Openidconnect)
+			key := string(bytes)
+			if j, ok := seen[key]; ok {
 				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("OpenID Connect auth provider at index %d is duplicate of index %d, ignoring", i, j)))
 			} else {
-				seen[*p.Openidconnect] = i
+				seen[key] = i
 			}
 		}
 	}
@@ -170,7 +170,7 @@ func authHandler(db database.DB) func(w http.ResponseWriter, r *http.Request) {
 			// if !idToken.Expiry.IsZero() {
 			// 	exp = time.Until(idToken.Expiry)
 			// }
-			if err = session.SetActor(w, r, actor.FromUser(result.User.ID), exp, result.User.CreatedAt); err != nil {
+			if err = session.SetActor(w, r, actor.FromUser(result.User.ID), exp, result.User.CreatedAt, providerType); err != nil {
 				log15.Error("Failed to authenticate with OpenID connect: could not initiate session.", "error", err)
 				http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: could not initiate session.", http.StatusInternalServerError)
 				return
@@ -328,6 +328,27 @@ func AuthCallback(db database.DB, r *http.Request, stateCookieName, usernamePref
 			errors.Wrap(err, "look up authenticated user")
 	}
 
+	// Roles are synced even without role groups, so that roles assigned by this provider are
+	// revoked once its role groups are removed from the configuration.
+	var groups map[string]bool
+	if len(p.config.RoleGroups) > 0 {
+		var rawClaims map[string]any
+		if err := userInfo.Claims(&rawClaims); err != nil {
+			return nil,
+				"Failed to read the groups of the user.",
+				http.StatusInternalServerError,
+				errors.Wrap(err, "parse userInfo claims")
+		}
+		groups = groupsFromClaims(rawClaims, p.config.GroupsClaimName)
+	}
+	roleSource := p.config.Type + ":" + p.config.Issuer
+	if err := auth.SyncRolesFromGroups(r.Context(), db, actor.UID, roleSource, p.config.RoleGroups, groups); err != nil {
+		return nil,
+			"Failed to assign roles based on the groups of the user.",
+			http.StatusInternalServerError,
+			errors.Wrap(err, "sync roles from groups")
+	}
+
 	user, err := db.Users().GetByID(r.Context(), actor.UID)
 	if err != nil {
 		return nil,
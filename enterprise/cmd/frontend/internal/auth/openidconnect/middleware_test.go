@@ -145,6 +145,7 @@ func TestMiddleware(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.RolesFunc.SetDefaultReturn(database.NewMockRoleStore())
 
 	securityLogs := database.NewStrictMockSecurityEventLogsStore()
 	db.SecurityEventLogsFunc.SetDefaultReturn(securityLogs)
@@ -364,6 +365,7 @@ func TestMiddleware_NoOpenRedirect(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.RolesFunc.SetDefaultReturn(database.NewMockRoleStore())
 
 	securityLogs := database.NewStrictMockSecurityEventLogsStore()
 	db.SecurityEventLogsFunc.SetDefaultReturn(securityLogs)
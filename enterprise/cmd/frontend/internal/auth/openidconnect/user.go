@@ -94,3 +94,24 @@ func getOrCreateUser(ctx context.Context, db database.DB, p *Provider, idToken *
 	}
 	return actor.FromUser(userID), "", nil
 }
+
+// groupsFromClaims returns the groups in the claim with the given name, which
+// may hold a single group or a list of groups.
+func groupsFromClaims(claims map[string]any, claimName string) map[string]bool {
+	if claimName == "" {
+		claimName = "groups"
+	}
+
+	groups := make(map[string]bool)
+	switch v := claims[claimName].(type) {
+	case string:
+		groups[v] = true
+	case []any:
+		for _, g := range v {
+			if group, ok := g.(string); ok {
+				groups[group] = true
+			}
+		}
+	}
+	return groups
+}
@@ -75,3 +75,34 @@ func TestAllowSignup(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupsFromClaims(t *testing.T) {
+	tests := map[string]struct {
+		claims    map[string]any
+		claimName string
+		want      map[string]bool
+	}{
+		"list of groups": {
+			claims: map[string]any{"groups": []any{"managers", "engineers"}},
+			want:   map[string]bool{"managers": true, "engineers": true},
+		},
+		"single group": {
+			claims: map[string]any{"groups": "managers"},
+			want:   map[string]bool{"managers": true},
+		},
+		"custom claim name": {
+			claims:    map[string]any{"groups": "engineers", "roles": []any{"managers"}},
+			claimName: "roles",
+			want:      map[string]bool{"managers": true},
+		},
+		"missing claim": {
+			claims: map[string]any{},
+			want:   map[string]bool{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, groupsFromClaims(test.claims, test.claimName))
+		})
+	}
+}
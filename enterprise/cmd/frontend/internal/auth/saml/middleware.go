@@ -156,6 +156,13 @@ func samlSPHandler(db database.DB) func(w http.ResponseWriter, r *http.Request)
 				return
 			}
 
+			roleSource := info.spec.ServiceType + ":" + info.spec.ServiceID
+			if err := auth.SyncRolesFromGroups(r.Context(), db, actor.UID, roleSource, p.config.RoleGroups, info.groups); err != nil {
+				log15.Error("Error assigning roles to SAML-authenticated user.", "err", err)
+				http.Error(w, "Error assigning roles based on SAML groups. Try signing in again.", http.StatusInternalServerError)
+				return
+			}
+
 			user, err := db.Users().GetByID(r.Context(), actor.UID)
 			if err != nil {
 				log15.Error("Error retrieving SAML-authenticated user from database.", "error", err)
@@ -174,7 +181,7 @@ func samlSPHandler(db database.DB) func(w http.ResponseWriter, r *http.Request)
 			// if info.SessionNotOnOrAfter != nil {
 			// 	exp = time.Until(*info.SessionNotOnOrAfter)
 			// }
-			if err := session.SetActor(w, r, actor, exp, user.CreatedAt); err != nil {
+			if err := session.SetActor(w, r, actor, exp, user.CreatedAt, providerType); err != nil {
 				log15.Error("Error setting SAML-authenticated actor in session.", "err", err)
 				http.Error(w, "Error starting SAML-authenticated session. Try signing in again.", http.StatusInternalServerError)
 				return
@@ -208,7 +215,7 @@ func samlSPHandler(db database.DB) func(w http.ResponseWriter, r *http.Request)
 			// If this is an SP-initiated logout, then the actor has already been cleared from the
 			// session (but there's no harm in clearing it again). If it's an IdP-initiated logout,
 			// then it hasn't, and we must clear it here.
-			if err := session.SetActor(w, r, nil, 0, time.Time{}); err != nil {
+			if err := session.SetActor(w, r, nil, 0, time.Time{}, ""); err != nil {
 				log15.Error("Error clearing actor from session in SAML logout handler.", "err", err)
 				http.Error(w, "Error signing out of SAML-authenticated session.", http.StatusInternalServerError)
 				return
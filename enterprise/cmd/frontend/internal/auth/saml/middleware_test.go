@@ -215,6 +215,7 @@ func TestMiddleware(t *testing.T) {
 
 	db := database.NewStrictMockDB()
 	db.UsersFunc.SetDefaultReturn(users)
+	db.RolesFunc.SetDefaultReturn(database.NewMockRoleStore())
 
 	// Set up the test handler.
 	authedHandler := http.NewServeMux()
@@ -148,7 +148,7 @@ func authHandler(db database.DB) func(w http.ResponseWriter, r *http.Request) {
 				UID:                 result.User.ID,
 				SourcegraphOperator: true,
 			}
-			err = session.SetActor(w, r, act, expiry, result.User.CreatedAt)
+			err = session.SetActor(w, r, act, expiry, result.User.CreatedAt, internalauth.SourcegraphOperatorProviderType)
 			if err != nil {
 				logger.Error("failed to authenticate with Sourcegraph Operator", log.Error(errors.Wrap(err, "initiate session")))
 				http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: could not initiate session.", http.StatusInternalServerError)
@@ -0,0 +1,278 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// BulkImportHandler handles the upload of documents with explicit repository
+// permissions, which are applied asynchronously by the worker.
+type BulkImportHandler struct {
+	logger      sglog.Logger
+	db          database.DB
+	uploadStore uploadstore.Store
+}
+
+// NewBulkImportHandler creates a new BulkImportHandler. Uploaded documents are
+// streamed to the given upload store, from which the worker reads them.
+func NewBulkImportHandler(db database.DB, uploadStore uploadstore.Store) *BulkImportHandler {
+	return &BulkImportHandler{
+		logger:      sglog.Scoped("BulkImportHandler", "explicit permissions bulk import REST API handler"),
+		db:          db,
+		uploadStore: uploadStore,
+	}
+}
+
+const maxBulkImportSize = 100 << 20 // 100MB
+
+// bulkImportKeyPrefix is the prefix of the keys of uploaded documents in the
+// upload store.
+const bulkImportKeyPrefix = "explicit-permissions-bulk-imports/"
+
+// deletePayloadTimeout bounds the deletion of documents that won't be imported.
+const deletePayloadTimeout = 30 * time.Second
+
+// Upload enqueues a bulk import of the explicit permissions in the request body.
+func (h *BulkImportHandler) Upload() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBulkImportSize)
+		responseBody, statusCode, err := h.upload(r)
+		if err != nil {
+			http.Error(w, err.Error(), statusCode)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		if err = json.NewEncoder(w).Encode(responseBody); err != nil {
+			h.logger.Error("failed to write json payload to client", sglog.Error(err))
+		}
+	})
+}
+
+type uploadResponse struct {
+	ID int `json:"id"`
+}
+
+func (h *BulkImportHandler) upload(r *http.Request) (resp uploadResponse, statusCode int, err error) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Only site admins can mutate repository permissions.
+	if statusCode, err := h.checkAccess(r); err != nil {
+		return resp, statusCode, err
+	}
+
+	format, err := bulkImportFormat(r)
+	if err != nil {
+		return resp, http.StatusBadRequest, err
+	}
+	mode, err := bulkImportMode(r)
+	if err != nil {
+		return resp, http.StatusBadRequest, err
+	}
+
+	// The document is streamed to the upload store rather than buffered, the
+	// job only references it.
+	key := bulkImportKeyPrefix + uuid.New().String()
+	size, err := h.uploadStore.Upload(ctx, key, r.Body)
+	if err != nil {
+		h.deletePayload(key)
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return resp, http.StatusRequestEntityTooLarge, errors.New("request payload exceeds 100MB limit")
+		}
+		return resp, http.StatusInternalServerError, errors.Wrap(err, "storing request")
+	}
+
+	creatorUserID := actor.FromContext(ctx).UID
+	id, err := h.db.ExplicitPermissionsBulkImportJobs().Enqueue(ctx, format, mode, key, creatorUserID)
+	if err != nil {
+		h.deletePayload(key)
+		return resp, http.StatusInternalServerError, errors.Wrap(err, "enqueueing bulk import")
+	}
+
+	audit.Log(ctx, h.logger, audit.Record{
+		Entity: "explicit repository permissions",
+		Action: "bulk import enqueued",
+		Fields: []sglog.Field{
+			sglog.Int("job_id", id),
+			sglog.String("format", string(format)),
+			sglog.String("mode", string(mode)),
+			sglog.Int64("payload_size", size),
+		},
+	})
+
+	resp.ID = id
+	return resp, http.StatusAccepted, nil
+}
+
+// bulkImportFormat returns the format of the request body, as given by the
+// format query parameter or the Content-Type header.
+func bulkImportFormat(r *http.Request) (types.ExplicitPermissionsBulkImportFormat, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+	case string(types.ExplicitPermissionsBulkImportFormatCSV), string(types.ExplicitPermissionsBulkImportFormatJSONL):
+		return types.ExplicitPermissionsBulkImportFormat(format), nil
+	default:
+		return "", errors.Errorf("unsupported format %q, must be one of %q or %q", format, types.ExplicitPermissionsBulkImportFormatCSV, types.ExplicitPermissionsBulkImportFormatJSONL)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return types.ExplicitPermissionsBulkImportFormatCSV, nil
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return types.ExplicitPermissionsBulkImportFormatJSONL, nil
+	}
+	return "", errors.New("unable to determine the format of the request body, set the format query parameter or the Content-Type header")
+}
+
+// deletePayload removes a document that won't be imported from the upload
+// store. Failures are only logged, since the upload store expires old objects.
+// The deletion is detached from the request context, which is usually canceled
+// when the upload failed.
+func (h *BulkImportHandler) deletePayload(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), deletePayloadTimeout)
+	defer cancel()
+
+	if err := h.uploadStore.Delete(ctx, key); err != nil {
+		h.logger.Warn("failed to delete bulk import payload", sglog.String("key", key), sglog.Error(err))
+	}
+}
+
+// bulkImportMode returns whether the permissions of the request body are merged
+// with or replace the existing permissions of the matched repositories, as
+// given by the mode query parameter. Permissions are merged by default.
+func bulkImportMode(r *http.Request) (types.ExplicitPermissionsBulkImportMode, error) {
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "":
+		return types.ExplicitPermissionsBulkImportModeMerge, nil
+	case string(types.ExplicitPermissionsBulkImportModeMerge), string(types.ExplicitPermissionsBulkImportModeReplace):
+		return types.ExplicitPermissionsBulkImportMode(mode), nil
+	default:
+		return "", errors.Errorf("unsupported mode %q, must be one of %q or %q", mode, types.ExplicitPermissionsBulkImportModeMerge, types.ExplicitPermissionsBulkImportModeReplace)
+	}
+}
+
+// Get returns the state and the errors of a bulk import.
+func (h *BulkImportHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responseBody, statusCode, err := h.get(r)
+		if err != nil {
+			http.Error(w, err.Error(), statusCode)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		if err = json.NewEncoder(w).Encode(responseBody); err != nil {
+			h.logger.Error("failed to write json payload to client", sglog.Error(err))
+		}
+	})
+}
+
+type getResponse struct {
+	ID             int                   `json:"id"`
+	State          string                `json:"state"`
+	FailureMessage *string               `json:"failureMessage"`
+	QueuedAt       time.Time             `json:"queuedAt"`
+	StartedAt      *time.Time            `json:"startedAt"`
+	FinishedAt     *time.Time            `json:"finishedAt"`
+	ProcessedLines int                   `json:"processedLines"`
+	FailedLines    int                   `json:"failedLines"`
+	Errors         []bulkImportLineError `json:"errors"`
+}
+
+type bulkImportLineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (h *BulkImportHandler) get(r *http.Request) (resp getResponse, statusCode int, err error) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Only site admins can view bulk imports, since their errors
+	// reveal the existence of users and repositories.
+	if statusCode, err := h.checkAccess(r); err != nil {
+		return resp, statusCode, err
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return resp, http.StatusBadRequest, errors.New("invalid bulk import ID")
+	}
+
+	store := h.db.ExplicitPermissionsBulkImportJobs()
+	job, err := store.GetByID(ctx, id)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return resp, http.StatusNotFound, errors.New("bulk import does not exist")
+		}
+		return resp, http.StatusInternalServerError, errors.Wrap(err, "looking up bulk import")
+	}
+
+	lineErrs, err := store.ListErrors(ctx, id)
+	if err != nil {
+		return resp, http.StatusInternalServerError, errors.Wrap(err, "listing bulk import errors")
+	}
+
+	resp = getResponse{
+		ID:             job.ID,
+		State:          job.State,
+		FailureMessage: job.FailureMessage,
+		QueuedAt:       job.QueuedAt,
+		StartedAt:      job.StartedAt,
+		FinishedAt:     job.FinishedAt,
+		ProcessedLines: job.ProcessedLines,
+		FailedLines:    job.FailedLines,
+		Errors:         make([]bulkImportLineError, 0, len(lineErrs)),
+	}
+	for _, e := range lineErrs {
+		resp.Errors = append(resp.Errors, bulkImportLineError{Line: e.Line, Message: e.Message})
+	}
+	return resp, http.StatusOK, nil
+}
+
+// checkAccess returns an error and the matching status code if bulk imports
+// are not available to the current user.
+func (h *BulkImportHandler) checkAccess(r *http.Request) (int, error) {
+	if envvar.SourcegraphDotComMode() {
+		return http.StatusNotFound, errors.New("not enabled on sourcegraph.com")
+	}
+
+	if err := licensing.Check(licensing.FeatureExplicitPermissionsAPI); err != nil {
+		if licensing.IsFeatureNotActivated(err) {
+			return http.StatusForbidden, err
+		}
+		h.logger.Error("failed to check license feature", sglog.Error(err))
+		return http.StatusInternalServerError, errors.New("Unable to check license feature, please refer to logs for actual error message.")
+	}
+
+	if err := auth.CheckCurrentUserIsSiteAdmin(r.Context(), h.db); err != nil {
+		if err == auth.ErrNotAuthenticated {
+			return http.StatusUnauthorized, err
+		}
+		return http.StatusForbidden, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,193 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+)
+
+func TestBulkImportHandler_Upload(t *testing.T) {
+	defer licensing.TestingSkipFeatureChecks()()
+
+	newDB := func(siteAdmin bool) (*database.MockDB, *database.MockExplicitPermissionsBulkImportJobsStore) {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: siteAdmin}, nil)
+
+		jobs := database.NewMockExplicitPermissionsBulkImportJobsStore()
+		jobs.EnqueueFunc.SetDefaultReturn(42, nil)
+
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.ExplicitPermissionsBulkImportJobsFunc.SetDefaultReturn(jobs)
+		return db, jobs
+	}
+
+	// newUploadStore returns an upload store that records the documents uploaded
+	// by key.
+	newUploadStore := func() (*uploadstoremocks.MockStore, map[string]string) {
+		uploads := make(map[string]string)
+		uploadStore := uploadstoremocks.NewMockStore()
+		uploadStore.UploadFunc.SetDefaultHook(func(_ context.Context, key string, r io.Reader) (int64, error) {
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return 0, err
+			}
+			uploads[key] = string(b)
+			return int64(len(b)), nil
+		})
+		return uploadStore, uploads
+	}
+
+	newRequest := func(target, contentType, body string) *http.Request {
+		r := httptest.NewRequest("POST", target, strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		return r.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 1}))
+	}
+
+	t.Run("enqueues a job", func(t *testing.T) {
+		db, jobs := newDB(true)
+		uploadStore, uploads := newUploadStore()
+
+		w := httptest.NewRecorder()
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, newRequest("/explicit-permissions/import", "text/csv", "alice,github.com/foo,read\n"))
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		var resp uploadResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, 42, resp.ID)
+
+		mockrequire.CalledOnce(t, jobs.EnqueueFunc)
+		call := jobs.EnqueueFunc.History()[0]
+		assert.Equal(t, types.ExplicitPermissionsBulkImportFormatCSV, call.Arg1)
+		assert.Equal(t, types.ExplicitPermissionsBulkImportModeMerge, call.Arg2)
+		assert.True(t, strings.HasPrefix(call.Arg3, bulkImportKeyPrefix))
+		assert.Equal(t, "alice,github.com/foo,read\n", uploads[call.Arg3])
+		assert.Equal(t, int32(1), call.Arg4)
+	})
+
+	t.Run("format query parameter", func(t *testing.T) {
+		db, jobs := newDB(true)
+		uploadStore, _ := newUploadStore()
+
+		w := httptest.NewRecorder()
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, newRequest("/explicit-permissions/import?format=jsonl", "text/plain", "{}"))
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, types.ExplicitPermissionsBulkImportFormatJSONL, jobs.EnqueueFunc.History()[0].Arg1)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		db, _ := newDB(true)
+		uploadStore, _ := newUploadStore()
+
+		w := httptest.NewRecorder()
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, newRequest("/explicit-permissions/import", "text/plain", "{}"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, uploadStore.UploadFunc.History())
+	})
+
+	t.Run("mode query parameter", func(t *testing.T) {
+		db, jobs := newDB(true)
+		uploadStore, _ := newUploadStore()
+
+		w := httptest.NewRecorder()
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, newRequest("/explicit-permissions/import?mode=replace", "text/csv", "alice,github.com/foo,read\n"))
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, types.ExplicitPermissionsBulkImportModeReplace, jobs.EnqueueFunc.History()[0].Arg2)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		db, jobs := newDB(true)
+		uploadStore, _ := newUploadStore()
+
+		w := httptest.NewRecorder()
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, newRequest("/explicit-permissions/import?mode=append", "text/csv", "alice,github.com/foo,read\n"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, jobs.EnqueueFunc.History())
+	})
+
+	t.Run("payload too large", func(t *testing.T) {
+		db, jobs := newDB(true)
+		uploadStore, _ := newUploadStore()
+
+		w := httptest.NewRecorder()
+		r := newRequest("/explicit-permissions/import", "text/csv", strings.Repeat("a", maxBulkImportSize+1))
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Empty(t, jobs.EnqueueFunc.History())
+		mockrequire.CalledOnce(t, uploadStore.DeleteFunc)
+	})
+
+	t.Run("non-admin", func(t *testing.T) {
+		db, jobs := newDB(false)
+		uploadStore, _ := newUploadStore()
+
+		w := httptest.NewRecorder()
+		NewBulkImportHandler(db, uploadStore).Upload().ServeHTTP(w, newRequest("/explicit-permissions/import", "text/csv", "alice,github.com/foo,read\n"))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, jobs.EnqueueFunc.History())
+		assert.Empty(t, uploadStore.UploadFunc.History())
+	})
+}
+
+func TestBulkImportHandler_Get(t *testing.T) {
+	defer licensing.TestingSkipFeatureChecks()()
+
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+	jobs := database.NewMockExplicitPermissionsBulkImportJobsStore()
+	jobs.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int) (*types.ExplicitPermissionsBulkImportJob, error) {
+		if id != 42 {
+			return nil, &database.ExplicitPermissionsBulkImportJobNotFoundErr{ID: id}
+		}
+		return &types.ExplicitPermissionsBulkImportJob{ID: 42, State: "completed", ProcessedLines: 2, FailedLines: 1}, nil
+	})
+	jobs.ListErrorsFunc.SetDefaultReturn([]*types.ExplicitPermissionsBulkImportError{{Line: 2, Message: "user must not be empty"}}, nil)
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.ExplicitPermissionsBulkImportJobsFunc.SetDefaultReturn(jobs)
+
+	router := mux.NewRouter()
+	router.Path("/explicit-permissions/import/{id:[0-9]+}").Handler(NewBulkImportHandler(db, uploadstoremocks.NewMockStore()).Get())
+
+	get := func(target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		r = r.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 1}))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/explicit-permissions/import/42")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp getResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "completed", resp.State)
+	assert.Equal(t, 2, resp.ProcessedLines)
+	assert.Equal(t, 1, resp.FailedLines)
+	assert.Equal(t, []bulkImportLineError{{Line: 2, Message: "user must not be empty"}}, resp.Errors)
+
+	assert.Equal(t, http.StatusNotFound, get("/explicit-permissions/import/1").Code)
+}
@@ -8,13 +8,16 @@ import (
 	"time"
 
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/hooks"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/httpapi"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/resolvers"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing/enforcement"
 	eiauthz "github.com/sourcegraph/sourcegraph/enterprise/internal/authz"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/bulkimportstore"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel"
 	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
@@ -32,6 +35,12 @@ import (
 
 var clock = timeutil.Now
 
+var bulkImportStoreConfig = &bulkimportstore.Config{}
+
+func init() {
+	bulkImportStoreConfig.Load()
+}
+
 func Init(
 	ctx context.Context,
 	db database.DB,
@@ -197,5 +206,19 @@ func Init(
 	}()
 
 	enterpriseServices.AuthzResolver = resolvers.NewResolver(db, timeutil.Now)
+
+	// Bulk imports are stored in their own upload store until they are processed
+	// by the worker. An invalid store configuration only disables bulk imports.
+	if err := bulkImportStoreConfig.Validate(); err != nil {
+		observationContext.Logger.Error("explicit permissions bulk imports are disabled: invalid upload store configuration", log.Error(err))
+		return nil
+	}
+	uploadStore, err := bulkimportstore.New(context.Background(), bulkImportStoreConfig, observationContext)
+	if err != nil {
+		return err
+	}
+	bulkImportHandler := httpapi.NewBulkImportHandler(db, uploadStore)
+	enterpriseServices.ExplicitPermissionsBulkImportUploadHandler = bulkImportHandler.Upload()
+	enterpriseServices.ExplicitPermissionsBulkImportGetHandler = bulkImportHandler.Get()
 	return nil
 }
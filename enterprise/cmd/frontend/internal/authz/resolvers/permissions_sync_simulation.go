@@ -0,0 +1,287 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	permissionsSyncDecisionSourceSourcegraph = "SOURCEGRAPH"
+	permissionsSyncDecisionSourceUser        = "USER_PERMISSIONS"
+	permissionsSyncDecisionSourceRepository  = "REPOSITORY_PERMISSIONS"
+)
+
+func (r *Resolver) PermissionsSyncSimulation(ctx context.Context, args *graphqlbackend.PermissionsSyncSimulationArgs) (graphqlbackend.PermissionsSyncSimulationResolver, error) {
+	if envvar.SourcegraphDotComMode() {
+		return nil, errDisabledSourcegraphDotCom
+	}
+
+	// 🚨 SECURITY: Only site admins can simulate permissions syncs, since the decisions
+	// reveal memberships of users on the code host.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.db.Users().GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := r.db.Repos().Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, providers := authz.GetProviders()
+	return simulatePermissionsSync(ctx, r.db, providers, user, repo)
+}
+
+// simulatePermissionsSync fetches the permissions of the given user and repository
+// from the authz provider of the repository's code host, the same way the perms
+// syncer does, while recording the decisions made by the provider. Nothing is
+// persisted: accounts fetched from the code host are not saved, and providers
+// neither read from nor write to their caches, e.g. the GitHub groups cache.
+func simulatePermissionsSync(ctx context.Context, db database.DB, providers []authz.Provider, user *types.User, repo *types.Repo) (*permissionsSyncSimulationResolver, error) {
+	res := &permissionsSyncSimulationResolver{}
+
+	if !repo.Private {
+		res.record(permissionsSyncDecisionSourceSourcegraph, authz.PermsDecisionGrant, "the repository is public, all users can access it")
+	}
+	if user.SiteAdmin {
+		res.record(permissionsSyncDecisionSourceSourcegraph, authz.PermsDecisionInfo, "the user is a site admin, which only grants access if authz.enforceForSiteAdmins is disabled")
+	}
+
+	var provider authz.Provider
+	for _, p := range providers {
+		if p.ServiceID() == repo.ExternalRepo.ServiceID {
+			provider = p
+			break
+		}
+	}
+	if provider == nil {
+		res.record(permissionsSyncDecisionSourceSourcegraph, authz.PermsDecisionInfo, "no authorization provider is configured for the code host of the repository, permissions are not synced from it")
+		return res, nil
+	}
+	res.providerType = provider.ServiceType()
+	res.providerID = provider.ServiceID()
+
+	account, err := simulationAccount(ctx, db, provider, user)
+	if err != nil {
+		res.errors = append(res.errors, err.Error())
+	}
+	if account == nil {
+		res.record(permissionsSyncDecisionSourceSourcegraph, authz.PermsDecisionDeny, "the user has no account on the code host, so no permissions are synced for them")
+	} else {
+		res.accountID = account.AccountID
+	}
+
+	var accountID extsvc.AccountID
+	if account != nil {
+		accountID = extsvc.AccountID(account.AccountID)
+	}
+	newSimulation := func() *authz.PermsSimulation {
+		return &authz.PermsSimulation{
+			Repo:    extsvc.RepoID(repo.ExternalRepo.ID),
+			Account: accountID,
+		}
+	}
+
+	// User-centric sync
+	if account != nil {
+		sim := newSimulation()
+		perms, err := provider.FetchUserPerms(authz.WithPermsSimulation(ctx, sim), account, authz.FetchPermsOptions{})
+		if err != nil && !errors.Is(err, &authz.ErrUnimplemented{}) {
+			res.errors = append(res.errors, errors.Wrap(err, "fetch user permissions").Error())
+		}
+		if !errors.Is(err, &authz.ErrUnimplemented{}) && perms != nil {
+			granted, err := userPermissionsGrantAccess(ctx, db, perms, repo)
+			if err != nil {
+				return nil, err
+			}
+			res.userPermissionsGrantAccess = &granted
+		}
+		res.recordAll(permissionsSyncDecisionSourceUser, sim.Decisions())
+	}
+
+	// Repository-centric sync
+	sim := newSimulation()
+	accountIDs, err := provider.FetchRepoPerms(authz.WithPermsSimulation(ctx, sim), &extsvc.Repository{
+		URI:              repo.URI,
+		ExternalRepoSpec: repo.ExternalRepo,
+	}, authz.FetchPermsOptions{})
+	if err != nil && !errors.Is(err, &authz.ErrUnimplemented{}) {
+		res.errors = append(res.errors, errors.Wrap(err, "fetch repository permissions").Error())
+	}
+	if !errors.Is(err, &authz.ErrUnimplemented{}) {
+		granted := false
+		for _, id := range accountIDs {
+			if sim.IsAccount(id) {
+				granted = true
+				break
+			}
+		}
+		res.repositoryPermissionsGrantAccess = &granted
+	}
+	res.recordAll(permissionsSyncDecisionSourceRepository, sim.Decisions())
+
+	return res, nil
+}
+
+// simulationAccount returns the account of the user on the code host of the given
+// provider. If the user has no such account yet, the provider is asked to find one,
+// but it is not saved.
+func simulationAccount(ctx context.Context, db database.DB, provider authz.Provider, user *types.User) (*extsvc.Account, error) {
+	accts, err := db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{UserID: user.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, "list external accounts")
+	}
+	for _, acct := range accts {
+		if acct.ServiceType == provider.ServiceType() && acct.ServiceID == provider.ServiceID() {
+			return acct, nil
+		}
+	}
+
+	userEmails, err := db.UserEmails().ListByUser(ctx, database.UserEmailsListOptions{
+		UserID:       user.ID,
+		OnlyVerified: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list user verified emails")
+	}
+	emails := make([]string, len(userEmails))
+	for i := range userEmails {
+		emails[i] = userEmails[i].Email
+	}
+
+	acct, err := provider.FetchAccount(ctx, user, accts, emails)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch account")
+	}
+	return acct, nil
+}
+
+// userPermissionsGrantAccess evaluates the permissions of a user the same way the
+// perms syncer does, and returns true if they include the given repository.
+func userPermissionsGrantAccess(ctx context.Context, db database.DB, perms *authz.ExternalUserPermissions, repo *types.Repo) (bool, error) {
+	for _, id := range perms.Exacts {
+		if string(id) == repo.ExternalRepo.ID {
+			return true, nil
+		}
+	}
+	if len(perms.IncludeContains) == 0 {
+		return false, nil
+	}
+
+	toSpecs := func(ids []extsvc.RepoID) []api.ExternalRepoSpec {
+		specs := make([]api.ExternalRepoSpec, 0, len(ids))
+		for _, id := range ids {
+			specs = append(specs, api.ExternalRepoSpec{
+				ID:          string(id),
+				ServiceType: repo.ExternalRepo.ServiceType,
+				ServiceID:   repo.ExternalRepo.ServiceID,
+			})
+		}
+		return specs
+	}
+	rs, err := db.Repos().ListMinimalRepos(ctx, database.ReposListOptions{
+		IDs:                         []api.RepoID{repo.ID},
+		ExternalRepoIncludeContains: toSpecs(perms.IncludeContains),
+		ExternalRepoExcludeContains: toSpecs(perms.ExcludeContains),
+		OnlyPrivate:                 true,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "list external repositories by contains matching")
+	}
+	return len(rs) > 0, nil
+}
+
+type permissionsSyncSimulationResolver struct {
+	providerType                     string
+	providerID                       string
+	accountID                        string
+	userPermissionsGrantAccess       *bool
+	repositoryPermissionsGrantAccess *bool
+	decisions                        []permissionsSyncDecisionResolver
+	errors                           []string
+}
+
+var _ graphqlbackend.PermissionsSyncSimulationResolver = &permissionsSyncSimulationResolver{}
+
+func (r *permissionsSyncSimulationResolver) record(source string, effect authz.PermsDecisionEffect, reason string) {
+	r.decisions = append(r.decisions, permissionsSyncDecisionResolver{source: source, effect: string(effect), reason: reason})
+}
+
+func (r *permissionsSyncSimulationResolver) recordAll(source string, decisions []authz.PermsDecision) {
+	for _, d := range decisions {
+		r.record(source, d.Effect, d.Reason)
+	}
+}
+
+func (r *permissionsSyncSimulationResolver) ProviderType() *string {
+	return stringOrNil(r.providerType)
+}
+
+func (r *permissionsSyncSimulationResolver) ProviderID() *string {
+	return stringOrNil(r.providerID)
+}
+
+func (r *permissionsSyncSimulationResolver) AccountID() *string {
+	return stringOrNil(r.accountID)
+}
+
+func (r *permissionsSyncSimulationResolver) UserPermissionsGrantAccess() *bool {
+	return r.userPermissionsGrantAccess
+}
+
+func (r *permissionsSyncSimulationResolver) RepositoryPermissionsGrantAccess() *bool {
+	return r.repositoryPermissionsGrantAccess
+}
+
+func (r *permissionsSyncSimulationResolver) Decisions() []graphqlbackend.PermissionsSyncDecisionResolver {
+	decisions := make([]graphqlbackend.PermissionsSyncDecisionResolver, 0, len(r.decisions))
+	for _, d := range r.decisions {
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+func (r *permissionsSyncSimulationResolver) Errors() []string {
+	if r.errors == nil {
+		return []string{}
+	}
+	return r.errors
+}
+
+type permissionsSyncDecisionResolver struct {
+	source string
+	effect string
+	reason string
+}
+
+func (r permissionsSyncDecisionResolver) Source() string { return r.source }
+func (r permissionsSyncDecisionResolver) Effect() string { return r.effect }
+func (r permissionsSyncDecisionResolver) Reason() string { return r.reason }
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
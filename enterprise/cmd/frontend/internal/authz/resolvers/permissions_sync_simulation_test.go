@@ -0,0 +1,152 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+type simulationProvider struct {
+	fetchUserPerms func(context.Context, *extsvc.Account) (*authz.ExternalUserPermissions, error)
+	fetchRepoPerms func(context.Context, *extsvc.Repository) ([]extsvc.AccountID, error)
+}
+
+func (*simulationProvider) FetchAccount(context.Context, *types.User, []*extsvc.Account, []string) (*extsvc.Account, error) {
+	return nil, nil
+}
+
+func (*simulationProvider) ServiceType() string { return extsvc.TypeGitHub }
+func (*simulationProvider) ServiceID() string   { return "https://github.com/" }
+func (*simulationProvider) URN() string         { return extsvc.URN(extsvc.TypeGitHub, 1) }
+
+func (*simulationProvider) ValidateConnection(context.Context) []string { return nil }
+
+func (p *simulationProvider) FetchUserPerms(ctx context.Context, acct *extsvc.Account, _ authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	return p.fetchUserPerms(ctx, acct)
+}
+
+func (p *simulationProvider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, _ authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	return p.fetchRepoPerms(ctx, repo)
+}
+
+func TestResolver_PermissionsSyncSimulation(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		users := database.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		db := edb.NewStrictMockEnterpriseDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{db: db}).PermissionsSyncSimulation(ctx, &graphqlbackend.PermissionsSyncSimulationArgs{
+			User:       graphqlbackend.MarshalUserID(1),
+			Repository: graphqlbackend.MarshalRepositoryID(1),
+		})
+		if want := auth.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	repo := &types.Repo{
+		ID:      1,
+		Name:    "github.com/sourcegraph/private",
+		URI:     "github.com/sourcegraph/private",
+		Private: true,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "MDEwOlJlcG9zaXRvcnkyNTI0MjU2NzE=",
+			ServiceType: extsvc.TypeGitHub,
+			ServiceID:   "https://github.com/",
+		},
+	}
+	user := &types.User{ID: 2, Username: "alice"}
+
+	newDB := func() database.DB {
+		accounts := database.NewMockUserExternalAccountsStore()
+		accounts.ListFunc.SetDefaultReturn([]*extsvc.Account{{
+			UserID: user.ID,
+			AccountSpec: extsvc.AccountSpec{
+				ServiceType: extsvc.TypeGitHub,
+				ServiceID:   "https://github.com/",
+				AccountID:   "42",
+			},
+		}}, nil)
+
+		db := database.NewMockDB()
+		db.UserExternalAccountsFunc.SetDefaultReturn(accounts)
+		return db
+	}
+
+	t.Run("records decisions of the provider", func(t *testing.T) {
+		provider := &simulationProvider{
+			fetchUserPerms: func(ctx context.Context, acct *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+				sim := authz.PermsSimulationFromContext(ctx)
+				require.NotNil(t, sim)
+				assert.Equal(t, extsvc.RepoID(repo.ExternalRepo.ID), sim.Repo)
+				sim.Grant("the user is a member of team %q", "backend")
+				return &authz.ExternalUserPermissions{Exacts: []extsvc.RepoID{sim.Repo}}, nil
+			},
+			fetchRepoPerms: func(ctx context.Context, _ *extsvc.Repository) ([]extsvc.AccountID, error) {
+				sim := authz.PermsSimulationFromContext(ctx)
+				require.NotNil(t, sim)
+				assert.Equal(t, extsvc.AccountID("42"), sim.Account)
+				sim.Info("the repository has 1 collaborator")
+				return []extsvc.AccountID{"7"}, nil
+			},
+		}
+
+		res, err := simulatePermissionsSync(context.Background(), newDB(), []authz.Provider{provider}, user, repo)
+		require.NoError(t, err)
+
+		assert.Equal(t, "github", *res.ProviderType())
+		assert.Equal(t, "42", *res.AccountID())
+		assert.True(t, *res.UserPermissionsGrantAccess())
+		assert.False(t, *res.RepositoryPermissionsGrantAccess())
+		assert.Empty(t, res.Errors())
+		assert.Equal(t, []permissionsSyncDecisionResolver{
+			{source: "USER_PERMISSIONS", effect: "GRANT", reason: `the user is a member of team "backend"`},
+			{source: "REPOSITORY_PERMISSIONS", effect: "INFO", reason: "the repository has 1 collaborator"},
+		}, res.decisions)
+	})
+
+	t.Run("unimplemented repository permissions", func(t *testing.T) {
+		provider := &simulationProvider{
+			fetchUserPerms: func(context.Context, *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+				return &authz.ExternalUserPermissions{}, nil
+			},
+			fetchRepoPerms: func(context.Context, *extsvc.Repository) ([]extsvc.AccountID, error) {
+				return nil, &authz.ErrUnimplemented{Feature: "FetchRepoPerms"}
+			},
+		}
+
+		res, err := simulatePermissionsSync(context.Background(), newDB(), []authz.Provider{provider}, user, repo)
+		require.NoError(t, err)
+
+		assert.False(t, *res.UserPermissionsGrantAccess())
+		assert.Nil(t, res.RepositoryPermissionsGrantAccess())
+		assert.Empty(t, res.Errors())
+	})
+
+	t.Run("no provider for the code host", func(t *testing.T) {
+		res, err := simulatePermissionsSync(context.Background(), newDB(), nil, user, repo)
+		require.NoError(t, err)
+
+		assert.Nil(t, res.ProviderType())
+		assert.Nil(t, res.UserPermissionsGrantAccess())
+		assert.Len(t, res.Decisions(), 1)
+	})
+}
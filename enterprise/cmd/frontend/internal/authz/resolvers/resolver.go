@@ -283,19 +283,7 @@ func (r *Resolver) SetSubRepositoryPermissionsForUsers(ctx context.Context, args
 				paths = append(paths, "-"+exclude) // excludes start with a minus (-)
 			}
 		} else {
-			paths = make([]string, 0, len(*perm.Paths))
-			for _, path := range *perm.Paths {
-				if strings.HasPrefix(path, "-") {
-					if !strings.HasPrefix(path, "-/") {
-						path = "-/" + strings.TrimPrefix(path, "-")
-					}
-				} else {
-					if !strings.HasPrefix(path, "/") {
-						path = "/" + path
-					}
-				}
-				paths = append(paths, path)
-			}
+			paths = normalizeSubRepoPaths(*perm.Paths)
 		}
 
 		if err := db.SubRepoPerms().Upsert(ctx, userID, repoID, authz.SubRepoPermissions{
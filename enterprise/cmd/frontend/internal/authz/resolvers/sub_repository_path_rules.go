@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (r *Resolver) SetSubRepositoryPathRules(ctx context.Context, args *graphqlbackend.SubRepoPathRulesArgs) (*graphqlbackend.EmptyResponse, error) {
+	if err := r.checkLicense(licensing.FeatureExplicitPermissionsAPI); err != nil {
+		return nil, err
+	}
+	if envvar.SourcegraphDotComMode() {
+		return nil, errDisabledSourcegraphDotCom
+	}
+
+	// 🚨 SECURITY: Only site admins can mutate repository permissions.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &types.SubRepoPathRule{
+		RepoID: repoID,
+		Paths:  normalizeSubRepoPaths(args.Paths),
+	}
+	switch kind := relay.UnmarshalKind(args.Subject); kind {
+	case "User":
+		rule.UserID, err = graphqlbackend.UnmarshalUserID(args.Subject)
+	case "Org":
+		rule.OrgID, err = graphqlbackend.UnmarshalOrgID(args.Subject)
+	case "Role":
+		rule.RoleID, err = graphqlbackend.UnmarshalRoleID(args.Subject)
+	default:
+		err = errors.Errorf("subject must be a user, an organization or a role, got %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := r.db.Transact(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "start transaction")
+	}
+	defer func() { err = db.Done(err) }()
+
+	// Make sure the repo and subject IDs are valid.
+	if _, err = db.Repos().Get(ctx, repoID); err != nil {
+		return nil, err
+	}
+	switch {
+	case rule.UserID != 0:
+		_, err = db.Users().GetByID(ctx, rule.UserID)
+	case rule.OrgID != 0:
+		_, err = db.Orgs().GetByID(ctx, rule.OrgID)
+	case rule.RoleID != 0:
+		_, err = db.Roles().GetByID(ctx, rule.RoleID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rule.Paths) == 0 {
+		var rules []*types.SubRepoPathRule
+		rules, err = db.SubRepoPerms().ListPathRules(ctx, repoID)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range rules {
+			if existing.UserID == rule.UserID && existing.OrgID == rule.OrgID && existing.RoleID == rule.RoleID {
+				if err = db.SubRepoPerms().DeletePathRule(ctx, existing.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return &graphqlbackend.EmptyResponse{}, nil
+	}
+
+	if err = db.SubRepoPerms().UpsertPathRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) RepositorySubRepositoryPathRules(ctx context.Context, id graphql.ID) ([]*graphqlbackend.SubRepositoryPathRuleResolver, error) {
+	// 🚨 SECURITY: Only site admins can query repository permissions.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.db.SubRepoPerms().ListPathRules(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*graphqlbackend.SubRepositoryPathRuleResolver, 0, len(rules))
+	for _, rule := range rules {
+		resolvers = append(resolvers, graphqlbackend.NewSubRepositoryPathRuleResolver(r.db, rule))
+	}
+	return resolvers, nil
+}
+
+// normalizeSubRepoPaths ensures that all paths start with a slash, after the minus
+// sign (-) for exclusion paths.
+func normalizeSubRepoPaths(paths []string) []string {
+	normalized := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if strings.HasPrefix(path, "-") {
+			if !strings.HasPrefix(path, "-/") {
+				path = "-/" + strings.TrimPrefix(path, "-")
+			}
+		} else {
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+		}
+		normalized = append(normalized, path)
+	}
+	return normalized
+}
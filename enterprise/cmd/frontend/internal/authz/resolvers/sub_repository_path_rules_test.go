@@ -0,0 +1,113 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestResolver_SetSubRepositoryPathRules(t *testing.T) {
+	licensing.MockCheckFeatureError("")
+	t.Cleanup(func() { licensing.MockCheckFeature = nil })
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		users := database.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		db := edb.NewStrictMockEnterpriseDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{db: db}).SetSubRepositoryPathRules(ctx, &graphqlbackend.SubRepoPathRulesArgs{})
+		if want := auth.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	newDB := func(subRepoPerms database.SubRepoPermsStore) *edb.MockEnterpriseDB {
+		users := database.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+		repos := database.NewStrictMockRepoStore()
+		repos.GetFunc.SetDefaultHook(func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+			return &types.Repo{ID: id, Name: "github.com/sourcegraph/private"}, nil
+		})
+
+		roles := database.NewStrictMockRoleStore()
+		roles.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int32) (*types.Role, error) {
+			return &types.Role{ID: id, Name: "contractor"}, nil
+		})
+
+		db := edb.NewStrictMockEnterpriseDB()
+		db.TransactFunc.SetDefaultReturn(db, nil)
+		db.DoneFunc.SetDefaultReturn(nil)
+		db.UsersFunc.SetDefaultReturn(users)
+		db.ReposFunc.SetDefaultReturn(repos)
+		db.RolesFunc.SetDefaultReturn(roles)
+		db.SubRepoPermsFunc.SetDefaultReturn(subRepoPerms)
+		return db
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("set path rules of a role", func(t *testing.T) {
+		subRepoPerms := database.NewStrictMockSubRepoPermsStore()
+		subRepoPerms.UpsertPathRuleFunc.SetDefaultReturn(nil)
+
+		_, err := (&Resolver{db: newDB(subRepoPerms)}).SetSubRepositoryPathRules(ctx, &graphqlbackend.SubRepoPathRulesArgs{
+			Repository: graphqlbackend.MarshalRepositoryID(1),
+			Subject:    graphqlbackend.MarshalRoleID(7),
+			Paths:      []string{"-secrets/**", "docs/**"},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, subRepoPerms.UpsertPathRuleFunc.History(), 1)
+		assert.Equal(t, &types.SubRepoPathRule{
+			RepoID: 1,
+			RoleID: 7,
+			Paths:  []string{"-/secrets/**", "/docs/**"},
+		}, subRepoPerms.UpsertPathRuleFunc.History()[0].Arg1)
+	})
+
+	t.Run("empty paths remove the path rules of the subject", func(t *testing.T) {
+		subRepoPerms := database.NewStrictMockSubRepoPermsStore()
+		subRepoPerms.ListPathRulesFunc.SetDefaultReturn([]*types.SubRepoPathRule{
+			{ID: 1, RepoID: 1, UserID: 7},
+			{ID: 2, RepoID: 1, RoleID: 7},
+		}, nil)
+		subRepoPerms.DeletePathRuleFunc.SetDefaultReturn(nil)
+
+		_, err := (&Resolver{db: newDB(subRepoPerms)}).SetSubRepositoryPathRules(ctx, &graphqlbackend.SubRepoPathRulesArgs{
+			Repository: graphqlbackend.MarshalRepositoryID(1),
+			Subject:    graphqlbackend.MarshalRoleID(7),
+			Paths:      []string{},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, subRepoPerms.DeletePathRuleFunc.History(), 1)
+		assert.Equal(t, int32(2), subRepoPerms.DeletePathRuleFunc.History()[0].Arg1)
+	})
+
+	t.Run("unsupported subject", func(t *testing.T) {
+		_, err := (&Resolver{db: newDB(database.NewStrictMockSubRepoPermsStore())}).SetSubRepositoryPathRules(ctx, &graphqlbackend.SubRepoPathRulesArgs{
+			Repository: graphqlbackend.MarshalRepositoryID(1),
+			Subject:    graphqlbackend.MarshalRepositoryID(2),
+			Paths:      []string{"/**"},
+		})
+		assert.EqualError(t, err, `subject must be a user, an organization or a role, got "Repository"`)
+	})
+}
@@ -345,7 +345,7 @@ func (r *Resolver) batchChangesUserCredentialByID(ctx context.Context, id int64)
 
 func (r *Resolver) batchChangesSiteCredentialByID(ctx context.Context, id int64) (batchChangesCredentialResolver, error) {
 	// Todo: Is this required? Should everyone be able to see there are _some_ credentials?
-	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.store.DatabaseDB()); err != nil {
+	if err := auth.CheckCurrentUserPermission(ctx, r.store.DatabaseDB(), auth.PermissionBatchChangesAdmin); err != nil {
 		return nil, err
 	}
 
@@ -1129,8 +1129,8 @@ func (r *Resolver) createBatchChangesUserCredential(ctx context.Context, externa
 
 func (r *Resolver) createBatchChangesSiteCredential(ctx context.Context, externalServiceURL, externalServiceType string, credential string, username *string) (graphqlbackend.BatchChangesCredentialResolver, error) {
 	// 🚨 SECURITY: Check that a site credential can only be created
-	// by a site-admin.
-	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.store.DatabaseDB()); err != nil {
+	// by a site-admin or a batch changes admin.
+	if err := auth.CheckCurrentUserPermission(ctx, r.store.DatabaseDB(), auth.PermissionBatchChangesAdmin); err != nil {
 		return nil, err
 	}
 
@@ -1255,7 +1255,7 @@ func (r *Resolver) deleteBatchChangesUserCredential(ctx context.Context, credent
 
 func (r *Resolver) deleteBatchChangesSiteCredential(ctx context.Context, credentialDBID int64) (*graphqlbackend.EmptyResponse, error) {
 	// 🚨 SECURITY: Check that the requesting user may delete the credential.
-	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.store.DatabaseDB()); err != nil {
+	if err := auth.CheckCurrentUserPermission(ctx, r.store.DatabaseDB(), auth.PermissionBatchChangesAdmin); err != nil {
 		return nil, err
 	}
 
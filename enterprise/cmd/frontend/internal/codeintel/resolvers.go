@@ -64,6 +64,14 @@ func (r *Resolver) DeleteLSIFUploads(ctx context.Context, args *resolverstubs.De
 	return r.uploadsRootResolver.DeleteLSIFUploads(ctx, args)
 }
 
+func (r *Resolver) GitTreeCodeIntelCoverage(ctx context.Context, args *resolverstubs.GitTreeCodeIntelCoverageArgs) (_ resolverstubs.CodeIntelDirectoryCoverageResolver, err error) {
+	return r.uploadsRootResolver.GitTreeCodeIntelCoverage(ctx, args)
+}
+
+func (r *Resolver) CodeIntelCoverageSummary(ctx context.Context, args *resolverstubs.CodeIntelCoverageSummaryArgs) (_ resolverstubs.CodeIntelRepositoryCoverageConnectionResolver, err error) {
+	return r.uploadsRootResolver.CodeIntelCoverageSummary(ctx, args)
+}
+
 func (r *Resolver) LSIFIndexByID(ctx context.Context, id graphql.ID) (_ resolverstubs.LSIFIndexResolver, err error) {
 	return r.autoIndexingRootResolver.LSIFIndexByID(ctx, id)
 }
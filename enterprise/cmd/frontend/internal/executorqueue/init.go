@@ -25,6 +25,7 @@ func Init(
 	observationContext *observation.Context,
 ) error {
 	codeintelUploadHandler := enterpriseServices.NewCodeIntelUploadHandler(false)
+	codeintelCacheHandler := enterpriseServices.CodeIntelCacheHandler
 	batchesWorkspaceFileGetHandler := enterpriseServices.BatchesChangesFileGetHandler
 	batchesWorkspaceFileExistsHandler := enterpriseServices.BatchesChangesFileGetHandler
 	accessToken := func() string { return conf.SiteConfig().ExecutorsAccessToken }
@@ -48,6 +49,7 @@ func Init(
 		queueOptions,
 		accessToken,
 		codeintelUploadHandler,
+		codeintelCacheHandler,
 		batchesWorkspaceFileGetHandler,
 		batchesWorkspaceFileExistsHandler,
 	)
@@ -2,6 +2,7 @@ package codeintel
 
 import (
 	"fmt"
+	"path"
 	"strconv"
 	"strings"
 
@@ -18,6 +19,9 @@ const defaultOutfile = "dump.lsif"
 const uploadRoute = "/.executors/lsif/upload"
 const schemeExecutorToken = "token-executor"
 
+// CacheBucket is the executor files store bucket holding auto-indexing dependency caches.
+const CacheBucket = "codeintel-caches"
+
 func transformRecord(index types.Index, resourceMetadata handler.ResourceMetadata, accessToken string) (apiclient.Job, error) {
 	resourceEnvironment := makeResourceEnvironment(resourceMetadata)
 
@@ -87,6 +91,23 @@ func transformRecord(index types.Index, resourceMetadata handler.ResourceMetadat
 		},
 	})
 
+	var caches []apiclient.Cache
+	for _, cache := range index.Caches {
+		keyFiles := make([]string, 0, len(cache.KeyFiles))
+		for _, keyFile := range cache.KeyFiles {
+			keyFiles = append(keyFiles, path.Join(index.Root, keyFile))
+		}
+
+		caches = append(caches, apiclient.Cache{
+			Bucket: CacheBucket,
+			// Scope caches to the repository so that jobs never observe the
+			// dependencies fetched on behalf of another repository.
+			Key:      fmt.Sprintf("%d/%s", index.RepositoryID, cache.Key),
+			KeyFiles: keyFiles,
+			Paths:    cache.Paths,
+		})
+	}
+
 	return apiclient.Job{
 		ID:             index.ID,
 		Commit:         index.Commit,
@@ -94,6 +115,7 @@ func transformRecord(index types.Index, resourceMetadata handler.ResourceMetadat
 		ShallowClone:   true,
 		FetchTags:      fetchTags,
 		DockerSteps:    dockerSteps,
+		Caches:         caches,
 		RedactedValues: map[string]string{
 			// 🚨 SECURITY: Catch leak of authorization header.
 			authorizationHeader: redactedAuthorizationHeader,
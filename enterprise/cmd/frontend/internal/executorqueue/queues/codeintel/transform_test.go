@@ -52,6 +52,7 @@ func TestTransformRecord(t *testing.T) {
 			index := types.Index{
 				ID:             42,
 				Commit:         "deadbeef",
+				RepositoryID:   50,
 				RepositoryName: "linux",
 				DockerSteps: []types.DockerStep{
 					{
@@ -60,6 +61,13 @@ func TestTransformRecord(t *testing.T) {
 						Root:     "web",
 					},
 				},
+				Caches: []types.IndexCache{
+					{
+						Key:      "yarn",
+						KeyFiles: []string{"yarn.lock"},
+						Paths:    []string{"/usr/local/share/.cache/yarn"},
+					},
+				},
 				Root:    "web",
 				Indexer: "lsif-node",
 				IndexerArgs: []string{
@@ -129,6 +137,14 @@ func TestTransformRecord(t *testing.T) {
 						},
 					},
 				},
+				Caches: []apiclient.Cache{
+					{
+						Bucket:   "codeintel-caches",
+						Key:      "50/yarn",
+						KeyFiles: []string{"web/yarn.lock"},
+						Paths:    []string{"/usr/local/share/.cache/yarn"},
+					},
+				},
 				RedactedValues: map[string]string{
 					"hunter2":                "PASSWORD_REMOVED",
 					"token-executor hunter2": "token-executor REDACTED",
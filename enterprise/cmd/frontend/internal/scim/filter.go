@@ -0,0 +1,51 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// filter is a parsed SCIM filter expression (RFC 7644, section 3.4.2.2).
+// Identity providers only use filters to look up a resource by one of its
+// unique attributes before creating it, so only the "eq" operator on a single
+// attribute is supported.
+type filter struct {
+	// attribute is the lowercased name of the compared attribute, e.g.
+	// "username".
+	attribute string
+	value     string
+}
+
+func parseFilter(s string) (*filter, error) {
+	attribute, rest, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return nil, errInvalidFilter("expected an expression of the form <attribute> eq <value>")
+	}
+	op, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(op, "eq") {
+		return nil, errInvalidFilter("only the eq operator is supported")
+	}
+
+	value = strings.TrimSpace(value)
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return nil, errInvalidFilter("the compared value must be a string")
+	}
+
+	return &filter{
+		attribute: strings.ToLower(attribute),
+		value:     unquoted,
+	}, nil
+}
+
+// matches reports whether the given value of the attribute of a resource
+// matches the filter. String comparisons are case-insensitive, like the
+// attributes supported by the filter.
+func (f *filter) matches(value string) bool {
+	return strings.EqualFold(f.value, value)
+}
+
+func errInvalidFilter(detail string) error {
+	return &scimError{status: http.StatusBadRequest, scimType: "invalidFilter", detail: "invalid filter: " + detail}
+}
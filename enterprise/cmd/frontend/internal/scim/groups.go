@@ -0,0 +1,327 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// groupResource is the SCIM representation of a group (RFC 7643, section 4.2),
+// which maps to an organization. The name of the organization is derived from
+// the display name of the group when it is created, and never changes.
+type groupResource struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []member `json:"members,omitempty"`
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+// member is a member of a group, or a group of a user.
+type member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+func validateGroup(g *groupResource) error {
+	if strings.TrimSpace(g.DisplayName) == "" {
+		return errInvalidValue("displayName is required")
+	}
+	return nil
+}
+
+func (h *Handler) listGroups(r *http.Request) (any, int, error) {
+	ctx := r.Context()
+	opts, err := parseListOptions(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if opts.filter != nil {
+		if opts.filter.attribute != "displayname" {
+			return nil, 0, errInvalidFilter("filtering groups by " + strconv.Quote(opts.filter.attribute) + " is not supported")
+		}
+		orgs, err := h.db.Orgs().List(ctx, &database.OrgsListOptions{Query: opts.filter.value})
+		if err != nil {
+			return nil, 0, err
+		}
+		var matching []any
+		for _, org := range orgs {
+			if !opts.filter.matches(orgDisplayName(org)) {
+				continue
+			}
+			g, err := h.toGroupResource(ctx, org)
+			if err != nil {
+				return nil, 0, err
+			}
+			matching = append(matching, g)
+		}
+		return newListResponse(opts.page(matching), len(matching), opts.startIndex), http.StatusOK, nil
+	}
+
+	total, err := h.db.Orgs().Count(ctx, database.OrgsListOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	orgs, err := h.db.Orgs().List(ctx, &database.OrgsListOptions{LimitOffset: opts.limitOffset()})
+	if err != nil {
+		return nil, 0, err
+	}
+	page := make([]any, 0, len(orgs))
+	for _, org := range orgs {
+		g, err := h.toGroupResource(ctx, org)
+		if err != nil {
+			return nil, 0, err
+		}
+		page = append(page, g)
+	}
+	return newListResponse(page, total, opts.startIndex), http.StatusOK, nil
+}
+
+func (h *Handler) getGroup(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	org, err := h.getOrgByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	g, err := h.toGroupResource(ctx, org)
+	if err != nil {
+		return nil, 0, err
+	}
+	return g, http.StatusOK, nil
+}
+
+func (h *Handler) createGroup(r *http.Request) (any, int, error) {
+	ctx := r.Context()
+	var g groupResource
+	if err := decodeBody(r, &g); err != nil {
+		return nil, 0, err
+	}
+	if err := validateGroup(&g); err != nil {
+		return nil, 0, err
+	}
+
+	orgName, err := auth.NormalizeUsername(g.DisplayName)
+	if err != nil {
+		return nil, 0, errInvalidValue(err.Error())
+	}
+	if _, err := h.db.Orgs().GetByName(ctx, orgName); err == nil {
+		return nil, 0, errUniqueness("organization " + strconv.Quote(orgName) + " already exists")
+	} else if !errcode.IsNotFound(err) {
+		return nil, 0, err
+	}
+	if _, err := h.db.Users().GetByUsername(ctx, orgName); err == nil {
+		return nil, 0, errUniqueness("the name " + strconv.Quote(orgName) + " is already in use by a user")
+	} else if !errcode.IsNotFound(err) {
+		return nil, 0, err
+	}
+
+	org, err := h.db.Orgs().Create(ctx, orgName, &g.DisplayName)
+	if err != nil {
+		return nil, 0, err
+	}
+	added, removed, err := h.syncMembers(ctx, org.ID, g.Members)
+	if err != nil {
+		return nil, 0, err
+	}
+	h.logEvent(ctx, r, database.SecurityEventSCIMGroupCreated, actor.FromContext(ctx).UID, map[string]any{
+		"org":     org.ID,
+		"added":   added,
+		"removed": removed,
+	})
+
+	resource, err := h.toGroupResource(ctx, org)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resource, http.StatusCreated, nil
+}
+
+func (h *Handler) replaceGroup(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	org, err := h.getOrgByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	var g groupResource
+	if err := decodeBody(r, &g); err != nil {
+		return nil, 0, err
+	}
+	return h.applyGroup(r, org, &g)
+}
+
+func (h *Handler) patchGroup(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	org, err := h.getOrgByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	var req patchRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, 0, err
+	}
+
+	current, err := h.toGroupResource(ctx, org)
+	if err != nil {
+		return nil, 0, err
+	}
+	var g groupResource
+	if err := applyPatch(current, &req, &g); err != nil {
+		return nil, 0, err
+	}
+	return h.applyGroup(r, org, &g)
+}
+
+// applyGroup updates the display name and members of the organization to
+// match g.
+func (h *Handler) applyGroup(r *http.Request, org *types.Org, g *groupResource) (any, int, error) {
+	ctx := r.Context()
+	if err := validateGroup(g); err != nil {
+		return nil, 0, err
+	}
+
+	if g.DisplayName != orgDisplayName(org) {
+		var err error
+		if org, err = h.db.Orgs().Update(ctx, org.ID, &g.DisplayName); err != nil {
+			return nil, 0, err
+		}
+	}
+	added, removed, err := h.syncMembers(ctx, org.ID, g.Members)
+	if err != nil {
+		return nil, 0, err
+	}
+	h.logEvent(ctx, r, database.SecurityEventSCIMGroupUpdated, actor.FromContext(ctx).UID, map[string]any{
+		"org":         org.ID,
+		"displayName": g.DisplayName,
+		"added":       added,
+		"removed":     removed,
+	})
+
+	resource, err := h.toGroupResource(ctx, org)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resource, http.StatusOK, nil
+}
+
+// syncMembers adds and removes members of the organization so that its members
+// are exactly the given members, and returns the IDs of the users that were
+// added and removed.
+func (h *Handler) syncMembers(ctx context.Context, orgID int32, members []member) (added, removed []int32, err error) {
+	wanted := make(map[int32]struct{}, len(members))
+	for _, m := range members {
+		userID, err := strconv.ParseInt(m.Value, 10, 32)
+		if err != nil {
+			return nil, nil, errInvalidValue("member " + strconv.Quote(m.Value) + " is not a user")
+		}
+		wanted[int32(userID)] = struct{}{}
+	}
+
+	if len(wanted) > 0 {
+		userIDs := make([]int32, 0, len(wanted))
+		for userID := range wanted {
+			userIDs = append(userIDs, userID)
+		}
+		users, err := h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: userIDs, IncludeDeleted: true})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(users) != len(userIDs) {
+			return nil, nil, errInvalidValue("some members are not existing users")
+		}
+	}
+
+	memberships, err := h.db.OrgMembers().GetByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	existing := make(map[int32]struct{}, len(memberships))
+	for _, m := range memberships {
+		existing[m.UserID] = struct{}{}
+		if _, ok := wanted[m.UserID]; ok {
+			continue
+		}
+		if err := h.db.OrgMembers().Remove(ctx, orgID, m.UserID); err != nil {
+			return nil, nil, err
+		}
+		removed = append(removed, m.UserID)
+	}
+	for userID := range wanted {
+		if _, ok := existing[userID]; ok {
+			continue
+		}
+		if _, err := h.db.OrgMembers().Create(ctx, orgID, userID); err != nil {
+			return nil, nil, err
+		}
+		added = append(added, userID)
+	}
+	return added, removed, nil
+}
+
+func (h *Handler) deleteGroup(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	if _, err := h.getOrgByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	if err := h.db.Orgs().Delete(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	h.logEvent(ctx, r, database.SecurityEventSCIMGroupDeleted, actor.FromContext(ctx).UID, map[string]any{"org": id})
+	return nil, http.StatusNoContent, nil
+}
+
+func (h *Handler) getOrgByID(ctx context.Context, id int32) (*types.Org, error) {
+	org, err := h.db.Orgs().GetByID(ctx, id)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, errNotFound("group " + strconv.Itoa(int(id)) + " does not exist")
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+func (h *Handler) toGroupResource(ctx context.Context, org *types.Org) (*groupResource, error) {
+	g := &groupResource{
+		Schemas:     []string{schemaGroup},
+		ID:          strconv.Itoa(int(org.ID)),
+		DisplayName: orgDisplayName(org),
+		Meta:        newMeta("Group", org.ID, org.CreatedAt, org.UpdatedAt),
+	}
+
+	memberships, err := h.db.OrgMembers().GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) == 0 {
+		return g, nil
+	}
+	userIDs := make([]int32, len(memberships))
+	for i, m := range memberships {
+		userIDs[i] = m.UserID
+	}
+	users, err := h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: userIDs, IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		g.Members = append(g.Members, member{Value: strconv.Itoa(int(u.ID)), Display: u.Username})
+	}
+	return g, nil
+}
+
+// orgDisplayName returns the display name of the organization, or its name if
+// it has none.
+func orgDisplayName(org *types.Org) string {
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		return *org.DisplayName
+	}
+	return org.Name
+}
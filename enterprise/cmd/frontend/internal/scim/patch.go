@@ -0,0 +1,305 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// patchRequest is the body of a PATCH request (RFC 7644, section 3.5.2).
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// applyPatch applies the operations of the PATCH request to the resource by
+// modifying its JSON representation, and decodes the result into patched.
+//
+// Supported paths are a top-level attribute ("active"), a sub-attribute
+// ("name.formatted") and a sub-attribute of the elements of a multi-valued
+// attribute matching a filter ('emails[type eq "work"].value'). Operations
+// without a path merge their value, which must be an object, into the resource.
+func applyPatch(resource any, req *patchRequest, patched any) error {
+	if len(req.Operations) == 0 {
+		return errInvalidValue("no operations in patch request")
+	}
+
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	for _, op := range req.Operations {
+		if err := applyOperation(doc, op); err != nil {
+			return err
+		}
+	}
+
+	if b, err = json.Marshal(doc); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, patched); err != nil {
+		return errInvalidValue(err.Error())
+	}
+	return nil
+}
+
+func applyOperation(doc map[string]any, op patchOperation) error {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case "add", "replace", "remove":
+	default:
+		return &scimError{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: "unsupported patch operation " + op.Op}
+	}
+
+	if op.Path == "" {
+		if kind == "remove" {
+			return &scimError{status: http.StatusBadRequest, scimType: "noTarget", detail: "remove operations require a path"}
+		}
+		values, ok := op.Value.(map[string]any)
+		if !ok {
+			return errInvalidValue("operations without a path require an object value")
+		}
+		for path, value := range values {
+			if err := applyOperation(doc, patchOperation{Op: kind, Path: path, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	p, err := parsePath(op.Path)
+	if err != nil {
+		return err
+	}
+	attribute := lookupKey(doc, p.attribute)
+
+	if p.filter == nil {
+		if p.subAttribute == "" {
+			doc[attribute] = patchValue(kind, doc[attribute], op.Value)
+			if doc[attribute] == nil {
+				delete(doc, attribute)
+			}
+			return nil
+		}
+
+		complex, _ := doc[attribute].(map[string]any)
+		if complex == nil {
+			complex = map[string]any{}
+		}
+		sub := lookupKey(complex, p.subAttribute)
+		if complex[sub] = patchValue(kind, complex[sub], op.Value); complex[sub] == nil {
+			delete(complex, sub)
+		}
+		doc[attribute] = complex
+		return nil
+	}
+
+	elements, _ := doc[attribute].([]any)
+	var result []any
+	matched := false
+	for _, e := range elements {
+		element, ok := e.(map[string]any)
+		if !ok || !p.filter.matchesElement(element) {
+			result = append(result, e)
+			continue
+		}
+		matched = true
+
+		switch {
+		case kind == "remove" && p.subAttribute == "":
+			// Drop the element.
+		case p.subAttribute == "":
+			value, ok := op.Value.(map[string]any)
+			if !ok {
+				return errInvalidValue("the value of " + op.Path + " must be an object")
+			}
+			result = append(result, value)
+		default:
+			sub := lookupKey(element, p.subAttribute)
+			if element[sub] = patchValue(kind, element[sub], op.Value); element[sub] == nil {
+				delete(element, sub)
+			}
+			result = append(result, element)
+		}
+	}
+
+	if !matched {
+		switch {
+		case kind == "remove":
+			return nil
+		case p.subAttribute == "":
+			return &scimError{status: http.StatusBadRequest, scimType: "noTarget", detail: "no value matches " + op.Path}
+		}
+		// Add the element matched by the filter, as Azure AD does when it sets
+		// the first email address of a user.
+		result = append(result, map[string]any{p.filter.attribute: p.filter.value, p.subAttribute: op.Value})
+	}
+
+	doc[attribute] = result
+	return nil
+}
+
+// patchValue returns the new value of an attribute whose current value is
+// current after the operation.
+func patchValue(kind string, current, value any) any {
+	switch kind {
+	case "remove":
+		// Remove the given elements of multi-valued attributes, as Azure AD does
+		// to remove members of a group, or the whole attribute otherwise.
+		elements, ok := current.([]any)
+		removed, ok2 := value.([]any)
+		if !ok || !ok2 {
+			return nil
+		}
+		var result []any
+		for _, e := range elements {
+			if !containsElement(removed, e) {
+				result = append(result, e)
+			}
+		}
+		return result
+
+	case "add":
+		// Add to multi-valued attributes, and replace any other attribute.
+		elements, ok := current.([]any)
+		added, ok2 := value.([]any)
+		if !ok || !ok2 {
+			return value
+		}
+		for _, e := range added {
+			if !containsElement(elements, e) {
+				elements = append(elements, e)
+			}
+		}
+		return elements
+	}
+	return value
+}
+
+// containsElement reports whether elements contains e. Complex elements are
+// compared by their "value" sub-attribute.
+func containsElement(elements []any, e any) bool {
+	for _, other := range elements {
+		if elementValue(other) == elementValue(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func elementValue(e any) any {
+	if m, ok := e.(map[string]any); ok {
+		return m[lookupKey(m, "value")]
+	}
+	return e
+}
+
+// lookupKey returns the key of m matching the attribute name, which is
+// case-insensitive, or name if there is none.
+func lookupKey(m map[string]any, name string) string {
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
+
+// path is a parsed attribute path of a PATCH operation.
+type path struct {
+	attribute    string
+	filter       *filter
+	subAttribute string
+}
+
+func parsePath(s string) (*path, error) {
+	var p path
+
+	if i := strings.Index(s, "["); i != -1 {
+		j := strings.LastIndex(s, "]")
+		if j < i {
+			return nil, &scimError{status: http.StatusBadRequest, scimType: "invalidPath", detail: "invalid path " + s}
+		}
+		f, err := parseFilter(s[i+1 : j])
+		if err != nil {
+			return nil, err
+		}
+		p.attribute, p.filter = s[:i], f
+		s = strings.TrimPrefix(s[j+1:], ".")
+		p.subAttribute = s
+	} else {
+		// Strip the schema URN of fully qualified paths.
+		if i := strings.LastIndex(s, ":"); i != -1 {
+			s = s[i+1:]
+		}
+		p.attribute, p.subAttribute, _ = strings.Cut(s, ".")
+	}
+
+	if p.attribute == "" || strings.ContainsAny(p.subAttribute, ".[]") {
+		return nil, &scimError{status: http.StatusBadRequest, scimType: "invalidPath", detail: "invalid path " + s}
+	}
+	return &p, nil
+}
+
+// matchesElement reports whether the element of a multi-valued attribute
+// matches the filter.
+func (f *filter) matchesElement(element map[string]any) bool {
+	for k, v := range element {
+		if strings.EqualFold(k, f.attribute) {
+			switch v := v.(type) {
+			case string:
+				return f.matches(v)
+			case bool:
+				return f.matches(boolString(v))
+			}
+		}
+	}
+	return false
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// scimBool is a boolean attribute. Some identity providers, such as Azure AD,
+// send booleans as the strings "True" and "False".
+type scimBool bool
+
+func (b *scimBool) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = scimBool(v)
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			*b = true
+		case "false":
+			*b = false
+		default:
+			return errors.Errorf("invalid boolean %q", v)
+		}
+	default:
+		return errors.Errorf("invalid boolean %v", v)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	f, err := parseFilter(`userName eq "alice@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, &filter{attribute: "username", value: "alice@example.com"}, f)
+	assert.True(t, f.matches("Alice@Example.com"))
+
+	for _, invalid := range []string{
+		`userName`,
+		`userName sw "alice"`,
+		`userName eq alice`,
+	} {
+		_, err := parseFilter(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	alice := func() *userResource {
+		return &userResource{
+			Schemas:  []string{schemaUser},
+			ID:       "1",
+			UserName: "alice@example.com",
+			Emails:   []email{{Value: "alice@example.com", Type: "work", Primary: true}},
+			Active:   true,
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		operations []patchOperation
+		want       func(u *userResource)
+	}{
+		{
+			name:       "Okta deactivation",
+			operations: []patchOperation{{Op: "replace", Value: map[string]any{"active": false}}},
+			want:       func(u *userResource) { u.Active = false },
+		},
+		{
+			name:       "Azure AD deactivation",
+			operations: []patchOperation{{Op: "Replace", Path: "active", Value: "False"}},
+			want:       func(u *userResource) { u.Active = false },
+		},
+		{
+			name:       "sub-attribute",
+			operations: []patchOperation{{Op: "add", Path: "name.formatted", Value: "Alice"}},
+			want:       func(u *userResource) { u.Name = &name{Formatted: "Alice"} },
+		},
+		{
+			name:       "filtered sub-attribute",
+			operations: []patchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: "alice@example.org"}},
+			want:       func(u *userResource) { u.Emails[0].Value = "alice@example.org" },
+		},
+		{
+			name: "fully qualified path",
+			operations: []patchOperation{{
+				Op:    "replace",
+				Path:  "urn:ietf:params:scim:schemas:core:2.0:User:displayName",
+				Value: "Alice",
+			}},
+			want: func(u *userResource) { u.DisplayName = "Alice" },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got userResource
+			require.NoError(t, applyPatch(alice(), &patchRequest{Operations: tc.operations}, &got))
+
+			want := alice()
+			tc.want(want)
+			if diff := cmp.Diff(want, &got); diff != "" {
+				t.Fatalf("unexpected user (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyPatch_Members(t *testing.T) {
+	group := &groupResource{
+		Schemas:     []string{schemaGroup},
+		DisplayName: "Engineering",
+		Members:     []member{{Value: "1"}, {Value: "2"}},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		operations []patchOperation
+		want       []member
+	}{
+		{
+			name:       "add",
+			operations: []patchOperation{{Op: "add", Path: "members", Value: []any{map[string]any{"value": "2"}, map[string]any{"value": "3"}}}},
+			want:       []member{{Value: "1"}, {Value: "2"}, {Value: "3"}},
+		},
+		{
+			name:       "remove by filter",
+			operations: []patchOperation{{Op: "remove", Path: `members[value eq "1"]`}},
+			want:       []member{{Value: "2"}},
+		},
+		{
+			name:       "remove by value",
+			operations: []patchOperation{{Op: "Remove", Path: "members", Value: []any{map[string]any{"value": "2"}}}},
+			want:       []member{{Value: "1"}},
+		},
+		{
+			name:       "remove all",
+			operations: []patchOperation{{Op: "remove", Path: "members"}},
+			want:       nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got groupResource
+			require.NoError(t, applyPatch(group, &patchRequest{Operations: tc.operations}, &got))
+			assert.Equal(t, tc.want, got.Members)
+		})
+	}
+
+	t.Run("unsupported operation", func(t *testing.T) {
+		var got groupResource
+		assert.Error(t, applyPatch(group, &patchRequest{Operations: []patchOperation{{Op: "move", Path: "members"}}}, &got))
+	})
+}
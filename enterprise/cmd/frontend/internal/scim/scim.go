@@ -0,0 +1,431 @@
+// Package scim implements a SCIM 2.0 service provider (RFC 7643 and RFC 7644),
+// which lets identity providers create, update, deactivate and delete users, and
+// sync their groups to organizations.
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	contentType = "application/scim+json"
+
+	// defaultCount and maxCount bound the number of resources returned by a list
+	// request.
+	defaultCount = 100
+	maxCount     = 1000
+
+	maxRequestSize = 1 << 20 // 1MB
+)
+
+// Handler serves the SCIM API under /.api/scim/v2. Users are mapped to Sourcegraph
+// users, which are linked to the identity provider through an external account,
+// and groups are mapped to organizations.
+type Handler struct {
+	logger sglog.Logger
+	db     database.DB
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(db database.DB) *Handler {
+	return &Handler{
+		logger: sglog.Scoped("SCIMHandler", "SCIM 2.0 user and group provisioning API"),
+		db:     db,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+
+	// 🚨 SECURITY: Only site admins holding an access token with the dedicated
+	// scope can provision users and groups.
+	ctx, err := h.authenticate(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	r = r.WithContext(ctx)
+
+	resp, statusCode, err := h.route(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	if resp == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("failed to write json payload to client", sglog.Error(err))
+	}
+}
+
+// route dispatches the request to the handler of the resource in its path.
+func (h *Handler) route(r *http.Request) (resp any, statusCode int, err error) {
+	parts := strings.Split(strings.Trim(mux.Vars(r)["rest"], "/"), "/")
+	if len(parts) > 2 {
+		return nil, 0, errNotFound("unknown endpoint")
+	}
+
+	var id string
+	if len(parts) == 2 {
+		id = parts[1]
+	}
+
+	switch resourceType := parts[0]; {
+	case resourceType == "ServiceProviderConfig" && id == "" && r.Method == http.MethodGet:
+		return serviceProviderConfig(), http.StatusOK, nil
+
+	case resourceType == "Users" && id == "":
+		switch r.Method {
+		case http.MethodGet:
+			return h.listUsers(r)
+		case http.MethodPost:
+			return h.createUser(r)
+		}
+	case resourceType == "Users":
+		userID, err := parseID(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		switch r.Method {
+		case http.MethodGet:
+			return h.getUser(r, userID)
+		case http.MethodPut:
+			return h.replaceUser(r, userID)
+		case http.MethodPatch:
+			return h.patchUser(r, userID)
+		case http.MethodDelete:
+			return h.deleteUser(r, userID)
+		}
+
+	case resourceType == "Groups" && id == "":
+		switch r.Method {
+		case http.MethodGet:
+			return h.listGroups(r)
+		case http.MethodPost:
+			return h.createGroup(r)
+		}
+	case resourceType == "Groups":
+		orgID, err := parseID(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		switch r.Method {
+		case http.MethodGet:
+			return h.getGroup(r, orgID)
+		case http.MethodPut:
+			return h.replaceGroup(r, orgID)
+		case http.MethodPatch:
+			return h.patchGroup(r, orgID)
+		case http.MethodDelete:
+			return h.deleteGroup(r, orgID)
+		}
+
+	default:
+		return nil, 0, errNotFound("unknown endpoint")
+	}
+	return nil, 0, &scimError{status: http.StatusMethodNotAllowed, detail: fmt.Sprintf("method %s is not supported", r.Method)}
+}
+
+// authenticate returns the context of the request with the actor of the access
+// token used to authenticate it. Identity providers send the token with the
+// "Bearer" scheme, which is only accepted by the SCIM API.
+func (h *Handler) authenticate(r *http.Request) (context.Context, error) {
+	if envvar.SourcegraphDotComMode() {
+		return nil, errNotFound("not enabled on sourcegraph.com")
+	}
+
+	if err := licensing.Check(licensing.FeatureSSO); err != nil {
+		if licensing.IsFeatureNotActivated(err) {
+			return nil, &scimError{status: http.StatusForbidden, detail: err.Error()}
+		}
+		h.logger.Error("failed to check license feature", sglog.Error(err))
+		return nil, &scimError{status: http.StatusInternalServerError, detail: "Unable to check license feature, please refer to logs for actual error message."}
+	}
+
+	ctx := r.Context()
+	a := actor.FromContext(ctx)
+	if a.AccessTokenScopes == nil {
+		// The request was not authenticated by the access token middleware, which
+		// ignores the "Bearer" scheme.
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return nil, &scimError{status: http.StatusUnauthorized, detail: "an access token with the scope " + strconv.Quote(authz.ScopeSCIMProvision) + " is required"}
+		}
+		if allow := conf.AccessTokensAllow(); allow != conf.AccessTokensAll && allow != conf.AccessTokensAdmin {
+			return nil, &scimError{status: http.StatusUnauthorized, detail: "access token authorization is disabled"}
+		}
+
+		userID, scopes, err := h.db.AccessTokens().LookupScopes(ctx, strings.TrimSpace(token))
+		if err != nil {
+			if err == database.ErrAccessTokenNotFound || errors.HasType(err, database.InvalidTokenError{}) {
+				return nil, &scimError{status: http.StatusUnauthorized, detail: "invalid access token"}
+			}
+			return nil, errors.Wrap(err, "looking up access token")
+		}
+		a = &actor.Actor{UID: userID, AccessTokenScopes: scopes}
+		ctx = actor.WithActor(ctx, a)
+	}
+
+	if !authz.HasScope(a.AccessTokenScopes, authz.ScopeSCIMProvision) {
+		return nil, &scimError{status: http.StatusForbidden, detail: (&authz.ErrScopeRequired{Scope: authz.ScopeSCIMProvision}).Error()}
+	}
+
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, h.db); err != nil {
+		if err == auth.ErrNotAuthenticated {
+			return nil, &scimError{status: http.StatusUnauthorized, detail: err.Error()}
+		}
+		return nil, &scimError{status: http.StatusForbidden, detail: err.Error()}
+	}
+	return ctx, nil
+}
+
+// logEvent records a change made through the SCIM API in the security event
+// logs. userID is the user the change applies to, or the actor for changes to
+// groups.
+func (h *Handler) logEvent(ctx context.Context, r *http.Request, name database.SecurityEventName, userID int32, argument map[string]any) {
+	if argument == nil {
+		argument = map[string]any{}
+	}
+	argument["actor"] = actor.FromContext(ctx).UID
+	arg, _ := json.Marshal(argument)
+
+	h.db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name:      name,
+		URL:       r.URL.RequestURI(),
+		UserID:    uint32(userID),
+		Argument:  arg,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	})
+}
+
+// meta is the metadata of a resource.
+type meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+func newMeta(resourceType string, id int32, created, lastModified time.Time) *meta {
+	return &meta{
+		ResourceType: resourceType,
+		Created:      created,
+		LastModified: lastModified,
+		Location:     fmt.Sprintf("%s/.api/scim/v2/%ss/%d", strings.TrimSuffix(conf.ExternalURL(), "/"), resourceType, id),
+	}
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+func newListResponse(resources []any, totalResults, startIndex int) *listResponse {
+	if resources == nil {
+		resources = []any{}
+	}
+	return &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// listOptions are the pagination and filtering parameters of a list request.
+type listOptions struct {
+	filter     *filter
+	startIndex int
+	count      int
+}
+
+func parseListOptions(r *http.Request) (opts listOptions, err error) {
+	q := r.URL.Query()
+
+	opts.startIndex = 1
+	if v := q.Get("startIndex"); v != "" {
+		if opts.startIndex, err = strconv.Atoi(v); err != nil {
+			return opts, errInvalidValue("startIndex must be an integer")
+		}
+		if opts.startIndex < 1 {
+			opts.startIndex = 1
+		}
+	}
+
+	opts.count = defaultCount
+	if v := q.Get("count"); v != "" {
+		if opts.count, err = strconv.Atoi(v); err != nil {
+			return opts, errInvalidValue("count must be an integer")
+		}
+		if opts.count < 0 {
+			opts.count = 0
+		}
+		if opts.count > maxCount {
+			opts.count = maxCount
+		}
+	}
+
+	if v := q.Get("filter"); v != "" {
+		f, err := parseFilter(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.filter = f
+	}
+	return opts, nil
+}
+
+func (o listOptions) limitOffset() *database.LimitOffset {
+	return &database.LimitOffset{Limit: o.count, Offset: o.startIndex - 1}
+}
+
+// page returns the page of the given resources selected by the options.
+func (o listOptions) page(resources []any) []any {
+	start := o.startIndex - 1
+	if start >= len(resources) {
+		return nil
+	}
+	end := start + o.count
+	if end > len(resources) {
+		end = len(resources)
+	}
+	return resources[start:end]
+}
+
+func serviceProviderConfig() any {
+	type supported struct {
+		Supported bool `json:"supported"`
+	}
+	type withMax struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults,omitempty"`
+	}
+	type authenticationScheme struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	return struct {
+		Schemas               []string               `json:"schemas"`
+		Patch                 supported              `json:"patch"`
+		Bulk                  supported              `json:"bulk"`
+		Filter                withMax                `json:"filter"`
+		ChangePassword        supported              `json:"changePassword"`
+		Sort                  supported              `json:"sort"`
+		ETag                  supported              `json:"etag"`
+		AuthenticationSchemes []authenticationScheme `json:"authenticationSchemes"`
+	}{
+		Schemas: []string{schemaServiceProviderConfig},
+		Patch:   supported{Supported: true},
+		Filter:  withMax{Supported: true, MaxResults: maxCount},
+		AuthenticationSchemes: []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Access token",
+			Description: "A Sourcegraph access token of a site admin with the " + strconv.Quote(authz.ScopeSCIMProvision) + " scope.",
+		}},
+	}
+}
+
+func parseID(id string) (int32, error) {
+	n, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return 0, errNotFound("resource " + strconv.Quote(id) + " does not exist")
+	}
+	return int32(n), nil
+}
+
+// decodeBody decodes the JSON body of the request into v.
+func decodeBody(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &scimError{status: http.StatusRequestEntityTooLarge, detail: "request payload exceeds 1MB limit"}
+		}
+		return &scimError{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: err.Error()}
+	}
+	return nil
+}
+
+// scimError is an error that is returned to the client in the format defined by
+// RFC 7644, section 3.12.
+type scimError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *scimError) Error() string { return e.detail }
+
+func errNotFound(detail string) error {
+	return &scimError{status: http.StatusNotFound, detail: detail}
+}
+
+func errInvalidValue(detail string) error {
+	return &scimError{status: http.StatusBadRequest, scimType: "invalidValue", detail: detail}
+}
+
+func errUniqueness(detail string) error {
+	return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: detail}
+}
+
+func errForbidden(detail string) error {
+	return &scimError{status: http.StatusForbidden, detail: detail}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	var e *scimError
+	if !errors.As(err, &e) {
+		h.logger.Error("failed to handle SCIM request", sglog.Error(err))
+		e = &scimError{status: http.StatusInternalServerError, detail: "internal error, please refer to logs for actual error message"}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(e.status)
+	if err := json.NewEncoder(w).Encode(struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		SCIMType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail"`
+	}{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(e.status),
+		SCIMType: e.scimType,
+		Detail:   e.detail,
+	}); err != nil {
+		h.logger.Error("failed to write json payload to client", sglog.Error(err))
+	}
+}
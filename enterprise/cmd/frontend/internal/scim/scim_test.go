@@ -0,0 +1,391 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func newRequest(ctx context.Context, method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, "/.api/scim/v2/"+target, strings.NewReader(body)).WithContext(ctx)
+	rest, _, _ := strings.Cut(target, "?")
+	return mux.SetURLVars(r, map[string]string{"rest": rest})
+}
+
+func TestHandler_Authentication(t *testing.T) {
+	defer licensing.TestingSkipFeatureChecks()()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{AuthAccessTokens: &schema.AuthAccessTokens{Allow: string(conf.AccessTokensAll)}}})
+	defer conf.Mock(nil)
+
+	newDB := func(siteAdmin bool) *database.MockDB {
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: siteAdmin}, nil)
+
+		accessTokens := database.NewMockAccessTokenStore()
+		accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, token string) (int32, []string, error) {
+			if token != "abc" {
+				return 0, nil, database.ErrAccessTokenNotFound
+			}
+			return 1, []string{authz.ScopeSCIMProvision}, nil
+		})
+
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+		return db
+	}
+
+	for _, tc := range []struct {
+		name          string
+		actor         *actor.Actor
+		authorization string
+		siteAdmin     bool
+		wantStatus    int
+	}{
+		{
+			name:       "no access token",
+			actor:      &actor.Actor{},
+			siteAdmin:  true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "session cookie",
+			actor:      &actor.Actor{UID: 1},
+			siteAdmin:  true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "invalid bearer token",
+			actor:         &actor.Actor{},
+			authorization: "Bearer xyz",
+			siteAdmin:     true,
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "access token without scope",
+			actor:      &actor.Actor{UID: 1, AccessTokenScopes: []string{authz.ScopeUserAll}},
+			siteAdmin:  true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:          "bearer token of user",
+			actor:         &actor.Actor{},
+			authorization: "Bearer abc",
+			siteAdmin:     false,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "bearer token of site admin",
+			actor:         &actor.Actor{},
+			authorization: "Bearer abc",
+			siteAdmin:     true,
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:       "access token of site admin",
+			actor:      &actor.Actor{UID: 1, AccessTokenScopes: []string{authz.ScopeSCIMProvision}},
+			siteAdmin:  true,
+			wantStatus: http.StatusOK,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newRequest(actor.WithActor(context.Background(), tc.actor), "GET", "ServiceProviderConfig", "")
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			NewHandler(newDB(tc.siteAdmin)).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.wantStatus, w.Code, w.Body.String())
+			assert.Equal(t, contentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestHandler_Users(t *testing.T) {
+	defer licensing.TestingSkipFeatureChecks()()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: []string{authz.ScopeSCIMProvision}})
+
+	newDB := func() (*database.MockDB, *database.MockUserStore, *database.MockUserExternalAccountsStore, *database.MockSecurityEventLogsStore) {
+		deleted := map[int32]bool{}
+
+		users := database.NewMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+		users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 2, Username: "alice"}, nil)
+		users.GetByUsernameFunc.SetDefaultReturn(nil, database.NewUserNotFoundError(0))
+		users.GetByVerifiedEmailFunc.SetDefaultReturn(nil, database.NewUserNotFoundError(0))
+		users.ListFunc.SetDefaultHook(func(_ context.Context, opts *database.UsersListOptions) ([]*types.User, error) {
+			if !opts.IncludeDeleted && deleted[2] {
+				return nil, nil
+			}
+			return []*types.User{{ID: 2, Username: "alice", DisplayName: "Alice"}}, nil
+		})
+		users.DeleteFunc.SetDefaultHook(func(_ context.Context, id int32) error {
+			deleted[id] = true
+			return nil
+		})
+		users.RecoverUsersListFunc.SetDefaultHook(func(_ context.Context, ids []int32) ([]int32, error) {
+			for _, id := range ids {
+				deleted[id] = false
+			}
+			return ids, nil
+		})
+
+		externalAccounts := database.NewMockUserExternalAccountsStore()
+		externalAccounts.CreateUserAndSaveFunc.SetDefaultReturn(2, nil)
+		// alice is linked to the identity provider.
+		externalAccounts.ListFunc.SetDefaultHook(func(_ context.Context, opts database.ExternalAccountsListOptions) ([]*extsvc.Account, error) {
+			if opts.UserID != 2 {
+				return nil, nil
+			}
+			return []*extsvc.Account{{UserID: 2, AccountSpec: extsvc.AccountSpec{ServiceType: "scim", ServiceID: "scim", AccountID: "00u1"}}}, nil
+		})
+
+		securityEventLogs := database.NewMockSecurityEventLogsStore()
+
+		db := database.NewMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.UserExternalAccountsFunc.SetDefaultReturn(externalAccounts)
+		db.UserEmailsFunc.SetDefaultReturn(database.NewMockUserEmailsStore())
+		db.OrgsFunc.SetDefaultReturn(database.NewMockOrgStore())
+		db.SecurityEventLogsFunc.SetDefaultReturn(securityEventLogs)
+		db.UserSessionsFunc.SetDefaultReturn(database.NewMockUserSessionStore())
+		return db, users, externalAccounts, securityEventLogs
+	}
+
+	eventNames := func(securityEventLogs *database.MockSecurityEventLogsStore) (names []database.SecurityEventName) {
+		for _, call := range securityEventLogs.LogEventFunc.History() {
+			names = append(names, call.Arg1.Name)
+		}
+		return names
+	}
+
+	t.Run("create", func(t *testing.T) {
+		db, _, externalAccounts, securityEventLogs := newDB()
+
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "POST", "Users", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"externalId": "00u1",
+			"userName": "alice@example.com",
+			"name": {"givenName": "Alice", "familyName": "Smith"},
+			"emails": [{"value": "alice@example.com", "primary": true}]
+		}`))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		mockrequire.CalledOnce(t, externalAccounts.CreateUserAndSaveFunc)
+		call := externalAccounts.CreateUserAndSaveFunc.History()[0]
+		assert.Equal(t, database.NewUser{
+			Username:        "alice",
+			DisplayName:     "Alice Smith",
+			Email:           "alice@example.com",
+			EmailIsVerified: true,
+		}, call.Arg1)
+		assert.Equal(t, extsvc.AccountSpec{ServiceType: "scim", ServiceID: "scim", AccountID: "00u1"}, call.Arg2)
+
+		var resp userResource
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "2", resp.ID)
+		assert.True(t, bool(resp.Active))
+
+		assert.Equal(t, []database.SecurityEventName{database.SecurityEventSCIMUserCreated}, eventNames(securityEventLogs))
+	})
+
+	t.Run("create duplicate", func(t *testing.T) {
+		db, _, externalAccounts, _ := newDB()
+		externalAccounts.ListFunc.SetDefaultReturn([]*extsvc.Account{{UserID: 2}}, nil)
+
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "POST", "Users", `{"userName": "alice@example.com"}`))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockrequire.NotCalled(t, externalAccounts.CreateUserAndSaveFunc)
+	})
+
+	t.Run("deactivate and reactivate", func(t *testing.T) {
+		db, users, _, securityEventLogs := newDB()
+		handler := NewHandler(db)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest(ctx, "PATCH", "Users/2", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "Replace", "path": "active", "value": "False"}]
+		}`))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		mockrequire.CalledOnceWith(t, users.DeleteFunc, mockrequire.Values(mockrequire.Skip, int32(2)))
+		mockrequire.CalledOnceWith(t, users.InvalidateSessionsByIDsFunc, mockrequire.Values(mockrequire.Skip, []int32{2}))
+
+		var resp userResource
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.False(t, bool(resp.Active))
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest(ctx, "PATCH", "Users/2", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "replace", "value": {"active": true}}]
+		}`))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		mockrequire.CalledOnceWith(t, users.RecoverUsersListFunc, mockrequire.Values(mockrequire.Skip, []int32{2}))
+
+		assert.Equal(t, []database.SecurityEventName{
+			database.SecurityEventSCIMUserUpdated,
+			database.SecurityEventSCIMUserDeactivated,
+			database.SecurityEventSCIMUserReactivated,
+			database.SecurityEventSCIMUserUpdated,
+		}, eventNames(securityEventLogs))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		db, users, _, securityEventLogs := newDB()
+
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "DELETE", "Users/2", ""))
+		require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+		mockrequire.CalledOnceWith(t, users.DeleteFunc, mockrequire.Values(mockrequire.Skip, int32(2)))
+		mockrequire.CalledOnceWith(t, users.InvalidateSessionsByIDsFunc, mockrequire.Values(mockrequire.Skip, []int32{2}))
+		mockrequire.NotCalled(t, users.HardDeleteFunc)
+		assert.Equal(t, []database.SecurityEventName{database.SecurityEventSCIMUserDeleted}, eventNames(securityEventLogs))
+	})
+
+	t.Run("unmanaged users cannot be modified", func(t *testing.T) {
+		for _, tc := range []struct {
+			name      string
+			siteAdmin bool
+			linked    bool
+		}{
+			{name: "user not provisioned through SCIM", siteAdmin: false, linked: false},
+			{name: "site admin", siteAdmin: true, linked: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				db, users, externalAccounts, securityEventLogs := newDB()
+				users.ListFunc.SetDefaultReturn([]*types.User{{ID: 2, Username: "alice", SiteAdmin: tc.siteAdmin}}, nil)
+				if !tc.linked {
+					externalAccounts.ListFunc.SetDefaultReturn(nil, nil)
+				}
+				handler := NewHandler(db)
+
+				for _, r := range []*http.Request{
+					newRequest(ctx, "DELETE", "Users/2", ""),
+					newRequest(ctx, "PUT", "Users/2", `{"userName": "alice", "active": false}`),
+					newRequest(ctx, "PATCH", "Users/2", `{
+						"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+						"Operations": [{"op": "replace", "path": "active", "value": false}]
+					}`),
+				} {
+					w := httptest.NewRecorder()
+					handler.ServeHTTP(w, r)
+					assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", r.Method, w.Body.String())
+				}
+
+				mockrequire.NotCalled(t, users.DeleteFunc)
+				mockrequire.NotCalled(t, users.HardDeleteFunc)
+				mockrequire.NotCalled(t, users.UpdateFunc)
+				mockrequire.NotCalled(t, users.InvalidateSessionsByIDsFunc)
+				assert.Empty(t, eventNames(securityEventLogs))
+			})
+		}
+	})
+
+	t.Run("site admins are not linked", func(t *testing.T) {
+		db, users, externalAccounts, _ := newDB()
+		users.GetByVerifiedEmailFunc.SetDefaultReturn(&types.User{ID: 3, Username: "admin", SiteAdmin: true}, nil)
+
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "POST", "Users", `{
+			"userName": "admin@example.com",
+			"emails": [{"value": "admin@example.com", "primary": true}]
+		}`))
+		assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		mockrequire.NotCalled(t, externalAccounts.AssociateUserAndSaveFunc)
+		mockrequire.NotCalled(t, externalAccounts.CreateUserAndSaveFunc)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, users, _, _ := newDB()
+		users.ListFunc.SetDefaultReturn(nil, nil)
+
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "GET", "Users/3", ""))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandler_Groups(t *testing.T) {
+	defer licensing.TestingSkipFeatureChecks()()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: []string{authz.ScopeSCIMProvision}})
+
+	users := database.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+	users.GetByUsernameFunc.SetDefaultReturn(nil, database.NewUserNotFoundError(0))
+	users.ListFunc.SetDefaultHook(func(_ context.Context, opts *database.UsersListOptions) (result []*types.User, _ error) {
+		for _, id := range opts.UserIDs {
+			result = append(result, &types.User{ID: id})
+		}
+		return result, nil
+	})
+
+	displayName := "Engineering"
+	orgs := database.NewMockOrgStore()
+	orgs.GetByNameFunc.SetDefaultReturn(nil, &database.OrgNotFoundError{Message: "name engineering"})
+	orgs.GetByIDFunc.SetDefaultReturn(&types.Org{ID: 3, Name: "Engineering", DisplayName: &displayName}, nil)
+	orgs.CreateFunc.SetDefaultReturn(&types.Org{ID: 3, Name: "Engineering", DisplayName: &displayName}, nil)
+
+	orgMembers := database.NewMockOrgMemberStore()
+	orgMembers.GetByOrgIDFunc.SetDefaultReturn([]*types.OrgMembership{{OrgID: 3, UserID: 1}, {OrgID: 3, UserID: 2}}, nil)
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.OrgsFunc.SetDefaultReturn(orgs)
+	db.OrgMembersFunc.SetDefaultReturn(orgMembers)
+	db.SecurityEventLogsFunc.SetDefaultReturn(database.NewMockSecurityEventLogsStore())
+
+	t.Run("create", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "POST", "Groups", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"],
+			"displayName": "Engineering",
+			"members": [{"value": "1"}, {"value": "2"}]
+		}`))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		mockrequire.CalledOnceWith(t, orgs.CreateFunc, mockrequire.Values(mockrequire.Skip, "Engineering", &displayName))
+	})
+
+	t.Run("patch members", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "PATCH", "Groups/3", `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "remove", "path": "members[value eq \"2\"]"},
+				{"op": "add", "path": "members", "value": [{"value": "4"}]}
+			]
+		}`))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		mockrequire.CalledOnceWith(t, orgMembers.RemoveFunc, mockrequire.Values(mockrequire.Skip, int32(3), int32(2)))
+		mockrequire.CalledOnceWith(t, orgMembers.CreateFunc, mockrequire.Values(mockrequire.Skip, int32(3), int32(4)))
+	})
+
+	t.Run("unsupported filter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHandler(db).ServeHTTP(w, newRequest(ctx, "GET", `Groups?filter=members+eq+"1"`, ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	})
+}
@@ -0,0 +1,761 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const (
+	// accountServiceType and accountServiceID identify the external accounts
+	// that link users to the identity provider. The account ID is the external
+	// ID of the user, or its user name if the identity provider does not set one.
+	accountServiceType = "scim"
+	accountServiceID   = "scim"
+)
+
+// userResource is the SCIM representation of a user (RFC 7643, section 4.1).
+type userResource struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []email  `json:"emails,omitempty"`
+	Active      scimBool `json:"active"`
+	Groups      []member `json:"groups,omitempty"`
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+type name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type email struct {
+	Value   string   `json:"value"`
+	Type    string   `json:"type,omitempty"`
+	Primary scimBool `json:"primary,omitempty"`
+}
+
+// displayName returns the display name of the user, falling back to its
+// formatted or full name.
+func (u *userResource) displayName() string {
+	if u.DisplayName != "" || u.Name == nil {
+		return u.DisplayName
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// primaryEmail returns the primary email address of the user, or its first
+// email address if none is marked as primary.
+func (u *userResource) primaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// accountID returns the ID of the external account linking the user to the
+// identity provider.
+func (u *userResource) accountID() string {
+	if u.ExternalID != "" {
+		return u.ExternalID
+	}
+	return u.UserName
+}
+
+// accountData is the data stored in the external account of a user, which
+// holds the attributes that do not map to a Sourcegraph user attribute, and the
+// email addresses of the user, which are removed when it is deactivated.
+type accountData struct {
+	UserName   string  `json:"userName"`
+	ExternalID string  `json:"externalId,omitempty"`
+	Name       *name   `json:"name,omitempty"`
+	Emails     []email `json:"emails,omitempty"`
+}
+
+// decodeUser decodes and validates the user in the body of the request.
+func decodeUser(r *http.Request) (*userResource, error) {
+	// Users are active unless stated otherwise.
+	u := &userResource{Active: true}
+	if err := decodeBody(r, u); err != nil {
+		return nil, err
+	}
+	if err := validateUser(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func validateUser(u *userResource) error {
+	if strings.TrimSpace(u.UserName) == "" {
+		return errInvalidValue("userName is required")
+	}
+	for _, e := range u.Emails {
+		if !strings.Contains(e.Value, "@") {
+			return errInvalidValue("invalid email address " + strconv.Quote(e.Value))
+		}
+	}
+	return nil
+}
+
+func (h *Handler) listUsers(r *http.Request) (any, int, error) {
+	ctx := r.Context()
+	opts, err := parseListOptions(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if opts.filter != nil {
+		users, err := h.findUsers(ctx, opts.filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		resources, err := h.toUserResources(ctx, users)
+		if err != nil {
+			return nil, 0, err
+		}
+		var matching []any
+		for _, u := range resources {
+			if userMatches(u, opts.filter) {
+				matching = append(matching, u)
+			}
+		}
+		return newListResponse(opts.page(matching), len(matching), opts.startIndex), http.StatusOK, nil
+	}
+
+	listOpts := database.UsersListOptions{IncludeDeleted: true, ExcludeSourcegraphOperators: true}
+	total, err := h.db.Users().Count(ctx, &listOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	listOpts.LimitOffset = opts.limitOffset()
+	users, err := h.db.Users().List(ctx, &listOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	resources, err := h.toUserResources(ctx, users)
+	if err != nil {
+		return nil, 0, err
+	}
+	page := make([]any, len(resources))
+	for i, u := range resources {
+		page[i] = u
+	}
+	return newListResponse(page, total, opts.startIndex), http.StatusOK, nil
+}
+
+// findUsers returns the candidates for the users matching the filter. The
+// caller must check whether the candidates actually match.
+func (h *Handler) findUsers(ctx context.Context, f *filter) ([]*types.User, error) {
+	var userIDs []int32
+	switch f.attribute {
+	case "username", "externalid":
+		accounts, err := h.db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{
+			ServiceType:    accountServiceType,
+			ServiceID:      accountServiceID,
+			AccountID:      f.value,
+			IncludeDeleted: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range accounts {
+			userIDs = append(userIDs, a.UserID)
+		}
+
+		// Users that were not provisioned through SCIM are matched by their
+		// username.
+		if f.attribute == "username" {
+			if username, err := auth.NormalizeUsername(f.value); err == nil {
+				user, err := h.db.Users().GetByUsername(ctx, username)
+				if err != nil && !errcode.IsNotFound(err) {
+					return nil, err
+				}
+				if user != nil {
+					userIDs = append(userIDs, user.ID)
+				}
+			}
+		}
+
+	case "emails", "emails.value":
+		user, err := h.db.Users().GetByVerifiedEmail(ctx, f.value)
+		if err != nil && !errcode.IsNotFound(err) {
+			return nil, err
+		}
+		if user != nil {
+			userIDs = append(userIDs, user.ID)
+		}
+
+	default:
+		return nil, errInvalidFilter("filtering users by " + strconv.Quote(f.attribute) + " is not supported")
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: userIDs, IncludeDeleted: true})
+}
+
+func userMatches(u *userResource, f *filter) bool {
+	switch f.attribute {
+	case "username":
+		return f.matches(u.UserName)
+	case "externalid":
+		return f.matches(u.ExternalID)
+	case "emails", "emails.value":
+		for _, e := range u.Emails {
+			if f.matches(e.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (h *Handler) getUser(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	user, err := h.getUserByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	resource, err := h.toUserResource(ctx, user)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resource, http.StatusOK, nil
+}
+
+func (h *Handler) createUser(r *http.Request) (any, int, error) {
+	ctx := r.Context()
+	u, err := decodeUser(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	spec := accountSpec(u.accountID())
+	taken, err := h.isAccountTaken(ctx, spec.AccountID, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if taken {
+		return nil, 0, errUniqueness("user " + strconv.Quote(spec.AccountID) + " already exists")
+	}
+
+	data, err := newAccountData(u)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Link the user to an existing user with the same verified email address,
+	// such as a user created just-in-time by a SAML or OpenID Connect auth
+	// provider, instead of creating a duplicate user.
+	var userID int32
+	if primaryEmail := u.primaryEmail(); primaryEmail != "" {
+		user, err := h.db.Users().GetByVerifiedEmail(ctx, primaryEmail)
+		if err != nil && !errcode.IsNotFound(err) {
+			return nil, 0, err
+		}
+		if user != nil {
+			account, err := h.getAccount(ctx, user.ID)
+			if err != nil {
+				return nil, 0, err
+			}
+			// 🚨 SECURITY: Site admins are never linked to the identity provider,
+			// so that they cannot be modified or deactivated through SCIM.
+			if account != nil || user.SiteAdmin {
+				return nil, 0, errUniqueness("a user with the email address " + strconv.Quote(primaryEmail) + " already exists")
+			}
+			if err := h.db.UserExternalAccounts().AssociateUserAndSave(ctx, user.ID, spec, data); err != nil {
+				return nil, 0, err
+			}
+			userID = user.ID
+		}
+	}
+
+	if userID == 0 {
+		username, err := auth.NormalizeUsername(u.UserName)
+		if err != nil {
+			return nil, 0, errInvalidValue(err.Error())
+		}
+		userID, err = h.db.UserExternalAccounts().CreateUserAndSave(ctx, database.NewUser{
+			Username:    username,
+			DisplayName: u.displayName(),
+			Email:       u.primaryEmail(),
+			// 🚨 SECURITY: The email addresses are verified by the identity
+			// provider, which is trusted by the site admin.
+			EmailIsVerified: true,
+		}, spec, data)
+		if err != nil {
+			if database.IsUsernameExists(err) || database.IsEmailExists(err) {
+				return nil, 0, errUniqueness(err.Error())
+			}
+			return nil, 0, err
+		}
+	}
+
+	user, err := h.db.Users().GetByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := h.updateUser(ctx, user, u); err != nil {
+		return nil, 0, err
+	}
+	h.logEvent(ctx, r, database.SecurityEventSCIMUserCreated, userID, map[string]any{"externalId": u.ExternalID, "userName": u.UserName})
+
+	if !u.Active {
+		if err := h.db.Users().Delete(ctx, userID); err != nil {
+			return nil, 0, err
+		}
+		h.logEvent(ctx, r, database.SecurityEventSCIMUserDeactivated, userID, nil)
+	}
+
+	resource, err := h.getUserResource(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resource, http.StatusCreated, nil
+}
+
+func (h *Handler) replaceUser(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	user, err := h.getManagedUser(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	u, err := decodeUser(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.applyUser(r, user, u)
+}
+
+func (h *Handler) patchUser(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	user, err := h.getManagedUser(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	var req patchRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, 0, err
+	}
+
+	current, err := h.toUserResource(ctx, user)
+	if err != nil {
+		return nil, 0, err
+	}
+	var u userResource
+	if err := applyPatch(current, &req, &u); err != nil {
+		return nil, 0, err
+	}
+	if err := validateUser(&u); err != nil {
+		return nil, 0, err
+	}
+	return h.applyUser(r, user, &u)
+}
+
+// applyUser updates the existing user to match u, and activates or deactivates
+// it as needed. The attributes of deactivated users are not updated, because
+// deactivated users are soft-deleted.
+func (h *Handler) applyUser(r *http.Request, user *types.User, u *userResource) (any, int, error) {
+	ctx := r.Context()
+	active, err := h.isActive(ctx, user.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !active && bool(u.Active) {
+		recovered, err := h.db.Users().RecoverUsersList(ctx, []int32{user.ID})
+		if err != nil {
+			if database.IsUsernameExists(err) {
+				return nil, 0, errUniqueness("the username of the user has been taken by another user or organization")
+			}
+			return nil, 0, err
+		}
+		active = len(recovered) > 0
+		if active {
+			h.logEvent(ctx, r, database.SecurityEventSCIMUserReactivated, user.ID, nil)
+		}
+	}
+
+	if active {
+		if err := h.updateUser(ctx, user, u); err != nil {
+			return nil, 0, err
+		}
+		h.logEvent(ctx, r, database.SecurityEventSCIMUserUpdated, user.ID, map[string]any{"externalId": u.ExternalID, "userName": u.UserName})
+	}
+
+	if active && !bool(u.Active) {
+		if err := session.InvalidateSessionsByIDs(ctx, h.db, []int32{user.ID}); err != nil {
+			return nil, 0, err
+		}
+		if err := h.db.Users().Delete(ctx, user.ID); err != nil {
+			return nil, 0, err
+		}
+		h.logEvent(ctx, r, database.SecurityEventSCIMUserDeactivated, user.ID, nil)
+	}
+
+	resource, err := h.getUserResource(ctx, user.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resource, http.StatusOK, nil
+}
+
+// updateUser updates the attributes of the active user to match u.
+func (h *Handler) updateUser(ctx context.Context, user *types.User, u *userResource) error {
+	update := database.UserUpdate{}
+	if username, err := auth.NormalizeUsername(u.UserName); err != nil {
+		return errInvalidValue(err.Error())
+	} else if username != user.Username && !h.isLinkedTo(ctx, user.ID, u.UserName) {
+		// Only rename users whose user name changed in the identity provider, to
+		// keep the usernames of existing users linked to it, which may differ
+		// from their user name in the identity provider.
+		other, err := h.db.Users().GetByUsername(ctx, username)
+		if err != nil && !errcode.IsNotFound(err) {
+			return err
+		}
+		if other != nil && other.ID != user.ID {
+			return errUniqueness("username " + strconv.Quote(username) + " is already in use")
+		}
+		update.Username = username
+	}
+	if displayName := u.displayName(); displayName != user.DisplayName {
+		update.DisplayName = &displayName
+	}
+	if update.Username != "" || update.DisplayName != nil {
+		if err := h.db.Users().Update(ctx, user.ID, update); err != nil {
+			return err
+		}
+	}
+
+	data, err := newAccountData(u)
+	if err != nil {
+		return err
+	}
+	if err := h.saveAccount(ctx, user.ID, u.accountID(), data); err != nil {
+		return err
+	}
+
+	return h.syncEmails(ctx, user.ID, u)
+}
+
+// isLinkedTo reports whether the user is linked to the identity provider with
+// the given user name.
+func (h *Handler) isLinkedTo(ctx context.Context, userID int32, userName string) bool {
+	account, err := h.getAccount(ctx, userID)
+	if err != nil || account == nil {
+		return false
+	}
+	data, err := decryptAccountData(ctx, account)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(data.UserName, userName)
+}
+
+// saveAccount creates or updates the external account linking the user to the
+// identity provider.
+func (h *Handler) saveAccount(ctx context.Context, userID int32, accountID string, data extsvc.AccountData) error {
+	taken, err := h.isAccountTaken(ctx, accountID, userID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errUniqueness("user " + strconv.Quote(accountID) + " already exists")
+	}
+
+	account, err := h.getAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if account != nil && account.AccountID != accountID {
+		// The external ID of the user changed.
+		if err := h.db.UserExternalAccounts().Delete(ctx, database.ExternalAccountsDeleteOptions{IDs: []int32{account.ID}}); err != nil {
+			return err
+		}
+	}
+	return h.db.UserExternalAccounts().AssociateUserAndSave(ctx, userID, accountSpec(accountID), data)
+}
+
+// isAccountTaken reports whether a user other than userID is linked to the
+// identity provider with the given account ID. Pass 0 as userID to check for
+// any user.
+func (h *Handler) isAccountTaken(ctx context.Context, accountID string, userID int32) (bool, error) {
+	accounts, err := h.db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{
+		ServiceType:    accountServiceType,
+		ServiceID:      accountServiceID,
+		AccountID:      accountID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, a := range accounts {
+		if a.UserID != userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// syncEmails adds the email addresses of u to the user as verified addresses,
+// sets its primary email address and removes the other email addresses of the
+// user. Email addresses are left unchanged if u has none.
+func (h *Handler) syncEmails(ctx context.Context, userID int32, u *userResource) error {
+	if len(u.Emails) == 0 {
+		return nil
+	}
+
+	existing, err := h.db.UserEmails().ListByUser(ctx, database.UserEmailsListOptions{UserID: userID})
+	if err != nil {
+		return err
+	}
+	existingByEmail := make(map[string]*database.UserEmail, len(existing))
+	for _, e := range existing {
+		existingByEmail[strings.ToLower(e.Email)] = e
+	}
+
+	wanted := make(map[string]struct{}, len(u.Emails))
+	for _, e := range u.Emails {
+		wanted[strings.ToLower(e.Value)] = struct{}{}
+		if existing, ok := existingByEmail[strings.ToLower(e.Value)]; ok && existing.VerifiedAt != nil {
+			continue
+		} else if !ok {
+			if err := h.db.UserEmails().Add(ctx, userID, e.Value, nil); err != nil {
+				if database.IsEmailExists(err) {
+					return errUniqueness(err.Error())
+				}
+				return err
+			}
+		}
+		// 🚨 SECURITY: The email addresses are verified by the identity provider,
+		// which is trusted by the site admin.
+		if err := h.db.UserEmails().SetVerified(ctx, userID, e.Value, true); err != nil {
+			return err
+		}
+	}
+
+	if primary := u.primaryEmail(); existingByEmail[strings.ToLower(primary)] == nil || !existingByEmail[strings.ToLower(primary)].Primary {
+		if err := h.db.UserEmails().SetPrimaryEmail(ctx, userID, primary); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range existing {
+		if _, ok := wanted[strings.ToLower(e.Email)]; !ok {
+			if err := h.db.UserEmails().Remove(ctx, userID, e.Email); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteUser soft-deletes the user, in the same way as deactivating it, so
+// that a user deleted by mistake in the identity provider can be recovered.
+func (h *Handler) deleteUser(r *http.Request, id int32) (any, int, error) {
+	ctx := r.Context()
+	if _, err := h.getManagedUser(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	active, err := h.isActive(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if active {
+		if err := session.InvalidateSessionsByIDs(ctx, h.db, []int32{id}); err != nil {
+			return nil, 0, err
+		}
+		if err := h.db.Users().Delete(ctx, id); err != nil {
+			return nil, 0, err
+		}
+	}
+	h.logEvent(ctx, r, database.SecurityEventSCIMUserDeleted, id, nil)
+	return nil, http.StatusNoContent, nil
+}
+
+// getManagedUser returns the user with the given ID if it can be modified
+// through SCIM, including deactivated users.
+//
+// 🚨 SECURITY: Only users provisioned through SCIM, i.e. linked to the identity
+// provider, can be updated, deactivated or deleted, and never site admins.
+// Otherwise a SCIM client could lock out any user of the instance.
+func (h *Handler) getManagedUser(ctx context.Context, id int32) (*types.User, error) {
+	user, err := h.getUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.SiteAdmin {
+		return nil, errForbidden("user " + strconv.Itoa(int(id)) + " is a site admin and cannot be modified through SCIM")
+	}
+	account, err := h.getAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errForbidden("user " + strconv.Itoa(int(id)) + " was not provisioned through SCIM and cannot be modified through it")
+	}
+	return user, nil
+}
+
+// getUserByID returns the user with the given ID, including deactivated users.
+func (h *Handler) getUserByID(ctx context.Context, id int32) (*types.User, error) {
+	users, err := h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: []int32{id}, IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, errNotFound("user " + strconv.Itoa(int(id)) + " does not exist")
+	}
+	return users[0], nil
+}
+
+func (h *Handler) getUserResource(ctx context.Context, id int32) (*userResource, error) {
+	user, err := h.getUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return h.toUserResource(ctx, user)
+}
+
+func (h *Handler) isActive(ctx context.Context, userID int32) (bool, error) {
+	users, err := h.db.Users().List(ctx, &database.UsersListOptions{UserIDs: []int32{userID}})
+	if err != nil {
+		return false, err
+	}
+	return len(users) > 0, nil
+}
+
+// getAccount returns the external account linking the user to the identity
+// provider, or nil if there is none.
+func (h *Handler) getAccount(ctx context.Context, userID int32) (*extsvc.Account, error) {
+	accounts, err := h.db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{
+		UserID:         userID,
+		ServiceType:    accountServiceType,
+		ServiceID:      accountServiceID,
+		IncludeDeleted: true,
+	})
+	if err != nil || len(accounts) == 0 {
+		return nil, err
+	}
+	return accounts[len(accounts)-1], nil
+}
+
+func (h *Handler) toUserResources(ctx context.Context, users []*types.User) ([]*userResource, error) {
+	resources := make([]*userResource, 0, len(users))
+	for _, user := range users {
+		resource, err := h.toUserResource(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func (h *Handler) toUserResource(ctx context.Context, user *types.User) (*userResource, error) {
+	active, err := h.isActive(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &userResource{
+		Schemas:     []string{schemaUser},
+		ID:          strconv.Itoa(int(user.ID)),
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      scimBool(active),
+		Meta:        newMeta("User", user.ID, user.CreatedAt, user.UpdatedAt),
+	}
+
+	data := &accountData{}
+	account, err := h.getAccount(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if account != nil {
+		if data, err = decryptAccountData(ctx, account); err != nil {
+			return nil, err
+		}
+		u.ExternalID = data.ExternalID
+		u.Name = data.Name
+		if data.UserName != "" {
+			u.UserName = data.UserName
+		}
+	}
+
+	if !active {
+		// The email addresses of deactivated users are removed, so they are
+		// restored from the identity provider when the user is reactivated.
+		u.Emails = data.Emails
+	} else {
+		emails, err := h.db.UserEmails().ListByUser(ctx, database.UserEmailsListOptions{UserID: user.ID, OnlyVerified: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range emails {
+			u.Emails = append(u.Emails, email{Value: e.Email, Type: "work", Primary: scimBool(e.Primary)})
+		}
+	}
+
+	orgs, err := h.db.Orgs().GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		u.Groups = append(u.Groups, member{Value: strconv.Itoa(int(org.ID)), Display: orgDisplayName(org)})
+	}
+	return u, nil
+}
+
+func accountSpec(accountID string) extsvc.AccountSpec {
+	return extsvc.AccountSpec{
+		ServiceType: accountServiceType,
+		ServiceID:   accountServiceID,
+		ClientID:    "",
+		AccountID:   accountID,
+	}
+}
+
+func newAccountData(u *userResource) (extsvc.AccountData, error) {
+	serialized, err := json.Marshal(accountData{
+		UserName:   u.UserName,
+		ExternalID: u.ExternalID,
+		Name:       u.Name,
+		Emails:     u.Emails,
+	})
+	if err != nil {
+		return extsvc.AccountData{}, err
+	}
+	return extsvc.AccountData{Data: extsvc.NewUnencryptedData(serialized)}, nil
+}
+
+func decryptAccountData(ctx context.Context, account *extsvc.Account) (*accountData, error) {
+	if account.Data == nil {
+		return &accountData{}, nil
+	}
+	return encryption.DecryptJSON[accountData](ctx, account.Data)
+}
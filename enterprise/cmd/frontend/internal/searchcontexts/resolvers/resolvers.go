@@ -146,7 +146,22 @@ func (r *Resolver) UpdateSearchContext(ctx context.Context, args graphqlbackend.
 
 func (r *Resolver) repositoryRevisionsFromInputArgs(ctx context.Context, args []graphqlbackend.SearchContextRepositoryRevisionsInputArgs) ([]*types.SearchContextRepositoryRevisions, error) {
 	repoIDs := make([]api.RepoID, 0, len(args))
+	virtualRepos := map[graphql.ID]*types.VirtualRepo{}
 	for _, repository := range args {
+		if relay.UnmarshalKind(repository.RepositoryID) == graphqlbackend.VirtualRepositoryIDKind {
+			vrID, err := graphqlbackend.UnmarshalVirtualRepositoryID(repository.RepositoryID)
+			if err != nil {
+				return nil, err
+			}
+			vr, err := r.db.VirtualRepos().GetByID(ctx, vrID)
+			if err != nil {
+				return nil, err
+			}
+			virtualRepos[repository.RepositoryID] = vr
+			repoIDs = append(repoIDs, vr.Parent.ID)
+			continue
+		}
+
 		repoID, err := graphqlbackend.UnmarshalRepositoryID(repository.RepositoryID)
 		if err != nil {
 			return nil, err
@@ -160,17 +175,25 @@ func (r *Resolver) repositoryRevisionsFromInputArgs(ctx context.Context, args []
 
 	repositoryRevisions := make([]*types.SearchContextRepositoryRevisions, 0, len(args))
 	for _, repository := range args {
-		repoID, err := graphqlbackend.UnmarshalRepositoryID(repository.RepositoryID)
-		if err != nil {
-			return nil, err
+		vr, isVirtual := virtualRepos[repository.RepositoryID]
+
+		var repoID api.RepoID
+		if isVirtual {
+			repoID = vr.Parent.ID
+		} else {
+			repoID, err = graphqlbackend.UnmarshalRepositoryID(repository.RepositoryID)
+			if err != nil {
+				return nil, err
+			}
 		}
 		repo, ok := idToRepo[repoID]
 		if !ok {
 			return nil, errors.Errorf("cannot find repo with id: %q", repository.RepositoryID)
 		}
 		repositoryRevisions = append(repositoryRevisions, &types.SearchContextRepositoryRevisions{
-			Repo:      types.MinimalRepo{ID: repo.ID, Name: repo.Name},
-			Revisions: repository.Revisions,
+			Repo:        types.MinimalRepo{ID: repo.ID, Name: repo.Name},
+			Revisions:   repository.Revisions,
+			VirtualRepo: vr,
 		})
 	}
 	return repositoryRevisions, nil
@@ -402,9 +425,14 @@ func (r *searchContextResolver) Repositories(ctx context.Context) ([]graphqlback
 		return nil, err
 	}
 
+	client := gitserver.NewClient(r.db)
 	searchContextRepositories := make([]graphqlbackend.SearchContextRepositoryRevisionsResolver, len(repoRevs))
 	for idx, repoRev := range repoRevs {
-		searchContextRepositories[idx] = &searchContextRepositoryRevisionsResolver{graphqlbackend.NewRepositoryResolver(r.db, gitserver.NewClient(r.db), repoRev.Repo.ToRepo()), repoRev.Revisions}
+		repository := graphqlbackend.NewRepositoryResolver(r.db, client, repoRev.Repo.ToRepo())
+		if repoRev.VirtualRepo != nil {
+			repository = graphqlbackend.NewVirtualRepositoryResolver(r.db, client, repoRev.Repo.ToRepo(), repoRev.VirtualRepo)
+		}
+		searchContextRepositories[idx] = &searchContextRepositoryRevisionsResolver{repository, repoRev.Revisions}
 	}
 	return searchContextRepositories, nil
 }
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// client is a minimal JSON-RPC 2.0 client for a language server communicating over the
+// stdin and stdout of a child process. Requests are issued one at a time; requests sent
+// from the server to the client while waiting on a response are answered with a null
+// result, and notifications sent from the server are discarded.
+type client struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	messages chan message
+	readErr  error
+	nextID   int64
+	timeout  time.Duration
+}
+
+// errConnectionClosed occurs when the language server exits or closes its stdout.
+var errConnectionClosed = errors.New("language server closed the connection")
+
+type message struct {
+	ID     *json.RawMessage `json:"id,omitempty"`
+	Method string           `json:"method,omitempty"`
+	Params json.RawMessage  `json:"params,omitempty"`
+	Result json.RawMessage  `json:"result,omitempty"`
+	Error  *responseError   `json:"error,omitempty"`
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// startClient starts the given language server command and returns a client connected
+// to its stdin and stdout. The stderr of the server is forwarded to our own stderr.
+func startClient(command []string, dir string, timeout time.Duration) (*client, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to start language server %q", command[0])
+	}
+
+	c := &client{
+		cmd:      cmd,
+		stdin:    stdin,
+		messages: make(chan message),
+		timeout:  timeout,
+	}
+	go c.readMessages(bufio.NewReader(stdout))
+
+	return c, nil
+}
+
+// readMessages sends each message read from the language server to the messages channel. The
+// channel is closed once the connection closes or an invalid message is read.
+func (c *client) readMessages(reader *bufio.Reader) {
+	defer close(c.messages)
+
+	for {
+		msg, err := readMessage(reader)
+		if err != nil {
+			c.readErr = err
+			return
+		}
+		c.messages <- msg
+	}
+}
+
+// call sends a request and unmarshals the response into result. A nil result
+// discards the response body.
+func (c *client) call(ctx context.Context, method string, params, result any) error {
+	c.nextID++
+	id := c.nextID
+
+	if err := c.write(request{JSONRPC: "2.0", ID: &id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	for {
+		select {
+		case msg, ok := <-c.messages:
+			if !ok {
+				return errors.Wrapf(errConnectionClosed, "%s: %s", method, c.readErr)
+			}
+
+			if msg.Method != "" {
+				// Request or notification from the server
+				if msg.ID != nil {
+					if err := c.write(response{JSONRPC: "2.0", ID: *msg.ID, Result: serverRequestResult(msg)}); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			if msg.ID == nil || string(*msg.ID) != strconv.FormatInt(id, 10) {
+				// Stale response to a request that previously timed out
+				continue
+			}
+			if msg.Error != nil {
+				return errors.Wrap(msg.Error, method)
+			}
+			if result == nil || len(msg.Result) == 0 {
+				return nil
+			}
+			return errors.Wrapf(json.Unmarshal(msg.Result, result), "failed to decode %s result", method)
+
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), method)
+		}
+	}
+}
+
+// notify sends a notification, which has no response.
+func (c *client) notify(method string, params any) error {
+	return c.write(request{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// close asks the language server to shut down and waits for it to exit. The process is
+// killed if it does not exit in time.
+func (c *client) close(ctx context.Context) error {
+	if err := c.call(ctx, "shutdown", nil, nil); err != nil {
+		_ = c.cmd.Process.Kill()
+		_ = c.cmd.Wait()
+		return err
+	}
+	_ = c.notify("exit", nil)
+	_ = c.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- c.cmd.Wait() }()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(c.timeout):
+		_ = c.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+func (c *client) write(v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(c.stdin, "Content-Length: %d\r\n\r\n%s", len(payload), payload); err != nil {
+		return errors.Wrap(err, "failed to write to language server")
+	}
+
+	return nil
+}
+
+// readMessage reads a single header-framed JSON-RPC message.
+func readMessage(reader *bufio.Reader) (message, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return message{}, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return message{}, errors.Wrap(err, "invalid Content-Length header")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return message{}, err
+	}
+
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return message{}, errors.Wrap(err, "invalid message")
+	}
+
+	return msg, nil
+}
+
+// serverRequestResult returns the result sent in response to a request from the server.
+// Servers commonly block on workspace/configuration, which expects one (here empty)
+// value per requested item; all other requests are acknowledged with a null result.
+func serverRequestResult(msg message) any {
+	if msg.Method != "workspace/configuration" {
+		return nil
+	}
+
+	var params struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	_ = json.Unmarshal(msg.Params, &params)
+
+	return make([]any, len(params.Items))
+}
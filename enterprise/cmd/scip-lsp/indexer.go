@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// indexer builds a SCIP index of a project by querying a language server for the definitions,
+// hover text, and references of each symbol defined in the project.
+type indexer struct {
+	client     *client
+	root       string
+	languageID string
+	extensions []string
+	namer      *symbolNamer
+	documents  map[string]*document
+}
+
+// document is a SCIP document under construction.
+type document struct {
+	*scip.Document
+	occurrences map[occurrenceKey]struct{}
+	symbols     map[string]struct{}
+}
+
+type occurrenceKey struct {
+	symbol string
+	rng    lsp.Range
+	roles  int32
+}
+
+// definition is a symbol defined in a document, along with the range of its name.
+type definition struct {
+	symbol string
+	rng    lsp.Range
+}
+
+func newIndexer(client *client, root, languageID string, extensions []string) *indexer {
+	return &indexer{
+		client:     client,
+		root:       root,
+		languageID: languageID,
+		extensions: extensions,
+		namer:      newSymbolNamer(),
+		documents:  map[string]*document{},
+	}
+}
+
+// initialize performs the initialization handshake with the language server.
+func (i *indexer) initialize(ctx context.Context) error {
+	var capabilities lsp.ClientCapabilities
+	capabilities.TextDocument.DocumentSymbol.HierarchicalDocumentSymbolSupport = true
+	capabilities.TextDocument.Hover = &struct {
+		ContentFormat []string `json:"contentFormat,omitempty"`
+	}{ContentFormat: []string{"markdown", "plaintext"}}
+	capabilities.Workspace.Configuration = true
+
+	params := lsp.InitializeParams{
+		ProcessID:    os.Getpid(),
+		RootURI:      i.uri("."),
+		ClientInfo:   lsp.ClientInfo{Name: symbolScheme},
+		Capabilities: capabilities,
+	}
+	if err := i.client.call(ctx, "initialize", params, nil); err != nil {
+		return err
+	}
+
+	return i.client.notify("initialized", struct{}{})
+}
+
+// index queries the language server for every matching file under the project root and returns
+// the resulting SCIP index. Failed requests for a single symbol or file are logged and skipped.
+func (i *indexer) index(ctx context.Context) (*scip.Index, error) {
+	paths, err := i.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if err := i.indexFile(ctx, path); err != nil {
+			if errors.Is(err, errConnectionClosed) || ctx.Err() != nil {
+				return nil, err
+			}
+			fmt.Fprintf(os.Stderr, "warning: failed to index %s: %s\n", path, err)
+		}
+	}
+
+	index := &scip.Index{
+		Documents: make([]*scip.Document, 0, len(paths)),
+	}
+	for _, path := range paths {
+		index.Documents = append(index.Documents, i.document(path).finalize())
+	}
+
+	return index, nil
+}
+
+// paths returns the sorted, slash-separated paths of the files under the project root that
+// have one of the configured extensions.
+func (i *indexer) paths() ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(i.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.Type().IsRegular() && i.matches(path) {
+			relativePath, err := filepath.Rel(i.root, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, filepath.ToSlash(relativePath))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (i *indexer) matches(path string) bool {
+	if len(i.extensions) == 0 {
+		return true
+	}
+
+	for _, extension := range i.extensions {
+		if strings.HasSuffix(path, extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexFile records the definitions in the given file, as well as the hover text and references
+// of each of these definitions.
+func (i *indexer) indexFile(ctx context.Context, path string) error {
+	contents, err := os.ReadFile(filepath.Join(i.root, filepath.FromSlash(path)))
+	if err != nil {
+		return err
+	}
+	uri := i.uri(path)
+
+	if err := i.client.notify("textDocument/didOpen", lsp.DidOpenTextDocumentParams{
+		TextDocument: lsp.TextDocumentItem{URI: uri, LanguageID: i.languageID, Version: 1, Text: string(contents)},
+	}); err != nil {
+		return err
+	}
+	defer func() {
+		_ = i.client.notify("textDocument/didClose", lsp.DidCloseTextDocumentParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+		})
+	}()
+
+	var symbols []documentSymbol
+	if err := i.client.call(ctx, "textDocument/documentSymbol", lsp.DocumentSymbolParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+	}, &symbols); err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	definitions := i.definitions(path, lines, symbols)
+
+	for _, definition := range definitions {
+		i.document(path).addOccurrence(definition.symbol, definition.rng, int32(scip.SymbolRole_Definition))
+
+		position := lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Position:     definition.rng.Start,
+		}
+
+		var hover json.RawMessage
+		if err := i.client.call(ctx, "textDocument/hover", position, &hover); err != nil {
+			if errors.Is(err, errConnectionClosed) {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "warning: failed to get hover text of %s: %s\n", definition.symbol, err)
+		}
+		documentation, err := decodeHover(hover)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to decode hover text of %s: %s\n", definition.symbol, err)
+		}
+		i.document(path).addSymbol(definition.symbol, documentation)
+
+		var locations []lsp.Location
+		if err := i.client.call(ctx, "textDocument/references", lsp.ReferenceParams{
+			TextDocumentPositionParams: position,
+			Context:                    lsp.ReferenceContext{IncludeDeclaration: false},
+		}, &locations); err != nil {
+			if errors.Is(err, errConnectionClosed) {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "warning: failed to get references of %s: %s\n", definition.symbol, err)
+		}
+		for _, location := range locations {
+			if referencePath, ok := i.relativePath(location.URI); ok {
+				i.document(referencePath).addOccurrence(definition.symbol, location.Range, 0)
+			}
+		}
+	}
+
+	return nil
+}
+
+// definitions assigns symbols to each of the given document symbols of the file with the given path.
+func (i *indexer) definitions(path string, lines []string, symbols []documentSymbol) []definition {
+	var definitions []definition
+
+	var visit func(parent string, symbols []documentSymbol)
+	visit = func(parent string, symbols []documentSymbol) {
+		for _, symbol := range symbols {
+			name := symbol.Name
+			if name == "" {
+				continue
+			}
+			s := i.namer.childSymbol(parent, name, symbol.Kind)
+
+			rng := symbol.Range
+			if symbol.SelectionRange != nil {
+				rng = *symbol.SelectionRange
+			} else {
+				rng = findName(lines, rng, name)
+			}
+
+			definitions = append(definitions, definition{symbol: s, rng: rng})
+			visit(s, symbol.Children)
+		}
+	}
+
+	if len(symbols) > 0 && symbols[0].Location != nil {
+		// The flat SymbolInformation form: nest each symbol under the most recent preceding
+		// symbol with a matching name, if any, to approximate the hierarchical form.
+		uri := i.uri(path)
+		containers := map[string]string{}
+
+		for _, symbol := range symbols {
+			if symbol.Location == nil || symbol.Location.URI != uri {
+				continue
+			}
+
+			parent, ok := containers[symbol.ContainerName]
+			if !ok {
+				parent = i.namer.documentSymbol(path)
+			}
+
+			symbol.Range = symbol.Location.Range
+			numDefinitions := len(definitions)
+			visit(parent, []documentSymbol{symbol})
+			if len(definitions) > numDefinitions {
+				containers[symbol.Name] = definitions[numDefinitions].symbol
+			}
+		}
+
+		return definitions
+	}
+
+	visit(i.namer.documentSymbol(path), symbols)
+	return definitions
+}
+
+// uri returns the file URI of the given slash-separated path relative to the project root.
+func (i *indexer) uri(path string) lsp.DocumentURI {
+	return lsp.DocumentURI((&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(i.root, filepath.FromSlash(path)))}).String())
+}
+
+// relativePath returns the slash-separated path relative to the project root of the file with
+// the given URI. If the file is outside of the project root, a false-valued flag is returned.
+func (i *indexer) relativePath(uri lsp.DocumentURI) (string, bool) {
+	u, err := url.Parse(string(uri))
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+
+	relativePath, err := filepath.Rel(i.root, filepath.FromSlash(u.Path))
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	relativePath = filepath.ToSlash(relativePath)
+	if !i.matches(relativePath) {
+		return "", false
+	}
+
+	return relativePath, true
+}
+
+func (i *indexer) document(path string) *document {
+	if d, ok := i.documents[path]; ok {
+		return d
+	}
+
+	d := &document{
+		Document: &scip.Document{
+			Language:     i.languageID,
+			RelativePath: path,
+		},
+		occurrences: map[occurrenceKey]struct{}{},
+		symbols:     map[string]struct{}{},
+	}
+	i.documents[path] = d
+
+	return d
+}
+
+func (d *document) addOccurrence(symbol string, r lsp.Range, roles int32) {
+	key := occurrenceKey{symbol: symbol, rng: r, roles: roles}
+	if _, ok := d.occurrences[key]; ok {
+		return
+	}
+	d.occurrences[key] = struct{}{}
+
+	d.Occurrences = append(d.Occurrences, &scip.Occurrence{
+		Range: scip.Range{
+			Start: scip.Position{Line: int32(r.Start.Line), Character: int32(r.Start.Character)},
+			End:   scip.Position{Line: int32(r.End.Line), Character: int32(r.End.Character)},
+		}.SCIPRange(),
+		Symbol:      symbol,
+		SymbolRoles: roles,
+	})
+}
+
+func (d *document) addSymbol(symbol string, documentation []string) {
+	if _, ok := d.symbols[symbol]; ok {
+		return
+	}
+	d.symbols[symbol] = struct{}{}
+
+	d.Symbols = append(d.Symbols, &scip.SymbolInformation{
+		Symbol:        symbol,
+		Documentation: documentation,
+	})
+}
+
+// finalize sorts the occurrences of the document by start position.
+func (d *document) finalize() *scip.Document {
+	sort.SliceStable(d.Occurrences, func(i, j int) bool {
+		ri, rj := d.Occurrences[i].Range, d.Occurrences[j].Range
+		return ri[0] < rj[0] || (ri[0] == rj[0] && ri[1] < rj[1])
+	})
+
+	return d.Document
+}
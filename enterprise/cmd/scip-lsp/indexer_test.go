@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+)
+
+const fakeServerEnv = "SCIP_LSP_FAKE_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeServerEnv) != "" {
+		runFakeServer()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	for path, contents := range map[string]string{
+		"lib.fake":  "class Greeter {\n  greet() {}\n}\n",
+		"main.fake": "func main() {\n  g.greet()\n}\n",
+		"README.md": "# fake\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, path), []byte(contents), 0o644); err != nil {
+			t.Fatalf("failed to write file: %s", err)
+		}
+	}
+
+	t.Setenv(fakeServerEnv, "1")
+	client, err := startClient([]string{os.Args[0]}, root, 10*time.Second)
+	if err != nil {
+		t.Fatalf("failed to start fake language server: %s", err)
+	}
+
+	ctx := context.Background()
+	indexer := newIndexer(client, root, "fake", []string{".fake"})
+	if err := indexer.initialize(ctx); err != nil {
+		t.Fatalf("failed to initialize: %s", err)
+	}
+	index, err := indexer.index(ctx)
+	if err != nil {
+		t.Fatalf("failed to index: %s", err)
+	}
+	if err := client.close(ctx); err != nil {
+		t.Fatalf("failed to shut down: %s", err)
+	}
+
+	greeter := "scip-lsp . . . `lib.fake`/Greeter#"
+	greet := "scip-lsp . . . `lib.fake`/Greeter#greet()."
+	mainFunc := "scip-lsp . . . `main.fake`/main()."
+
+	expected := map[string][]string{
+		"lib.fake": {
+			"[0 6 13] " + greeter + " definition",
+			"[1 2 7] " + greet + " definition",
+		},
+		"main.fake": {
+			"[0 5 9] " + mainFunc + " definition",
+			"[1 4 9] " + greet + " reference",
+		},
+	}
+	if diff := cmp.Diff(expected, summarizeIndex(index)); diff != "" {
+		t.Errorf("unexpected occurrences (-want +got):\n%s", diff)
+	}
+
+	for _, document := range index.Documents {
+		for _, symbol := range document.Symbols {
+			if diff := cmp.Diff([]string{"docs for " + symbol.Symbol}, symbol.Documentation); diff != "" {
+				t.Errorf("unexpected documentation (-want +got):\n%s", diff)
+			}
+		}
+	}
+}
+
+func summarizeIndex(index *scip.Index) map[string][]string {
+	summary := map[string][]string{}
+	for _, document := range index.Documents {
+		for _, occurrence := range document.Occurrences {
+			role := "reference"
+			if occurrence.SymbolRoles&int32(scip.SymbolRole_Definition) != 0 {
+				role = "definition"
+			}
+
+			summary[document.RelativePath] = append(summary[document.RelativePath], fmt.Sprintf("%v %s %s", occurrence.Range, occurrence.Symbol, role))
+		}
+	}
+
+	return summary
+}
+
+// runFakeServer serves a fixed set of responses for the files written by TestIndex. The
+// documentSymbol response of lib.fake uses the hierarchical form, and that of main.fake uses
+// the flat form.
+func runFakeServer() {
+	reader := bufio.NewReader(os.Stdin)
+	write := func(v any) {
+		payload, _ := json.Marshal(v)
+		fmt.Fprintf(os.Stdout, "Content-Length: %d\r\n\r\n%s", len(payload), payload)
+	}
+	respond := func(msg message, result any) {
+		write(map[string]any{"jsonrpc": "2.0", "id": msg.ID, "result": result})
+	}
+	rng := func(startLine, startCharacter, endLine, endCharacter int) map[string]any {
+		return map[string]any{
+			"start": map[string]int{"line": startLine, "character": startCharacter},
+			"end":   map[string]int{"line": endLine, "character": endCharacter},
+		}
+	}
+
+	for {
+		msg, err := readMessage(reader)
+		if err != nil {
+			return
+		}
+
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			Position struct {
+				Line int `json:"line"`
+			} `json:"position"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		uri := params.TextDocument.URI
+
+		switch msg.Method {
+		case "initialize":
+			// Block on a request to the client, as many servers do
+			write(map[string]any{"jsonrpc": "2.0", "id": "configuration", "method": "workspace/configuration", "params": map[string]any{"items": []any{map[string]any{}}}})
+			if response, err := readMessage(reader); err != nil || string(response.Result) != "[null]" {
+				return
+			}
+			respond(msg, map[string]any{"capabilities": map[string]any{}})
+
+		case "textDocument/documentSymbol":
+			switch {
+			case strings.HasSuffix(uri, "/lib.fake"):
+				respond(msg, []any{map[string]any{
+					"name": "Greeter", "kind": 5, "range": rng(0, 0, 2, 1), "selectionRange": rng(0, 6, 0, 13),
+					"children": []any{map[string]any{
+						"name": "greet", "kind": 6, "range": rng(1, 2, 1, 12), "selectionRange": rng(1, 2, 1, 7),
+					}},
+				}})
+			case strings.HasSuffix(uri, "/main.fake"):
+				respond(msg, []any{map[string]any{
+					"name": "main", "kind": 12, "location": map[string]any{"uri": uri, "range": rng(0, 0, 2, 1)},
+				}})
+			default:
+				respond(msg, nil)
+			}
+
+		case "textDocument/hover":
+			symbols := map[string]string{
+				"lib.fake:0":  "scip-lsp . . . `lib.fake`/Greeter#",
+				"lib.fake:1":  "scip-lsp . . . `lib.fake`/Greeter#greet().",
+				"main.fake:0": "scip-lsp . . . `main.fake`/main().",
+			}
+			key := fmt.Sprintf("%s:%d", filepath.Base(uri), params.Position.Line)
+			respond(msg, map[string]any{"contents": map[string]any{"kind": "markdown", "value": "docs for " + symbols[key]}})
+
+		case "textDocument/references":
+			if strings.HasSuffix(uri, "/lib.fake") && params.Position.Line == 1 {
+				mainURI := strings.TrimSuffix(uri, "lib.fake") + "main.fake"
+				respond(msg, []any{
+					map[string]any{"uri": mainURI, "range": rng(1, 4, 1, 9)},
+					map[string]any{"uri": "file:///outside/root.fake", "range": rng(0, 0, 0, 5)},
+				})
+			} else {
+				respond(msg, []any{})
+			}
+
+		case "shutdown":
+			respond(msg, nil)
+
+		case "exit":
+			return
+
+		default:
+			if msg.ID != nil {
+				respond(msg, nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Command scip-lsp builds a SCIP index for a project by driving a Language Server Protocol server
+// over stdin and stdout. It records the definitions, hover text, and references reported by the
+// server for each matching file under the project root. This provides precise code intelligence
+// for languages that have a language server but no dedicated SCIP indexer.
+//
+// Usage:
+//
+//	scip-lsp -language-id <id> [-extensions .ext,...] [-root dir] [-output index.scip] -- <server command...>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sourcegraph/sourcegraph/internal/version"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var (
+	root           = flag.String("root", ".", "The root of the project to index.")
+	output         = flag.String("output", "index.scip", "The path of the SCIP index to write.")
+	languageID     = flag.String("language-id", "", "The LSP language identifier of the indexed files (e.g. elixir).")
+	extensions     = flag.String("extensions", "", "A comma-separated list of extensions of the files to index (e.g. .ex,.exs). All files are indexed if empty.")
+	requestTimeout = flag.Duration("request-timeout", time.Minute, "The maximum time to wait for a single response from the language server.")
+)
+
+func main() {
+	if err := doMain(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func doMain() error {
+	flag.Parse()
+	ctx := context.Background()
+
+	command := flag.Args()
+	if len(command) == 0 {
+		return errors.New("no language server command supplied")
+	}
+	if *languageID == "" {
+		return errors.New("no language identifier supplied")
+	}
+
+	projectRoot, err := filepath.Abs(*root)
+	if err != nil {
+		return err
+	}
+
+	client, err := startClient(command, projectRoot, *requestTimeout)
+	if err != nil {
+		return err
+	}
+
+	indexer := newIndexer(client, projectRoot, *languageID, splitExtensions(*extensions))
+	if err := indexer.initialize(ctx); err != nil {
+		_ = client.close(ctx)
+		return errors.Wrap(err, "failed to initialize language server")
+	}
+
+	index, err := indexer.index(ctx)
+	if closeErr := client.close(ctx); closeErr != nil && err == nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to shut down language server: %s\n", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	index.Metadata = &scip.Metadata{
+		ToolInfo: &scip.ToolInfo{
+			Name:      symbolScheme,
+			Version:   version.Version(),
+			Arguments: os.Args[1:],
+		},
+		ProjectRoot:          string(indexer.uri(".")),
+		TextDocumentEncoding: scip.TextEncoding_UTF8,
+	}
+
+	payload, err := proto.Marshal(index)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal index")
+	}
+	if err := os.WriteFile(*output, payload, 0o644); err != nil {
+		return errors.Wrap(err, "failed to write index")
+	}
+
+	return nil
+}
+
+// splitExtensions returns the non-empty extensions of the given comma-separated list, each
+// beginning with a leading dot.
+func splitExtensions(value string) []string {
+	var extensions []string
+	for _, extension := range strings.Split(value, ",") {
+		if extension = strings.TrimSpace(extension); extension != "" {
+			if !strings.HasPrefix(extension, ".") {
+				extension = "." + extension
+			}
+			extensions = append(extensions, extension)
+		}
+	}
+
+	return extensions
+}
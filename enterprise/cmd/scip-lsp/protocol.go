@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+// documentSymbol is a definition reported by textDocument/documentSymbol. The go-lsp package only
+// models the flat SymbolInformation response, so this type decodes both that form and the newer
+// hierarchical DocumentSymbol form.
+type documentSymbol struct {
+	Name           string           `json:"name"`
+	Kind           lsp.SymbolKind   `json:"kind"`
+	Range          lsp.Range        `json:"range"`
+	SelectionRange *lsp.Range       `json:"selectionRange"`
+	Children       []documentSymbol `json:"children"`
+	Location       *lsp.Location    `json:"location"`
+	ContainerName  string           `json:"containerName"`
+}
+
+// decodeHover returns the contents of the given textDocument/hover result as a list of markdown
+// strings. Hover contents may be a MarkupContent value, a MarkedString, or a list of MarkedStrings.
+func decodeHover(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	var hover struct {
+		Contents json.RawMessage `json:"contents"`
+	}
+	if err := json.Unmarshal(raw, &hover); err != nil {
+		return nil, err
+	}
+
+	var contents []lsp.MarkedString
+	if trimmed := bytes.TrimSpace(hover.Contents); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &contents); err != nil {
+			return nil, err
+		}
+	} else if len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		// MarkupContent values decode as a MarkedString without a language
+		var content lsp.MarkedString
+		if err := json.Unmarshal(trimmed, &content); err != nil {
+			return nil, err
+		}
+		contents = append(contents, content)
+	}
+
+	documentation := make([]string, 0, len(contents))
+	for _, content := range contents {
+		value := strings.TrimSpace(content.Value)
+		if value == "" {
+			continue
+		}
+
+		if content.Language != "" {
+			value = fmt.Sprintf("```%s\n%s\n```", content.Language, value)
+		}
+		documentation = append(documentation, value)
+	}
+
+	return documentation, nil
+}
+
+// findName returns the range of the first occurrence of the given name within the given range of
+// the given lines. If the name does not occur in the range, the start of the range is returned.
+// Character offsets of LSP positions are counted in UTF-16 code units.
+func findName(lines []string, r lsp.Range, name string) lsp.Range {
+	nameLength := len(utf16.Encode([]rune(name)))
+
+	for line := r.Start.Line; line <= r.End.Line && line < len(lines); line++ {
+		text := utf16.Encode([]rune(lines[line]))
+
+		start := 0
+		if line == r.Start.Line {
+			start = r.Start.Character
+		}
+		end := len(text)
+		if line == r.End.Line && r.End.Character < end {
+			end = r.End.Character
+		}
+		if start > end {
+			continue
+		}
+
+		segment := string(utf16.Decode(text[start:end]))
+		if offset := strings.Index(segment, name); offset >= 0 {
+			character := start + len(utf16.Encode([]rune(segment[:offset])))
+
+			return lsp.Range{
+				Start: lsp.Position{Line: line, Character: character},
+				End:   lsp.Position{Line: line, Character: character + nameLength},
+			}
+		}
+	}
+
+	return lsp.Range{
+		Start: r.Start,
+		End:   lsp.Position{Line: r.Start.Line, Character: r.Start.Character + nameLength},
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-lsp"
+)
+
+func TestDecodeHover(t *testing.T) {
+	testCases := map[string][]string{
+		`null`: nil,
+		`{"contents": {"kind": "markdown", "value": "**docs**"}}`:          {"**docs**"},
+		`{"contents": "plain docs"}`:                                       {"plain docs"},
+		`{"contents": {"language": "elixir", "value": "def hello(name)"}}`: {"```elixir\ndef hello(name)\n```"},
+		`{"contents": [{"language": "elixir", "value": "def hello(name)"}, "docs", ""]}`: {
+			"```elixir\ndef hello(name)\n```",
+			"docs",
+		},
+	}
+
+	for raw, expected := range testCases {
+		documentation, err := decodeHover(json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("unexpected error decoding %s: %s", raw, err)
+		}
+		if len(expected) == 0 && len(documentation) == 0 {
+			continue
+		}
+		if diff := cmp.Diff(expected, documentation); diff != "" {
+			t.Errorf("unexpected documentation for %s (-want +got):\n%s", raw, diff)
+		}
+	}
+}
+
+func TestDecodeDocumentSymbols(t *testing.T) {
+	var hierarchical []documentSymbol
+	if err := json.Unmarshal([]byte(`[{
+		"name": "Greeter",
+		"kind": 2,
+		"range": {"start": {"line": 0, "character": 0}, "end": {"line": 4, "character": 3}},
+		"selectionRange": {"start": {"line": 0, "character": 10}, "end": {"line": 0, "character": 17}},
+		"children": [{
+			"name": "hello",
+			"kind": 12,
+			"range": {"start": {"line": 1, "character": 2}, "end": {"line": 3, "character": 5}},
+			"selectionRange": {"start": {"line": 1, "character": 6}, "end": {"line": 1, "character": 11}}
+		}]
+	}]`), &hierarchical); err != nil {
+		t.Fatalf("unexpected error decoding symbols: %s", err)
+	}
+	if len(hierarchical) != 1 || hierarchical[0].SelectionRange == nil || len(hierarchical[0].Children) != 1 || hierarchical[0].Location != nil {
+		t.Errorf("unexpected hierarchical symbols: %+v", hierarchical)
+	}
+
+	var flat []documentSymbol
+	if err := json.Unmarshal([]byte(`[{
+		"name": "hello",
+		"kind": 12,
+		"location": {"uri": "file:///repo/greeter.ex", "range": {"start": {"line": 1, "character": 2}, "end": {"line": 3, "character": 5}}},
+		"containerName": "Greeter"
+	}]`), &flat); err != nil {
+		t.Fatalf("unexpected error decoding symbols: %s", err)
+	}
+	if len(flat) != 1 || flat[0].Location == nil || flat[0].ContainerName != "Greeter" {
+		t.Errorf("unexpected flat symbols: %+v", flat)
+	}
+}
+
+func TestFindName(t *testing.T) {
+	lines := []string{
+		"defmodule Greeter do",
+		"  def héllo(name), do: \"😀 #{name}\"",
+		"  def name, do: nil",
+	}
+
+	testCases := []struct {
+		name     string
+		rng      lsp.Range
+		expected lsp.Range
+	}{
+		{"Greeter", rangeOf(0, 0, 2, 19), rangeOf(0, 10, 0, 17)},
+		{"héllo", rangeOf(1, 2, 1, 36), rangeOf(1, 6, 1, 11)},
+		// The emoji is two UTF-16 code units wide
+		{"name", rangeOf(1, 24, 2, 19), rangeOf(1, 29, 1, 33)},
+		{"name", rangeOf(2, 2, 2, 19), rangeOf(2, 6, 2, 10)},
+		{"missing", rangeOf(2, 2, 2, 19), rangeOf(2, 2, 2, 9)},
+	}
+
+	for _, testCase := range testCases {
+		if diff := cmp.Diff(testCase.expected, findName(lines, testCase.rng, testCase.name)); diff != "" {
+			t.Errorf("unexpected range for %q (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
+
+func rangeOf(startLine, startCharacter, endLine, endCharacter int) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: startLine, Character: startCharacter},
+		End:   lsp.Position{Line: endLine, Character: endCharacter},
+	}
+}
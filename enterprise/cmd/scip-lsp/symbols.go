@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/go-lsp"
+)
+
+// symbolScheme is the scheme of every global symbol emitted by this indexer. Language servers do
+// not expose package information, so the package fields of each symbol are left empty and symbols
+// are instead qualified by the path of the document that defines them.
+const symbolScheme = "scip-lsp"
+
+// symbolNamer assigns unique SCIP symbols to the definitions reported by a language server.
+type symbolNamer struct {
+	seen map[string]int
+}
+
+func newSymbolNamer() *symbolNamer {
+	return &symbolNamer{seen: map[string]int{}}
+}
+
+// documentSymbol returns the SCIP symbol prefix shared by all definitions in the document with
+// the given repository-relative path. Each path segment becomes a namespace descriptor.
+func (n *symbolNamer) documentSymbol(relativePath string) string {
+	var b strings.Builder
+	b.WriteString(symbolScheme)
+	b.WriteString(" . . . ")
+
+	for _, segment := range strings.Split(relativePath, "/") {
+		b.WriteString(escapeName(segment))
+		b.WriteByte('/')
+	}
+
+	return b.String()
+}
+
+// childSymbol returns the SCIP symbol for a definition with the given name and kind nested within
+// the given parent symbol. Overloaded methods are distinguished by a disambiguator; any other
+// duplicate definitions within the same parent share a symbol.
+func (n *symbolNamer) childSymbol(parent, name string, kind lsp.SymbolKind) string {
+	name = escapeName(name)
+
+	switch kind {
+	case lsp.SKFile, lsp.SKModule, lsp.SKNamespace, lsp.SKPackage:
+		return parent + name + "/"
+
+	case lsp.SKClass, lsp.SKInterface, lsp.SKEnum, lsp.SKStruct:
+		return parent + name + "#"
+
+	case lsp.SKTypeParameter:
+		return parent + "[" + name + "]"
+
+	case lsp.SKMethod, lsp.SKFunction, lsp.SKConstructor, lsp.SKOperator:
+		prefix := parent + name
+		count := n.seen[prefix]
+		n.seen[prefix]++
+
+		if count == 0 {
+			return prefix + "()."
+		}
+		return fmt.Sprintf("%s(+%d).", prefix, count)
+
+	default:
+		return parent + name + "."
+	}
+}
+
+// escapeName returns the given name as a SCIP descriptor name, wrapping it in backticks if it
+// contains characters that cannot appear in a simple identifier.
+func escapeName(name string) string {
+	if name != "" && strings.IndexFunc(name, func(r rune) bool { return !isIdentifierCharacter(r) }) < 0 {
+		return name
+	}
+
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func isIdentifierCharacter(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '+' || r == '$' || r == '_'
+}
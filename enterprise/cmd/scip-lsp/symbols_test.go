@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+)
+
+func TestChildSymbol(t *testing.T) {
+	namer := newSymbolNamer()
+	document := namer.documentSymbol("lib/greeter.ex")
+	module := namer.childSymbol(document, "Greeter", lsp.SKModule)
+	class := namer.childSymbol(module, "Person", lsp.SKClass)
+
+	testCases := []struct {
+		parent   string
+		name     string
+		kind     lsp.SymbolKind
+		expected string
+	}{
+		{document, "Greeter", lsp.SKModule, "scip-lsp . . . lib/`greeter.ex`/Greeter/"},
+		{module, "Person", lsp.SKClass, "scip-lsp . . . lib/`greeter.ex`/Greeter/Person#"},
+		{class, "name", lsp.SKField, "scip-lsp . . . lib/`greeter.ex`/Greeter/Person#name."},
+		{class, "T", lsp.SKTypeParameter, "scip-lsp . . . lib/`greeter.ex`/Greeter/Person#[T]"},
+		{module, "hello", lsp.SKFunction, "scip-lsp . . . lib/`greeter.ex`/Greeter/hello()."},
+		{module, "hello", lsp.SKFunction, "scip-lsp . . . lib/`greeter.ex`/Greeter/hello(+1)."},
+		{module, "hello/2", lsp.SKFunction, "scip-lsp . . . lib/`greeter.ex`/Greeter/`hello/2`()."},
+		{module, "with `ticks`", lsp.SKConstant, "scip-lsp . . . lib/`greeter.ex`/Greeter/`with ``ticks```."},
+	}
+
+	for _, testCase := range testCases {
+		symbol := namer.childSymbol(testCase.parent, testCase.name, testCase.kind)
+		if symbol != testCase.expected {
+			t.Errorf("unexpected symbol for %q. want=%q have=%q", testCase.name, testCase.expected, symbol)
+		}
+
+		if _, err := scip.ParseSymbol(symbol); err != nil {
+			t.Errorf("invalid symbol %q: %s", symbol, err)
+		}
+	}
+}
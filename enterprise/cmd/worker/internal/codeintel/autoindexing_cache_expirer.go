@@ -0,0 +1,68 @@
+package codeintel
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	autoindexinghttp "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/transport/http"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/lsifuploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type autoindexingCacheExpirer struct{}
+
+func NewAutoindexingCacheExpirerJob() job.Job {
+	return &autoindexingCacheExpirer{}
+}
+
+func (j *autoindexingCacheExpirer) Description() string {
+	return "Evicts stale auto-indexing dependency caches from the precise code intel upload bucket."
+}
+
+func (j *autoindexingCacheExpirer) Config() []env.Config {
+	return []env.Config{
+		autoindexingCacheExpirerConfigInst,
+	}
+}
+
+func (j *autoindexingCacheExpirer) Routines(startupCtx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	uploadStore, err := initLSIFUploadStore(autoindexingCacheExpirerConfigInst.LSIFUploadStoreConfig)
+	if err != nil {
+		logger.Fatal("Failed to create upload store", log.Error(err))
+	}
+
+	return []goroutine.BackgroundRoutine{
+		uploadstore.NewExpirer(context.Background(), uploadStore, autoindexinghttp.CachePrefix, autoindexingCacheExpirerConfigInst.maxAge, autoindexingCacheExpirerConfigInst.interval),
+	}, nil
+}
+
+type autoindexingCacheExpirerConfig struct {
+	env.BaseConfig
+
+	maxAge                time.Duration
+	interval              time.Duration
+	LSIFUploadStoreConfig *lsifuploadstore.Config
+}
+
+var autoindexingCacheExpirerConfigInst = &autoindexingCacheExpirerConfig{}
+
+func (c *autoindexingCacheExpirerConfig) Load() {
+	c.LSIFUploadStoreConfig = &lsifuploadstore.Config{}
+	c.LSIFUploadStoreConfig.Load()
+
+	c.maxAge = c.GetInterval("CODEINTEL_AUTOINDEXING_CACHE_MAX_AGE", "72h", "The max age of auto-indexing dependency caches before they are evicted.")
+	c.interval = c.GetInterval("CODEINTEL_AUTOINDEXING_CACHE_EXPIRER_INTERVAL", "1h", "The frequency at which to evict stale auto-indexing dependency caches.")
+}
+
+func (c *autoindexingCacheExpirerConfig) Validate() error {
+	var errs error
+	errs = errors.Append(errs, c.BaseConfig.Validate())
+	errs = errors.Append(errs, c.LSIFUploadStoreConfig.Validate())
+	return errs
+}
@@ -0,0 +1,25 @@
+package codeintel
+
+import (
+	"context"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/lsifuploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/memo"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+)
+
+// initLSIFUploadStore returns the precise code intel upload store shared by the jobs of this package.
+// The upload store registers its metrics when created, so it can be created only once per process.
+// The configuration of the first caller is used.
+func initLSIFUploadStore(config *lsifuploadstore.Config) (uploadstore.Store, error) {
+	return initLSIFUploadStoreMemo.Init(config)
+}
+
+var initLSIFUploadStoreMemo = memo.NewMemoizedConstructorWithArg(func(config *lsifuploadstore.Config) (uploadstore.Store, error) {
+	logger := log.Scoped("lsifuploadstore", "precise code intel upload store")
+
+	return lsifuploadstore.New(context.Background(), config, observation.ContextWithLogger(logger))
+})
@@ -10,7 +10,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/lsifuploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
-	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -32,7 +31,7 @@ func (j *lsifuploadstoreExpirer) Config() []env.Config {
 }
 
 func (j *lsifuploadstoreExpirer) Routines(startupCtx context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
-	uploadStore, err := lsifuploadstore.New(context.Background(), lsifuploadstoreExpirerConfigInst.LSIFUploadStoreConfig, observation.ContextWithLogger(logger))
+	uploadStore, err := initLSIFUploadStore(lsifuploadstoreExpirerConfigInst.LSIFUploadStoreConfig)
 	if err != nil {
 		logger.Fatal("Failed to create upload store", log.Error(err))
 	}
@@ -3,6 +3,7 @@ package permissions
 import (
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/bulkimportstore"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -35,3 +36,37 @@ func (c *config) Validate() error {
 
 	return errs
 }
+
+type bulkImportConfig struct {
+	env.BaseConfig
+
+	WorkerPollInterval  time.Duration
+	WorkerConcurrency   int
+	WorkerRetryInterval time.Duration
+	UploadStoreConfig   *bulkimportstore.Config
+}
+
+var BulkImportConfigInst = &bulkImportConfig{}
+
+func (c *bulkImportConfig) Load() {
+	c.UploadStoreConfig = &bulkimportstore.Config{}
+	c.UploadStoreConfig.Load()
+
+	c.WorkerPollInterval = c.GetInterval("EXPLICIT_PERMISSIONS_BULK_IMPORT_WORKER_POLL_INTERVAL", "1s", "How frequently to query the job queue")
+	c.WorkerConcurrency = c.GetInt("EXPLICIT_PERMISSIONS_BULK_IMPORT_WORKER_CONCURRENCY", "1", "The maximum number of bulk imports that can be processed concurrently")
+	c.WorkerRetryInterval = c.GetInterval("EXPLICIT_PERMISSIONS_BULK_IMPORT_WORKER_RETRY_INTERVAL", "30s", "The minimum number of time to wait before retrying a failed job")
+}
+
+func (c *bulkImportConfig) Validate() error {
+	var errs error
+	errs = errors.Append(errs, c.BaseConfig.Validate())
+	errs = errors.Append(errs, c.UploadStoreConfig.Validate())
+	if c.WorkerPollInterval < 0 {
+		errs = errors.Append(errs, errors.New("EXPLICIT_PERMISSIONS_BULK_IMPORT_WORKER_POLL_INTERVAL must be greater than or equal to 0"))
+	}
+	if c.WorkerConcurrency < 1 {
+		errs = errors.Append(errs, errors.New("EXPLICIT_PERMISSIONS_BULK_IMPORT_WORKER_CONCURRENCY must be greater than 0"))
+	}
+
+	return errs
+}
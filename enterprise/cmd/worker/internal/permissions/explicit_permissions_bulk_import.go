@@ -0,0 +1,528 @@
+package permissions
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/authz/bulkimportstore"
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// explicitPermissionsBulkImportJob implements the job.Job interface. It is used by the worker service
+// to spawn a new background worker.
+type explicitPermissionsBulkImportJob struct{}
+
+// NewExplicitPermissionsBulkImportJob creates a new job for applying explicit permissions
+// uploaded as a CSV or JSONL document.
+func NewExplicitPermissionsBulkImportJob() job.Job {
+	return &explicitPermissionsBulkImportJob{}
+}
+
+func (j *explicitPermissionsBulkImportJob) Description() string {
+	return "Applies explicit repository permissions uploaded in bulk."
+}
+
+func (j *explicitPermissionsBulkImportJob) Config() []env.Config {
+	return []env.Config{BulkImportConfigInst}
+}
+
+// Routines is called by the worker service to start the worker.
+// It returns a list of goroutines that the worker service should start and manage.
+func (j *explicitPermissionsBulkImportJob) Routines(_ context.Context, logger log.Logger) ([]goroutine.BackgroundRoutine, error) {
+	wdb, err := workerdb.InitDBWithLogger(logger)
+	if err != nil {
+		return nil, err
+	}
+	db := edb.NewEnterpriseDB(wdb)
+
+	uploadStore, err := bulkimportstore.New(context.Background(), BulkImportConfigInst.UploadStoreConfig, observation.ContextWithLogger(logger))
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := newMetricsForExplicitPermissionsBulkImports(logger)
+
+	rootContext := actor.WithInternalActor(context.Background())
+
+	return []goroutine.BackgroundRoutine{
+		newExplicitPermissionsBulkImportWorker(rootContext, logger.Scoped("ExplicitPermissionsBulkImportWorker", ""), db, uploadStore, BulkImportConfigInst, metrics),
+		newExplicitPermissionsBulkImportResetter(logger.Scoped("ExplicitPermissionsBulkImportResetter", ""), db, BulkImportConfigInst, metrics),
+	}, nil
+}
+
+// explicitPermissionsBulkImportHandler handles the execution of a single explicit_permissions_bulk_import_jobs record.
+type explicitPermissionsBulkImportHandler struct {
+	db          edb.EnterpriseDB
+	uploadStore uploadstore.Store
+}
+
+// bulkImportEntry is a single (user, repo pattern, permission) entry of a bulk import.
+type bulkImportEntry struct {
+	// Line is the 1-based line number of the entry in the payload.
+	Line int
+	// User is the bind ID of the user, i.e. a username or an email address
+	// depending on the permissions user mapping.
+	User string `json:"user"`
+	// Repo is a regular expression matching the names of repositories.
+	Repo string `json:"repo"`
+	Perm string `json:"perm"`
+}
+
+// Handle implements the workerutil.Handler interface.
+//
+// The users of all entries matching a repository are granted access to it. In
+// merge mode they are added to the users that already have explicit access to
+// the repository, in replace mode they replace them. Entries that cannot be
+// applied are recorded as errors of the job and don't fail the job.
+func (h *explicitPermissionsBulkImportHandler) Handle(ctx context.Context, logger log.Logger, workerJob *types.ExplicitPermissionsBulkImportJob) (err error) {
+	logger = logger.Scoped("ExplicitPermissionsBulkImportHandler", "handles jobs to apply explicit repository permissions uploaded in bulk").With(log.Int("job_id", workerJob.ID))
+	defer func() {
+		if err != nil {
+			logger.Error("Handle", log.Error(err))
+		}
+	}()
+
+	payload, err := h.uploadStore.Get(ctx, workerJob.PayloadKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to get payload")
+	}
+	entries, lineErrs, processed, err := parseBulkImport(workerJob.Format, payload)
+	payload.Close()
+	if err != nil {
+		return errcode.MakeNonRetryable(err)
+	}
+
+	repoUsers, patternErrs, err := h.resolveRepos(ctx, entries)
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve repository patterns")
+	}
+	lineErrs = append(lineErrs, patternErrs...)
+	sort.SliceStable(lineErrs, func(i, j int) bool { return lineErrs[i].Line < lineErrs[j].Line })
+
+	var bindIDs []string
+	seen := make(map[string]struct{})
+	for _, users := range repoUsers {
+		for _, bindID := range users {
+			if _, ok := seen[bindID]; !ok {
+				seen[bindID] = struct{}{}
+				bindIDs = append(bindIDs, bindID)
+			}
+		}
+	}
+	sort.Strings(bindIDs)
+
+	// bind the bindIDs to actual user IDs
+	mapping, err := h.db.Perms().MapUsers(ctx, bindIDs, globals.PermissionsUserMapping())
+	if err != nil {
+		return errors.Wrap(err, "failed to map bind IDs to user IDs")
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(repoUsers))
+	for repoID := range repoUsers {
+		repoIDs = append(repoIDs, repoID)
+	}
+	sort.Slice(repoIDs, func(i, j int) bool { return repoIDs[i] < repoIDs[j] })
+
+	merge := workerJob.Mode != types.ExplicitPermissionsBulkImportModeReplace
+	var existingPendingBindIDs map[api.RepoID][]string
+	if merge {
+		existingPendingBindIDs, err = h.loadPendingBindIDs(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to load pending permissions")
+		}
+	}
+
+	logger.Info("Applying explicit permissions from bulk import",
+		log.Int("repo_ids_len", len(repoIDs)),
+		log.Int("bind_ids_len", len(bindIDs)),
+		log.Int("errors_len", len(lineErrs)),
+		log.String("mode", string(workerJob.Mode)),
+	)
+
+	// The audit log is attributed to the user that uploaded the document rather
+	// than to the internal actor of the worker.
+	auditCtx := ctx
+	if workerJob.CreatorUserID != 0 {
+		auditCtx = actor.WithActor(ctx, actor.FromUser(workerJob.CreatorUserID))
+	}
+
+	// apply the permissions for each repo. Each repo is processed atomically. In
+	// case of error, the task fails but doesn't rollback the changes committed
+	// for previous repos, which are applied again when the task is retried.
+	for _, repoID := range repoIDs {
+		userIDs := make(map[int32]struct{})
+		var pendingBindIDs []string
+		for _, bindID := range repoUsers[repoID] {
+			if id, ok := mapping[bindID]; ok {
+				userIDs[id] = struct{}{}
+			} else {
+				pendingBindIDs = append(pendingBindIDs, bindID)
+			}
+		}
+
+		if merge {
+			pendingBindIDs = append(pendingBindIDs, existingPendingBindIDs[repoID]...)
+		}
+
+		if err := h.setRepoPermissions(ctx, repoID, userIDs, pendingBindIDs, merge); err != nil {
+			return errors.Wrapf(err, "failed to set permissions for repo %d", repoID)
+		}
+
+		audit.Log(auditCtx, logger, audit.Record{
+			Entity: "explicit repository permissions",
+			Action: "bulk import set repository permissions",
+			Fields: []log.Field{
+				log.Int("job_id", workerJob.ID),
+				log.Int32("repo_id", int32(repoID)),
+				log.Int("user_ids_len", len(userIDs)),
+				log.Strings("pending_bind_ids", pendingBindIDs),
+			},
+		})
+	}
+
+	if err := h.db.ExplicitPermissionsBulkImportJobs().RecordResult(ctx, workerJob.ID, processed, lineErrs); err != nil {
+		return errors.Wrap(err, "failed to record result")
+	}
+
+	// The payload isn't needed anymore once the job succeeded. Objects left
+	// behind are removed by the expirer of the upload store.
+	if err := h.uploadStore.Delete(ctx, workerJob.PayloadKey); err != nil {
+		logger.Warn("failed to delete payload", log.String("key", workerJob.PayloadKey), log.Error(err))
+	}
+	return nil
+}
+
+// loadPendingBindIDs returns the bind IDs of the users that don't exist yet and
+// have pending explicit permissions to each repository.
+func (h *explicitPermissionsBulkImportHandler) loadPendingBindIDs(ctx context.Context) (map[api.RepoID][]string, error) {
+	bindIDs, err := h.db.Perms().ListPendingUsers(ctx, authz.SourcegraphServiceType, authz.SourcegraphServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	repoBindIDs := make(map[api.RepoID][]string)
+	for _, bindID := range bindIDs {
+		p := &authz.UserPendingPermissions{
+			ServiceType: authz.SourcegraphServiceType,
+			ServiceID:   authz.SourcegraphServiceID,
+			BindID:      bindID,
+			Perm:        authz.Read,
+			Type:        authz.PermRepos,
+		}
+		if err := h.db.Perms().LoadUserPendingPermissions(ctx, p); err != nil {
+			if errors.Is(err, authz.ErrPermsNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		for id := range p.IDs {
+			repoBindIDs[api.RepoID(id)] = append(repoBindIDs[api.RepoID(id)], bindID)
+		}
+	}
+	return repoBindIDs, nil
+}
+
+// resolveRepos returns the bind IDs of the users that should have access to
+// each repository matched by the entries, in the order of the entries. Entries
+// whose pattern matches no repository are returned as errors.
+func (h *explicitPermissionsBulkImportHandler) resolveRepos(ctx context.Context, entries []bulkImportEntry) (map[api.RepoID][]string, []*types.ExplicitPermissionsBulkImportError, error) {
+	patternRepos := make(map[string][]types.MinimalRepo)
+	repoUsers := make(map[api.RepoID][]string)
+	var errs []*types.ExplicitPermissionsBulkImportError
+
+	for _, entry := range entries {
+		repos, ok := patternRepos[entry.Repo]
+		if !ok {
+			var err error
+			repos, err = h.db.Repos().ListMinimalRepos(ctx, database.ReposListOptions{IncludePatterns: []string{entry.Repo}})
+			if err != nil {
+				return nil, nil, err
+			}
+			patternRepos[entry.Repo] = repos
+		}
+
+		if len(repos) == 0 {
+			errs = append(errs, &types.ExplicitPermissionsBulkImportError{
+				Line:    entry.Line,
+				Message: fmt.Sprintf("no repositories match %q", entry.Repo),
+			})
+			continue
+		}
+		for _, repo := range repos {
+			repoUsers[repo.ID] = append(repoUsers[repo.ID], entry.User)
+		}
+	}
+
+	return repoUsers, errs, nil
+}
+
+// setRepoPermissions sets the users that have explicit access to the
+// repository. When merge is true, the users that already have access keep it.
+func (h *explicitPermissionsBulkImportHandler) setRepoPermissions(ctx context.Context, repoID api.RepoID, userIDs map[int32]struct{}, pendingBindIDs []string, merge bool) (err error) {
+	p := authz.RepoPermissions{
+		RepoID:  int32(repoID),
+		Perm:    authz.Read, // Note: We currently only support read for repository permissions.
+		UserIDs: userIDs,
+	}
+
+	txs, err := h.db.Perms().Transact(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer func() { err = txs.Done(err) }()
+
+	if merge {
+		existing := authz.RepoPermissions{RepoID: int32(repoID), Perm: authz.Read}
+		if err := txs.LoadRepoPermissions(ctx, &existing); err != nil && !errors.Is(err, authz.ErrPermsNotFound) {
+			return errors.Wrapf(err, "failed to load repo permissions for repo %d", repoID)
+		}
+		for id := range existing.UserIDs {
+			userIDs[id] = struct{}{}
+		}
+	}
+
+	accounts := &extsvc.Accounts{
+		ServiceType: authz.SourcegraphServiceType,
+		ServiceID:   authz.SourcegraphServiceID,
+		AccountIDs:  pendingBindIDs,
+	}
+
+	// set repo permissions (and user permissions)
+	err = txs.SetRepoPermissions(ctx, &p)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set repo permissions for repo %d", repoID)
+	}
+
+	// set pending permissions
+	err = txs.SetRepoPendingPermissions(ctx, accounts, &p)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set pending permissions for repo %d", repoID)
+	}
+
+	return nil
+}
+
+// parseBulkImport reads the entries of a bulk import document. Entries that
+// are malformed or invalid are returned as errors. processed is the number of
+// entries read, including the invalid ones.
+func parseBulkImport(format types.ExplicitPermissionsBulkImportFormat, r io.Reader) (entries []bulkImportEntry, errs []*types.ExplicitPermissionsBulkImportError, processed int, err error) {
+	addEntry := func(entry bulkImportEntry) {
+		processed++
+		if msg := validateBulkImportEntry(entry); msg != "" {
+			errs = append(errs, &types.ExplicitPermissionsBulkImportError{Line: entry.Line, Message: msg})
+			return
+		}
+		entries = append(entries, entry)
+	}
+	addError := func(line int, msg string) {
+		processed++
+		errs = append(errs, &types.ExplicitPermissionsBulkImportError{Line: line, Message: msg})
+	}
+
+	switch format {
+	case types.ExplicitPermissionsBulkImportFormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		for first := true; ; first = false {
+			record, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				var parseErr *csv.ParseError
+				if errors.As(err, &parseErr) {
+					addError(parseErr.StartLine, parseErr.Err.Error())
+					continue
+				}
+				return nil, nil, 0, err
+			}
+
+			line, _ := cr.FieldPos(0)
+			// The header row is optional.
+			if first && isBulkImportCSVHeader(record) {
+				continue
+			}
+			if len(record) != 3 {
+				addError(line, fmt.Sprintf("expected 3 fields (user, repo, perm), got %d", len(record)))
+				continue
+			}
+			addEntry(bulkImportEntry{Line: line, User: record[0], Repo: record[1], Perm: record[2]})
+		}
+
+	case types.ExplicitPermissionsBulkImportFormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+
+			entry := bulkImportEntry{Line: line}
+			if err := json.Unmarshal([]byte(text), &entry); err != nil {
+				addError(line, fmt.Sprintf("invalid JSON: %s", err))
+				continue
+			}
+			entry.Line = line
+			addEntry(entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, nil, 0, err
+		}
+
+	default:
+		return nil, nil, 0, errors.Errorf("unsupported format %q", format)
+	}
+
+	return entries, errs, processed, nil
+}
+
+func isBulkImportCSVHeader(record []string) bool {
+	return len(record) == 3 &&
+		strings.EqualFold(record[0], "user") &&
+		strings.EqualFold(record[1], "repo") &&
+		strings.EqualFold(record[2], "perm")
+}
+
+// validateBulkImportEntry returns a description of the problem with the
+// entry, or an empty string if it is valid.
+func validateBulkImportEntry(entry bulkImportEntry) string {
+	if entry.User == "" {
+		return "user must not be empty"
+	}
+	if entry.Repo == "" {
+		return "repo must not be empty"
+	}
+	if _, err := regexp.Compile(entry.Repo); err != nil {
+		return fmt.Sprintf("invalid repo pattern %q: %s", entry.Repo, err)
+	}
+	// We currently only support read for repository permissions.
+	if entry.Perm != authz.Read.String() {
+		return fmt.Sprintf("unsupported permission %q, only %q is supported", entry.Perm, authz.Read.String())
+	}
+	return ""
+}
+
+// newExplicitPermissionsBulkImportWorker creates a worker that reads the explicit_permissions_bulk_import_jobs table and
+// executes the jobs.
+func newExplicitPermissionsBulkImportWorker(ctx context.Context, logger log.Logger, db edb.EnterpriseDB, uploadStore uploadstore.Store, cfg *bulkImportConfig, metrics explicitPermissionsBulkImportMetrics) *workerutil.Worker[*types.ExplicitPermissionsBulkImportJob] {
+	options := workerutil.WorkerOptions{
+		Name:              "explicit_permissions_bulk_import_jobs_worker",
+		NumHandlers:       cfg.WorkerConcurrency,
+		Interval:          cfg.WorkerPollInterval,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics.workerMetrics,
+	}
+
+	return dbworker.NewWorker[*types.ExplicitPermissionsBulkImportJob](ctx, createExplicitPermissionsBulkImportStore(logger, db, cfg), &explicitPermissionsBulkImportHandler{db: db, uploadStore: uploadStore}, options)
+}
+
+// newExplicitPermissionsBulkImportResetter implements resetter for the explicit_permissions_bulk_import_jobs table.
+// See resetter documentation for more details. https://docs.sourcegraph.com/dev/background-information/workers#dequeueing-and-resetting-jobs
+func newExplicitPermissionsBulkImportResetter(logger log.Logger, db edb.EnterpriseDB, cfg *bulkImportConfig, metrics explicitPermissionsBulkImportMetrics) *dbworker.Resetter[*types.ExplicitPermissionsBulkImportJob] {
+	workerStore := createExplicitPermissionsBulkImportStore(logger, db, cfg)
+
+	options := dbworker.ResetterOptions{
+		Name:     "explicit_permissions_bulk_import_jobs_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics: dbworker.ResetterMetrics{
+			Errors:              metrics.errors,
+			RecordResetFailures: metrics.resetFailures,
+			RecordResets:        metrics.resets,
+		},
+	}
+	return dbworker.NewResetter(logger, workerStore, options)
+}
+
+// createExplicitPermissionsBulkImportStore creates a store that reads and writes to the explicit_permissions_bulk_import_jobs table.
+// It is used by the worker and resetter.
+func createExplicitPermissionsBulkImportStore(logger log.Logger, s basestore.ShareableStore, cfg *bulkImportConfig) dbworkerstore.Store[*types.ExplicitPermissionsBulkImportJob] {
+	return dbworkerstore.New(logger.Scoped("ExplicitPermissionsBulkImport.Store", ""), s.Handle(), dbworkerstore.Options[*types.ExplicitPermissionsBulkImportJob]{
+		Name:              "explicit_permissions_bulk_import_jobs_store",
+		TableName:         "explicit_permissions_bulk_import_jobs",
+		ColumnExpressions: database.ExplicitPermissionsBulkImportJobColumnExpressions,
+		Scan:              dbworkerstore.BuildWorkerScan(database.ScanExplicitPermissionsBulkImportJob),
+		StalledMaxAge:     60 * time.Second,
+		RetryAfter:        cfg.WorkerRetryInterval,
+		MaxNumRetries:     5,
+		OrderByExpression: sqlf.Sprintf("explicit_permissions_bulk_import_jobs.id"),
+	})
+}
+
+// These are the metrics that are used by the worker and resetter.
+// They are required by the workerutil package for automatic metrics collection.
+type explicitPermissionsBulkImportMetrics struct {
+	workerMetrics workerutil.WorkerObservability
+	resets        prometheus.Counter
+	resetFailures prometheus.Counter
+	errors        prometheus.Counter
+}
+
+func newMetricsForExplicitPermissionsBulkImports(logger log.Logger) explicitPermissionsBulkImportMetrics {
+	observationContext := &observation.Context{
+		Logger:     logger.Scoped("routines", "explicit permissions bulk import job routines"),
+		Tracer:     &trace.Tracer{TracerProvider: otel.GetTracerProvider()},
+		Registerer: prometheus.DefaultRegisterer,
+	}
+
+	resetFailures := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_explicit_permissions_bulk_import_reset_failures_total",
+		Help: "The number of reset failures.",
+	})
+	observationContext.Registerer.MustRegister(resetFailures)
+
+	resets := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_explicit_permissions_bulk_import_resets_total",
+		Help: "The number of records reset.",
+	})
+	observationContext.Registerer.MustRegister(resets)
+
+	errors := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_explicit_permissions_bulk_import_errors_total",
+		Help: "The number of errors that occur during job.",
+	})
+	observationContext.Registerer.MustRegister(errors)
+
+	return explicitPermissionsBulkImportMetrics{
+		workerMetrics: workerutil.NewMetrics(observationContext, "explicit_permissions_bulk_imports"),
+		resets:        resets,
+		resetFailures: resetFailures,
+		errors:        errors,
+	}
+}
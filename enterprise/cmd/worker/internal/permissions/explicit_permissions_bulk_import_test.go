@@ -0,0 +1,242 @@
+package permissions
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	edb "github.com/sourcegraph/sourcegraph/enterprise/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+)
+
+func TestParseBulkImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CSV", func(t *testing.T) {
+		payload := `user,repo,perm
+alice@example.com,^github\.com/sourcegraph/.*$,read
+bob@example.com,,read
+carol@example.com,github.com/foo,write
+"dave@example.com,github.com/foo
+eve@example.com,github.com/foo,read
+`
+		entries, errs, processed, err := parseBulkImport(types.ExplicitPermissionsBulkImportFormatCSV, strings.NewReader(payload))
+		require.NoError(t, err)
+
+		assert.Equal(t, []bulkImportEntry{
+			{Line: 2, User: "alice@example.com", Repo: `^github\.com/sourcegraph/.*$`, Perm: "read"},
+		}, entries)
+		assert.Equal(t, []*types.ExplicitPermissionsBulkImportError{
+			{Line: 3, Message: "repo must not be empty"},
+			{Line: 4, Message: `unsupported permission "write", only "read" is supported`},
+			{Line: 5, Message: `extraneous or missing " in quoted-field`},
+		}, errs)
+		assert.Equal(t, 4, processed)
+	})
+
+	t.Run("CSV without header", func(t *testing.T) {
+		entries, errs, processed, err := parseBulkImport(types.ExplicitPermissionsBulkImportFormatCSV, strings.NewReader("alice@example.com, github.com/foo, read\nbob@example.com,github.com/bar\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []bulkImportEntry{
+			{Line: 1, User: "alice@example.com", Repo: "github.com/foo", Perm: "read"},
+		}, entries)
+		assert.Equal(t, []*types.ExplicitPermissionsBulkImportError{
+			{Line: 2, Message: "expected 3 fields (user, repo, perm), got 2"},
+		}, errs)
+		assert.Equal(t, 2, processed)
+	})
+
+	t.Run("JSONL", func(t *testing.T) {
+		payload := `{"user": "alice@example.com", "repo": "github.com/foo", "perm": "read"}
+
+{"user": "bob@example.com", "repo": "github.com/(foo", "perm": "read"}
+{"user": "carol@example.com"
+{"repo": "github.com/foo", "perm": "read"}
+`
+		entries, errs, processed, err := parseBulkImport(types.ExplicitPermissionsBulkImportFormatJSONL, strings.NewReader(payload))
+		require.NoError(t, err)
+
+		assert.Equal(t, []bulkImportEntry{
+			{Line: 1, User: "alice@example.com", Repo: "github.com/foo", Perm: "read"},
+		}, entries)
+		require.Len(t, errs, 3)
+		assert.Equal(t, 3, errs[0].Line)
+		assert.Contains(t, errs[0].Message, `invalid repo pattern "github.com/(foo"`)
+		assert.Equal(t, 4, errs[1].Line)
+		assert.Contains(t, errs[1].Message, "invalid JSON")
+		assert.Equal(t, &types.ExplicitPermissionsBulkImportError{Line: 5, Message: "user must not be empty"}, errs[2])
+		assert.Equal(t, 4, processed)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, _, _, err := parseBulkImport("xml", strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
+
+func TestExplicitPermissionsBulkImportHandle_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	uploadStore := uploadstoremocks.NewMockStore()
+	uploadStore.GetFunc.SetDefaultReturn(io.NopCloser(strings.NewReader("")), nil)
+
+	handler := &explicitPermissionsBulkImportHandler{db: edb.NewEnterpriseDB(database.NewMockDB()), uploadStore: uploadStore}
+	err := handler.Handle(context.Background(), logtest.Scoped(t), &types.ExplicitPermissionsBulkImportJob{Format: "xml"})
+
+	require.True(t, errcode.IsNonRetryable(err))
+}
+
+func TestExplicitPermissionsBulkImportHandle(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+
+	db := edb.NewEnterpriseDB(database.NewDB(logger, dbtest.NewDB(logger, t)))
+
+	alice, err := db.Users().Create(ctx, database.NewUser{
+		Email:           "alice@example.com",
+		Username:        "alice",
+		EmailIsVerified: true,
+	})
+	require.NoError(t, err)
+
+	for _, repo := range []*types.Repo{
+		{ID: 1, Name: "github.com/sourcegraph/sourcegraph"},
+		{ID: 2, Name: "github.com/sourcegraph/handbook"},
+		{ID: 3, Name: "github.com/other/repo"},
+	} {
+		require.NoError(t, db.Repos().Create(ctx, repo))
+	}
+
+	payload := `user,repo,perm
+alice@example.com,^github\.com/sourcegraph/,read
+bob@example.com,^github\.com/sourcegraph/sourcegraph$,read
+carol@example.com,^gitlab\.com/,read
+`
+	uploadStore := uploadstoremocks.NewMockStore()
+	uploadStore.GetFunc.SetDefaultHook(func(_ context.Context, key string) (io.ReadCloser, error) {
+		require.Equal(t, "explicit-permissions-bulk-imports/1", key)
+		return io.NopCloser(strings.NewReader(payload)), nil
+	})
+
+	jobID, err := db.ExplicitPermissionsBulkImportJobs().Enqueue(ctx, types.ExplicitPermissionsBulkImportFormatCSV, types.ExplicitPermissionsBulkImportModeReplace, "explicit-permissions-bulk-imports/1", alice.ID)
+	require.NoError(t, err)
+	job, err := db.ExplicitPermissionsBulkImportJobs().GetByID(ctx, jobID)
+	require.NoError(t, err)
+
+	h := explicitPermissionsBulkImportHandler{db: db, uploadStore: uploadStore}
+	require.NoError(t, h.Handle(ctx, logger, job))
+
+	// The payload is deleted once the job succeeded.
+	require.Len(t, uploadStore.DeleteFunc.History(), 1)
+	assert.Equal(t, "explicit-permissions-bulk-imports/1", uploadStore.DeleteFunc.History()[0].Arg1)
+
+	for _, repoID := range []int32{1, 2} {
+		p := authz.RepoPermissions{RepoID: repoID, Perm: authz.Read}
+		require.NoError(t, db.Perms().LoadRepoPermissions(ctx, &p))
+		assert.Equal(t, map[int32]struct{}{alice.ID: {}}, p.UserIDs)
+	}
+
+	pending := &authz.UserPendingPermissions{
+		ServiceType: authz.SourcegraphServiceType,
+		ServiceID:   authz.SourcegraphServiceID,
+		BindID:      "bob@example.com",
+		Perm:        authz.Read,
+		Type:        authz.PermRepos,
+	}
+	require.NoError(t, db.Perms().LoadUserPendingPermissions(ctx, pending))
+	assert.Equal(t, map[int32]struct{}{1: {}}, pending.IDs)
+
+	job, err = db.ExplicitPermissionsBulkImportJobs().GetByID(ctx, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, job.ProcessedLines)
+	assert.Equal(t, 1, job.FailedLines)
+
+	errs, err := db.ExplicitPermissionsBulkImportJobs().ListErrors(ctx, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, []*types.ExplicitPermissionsBulkImportError{
+		{Line: 4, Message: `no repositories match "^gitlab\\.com/"`},
+	}, errs)
+}
+
+func TestExplicitPermissionsBulkImportHandle_Modes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+
+	db := edb.NewEnterpriseDB(database.NewDB(logger, dbtest.NewDB(logger, t)))
+
+	var userIDs []int32
+	for _, username := range []string{"alice", "bob", "carol"} {
+		user, err := db.Users().Create(ctx, database.NewUser{
+			Email:           username + "@example.com",
+			Username:        username,
+			EmailIsVerified: true,
+		})
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID)
+	}
+	require.NoError(t, db.Repos().Create(ctx, &types.Repo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}))
+
+	payloads := map[string]string{
+		"first":  "alice@example.com,^github\\.com/sourcegraph/sourcegraph$,read\ndave@example.com,^github\\.com/sourcegraph/sourcegraph$,read\n",
+		"second": "bob@example.com,^github\\.com/sourcegraph/sourcegraph$,read\n",
+		"third":  "carol@example.com,^github\\.com/sourcegraph/sourcegraph$,read\n",
+	}
+	uploadStore := uploadstoremocks.NewMockStore()
+	uploadStore.GetFunc.SetDefaultHook(func(_ context.Context, key string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(payloads[key])), nil
+	})
+	h := explicitPermissionsBulkImportHandler{db: db, uploadStore: uploadStore}
+
+	handle := func(mode types.ExplicitPermissionsBulkImportMode, key string) {
+		t.Helper()
+		jobID, err := db.ExplicitPermissionsBulkImportJobs().Enqueue(ctx, types.ExplicitPermissionsBulkImportFormatCSV, mode, key, userIDs[0])
+		require.NoError(t, err)
+		job, err := db.ExplicitPermissionsBulkImportJobs().GetByID(ctx, jobID)
+		require.NoError(t, err)
+		require.NoError(t, h.Handle(ctx, logger, job))
+	}
+	assertPerms := func(wantUserIDs map[int32]struct{}, wantPendingRepoIDs map[int32]struct{}) {
+		t.Helper()
+		p := authz.RepoPermissions{RepoID: 1, Perm: authz.Read}
+		require.NoError(t, db.Perms().LoadRepoPermissions(ctx, &p))
+		assert.Equal(t, wantUserIDs, p.UserIDs)
+
+		pending := &authz.UserPendingPermissions{
+			ServiceType: authz.SourcegraphServiceType,
+			ServiceID:   authz.SourcegraphServiceID,
+			BindID:      "dave@example.com",
+			Perm:        authz.Read,
+			Type:        authz.PermRepos,
+		}
+		require.NoError(t, db.Perms().LoadUserPendingPermissions(ctx, pending))
+		assert.Equal(t, wantPendingRepoIDs, pending.IDs)
+	}
+
+	handle(types.ExplicitPermissionsBulkImportModeMerge, "first")
+	assertPerms(map[int32]struct{}{userIDs[0]: {}}, map[int32]struct{}{1: {}})
+
+	// Merging keeps the users, including the pending ones, that already have access.
+	handle(types.ExplicitPermissionsBulkImportModeMerge, "second")
+	assertPerms(map[int32]struct{}{userIDs[0]: {}, userIDs[1]: {}}, map[int32]struct{}{1: {}})
+
+	// Replacing revokes the access of the users that aren't part of the import.
+	handle(types.ExplicitPermissionsBulkImportModeReplace, "third")
+	assertPerms(map[int32]struct{}{userIDs[2]: {}}, map[int32]struct{}{})
+}
@@ -41,24 +41,26 @@ func main() {
 	go setAuthzProviders(logger)
 
 	additionalJobs := map[string]job.Job{
-		"codehost-version-syncing":      versions.NewSyncingJob(),
-		"insights-job":                  workerinsights.NewInsightsJob(),
-		"insights-query-runner-job":     workerinsights.NewInsightsQueryRunnerJob(),
-		"batches-janitor":               batches.NewJanitorJob(),
-		"batches-scheduler":             batches.NewSchedulerJob(),
-		"batches-reconciler":            batches.NewReconcilerJob(),
-		"batches-bulk-processor":        batches.NewBulkOperationProcessorJob(),
-		"batches-workspace-resolver":    batches.NewWorkspaceResolverJob(),
-		"executors-janitor":             executors.NewJanitorJob(),
-		"executors-metricsserver":       executors.NewMetricsServerJob(),
-		"codemonitors-job":              codemonitors.NewCodeMonitorJob(),
-		"bitbucket-project-permissions": permissions.NewBitbucketProjectPermissionsJob(),
-		"export-usage-telemetry":        telemetry.NewTelemetryJob(),
-		"webhook-build-job":             repos.NewWebhookBuildJob(),
+		"codehost-version-syncing":         versions.NewSyncingJob(),
+		"insights-job":                     workerinsights.NewInsightsJob(),
+		"insights-query-runner-job":        workerinsights.NewInsightsQueryRunnerJob(),
+		"batches-janitor":                  batches.NewJanitorJob(),
+		"batches-scheduler":                batches.NewSchedulerJob(),
+		"batches-reconciler":               batches.NewReconcilerJob(),
+		"batches-bulk-processor":           batches.NewBulkOperationProcessorJob(),
+		"batches-workspace-resolver":       batches.NewWorkspaceResolverJob(),
+		"executors-janitor":                executors.NewJanitorJob(),
+		"executors-metricsserver":          executors.NewMetricsServerJob(),
+		"codemonitors-job":                 codemonitors.NewCodeMonitorJob(),
+		"bitbucket-project-permissions":    permissions.NewBitbucketProjectPermissionsJob(),
+		"explicit-permissions-bulk-import": permissions.NewExplicitPermissionsBulkImportJob(),
+		"export-usage-telemetry":           telemetry.NewTelemetryJob(),
+		"webhook-build-job":                repos.NewWebhookBuildJob(),
 
 		"codeintel-policies-repository-matcher":       codeintel.NewPoliciesRepositoryMatcherJob(),
 		"codeintel-autoindexing-dependency-scheduler": codeintel.NewAutoindexingDependencySchedulerJob(),
 		"codeintel-autoindexing-janitor":              codeintel.NewAutoindexingJanitorJob(),
+		"codeintel-autoindexing-cache-expirer":        codeintel.NewAutoindexingCacheExpirerJob(),
 		"codeintel-autoindexing-scheduler":            codeintel.NewAutoindexingSchedulerJob(),
 		"codeintel-commitgraph-updater":               codeintel.NewCommitGraphUpdaterJob(),
 		"codeintel-metrics-reporter":                  codeintel.NewMetricsReporterJob(),
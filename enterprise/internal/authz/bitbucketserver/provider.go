@@ -146,9 +146,14 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 
 	ids, err := p.repoIDs(ctx, user.Name, false)
 
+	sim := authz.PermsSimulationFromContext(ctx)
 	extIDs := make([]extsvc.RepoID, 0, len(ids))
 	for _, id := range ids {
-		extIDs = append(extIDs, extsvc.RepoID(strconv.FormatUint(uint64(id), 10)))
+		extID := extsvc.RepoID(strconv.FormatUint(uint64(id), 10))
+		if sim.IsRepo(extID) {
+			sim.Grant("the repository is listed by the %s as readable by user %q", p.repoIDsSource(), user.Name)
+		}
+		extIDs = append(extIDs, extID)
 	}
 
 	return &authz.ExternalUserPermissions{
@@ -176,9 +181,14 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 
 	ids, err := p.userIDs(ctx, repo.ID)
 
+	sim := authz.PermsSimulationFromContext(ctx)
 	extIDs := make([]extsvc.AccountID, 0, len(ids))
 	for _, id := range ids {
-		extIDs = append(extIDs, extsvc.AccountID(strconv.FormatInt(int64(id), 10)))
+		extID := extsvc.AccountID(strconv.FormatInt(int64(id), 10))
+		if sim.IsAccount(extID) {
+			sim.Grant("the user is listed by the Bitbucket Server API as having read access to the repository, directly or through a group")
+		}
+		extIDs = append(extIDs, extID)
 	}
 
 	return extIDs, err
@@ -193,6 +203,14 @@ func (p *Provider) repoIDs(ctx context.Context, username string, public bool) ([
 	return p.repoIDsFromAPI(ctx, username, public)
 }
 
+// repoIDsSource describes where repoIDs gets its results from.
+func (p *Provider) repoIDsSource() string {
+	if p.pluginPerm {
+		return "Bitbucket Server permissions plugin"
+	}
+	return "Bitbucket Server API"
+}
+
 // repoIDsFromAPI returns all repositories for which the given user has the permission to read from
 // the Bitbucket Server API. when no username is given, only public repos are returned.
 func (p *Provider) repoIDsFromAPI(ctx context.Context, username string, public bool) (ids []uint32, err error) {
@@ -0,0 +1,76 @@
+package bulkimportstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	config := Config{}
+	config.SetMockGetter(mapGetter(nil))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if config.Bucket != "explicit-permissions-bulk-imports" {
+		t.Errorf("unexpected value for Bucket. want=%s have=%s", "explicit-permissions-bulk-imports", config.Bucket)
+	}
+	if !config.ManageBucket {
+		t.Errorf("unexpected value for ManageBucket. want=%v have=%v", true, config.ManageBucket)
+	}
+	if config.TTL != 30*24*time.Hour {
+		t.Errorf("unexpected value for TTL. want=%v have=%v", 30*24*time.Hour, config.TTL)
+	}
+	if config.S3Endpoint != "http://minio:9000" {
+		t.Errorf("unexpected value for S3.Endpoint. want=%s have=%s", "http://minio:9000", config.S3Endpoint)
+	}
+}
+
+func TestConfigIndependentOfCodeIntel(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":               "invalid",
+		"EXPLICIT_PERMISSIONS_BULK_IMPORT_UPLOAD_BACKEND": "S3",
+		"EXPLICIT_PERMISSIONS_BULK_IMPORT_UPLOAD_BUCKET":  "bulk-imports",
+		"EXPLICIT_PERMISSIONS_BULK_IMPORT_UPLOAD_TTL":     "8h",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if config.Backend != "s3" {
+		t.Errorf("unexpected value for Backend. want=%s have=%s", "s3", config.Backend)
+	}
+	if config.Bucket != "bulk-imports" {
+		t.Errorf("unexpected value for Bucket. want=%s have=%s", "bulk-imports", config.Bucket)
+	}
+	if config.TTL != 8*time.Hour {
+		t.Errorf("unexpected value for TTL. want=%v have=%v", 8*time.Hour, config.TTL)
+	}
+}
+
+func TestConfigInvalidBackend(t *testing.T) {
+	config := Config{}
+	config.SetMockGetter(mapGetter(map[string]string{"EXPLICIT_PERMISSIONS_BULK_IMPORT_UPLOAD_BACKEND": "ftp"}))
+	config.Load()
+
+	if err := config.Validate(); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+func mapGetter(env map[string]string) func(name, defaultValue, description string) string {
+	return func(name, defaultValue, description string) string {
+		if v, ok := env[name]; ok {
+			return v
+		}
+
+		return defaultValue
+	}
+}
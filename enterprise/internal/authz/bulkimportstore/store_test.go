@@ -0,0 +1,35 @@
+package bulkimportstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/lsifuploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestNewAlongsideLSIFUploadStore(t *testing.T) {
+	// The worker creates both stores in the same process, their metrics must
+	// not collide.
+	observationContext := &observation.Context{
+		Logger:     logtest.Scoped(t),
+		Registerer: prometheus.NewRegistry(),
+	}
+
+	lsifConfig := &lsifuploadstore.Config{}
+	lsifConfig.SetMockGetter(mapGetter(nil))
+	lsifConfig.Load()
+	if _, err := lsifuploadstore.New(context.Background(), lsifConfig, observationContext); err != nil {
+		t.Fatalf("unexpected error creating lsif upload store: %s", err)
+	}
+
+	config := &Config{}
+	config.SetMockGetter(mapGetter(nil))
+	config.Load()
+	if _, err := New(context.Background(), config, observationContext); err != nil {
+		t.Fatalf("unexpected error creating bulk import store: %s", err)
+	}
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/gregjones/httpcache"
 
@@ -42,6 +43,14 @@ func (g *cachedGroup) key() string {
 	return key
 }
 
+// describe returns a human readable description of the group.
+func (g *cachedGroup) describe() string {
+	if g.Team == "" {
+		return fmt.Sprintf("organization %q", g.Org)
+	}
+	return fmt.Sprintf("team %q of organization %q", g.Team, g.Org)
+}
+
 type cachedGroups struct {
 	cache httpcache.Cache
 }
@@ -82,7 +82,10 @@ func NewProvider(urn string, opts ProviderOptions) *Provider {
 	}
 }
 
-var _ authz.Provider = (*Provider)(nil)
+var (
+	_ authz.Provider               = (*Provider)(nil)
+	_ authz.GroupsCacheInvalidator = (*Provider)(nil)
+)
 
 // FetchAccount implements the authz.Provider interface. It always returns nil, because the GitHub
 // API doesn't currently provide a way to fetch user by external SSO account.
@@ -90,6 +93,15 @@ func (p *Provider) FetchAccount(context.Context, *types.User, []*extsvc.Account,
 	return nil, nil
 }
 
+// InvalidateGroupsCache implements the authz.GroupsCacheInvalidator interface. It
+// is a no-op if group caching is disabled.
+func (p *Provider) InvalidateGroupsCache(org, team string) {
+	if p.groupsCache == nil {
+		return
+	}
+	p.groupsCache.invalidateGroup(&cachedGroup{Org: org, Team: team})
+}
+
 func (p *Provider) URN() string {
 	return p.urn
 }
@@ -222,6 +234,8 @@ func (p *Provider) fetchUserPermsByToken(ctx context.Context, accountID extsvc.A
 		affiliations = []github.RepositoryAffiliation{github.AffiliationOwner, github.AffiliationCollaborator}
 	}
 
+	sim := authz.PermsSimulationFromContext(ctx)
+
 	// Sync direct affiliations
 	hasNextPage := true
 	for page := 1; hasNextPage; page++ {
@@ -233,6 +247,13 @@ func (p *Provider) fetchUserPermsByToken(ctx context.Context, accountID extsvc.A
 		}
 
 		for _, r := range repos {
+			if sim.IsRepo(extsvc.RepoID(r.ID)) {
+				if affiliations == nil {
+					sim.Grant("the user is an owner, collaborator or organization member of the repository")
+				} else {
+					sim.Grant("the user is an owner or a direct collaborator of the repository")
+				}
+			}
 			addRepoToUserPerms(extsvc.RepoID(r.ID))
 		}
 	}
@@ -264,7 +285,7 @@ func (p *Provider) fetchUserPermsByToken(ctx context.Context, accountID extsvc.A
 			}
 			if !hasUser {
 				group.Users = append(group.Users, accountID)
-				if err := p.groupsCache.setGroup(group); err != nil {
+				if err := p.setCachedGroup(ctx, group); err != nil {
 					logger.Warn("setting group", log.Error(err))
 				}
 			}
@@ -311,13 +332,16 @@ func (p *Provider) fetchUserPermsByToken(ctx context.Context, accountID extsvc.A
 			// Add results to both group (for persistence) and permissions for user
 			for _, r := range repos {
 				repoID := extsvc.RepoID(r.ID)
+				if sim.IsRepo(repoID) {
+					sim.Grant("the user is a member of %s, which has access to the repository", group.describe())
+				}
 				group.Repositories = append(group.Repositories, repoID)
 				addRepoToUserPerms(repoID)
 			}
 		}
 
 		// Persist repos affiliated with group to cache
-		if err := p.groupsCache.setGroup(group); err != nil {
+		if err := p.setCachedGroup(ctx, group); err != nil {
 			logger.Warn("setting group", log.Error(err))
 		}
 	}
@@ -435,6 +459,8 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 		return nil, errors.Wrap(err, "get client")
 	}
 
+	sim := authz.PermsSimulationFromContext(ctx)
+
 	// Sync collaborators
 	hasNextPage := true
 	for page := 1; hasNextPage; page++ {
@@ -451,6 +477,13 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 				userID = strconv.FormatInt(*p.InstallationID, 10) + "/" + userID
 			}
 
+			if sim.IsAccount(extsvc.AccountID(userID)) {
+				if affiliation == "" {
+					sim.Grant("the user is a collaborator of the repository, directly or through an organization or team")
+				} else {
+					sim.Grant("the user is a direct collaborator of the repository")
+				}
+			}
 			addUserToRepoPerms(extsvc.AccountID(userID))
 		}
 	}
@@ -480,7 +513,7 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 			}
 			if !hasRepo {
 				group.Repositories = append(group.Repositories, repoID)
-				p.groupsCache.setGroup(group.cachedGroup)
+				p.setCachedGroup(ctx, group.cachedGroup)
 			}
 		}
 
@@ -505,18 +538,44 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 			for _, u := range members {
 				// Add results to both group (for persistence) and permissions for user
 				accountID := extsvc.AccountID(strconv.FormatInt(u.DatabaseID, 10))
+				if sim.IsAccount(accountID) {
+					if group.adminsOnly {
+						sim.Grant("the user is an admin of %s, which has access to the repository", group.describe())
+					} else {
+						sim.Grant("the user is a member of %s, which has access to the repository", group.describe())
+					}
+				}
 				group.Users = append(group.Users, accountID)
 				addUserToRepoPerms(accountID)
 			}
 		}
 
 		// Persist group
-		p.groupsCache.setGroup(group.cachedGroup)
+		p.setCachedGroup(ctx, group.cachedGroup)
 	}
 
 	return userIDs, nil
 }
 
+// setCachedGroup persists the given group to the groups cache, unless permissions
+// are only being simulated.
+func (p *Provider) setCachedGroup(ctx context.Context, group cachedGroup) error {
+	if authz.IsPermsSimulation(ctx) {
+		return nil
+	}
+	return p.groupsCache.setGroup(group)
+}
+
+// getCachedGroup retrieves the given group from the groups cache. The cache is
+// bypassed while permissions are only being simulated, so that all decisions
+// are made from the code host rather than from possibly stale cached groups.
+func (p *Provider) getCachedGroup(ctx context.Context, org, team string) (cachedGroup, bool) {
+	if authz.IsPermsSimulation(ctx) {
+		return cachedGroup{Org: org, Team: team}, false
+	}
+	return p.groupsCache.getGroup(org, team)
+}
+
 // getUserAffiliatedGroups retrieves affiliated organizations and teams for the given client
 // with token. Returned groups are populated from cache if a valid value is available.
 //
@@ -536,7 +595,7 @@ func (p *Provider) getUserAffiliatedGroups(ctx context.Context, clientWithToken
 				return
 			}
 		}
-		cachedPerms, exists := p.groupsCache.getGroup(org, team)
+		cachedPerms, exists := p.getCachedGroup(ctx, org, team)
 		if exists && opts.InvalidateCaches {
 			// invalidate this cache
 			p.groupsCache.invalidateGroup(&cachedPerms)
@@ -609,7 +668,7 @@ func (p *Provider) getRepoAffiliatedGroups(ctx context.Context, owner, name stri
 
 	// indicate if a group should be sync'd
 	syncGroup := func(owner, team string, adminsOnly bool) {
-		group, exists := p.groupsCache.getGroup(owner, team)
+		group, exists := p.getCachedGroup(ctx, owner, team)
 		if exists && opts.InvalidateCaches {
 			// invalidate this cache
 			p.groupsCache.invalidateGroup(&group)
@@ -292,6 +292,81 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 			}
 		})
 
+		t.Run("simulation", func(t *testing.T) {
+			mockClient := newMockClientWithTokenMock()
+			mockClient.ListAffiliatedRepositoriesFunc.SetDefaultHook(mockListAffiliatedRepositories)
+			mockClient.GetAuthenticatedUserOrgsDetailsAndMembershipFunc.SetDefaultHook(mockListOrgDetails)
+			mockClient.GetAuthenticatedUserTeamsFunc.SetDefaultHook(
+				func(ctx context.Context, page int) (teams []*github.Team, hasNextPage bool, rateLimitCost int, err error) {
+					// No teams
+					return nil, false, 1, nil
+				})
+			mockClient.ListOrgRepositoriesFunc.SetDefaultHook(mockListOrgRepositories)
+
+			p := setupProvider(t, mockClient)
+
+			sim := &authz.PermsSimulation{Repo: "MDEwOlJlcG9zaXRvcnkyNTI0MjU2NzE="}
+			_, err := p.FetchUserPerms(authz.WithPermsSimulation(context.Background(), sim),
+				mockAccount,
+				authz.FetchPermsOptions{},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := []authz.PermsDecision{
+				{Effect: authz.PermsDecisionGrant, Reason: "the user is an owner or a direct collaborator of the repository"},
+				{Effect: authz.PermsDecisionGrant, Reason: `the user is a member of organization "sourcegraph", which has access to the repository`},
+			}
+			if diff := cmp.Diff(want, sim.Decisions()); diff != "" {
+				t.Fatalf("Decisions mismatch (-want +got):\n%s", diff)
+			}
+
+			// Nothing is persisted while simulating
+			if _, found := p.groupsCache.getGroup(mockOrgRead.Login, ""); found {
+				t.Fatal("unexpected group in cache")
+			}
+		})
+
+		t.Run("simulation bypasses the groups cache", func(t *testing.T) {
+			mockClient := newMockClientWithTokenMock()
+			mockClient.ListAffiliatedRepositoriesFunc.SetDefaultHook(mockListAffiliatedRepositories)
+			mockClient.GetAuthenticatedUserOrgsDetailsAndMembershipFunc.SetDefaultHook(mockListOrgDetails)
+			mockClient.GetAuthenticatedUserTeamsFunc.SetDefaultHook(
+				func(ctx context.Context, page int) (teams []*github.Team, hasNextPage bool, rateLimitCost int, err error) {
+					// No teams
+					return nil, false, 1, nil
+				})
+			mockClient.ListOrgRepositoriesFunc.SetDefaultHook(mockListOrgRepositories)
+
+			p := setupProvider(t, mockClient)
+
+			// The cached organization grants access to a repository it no longer has
+			// access to on the code host.
+			if err := p.groupsCache.setGroup(cachedGroup{
+				Org:          mockOrgRead.Login,
+				Repositories: []extsvc.RepoID{"stale"},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			sim := &authz.PermsSimulation{Repo: "stale"}
+			_, err := p.FetchUserPerms(authz.WithPermsSimulation(context.Background(), sim),
+				mockAccount,
+				authz.FetchPermsOptions{},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if decisions := sim.Decisions(); len(decisions) != 0 {
+				t.Fatalf("unexpected decisions: %v", decisions)
+			}
+			if len(mockClient.ListOrgRepositoriesFunc.History()) == 0 {
+				t.Fatal("expected the repositories of the organization to be listed from the code host")
+			}
+		})
+
 		t.Run("user in orgs and teams", func(t *testing.T) {
 			mockClient := newMockClientWithTokenMock()
 			mockClient.ListAffiliatedRepositoriesFunc.SetDefaultHook(mockListAffiliatedRepositories)
@@ -1276,6 +1351,36 @@ func TestProvider_ValidateConnection(t *testing.T) {
 	})
 }
 
+func TestProvider_InvalidateGroupsCache(t *testing.T) {
+	t.Run("cache disabled", func(t *testing.T) {
+		p := NewProvider("", ProviderOptions{GitHubURL: mustURL(t, "https://github.com"), GroupsCacheTTL: -1})
+		// Should not panic
+		p.InvalidateGroupsCache("org", "team")
+	})
+
+	p := NewProvider("", ProviderOptions{GitHubURL: mustURL(t, "https://github.com")})
+	p.groupsCache = memGroupsCache()
+	p.groupsCache.setGroup(cachedGroup{Org: "org"})
+	p.groupsCache.setGroup(cachedGroup{Org: "org", Team: "team-1"})
+	p.groupsCache.setGroup(cachedGroup{Org: "org", Team: "team-2"})
+
+	p.InvalidateGroupsCache("org", "team-1")
+	if _, found := p.groupsCache.getGroup("org", "team-1"); found {
+		t.Fatal("expected team-1 to be invalidated")
+	}
+	if _, found := p.groupsCache.getGroup("org", "team-2"); !found {
+		t.Fatal("expected team-2 to remain cached")
+	}
+	if _, found := p.groupsCache.getGroup("org", ""); !found {
+		t.Fatal("expected org to remain cached")
+	}
+
+	p.InvalidateGroupsCache("org", "")
+	if _, found := p.groupsCache.getGroup("org", ""); found {
+		t.Fatal("expected org to be invalidated")
+	}
+}
+
 func setupProvider(t *testing.T, mc *MockClient) *Provider {
 	p := NewProvider("", ProviderOptions{GitHubURL: mustURL(t, "https://github.com")})
 	p.client = mockClientFunc(mc)
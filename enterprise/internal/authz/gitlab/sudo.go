@@ -226,6 +226,8 @@ func listProjects(ctx context.Context, client *gitlab.Client) (*authz.ExternalUs
 	// when appending the first 100 results to the slice.
 	projectIDs := make([]extsvc.RepoID, 0, 100)
 
+	sim := authz.PermsSimulationFromContext(ctx)
+
 	// This method is meant to return only private or internal projects
 	for _, visibility := range []string{"private", "internal"} {
 		q.Set("visibility", visibility)
@@ -242,7 +244,11 @@ func listProjects(ctx context.Context, client *gitlab.Client) (*authz.ExternalUs
 			}
 
 			for _, p := range projects {
-				projectIDs = append(projectIDs, extsvc.RepoID(strconv.Itoa(p.ID)))
+				projectID := extsvc.RepoID(strconv.Itoa(p.ID))
+				if sim.IsRepo(projectID) {
+					sim.Grant("project %q is listed as a %s project the user has at least Reporter access to", p.PathWithNamespace, visibility)
+				}
+				projectIDs = append(projectIDs, projectID)
 			}
 
 			if next == nil {
@@ -294,6 +300,7 @@ func listMembers(ctx context.Context, client *gitlab.Client, repoID string) ([]e
 	// when appending the first 100 results to the slice.
 	userIDs := make([]extsvc.AccountID, 0, 100)
 
+	sim := authz.PermsSimulationFromContext(ctx)
 	for {
 		members, next, err := client.ListMembers(ctx, nextURL)
 		if err != nil {
@@ -301,12 +308,20 @@ func listMembers(ctx context.Context, client *gitlab.Client, repoID string) ([]e
 		}
 
 		for _, m := range members {
+			userID := extsvc.AccountID(strconv.Itoa(int(m.ID)))
+
 			// Members with access level 20 (i.e. Reporter) has access to project code.
 			if m.AccessLevel < 20 {
+				if sim.IsAccount(userID) {
+					sim.Deny("the user is a member of the project with access level %d, below Reporter (20)", m.AccessLevel)
+				}
 				continue
 			}
 
-			userIDs = append(userIDs, extsvc.AccountID(strconv.Itoa(int(m.ID))))
+			if sim.IsAccount(userID) {
+				sim.Grant("the user is a direct or inherited member of the project with access level %d", m.AccessLevel)
+			}
+			userIDs = append(userIDs, userID)
 		}
 
 		if next == nil {
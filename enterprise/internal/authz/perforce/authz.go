@@ -67,7 +67,9 @@ func newAuthzProvider(
 		}
 	}
 
-	return NewProvider(logger, urn, host, user, password, depotIDs, db), nil
+	p := NewProvider(logger, urn, host, user, password, depotIDs, db)
+	p.clientHost = a.ClientHost
+	return p, nil
 }
 
 // ValidateAuthz validates the authorization fields of the given Perforce
@@ -3,6 +3,7 @@ package perforce
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -46,9 +47,16 @@ type Provider struct {
 	cachedAllUserEmails   map[string]string // username -> email
 	emailsCacheLastUpdate time.Time
 
-	groupsCacheMutex      sync.RWMutex
-	cachedGroupMembers    map[string][]string // group -> members
-	groupsCacheLastUpdate time.Time
+	groupsCacheMutex         sync.RWMutex
+	cachedGroupMembers       map[string][]string // group -> users
+	cachedSubgroups          map[string][]string // group -> subgroups
+	groupsCacheLastUpdate    time.Time
+	cachedAllGroups          []string
+	allGroupsCacheLastUpdate time.Time
+
+	// clientHost is the address protection lines with a host field are evaluated
+	// against.
+	clientHost string
 }
 
 func cacheIsUpToDate(lastUpdate time.Time) bool {
@@ -75,6 +83,7 @@ func NewProvider(logger log.Logger, urn, host, user, password string, depots []e
 		password:           password,
 		p4Execer:           gitserver.NewClient(db),
 		cachedGroupMembers: make(map[string][]string),
+		cachedSubgroups:    make(map[string][]string),
 	}
 }
 
@@ -176,14 +185,20 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 	}
 	defer func() { _ = rc.Close() }()
 
+	// All lines returned by `p4 protects -u` apply to the user.
+	sim := authz.PermsSimulationFromContext(ctx)
+	appliesToUser := func(p4ProtectLine) (string, bool, error) { return "", true, nil }
+
 	// Pull permissions from protects file.
 	perms := &authz.ExternalUserPermissions{}
 	if len(p.depots) == 0 {
-		err = errors.Wrap(scanProtects(p.logger, rc, repoIncludesExcludesScanner(perms)), "repoIncludesExcludesScanner")
+		scanner := hostScanner(p.logger, p.clientHost, simulationScanner(sim, repoIncludesExcludesScanner(perms), appliesToUser))
+		err = errors.Wrap(scanProtects(p.logger, rc, scanner), "repoIncludesExcludesScanner")
 	} else {
 		// SubRepoPermissions-enabled code path
 		perms.SubRepoPermissions = make(map[extsvc.RepoID]*authz.SubRepoPermissions, len(p.depots))
-		err = errors.Wrap(scanProtects(p.logger, rc, fullRepoPermsScanner(p.logger, perms, p.depots)), "fullRepoPermsScanner")
+		scanner := hostScanner(p.logger, p.clientHost, simulationScanner(sim, fullRepoPermsScanner(p.logger, perms, p.depots), appliesToUser))
+		err = errors.Wrap(scanProtects(p.logger, rc, scanner), "fullRepoPermsScanner")
 	}
 
 	// As per interface definition for this method, implementation should return
@@ -193,7 +208,9 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 
 // getAllUserEmails returns a set of username -> email pairs of all users in the Perforce server.
 func (p *Provider) getAllUserEmails(ctx context.Context) (map[string]string, error) {
-	if p.cachedAllUserEmails != nil && cacheIsUpToDate(p.emailsCacheLastUpdate) {
+	// Caches are bypassed while permissions are only being simulated.
+	simulating := authz.IsPermsSimulation(ctx)
+	if !simulating && p.cachedAllUserEmails != nil && cacheIsUpToDate(p.emailsCacheLastUpdate) {
 		return p.cachedAllUserEmails, nil
 	}
 
@@ -215,6 +232,9 @@ func (p *Provider) getAllUserEmails(ctx context.Context) (map[string]string, err
 	if err = scanner.Err(); err != nil {
 		return nil, errors.Wrap(err, "scanner.Err")
 	}
+	if simulating {
+		return userEmails, nil
+	}
 
 	p.emailsCacheMutex.Lock()
 	defer p.emailsCacheMutex.Unlock()
@@ -241,51 +261,136 @@ func (p *Provider) getAllUsers(ctx context.Context) ([]string, error) {
 	return users, nil
 }
 
-// getGroupMembers returns all members of the given group in the Perforce server.
-func (p *Provider) getGroupMembers(ctx context.Context, group string) ([]string, error) {
-	if p.cachedGroupMembers[group] != nil && cacheIsUpToDate(p.groupsCacheLastUpdate) {
-		return p.cachedGroupMembers[group], nil
+// getAllGroups returns the names of all groups in the Perforce server.
+func (p *Provider) getAllGroups(ctx context.Context) ([]string, error) {
+	simulating := authz.IsPermsSimulation(ctx)
+	p.groupsCacheMutex.RLock()
+	if !simulating && p.cachedAllGroups != nil && cacheIsUpToDate(p.allGroupsCacheLastUpdate) {
+		defer p.groupsCacheMutex.RUnlock()
+		return p.cachedAllGroups, nil
+	}
+	p.groupsCacheMutex.RUnlock()
+
+	rc, _, err := p.p4Execer.P4Exec(ctx, p.host, p.user, p.password, "groups")
+	if err != nil {
+		return nil, errors.Wrap(err, "list groups")
+	}
+	defer func() { _ = rc.Close() }()
+
+	groups := []string{}
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		if group := strings.TrimSpace(scanner.Text()); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "scanner.Err")
+	}
+	if simulating {
+		return groups, nil
 	}
 
 	p.groupsCacheMutex.Lock()
 	defer p.groupsCacheMutex.Unlock()
+	p.cachedAllGroups = groups
+	p.allGroupsCacheLastUpdate = time.Now()
+	return p.cachedAllGroups, nil
+}
+
+// getGroup returns the users and subgroups that are direct members of the given
+// group in the Perforce server.
+func (p *Provider) getGroup(ctx context.Context, group string) (users, subgroups []string, err error) {
+	simulating := authz.IsPermsSimulation(ctx)
+	p.groupsCacheMutex.RLock()
+	if !simulating && p.cachedGroupMembers[group] != nil && cacheIsUpToDate(p.groupsCacheLastUpdate) {
+		defer p.groupsCacheMutex.RUnlock()
+		return p.cachedGroupMembers[group], p.cachedSubgroups[group], nil
+	}
+	p.groupsCacheMutex.RUnlock()
+
 	rc, _, err := p.p4Execer.P4Exec(ctx, p.host, p.user, p.password, "group", "-o", group)
 	if err != nil {
-		return nil, errors.Wrap(err, "list group members")
+		return nil, nil, errors.Wrap(err, "list group members")
 	}
 	defer func() { _ = rc.Close() }()
 
-	var members []string
-	startScan := false
+	// Members are listed as tab-indented lines below the "Users:" and "Subgroups:"
+	// fields of the group spec.
+	users = []string{}
+	var field *[]string
 	scanner := bufio.NewScanner(rc)
 	for scanner.Scan() {
 		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "Users:"):
+			field = &users
+		case strings.HasPrefix(line, "Subgroups:"):
+			field = &subgroups
+		case strings.HasPrefix(line, "\t") && field != nil:
+			*field = append(*field, strings.TrimSpace(line))
+		default:
+			field = nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "scanner.Err")
+	}
+	if simulating {
+		return users, subgroups, nil
+	}
+
+	p.groupsCacheMutex.Lock()
+	defer p.groupsCacheMutex.Unlock()
+	p.cachedGroupMembers[group] = users
+	p.cachedSubgroups[group] = subgroups
+	p.groupsCacheLastUpdate = time.Now()
+	return users, subgroups, nil
+}
 
-		// Only start scan when we encounter the "Users:" line
-		if !startScan {
-			if strings.HasPrefix(line, "Users:") {
-				startScan = true
+// getGroupMembers returns all members of the groups matching the given name in
+// the Perforce server, including members of their subgroups. The name may contain
+// wildcards.
+func (p *Provider) getGroupMembers(ctx context.Context, name string) ([]string, error) {
+	groups := []string{name}
+	if hasPerforceWildcard(name) {
+		all, err := p.getAllGroups(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "list all groups")
+		}
+		groups = groups[:0]
+		for _, group := range all {
+			if matchesName(name, group) {
+				groups = append(groups, group)
 			}
-			continue
 		}
+	}
 
-		// Lines for users always start with a tab "\t"
-		if !strings.HasPrefix(line, "\t") {
-			break
+	var members []string
+	seenGroups := make(map[string]struct{})
+	seenUsers := make(map[string]struct{})
+	for len(groups) > 0 {
+		group := groups[0]
+		groups = groups[1:]
+		// Subgroups may be nested in cycles.
+		if _, ok := seenGroups[group]; ok {
+			continue
 		}
+		seenGroups[group] = struct{}{}
 
-		members = append(members, strings.TrimSpace(line))
-	}
-	if err = scanner.Err(); err != nil {
-		return nil, errors.Wrap(err, "scanner.Err")
+		users, subgroups, err := p.getGroup(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if _, ok := seenUsers[user]; !ok {
+				seenUsers[user] = struct{}{}
+				members = append(members, user)
+			}
+		}
+		groups = append(groups, subgroups...)
 	}
-
-	// Drain remaining body
-	_, _ = io.Copy(io.Discard, rc)
-
-	p.cachedGroupMembers[group] = members
-	p.groupsCacheLastUpdate = time.Now()
-	return p.cachedGroupMembers[group], nil
+	return members, nil
 }
 
 // excludeGroupMembers excludes members of a given group from provided users map
@@ -295,9 +400,6 @@ func (p *Provider) excludeGroupMembers(ctx context.Context, group string, users
 		return errors.Wrapf(err, "list members of group %q", group)
 	}
 
-	p.groupsCacheMutex.RLock()
-	defer p.groupsCacheMutex.RUnlock()
-
 	for _, member := range members {
 		delete(users, member)
 	}
@@ -311,15 +413,35 @@ func (p *Provider) includeGroupMembers(ctx context.Context, group string, users
 		return errors.Wrapf(err, "list members of group %q", group)
 	}
 
-	p.groupsCacheMutex.RLock()
-	defer p.groupsCacheMutex.RUnlock()
-
 	for _, member := range members {
 		users[member] = struct{}{}
 	}
 	return nil
 }
 
+// getMatchingUsers returns the names of all users in the Perforce server that
+// match the given name, which may contain wildcards.
+func (p *Provider) getMatchingUsers(ctx context.Context, name string) ([]string, error) {
+	if !hasPerforceWildcard(name) {
+		return []string{name}, nil
+	}
+
+	all, err := p.getAllUsers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list all users")
+	}
+	if name == perforceWildcardMatchDirectory {
+		return all, nil
+	}
+	var users []string
+	for _, user := range all {
+		if matchesName(name, user) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
 // FetchRepoPerms returns a list of users that have access to the given
 // repository on the Perforce Server.
 func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, _ authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
@@ -344,7 +466,11 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 	defer func() { _ = rc.Close() }()
 
 	users := make(map[string]struct{})
-	if err := scanProtects(p.logger, rc, allUsersScanner(ctx, p, users)); err != nil {
+	scanner, err := p.repoPermsSimulationScanner(ctx, allUsersScanner(ctx, p, users))
+	if err != nil {
+		return nil, err
+	}
+	if err := scanProtects(p.logger, rc, hostScanner(p.logger, p.clientHost, scanner)); err != nil {
 		return nil, errors.Wrap(err, "scanning protects")
 	}
 
@@ -367,6 +493,54 @@ func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository,
 	return extIDs, nil
 }
 
+// repoPermsSimulationScanner wraps s to record the lines of `p4 protects -a` that
+// apply to the simulated user, either directly or through a group.
+func (p *Provider) repoPermsSimulationScanner(ctx context.Context, s *protectsScanner) (*protectsScanner, error) {
+	sim := authz.PermsSimulationFromContext(ctx)
+	if sim == nil {
+		return s, nil
+	}
+
+	// The account ID of Perforce users is their email.
+	userEmails, err := p.getAllUserEmails(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get all user emails")
+	}
+	var username string
+	p.emailsCacheMutex.RLock()
+	for name, email := range userEmails {
+		if sim.IsAccount(extsvc.AccountID(email)) {
+			username = name
+			break
+		}
+	}
+	p.emailsCacheMutex.RUnlock()
+	if username == "" {
+		sim.Deny("no Perforce user has the email %q", sim.Account)
+		return s, nil
+	}
+	sim.Info("the user is Perforce user %q", username)
+
+	return simulationScanner(sim, s, func(line p4ProtectLine) (string, bool, error) {
+		switch line.entityType {
+		case "user":
+			return "", matchesName(line.name, username), nil
+		case "group":
+			members, err := p.getGroupMembers(ctx, line.name)
+			if err != nil {
+				return "", false, errors.Wrapf(err, "list members of group %q", line.name)
+			}
+
+			for _, member := range members {
+				if member == username {
+					return fmt.Sprintf(" (via group %q)", line.name), true, nil
+				}
+			}
+		}
+		return "", false, nil
+	}), nil
+}
+
 func (p *Provider) ServiceType() string {
 	return p.codeHost.ServiceType
 }
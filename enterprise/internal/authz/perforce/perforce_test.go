@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	jsoniter "github.com/json-iterator/go"
@@ -180,11 +181,11 @@ review user alice * //Sourcegraph/.../Handbook/...
 			response: `
 list user alice * -//Sourcegraph/Security/...
 read user alice * -//Sourcegraph/Engineering/...
-owner user alice * -//Sourcegraph/Engineering/Backend/...
-open user alice * -//Sourcegraph/Engineering/Frontend/...
-review user alice * -//Sourcegraph/Handbook/...
-review user alice * -//Sourcegraph/*/Handbook/...
-review user alice * -//Sourcegraph/.../Handbook/...
+owner user alice * -//Sourcegraph/Engineering/Backend/...  ## "owner" can't revoke read access
+open user alice * -//Sourcegraph/Engineering/Frontend/...   ## "open" can't revoke read access
+review user alice * -//Sourcegraph/Handbook/...             ## "review" can't revoke read access
+read user alice * -//Sourcegraph/*/Handbook/...
+read user alice * -//Sourcegraph/.../Handbook/...
 `,
 			wantPerms: &authz.ExternalUserPermissions{
 				ExcludeContains: []extsvc.RepoID{
@@ -206,9 +207,10 @@ open user alice * //Sourcegraph/.../Handbook/...  ## wildcard A
 
 list user alice * -//Sourcegraph/Security/...                        ## "list" can revoke read access
 =read user alice * -//Sourcegraph/Engineering/Frontend/...           ## exact match of a previous include
-open user alice * -//Sourcegraph/Engineering/Backend/Credentials/... ## sub-match of a previous include
-open user alice * -//Sourcegraph/Engineering/*/Frontend/Folder/...   ## sub-match of a previous include
-open user alice * -//Sourcegraph/*/Handbook/...                      ## sub-match of wildcard A include
+read user alice * -//Sourcegraph/Engineering/Backend/Credentials/... ## sub-match of a previous include
+read user alice * -//Sourcegraph/Engineering/*/Frontend/Folder/...   ## sub-match of a previous include
+read user alice * -//Sourcegraph/*/Handbook/...                      ## sub-match of wildcard A include
+write user alice * -//Sourcegraph/Handbook/...                       ## "write" can't revoke read access
 `,
 			wantPerms: &authz.ExternalUserPermissions{
 				IncludeContains: []extsvc.RepoID{
@@ -240,9 +242,10 @@ open user alice * //Sourcegraph/.../Handbook/...  ## wildcard A
 
 list user alice * -//Sourcegraph/Security/...                        ## "list" can revoke read access
 =read user alice * -//Sourcegraph/Engineering/Frontend/...           ## exact match of a previous include
-open user alice * -//Sourcegraph/Engineering/Backend/Credentials/... ## sub-match of a previous include
-open user alice * -//Sourcegraph/Engineering/*/Frontend/Folder/...   ## sub-match of a previous include
-open user alice * -//Sourcegraph/*/Handbook/...                      ## sub-match of wildcard A include
+read user alice * -//Sourcegraph/Engineering/Backend/Credentials/... ## sub-match of a previous include
+read user alice * -//Sourcegraph/Engineering/*/Frontend/Folder/...   ## sub-match of a previous include
+read user alice * -//Sourcegraph/*/Handbook/...                      ## sub-match of wildcard A include
+write user alice * -//Sourcegraph/Handbook/...                       ## "write" can't revoke read access
 
 read user alice * //Sourcegraph/Security/... 						 ## give access to alice again after revoking
 `,
@@ -337,6 +340,45 @@ read user alice * -//Sourcegraph/Security/...
 			t.Fatalf("Mismatch (-want +got):\n%s", diff)
 		}
 	})
+
+	t.Run("simulation", func(t *testing.T) {
+		logger := logtest.Scoped(t)
+		execer := p4ExecFunc(func(ctx context.Context, host, user, password string, args ...string) (io.ReadCloser, http.Header, error) {
+			return io.NopCloser(strings.NewReader(`
+read user alice * //Sourcegraph/...
+read user alice * -//Sourcegraph/Engineering/...
+read user alice * //Sourcegraph/Engineering/Docs/...
+read user alice * //Sourcegraph/Handbook/...
+`)), nil, nil
+		})
+		p := NewTestProvider(logger, "", "ssl:111.222.333.444:1666", "admin", "password", execer)
+
+		sim := &authz.PermsSimulation{Repo: "//Sourcegraph/Engineering/"}
+		_, err := p.FetchUserPerms(authz.WithPermsSimulation(ctx, sim),
+			&extsvc.Account{
+				AccountSpec: extsvc.AccountSpec{
+					ServiceType: extsvc.TypePerforce,
+					ServiceID:   "ssl:111.222.333.444:1666",
+				},
+				AccountData: extsvc.AccountData{
+					Data: extsvc.NewUnencryptedData(accountData),
+				},
+			},
+			authz.FetchPermsOptions{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []authz.PermsDecision{
+			{Effect: authz.PermsDecisionGrant, Reason: `protects line 2 "read user alice //Sourcegraph/..." grants access to the depot`},
+			{Effect: authz.PermsDecisionDeny, Reason: `protects line 3 "read user alice -//Sourcegraph/Engineering/..." revokes access to the depot`},
+			{Effect: authz.PermsDecisionInfo, Reason: `protects line 4 "read user alice //Sourcegraph/Engineering/Docs/..." applies to paths within the depot (sub-repository permissions)`},
+		}
+		if diff := cmp.Diff(want, sim.Decisions()); diff != "" {
+			t.Fatalf("Mismatch (-want +got):\n%s", diff)
+		}
+	})
 }
 
 func TestProvider_FetchRepoPerms(t *testing.T) {
@@ -385,7 +427,8 @@ write user alice * //Sourcegraph/...
 write user bob * //Sourcegraph/...
 admin group Backend * //Sourcegraph/...   ## includes "alice" and "cindy"
 
-admin group Frontend * -//Sourcegraph/... ## excludes "bob", "david" and "frank"
+admin group Frontend * -//Sourcegraph/... ## only revokes admin access
+read group Frontend * -//Sourcegraph/...  ## excludes "bob", "david" and "frank"
 read user cindy * -//Sourcegraph/...
 
 list user david * //Sourcegraph/...       ## "list" can't grant read access
@@ -438,6 +481,35 @@ Users:
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Fatalf("Mismatch (-want +got):\n%s", diff)
 	}
+
+	t.Run("simulation", func(t *testing.T) {
+		p := NewTestProvider(logger, "", "ssl:111.222.333.444:1666", "admin", "password", execer)
+		sim := &authz.PermsSimulation{Repo: "//Sourcegraph/", Account: "bob@example.com"}
+		_, err := p.FetchRepoPerms(authz.WithPermsSimulation(ctx, sim),
+			&extsvc.Repository{
+				URI: "gitlab.com/user/repo",
+				ExternalRepoSpec: api.ExternalRepoSpec{
+					ID:          "//Sourcegraph/",
+					ServiceType: extsvc.TypePerforce,
+					ServiceID:   "ssl:111.222.333.444:1666",
+				},
+			},
+			authz.FetchPermsOptions{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []authz.PermsDecision{
+			{Effect: authz.PermsDecisionInfo, Reason: `the user is Perforce user "bob"`},
+			{Effect: authz.PermsDecisionDeny, Reason: `protects line 3 "list user * -//..." revokes access to the depot`},
+			{Effect: authz.PermsDecisionGrant, Reason: `protects line 5 "write user bob //Sourcegraph/..." grants access to the depot`},
+			{Effect: authz.PermsDecisionDeny, Reason: `protects line 9 "read group Frontend -//Sourcegraph/..." (via group "Frontend") revokes access to the depot`},
+		}
+		if diff := cmp.Diff(want, sim.Decisions()); diff != "" {
+			t.Fatalf("Mismatch (-want +got):\n%s", diff)
+		}
+	})
 }
 
 func NewTestProvider(logger log.Logger, urn, host, user, password string, execer p4Execer) *Provider {
@@ -451,3 +523,49 @@ type p4ExecFunc func(ctx context.Context, host, user, password string, args ...s
 func (p p4ExecFunc) P4Exec(ctx context.Context, host, user, password string, args ...string) (io.ReadCloser, http.Header, error) {
 	return p(ctx, host, user, password, args...)
 }
+
+func TestProvider_getGroup(t *testing.T) {
+	logger := logtest.Scoped(t)
+
+	execs := 0
+	execer := p4ExecFunc(func(ctx context.Context, host, user, password string, args ...string) (io.ReadCloser, http.Header, error) {
+		execs++
+		return io.NopCloser(strings.NewReader("Users:\n\talice\n")), nil, nil
+	})
+
+	p := NewTestProvider(logger, "", "ssl:111.222.333.444:1666", "admin", "password", execer)
+	p.cachedGroupMembers["dev"] = []string{"stale"}
+	p.groupsCacheLastUpdate = time.Now()
+
+	t.Run("cached", func(t *testing.T) {
+		users, _, err := p.getGroup(context.Background(), "dev")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"stale"}, users); diff != "" {
+			t.Fatalf("users mismatch (-want +got):\n%s", diff)
+		}
+		if execs != 0 {
+			t.Fatalf("unexpected p4 executions: %d", execs)
+		}
+	})
+
+	t.Run("simulation bypasses the cache", func(t *testing.T) {
+		ctx := authz.WithPermsSimulation(context.Background(), &authz.PermsSimulation{})
+		users, _, err := p.getGroup(ctx, "dev")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"alice"}, users); diff != "" {
+			t.Fatalf("users mismatch (-want +got):\n%s", diff)
+		}
+		if execs != 1 {
+			t.Fatalf("want 1 p4 execution but got %d", execs)
+		}
+
+		// Nothing is persisted while simulating
+		if diff := cmp.Diff([]string{"stale"}, p.cachedGroupMembers["dev"]); diff != "" {
+			t.Fatalf("cached users mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"strings"
 
 	"github.com/gobwas/glob"
@@ -22,40 +23,49 @@ type p4ProtectLine struct {
 	level      string // e.g. read
 	entityType string // e.g. user
 	name       string // e.g. alice
+	host       string // e.g. *
 	match      string // raw match, e.g. //Sourcegraph/, trimmed of leading '-' for exclusion
 
 	// isExclusion is whether the match is an exclusion or inclusion (had a leading '-' or not)
 	// which indicates access should be revoked
 	isExclusion bool
+
+	// lineNumber is the 1-based position of the line in the output of `p4 protects`
+	lineNumber int
+}
+
+// String returns the line as it appeared in `p4 protects`, without the host field.
+func (p *p4ProtectLine) String() string {
+	match := p.match
+	if p.isExclusion {
+		match = "-" + match
+	}
+	return fmt.Sprintf("%s %s %s %s", p.level, p.entityType, p.name, match)
 }
 
 // revokesReadAccess returns true if the line's access level is able to revoke
-// read account for a depot prefix.
+// read account for a depot prefix. An exclusion only revokes the given level and
+// the levels above it, e.g. "write user alice * -//depot/..." revokes write
+// access but leaves read access untouched, so only exclusions at the list and
+// read levels, or of the read right, revoke read access.
 func (p *p4ProtectLine) revokesReadAccess() bool {
 	_, canRevokeReadAccess := map[string]struct{}{
-		"list":   {},
-		"read":   {},
-		"=read":  {},
-		"open":   {},
-		"write":  {},
-		"review": {},
-		"owner":  {},
-		"admin":  {},
-		"super":  {},
+		"list":  {},
+		"read":  {},
+		"=read": {},
 	}[p.level]
 	return canRevokeReadAccess
 }
 
 // grantsReadAccess returns true if the line's access level is able to grant
-// read account for a depot prefix.
+// read account for a depot prefix. Unlike levels, rights such as "=open" only
+// grant the specific right and not the rights below it.
 func (p *p4ProtectLine) grantsReadAccess() bool {
 	_, canGrantReadAccess := map[string]struct{}{
 		"read":   {},
 		"=read":  {},
 		"open":   {},
-		"=open":  {},
 		"write":  {},
-		"=write": {},
 		"review": {},
 		"owner":  {},
 		"admin":  {},
@@ -70,6 +80,58 @@ func (p *p4ProtectLine) affectsReadAccess() bool {
 		(!p.isExclusion && p.grantsReadAccess())
 }
 
+// appliesToHost returns true if the line applies to connections from clientHost.
+// Host filtering is opt-in: if clientHost is unknown, the host field is ignored
+// and every line applies.
+func (p *p4ProtectLine) appliesToHost(clientHost string) bool {
+	if clientHost == "" {
+		return true
+	}
+	return matchesHost(p.host, clientHost)
+}
+
+// matchesHost returns true if the host field of a protection line matches the
+// given client host. See:
+//   - https://www.perforce.com/manuals/p4sag/Content/P4SAG/protections.hosts.html
+func matchesHost(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	// Lines for connections through a proxy or broker only match clients that
+	// connect through one, and vice versa.
+	const proxyPrefix = "proxy-"
+	if strings.HasPrefix(pattern, proxyPrefix) != strings.HasPrefix(host, proxyPrefix) {
+		return false
+	}
+	pattern = strings.Trim(strings.TrimPrefix(pattern, proxyPrefix), "[]")
+	host = strings.Trim(strings.TrimPrefix(host, proxyPrefix), "[]")
+
+	if _, network, err := net.ParseCIDR(pattern); err == nil {
+		ip := net.ParseIP(host)
+		return ip != nil && network.Contains(ip)
+	}
+	if strings.Contains(pattern, "*") {
+		// Host wildcards match any characters, including dots and colons.
+		g, err := glob.Compile(strings.ReplaceAll(glob.QuoteMeta(pattern), `\*`, "*"))
+		return err == nil && g.Match(host)
+	}
+	if patternIP, hostIP := net.ParseIP(pattern), net.ParseIP(host); patternIP != nil && hostIP != nil {
+		return patternIP.Equal(hostIP)
+	}
+	return pattern == host
+}
+
+// matchesName returns true if the name field of a protection line, which may
+// contain wildcards, matches the given user or group name.
+func matchesName(pattern, name string) bool {
+	if !hasPerforceWildcard(pattern) {
+		return pattern == name
+	}
+	g, err := convertToGlobMatch(pattern)
+	return err == nil && g.Match(name)
+}
+
 // Perforce wildcards file match syntax, specifically Helix server wildcards. This is the format
 // we expect to get back from p4 protects.
 //
@@ -202,14 +264,34 @@ type protectsScanner struct {
 	finalize func() error
 }
 
+// hostScanner wraps s to skip the lines of `p4 protects` that do not apply to
+// connections from clientHost.
+func hostScanner(logger log.Logger, clientHost string, s *protectsScanner) *protectsScanner {
+	logger = logger.Scoped("hostScanner", "")
+	return &protectsScanner{
+		processLine: func(line p4ProtectLine) error {
+			if !line.appliesToHost(clientHost) {
+				logger.Debug("Line does not apply to client host, discarding",
+					log.String("line.host", line.host),
+					log.String("clientHost", clientHost))
+				return nil
+			}
+			return s.processLine(line)
+		},
+		finalize: s.finalize,
+	}
+}
+
 // scanProtects is a utility function for processing values from `p4 protects`.
 // It handles skipping comments, cleaning whitespace, parsing relevant fields, and
 // skipping entries that do not affect read access.
 func scanProtects(logger log.Logger, rc io.Reader, s *protectsScanner) error {
 	logger = logger.Scoped("scanProtects", "")
 	scanner := bufio.NewScanner(rc)
+	lineNumber := 0
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNumber++
 
 		// Skip comments
 		if strings.HasPrefix(line, "##") {
@@ -239,7 +321,9 @@ func scanProtects(logger log.Logger, rc io.Reader, s *protectsScanner) error {
 			level:      fields[0],
 			entityType: fields[1],
 			name:       fields[2],
+			host:       fields[3],
 			match:      fields[4],
+			lineNumber: lineNumber,
 		}
 		if strings.HasPrefix(parsedLine.match, "-") {
 			parsedLine.isExclusion = true                                // is an exclusion
@@ -525,6 +609,62 @@ func convertRulesForWildcardDepotMatch(match globMatch, depot extsvc.RepoID, pat
 	return newRules
 }
 
+// simulationScanner wraps s to record the lines of `p4 protects` that affect access
+// to the simulated depot. appliesToUser reports whether a line applies to the
+// simulated user, and if so, how (e.g. through a group). If no simulation is in
+// progress, s is returned as is.
+func simulationScanner(sim *authz.PermsSimulation, s *protectsScanner, appliesToUser func(p4ProtectLine) (via string, ok bool, err error)) *protectsScanner {
+	if sim == nil {
+		return s
+	}
+
+	depot := string(sim.Repo)
+	depotMatch, err := convertToGlobMatch(depot + perforceWildcardMatchAll)
+	if err != nil {
+		sim.Info("unable to convert depot %q to a pattern: %s", depot, err)
+		return s
+	}
+
+	return &protectsScanner{
+		processLine: func(line p4ProtectLine) error {
+			if err := s.processLine(line); err != nil {
+				return err
+			}
+
+			match, err := convertToGlobMatch(line.match)
+			if err != nil {
+				return nil
+			}
+
+			// Lines that cover the depot as a whole grant or deny access to the
+			// repository, lines that only match paths within the depot become
+			// sub-repository permissions.
+			coversDepot := match.Match(depot)
+			if !coversDepot && !matchesAgainstDepot(match, depot) && !depotMatch.Match(line.match) {
+				return nil
+			}
+
+			via, ok, err := appliesToUser(line)
+			if err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+
+			switch {
+			case !coversDepot:
+				sim.Info("protects line %d %q%s applies to paths within the depot (sub-repository permissions)", line.lineNumber, line.String(), via)
+			case line.isExclusion:
+				sim.Deny("protects line %d %q%s revokes access to the depot", line.lineNumber, line.String(), via)
+			default:
+				sim.Grant("protects line %d %q%s grants access to the depot", line.lineNumber, line.String(), via)
+			}
+			return nil
+		},
+		finalize: s.finalize,
+	}
+}
+
 // allUsersScanner converts `p4 protects` to a map of users within the protection rules.
 func allUsersScanner(ctx context.Context, p *Provider, users map[string]struct{}) *protectsScanner {
 	logger := log.Scoped("allUsersScanner", "")
@@ -538,7 +678,11 @@ func allUsersScanner(ctx context.Context, p *Provider, users map[string]struct{}
 							delete(users, u)
 						}
 					} else {
-						delete(users, line.name)
+						for u := range users {
+							if matchesName(line.name, u) {
+								delete(users, u)
+							}
+						}
 					}
 				case "group":
 					if err := p.excludeGroupMembers(ctx, line.name, users); err != nil {
@@ -553,16 +697,12 @@ func allUsersScanner(ctx context.Context, p *Provider, users map[string]struct{}
 
 			switch line.entityType {
 			case "user":
-				if line.name == "*" {
-					all, err := p.getAllUsers(ctx)
-					if err != nil {
-						return errors.Wrap(err, "list all users")
-					}
-					for _, user := range all {
-						users[user] = struct{}{}
-					}
-				} else {
-					users[line.name] = struct{}{}
+				matching, err := p.getMatchingUsers(ctx, line.name)
+				if err != nil {
+					return err
+				}
+				for _, user := range matching {
+					users[user] = struct{}{}
 				}
 			case "group":
 				if err := p.includeGroupMembers(ctx, line.name, users); err != nil {
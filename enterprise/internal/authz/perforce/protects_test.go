@@ -557,6 +557,26 @@ read    group   Dev1    *   //depot/main/dir/file.java
 `,
 			canReadAll: []string{"dir/file.java", "dir/"},
 		},
+		{
+			name:          "Rights only grant and revoke the specific right",
+			depot:         "//depot/main/",
+			protectsFile:  "testdata/sample-protects-rights.txt",
+			canReadAll:    []string{"src/main.go", "src/generated/types.go", "src/vendor/lib.go", "docs/README.md"},
+			cannotReadAny: []string{"open-only/main.go", "write-only/main.go", "branch-only/main.go", "src/secrets/key.pem"},
+		},
+		{
+			name:          "Only exclusions of the list and read levels revoke read access",
+			depot:         "//depot/main/",
+			protectsFile:  "testdata/sample-protects-exclusion-levels.txt",
+			canReadAll:    []string{"src/main.go", "open/main.go", "write/main.go", "review/main.go", "owner/main.go", "admin/main.go", "super/main.go"},
+			cannotReadAny: []string{"list/main.go", "read/main.go", "read-right/main.go"},
+		},
+		{
+			name:         "Excluding a level above read from a whole depot keeps read access",
+			depot:        "//depot/main/",
+			protectsFile: "testdata/sample-protects-exclusion-levels-depot.txt",
+			canReadAll:   []string{"src/main.go", "README.md"},
+		},
 		{
 			// Should still be able to browse directories
 			name:  "Rules start with wildcard",
@@ -693,7 +713,6 @@ func TestFullScanWildcardDepotMatching(t *testing.T) {
 					mustGlobPattern(t, "-/**"),
 					mustGlobPattern(t, "-/**/base/build/deleteorgs.txt"),
 					mustGlobPattern(t, "-/build/deleteorgs.txt"),
-					mustGlobPattern(t, "-/asdf/plsql/base/cCustomSchema*.sql"),
 					mustGlobPattern(t, "/db/upgrade-scripts/**"),
 					mustGlobPattern(t, "/db/my_db/upgrade-scripts/**"),
 					mustGlobPattern(t, "/asdf/config/my_schema.xml"),
@@ -843,3 +862,186 @@ func TestScanAllUsers(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func TestMatchesHost(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		host    string
+		want    bool
+	}{
+		{pattern: "*", host: "10.0.0.12", want: true},
+		{pattern: "*", host: "proxy-10.0.0.12", want: true},
+		{pattern: "10.0.0.12", host: "10.0.0.12", want: true},
+		{pattern: "10.0.0.12", host: "10.0.0.13", want: false},
+		{pattern: "10.0.0.*", host: "10.0.0.12", want: true},
+		{pattern: "10.0.*", host: "10.0.1.12", want: true},
+		{pattern: "10.0.0.*", host: "10.0.1.12", want: false},
+		{pattern: "10.0.0.0/16", host: "10.0.255.1", want: true},
+		{pattern: "10.0.0.0/16", host: "10.1.0.1", want: false},
+		{pattern: "2001:db8::/32", host: "2001:db8::1", want: true},
+		{pattern: "[2001:db8::1]", host: "2001:db8:0:0:0:0:0:1", want: true},
+		{pattern: "proxy-*", host: "10.0.0.12", want: false},
+		{pattern: "proxy-10.0.0.*", host: "proxy-10.0.0.12", want: true},
+		{pattern: "10.0.0.*", host: "proxy-10.0.0.12", want: false},
+	} {
+		t.Run(fmt.Sprintf("%s %s", tc.pattern, tc.host), func(t *testing.T) {
+			if got := matchesHost(tc.pattern, tc.host); got != tc.want {
+				t.Fatalf("want %v but got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestScanProtectsHosts(t *testing.T) {
+	logger := logtest.Scoped(t)
+	data, err := os.ReadFile("testdata/sample-protects-hosts.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// See sample-protects-hosts.txt for notes
+	for _, tc := range []struct {
+		clientHost string
+		want       *authz.ExternalUserPermissions
+	}{
+		{
+			clientHost: "10.0.0.12",
+			want: &authz.ExternalUserPermissions{
+				IncludeContains: []extsvc.RepoID{"//depot/any/%", "//depot/office/%", "//depot/cidr/%", "//depot/exact/%"},
+			},
+		},
+		{
+			clientHost: "192.168.1.5",
+			want: &authz.ExternalUserPermissions{
+				IncludeContains: []extsvc.RepoID{"//depot/any/%"},
+				ExcludeContains: []extsvc.RepoID{"//depot/any/secret/%"},
+			},
+		},
+		{
+			clientHost: "proxy-10.0.0.12",
+			want: &authz.ExternalUserPermissions{
+				IncludeContains: []extsvc.RepoID{"//depot/any/%", "//depot/proxy/%"},
+			},
+		},
+		{
+			// The host field is ignored if the client host is unknown
+			clientHost: "",
+			want: &authz.ExternalUserPermissions{
+				IncludeContains: []extsvc.RepoID{"//depot/any/%", "//depot/office/%", "//depot/cidr/%", "//depot/exact/%", "//depot/proxy/%"},
+				ExcludeContains: []extsvc.RepoID{"//depot/any/secret/%"},
+			},
+		},
+	} {
+		t.Run(tc.clientHost, func(t *testing.T) {
+			perms := &authz.ExternalUserPermissions{}
+			scanner := hostScanner(logger, tc.clientHost, repoIncludesExcludesScanner(perms))
+			if err := scanProtects(logger, bytes.NewReader(data), scanner); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, perms); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestScanAllUsersNestedGroups(t *testing.T) {
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+	protects, err := os.ReadFile("testdata/sample-protects-groups.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	execer := p4ExecFunc(func(ctx context.Context, host, user, password string, args ...string) (io.ReadCloser, http.Header, error) {
+		var data string
+		switch args[0] {
+		case "groups":
+			data = `
+engineering
+backend
+frontend
+design
+contractors-eu
+contractors-us
+`
+		case "users":
+			data = `
+alice <alice@example.com> (Alice) accessed 2020/12/04
+bob <bob@example.com> (Bob) accessed 2020/12/04
+carol <carol@example.com> (Carol) accessed 2020/12/04
+dave <dave@example.com> (Dave) accessed 2020/12/04
+eve <eve@example.com> (Eve) accessed 2020/12/04
+frank <frank@example.com> (Frank) accessed 2020/12/04
+lead <lead@example.com> (Lead) accessed 2020/12/04
+ops-alice <ops-alice@example.com> (Ops Alice) accessed 2020/12/04
+ops-bob <ops-bob@example.com> (Ops Bob) accessed 2020/12/04
+`
+		case "group":
+			data = map[string]string{
+				"engineering": `
+Group:	engineering
+
+Subgroups:
+	backend
+	frontend
+
+Owners:
+	lead
+
+Users:
+	eve
+`,
+				"backend": `
+Users:
+	alice
+	bob
+`,
+				"frontend": `
+Subgroups:
+	design
+
+Users:
+	carol
+`,
+				"design": `
+Subgroups:
+	frontend
+
+Users:
+	dave
+`,
+				"contractors-eu": `
+Users:
+	bob
+`,
+				"contractors-us": `
+Users:
+	frank
+`,
+			}[args[2]]
+		default:
+			t.Fatalf("unexpected command %q", args)
+		}
+		return io.NopCloser(strings.NewReader(data)), nil, nil
+	})
+
+	p := NewTestProvider(logger, "", "ssl:111.222.333.444:1666", "admin", "password", execer)
+	users := make(map[string]struct{})
+	if err := scanProtects(logger, bytes.NewReader(protects), allUsersScanner(ctx, p, users)); err != nil {
+		t.Fatal(err)
+	}
+
+	// See sample-protects-groups.txt for notes
+	want := map[string]struct{}{
+		"alice":     {},
+		"carol":     {},
+		"dave":      {},
+		"eve":       {},
+		"ops-alice": {},
+		"ops-bob":   {},
+	}
+	if diff := cmp.Diff(want, users); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -58,10 +58,12 @@ var indexColumnsWithNullRank = []*sqlf.Query{
 	sqlf.Sprintf(`u.local_steps`),
 	sqlf.Sprintf(`(SELECT MAX(id) FROM lsif_uploads WHERE associated_index_id = u.id) AS associated_upload_id`),
 	sqlf.Sprintf(`u.should_reindex`),
+	sqlf.Sprintf(`u.caches`),
 }
 
 func scanIndex(s dbutil.Scanner) (index types.Index, err error) {
 	var executionLogs []workerutil.ExecutionLogEntry
+	var caches []types.IndexCache
 	if err := s.Scan(
 		&index.ID,
 		&index.Commit,
@@ -85,11 +87,13 @@ func scanIndex(s dbutil.Scanner) (index types.Index, err error) {
 		pq.Array(&index.LocalSteps),
 		&index.AssociatedUploadID,
 		&index.ShouldReindex,
+		pq.Array(&caches),
 	); err != nil {
 		return index, err
 	}
 
 	index.ExecutionLogs = append(index.ExecutionLogs, executionLogs...)
+	index.Caches = append(index.Caches, caches...)
 
 	return index, nil
 }
@@ -0,0 +1,100 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/internal/inference/libs"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestDotnetGenerator(t *testing.T) {
+	expectedIndexerImage, _ := libs.DefaultIndexerForLang("dotnet")
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "solution files",
+			repositoryContents: map[string]string{
+				"App.sln":                      "",
+				"src/App/App.csproj":           "",
+				"src/App.Core/App.Core.csproj": "",
+				"tools/Tools.sln":              "",
+				"tools/Gen/Gen.csproj":         "",
+				"tests/App.Tests/Tests.csproj": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps: []config.DockerStep{
+						{
+							Root:     "",
+							Image:    expectedIndexerImage,
+							Commands: []string{"dotnet restore"},
+						},
+					},
+					LocalSteps:  nil,
+					Root:        "",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-dotnet", "index"},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps: []config.DockerStep{
+						{
+							Root:     "tools",
+							Image:    expectedIndexerImage,
+							Commands: []string{"dotnet restore"},
+						},
+					},
+					LocalSteps:  nil,
+					Root:        "tools",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-dotnet", "index"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "project files without solution",
+			repositoryContents: map[string]string{
+				"foo/Foo.csproj": "",
+				"bar/Bar.csproj": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps: []config.DockerStep{
+						{
+							Root:     "bar",
+							Image:    expectedIndexerImage,
+							Commands: []string{"dotnet restore"},
+						},
+					},
+					LocalSteps:  nil,
+					Root:        "bar",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-dotnet", "index"},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps: []config.DockerStep{
+						{
+							Root:     "foo",
+							Image:    expectedIndexerImage,
+							Commands: []string{"dotnet restore"},
+						},
+					},
+					LocalSteps:  nil,
+					Root:        "foo",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-dotnet", "index"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "c# files without project (no match)",
+			repositoryContents: map[string]string{
+				"src/Program.cs": "",
+			},
+			expected: []config.IndexJob{},
+		},
+	)
+}
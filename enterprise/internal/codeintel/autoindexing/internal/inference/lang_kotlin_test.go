@@ -0,0 +1,82 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/internal/inference/libs"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestKotlinGenerator(t *testing.T) {
+	expectedIndexerImage, _ := libs.DefaultIndexerForLang("java")
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "gradle kotlin builds",
+			repositoryContents: map[string]string{
+				"settings.gradle.kts":               "",
+				"build.gradle.kts":                  "",
+				"app/build.gradle.kts":              "",
+				"lib/build.gradle.kts":              "",
+				"app/src/main/kotlin/App.kt":        "",
+				"samples/other/build.gradle.kts":    "",
+				"samples/other/settings.gradle.kts": "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=gradle"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "nested gradle kotlin builds",
+			repositoryContents: map[string]string{
+				"server/settings.gradle.kts":  "",
+				"server/api/build.gradle.kts": "",
+				"android/build.gradle.kts":    "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "android",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=gradle"},
+					Outfile:     "index.scip",
+				},
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "server",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=gradle"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+		generatorTestCase{
+			description: "gradle kotlin build with lsif-java.json",
+			repositoryContents: map[string]string{
+				"lsif-java.json":       "",
+				"build.gradle.kts":     "",
+				"app/build.gradle.kts": "",
+				"app/src/App.kt":       "",
+			},
+			expected: []config.IndexJob{
+				{
+					Steps:       nil,
+					LocalSteps:  nil,
+					Root:        "",
+					Indexer:     expectedIndexerImage,
+					IndexerArgs: []string{"scip-java", "index", "--build-tool=scip"},
+					Outfile:     "index.scip",
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,57 @@
+package inference
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindexing/internal/inference/libs"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestLSPGenerator(t *testing.T) {
+	expectedIndexerImage, _ := libs.DefaultIndexerForLang("lsp")
+	expectedIndexerArgs := []string{
+		"scip-lsp",
+		"-language-id", "elixir",
+		"-extensions", ".ex,.exs",
+		"-output", "index.scip",
+		"--", "elixir-ls",
+	}
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "scip-lsp",
+			overrideScript: `
+				local lsp = require("sg.autoindex.lsp")
+
+				return require("sg.autoindex.config").new({
+					["custom.elixir"] = lsp.new_recognizer {
+						language_id = "elixir",
+						project_files = { "mix.exs" },
+						extensions = { "ex", ".exs" },
+						server = { "elixir-ls" },
+						install = { "apk add --no-cache elixir-ls" },
+					},
+				})
+			`,
+			repositoryContents: map[string]string{
+				"mix.exs":        "",
+				"apps/a/mix.exs": "",
+				"apps/b/mix.exs": "",
+			},
+			expected: func() []config.IndexJob {
+				var out []config.IndexJob
+				for _, root := range []string{"", "apps/a", "apps/b"} {
+					out = append(out, config.IndexJob{
+						Steps:       nil,
+						LocalSteps:  []string{"apk add --no-cache elixir-ls"},
+						Root:        root,
+						Indexer:     expectedIndexerImage,
+						IndexerArgs: expectedIndexerArgs,
+						Outfile:     "index.scip",
+					})
+				}
+				return out
+			}(),
+		},
+	)
+}
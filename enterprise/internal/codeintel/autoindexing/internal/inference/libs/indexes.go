@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/luasandbox/util"
+	"github.com/sourcegraph/sourcegraph/internal/version"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -17,15 +18,19 @@ type indexesAPI struct{}
 
 var defaultIndexers = map[string]string{
 	"clang":      "sourcegraph/lsif-clang",
+	"dotnet":     "sourcegraph/scip-dotnet",
 	"go":         "sourcegraph/lsif-go",
 	"java":       "sourcegraph/scip-java",
+	"lsp":        "sourcegraph/scip-lsp",
+	"php":        "sourcegraph/scip-php",
 	"python":     "sourcegraph/scip-python",
 	"rust":       "sourcegraph/lsif-rust",
 	"typescript": "sourcegraph/scip-typescript",
 	"ruby":       "sourcegraph/scip-ruby",
 }
 
-// To update, run `DOCKER_USER=... DOCKER_PASS=... ./update-shas.sh`
+// To update, run `DOCKER_USER=... DOCKER_PASS=... ./update-shas.sh [indexer...]`. Indexers listed
+// in versionedIndexers are not pinned.
 var defaultIndexerSHAs = map[string]string{
 	"sourcegraph/lsif-clang":      "sha256:5ef2334ac9d58f1f947651812aa8d8ba0ed584913f2429cc9952cb25f94976d8",
 	"sourcegraph/lsif-go":         "sha256:cba76f5b3edb5d9af43e1dc59e27ecdb4b8b2fafda6a5d55d7e37def3b502775",
@@ -34,6 +39,15 @@ var defaultIndexerSHAs = map[string]string{
 	"sourcegraph/scip-python":     "sha256:5049c4598d03af542bde5e1254a17fa6d1eb794c1bdd14d0162fb39c604581b4",
 	"sourcegraph/scip-typescript": "sha256:37546e04763d6d1853fb6f32285ad630fc0d8671d4f1d2db40c6df272120f2f8",
 	"sourcegraph/scip-ruby":       "sha256:1e7538eead787a9a220e54c442eaf10372f3f41d2be2871713e6ec367bd40f81",
+	// TODO: pin with `./update-shas.sh scip-dotnet scip-php`; until then these resolve to `latest`.
+	"sourcegraph/scip-dotnet": "",
+	"sourcegraph/scip-php":    "",
+}
+
+// versionedIndexers are built and published with Sourcegraph itself, so they are tagged with
+// the version of the running instance rather than pinned to a digest.
+var versionedIndexers = map[string]struct{}{
+	"sourcegraph/scip-lsp": {},
 }
 
 func DefaultIndexerForLang(language string) (string, bool) {
@@ -42,14 +56,31 @@ func DefaultIndexerForLang(language string) (string, bool) {
 		return "", false
 	}
 
+	if _, ok := versionedIndexers[indexer]; ok {
+		return fmt.Sprintf("%s:%s", indexer, versionedIndexerTag()), true
+	}
+
 	sha, ok := defaultIndexerSHAs[indexer]
 	if !ok {
 		panic(fmt.Sprintf("no SHA set for indexer %q", indexer))
 	}
+	if sha == "" {
+		return fmt.Sprintf("%s:latest", indexer), true
+	}
 
 	return fmt.Sprintf("%s@%s", indexer, sha), true
 }
 
+func versionedIndexerTag() string {
+	tag := version.Version()
+	// In dev, just use insiders for convenience.
+	if version.IsDev(tag) {
+		tag = "insiders"
+	}
+
+	return tag
+}
+
 func (api indexesAPI) LuaAPI() map[string]lua.LGFunction {
 	return map[string]lua.LGFunction{
 		"get": util.WrapLuaFunction(func(state *lua.LState) error {
@@ -0,0 +1,51 @@
+package libs
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/version"
+)
+
+func TestDefaultIndexerForLangVersioned(t *testing.T) {
+	t.Cleanup(func() { version.Mock("0.0.0+dev") })
+
+	for _, testCase := range []struct {
+		version  string
+		expected string
+	}{
+		{"0.0.0+dev", "sourcegraph/scip-lsp:insiders"},
+		{"4.4.0", "sourcegraph/scip-lsp:4.4.0"},
+		{"203471_2023-01-20_4.4-9d9bbe6bb33b", "sourcegraph/scip-lsp:203471_2023-01-20_4.4-9d9bbe6bb33b"},
+	} {
+		version.Mock(testCase.version)
+
+		if indexer, ok := DefaultIndexerForLang("lsp"); !ok {
+			t.Fatalf("expected an indexer for lsp")
+		} else if indexer != testCase.expected {
+			t.Errorf("unexpected indexer for version %q. want=%q have=%q", testCase.version, testCase.expected, indexer)
+		}
+	}
+}
+
+func TestDefaultIndexerForLangPinned(t *testing.T) {
+	// Do not add to this list: new indexers must be pinned with update-shas.sh
+	unpinned := map[string]struct{}{
+		"sourcegraph/scip-dotnet": {},
+		"sourcegraph/scip-php":    {},
+	}
+
+	for language, indexer := range defaultIndexers {
+		if _, ok := versionedIndexers[indexer]; ok {
+			continue
+		}
+
+		sha, ok := defaultIndexerSHAs[indexer]
+		if !ok {
+			t.Errorf("no SHA set for indexer %q of language %q", indexer, language)
+			continue
+		}
+		if _, ok := unpinned[indexer]; !ok && sha == "" {
+			t.Errorf("empty SHA set for indexer %q of language %q", indexer, language)
+		}
+	}
+}
@@ -5,7 +5,12 @@ set -u
 DOCKER_USER=${DOCKER_USER:?"No DOCKER_USER is set."}
 DOCKER_PASS=${DOCKER_PASS:?"No DOCKER_PASS is set."}
 
-for indexer in lsif-clang lsif-go lsif-rust scip-java scip-python scip-typescript scip-ruby; do
+indexers=("$@")
+if [[ ${#indexers[@]} -eq 0 ]]; then
+  indexers=(lsif-clang lsif-go lsif-rust scip-java scip-python scip-typescript scip-ruby scip-dotnet scip-php)
+fi
+
+for indexer in "${indexers[@]}"; do
   tag="latest"
   if [[ "${indexer}" = "scip-python" ]] || [[ "${indexer}" = "scip-typescript" || "${indexer}" = "scip-ruby" ]]; then
     tag="autoindex"
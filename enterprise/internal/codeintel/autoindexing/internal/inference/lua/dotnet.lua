@@ -0,0 +1,74 @@
+local path = require "path"
+local recognizer = require "sg.autoindex.recognizer"
+local pattern = require "sg.autoindex.patterns"
+
+local shared = require "sg.autoindex.shared"
+
+local indexer = require("sg.autoindex.indexes").get "dotnet"
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine(shared.exclude_paths, {
+  pattern.new_path_segment "bin",
+  pattern.new_path_segment "obj",
+})
+
+-- Returns true if any ancestor directory of the given path is a key of roots
+local has_ancestor_root = function(roots, filepath)
+  local ancestors = path.ancestors(filepath)
+  for i = 1, #ancestors do
+    if roots[ancestors[i]] then
+      return true
+    end
+  end
+
+  return false
+end
+
+return recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_extension "sln",
+    pattern.new_path_extension "csproj",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when solution or C# project files exist. Each directory containing a solution
+  -- file is indexed as a unit; project files not covered by a solution are indexed on
+  -- their own.
+  generate = function(_, paths)
+    local solution_roots = {}
+    for i = 1, #paths do
+      if path.basename(paths[i]):match "%.sln$" then
+        solution_roots[path.dirname(paths[i])] = true
+      end
+    end
+
+    local roots = {}
+    for root in pairs(solution_roots) do
+      roots[root] = true
+    end
+    for i = 1, #paths do
+      if path.basename(paths[i]):match "%.csproj$" and not has_ancestor_root(solution_roots, paths[i]) then
+        roots[path.dirname(paths[i])] = true
+      end
+    end
+
+    local jobs = {}
+    for root in pairs(roots) do
+      table.insert(jobs, {
+        steps = {
+          {
+            root = root,
+            image = indexer,
+            commands = { "dotnet restore" },
+          },
+        },
+        root = root,
+        indexer = indexer,
+        indexer_args = { "scip-dotnet", "index" },
+        outfile = outfile,
+      })
+    end
+
+    return jobs
+  end,
+}
@@ -0,0 +1,59 @@
+local path = require "path"
+local recognizer = require "sg.autoindex.recognizer"
+local pattern = require "sg.autoindex.patterns"
+
+local shared = require "sg.autoindex.shared"
+local util = require "sg.autoindex.util"
+
+local indexer = require("sg.autoindex.indexes").get "java"
+local outfile = "index.scip"
+
+return recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_basename "build.gradle.kts",
+    pattern.new_path_basename "settings.gradle.kts",
+    -- To defer to the java recognizer
+    pattern.new_path_literal "lsif-java.json",
+    pattern.new_path_exclude(shared.exclude_paths),
+  },
+
+  -- Invoked when Gradle Kotlin DSL build files exist. Only the top-most build in each
+  -- directory tree is indexed, as Gradle builds its subprojects from there.
+  generate = function(_, paths)
+    if util.contains(paths, "lsif-java.json") then
+      -- Repository is explicitly configured for scip-java; the java recognizer handles it
+      return {}
+    end
+
+    local dirs = {}
+    for i = 1, #paths do
+      dirs[path.dirname(paths[i])] = true
+    end
+
+    local jobs = {}
+    for root in pairs(dirs) do
+      local is_nested = false
+      local ancestors = path.ancestors(root)
+      if root ~= "" then
+        for i = 1, #ancestors do
+          if dirs[ancestors[i]] then
+            is_nested = true
+            break
+          end
+        end
+      end
+
+      if not is_nested then
+        table.insert(jobs, {
+          steps = {},
+          root = root,
+          indexer = indexer,
+          indexer_args = { "scip-java", "index", "--build-tool=gradle" },
+          outfile = outfile,
+        })
+      end
+    end
+
+    return jobs
+  end,
+}
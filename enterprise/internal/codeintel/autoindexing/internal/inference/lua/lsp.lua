@@ -0,0 +1,83 @@
+local path = require "path"
+local recognizer = require "sg.autoindex.recognizer"
+local pattern = require "sg.autoindex.patterns"
+
+local shared = require "sg.autoindex.shared"
+
+local outfile = "index.scip"
+
+local M = {}
+
+-- Returns a recognizer that indexes projects of a language without a dedicated SCIP
+-- indexer by driving its language server with scip-lsp. This recognizer is not
+-- registered by default; it is meant to be returned from an inference override script.
+--
+-- The given config table has the following fields:
+--   language_id:   the LSP language identifier of the indexed files (e.g. "elixir")
+--   project_files: basenames of files marking the root of a project (e.g. { "mix.exs" })
+--   extensions:    extensions of the files to index (e.g. { "ex", "exs" })
+--   server:        the command (and arguments) that runs the language server over stdio
+--   install:       optional shell commands run in the indexer container before indexing,
+--                  e.g. to install the language server
+M.new_recognizer = function(config)
+  assert(type(config.language_id) == "string", "language_id must be a string")
+  assert(type(config.project_files) == "table", "project_files must be a table")
+  assert(type(config.extensions) == "table", "extensions must be a table")
+  assert(type(config.server) == "table" and #config.server > 0, "server must be a non-empty table")
+
+  local indexer = require("sg.autoindex.indexes").get "lsp"
+
+  local patterns = {}
+  for i = 1, #config.project_files do
+    table.insert(patterns, pattern.new_path_basename(config.project_files[i]))
+  end
+  table.insert(patterns, pattern.new_path_exclude(shared.exclude_paths))
+
+  local extensions = {}
+  for i = 1, #config.extensions do
+    table.insert(extensions, "." .. config.extensions[i]:gsub("^%.", ""))
+  end
+
+  local args = {
+    "scip-lsp",
+    "-language-id",
+    config.language_id,
+    "-extensions",
+    table.concat(extensions, ","),
+    "-output",
+    outfile,
+    "--",
+  }
+  for i = 1, #config.server do
+    table.insert(args, config.server[i])
+  end
+
+  return recognizer.new_path_recognizer {
+    patterns = patterns,
+
+    -- Invoked when project files exist. Each directory containing a project file is indexed
+    -- as a unit.
+    generate = function(_, paths)
+      local roots = {}
+      for i = 1, #paths do
+        roots[path.dirname(paths[i])] = true
+      end
+
+      local jobs = {}
+      for root in pairs(roots) do
+        table.insert(jobs, {
+          steps = {},
+          local_steps = config.install or {},
+          root = root,
+          indexer = indexer,
+          indexer_args = args,
+          outfile = outfile,
+        })
+      end
+
+      return jobs
+    end,
+  }
+end
+
+return M
@@ -2,8 +2,11 @@ local config = require("sg.autoindex.config").new {}
 
 for _, name in ipairs {
   "clang",
+  "dotnet",
   "go",
   "java",
+  "kotlin",
+  "php",
   "python",
   "ruby",
   "rust",
@@ -39,6 +39,7 @@ func indexJobFromTable(value lua.LValue) (config.IndexJob, error) {
 		"indexer":      util.SetString(&job.Indexer),
 		"indexer_args": util.SetStrings(&job.IndexerArgs),
 		"outfile":      util.SetString(&job.Outfile),
+		"caches":       setCaches(&job.Caches),
 	}); err != nil {
 		return config.IndexJob{}, err
 	}
@@ -92,3 +93,49 @@ func setDockerSteps(ptr *[]config.DockerStep) func(lua.LValue) error {
 		return nil
 	}
 }
+
+// cacheFromTable decodes a single Lua table value into a cache instance.
+func cacheFromTable(value lua.LValue) (cache config.Cache, _ error) {
+	table, ok := value.(*lua.LTable)
+	if !ok {
+		return config.Cache{}, util.NewTypeError("table", value)
+	}
+
+	if err := util.DecodeTable(table, map[string]func(lua.LValue) error{
+		"key":       util.SetString(&cache.Key),
+		"key_files": util.SetStrings(&cache.KeyFiles),
+		"paths":     util.SetStrings(&cache.Paths),
+	}); err != nil {
+		return config.Cache{}, err
+	}
+
+	if cache.Key == "" {
+		return config.Cache{}, errors.Newf("no cache key supplied")
+	}
+	if len(cache.Paths) == 0 {
+		return config.Cache{}, errors.Newf("no cache paths supplied")
+	}
+
+	return cache, nil
+}
+
+// setCaches returns a decoder function that updates the given cache slice value
+// on invocation. For use in luasandbox.DecodeTable.
+func setCaches(ptr *[]config.Cache) func(lua.LValue) error {
+	return func(value lua.LValue) (err error) {
+		values, err := util.DecodeSlice(value)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range values {
+			cache, err := cacheFromTable(v)
+			if err != nil {
+				return err
+			}
+			*ptr = append(*ptr, cache)
+		}
+
+		return nil
+	}
+}
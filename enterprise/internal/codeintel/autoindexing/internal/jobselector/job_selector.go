@@ -231,6 +231,7 @@ func convertIndexConfiguration(repositoryID int, commit string, indexConfigurati
 			Indexer:      indexJob.Indexer,
 			IndexerArgs:  indexJob.IndexerArgs,
 			Outfile:      indexJob.Outfile,
+			Caches:       convertCaches(indexJob.Caches),
 		})
 	}
 
@@ -260,8 +261,22 @@ func convertInferredConfiguration(repositoryID int, commit string, indexJobs []c
 			Indexer:      indexJob.Indexer,
 			IndexerArgs:  indexJob.IndexerArgs,
 			Outfile:      indexJob.Outfile,
+			Caches:       convertCaches(indexJob.Caches),
 		})
 	}
 
 	return indexes
 }
+
+// convertCaches converts the cache declarations of an index job into their index record form.
+func convertCaches(caches []config.Cache) (indexCaches []types.IndexCache) {
+	for _, cache := range caches {
+		indexCaches = append(indexCaches, types.IndexCache{
+			Key:      cache.Key,
+			KeyFiles: cache.KeyFiles,
+			Paths:    cache.Paths,
+		})
+	}
+
+	return indexCaches
+}
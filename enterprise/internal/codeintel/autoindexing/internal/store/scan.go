@@ -23,6 +23,7 @@ var scanFirstIndex = basestore.NewFirstScanner(scanIndex)
 
 func scanIndex(s dbutil.Scanner) (index types.Index, err error) {
 	var executionLogs []workerutil.ExecutionLogEntry
+	var caches []types.IndexCache
 	if err := s.Scan(
 		&index.ID,
 		&index.Commit,
@@ -46,11 +47,13 @@ func scanIndex(s dbutil.Scanner) (index types.Index, err error) {
 		pq.Array(&index.LocalSteps),
 		&index.AssociatedUploadID,
 		&index.ShouldReindex,
+		pq.Array(&caches),
 	); err != nil {
 		return index, err
 	}
 
 	index.ExecutionLogs = append(index.ExecutionLogs, executionLogs...)
+	index.Caches = append(index.Caches, caches...)
 
 	return index, nil
 }
@@ -81,6 +84,7 @@ var scanIndexesWithCount = basestore.NewSliceWithCountScanner(scanIndexWithCount
 // scanIndexes scans a slice of indexes from the return value of `*Store.query`.
 func scanIndexWithCount(s dbutil.Scanner) (index types.Index, count int, err error) {
 	var executionLogs []workerutil.ExecutionLogEntry
+	var caches []types.IndexCache
 
 	if err := s.Scan(
 		&index.ID,
@@ -105,12 +109,14 @@ func scanIndexWithCount(s dbutil.Scanner) (index types.Index, count int, err err
 		pq.Array(&index.LocalSteps),
 		&index.AssociatedUploadID,
 		&index.ShouldReindex,
+		pq.Array(&caches),
 		&count,
 	); err != nil {
 		return index, 0, err
 	}
 
 	index.ExecutionLogs = append(index.ExecutionLogs, executionLogs...)
+	index.Caches = append(index.Caches, caches...)
 
 	return index, count, nil
 }
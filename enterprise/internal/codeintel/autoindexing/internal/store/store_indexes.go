@@ -39,9 +39,12 @@ func (s *store) InsertIndexes(ctx context.Context, indexes []types.Index) (_ []t
 		if index.LocalSteps == nil {
 			index.LocalSteps = []string{}
 		}
+		if index.Caches == nil {
+			index.Caches = []types.IndexCache{}
+		}
 
 		values = append(values, sqlf.Sprintf(
-			"(%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)",
+			"(%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)",
 			index.State,
 			index.Commit,
 			index.RepositoryID,
@@ -52,6 +55,7 @@ func (s *store) InsertIndexes(ctx context.Context, indexes []types.Index) (_ []t
 			pq.Array(index.IndexerArgs),
 			index.Outfile,
 			pq.Array(index.ExecutionLogs),
+			pq.Array(index.Caches),
 		))
 	}
 
@@ -82,7 +86,8 @@ INSERT INTO lsif_indexes (
 	indexer,
 	indexer_args,
 	outfile,
-	execution_logs
+	execution_logs,
+	caches
 ) VALUES %s
 RETURNING id
 `
@@ -158,6 +163,7 @@ SELECT
 	u.local_steps,
 	` + indexAssociatedUploadIDQueryFragment + `,
 	u.should_reindex,
+	u.caches,
 	COUNT(*) OVER() AS count
 FROM lsif_indexes u
 LEFT JOIN (` + indexRankQueryFragment + `) s
@@ -363,7 +369,8 @@ SELECT
 	s.rank,
 	u.local_steps,
 	` + indexAssociatedUploadIDQueryFragment + `,
-	u.should_reindex
+	u.should_reindex,
+	u.caches
 FROM lsif_indexes u
 LEFT JOIN (` + indexRankQueryFragment + `) s
 ON u.id = s.id
@@ -419,7 +426,8 @@ SELECT
 	s.rank,
 	u.local_steps,
 	` + indexAssociatedUploadIDQueryFragment + `,
-	u.should_reindex
+	u.should_reindex,
+	u.caches
 FROM lsif_indexes u
 LEFT JOIN (` + indexRankQueryFragment + `) s
 ON u.id = s.id
@@ -789,7 +797,8 @@ SELECT
 	s.rank,
 	u.local_steps,
 	` + indexAssociatedUploadIDQueryFragment + `,
-	u.should_reindex
+	u.should_reindex,
+	u.caches
 FROM lsif_indexes_with_repository_name u
 LEFT JOIN (` + indexRankQueryFragment + `) s
 ON u.id = s.id
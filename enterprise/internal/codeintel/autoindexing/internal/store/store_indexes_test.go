@@ -53,6 +53,9 @@ func TestInsertIndexes(t *testing.T) {
 				{Command: []string{"op", "1"}, Out: "Indexing\nUploading\nDone with 1.\n"},
 				{Command: []string{"op", "2"}, Out: "Indexing\nUploading\nDone with 2.\n"},
 			},
+			Caches: []types.IndexCache{
+				{Key: "yarn", KeyFiles: []string{"yarn.lock"}, Paths: []string{"/usr/local/share/.cache/yarn"}},
+			},
 		},
 		{
 			State:        "queued",
@@ -110,6 +113,9 @@ func TestInsertIndexes(t *testing.T) {
 				{Command: []string{"op", "1"}, Out: "Indexing\nUploading\nDone with 1.\n"},
 				{Command: []string{"op", "2"}, Out: "Indexing\nUploading\nDone with 2.\n"},
 			},
+			Caches: []types.IndexCache{
+				{Key: "yarn", KeyFiles: []string{"yarn.lock"}, Paths: []string{"/usr/local/share/.cache/yarn"}},
+			},
 			Rank: &rank1,
 		},
 		{
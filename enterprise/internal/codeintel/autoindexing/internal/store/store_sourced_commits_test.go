@@ -158,6 +158,9 @@ func insertIndexes(t testing.TB, db database.DB, indexes ...types.Index) {
 		if index.LocalSteps == nil {
 			index.LocalSteps = []string{}
 		}
+		if index.Caches == nil {
+			index.Caches = []types.IndexCache{}
+		}
 
 		// Ensure we have a repo for the inner join in select queries
 		insertRepo(t, db, index.RepositoryID, index.RepositoryName)
@@ -182,8 +185,9 @@ func insertIndexes(t testing.TB, db database.DB, indexes ...types.Index) {
 				outfile,
 				execution_logs,
 				local_steps,
-				should_reindex
-			) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+				should_reindex,
+				caches
+			) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 		`,
 			index.ID,
 			index.Commit,
@@ -204,6 +208,7 @@ func insertIndexes(t testing.TB, db database.DB, indexes ...types.Index) {
 			pq.Array(index.ExecutionLogs),
 			pq.Array(index.LocalSteps),
 			index.ShouldReindex,
+			pq.Array(index.Caches),
 		)
 
 		if _, err := db.ExecContext(context.Background(), query.Query(sqlf.PostgresBindVar), query.Args()...); err != nil {
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+)
+
+// CachePrefix is the prefix of all dependency cache archives within the code intel
+// upload store. Objects under this prefix are expired by the worker.
+const CachePrefix = "caches/"
+
+// NewCacheHandler creates a handler that serves the dependency cache archives of
+// auto-indexing jobs to executors. The handler expects a `key` route variable and
+// supports HEAD (existence checks), GET (downloads), and PUT (uploads) requests.
+func NewCacheHandler(uploadStore uploadstore.Store) http.Handler {
+	logger := log.Scoped("autoindexing.cachehandler", "codeintel auto-indexing dependency cache handler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := mux.Vars(r)["key"]
+		if key == "" || strings.Contains(key, "..") {
+			http.Error(w, "invalid cache key", http.StatusBadRequest)
+			return
+		}
+		key = CachePrefix + key
+
+		switch r.Method {
+		case http.MethodHead, http.MethodGet:
+			rc, err := uploadStore.Get(r.Context(), key)
+			if err != nil {
+				http.Error(w, "cache archive does not exist", http.StatusNotFound)
+				return
+			}
+			defer rc.Close()
+
+			// Some store implementations only surface a missing object once the
+			// first read is attempted. Peek so that we can respond with a 404
+			// instead of a truncated 200.
+			br := bufio.NewReader(rc)
+			if _, err := br.Peek(1); err != nil && err != io.EOF {
+				http.Error(w, "cache archive does not exist", http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.WriteHeader(http.StatusOK)
+
+			if r.Method == http.MethodHead {
+				return
+			}
+			if _, err := io.Copy(w, br); err != nil {
+				logger.Error("failed to write cache archive to client", log.String("key", key), log.Error(err))
+			}
+
+		case http.MethodPut:
+			if _, err := uploadStore.Upload(r.Context(), key, r.Body); err != nil {
+				logger.Error("failed to upload cache archive", log.String("key", key), log.Error(err))
+				http.Error(w, "failed to upload cache archive", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gorilla/mux"
+
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestCacheHandler(t *testing.T) {
+	uploadStore := uploadstoremocks.NewMockStore()
+	uploadStore.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
+		if key == "caches/42/go/abc.tar.gz" {
+			return io.NopCloser(strings.NewReader("payload")), nil
+		}
+
+		// Mimic stores that only fail once the object is read
+		return io.NopCloser(iotest.ErrReader(errors.New("object does not exist"))), nil
+	})
+
+	router := mux.NewRouter().SkipClean(true)
+	router.Path("/caches/{key:.*}").Handler(NewCacheHandler(uploadStore))
+
+	serve := func(method, path string, body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, body))
+		return w
+	}
+
+	t.Run("get", func(t *testing.T) {
+		w := serve("GET", "/caches/42/go/abc.tar.gz", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+		}
+		if body := w.Body.String(); body != "payload" {
+			t.Errorf("unexpected body. want=%q have=%q", "payload", body)
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		w := serve("HEAD", "/caches/42/go/abc.tar.gz", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("unexpected body for HEAD request: %q", w.Body.String())
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if w := serve("HEAD", "/caches/42/go/missing.tar.gz", nil); w.Code != http.StatusNotFound {
+			t.Errorf("unexpected status code. want=%d have=%d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		if w := serve("GET", "/caches/42/../43/go/abc.tar.gz", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("unexpected status code. want=%d have=%d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("put", func(t *testing.T) {
+		var uploaded bytes.Buffer
+		uploadStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+			if key != "caches/42/go/def.tar.gz" {
+				return 0, errors.Newf("unexpected key %q", key)
+			}
+			return io.Copy(&uploaded, r)
+		})
+
+		if w := serve("PUT", "/caches/42/go/def.tar.gz", strings.NewReader("archive")); w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+		}
+		if uploaded.String() != "archive" {
+			t.Errorf("unexpected upload. want=%q have=%q", "archive", uploaded.String())
+		}
+	})
+}
@@ -0,0 +1,540 @@
+package codenav
+
+import (
+	"context"
+	"sort"
+
+	traceLog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetIncomingCalls returns the functions and methods that call the function or method at the requested
+// position, along with the call sites within each caller. Callers are found in the visible uploads as well
+// as in uploads of other repositories that reference the target symbol via monikers.
+//
+// Call hierarchy is computed from SCIP symbol data, which is written for SCIP and LSIF uploads alike. The
+// ranges of an LSIF upload only carry global symbols when its monikers are SCIP symbols, as is the case for
+// indexes converted from SCIP; uploads processed before SCIP data was written contribute no calls.
+func (s *Service) GetIncomingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.CallHierarchyCall, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getIncomingCalls, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := newSymbolTable()
+	targetSymbols, err := s.getCallableSymbolsAtPosition(ctx, visibleUploads, symbols)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numTargetSymbols", len(targetSymbols)))
+	if len(targetSymbols) == 0 {
+		return nil, nil
+	}
+
+	// Search the visible uploads as well as the uploads that import the target symbols
+	uploads := make([]types.Dump, 0, len(visibleUploads))
+	ignoreIDs := make([]int, 0, len(visibleUploads))
+	for i := range visibleUploads {
+		uploads = append(uploads, visibleUploads[i].Upload)
+		ignoreIDs = append(ignoreIDs, visibleUploads[i].Upload.ID)
+	}
+
+	monikers := symbols.monikers(targetSymbols, "import")
+	referenceUploadIDs, _, _, err := s.uploadSvc.GetUploadIDsWithReferences(
+		ctx,
+		monikers,
+		ignoreIDs,
+		args.RepositoryID,
+		args.Commit,
+		requestState.maximumIndexesPerMonikerSearch,
+		0,
+	)
+	if err != nil {
+		return nil, err
+	}
+	referenceUploads, err := s.getUploadsByIDs(ctx, referenceUploadIDs, requestState)
+	if err != nil {
+		return nil, err
+	}
+	uploads = append(uploads, referenceUploads...)
+	trace.Log(
+		traceLog.Int("numUploads", len(uploads)),
+		traceLog.String("uploads", uploadIDsToString(uploads)),
+	)
+
+	references, err := s.lsifstore.GetSymbolUsages(ctx, lsifstore.SymbolUsageReferences, dumpIDs(uploads), targetSymbols)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifstore.GetSymbolUsages")
+	}
+	trace.Log(traceLog.Int("numReferences", len(references)))
+
+	// Attribute each reference to the callable definition that encloses it
+	calls := newCallSet()
+	for _, group := range groupByDocument(references) {
+		callables, err := s.getCallableDefinitions(ctx, group[0].DumpID, group[0].Path, symbols)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reference := range group {
+			caller, ok := findEnclosingCallable(callables, reference.Range)
+			if !ok {
+				// Calls at the top-level of a file are not attributable to a caller
+				continue
+			}
+
+			calls.addDefinition(caller.symbol, shared.Location{DumpID: reference.DumpID, Path: reference.Path, Range: caller.rng})
+			calls.addCallSite(caller.symbol, reference.Location)
+		}
+	}
+
+	return s.resolveCalls(ctx, args, requestState, calls, symbols)
+}
+
+// GetOutgoingCalls returns the functions and methods called by the function or method at the requested
+// position, along with the call sites within its body. Callee definitions are resolved in the visible
+// uploads as well as in uploads of other repositories that export the callee symbols via monikers.
+//
+// Call hierarchy is computed from SCIP symbol data, which is written for SCIP and LSIF uploads alike. The
+// ranges of an LSIF upload only carry global symbols when its monikers are SCIP symbols, as is the case for
+// indexes converted from SCIP; uploads processed before SCIP data was written contribute no calls.
+func (s *Service) GetOutgoingCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.CallHierarchyCall, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getOutgoingCalls, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := newSymbolTable()
+	targetSymbols, err := s.getCallableSymbolsAtPosition(ctx, visibleUploads, symbols)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numTargetSymbols", len(targetSymbols)))
+	if len(targetSymbols) == 0 {
+		return nil, nil
+	}
+
+	// The target may be defined in the visible uploads or, if the requested position is a call of
+	// a function in a dependency, in an upload that exports the target symbols
+	uploads := make([]types.Dump, 0, len(visibleUploads))
+	for i := range visibleUploads {
+		uploads = append(uploads, visibleUploads[i].Upload)
+	}
+	definitionUploads, err := s.getUploadsWithDefinitionsForMonikers(ctx, symbols.monikers(targetSymbols, "export"), requestState)
+	if err != nil {
+		return nil, err
+	}
+	uploads = append(uploads, definitionUploads...)
+	trace.Log(
+		traceLog.Int("numUploads", len(uploads)),
+		traceLog.String("uploads", uploadIDsToString(uploads)),
+	)
+
+	definitions, err := s.lsifstore.GetSymbolUsages(ctx, lsifstore.SymbolUsageDefinitions, dumpIDs(uploads), targetSymbols)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifstore.GetSymbolUsages")
+	}
+	trace.Log(traceLog.Int("numDefinitions", len(definitions)))
+
+	// Collect the calls within the body of each definition of the target
+	calls := newCallSet()
+	calleeUploadIDs := map[int]struct{}{}
+	for _, group := range groupByDocument(definitions) {
+		document, ok, err := s.lsifstore.GetSCIPDocument(ctx, group[0].DumpID, group[0].Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+		}
+		if !ok {
+			continue
+		}
+		callables := findCallableDefinitions(document, symbols)
+
+		for _, definition := range group {
+			for _, occurrence := range findCallsWithinBody(document, callables, definition.Range, symbols) {
+				calls.addCallSite(occurrence.Symbol, shared.Location{
+					DumpID: definition.DumpID,
+					Path:   definition.Path,
+					Range:  translateSCIPRange(occurrence.Range),
+				})
+				calleeUploadIDs[definition.DumpID] = struct{}{}
+			}
+		}
+	}
+	trace.Log(traceLog.Int("numCallees", len(calls.symbols)))
+	if len(calls.symbols) == 0 {
+		return nil, nil
+	}
+
+	// Resolve the definitions of each callee, which may live in the upload containing the call
+	// or in an upload that exports the callee symbol
+	calleeUploads, err := s.getUploadsWithDefinitionsForMonikers(ctx, symbols.monikers(calls.symbols, "export"), requestState)
+	if err != nil {
+		return nil, err
+	}
+	ids := dumpIDs(calleeUploads)
+	for id := range calleeUploadIDs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	calleeDefinitions, err := s.lsifstore.GetSymbolUsages(ctx, lsifstore.SymbolUsageDefinitions, ids, calls.symbols)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifstore.GetSymbolUsages")
+	}
+	for _, definition := range calleeDefinitions {
+		calls.addDefinition(definition.Symbol, definition.Location)
+	}
+
+	return s.resolveCalls(ctx, args, requestState, calls, symbols)
+}
+
+// getCallableSymbolsAtPosition returns the global function and method symbols of the SCIP occurrences
+// enclosing the adjusted position within each of the given visible uploads.
+func (s *Service) getCallableSymbolsAtPosition(ctx context.Context, visibleUploads []visibleUpload, symbols *symbolTable) ([]string, error) {
+	var callableSymbols []string
+	seen := map[string]struct{}{}
+
+	for i := range visibleUploads {
+		document, ok, err := s.lsifstore.GetSCIPDocument(ctx, visibleUploads[i].Upload.ID, visibleUploads[i].TargetPathWithoutRoot)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+		}
+		if !ok {
+			continue
+		}
+
+		occurrences := types.FindOccurrences(
+			document.Occurrences,
+			int32(visibleUploads[i].TargetPosition.Line),
+			int32(visibleUploads[i].TargetPosition.Character),
+		)
+		for _, occurrence := range occurrences {
+			if _, ok := seen[occurrence.Symbol]; ok || !symbols.isCallable(occurrence.Symbol) {
+				continue
+			}
+
+			seen[occurrence.Symbol] = struct{}{}
+			callableSymbols = append(callableSymbols, occurrence.Symbol)
+		}
+	}
+
+	return callableSymbols, nil
+}
+
+// getCallableDefinitions returns the callable definitions of the given SCIP document ordered by position.
+// If the upload does not contain the document, an empty slice is returned.
+func (s *Service) getCallableDefinitions(ctx context.Context, uploadID int, path string, symbols *symbolTable) ([]callableDefinition, error) {
+	document, ok, err := s.lsifstore.GetSCIPDocument(ctx, uploadID, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return findCallableDefinitions(document, symbols), nil
+}
+
+// resolveCalls converts the locations of the given call set into locations in the requested commit. The
+// number of calls is capped by the request limit, and calls whose call sites are all filtered by sub-repo
+// permissions are dropped.
+func (s *Service) resolveCalls(ctx context.Context, args shared.RequestArgs, requestState RequestState, calls *callSet, symbols *symbolTable) ([]shared.CallHierarchyCall, error) {
+	resolvedCalls := make([]shared.CallHierarchyCall, 0, len(calls.symbols))
+	for _, symbol := range calls.symbols {
+		if args.Limit > 0 && len(resolvedCalls) >= args.Limit {
+			break
+		}
+
+		callSites, err := s.getUploadLocations(ctx, args, requestState, calls.callSites[symbol], true)
+		if err != nil {
+			return nil, err
+		}
+		if len(callSites) == 0 {
+			continue
+		}
+
+		definitions, err := s.getUploadLocations(ctx, args, requestState, calls.definitions[symbol], true)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvedCalls = append(resolvedCalls, shared.CallHierarchyCall{
+			Symbol:      symbol,
+			Name:        symbols.displayName(symbol),
+			Definitions: definitions,
+			CallSites:   callSites,
+		})
+	}
+
+	return resolvedCalls, nil
+}
+
+// callSet accumulates the definitions and call sites of calls keyed by symbol in order of discovery.
+type callSet struct {
+	symbols     []string
+	definitions map[string][]shared.Location
+	callSites   map[string][]shared.Location
+}
+
+func newCallSet() *callSet {
+	return &callSet{
+		definitions: map[string][]shared.Location{},
+		callSites:   map[string][]shared.Location{},
+	}
+}
+
+func (c *callSet) add(symbol string) {
+	if _, ok := c.callSites[symbol]; !ok {
+		c.symbols = append(c.symbols, symbol)
+		c.callSites[symbol] = nil
+	}
+}
+
+func (c *callSet) addDefinition(symbol string, location shared.Location) {
+	c.add(symbol)
+
+	for _, definition := range c.definitions[symbol] {
+		if definition == location {
+			return
+		}
+	}
+	c.definitions[symbol] = append(c.definitions[symbol], location)
+}
+
+func (c *callSet) addCallSite(symbol string, location shared.Location) {
+	c.add(symbol)
+	c.callSites[symbol] = append(c.callSites[symbol], location)
+}
+
+// callableDefinition is the definition occurrence of a function or method within a SCIP document.
+type callableDefinition struct {
+	symbol string
+	rng    types.Range
+}
+
+// findCallableDefinitions returns the definition occurrences of functions and methods within the given
+// document ordered by position.
+func findCallableDefinitions(document *scip.Document, symbols *symbolTable) []callableDefinition {
+	var callables []callableDefinition
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) && symbols.isCallable(occurrence.Symbol) {
+			callables = append(callables, callableDefinition{
+				symbol: occurrence.Symbol,
+				rng:    translateSCIPRange(occurrence.Range),
+			})
+		}
+	}
+
+	sort.SliceStable(callables, func(i, j int) bool {
+		return comparePosition(callables[i].rng.Start, callables[j].rng.Start) < 0
+	})
+
+	return callables
+}
+
+// findEnclosingCallable returns the callable definition whose body encloses the given range.
+//
+// SCIP occurrences do not record the extent of a definition, so the body of a callable is approximated
+// as the span from its definition to the definition of the next callable in the same document (or the
+// end of the document).
+func findEnclosingCallable(callables []callableDefinition, r types.Range) (callableDefinition, bool) {
+	i := sort.Search(len(callables), func(i int) bool {
+		return comparePosition(callables[i].rng.Start, r.Start) > 0
+	})
+	if i == 0 {
+		return callableDefinition{}, false
+	}
+
+	return callables[i-1], true
+}
+
+// findCallsWithinBody returns the reference occurrences of functions and methods within the body of the
+// callable defined at the given range. See findEnclosingCallable for how the body is approximated.
+func findCallsWithinBody(document *scip.Document, callables []callableDefinition, definitionRange types.Range, symbols *symbolTable) []*scip.Occurrence {
+	var calls []*scip.Occurrence
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) || !symbols.isCallable(occurrence.Symbol) {
+			continue
+		}
+
+		if enclosing, ok := findEnclosingCallable(callables, translateSCIPRange(occurrence.Range)); ok && enclosing.rng == definitionRange {
+			calls = append(calls, occurrence)
+		}
+	}
+
+	return types.SortOccurrences(calls)
+}
+
+// symbolTable caches parsed SCIP symbols for the duration of a request.
+type symbolTable struct {
+	parsed map[string]*scip.Symbol
+}
+
+func newSymbolTable() *symbolTable {
+	return &symbolTable{parsed: map[string]*scip.Symbol{}}
+}
+
+// get returns the parsed form of the given symbol, or nil if the symbol is local or malformed.
+func (t *symbolTable) get(symbol string) *scip.Symbol {
+	if parsed, ok := t.parsed[symbol]; ok {
+		return parsed
+	}
+
+	var parsed *scip.Symbol
+	if symbol != "" && !scip.IsLocalSymbol(symbol) {
+		if p, err := scip.ParseSymbol(symbol); err == nil {
+			parsed = p
+		}
+	}
+	t.parsed[symbol] = parsed
+
+	return parsed
+}
+
+// isCallable returns true if the given symbol is a global function or method symbol.
+func (t *symbolTable) isCallable(symbol string) bool {
+	parsed := t.get(symbol)
+	if parsed == nil || len(parsed.Descriptors) == 0 {
+		return false
+	}
+
+	return parsed.Descriptors[len(parsed.Descriptors)-1].Suffix == scip.Descriptor_Method
+}
+
+// isType returns true if the given symbol is a global type symbol.
+func (t *symbolTable) isType(symbol string) bool {
+	parsed := t.get(symbol)
+	if parsed == nil || len(parsed.Descriptors) == 0 {
+		return false
+	}
+
+	return parsed.Descriptors[len(parsed.Descriptors)-1].Suffix == scip.Descriptor_Type
+}
+
+// displayName returns the name of the last descriptor of the given symbol.
+func (t *symbolTable) displayName(symbol string) string {
+	parsed := t.get(symbol)
+	if parsed == nil || len(parsed.Descriptors) == 0 {
+		return symbol
+	}
+
+	return parsed.Descriptors[len(parsed.Descriptors)-1].Name
+}
+
+// monikers returns the monikers of the given kind that correspond to the given symbols. These match the
+// monikers and package information emitted for SCIP uploads, which use the symbol itself as identifier.
+func (t *symbolTable) monikers(symbols []string, kind string) []precise.QualifiedMonikerData {
+	monikers := make([]precise.QualifiedMonikerData, 0, len(symbols))
+	for _, symbol := range symbols {
+		parsed := t.get(symbol)
+		if parsed == nil || parsed.Package == nil {
+			continue
+		}
+
+		scheme := parsed.Scheme
+		switch scheme {
+		case "scip-java", "lsif-java":
+			scheme = "semanticdb"
+		case "scip-typescript", "lsif-typescript":
+			scheme = "npm"
+		}
+
+		monikers = append(monikers, precise.QualifiedMonikerData{
+			MonikerData: precise.MonikerData{
+				Kind:       kind,
+				Scheme:     scheme,
+				Identifier: symbol,
+			},
+			PackageInformationData: precise.PackageInformationData{
+				Manager: parsed.Package.Manager,
+				Name:    parsed.Package.Name,
+				Version: parsed.Package.Version,
+			},
+		})
+	}
+
+	return monikers
+}
+
+// groupByDocument partitions the given symbol locations, which are ordered by upload and path, into runs
+// that share an upload and path.
+func groupByDocument(locations []shared.SymbolLocation) [][]shared.SymbolLocation {
+	var groups [][]shared.SymbolLocation
+	for i, location := range locations {
+		if i == 0 || location.DumpID != locations[i-1].DumpID || location.Path != locations[i-1].Path {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], location)
+	}
+
+	return groups
+}
+
+func dumpIDs(dumps []types.Dump) []int {
+	ids := make([]int, 0, len(dumps))
+	for _, dump := range dumps {
+		ids = append(ids, dump.ID)
+	}
+
+	return ids
+}
+
+func translateSCIPRange(r []int32) types.Range {
+	scipRange := scip.NewRange(r)
+
+	return types.Range{
+		Start: types.Position{Line: int(scipRange.Start.Line), Character: int(scipRange.Start.Character)},
+		End:   types.Position{Line: int(scipRange.End.Line), Character: int(scipRange.End.Character)},
+	}
+}
+
+// comparePosition returns -1, 0, or 1 when the first position is before, equal to, or after the second.
+func comparePosition(a, b types.Position) int {
+	if a.Line != b.Line {
+		if a.Line < b.Line {
+			return -1
+		}
+		return 1
+	}
+	if a.Character != b.Character {
+		if a.Character < b.Character {
+			return -1
+		}
+		return 1
+	}
+
+	return 0
+}
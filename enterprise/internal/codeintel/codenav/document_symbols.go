@@ -0,0 +1,291 @@
+package codenav
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	traceLog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetDocumentSymbols returns the symbol outline of the requested file as a tree of document symbols.
+// When a visible upload contains a SCIP document for the file that defines global symbols, the outline
+// is built from the definitions within that document and nested by symbol descriptors. Otherwise, the
+// outline is built from the search-based symbols (ctags) of the file. Each node records which of the two
+// sources produced it.
+//
+// The SCIP documents of LSIF uploads are derived from their LSIF data, and only define global symbols
+// when the monikers of the upload are SCIP symbols (as for indexes converted from SCIP).
+func (s *Service) GetDocumentSymbols(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []shared.DocumentSymbol, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getDocumentSymbols, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+		},
+	})
+	defer endObservation()
+
+	if authz.SubRepoEnabled(requestState.authChecker) {
+		repo := api.RepoName(requestState.RepositoryName)
+		if include, err := authz.FilterActorPath(ctx, requestState.authChecker, actor.FromContext(ctx), repo, args.Path); err != nil || !include {
+			return nil, err
+		}
+	}
+
+	visibleUploads, err := s.getUploadPaths(ctx, args.Path, requestState)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, upload := range visibleUploads {
+		document, ok, err := s.lsifstore.GetSCIPDocument(ctx, upload.Upload.ID, upload.TargetPathWithoutRoot)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+		}
+		if !ok {
+			continue
+		}
+
+		documentSymbols := buildPreciseDocumentSymbols(document, newSymbolTable())
+		if len(documentSymbols) == 0 {
+			continue
+		}
+		trace.Log(traceLog.Int("uploadID", upload.Upload.ID))
+
+		if err := s.adjustDocumentSymbolRanges(ctx, args, requestState, upload, documentSymbols); err != nil {
+			return nil, err
+		}
+
+		return documentSymbols, nil
+	}
+
+	trace.Log(traceLog.Bool("searchBased", true))
+	return s.getSearchBasedDocumentSymbols(ctx, args, requestState)
+}
+
+// adjustDocumentSymbolRanges translates the ranges of the given document symbols (relative to the indexed
+// commit) into the requested commit in-place. Ranges that cannot be translated are left as indexed.
+func (s *Service) adjustDocumentSymbolRanges(ctx context.Context, args shared.RequestArgs, requestState RequestState, upload visibleUpload, documentSymbols []shared.DocumentSymbol) error {
+	for i := range documentSymbols {
+		_, adjustedRange, _, err := s.getSourceRange(ctx, args, requestState, upload.Upload.RepositoryID, upload.Upload.Commit, upload.TargetPath, documentSymbols[i].Range)
+		if err != nil {
+			return err
+		}
+		documentSymbols[i].Range = adjustedRange
+
+		if err := s.adjustDocumentSymbolRanges(ctx, args, requestState, upload, documentSymbols[i].Children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getSearchBasedDocumentSymbols returns the symbol outline of the requested file as reported by the
+// symbols service. Symbols are nested under the preceding symbol named by their ctags parent.
+func (s *Service) getSearchBasedDocumentSymbols(ctx context.Context, args shared.RequestArgs, requestState RequestState) ([]shared.DocumentSymbol, error) {
+	if s.symbols == nil || requestState.RepositoryName == "" {
+		return nil, nil
+	}
+
+	symbols, err := s.symbols.Search(ctx, search.SymbolsParameters{
+		Repo:            api.RepoName(requestState.RepositoryName),
+		CommitID:        api.CommitID(args.Commit),
+		IncludePatterns: []string{"^" + regexp.QuoteMeta(args.Path) + "$"},
+		First:           args.Limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "symbols.Search")
+	}
+
+	return buildSearchBasedDocumentSymbols(symbols), nil
+}
+
+// buildPreciseDocumentSymbols returns the outline of the given SCIP document. Each global type, term,
+// method, and macro defined in the document becomes a node, which is nested under the defined symbol
+// whose descriptors are a prefix of its own. Namespaces, parameters, and locals are omitted.
+func buildPreciseDocumentSymbols(document *scip.Document, symbols *symbolTable) []shared.DocumentSymbol {
+	nodes := map[string]*documentSymbolNode{}
+	var definitions []*documentSymbolNode
+	for _, occurrence := range document.Occurrences {
+		if !scip.SymbolRole_Definition.Matches(occurrence) || nodes[occurrence.Symbol] != nil {
+			continue
+		}
+
+		kind, ok := preciseDocumentSymbolKind(symbols.get(occurrence.Symbol))
+		if !ok {
+			continue
+		}
+
+		node := &documentSymbolNode{
+			DocumentSymbol: shared.DocumentSymbol{
+				Name:       symbols.displayName(occurrence.Symbol),
+				Kind:       kind,
+				Symbol:     occurrence.Symbol,
+				Range:      translateSCIPRange(occurrence.Range),
+				Provenance: shared.DocumentSymbolProvenancePrecise,
+			},
+		}
+		nodes[occurrence.Symbol] = node
+		definitions = append(definitions, node)
+	}
+
+	// Symbols sort immediately after their ancestors, so a stack of the current ancestor chain
+	// determines the nearest defined ancestor of each symbol.
+	sortedSymbols := make([]string, 0, len(nodes))
+	for symbol := range nodes {
+		sortedSymbols = append(sortedSymbols, symbol)
+	}
+	sort.Strings(sortedSymbols)
+
+	parents := make(map[string]string, len(nodes))
+	var stack []string
+	for _, symbol := range sortedSymbols {
+		for len(stack) > 0 && !strings.HasPrefix(symbol, stack[len(stack)-1]) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			parents[symbol] = stack[len(stack)-1]
+		}
+		stack = append(stack, symbol)
+	}
+
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return comparePosition(definitions[i].Range.Start, definitions[j].Range.Start) < 0
+	})
+
+	var roots []*documentSymbolNode
+	for _, node := range definitions {
+		if parent, ok := parents[node.Symbol]; ok {
+			nodes[parent].children = append(nodes[parent].children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return flattenDocumentSymbolNodes(roots)
+}
+
+// preciseDocumentSymbolKind returns the LSP symbol kind (as named by the GraphQL SymbolKind enum) of the
+// given symbol. A false-valued flag is returned for symbols that do not belong in a file outline.
+func preciseDocumentSymbolKind(symbol *scip.Symbol) (string, bool) {
+	if symbol == nil || len(symbol.Descriptors) == 0 {
+		return "", false
+	}
+
+	enclosedByType := len(symbol.Descriptors) > 1 && symbol.Descriptors[len(symbol.Descriptors)-2].Suffix == scip.Descriptor_Type
+
+	var kind lsp.SymbolKind
+	switch symbol.Descriptors[len(symbol.Descriptors)-1].Suffix {
+	case scip.Descriptor_Type:
+		kind = lsp.SKClass
+	case scip.Descriptor_Method:
+		kind = lsp.SKFunction
+		if enclosedByType {
+			kind = lsp.SKMethod
+		}
+	case scip.Descriptor_Term:
+		kind = lsp.SKVariable
+		if enclosedByType {
+			kind = lsp.SKField
+		}
+	case scip.Descriptor_Macro:
+		kind = lsp.SKFunction
+	default:
+		return "", false
+	}
+
+	return strings.ToUpper(kind.String()), true
+}
+
+// buildSearchBasedDocumentSymbols returns the outline formed by the given ctags symbols of a single file.
+// Symbols are attached to the closest preceding symbol whose name matches their parent; symbols without a
+// matching parent become roots.
+func buildSearchBasedDocumentSymbols(symbols result.Symbols) []shared.DocumentSymbol {
+	sorted := make(result.Symbols, len(symbols))
+	copy(sorted, symbols)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Line != sorted[j].Line {
+			return sorted[i].Line < sorted[j].Line
+		}
+		return sorted[i].Character < sorted[j].Character
+	})
+
+	var roots []*documentSymbolNode
+	byName := map[string]*documentSymbolNode{}
+	for _, symbol := range sorted {
+		kind := "UNKNOWN"
+		if lspKind := symbol.LSPKind(); lspKind != 0 {
+			kind = strings.ToUpper(lspKind.String())
+		}
+
+		r := symbol.Range()
+		node := &documentSymbolNode{
+			DocumentSymbol: shared.DocumentSymbol{
+				Name: symbol.Name,
+				Kind: kind,
+				Range: types.Range{
+					Start: types.Position{Line: r.Start.Line, Character: r.Start.Character},
+					End:   types.Position{Line: r.End.Line, Character: r.End.Character},
+				},
+				Provenance: shared.DocumentSymbolProvenanceSearchBased,
+			},
+		}
+
+		if parent, ok := byName[searchBasedParentName(symbol.Parent)]; ok && symbol.Parent != "" {
+			parent.children = append(parent.children, node)
+		} else {
+			roots = append(roots, node)
+		}
+		byName[symbol.Name] = node
+	}
+
+	return flattenDocumentSymbolNodes(roots)
+}
+
+// searchBasedParentName returns the unqualified name of a ctags parent, which may be qualified by its
+// own enclosing scopes (e.g. `Outer.Inner` or `Outer::Inner`).
+func searchBasedParentName(parent string) string {
+	if i := strings.LastIndexAny(parent, ".:"); i >= 0 {
+		return parent[i+1:]
+	}
+
+	return parent
+}
+
+// documentSymbolNode is a document symbol whose children are still being collected.
+type documentSymbolNode struct {
+	shared.DocumentSymbol
+	children []*documentSymbolNode
+}
+
+func flattenDocumentSymbolNodes(nodes []*documentSymbolNode) []shared.DocumentSymbol {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	documentSymbols := make([]shared.DocumentSymbol, 0, len(nodes))
+	for _, node := range nodes {
+		documentSymbol := node.DocumentSymbol
+		documentSymbol.Children = flattenDocumentSymbolNodes(node.children)
+		documentSymbols = append(documentSymbols, documentSymbol)
+	}
+
+	return documentSymbols
+}
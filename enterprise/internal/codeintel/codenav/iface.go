@@ -9,6 +9,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -24,6 +26,10 @@ type GitserverClient interface {
 	DiffPath(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, sourceCommit, targetCommit, path string) ([]*diff.Hunk, error)
 }
 
+type SymbolsClient interface {
+	Search(ctx context.Context, args search.SymbolsParameters) (result.Symbols, error)
+}
+
 type DBStore interface {
 	RepoName(ctx context.Context, repositoryID int) (string, error)
 	RepoNames(ctx context.Context, repositoryIDs ...int) (map[int]string, error)
@@ -3,6 +3,8 @@ package lsifstore
 import (
 	"context"
 
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
 	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
@@ -42,6 +44,13 @@ type LsifStore interface {
 
 	// Paths
 	GetPathExists(ctx context.Context, bundleID int, path string) (_ bool, err error)
+
+	// SCIP
+	GetSCIPDocument(ctx context.Context, uploadID int, path string) (_ *scip.Document, _ bool, err error)
+	GetSymbolUsages(ctx context.Context, kind SymbolUsageKind, uploadIDs []int, symbolNames []string) (_ []shared.SymbolLocation, err error)
+
+	// Symbols
+	SearchSymbols(ctx context.Context, uploadIDs []int, args shared.SymbolSearchArgs) (_ []shared.IndexedSymbol, err error)
 }
 
 type store struct {
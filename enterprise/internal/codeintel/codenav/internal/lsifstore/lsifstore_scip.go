@@ -0,0 +1,152 @@
+package lsifstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetSCIPDocument returns the SCIP document with the given path within the given upload. If the
+// upload does not contain the document, a false-valued flag is returned.
+func (s *store) GetSCIPDocument(ctx context.Context, uploadID int, path string) (_ *scip.Document, _ bool, err error) {
+	ctx, _, endObservation := s.operations.getSCIPDocument.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("uploadID", uploadID),
+		log.String("path", path),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	payload, exists, err := scanFirstPayload(s.db.Query(ctx, sqlf.Sprintf(
+		getSCIPDocumentQuery,
+		uploadID,
+		path,
+	)))
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	var document scip.Document
+	if err := proto.Unmarshal(payload, &document); err != nil {
+		return nil, false, err
+	}
+
+	return &document, true, nil
+}
+
+var scanFirstPayload = basestore.NewFirstScanner(basestore.ScanAny[[]byte])
+
+const getSCIPDocumentQuery = `
+SELECT sd.raw_scip_payload
+FROM codeintel_scip_document_lookup sid
+JOIN codeintel_scip_documents sd ON sd.id = sid.document_id
+WHERE
+	sid.upload_id = %s AND
+	sid.document_path = %s
+LIMIT 1
+`
+
+// SymbolUsageKind selects which uses of a symbol are returned by GetSymbolUsages.
+type SymbolUsageKind string
+
+const (
+	SymbolUsageDefinitions     SymbolUsageKind = "definition_ranges"
+	SymbolUsageReferences      SymbolUsageKind = "reference_ranges"
+	SymbolUsageImplementations SymbolUsageKind = "implementation_ranges"
+)
+
+// GetSymbolUsages returns the definitions, references, or implementations of the given symbols within the
+// given uploads, ordered by upload and document path. The implementations of a symbol are the definitions
+// of the symbols that declare an implementation relationship to it.
+func (s *store) GetSymbolUsages(ctx context.Context, kind SymbolUsageKind, uploadIDs []int, symbolNames []string) (_ []shared.SymbolLocation, err error) {
+	ctx, trace, endObservation := s.operations.getSymbolUsages.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("kind", string(kind)),
+		log.Int("numUploadIDs", len(uploadIDs)),
+		log.String("uploadIDs", intsToString(uploadIDs)),
+		log.Int("numSymbolNames", len(symbolNames)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	switch kind {
+	case SymbolUsageDefinitions, SymbolUsageReferences, SymbolUsageImplementations:
+	default:
+		return nil, errors.Newf("unknown symbol usage kind %q", kind)
+	}
+	if len(uploadIDs) == 0 || len(symbolNames) == 0 {
+		return nil, nil
+	}
+
+	locations, err := scanSymbolLocations(s.db.Query(ctx, sqlf.Sprintf(
+		getSymbolUsagesQuery,
+		sqlf.Sprintf(string(kind)),
+		pq.Array(uploadIDs),
+		pq.Array(symbolNames),
+		sqlf.Sprintf(string(kind)),
+	)))
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(log.Int("numLocations", len(locations)))
+
+	return locations, nil
+}
+
+const getSymbolUsagesQuery = `
+SELECT
+	ss.upload_id,
+	sid.document_path,
+	ss.symbol_name,
+	ss.%s
+FROM codeintel_scip_symbols ss
+JOIN codeintel_scip_document_lookup sid ON sid.id = ss.document_lookup_id
+WHERE
+	ss.upload_id = ANY(%s) AND
+	ss.symbol_name = ANY(%s) AND
+	ss.%s IS NOT NULL
+ORDER BY ss.upload_id, sid.document_path, ss.symbol_name
+`
+
+func scanSymbolLocations(rows *sql.Rows, queryErr error) (_ []shared.SymbolLocation, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var locations []shared.SymbolLocation
+	for rows.Next() {
+		var (
+			uploadID      int
+			path, symbol  string
+			encodedRanges []byte
+		)
+		if err := rows.Scan(&uploadID, &path, &symbol, &encodedRanges); err != nil {
+			return nil, err
+		}
+
+		ranges, err := types.DecodeRanges(encodedRanges)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range ranges {
+			locations = append(locations, shared.SymbolLocation{
+				Symbol: symbol,
+				Location: shared.Location{
+					DumpID: uploadID,
+					Path:   path,
+					Range:  translateRange(r),
+				},
+			})
+		}
+	}
+
+	return locations, nil
+}
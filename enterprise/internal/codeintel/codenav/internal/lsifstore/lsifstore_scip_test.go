@@ -0,0 +1,163 @@
+package lsifstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+const (
+	testSCIPUploadID = 42
+	testCalleeSymbol = "scip-go gomod github.com/example/a v1.0.0 `github.com/example/a`/Callee()."
+	testCallerSymbol = "scip-go gomod github.com/example/a v1.0.0 `github.com/example/a`/Caller()."
+	testAnimalSymbol = "scip-go gomod github.com/example/a v1.0.0 `github.com/example/a`/Animal#"
+	testDogSymbol    = "scip-go gomod github.com/example/a v1.0.0 `github.com/example/a`/Dog#"
+)
+
+var testSCIPDocument = &scip.Document{
+	RelativePath: "a.go",
+	Occurrences: []*scip.Occurrence{
+		{Range: []int32{2, 5, 11}, Symbol: testCalleeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{6, 5, 11}, Symbol: testCallerSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{7, 1, 7}, Symbol: testCalleeSymbol},
+		{Range: []int32{8, 1, 7}, Symbol: testCalleeSymbol},
+	},
+}
+
+var testSCIPTypesDocument = &scip.Document{
+	RelativePath: "b.go",
+	Occurrences: []*scip.Occurrence{
+		{Range: []int32{2, 5, 11}, Symbol: testAnimalSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{6, 5, 8}, Symbol: testDogSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+	},
+	Symbols: []*scip.SymbolInformation{
+		{Symbol: testDogSymbol, Relationships: []*scip.Relationship{{Symbol: testAnimalSymbol, IsImplementation: true}}},
+	},
+}
+
+func TestGetSCIPDocument(t *testing.T) {
+	store := populateSCIPTestStore(t, testSCIPUploadID, testSCIPDocument)
+
+	document, exists, err := store.GetSCIPDocument(context.Background(), testSCIPUploadID, "a.go")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !exists {
+		t.Fatalf("expected document to exist")
+	}
+	if diff := cmp.Diff(testSCIPDocument.Occurrences, document.Occurrences, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("unexpected occurrences (-want +got):\n%s", diff)
+	}
+
+	if _, exists, err := store.GetSCIPDocument(context.Background(), testSCIPUploadID, "missing.go"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if exists {
+		t.Errorf("expected document not to exist")
+	}
+}
+
+func TestGetSymbolUsages(t *testing.T) {
+	store := populateSCIPTestStore(t, testSCIPUploadID, testSCIPDocument, testSCIPTypesDocument)
+
+	references, err := store.GetSymbolUsages(context.Background(), SymbolUsageReferences, []int{testSCIPUploadID}, []string{testCalleeSymbol})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expectedReferences := []shared.SymbolLocation{
+		{Symbol: testCalleeSymbol, Location: shared.Location{DumpID: testSCIPUploadID, Path: "a.go", Range: newRange(7, 1, 7, 7)}},
+		{Symbol: testCalleeSymbol, Location: shared.Location{DumpID: testSCIPUploadID, Path: "a.go", Range: newRange(8, 1, 8, 7)}},
+	}
+	if diff := cmp.Diff(expectedReferences, references); diff != "" {
+		t.Errorf("unexpected references (-want +got):\n%s", diff)
+	}
+
+	definitions, err := store.GetSymbolUsages(context.Background(), SymbolUsageDefinitions, []int{testSCIPUploadID}, []string{testCalleeSymbol, testCallerSymbol})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expectedDefinitions := []shared.SymbolLocation{
+		{Symbol: testCalleeSymbol, Location: shared.Location{DumpID: testSCIPUploadID, Path: "a.go", Range: newRange(2, 5, 2, 11)}},
+		{Symbol: testCallerSymbol, Location: shared.Location{DumpID: testSCIPUploadID, Path: "a.go", Range: newRange(6, 5, 6, 11)}},
+	}
+	if diff := cmp.Diff(expectedDefinitions, definitions); diff != "" {
+		t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+	}
+
+	implementations, err := store.GetSymbolUsages(context.Background(), SymbolUsageImplementations, []int{testSCIPUploadID}, []string{testAnimalSymbol})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expectedImplementations := []shared.SymbolLocation{
+		{Symbol: testAnimalSymbol, Location: shared.Location{DumpID: testSCIPUploadID, Path: "b.go", Range: newRange(6, 5, 6, 8)}},
+	}
+	if diff := cmp.Diff(expectedImplementations, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+}
+
+// populateSCIPTestStore inserts the given SCIP documents and their symbols for the given upload.
+func populateSCIPTestStore(t testing.TB, uploadID int, documents ...*scip.Document) LsifStore {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	db := basestore.NewWithHandle(codeIntelDB.Handle())
+	ctx := context.Background()
+
+	for _, document := range documents {
+		payload, err := proto.Marshal(types.CanonicalizeDocument(document))
+		if err != nil {
+			t.Fatalf("unexpected error marshalling document: %s", err)
+		}
+		hash := sha256.Sum256(payload)
+
+		documentLookupID, _, err := basestore.ScanFirstInt(db.Query(ctx, sqlf.Sprintf(`
+			WITH document AS (
+				INSERT INTO codeintel_scip_documents (schema_version, payload_hash, raw_scip_payload)
+				VALUES (1, %s, %s)
+				RETURNING id
+			)
+			INSERT INTO codeintel_scip_document_lookup (upload_id, document_path, document_id)
+			SELECT %s, %s, id FROM document
+			RETURNING id
+		`, hash[:], payload, uploadID, document.RelativePath)))
+		if err != nil {
+			t.Fatalf("unexpected error inserting document: %s", err)
+		}
+
+		for _, symbol := range types.ExtractSymbolIndexes(document) {
+			definitionRanges, err := types.EncodeRanges(symbol.DefinitionRanges)
+			if err != nil {
+				t.Fatalf("unexpected error encoding ranges: %s", err)
+			}
+			referenceRanges, err := types.EncodeRanges(symbol.ReferenceRanges)
+			if err != nil {
+				t.Fatalf("unexpected error encoding ranges: %s", err)
+			}
+			implementationRanges, err := types.EncodeRanges(symbol.ImplementationRanges)
+			if err != nil {
+				t.Fatalf("unexpected error encoding ranges: %s", err)
+			}
+
+			if err := db.Exec(ctx, sqlf.Sprintf(`
+				INSERT INTO codeintel_scip_symbols (upload_id, symbol_name, document_lookup_id, schema_version, definition_ranges, reference_ranges, implementation_ranges)
+				VALUES (%s, %s, %s, 1, %s, %s, %s)
+			`, uploadID, symbol.SymbolName, documentLookupID, definitionRanges, referenceRanges, implementationRanges)); err != nil {
+				t.Fatalf("unexpected error inserting symbol: %s", err)
+			}
+		}
+	}
+
+	return New(codeIntelDB, &observation.TestContext)
+}
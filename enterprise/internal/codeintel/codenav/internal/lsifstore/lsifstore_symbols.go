@@ -0,0 +1,144 @@
+package lsifstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// SearchSymbols returns the symbols defined by the given uploads whose names match the given search
+// arguments. Symbols are ordered by upload, path, and position.
+func (s *store) SearchSymbols(ctx context.Context, uploadIDs []int, args shared.SymbolSearchArgs) (_ []shared.IndexedSymbol, err error) {
+	ctx, trace, endObservation := s.operations.searchSymbols.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numUploadIDs", len(uploadIDs)),
+		log.String("uploadIDs", intsToString(uploadIDs)),
+		log.String("query", args.Query),
+		log.Bool("isRegExp", args.IsRegExp),
+		log.Bool("isCaseSensitive", args.IsCaseSensitive),
+		log.Int("limit", args.Limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(uploadIDs) == 0 || args.Limit <= 0 {
+		return nil, nil
+	}
+
+	symbols, err := scanIndexedSymbols(s.db.Query(ctx, sqlf.Sprintf(
+		searchSymbolsQuery,
+		pq.Array(uploadIDs),
+		sqlf.Join(makeSearchSymbolsConditions(args), " AND "),
+		args.Limit,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(log.Int("numSymbols", len(symbols)))
+
+	return symbols, nil
+}
+
+const searchSymbolsQuery = `
+SELECT
+	s.upload_id,
+	s.scheme,
+	s.symbol_name,
+	s.name,
+	s.kind,
+	s.package_manager,
+	s.package_name,
+	s.package_version,
+	s.path,
+	s.start_line,
+	s.start_character,
+	s.end_line,
+	s.end_character,
+	s.documentation
+FROM codeintel_symbol_index s
+WHERE
+	s.upload_id = ANY(%s) AND
+	%s
+ORDER BY s.upload_id, s.path, s.start_line, s.start_character
+LIMIT %s
+`
+
+// makeSearchSymbolsConditions returns the conditions matching the symbol name and path patterns
+// of the given search arguments. Regular expressions are evaluated by Postgres, which supports
+// the subset of RE2 syntax used by typical symbol queries.
+func makeSearchSymbolsConditions(args shared.SymbolSearchArgs) []*sqlf.Query {
+	matchOperator, notMatchOperator := "~*", "!~*"
+	if args.IsCaseSensitive {
+		matchOperator, notMatchOperator = "~", "!~"
+	}
+
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if args.Query != "" {
+		if args.IsRegExp {
+			conds = append(conds, sqlf.Sprintf("s.name "+matchOperator+" %s", args.Query))
+		} else if args.IsCaseSensitive {
+			conds = append(conds, sqlf.Sprintf("s.name LIKE %s", "%"+escapeLikePattern(args.Query)+"%"))
+		} else {
+			conds = append(conds, sqlf.Sprintf("s.name ILIKE %s", "%"+escapeLikePattern(args.Query)+"%"))
+		}
+	}
+	for _, pattern := range args.IncludePatterns {
+		conds = append(conds, sqlf.Sprintf("s.path "+matchOperator+" %s", pattern))
+	}
+	if args.ExcludePattern != "" {
+		conds = append(conds, sqlf.Sprintf("s.path "+notMatchOperator+" %s", args.ExcludePattern))
+	}
+
+	return conds
+}
+
+var likePatternReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLikePattern escapes the wildcard characters of the given literal so that it can be
+// embedded in a LIKE pattern.
+func escapeLikePattern(s string) string {
+	return likePatternReplacer.Replace(s)
+}
+
+func scanIndexedSymbols(rows *sql.Rows, queryErr error) (_ []shared.IndexedSymbol, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var symbols []shared.IndexedSymbol
+	for rows.Next() {
+		var symbol shared.IndexedSymbol
+		var r types.Range
+		if err := rows.Scan(
+			&symbol.UploadID,
+			&symbol.Scheme,
+			&symbol.Symbol,
+			&symbol.Name,
+			&symbol.Kind,
+			&symbol.PackageManager,
+			&symbol.PackageName,
+			&symbol.PackageVersion,
+			&symbol.Path,
+			&r.Start.Line,
+			&r.Start.Character,
+			&r.End.Line,
+			&r.End.Character,
+			&symbol.Documentation,
+		); err != nil {
+			return nil, err
+		}
+		symbol.Range = r
+
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, nil
+}
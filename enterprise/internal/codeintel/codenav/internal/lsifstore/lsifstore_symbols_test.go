@@ -0,0 +1,94 @@
+package lsifstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestSearchSymbols(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	db := basestore.NewWithHandle(codeIntelDB.Handle())
+	store := New(codeIntelDB, &observation.TestContext)
+	ctx := context.Background()
+
+	symbols := []shared.IndexedSymbol{
+		{UploadID: 50, Scheme: "gomod", Symbol: "github.com/foo/pkg:NewClient", Name: "NewClient", PackageName: "github.com/foo", Path: "pkg/client.go", Range: newRange(3, 5, 3, 14), Documentation: "func NewClient()"},
+		{UploadID: 50, Scheme: "gomod", Symbol: "github.com/foo/pkg:Client", Name: "Client", PackageName: "github.com/foo", Path: "pkg/client.go", Range: newRange(1, 5, 1, 11)},
+		{UploadID: 50, Scheme: "gomod", Symbol: "github.com/foo/pkg:new_client_100%", Name: "new_client_100%", Path: "pkg/client_test.go", Range: newRange(7, 5, 7, 20)},
+		{UploadID: 51, Scheme: "npm", Symbol: "client:createClient", Name: "createClient", Path: "web/client.ts", Range: newRange(2, 16, 2, 28)},
+		{UploadID: 52, Scheme: "npm", Symbol: "other:Client", Name: "Client", Path: "other/client.ts", Range: newRange(0, 0, 0, 6)},
+	}
+	for _, symbol := range symbols {
+		if err := db.Exec(ctx, sqlf.Sprintf(`
+			INSERT INTO codeintel_symbol_index (
+				upload_id, scheme, symbol_name, name, kind, package_manager, package_name, package_version,
+				path, start_line, start_character, end_line, end_character, documentation
+			) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+		`,
+			symbol.UploadID, symbol.Scheme, symbol.Symbol, symbol.Name, symbol.Kind, symbol.PackageManager, symbol.PackageName, symbol.PackageVersion,
+			symbol.Path, symbol.Range.Start.Line, symbol.Range.Start.Character, symbol.Range.End.Line, symbol.Range.End.Character, symbol.Documentation,
+		)); err != nil {
+			t.Fatalf("unexpected error inserting symbol: %s", err)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		args     shared.SymbolSearchArgs
+		expected []shared.IndexedSymbol
+	}{
+		{
+			name:     "literal",
+			args:     shared.SymbolSearchArgs{Query: "client", Limit: 10},
+			expected: []shared.IndexedSymbol{symbols[1], symbols[0], symbols[2], symbols[3]},
+		},
+		{
+			name:     "case sensitive literal",
+			args:     shared.SymbolSearchArgs{Query: "Client", IsCaseSensitive: true, Limit: 10},
+			expected: []shared.IndexedSymbol{symbols[1], symbols[0], symbols[3]},
+		},
+		{
+			name:     "literal wildcards",
+			args:     shared.SymbolSearchArgs{Query: "_100%", Limit: 10},
+			expected: []shared.IndexedSymbol{symbols[2]},
+		},
+		{
+			name:     "regexp",
+			args:     shared.SymbolSearchArgs{Query: "^(new|create)client$", IsRegExp: true, Limit: 10},
+			expected: []shared.IndexedSymbol{symbols[0], symbols[3]},
+		},
+		{
+			name:     "include and exclude patterns",
+			args:     shared.SymbolSearchArgs{Query: "client", IncludePatterns: []string{`^pkg/`}, ExcludePattern: `_test\.go$`, Limit: 10},
+			expected: []shared.IndexedSymbol{symbols[1], symbols[0]},
+		},
+		{
+			name:     "limit",
+			args:     shared.SymbolSearchArgs{Query: "client", Limit: 1},
+			expected: []shared.IndexedSymbol{symbols[1]},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			results, err := store.SearchSymbols(ctx, []int{50, 51}, testCase.args)
+			if err != nil {
+				t.Fatalf("unexpected error searching symbols: %s", err)
+			}
+			if diff := cmp.Diff(testCase.expected, results); diff != "" {
+				t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ type operations struct {
 	getPackageInformation  *observation.Operation
 	getBulkMonikerResults  *observation.Operation
 	getLocationsWithinFile *observation.Operation
+	getSCIPDocument        *observation.Operation
+	getSymbolUsages        *observation.Operation
+	searchSymbols          *observation.Operation
 
 	locations *observation.Operation
 }
@@ -62,6 +65,9 @@ func newOperations(observationContext *observation.Context) *operations {
 		getPackageInformation:  op("GetPackageInformation"),
 		getBulkMonikerResults:  op("GetBulkMonikerResults"),
 		getLocationsWithinFile: op("GetLocationsWithinFile"),
+		getSCIPDocument:        op("GetSCIPDocument"),
+		getSymbolUsages:        op("GetSymbolUsages"),
+		searchSymbols:          op("SearchSymbols"),
 
 		locations: subOp("locations"),
 	}
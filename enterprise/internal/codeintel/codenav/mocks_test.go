@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	diff "github.com/sourcegraph/go-diff/diff"
+	scip "github.com/sourcegraph/scip/bindings/go/scip"
 	lsifstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/lsifstore"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/store"
 	shared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
@@ -19,6 +20,8 @@ import (
 	api "github.com/sourcegraph/sourcegraph/internal/api"
 	authz "github.com/sourcegraph/sourcegraph/internal/authz"
 	database "github.com/sourcegraph/sourcegraph/internal/database"
+	search "github.com/sourcegraph/sourcegraph/internal/search"
+	result "github.com/sourcegraph/sourcegraph/internal/search/result"
 	precise "github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -200,9 +203,18 @@ type MockLsifStore struct {
 	// GetReferenceLocationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetReferenceLocations.
 	GetReferenceLocationsFunc *LsifStoreGetReferenceLocationsFunc
+	// GetSCIPDocumentFunc is an instance of a mock function object
+	// controlling the behavior of the method GetSCIPDocument.
+	GetSCIPDocumentFunc *LsifStoreGetSCIPDocumentFunc
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *LsifStoreGetStencilFunc
+	// GetSymbolUsagesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetSymbolUsages.
+	GetSymbolUsagesFunc *LsifStoreGetSymbolUsagesFunc
+	// SearchSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method SearchSymbols.
+	SearchSymbolsFunc *LsifStoreSearchSymbolsFunc
 }
 
 // NewMockLsifStore creates a new mock of the LsifStore interface. All
@@ -259,11 +271,26 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		GetSCIPDocumentFunc: &LsifStoreGetSCIPDocumentFunc{
+			defaultHook: func(context.Context, int, string) (r0 *scip.Document, r1 bool, r2 error) {
+				return
+			},
+		},
 		GetStencilFunc: &LsifStoreGetStencilFunc{
 			defaultHook: func(context.Context, int, string) (r0 []types.Range, r1 error) {
 				return
 			},
 		},
+		GetSymbolUsagesFunc: &LsifStoreGetSymbolUsagesFunc{
+			defaultHook: func(context.Context, lsifstore.SymbolUsageKind, []int, []string) (r0 []shared.SymbolLocation, r1 error) {
+				return
+			},
+		},
+		SearchSymbolsFunc: &LsifStoreSearchSymbolsFunc{
+			defaultHook: func(context.Context, []int, shared.SymbolSearchArgs) (r0 []shared.IndexedSymbol, r1 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -321,11 +348,26 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.GetReferenceLocations")
 			},
 		},
+		GetSCIPDocumentFunc: &LsifStoreGetSCIPDocumentFunc{
+			defaultHook: func(context.Context, int, string) (*scip.Document, bool, error) {
+				panic("unexpected invocation of MockLsifStore.GetSCIPDocument")
+			},
+		},
 		GetStencilFunc: &LsifStoreGetStencilFunc{
 			defaultHook: func(context.Context, int, string) ([]types.Range, error) {
 				panic("unexpected invocation of MockLsifStore.GetStencil")
 			},
 		},
+		GetSymbolUsagesFunc: &LsifStoreGetSymbolUsagesFunc{
+			defaultHook: func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error) {
+				panic("unexpected invocation of MockLsifStore.GetSymbolUsages")
+			},
+		},
+		SearchSymbolsFunc: &LsifStoreSearchSymbolsFunc{
+			defaultHook: func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error) {
+				panic("unexpected invocation of MockLsifStore.SearchSymbols")
+			},
+		},
 	}
 }
 
@@ -363,9 +405,18 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		GetReferenceLocationsFunc: &LsifStoreGetReferenceLocationsFunc{
 			defaultHook: i.GetReferenceLocations,
 		},
+		GetSCIPDocumentFunc: &LsifStoreGetSCIPDocumentFunc{
+			defaultHook: i.GetSCIPDocument,
+		},
 		GetStencilFunc: &LsifStoreGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetSymbolUsagesFunc: &LsifStoreGetSymbolUsagesFunc{
+			defaultHook: i.GetSymbolUsages,
+		},
+		SearchSymbolsFunc: &LsifStoreSearchSymbolsFunc{
+			defaultHook: i.SearchSymbols,
+		},
 	}
 }
 
@@ -1588,6 +1639,120 @@ func (c LsifStoreGetReferenceLocationsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// LsifStoreGetSCIPDocumentFunc describes the behavior when the
+// GetSCIPDocument method of the parent MockLsifStore instance is invoked.
+type LsifStoreGetSCIPDocumentFunc struct {
+	defaultHook func(context.Context, int, string) (*scip.Document, bool, error)
+	hooks       []func(context.Context, int, string) (*scip.Document, bool, error)
+	history     []LsifStoreGetSCIPDocumentFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSCIPDocument delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetSCIPDocument(v0 context.Context, v1 int, v2 string) (*scip.Document, bool, error) {
+	r0, r1, r2 := m.GetSCIPDocumentFunc.nextHook()(v0, v1, v2)
+	m.GetSCIPDocumentFunc.appendCall(LsifStoreGetSCIPDocumentFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetSCIPDocument
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreGetSCIPDocumentFunc) SetDefaultHook(hook func(context.Context, int, string) (*scip.Document, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSCIPDocument method of the parent MockLsifStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LsifStoreGetSCIPDocumentFunc) PushHook(hook func(context.Context, int, string) (*scip.Document, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetSCIPDocumentFunc) SetDefaultReturn(r0 *scip.Document, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string) (*scip.Document, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetSCIPDocumentFunc) PushReturn(r0 *scip.Document, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int, string) (*scip.Document, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LsifStoreGetSCIPDocumentFunc) nextHook() func(context.Context, int, string) (*scip.Document, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetSCIPDocumentFunc) appendCall(r0 LsifStoreGetSCIPDocumentFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetSCIPDocumentFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetSCIPDocumentFunc) History() []LsifStoreGetSCIPDocumentFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetSCIPDocumentFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetSCIPDocumentFuncCall is an object that describes an
+// invocation of method GetSCIPDocument on an instance of MockLsifStore.
+type LsifStoreGetSCIPDocumentFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *scip.Document
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetSCIPDocumentFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetSCIPDocumentFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // LsifStoreGetStencilFunc describes the behavior when the GetStencil method
 // of the parent MockLsifStore instance is invoked.
 type LsifStoreGetStencilFunc struct {
@@ -1698,6 +1863,231 @@ func (c LsifStoreGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// LsifStoreGetSymbolUsagesFunc describes the behavior when the
+// GetSymbolUsages method of the parent MockLsifStore instance is invoked.
+type LsifStoreGetSymbolUsagesFunc struct {
+	defaultHook func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error)
+	hooks       []func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error)
+	history     []LsifStoreGetSymbolUsagesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSymbolUsages delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) GetSymbolUsages(v0 context.Context, v1 lsifstore.SymbolUsageKind, v2 []int, v3 []string) ([]shared.SymbolLocation, error) {
+	r0, r1 := m.GetSymbolUsagesFunc.nextHook()(v0, v1, v2, v3)
+	m.GetSymbolUsagesFunc.appendCall(LsifStoreGetSymbolUsagesFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetSymbolUsages
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreGetSymbolUsagesFunc) SetDefaultHook(hook func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSymbolUsages method of the parent MockLsifStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LsifStoreGetSymbolUsagesFunc) PushHook(hook func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreGetSymbolUsagesFunc) SetDefaultReturn(r0 []shared.SymbolLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreGetSymbolUsagesFunc) PushReturn(r0 []shared.SymbolLocation, r1 error) {
+	f.PushHook(func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreGetSymbolUsagesFunc) nextHook() func(context.Context, lsifstore.SymbolUsageKind, []int, []string) ([]shared.SymbolLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreGetSymbolUsagesFunc) appendCall(r0 LsifStoreGetSymbolUsagesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreGetSymbolUsagesFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreGetSymbolUsagesFunc) History() []LsifStoreGetSymbolUsagesFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreGetSymbolUsagesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreGetSymbolUsagesFuncCall is an object that describes an
+// invocation of method GetSymbolUsages on an instance of MockLsifStore.
+type LsifStoreGetSymbolUsagesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 lsifstore.SymbolUsageKind
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.SymbolLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreGetSymbolUsagesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreGetSymbolUsagesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LsifStoreSearchSymbolsFunc describes the behavior when the SearchSymbols
+// method of the parent MockLsifStore instance is invoked.
+type LsifStoreSearchSymbolsFunc struct {
+	defaultHook func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error)
+	hooks       []func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error)
+	history     []LsifStoreSearchSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchSymbols delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLsifStore) SearchSymbols(v0 context.Context, v1 []int, v2 shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error) {
+	r0, r1 := m.SearchSymbolsFunc.nextHook()(v0, v1, v2)
+	m.SearchSymbolsFunc.appendCall(LsifStoreSearchSymbolsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchSymbols method
+// of the parent MockLsifStore instance is invoked and the hook queue is
+// empty.
+func (f *LsifStoreSearchSymbolsFunc) SetDefaultHook(hook func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchSymbols method of the parent MockLsifStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LsifStoreSearchSymbolsFunc) PushHook(hook func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreSearchSymbolsFunc) SetDefaultReturn(r0 []shared.IndexedSymbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreSearchSymbolsFunc) PushReturn(r0 []shared.IndexedSymbol, r1 error) {
+	f.PushHook(func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreSearchSymbolsFunc) nextHook() func(context.Context, []int, shared.SymbolSearchArgs) ([]shared.IndexedSymbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreSearchSymbolsFunc) appendCall(r0 LsifStoreSearchSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreSearchSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreSearchSymbolsFunc) History() []LsifStoreSearchSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreSearchSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreSearchSymbolsFuncCall is an object that describes an invocation
+// of method SearchSymbols on an instance of MockLsifStore.
+type LsifStoreSearchSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 shared.SymbolSearchArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.IndexedSymbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreSearchSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreSearchSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockGitTreeTranslator is a mock implementation of the GitTreeTranslator
 // interface (from the package
 // github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav)
@@ -2447,6 +2837,158 @@ func (c GitserverClientDiffPathFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// MockSymbolsClient is a mock implementation of the SymbolsClient interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav)
+// used for unit testing.
+type MockSymbolsClient struct {
+	// SearchFunc is an instance of a mock function object controlling the
+	// behavior of the method Search.
+	SearchFunc *SymbolsClientSearchFunc
+}
+
+// NewMockSymbolsClient creates a new mock of the SymbolsClient interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockSymbolsClient() *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: func(context.Context, search.SymbolsParameters) (r0 []result.Symbol, r1 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockSymbolsClient creates a new mock of the SymbolsClient
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockSymbolsClient() *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+				panic("unexpected invocation of MockSymbolsClient.Search")
+			},
+		},
+	}
+}
+
+// NewMockSymbolsClientFrom creates a new mock of the MockSymbolsClient
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockSymbolsClientFrom(i SymbolsClient) *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: i.Search,
+		},
+	}
+}
+
+// SymbolsClientSearchFunc describes the behavior when the Search method of
+// the parent MockSymbolsClient instance is invoked.
+type SymbolsClientSearchFunc struct {
+	defaultHook func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)
+	hooks       []func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)
+	history     []SymbolsClientSearchFuncCall
+	mutex       sync.Mutex
+}
+
+// Search delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSymbolsClient) Search(v0 context.Context, v1 search.SymbolsParameters) ([]result.Symbol, error) {
+	r0, r1 := m.SearchFunc.nextHook()(v0, v1)
+	m.SearchFunc.appendCall(SymbolsClientSearchFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Search method of the
+// parent MockSymbolsClient instance is invoked and the hook queue is empty.
+func (f *SymbolsClientSearchFunc) SetDefaultHook(hook func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Search method of the parent MockSymbolsClient instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *SymbolsClientSearchFunc) PushHook(hook func(context.Context, search.SymbolsParameters) ([]result.Symbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SymbolsClientSearchFunc) SetDefaultReturn(r0 []result.Symbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SymbolsClientSearchFunc) PushReturn(r0 []result.Symbol, r1 error) {
+	f.PushHook(func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *SymbolsClientSearchFunc) nextHook() func(context.Context, search.SymbolsParameters) ([]result.Symbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SymbolsClientSearchFunc) appendCall(r0 SymbolsClientSearchFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SymbolsClientSearchFuncCall objects
+// describing the invocations of this function.
+func (f *SymbolsClientSearchFunc) History() []SymbolsClientSearchFuncCall {
+	f.mutex.Lock()
+	history := make([]SymbolsClientSearchFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SymbolsClientSearchFuncCall is an object that describes an invocation of
+// method Search on an instance of MockSymbolsClient.
+type SymbolsClientSearchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 search.SymbolsParameters
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []result.Symbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SymbolsClientSearchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SymbolsClientSearchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockUploadService is a mock implementation of the UploadService interface
 // (from the package
 // github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav)
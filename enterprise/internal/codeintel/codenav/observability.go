@@ -19,6 +19,13 @@ type operations struct {
 	getDefinitions         *observation.Operation
 	getRanges              *observation.Operation
 	getStencil             *observation.Operation
+	getIncomingCalls       *observation.Operation
+	getOutgoingCalls       *observation.Operation
+	getSupertypes          *observation.Operation
+	getSubtypes            *observation.Operation
+	getPrototypes          *observation.Operation
+	getDocumentSymbols     *observation.Operation
+	searchPreciseSymbols   *observation.Operation
 	getDumpsByIDs          *observation.Operation
 	getClosestDumpsForBlob *observation.Operation
 }
@@ -47,6 +54,13 @@ func newOperations(observationContext *observation.Context) *operations {
 		getDefinitions:         op("getDefinitions"),
 		getRanges:              op("getRanges"),
 		getStencil:             op("getStencil"),
+		getIncomingCalls:       op("getIncomingCalls"),
+		getOutgoingCalls:       op("getOutgoingCalls"),
+		getSupertypes:          op("getSupertypes"),
+		getSubtypes:            op("getSubtypes"),
+		getPrototypes:          op("getPrototypes"),
+		getDocumentSymbols:     op("getDocumentSymbols"),
+		searchPreciseSymbols:   op("searchPreciseSymbols"),
 		getDumpsByIDs:          op("GetDumpsByIDs"),
 		getClosestDumpsForBlob: op("GetClosestDumpsForBlob"),
 	}
@@ -26,6 +26,7 @@ type Service struct {
 	lsifstore  lsifstore.LsifStore
 	gitserver  GitserverClient
 	uploadSvc  UploadService
+	symbols    SymbolsClient
 	operations *operations
 	logger     log.Logger
 }
@@ -35,6 +36,7 @@ func newService(
 	lsifstore lsifstore.LsifStore,
 	uploadSvc UploadService,
 	gitserver GitserverClient,
+	symbols SymbolsClient,
 	observationContext *observation.Context,
 ) *Service {
 	return &Service{
@@ -42,6 +44,7 @@ func newService(
 		lsifstore:  lsifstore,
 		gitserver:  gitserver,
 		uploadSvc:  uploadSvc,
+		symbols:    symbols,
 		operations: newOperations(observationContext),
 		logger:     log.Scoped("codenav", ""),
 	}
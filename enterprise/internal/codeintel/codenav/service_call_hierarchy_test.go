@@ -0,0 +1,199 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const (
+	testCalleeSymbol = "scip-go gomod github.com/example/a v1.0.0 `github.com/example/a`/Callee()."
+	testCallerSymbol = "scip-go gomod github.com/example/a v1.0.0 `github.com/example/a`/Caller()."
+)
+
+var (
+	testCalleeDefinitionRange = types.Range{Start: types.Position{Line: 2, Character: 5}, End: types.Position{Line: 2, Character: 11}}
+	testCallerDefinitionRange = types.Range{Start: types.Position{Line: 6, Character: 5}, End: types.Position{Line: 6, Character: 11}}
+	testCallSiteRange1        = types.Range{Start: types.Position{Line: 7, Character: 1}, End: types.Position{Line: 7, Character: 7}}
+	testCallSiteRange2        = types.Range{Start: types.Position{Line: 8, Character: 1}, End: types.Position{Line: 8, Character: 7}}
+
+	// func Callee() {}
+	//
+	// func Caller() {
+	//     Callee()
+	//     Callee()
+	// }
+	testCallHierarchyDocument = &scip.Document{
+		RelativePath: "a.go",
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{2, 5, 11}, Symbol: testCalleeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{6, 5, 11}, Symbol: testCallerSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{7, 1, 7}, Symbol: testCalleeSymbol},
+			{Range: []int32{8, 1, 7}, Symbol: testCalleeSymbol},
+		},
+	}
+)
+
+func TestIncomingCalls(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), &observation.TestContext)
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	mockLsifStore.GetSCIPDocumentFunc.SetDefaultReturn(testCallHierarchyDocument, true, nil)
+	mockLsifStore.GetSymbolUsagesFunc.SetDefaultReturn([]shared.SymbolLocation{
+		{Symbol: testCalleeSymbol, Location: shared.Location{DumpID: 50, Path: "a.go", Range: testCallSiteRange1}},
+		{Symbol: testCalleeSymbol, Location: shared.Location{DumpID: 50, Path: "a.go", Range: testCallSiteRange2}},
+	}, nil)
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         2,
+		Character:    6,
+		Limit:        50,
+	}
+	calls, err := svc.GetIncomingCalls(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying incoming calls: %s", err)
+	}
+
+	expectedCalls := []shared.CallHierarchyCall{
+		{
+			Symbol: testCallerSymbol,
+			Name:   "Caller",
+			Definitions: []types.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testCallerDefinitionRange},
+			},
+			CallSites: []types.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testCallSiteRange1},
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testCallSiteRange2},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+
+	if history := mockLsifStore.GetSymbolUsagesFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of symbol usage queries. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != lsifstore.SymbolUsageReferences {
+		t.Errorf("unexpected symbol usage kind. want=%q have=%q", lsifstore.SymbolUsageReferences, history[0].Arg1)
+	} else if diff := cmp.Diff([]string{testCalleeSymbol}, history[0].Arg3); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), &observation.TestContext)
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	mockLsifStore.GetSCIPDocumentFunc.SetDefaultReturn(testCallHierarchyDocument, true, nil)
+	mockLsifStore.GetSymbolUsagesFunc.SetDefaultHook(func(_ context.Context, kind lsifstore.SymbolUsageKind, _ []int, symbolNames []string) ([]shared.SymbolLocation, error) {
+		var locations []shared.SymbolLocation
+		for _, symbolName := range symbolNames {
+			switch symbolName {
+			case testCallerSymbol:
+				locations = append(locations, shared.SymbolLocation{Symbol: symbolName, Location: shared.Location{DumpID: 50, Path: "a.go", Range: testCallerDefinitionRange}})
+			case testCalleeSymbol:
+				locations = append(locations, shared.SymbolLocation{Symbol: symbolName, Location: shared.Location{DumpID: 50, Path: "a.go", Range: testCalleeDefinitionRange}})
+			}
+		}
+
+		return locations, nil
+	})
+
+	mockRequest := shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         6,
+		Character:    6,
+		Limit:        50,
+	}
+	calls, err := svc.GetOutgoingCalls(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying outgoing calls: %s", err)
+	}
+
+	expectedCalls := []shared.CallHierarchyCall{
+		{
+			Symbol: testCalleeSymbol,
+			Name:   "Callee",
+			Definitions: []types.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testCalleeDefinitionRange},
+			},
+			CallSites: []types.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testCallSiteRange1},
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: "deadbeef", TargetRange: testCallSiteRange2},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindEnclosingCallable(t *testing.T) {
+	callables := findCallableDefinitions(testCallHierarchyDocument, newSymbolTable())
+
+	testCases := []struct {
+		r              types.Range
+		expectedSymbol string
+	}{
+		{r: types.Range{Start: types.Position{Line: 0, Character: 0}}, expectedSymbol: ""},
+		{r: types.Range{Start: types.Position{Line: 3, Character: 2}}, expectedSymbol: testCalleeSymbol},
+		{r: testCallSiteRange1, expectedSymbol: testCallerSymbol},
+		{r: types.Range{Start: types.Position{Line: 100, Character: 0}}, expectedSymbol: testCallerSymbol},
+	}
+
+	for _, testCase := range testCases {
+		callable, _ := findEnclosingCallable(callables, testCase.r)
+		if callable.symbol != testCase.expectedSymbol {
+			t.Errorf("unexpected enclosing callable for %v. want=%q have=%q", testCase.r, testCase.expectedSymbol, callable.symbol)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestDocumentSymbols(t *testing.T) {
+	svc, mockLsifStore, mockSymbolsClient, mockRequestState := setupDocumentSymbolsTest()
+	mockLsifStore.GetSCIPDocumentFunc.SetDefaultReturn(testTypeHierarchyDocument, true, nil)
+
+	documentSymbols, err := svc.GetDocumentSymbols(context.Background(), documentSymbolsRequest(), mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying document symbols: %s", err)
+	}
+
+	precise := func(symbol, name, kind string, children ...shared.DocumentSymbol) shared.DocumentSymbol {
+		return shared.DocumentSymbol{
+			Name:       name,
+			Kind:       kind,
+			Symbol:     symbol,
+			Range:      testTypeDefinitionRanges[symbol],
+			Provenance: shared.DocumentSymbolProvenancePrecise,
+			Children:   children,
+		}
+	}
+	expectedDocumentSymbols := []shared.DocumentSymbol{
+		precise(testAnimalSymbol, "Animal", "CLASS", precise(testAnimalSoundSymbol, "sound", "METHOD")),
+		precise(testDogSymbol, "Dog", "CLASS", precise(testDogSoundSymbol, "sound", "METHOD")),
+		precise(testPuppySymbol, "Puppy", "CLASS"),
+		precise(testPetSymbol, "pet", "FIELD"),
+	}
+	if diff := cmp.Diff(expectedDocumentSymbols, documentSymbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+
+	if history := mockLsifStore.GetSCIPDocumentFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of document queries. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != 50 || history[0].Arg2 != "main.go" {
+		t.Errorf("unexpected document query. want=(%d, %q) have=(%d, %q)", 50, "main.go", history[0].Arg1, history[0].Arg2)
+	}
+	if history := mockSymbolsClient.SearchFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected symbols service queries. want=%d have=%d", 0, len(history))
+	}
+}
+
+func TestDocumentSymbolsSearchBasedFallback(t *testing.T) {
+	svc, _, mockSymbolsClient, mockRequestState := setupDocumentSymbolsTest()
+	mockSymbolsClient.SearchFunc.SetDefaultReturn([]result.Symbol{
+		{Name: "Dog", Kind: "class", Line: 6, Character: 6},
+		{Name: "sound", Kind: "method", Parent: "com.example.Dog", Line: 7, Character: 9},
+		{Name: "Animal", Kind: "interface", Line: 2, Character: 10},
+		{Name: "sound", Kind: "method", Parent: "Animal", Line: 3, Character: 9},
+		{Name: "main", Kind: "function", Parent: "Unknown", Line: 10, Character: 5},
+	}, nil)
+
+	documentSymbols, err := svc.GetDocumentSymbols(context.Background(), documentSymbolsRequest(), mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying document symbols: %s", err)
+	}
+
+	searchBased := func(name, kind string, line, character int, children ...shared.DocumentSymbol) shared.DocumentSymbol {
+		return shared.DocumentSymbol{
+			Name: name,
+			Kind: kind,
+			Range: types.Range{
+				Start: types.Position{Line: line, Character: character},
+				End:   types.Position{Line: line, Character: character + len(name)},
+			},
+			Provenance: shared.DocumentSymbolProvenanceSearchBased,
+			Children:   children,
+		}
+	}
+	expectedDocumentSymbols := []shared.DocumentSymbol{
+		searchBased("Animal", "INTERFACE", 1, 10, searchBased("sound", "METHOD", 2, 9)),
+		searchBased("Dog", "CLASS", 5, 6, searchBased("sound", "METHOD", 6, 9)),
+		searchBased("main", "FUNCTION", 9, 5),
+	}
+	if diff := cmp.Diff(expectedDocumentSymbols, documentSymbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+
+	if history := mockSymbolsClient.SearchFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of symbols service queries. want=%d have=%d", 1, len(history))
+	} else {
+		args := history[0].Arg1
+		if args.Repo != "github.com/sourcegraph/sourcegraph" || string(args.CommitID) != mockCommit {
+			t.Errorf("unexpected repository. want=%q@%q have=%q@%q", "github.com/sourcegraph/sourcegraph", mockCommit, args.Repo, args.CommitID)
+		}
+		if diff := cmp.Diff([]string{`^s1/main\.go$`}, args.IncludePatterns); diff != "" {
+			t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestDocumentSymbolsLocalSymbolsOnly(t *testing.T) {
+	svc, mockLsifStore, mockSymbolsClient, mockRequestState := setupDocumentSymbolsTest()
+	mockLsifStore.GetSCIPDocumentFunc.SetDefaultReturn(&scip.Document{
+		RelativePath: "main.go",
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{2, 10, 16}, Symbol: "local 12", SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{6, 6, 9}, Symbol: "local 14", SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+	}, true, nil)
+	mockSymbolsClient.SearchFunc.SetDefaultReturn([]result.Symbol{
+		{Name: "Dog", Kind: "class", Line: 6, Character: 6},
+	}, nil)
+
+	documentSymbols, err := svc.GetDocumentSymbols(context.Background(), documentSymbolsRequest(), mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying document symbols: %s", err)
+	}
+
+	expectedDocumentSymbols := []shared.DocumentSymbol{
+		{
+			Name: "Dog",
+			Kind: "CLASS",
+			Range: types.Range{
+				Start: types.Position{Line: 5, Character: 6},
+				End:   types.Position{Line: 5, Character: 9},
+			},
+			Provenance: shared.DocumentSymbolProvenanceSearchBased,
+		},
+	}
+	if diff := cmp.Diff(expectedDocumentSymbols, documentSymbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+
+	if history := mockSymbolsClient.SearchFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of symbols service queries. want=%d have=%d", 1, len(history))
+	}
+}
+
+func setupDocumentSymbolsTest() (*Service, *MockLsifStore, *MockSymbolsClient, RequestState) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockSymbolsClient := NewMockSymbolsClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), &observation.TestContext)
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, mockSymbolsClient, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{RepositoryName: "github.com/sourcegraph/sourcegraph"}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	mockRequestState.SetUploadsDataLoader([]types.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "s1/"},
+	})
+
+	return svc, mockLsifStore, mockSymbolsClient, mockRequestState
+}
+
+func documentSymbolsRequest() shared.RequestArgs {
+	return shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Limit:        50,
+	}
+}
@@ -0,0 +1,209 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-diff/diff"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+func TestSearchPreciseSymbols(t *testing.T) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	mockUploadSvc.InferClosestUploadsFunc.SetDefaultReturn([]types.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+	}, nil)
+	mockLsifStore.SearchSymbolsFunc.SetDefaultReturn([]shared.IndexedSymbol{
+		{
+			UploadID:       50,
+			Scheme:         "scip-go",
+			Symbol:         "scip-go gomod github.com/foo/bar v1.2.3 `github.com/foo/bar/pkg`/Widget#",
+			Name:           "Widget",
+			Kind:           "type",
+			PackageManager: "gomod",
+			PackageName:    "github.com/foo/bar",
+			PackageVersion: "v1.2.3",
+			Path:           "sub1/pkg/widget.go",
+			Range:          types.Range{Start: types.Position{Line: 10, Character: 5}, End: types.Position{Line: 10, Character: 11}},
+			Documentation:  "Widget is a widget.",
+		},
+		{
+			UploadID: 51,
+			Scheme:   "npm",
+			Symbol:   "index:WidgetFactory",
+			Name:     "WidgetFactory",
+			Path:     "sub2/src/index.ts",
+			Range:    types.Range{Start: types.Position{Line: 3, Character: 0}, End: types.Position{Line: 3, Character: 13}},
+		},
+	}, nil)
+
+	symbols, err := svc.SearchPreciseSymbols(context.Background(), 42, search.SymbolsParameters{
+		CommitID:        api.CommitID("deadbeef"),
+		Query:           "^Widget",
+		IsRegExp:        true,
+		IncludePatterns: []string{`\.go$`},
+		First:           11,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error searching symbols: %s", err)
+	}
+
+	expectedSymbols := []result.Symbol{
+		{
+			Name:               "Widget",
+			Path:               "sub1/pkg/widget.go",
+			Line:               11,
+			Character:          5,
+			Kind:               "type",
+			FullyQualifiedName: "scip-go gomod github.com/foo/bar v1.2.3 `github.com/foo/bar/pkg`/Widget#",
+			Package:            "github.com/foo/bar@v1.2.3",
+			Documentation:      "Widget is a widget.",
+		},
+		{
+			Name:               "WidgetFactory",
+			Path:               "sub2/src/index.ts",
+			Line:               4,
+			Character:          0,
+			FullyQualifiedName: "index:WidgetFactory",
+		},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+
+	if history := mockUploadSvc.InferClosestUploadsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of InferClosestUploads calls. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != 42 || history[0].Arg2 != "deadbeef" || history[0].Arg3 != "" {
+		t.Errorf("unexpected InferClosestUploads args: %d %q %q", history[0].Arg1, history[0].Arg2, history[0].Arg3)
+	}
+
+	if history := mockLsifStore.SearchSymbolsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of SearchSymbols calls. want=%d have=%d", 1, len(history))
+	} else {
+		if diff := cmp.Diff([]int{50, 51}, history[0].Arg1); diff != "" {
+			t.Errorf("unexpected upload ids (-want +got):\n%s", diff)
+		}
+
+		expectedArgs := shared.SymbolSearchArgs{
+			Query:           "^Widget",
+			IsRegExp:        true,
+			IncludePatterns: []string{`\.go$`},
+			Limit:           11,
+		}
+		if diff := cmp.Diff(expectedArgs, history[0].Arg2); diff != "" {
+			t.Errorf("unexpected search args (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestSearchPreciseSymbolsTranslatesRanges(t *testing.T) {
+	// Set up mocks
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+
+	// Init service
+	svc := newService(NewMockStore(), mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	mockUploadSvc.InferClosestUploadsFunc.SetDefaultReturn([]types.Dump{
+		{ID: 50, Commit: "cafebabe", Root: "sub1/"},
+		{ID: 51, Commit: "cafebabe", Root: "sub2/"},
+	}, nil)
+	mockLsifStore.SearchSymbolsFunc.SetDefaultReturn([]shared.IndexedSymbol{
+		{
+			UploadID: 50,
+			Symbol:   "a",
+			Name:     "Widget",
+			Path:     "sub1/pkg/widget.go",
+			Range:    types.Range{Start: types.Position{Line: 10, Character: 5}, End: types.Position{Line: 10, Character: 11}},
+		},
+		{
+			UploadID: 50,
+			Symbol:   "b",
+			Name:     "WidgetOption",
+			Path:     "sub1/pkg/widget.go",
+			Range:    types.Range{Start: types.Position{Line: 20, Character: 5}, End: types.Position{Line: 20, Character: 17}},
+		},
+		{
+			UploadID: 51,
+			Symbol:   "c",
+			Name:     "WidgetFactory",
+			Path:     "sub2/src/index.ts",
+			Range:    types.Range{Start: types.Position{Line: 3, Character: 0}, End: types.Position{Line: 3, Character: 13}},
+		},
+	}, nil)
+
+	mockGitserverClient.DiffPathFunc.SetDefaultHook(func(_ context.Context, _ authz.SubRepoPermissionChecker, _ api.RepoName, _, _, path string) ([]*diff.Hunk, error) {
+		switch path {
+		case "sub1/pkg/widget.go":
+			// Two lines added at the top of the file
+			return []*diff.Hunk{{OrigStartLine: 1, OrigLines: 1, NewStartLine: 1, NewLines: 3, Body: []byte(" a\n+b\n+c\n")}}, nil
+		case "sub2/src/index.ts":
+			// The line defining the symbol was edited
+			return []*diff.Hunk{{OrigStartLine: 3, OrigLines: 3, NewStartLine: 3, NewLines: 3, Body: []byte(" x\n-old\n+new\n y\n")}}, nil
+		}
+		return nil, nil
+	})
+
+	symbols, err := svc.SearchPreciseSymbols(context.Background(), 42, search.SymbolsParameters{
+		Repo:     api.RepoName("github.com/test/repo"),
+		CommitID: api.CommitID("deadbeef"),
+		Query:    "Widget",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error searching symbols: %s", err)
+	}
+
+	expectedSymbols := []result.Symbol{
+		{Name: "Widget", Path: "sub1/pkg/widget.go", Line: 13, Character: 5, FullyQualifiedName: "a"},
+		{Name: "WidgetOption", Path: "sub1/pkg/widget.go", Line: 23, Character: 5, FullyQualifiedName: "b"},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+
+	// Hunks are read once per path
+	history := mockGitserverClient.DiffPathFunc.History()
+	if len(history) != 2 {
+		t.Fatalf("unexpected number of DiffPath calls. want=%d have=%d", 2, len(history))
+	}
+	for _, call := range history {
+		if call.Arg2 != "github.com/test/repo" || call.Arg3 != "cafebabe" || call.Arg4 != "deadbeef" {
+			t.Errorf("unexpected DiffPath args: %q %q %q", call.Arg2, call.Arg3, call.Arg4)
+		}
+	}
+}
+
+func TestSearchPreciseSymbolsNoUploads(t *testing.T) {
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	svc := newService(NewMockStore(), mockLsifStore, mockUploadSvc, NewMockGitserverClient(), nil, &observation.TestContext)
+
+	symbols, err := svc.SearchPreciseSymbols(context.Background(), 42, search.SymbolsParameters{CommitID: "deadbeef", Query: "Widget"})
+	if err != nil {
+		t.Fatalf("unexpected error searching symbols: %s", err)
+	}
+	if len(symbols) != 0 {
+		t.Errorf("unexpected symbols: %v", symbols)
+	}
+	if calls := len(mockLsifStore.SearchSymbolsFunc.History()); calls != 0 {
+		t.Errorf("unexpected number of SearchSymbols calls. want=%d have=%d", 0, calls)
+	}
+}
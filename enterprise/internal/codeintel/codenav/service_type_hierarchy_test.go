@@ -0,0 +1,212 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	codeintelgitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const (
+	testAnimalSymbol      = "scip-java maven com.example a 1.0.0 com/example/Animal#"
+	testAnimalSoundSymbol = "scip-java maven com.example a 1.0.0 com/example/Animal#sound()."
+	testDogSymbol         = "scip-java maven com.example a 1.0.0 com/example/Dog#"
+	testDogSoundSymbol    = "scip-java maven com.example a 1.0.0 com/example/Dog#sound()."
+	testPuppySymbol       = "scip-java maven com.example a 1.0.0 com/example/Puppy#"
+	testPetSymbol         = "scip-java maven com.example a 1.0.0 com/example/Main#pet."
+)
+
+var (
+	testTypeDefinitionRanges = map[string]types.Range{
+		testAnimalSymbol:      {Start: types.Position{Line: 1, Character: 10}, End: types.Position{Line: 1, Character: 16}},
+		testAnimalSoundSymbol: {Start: types.Position{Line: 2, Character: 9}, End: types.Position{Line: 2, Character: 14}},
+		testDogSymbol:         {Start: types.Position{Line: 5, Character: 6}, End: types.Position{Line: 5, Character: 9}},
+		testDogSoundSymbol:    {Start: types.Position{Line: 6, Character: 9}, End: types.Position{Line: 6, Character: 14}},
+		testPuppySymbol:       {Start: types.Position{Line: 9, Character: 6}, End: types.Position{Line: 9, Character: 11}},
+		testPetSymbol:         {Start: types.Position{Line: 12, Character: 4}, End: types.Position{Line: 12, Character: 7}},
+	}
+
+	// interface Animal { void sound(); }
+	// class Dog implements Animal { void sound() {} }
+	// class Puppy extends Dog {}
+	// Puppy pet;
+	testTypeHierarchyDocument = &scip.Document{
+		RelativePath: "Animal.java",
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{1, 10, 16}, Symbol: testAnimalSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{2, 9, 14}, Symbol: testAnimalSoundSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{5, 6, 9}, Symbol: testDogSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{6, 9, 14}, Symbol: testDogSoundSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{9, 6, 11}, Symbol: testPuppySymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{12, 4, 7}, Symbol: testPetSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+		Symbols: []*scip.SymbolInformation{
+			{Symbol: testDogSymbol, Relationships: []*scip.Relationship{{Symbol: testAnimalSymbol, IsImplementation: true}}},
+			{Symbol: testDogSoundSymbol, Relationships: []*scip.Relationship{{Symbol: testAnimalSoundSymbol, IsImplementation: true, IsReference: true}}},
+			{Symbol: testPuppySymbol, Relationships: []*scip.Relationship{{Symbol: testDogSymbol, IsImplementation: true}}},
+			{Symbol: testPetSymbol, Relationships: []*scip.Relationship{{Symbol: testPuppySymbol, IsTypeDefinition: true}}},
+		},
+	}
+)
+
+// testTypeHierarchySymbolUsages answers symbol usage queries over testTypeHierarchyDocument within upload 50.
+func testTypeHierarchySymbolUsages(_ context.Context, kind lsifstore.SymbolUsageKind, _ []int, symbolNames []string) ([]shared.SymbolLocation, error) {
+	implementations := map[string]string{
+		testAnimalSymbol:      testDogSymbol,
+		testAnimalSoundSymbol: testDogSoundSymbol,
+		testDogSymbol:         testPuppySymbol,
+	}
+
+	var locations []shared.SymbolLocation
+	for _, symbolName := range symbolNames {
+		definitionSymbol := symbolName
+		if kind == lsifstore.SymbolUsageImplementations {
+			if definitionSymbol = implementations[symbolName]; definitionSymbol == "" {
+				continue
+			}
+		}
+
+		locations = append(locations, shared.SymbolLocation{
+			Symbol:   symbolName,
+			Location: shared.Location{DumpID: 50, Path: "Animal.java", Range: testTypeDefinitionRanges[definitionSymbol]},
+		})
+	}
+
+	return locations, nil
+}
+
+func TestSupertypes(t *testing.T) {
+	svc, mockLsifStore, mockRequestState, uploads := setupTypeHierarchyTest()
+	mockLsifStore.GetSymbolUsagesFunc.SetDefaultHook(testTypeHierarchySymbolUsages)
+
+	// Request from a variable of type Puppy
+	items, err := svc.GetSupertypes(context.Background(), typeHierarchyRequest(testPetSymbol), mockRequestState, 5)
+	if err != nil {
+		t.Fatalf("unexpected error querying supertypes: %s", err)
+	}
+
+	expectedItems := []shared.TypeHierarchyItem{
+		{
+			Symbol:      testDogSymbol,
+			Name:        "Dog",
+			Parent:      testPuppySymbol,
+			Depth:       1,
+			Definitions: []types.UploadLocation{{Dump: uploads[0], Path: "sub1/Animal.java", TargetCommit: "deadbeef", TargetRange: testTypeDefinitionRanges[testDogSymbol]}},
+		},
+		{
+			Symbol:      testAnimalSymbol,
+			Name:        "Animal",
+			Parent:      testDogSymbol,
+			Depth:       2,
+			Definitions: []types.UploadLocation{{Dump: uploads[0], Path: "sub1/Animal.java", TargetCommit: "deadbeef", TargetRange: testTypeDefinitionRanges[testAnimalSymbol]}},
+		},
+	}
+	if diff := cmp.Diff(expectedItems, items); diff != "" {
+		t.Errorf("unexpected supertypes (-want +got):\n%s", diff)
+	}
+
+	// Depth limit
+	items, err = svc.GetSupertypes(context.Background(), typeHierarchyRequest(testPuppySymbol), mockRequestState, 1)
+	if err != nil {
+		t.Fatalf("unexpected error querying supertypes: %s", err)
+	}
+	if diff := cmp.Diff(expectedItems[:1], items); diff != "" {
+		t.Errorf("unexpected supertypes (-want +got):\n%s", diff)
+	}
+}
+
+func TestSubtypes(t *testing.T) {
+	svc, mockLsifStore, mockRequestState, uploads := setupTypeHierarchyTest()
+	mockLsifStore.GetSymbolUsagesFunc.SetDefaultHook(testTypeHierarchySymbolUsages)
+
+	items, err := svc.GetSubtypes(context.Background(), typeHierarchyRequest(testAnimalSymbol), mockRequestState, 5)
+	if err != nil {
+		t.Fatalf("unexpected error querying subtypes: %s", err)
+	}
+
+	expectedItems := []shared.TypeHierarchyItem{
+		{
+			Symbol:      testDogSymbol,
+			Name:        "Dog",
+			Parent:      testAnimalSymbol,
+			Depth:       1,
+			Definitions: []types.UploadLocation{{Dump: uploads[0], Path: "sub1/Animal.java", TargetCommit: "deadbeef", TargetRange: testTypeDefinitionRanges[testDogSymbol]}},
+		},
+		{
+			Symbol:      testPuppySymbol,
+			Name:        "Puppy",
+			Parent:      testDogSymbol,
+			Depth:       2,
+			Definitions: []types.UploadLocation{{Dump: uploads[0], Path: "sub1/Animal.java", TargetCommit: "deadbeef", TargetRange: testTypeDefinitionRanges[testPuppySymbol]}},
+		},
+	}
+	if diff := cmp.Diff(expectedItems, items); diff != "" {
+		t.Errorf("unexpected subtypes (-want +got):\n%s", diff)
+	}
+}
+
+func TestPrototypes(t *testing.T) {
+	svc, mockLsifStore, mockRequestState, uploads := setupTypeHierarchyTest()
+	mockLsifStore.GetSymbolUsagesFunc.SetDefaultHook(testTypeHierarchySymbolUsages)
+
+	locations, err := svc.GetPrototypes(context.Background(), typeHierarchyRequest(testDogSoundSymbol), mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying prototypes: %s", err)
+	}
+
+	expectedLocations := []types.UploadLocation{
+		{Dump: uploads[0], Path: "sub1/Animal.java", TargetCommit: "deadbeef", TargetRange: testTypeDefinitionRanges[testAnimalSoundSymbol]},
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("unexpected prototypes (-want +got):\n%s", diff)
+	}
+}
+
+func setupTypeHierarchyTest() (*Service, *MockLsifStore, RequestState, []types.Dump) {
+	// Set up mocks
+	mockStore := NewMockStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitServer := codeintelgitserver.New(database.NewMockDB(), &observation.TestContext)
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(mockStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, &observation.TestContext)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitServer, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []types.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	mockLsifStore.GetSCIPDocumentFunc.SetDefaultReturn(testTypeHierarchyDocument, true, nil)
+
+	return svc, mockLsifStore, mockRequestState, uploads
+}
+
+// typeHierarchyRequest returns request arguments positioned within the definition of the given symbol.
+func typeHierarchyRequest(symbol string) shared.RequestArgs {
+	r := testTypeDefinitionRanges[symbol]
+
+	return shared.RequestArgs{
+		RepositoryID: 42,
+		Commit:       mockCommit,
+		Path:         mockPath,
+		Line:         r.Start.Line,
+		Character:    r.Start.Character + 1,
+		Limit:        50,
+	}
+}
@@ -12,6 +12,84 @@ type Location struct {
 	Range  types.Range
 }
 
+// SymbolLocation is the location of a use of a SCIP symbol scoped to a dump.
+type SymbolLocation struct {
+	Symbol string
+	Location
+}
+
+// CallHierarchyCall is a function or method that calls, or is called by, the function or method at
+// the requested position. Definitions are the locations at which the caller (for incoming calls) or
+// callee (for outgoing calls) is defined, and CallSites are the locations of the calls themselves.
+type CallHierarchyCall struct {
+	Symbol      string
+	Name        string
+	Definitions []types.UploadLocation
+	CallSites   []types.UploadLocation
+}
+
+// TypeHierarchyItem is a type or method reached from the requested position by following implementation
+// relationships. Parent is the symbol through which the item was reached, which is the symbol at the requested
+// position for items of depth one, and Depth is the number of relationships followed to reach it.
+type TypeHierarchyItem struct {
+	Symbol      string
+	Name        string
+	Parent      string
+	Depth       int
+	Definitions []types.UploadLocation
+}
+
+// DocumentSymbolProvenance describes the source of the data for a document symbol.
+type DocumentSymbolProvenance string
+
+const (
+	// DocumentSymbolProvenancePrecise marks symbols read from a SCIP upload.
+	DocumentSymbolProvenancePrecise DocumentSymbolProvenance = "PRECISE"
+
+	// DocumentSymbolProvenanceSearchBased marks symbols read from the symbols service (ctags).
+	DocumentSymbolProvenanceSearchBased DocumentSymbolProvenance = "SEARCH_BASED"
+)
+
+// DocumentSymbol is a node in the symbol outline of a file. Kind is the uppercase name of an LSP
+// symbol kind (e.g. CLASS or METHOD), and Symbol is the SCIP symbol of precise nodes. Ranges are
+// relative to the requested commit.
+type DocumentSymbol struct {
+	Name       string
+	Kind       string
+	Symbol     string
+	Range      types.Range
+	Provenance DocumentSymbolProvenance
+	Children   []DocumentSymbol
+}
+
+// SymbolSearchArgs describes a search over the symbol index of a set of uploads. The query
+// is matched against symbol display names, and the include and exclude patterns are regular
+// expressions matched against paths relative to the repository root.
+type SymbolSearchArgs struct {
+	Query           string
+	IsRegExp        bool
+	IsCaseSensitive bool
+	IncludePatterns []string
+	ExcludePattern  string
+	Limit           int
+}
+
+// IndexedSymbol is a symbol defined by an upload as recorded in the symbol index. The path
+// is relative to the repository root, and the range is relative to the indexed commit.
+type IndexedSymbol struct {
+	UploadID       int
+	Scheme         string
+	Symbol         string
+	Name           string
+	Kind           string
+	PackageManager string
+	PackageName    string
+	PackageVersion string
+	Path           string
+	Range          types.Range
+	Documentation  string
+}
+
 type RequestArgs struct {
 	RepositoryID int
 	Commit       string
@@ -0,0 +1,131 @@
+package codenav
+
+import (
+	"context"
+
+	traceLog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultPreciseSymbolSearchLimit is the maximum number of precise symbols returned for a single
+// repository when the search parameters do not specify a limit.
+const DefaultPreciseSymbolSearchLimit = 100
+
+// SearchPreciseSymbols returns the symbols defined by the uploads visible from the requested commit
+// of the given repository whose names match the given symbol search parameters. This backs symbol
+// searches that specify `precise:yes` or `precise:only`. Symbol positions are translated from the
+// commit of the upload that defines them into the requested commit. Symbols whose definition has been
+// edited between the two commits are dropped.
+func (s *Service) SearchPreciseSymbols(ctx context.Context, repositoryID int, args search.SymbolsParameters) (_ []result.Symbol, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.searchPreciseSymbols, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", repositoryID),
+			traceLog.String("commit", string(args.CommitID)),
+			traceLog.String("query", args.Query),
+		},
+	})
+	defer endObservation()
+
+	dumps, err := s.uploadSvc.InferClosestUploads(ctx, repositoryID, string(args.CommitID), "", false, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "uploadSvc.InferClosestUploads")
+	}
+	if len(dumps) == 0 {
+		return nil, nil
+	}
+
+	trace.Log(traceLog.String("uploads", uploadIDsToString(dumps)))
+
+	uploadIDs := make([]int, 0, len(dumps))
+	dumpsByID := make(map[int]types.Dump, len(dumps))
+	for _, dump := range dumps {
+		uploadIDs = append(uploadIDs, dump.ID)
+		dumpsByID[dump.ID] = dump
+	}
+
+	limit := args.First
+	if limit <= 0 {
+		limit = DefaultPreciseSymbolSearchLimit
+	}
+
+	indexedSymbols, err := s.lsifstore.SearchSymbols(ctx, uploadIDs, shared.SymbolSearchArgs{
+		Query:           args.Query,
+		IsRegExp:        args.IsRegExp,
+		IsCaseSensitive: args.IsCaseSensitive,
+		IncludePatterns: args.IncludePatterns,
+		ExcludePattern:  args.ExcludePattern,
+		Limit:           limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifstore.SearchSymbols")
+	}
+	trace.Log(traceLog.Int("numSymbols", len(indexedSymbols)))
+
+	gitTreeTranslator := NewGitTreeTranslator(s.gitserver, &requestArgs{
+		repo:   &sgtypes.Repo{ID: api.RepoID(repositoryID), Name: args.Repo},
+		commit: string(args.CommitID),
+	}, mapHunkCache{})
+
+	symbols := make([]result.Symbol, 0, len(indexedSymbols))
+	for _, symbol := range indexedSymbols {
+		dump, ok := dumpsByID[symbol.UploadID]
+		if !ok {
+			continue
+		}
+
+		_, targetRange, ok, err := gitTreeTranslator.GetTargetCommitRangeFromSourceRange(ctx, dump.Commit, symbol.Path, symbol.Range, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "gitTreeTranslator.GetTargetCommitRangeFromSourceRange")
+		}
+		if !ok {
+			continue
+		}
+
+		symbol.Range = targetRange
+		symbols = append(symbols, indexedSymbolToResult(symbol))
+	}
+	trace.Log(traceLog.Int("numTranslatedSymbols", len(symbols)))
+
+	return symbols, nil
+}
+
+// mapHunkCache is an unbounded HunkCache used for the lifetime of a single request, so that
+// symbols defined in the same file are translated with a single diff.
+type mapHunkCache map[any]any
+
+func (c mapHunkCache) Get(key any) (any, bool) {
+	value, ok := c[key]
+	return value, ok
+}
+
+func (c mapHunkCache) Set(key, value any, _ int64) bool {
+	c[key] = value
+	return true
+}
+
+// indexedSymbolToResult converts a symbol read from the symbol index into a symbol search result.
+func indexedSymbolToResult(symbol shared.IndexedSymbol) result.Symbol {
+	pkg := symbol.PackageName
+	if pkg != "" && symbol.PackageVersion != "" {
+		pkg += "@" + symbol.PackageVersion
+	}
+
+	return result.Symbol{
+		Name:               symbol.Name,
+		Path:               symbol.Path,
+		Line:               symbol.Range.Start.Line + 1,
+		Character:          symbol.Range.Start.Character,
+		Kind:               symbol.Kind,
+		FullyQualifiedName: symbol.Symbol,
+		Package:            pkg,
+		Documentation:      symbol.Documentation,
+	}
+}
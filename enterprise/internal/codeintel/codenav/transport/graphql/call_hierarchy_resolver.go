@@ -0,0 +1,30 @@
+package graphql
+
+import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+)
+
+type callHierarchyCallResolver struct {
+	call             shared.CallHierarchyCall
+	locationResolver *sharedresolvers.CachedLocationResolver
+}
+
+func NewCallHierarchyCallResolver(call shared.CallHierarchyCall, locationResolver *sharedresolvers.CachedLocationResolver) resolverstubs.CallHierarchyCallResolver {
+	return &callHierarchyCallResolver{
+		call:             call,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *callHierarchyCallResolver) Symbol() string { return r.call.Symbol }
+func (r *callHierarchyCallResolver) Name() string   { return r.call.Name }
+
+func (r *callHierarchyCallResolver) Definitions() resolverstubs.LocationConnectionResolver {
+	return NewLocationConnectionResolver(r.call.Definitions, nil, r.locationResolver)
+}
+
+func (r *callHierarchyCallResolver) CallSites() resolverstubs.LocationConnectionResolver {
+	return NewLocationConnectionResolver(r.call.CallSites, nil, r.locationResolver)
+}
@@ -0,0 +1,39 @@
+package graphql
+
+import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+)
+
+type documentSymbolResolver struct {
+	documentSymbol shared.DocumentSymbol
+}
+
+func NewDocumentSymbolResolvers(documentSymbols []shared.DocumentSymbol) []resolverstubs.DocumentSymbolResolver {
+	resolvers := make([]resolverstubs.DocumentSymbolResolver, 0, len(documentSymbols))
+	for _, documentSymbol := range documentSymbols {
+		resolvers = append(resolvers, &documentSymbolResolver{documentSymbol: documentSymbol})
+	}
+
+	return resolvers
+}
+
+func (r *documentSymbolResolver) Name() string       { return r.documentSymbol.Name }
+func (r *documentSymbolResolver) Kind() string       { return r.documentSymbol.Kind }
+func (r *documentSymbolResolver) Provenance() string { return string(r.documentSymbol.Provenance) }
+
+func (r *documentSymbolResolver) Symbol() *string {
+	if r.documentSymbol.Symbol == "" {
+		return nil
+	}
+
+	return &r.documentSymbol.Symbol
+}
+
+func (r *documentSymbolResolver) Range() resolverstubs.RangeResolver {
+	return NewRangeResolver(convertRange(r.documentSymbol.Range))
+}
+
+func (r *documentSymbolResolver) Children() []resolverstubs.DocumentSymbolResolver {
+	return NewDocumentSymbolResolvers(r.documentSymbol.Children)
+}
@@ -268,6 +268,167 @@ func (r *gitBlobLSIFDataResolver) Hover(ctx context.Context, args *resolverstubs
 	return NewHoverResolver(text, sharedRangeTolspRange(rx)), nil
 }
 
+// DefaultCallHierarchyPageSize is the number of calls returned when no limit is supplied.
+const DefaultCallHierarchyPageSize = 100
+
+// IncomingCalls returns the functions and methods that call the function or method at the given position.
+func (r *gitBlobLSIFDataResolver) IncomingCalls(ctx context.Context, args *resolverstubs.LSIFCallHierarchyArgs) (_ []resolverstubs.CallHierarchyCallResolver, err error) {
+	limit := derefInt32(args.First, DefaultCallHierarchyPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character), Limit: limit}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.incomingCalls, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	calls, err := r.codeNavSvc.GetIncomingCalls(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetIncomingCalls")
+	}
+
+	return r.resolveCalls(calls), nil
+}
+
+// OutgoingCalls returns the functions and methods called by the function or method at the given position.
+func (r *gitBlobLSIFDataResolver) OutgoingCalls(ctx context.Context, args *resolverstubs.LSIFCallHierarchyArgs) (_ []resolverstubs.CallHierarchyCallResolver, err error) {
+	limit := derefInt32(args.First, DefaultCallHierarchyPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character), Limit: limit}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.outgoingCalls, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	calls, err := r.codeNavSvc.GetOutgoingCalls(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetOutgoingCalls")
+	}
+
+	return r.resolveCalls(calls), nil
+}
+
+func (r *gitBlobLSIFDataResolver) resolveCalls(calls []shared.CallHierarchyCall) []resolverstubs.CallHierarchyCallResolver {
+	resolvers := make([]resolverstubs.CallHierarchyCallResolver, 0, len(calls))
+	for _, call := range calls {
+		resolvers = append(resolvers, NewCallHierarchyCallResolver(call, r.locationResolver))
+	}
+
+	return resolvers
+}
+
+// DefaultTypeHierarchyDepth is the number of implementation relationships followed when no depth is supplied.
+const DefaultTypeHierarchyDepth = 1
+
+// DefaultTypeHierarchyPageSize is the number of types returned when no limit is supplied.
+const DefaultTypeHierarchyPageSize = 100
+
+// Supertypes returns the types and methods implemented by the type or method at the given position.
+func (r *gitBlobLSIFDataResolver) Supertypes(ctx context.Context, args *resolverstubs.LSIFTypeHierarchyArgs) (_ []resolverstubs.TypeHierarchyItemResolver, err error) {
+	requestArgs, depth, err := r.typeHierarchyRequestArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.supertypes, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	items, err := r.codeNavSvc.GetSupertypes(ctx, requestArgs, r.requestState, depth)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetSupertypes")
+	}
+
+	return r.resolveTypeHierarchyItems(items), nil
+}
+
+// Subtypes returns the types and methods that implement the type or method at the given position.
+func (r *gitBlobLSIFDataResolver) Subtypes(ctx context.Context, args *resolverstubs.LSIFTypeHierarchyArgs) (_ []resolverstubs.TypeHierarchyItemResolver, err error) {
+	requestArgs, depth, err := r.typeHierarchyRequestArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.subtypes, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	items, err := r.codeNavSvc.GetSubtypes(ctx, requestArgs, r.requestState, depth)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetSubtypes")
+	}
+
+	return r.resolveTypeHierarchyItems(items), nil
+}
+
+// Prototypes returns the list of source locations that define the interface methods satisfied by the method
+// at the given position.
+func (r *gitBlobLSIFDataResolver) Prototypes(ctx context.Context, args *resolverstubs.LSIFQueryPositionArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character)}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.prototypes, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	prototypes, err := r.codeNavSvc.GetPrototypes(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetPrototypes")
+	}
+
+	if args.Filter != nil && *args.Filter != "" {
+		filtered := prototypes[:0]
+		for _, loc := range prototypes {
+			if strings.Contains(loc.Path, *args.Filter) {
+				filtered = append(filtered, loc)
+			}
+		}
+		prototypes = filtered
+	}
+
+	return NewLocationConnectionResolver(prototypes, nil, r.locationResolver), nil
+}
+
+func (r *gitBlobLSIFDataResolver) typeHierarchyRequestArgs(args *resolverstubs.LSIFTypeHierarchyArgs) (shared.RequestArgs, int, error) {
+	limit := derefInt32(args.First, DefaultTypeHierarchyPageSize)
+	if limit <= 0 {
+		return shared.RequestArgs{}, 0, ErrIllegalLimit
+	}
+
+	depth := derefInt32(args.Depth, DefaultTypeHierarchyDepth)
+	if depth <= 0 || depth > codenav.MaximumTypeHierarchyDepth {
+		return shared.RequestArgs{}, 0, errors.Newf("illegal depth: must be between 1 and %d", codenav.MaximumTypeHierarchyDepth)
+	}
+
+	return shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Line: int(args.Line), Character: int(args.Character), Limit: limit}, depth, nil
+}
+
+func (r *gitBlobLSIFDataResolver) resolveTypeHierarchyItems(items []shared.TypeHierarchyItem) []resolverstubs.TypeHierarchyItemResolver {
+	resolvers := make([]resolverstubs.TypeHierarchyItemResolver, 0, len(items))
+	for _, item := range items {
+		resolvers = append(resolvers, NewTypeHierarchyItemResolver(item, r.locationResolver))
+	}
+
+	return resolvers
+}
+
+// DefaultDocumentSymbolsPageSize is the number of search-based symbols returned when no limit is supplied.
+const DefaultDocumentSymbolsPageSize = 1000
+
+// DocumentSymbols returns the symbol outline of the file. The outline is read from a SCIP index covering
+// the file when one exists, and from search-based symbols otherwise.
+func (r *gitBlobLSIFDataResolver) DocumentSymbols(ctx context.Context, args *resolverstubs.LSIFDocumentSymbolsArgs) (_ []resolverstubs.DocumentSymbolResolver, err error) {
+	limit := derefInt32(args.First, DefaultDocumentSymbolsPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	requestArgs := shared.RequestArgs{RepositoryID: r.requestState.RepositoryID, Commit: r.requestState.Commit, Path: r.requestState.Path, Limit: limit}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.documentSymbols, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	documentSymbols, err := r.codeNavSvc.GetDocumentSymbols(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetDocumentSymbols")
+	}
+
+	return NewDocumentSymbolResolvers(documentSymbols), nil
+}
+
 // LSIFUploads returns the list of dbstore.Uploads for the store.Dumps determined to be applicable
 // for answering code-intel queries.
 func (r *gitBlobLSIFDataResolver) LSIFUploads(ctx context.Context) (_ []resolverstubs.LSIFUploadResolver, err error) {
@@ -26,6 +26,12 @@ type CodeNavService interface {
 	GetDiagnostics(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (diagnosticsAtUploads []shared.DiagnosticAtUpload, _ int, err error)
 	GetRanges(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, startLine, endLine int) (adjustedRanges []shared.AdjustedCodeIntelligenceRange, err error)
 	GetStencil(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (adjustedRanges []types.Range, err error)
+	GetIncomingCalls(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []shared.CallHierarchyCall, err error)
+	GetOutgoingCalls(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []shared.CallHierarchyCall, err error)
+	GetSupertypes(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, depth int) (_ []shared.TypeHierarchyItem, err error)
+	GetSubtypes(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState, depth int) (_ []shared.TypeHierarchyItem, err error)
+	GetPrototypes(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []types.UploadLocation, err error)
+	GetDocumentSymbols(ctx context.Context, args shared.RequestArgs, requestState codenav.RequestState) (_ []shared.DocumentSymbol, err error)
 
 	// Uploads Service
 	GetDumpsByIDs(ctx context.Context, ids []int) (_ []types.Dump, err error)
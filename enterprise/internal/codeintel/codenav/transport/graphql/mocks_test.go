@@ -842,6 +842,9 @@ type MockCodeNavService struct {
 	// GetDiagnosticsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDiagnostics.
 	GetDiagnosticsFunc *CodeNavServiceGetDiagnosticsFunc
+	// GetDocumentSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDocumentSymbols.
+	GetDocumentSymbolsFunc *CodeNavServiceGetDocumentSymbolsFunc
 	// GetDumpsByIDsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDumpsByIDs.
 	GetDumpsByIDsFunc *CodeNavServiceGetDumpsByIDsFunc
@@ -851,6 +854,15 @@ type MockCodeNavService struct {
 	// GetImplementationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetImplementations.
 	GetImplementationsFunc *CodeNavServiceGetImplementationsFunc
+	// GetIncomingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIncomingCalls.
+	GetIncomingCallsFunc *CodeNavServiceGetIncomingCallsFunc
+	// GetOutgoingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetOutgoingCalls.
+	GetOutgoingCallsFunc *CodeNavServiceGetOutgoingCallsFunc
+	// GetPrototypesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetPrototypes.
+	GetPrototypesFunc *CodeNavServiceGetPrototypesFunc
 	// GetRangesFunc is an instance of a mock function object controlling
 	// the behavior of the method GetRanges.
 	GetRangesFunc *CodeNavServiceGetRangesFunc
@@ -860,6 +872,12 @@ type MockCodeNavService struct {
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *CodeNavServiceGetStencilFunc
+	// GetSubtypesFunc is an instance of a mock function object controlling
+	// the behavior of the method GetSubtypes.
+	GetSubtypesFunc *CodeNavServiceGetSubtypesFunc
+	// GetSupertypesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetSupertypes.
+	GetSupertypesFunc *CodeNavServiceGetSupertypesFunc
 	// GetUnsafeDBFunc is an instance of a mock function object controlling
 	// the behavior of the method GetUnsafeDB.
 	GetUnsafeDBFunc *CodeNavServiceGetUnsafeDBFunc
@@ -884,6 +902,11 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetDocumentSymbolsFunc: &CodeNavServiceGetDocumentSymbolsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []shared1.DocumentSymbol, r1 error) {
+				return
+			},
+		},
 		GetDumpsByIDsFunc: &CodeNavServiceGetDumpsByIDsFunc{
 			defaultHook: func(context.Context, []int) (r0 []types.Dump, r1 error) {
 				return
@@ -899,6 +922,21 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []shared1.CallHierarchyCall, r1 error) {
+				return
+			},
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []shared1.CallHierarchyCall, r1 error) {
+				return
+			},
+		},
+		GetPrototypesFunc: &CodeNavServiceGetPrototypesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) (r0 []types.UploadLocation, r1 error) {
+				return
+			},
+		},
 		GetRangesFunc: &CodeNavServiceGetRangesFunc{
 			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) (r0 []shared1.AdjustedCodeIntelligenceRange, r1 error) {
 				return
@@ -914,6 +952,16 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetSubtypesFunc: &CodeNavServiceGetSubtypesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int) (r0 []shared1.TypeHierarchyItem, r1 error) {
+				return
+			},
+		},
+		GetSupertypesFunc: &CodeNavServiceGetSupertypesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int) (r0 []shared1.TypeHierarchyItem, r1 error) {
+				return
+			},
+		},
 		GetUnsafeDBFunc: &CodeNavServiceGetUnsafeDBFunc{
 			defaultHook: func() (r0 database.DB) {
 				return
@@ -941,6 +989,11 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetDiagnostics")
 			},
 		},
+		GetDocumentSymbolsFunc: &CodeNavServiceGetDocumentSymbolsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error) {
+				panic("unexpected invocation of MockCodeNavService.GetDocumentSymbols")
+			},
+		},
 		GetDumpsByIDsFunc: &CodeNavServiceGetDumpsByIDsFunc{
 			defaultHook: func(context.Context, []int) ([]types.Dump, error) {
 				panic("unexpected invocation of MockCodeNavService.GetDumpsByIDs")
@@ -956,6 +1009,21 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetImplementations")
 			},
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
+				panic("unexpected invocation of MockCodeNavService.GetIncomingCalls")
+			},
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
+				panic("unexpected invocation of MockCodeNavService.GetOutgoingCalls")
+			},
+		},
+		GetPrototypesFunc: &CodeNavServiceGetPrototypesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+				panic("unexpected invocation of MockCodeNavService.GetPrototypes")
+			},
+		},
 		GetRangesFunc: &CodeNavServiceGetRangesFunc{
 			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
 				panic("unexpected invocation of MockCodeNavService.GetRanges")
@@ -971,6 +1039,16 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetStencil")
 			},
 		},
+		GetSubtypesFunc: &CodeNavServiceGetSubtypesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+				panic("unexpected invocation of MockCodeNavService.GetSubtypes")
+			},
+		},
+		GetSupertypesFunc: &CodeNavServiceGetSupertypesFunc{
+			defaultHook: func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+				panic("unexpected invocation of MockCodeNavService.GetSupertypes")
+			},
+		},
 		GetUnsafeDBFunc: &CodeNavServiceGetUnsafeDBFunc{
 			defaultHook: func() database.DB {
 				panic("unexpected invocation of MockCodeNavService.GetUnsafeDB")
@@ -993,6 +1071,9 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetDiagnosticsFunc: &CodeNavServiceGetDiagnosticsFunc{
 			defaultHook: i.GetDiagnostics,
 		},
+		GetDocumentSymbolsFunc: &CodeNavServiceGetDocumentSymbolsFunc{
+			defaultHook: i.GetDocumentSymbols,
+		},
 		GetDumpsByIDsFunc: &CodeNavServiceGetDumpsByIDsFunc{
 			defaultHook: i.GetDumpsByIDs,
 		},
@@ -1002,6 +1083,15 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetImplementationsFunc: &CodeNavServiceGetImplementationsFunc{
 			defaultHook: i.GetImplementations,
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: i.GetIncomingCalls,
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: i.GetOutgoingCalls,
+		},
+		GetPrototypesFunc: &CodeNavServiceGetPrototypesFunc{
+			defaultHook: i.GetPrototypes,
+		},
 		GetRangesFunc: &CodeNavServiceGetRangesFunc{
 			defaultHook: i.GetRanges,
 		},
@@ -1011,6 +1101,12 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetStencilFunc: &CodeNavServiceGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetSubtypesFunc: &CodeNavServiceGetSubtypesFunc{
+			defaultHook: i.GetSubtypes,
+		},
+		GetSupertypesFunc: &CodeNavServiceGetSupertypesFunc{
+			defaultHook: i.GetSupertypes,
+		},
 		GetUnsafeDBFunc: &CodeNavServiceGetUnsafeDBFunc{
 			defaultHook: i.GetUnsafeDB,
 		},
@@ -1368,6 +1464,120 @@ func (c CodeNavServiceGetDiagnosticsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// CodeNavServiceGetDocumentSymbolsFunc describes the behavior when the
+// GetDocumentSymbols method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetDocumentSymbolsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error)
+	history     []CodeNavServiceGetDocumentSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDocumentSymbols delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetDocumentSymbols(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]shared1.DocumentSymbol, error) {
+	r0, r1 := m.GetDocumentSymbolsFunc.nextHook()(v0, v1, v2)
+	m.GetDocumentSymbolsFunc.appendCall(CodeNavServiceGetDocumentSymbolsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDocumentSymbols
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDocumentSymbols method of the parent MockCodeNavService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) SetDefaultReturn(r0 []shared1.DocumentSymbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) PushReturn(r0 []shared1.DocumentSymbol, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetDocumentSymbolsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.DocumentSymbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetDocumentSymbolsFunc) appendCall(r0 CodeNavServiceGetDocumentSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetDocumentSymbolsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetDocumentSymbolsFunc) History() []CodeNavServiceGetDocumentSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetDocumentSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetDocumentSymbolsFuncCall is an object that describes an
+// invocation of method GetDocumentSymbols on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetDocumentSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.DocumentSymbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetDocumentSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetDocumentSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeNavServiceGetDumpsByIDsFunc describes the behavior when the
 // GetDumpsByIDs method of the parent MockCodeNavService instance is
 // invoked.
@@ -1714,35 +1924,36 @@ func (c CodeNavServiceGetImplementationsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// CodeNavServiceGetRangesFunc describes the behavior when the GetRanges
-// method of the parent MockCodeNavService instance is invoked.
-type CodeNavServiceGetRangesFunc struct {
-	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)
-	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)
-	history     []CodeNavServiceGetRangesFuncCall
+// CodeNavServiceGetIncomingCallsFunc describes the behavior when the
+// GetIncomingCalls method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetIncomingCallsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)
+	history     []CodeNavServiceGetIncomingCallsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetRanges delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockCodeNavService) GetRanges(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 int, v4 int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
-	r0, r1 := m.GetRangesFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.GetRangesFunc.appendCall(CodeNavServiceGetRangesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+// GetIncomingCalls delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetIncomingCalls(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
+	r0, r1 := m.GetIncomingCallsFunc.nextHook()(v0, v1, v2)
+	m.GetIncomingCallsFunc.appendCall(CodeNavServiceGetIncomingCallsFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetRanges method of
-// the parent MockCodeNavService instance is invoked and the hook queue is
-// empty.
-func (f *CodeNavServiceGetRangesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)) {
+// SetDefaultHook sets function that is called when the GetIncomingCalls
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetIncomingCallsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetRanges method of the parent MockCodeNavService instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetRangesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)) {
+// GetIncomingCalls method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetIncomingCallsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1750,20 +1961,20 @@ func (f *CodeNavServiceGetRangesFunc) PushHook(hook func(context.Context, shared
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetRangesFunc) SetDefaultReturn(r0 []shared1.AdjustedCodeIntelligenceRange, r1 error) {
-	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+func (f *CodeNavServiceGetIncomingCallsFunc) SetDefaultReturn(r0 []shared1.CallHierarchyCall, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetRangesFunc) PushReturn(r0 []shared1.AdjustedCodeIntelligenceRange, r1 error) {
-	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+func (f *CodeNavServiceGetIncomingCallsFunc) PushReturn(r0 []shared1.CallHierarchyCall, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
 		return r0, r1
 	})
 }
 
-func (f *CodeNavServiceGetRangesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+func (f *CodeNavServiceGetIncomingCallsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1776,26 +1987,27 @@ func (f *CodeNavServiceGetRangesFunc) nextHook() func(context.Context, shared1.R
 	return hook
 }
 
-func (f *CodeNavServiceGetRangesFunc) appendCall(r0 CodeNavServiceGetRangesFuncCall) {
+func (f *CodeNavServiceGetIncomingCallsFunc) appendCall(r0 CodeNavServiceGetIncomingCallsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetRangesFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetRangesFunc) History() []CodeNavServiceGetRangesFuncCall {
+// History returns a sequence of CodeNavServiceGetIncomingCallsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetIncomingCallsFunc) History() []CodeNavServiceGetIncomingCallsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetRangesFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetIncomingCallsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetRangesFuncCall is an object that describes an invocation
-// of method GetRanges on an instance of MockCodeNavService.
-type CodeNavServiceGetRangesFuncCall struct {
+// CodeNavServiceGetIncomingCallsFuncCall is an object that describes an
+// invocation of method GetIncomingCalls on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetIncomingCallsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1805,15 +2017,9 @@ type CodeNavServiceGetRangesFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 codenav.RequestState
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []shared1.AdjustedCodeIntelligenceRange
+	Result0 []shared1.CallHierarchyCall
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -1821,46 +2027,46 @@ type CodeNavServiceGetRangesFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetRangesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c CodeNavServiceGetIncomingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetRangesFuncCall) Results() []interface{} {
+func (c CodeNavServiceGetIncomingCallsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeNavServiceGetReferencesFunc describes the behavior when the
-// GetReferences method of the parent MockCodeNavService instance is
+// CodeNavServiceGetOutgoingCallsFunc describes the behavior when the
+// GetOutgoingCalls method of the parent MockCodeNavService instance is
 // invoked.
-type CodeNavServiceGetReferencesFunc struct {
-	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)
-	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)
-	history     []CodeNavServiceGetReferencesFuncCall
+type CodeNavServiceGetOutgoingCallsFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)
+	history     []CodeNavServiceGetOutgoingCallsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetReferences delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockCodeNavService) GetReferences(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
-	r0, r1, r2 := m.GetReferencesFunc.nextHook()(v0, v1, v2, v3)
-	m.GetReferencesFunc.appendCall(CodeNavServiceGetReferencesFuncCall{v0, v1, v2, v3, r0, r1, r2})
-	return r0, r1, r2
+// GetOutgoingCalls delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetOutgoingCalls(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
+	r0, r1 := m.GetOutgoingCallsFunc.nextHook()(v0, v1, v2)
+	m.GetOutgoingCallsFunc.appendCall(CodeNavServiceGetOutgoingCallsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetReferences method
-// of the parent MockCodeNavService instance is invoked and the hook queue
-// is empty.
-func (f *CodeNavServiceGetReferencesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)) {
+// SetDefaultHook sets function that is called when the GetOutgoingCalls
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetOutgoingCallsFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetReferences method of the parent MockCodeNavService instance invokes
+// GetOutgoingCalls method of the parent MockCodeNavService instance invokes
 // the hook at the front of the queue and discards it. After the queue is
 // empty, the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetReferencesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1868,20 +2074,20 @@ func (f *CodeNavServiceGetReferencesFunc) PushHook(hook func(context.Context, sh
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetReferencesFunc) SetDefaultReturn(r0 []types.UploadLocation, r1 shared1.ReferencesCursor, r2 error) {
-	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
-		return r0, r1, r2
+func (f *CodeNavServiceGetOutgoingCallsFunc) SetDefaultReturn(r0 []shared1.CallHierarchyCall, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetReferencesFunc) PushReturn(r0 []types.UploadLocation, r1 shared1.ReferencesCursor, r2 error) {
-	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
-		return r0, r1, r2
+func (f *CodeNavServiceGetOutgoingCallsFunc) PushReturn(r0 []shared1.CallHierarchyCall, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
+		return r0, r1
 	})
 }
 
-func (f *CodeNavServiceGetReferencesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]shared1.CallHierarchyCall, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1894,26 +2100,27 @@ func (f *CodeNavServiceGetReferencesFunc) nextHook() func(context.Context, share
 	return hook
 }
 
-func (f *CodeNavServiceGetReferencesFunc) appendCall(r0 CodeNavServiceGetReferencesFuncCall) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) appendCall(r0 CodeNavServiceGetOutgoingCallsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetReferencesFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetReferencesFunc) History() []CodeNavServiceGetReferencesFuncCall {
+// History returns a sequence of CodeNavServiceGetOutgoingCallsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetOutgoingCallsFunc) History() []CodeNavServiceGetOutgoingCallsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetReferencesFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetOutgoingCallsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetReferencesFuncCall is an object that describes an
-// invocation of method GetReferences on an instance of MockCodeNavService.
-type CodeNavServiceGetReferencesFuncCall struct {
+// CodeNavServiceGetOutgoingCallsFuncCall is an object that describes an
+// invocation of method GetOutgoingCalls on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetOutgoingCallsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1923,61 +2130,56 @@ type CodeNavServiceGetReferencesFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 codenav.RequestState
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 shared1.ReferencesCursor
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []types.UploadLocation
+	Result0 []shared1.CallHierarchyCall
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 shared1.ReferencesCursor
-	// Result2 is the value of the 3rd result returned from this method
-	// invocation.
-	Result2 error
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetReferencesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c CodeNavServiceGetOutgoingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetReferencesFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c CodeNavServiceGetOutgoingCallsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeNavServiceGetStencilFunc describes the behavior when the GetStencil
-// method of the parent MockCodeNavService instance is invoked.
-type CodeNavServiceGetStencilFunc struct {
-	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)
-	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)
-	history     []CodeNavServiceGetStencilFuncCall
+// CodeNavServiceGetPrototypesFunc describes the behavior when the
+// GetPrototypes method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetPrototypesFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)
+	history     []CodeNavServiceGetPrototypesFuncCall
 	mutex       sync.Mutex
 }
 
-// GetStencil delegates to the next hook function in the queue and stores
+// GetPrototypes delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockCodeNavService) GetStencil(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]types.Range, error) {
-	r0, r1 := m.GetStencilFunc.nextHook()(v0, v1, v2)
-	m.GetStencilFunc.appendCall(CodeNavServiceGetStencilFuncCall{v0, v1, v2, r0, r1})
+func (m *MockCodeNavService) GetPrototypes(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]types.UploadLocation, error) {
+	r0, r1 := m.GetPrototypesFunc.nextHook()(v0, v1, v2)
+	m.GetPrototypesFunc.appendCall(CodeNavServiceGetPrototypesFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetStencil method of
-// the parent MockCodeNavService instance is invoked and the hook queue is
-// empty.
-func (f *CodeNavServiceGetStencilFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)) {
+// SetDefaultHook sets function that is called when the GetPrototypes method
+// of the parent MockCodeNavService instance is invoked and the hook queue
+// is empty.
+func (f *CodeNavServiceGetPrototypesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetStencil method of the parent MockCodeNavService instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetStencilFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)) {
+// GetPrototypes method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetPrototypesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1985,7 +2187,353 @@ func (f *CodeNavServiceGetStencilFunc) PushHook(hook func(context.Context, share
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetStencilFunc) SetDefaultReturn(r0 []types.Range, r1 error) {
+func (f *CodeNavServiceGetPrototypesFunc) SetDefaultReturn(r0 []types.UploadLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetPrototypesFunc) PushReturn(r0 []types.UploadLocation, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetPrototypesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.UploadLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetPrototypesFunc) appendCall(r0 CodeNavServiceGetPrototypesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetPrototypesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetPrototypesFunc) History() []CodeNavServiceGetPrototypesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetPrototypesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetPrototypesFuncCall is an object that describes an
+// invocation of method GetPrototypes on an instance of MockCodeNavService.
+type CodeNavServiceGetPrototypesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.UploadLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetPrototypesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetPrototypesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetRangesFunc describes the behavior when the GetRanges
+// method of the parent MockCodeNavService instance is invoked.
+type CodeNavServiceGetRangesFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)
+	history     []CodeNavServiceGetRangesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRanges delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockCodeNavService) GetRanges(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 int, v4 int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+	r0, r1 := m.GetRangesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetRangesFunc.appendCall(CodeNavServiceGetRangesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetRanges method of
+// the parent MockCodeNavService instance is invoked and the hook queue is
+// empty.
+func (f *CodeNavServiceGetRangesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRanges method of the parent MockCodeNavService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetRangesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetRangesFunc) SetDefaultReturn(r0 []shared1.AdjustedCodeIntelligenceRange, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetRangesFunc) PushReturn(r0 []shared1.AdjustedCodeIntelligenceRange, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetRangesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, int, int) ([]shared1.AdjustedCodeIntelligenceRange, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetRangesFunc) appendCall(r0 CodeNavServiceGetRangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetRangesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetRangesFunc) History() []CodeNavServiceGetRangesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetRangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetRangesFuncCall is an object that describes an invocation
+// of method GetRanges on an instance of MockCodeNavService.
+type CodeNavServiceGetRangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.AdjustedCodeIntelligenceRange
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetRangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetRangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetReferencesFunc describes the behavior when the
+// GetReferences method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetReferencesFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)
+	history     []CodeNavServiceGetReferencesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetReferences delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetReferences(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
+	r0, r1, r2 := m.GetReferencesFunc.nextHook()(v0, v1, v2, v3)
+	m.GetReferencesFunc.appendCall(CodeNavServiceGetReferencesFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetReferences method
+// of the parent MockCodeNavService instance is invoked and the hook queue
+// is empty.
+func (f *CodeNavServiceGetReferencesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetReferences method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetReferencesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetReferencesFunc) SetDefaultReturn(r0 []types.UploadLocation, r1 shared1.ReferencesCursor, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetReferencesFunc) PushReturn(r0 []types.UploadLocation, r1 shared1.ReferencesCursor, r2 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *CodeNavServiceGetReferencesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, shared1.ReferencesCursor) ([]types.UploadLocation, shared1.ReferencesCursor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetReferencesFunc) appendCall(r0 CodeNavServiceGetReferencesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetReferencesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetReferencesFunc) History() []CodeNavServiceGetReferencesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetReferencesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetReferencesFuncCall is an object that describes an
+// invocation of method GetReferences on an instance of MockCodeNavService.
+type CodeNavServiceGetReferencesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 shared1.ReferencesCursor
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.UploadLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 shared1.ReferencesCursor
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetReferencesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetReferencesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// CodeNavServiceGetStencilFunc describes the behavior when the GetStencil
+// method of the parent MockCodeNavService instance is invoked.
+type CodeNavServiceGetStencilFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)
+	history     []CodeNavServiceGetStencilFuncCall
+	mutex       sync.Mutex
+}
+
+// GetStencil delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetStencil(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState) ([]types.Range, error) {
+	r0, r1 := m.GetStencilFunc.nextHook()(v0, v1, v2)
+	m.GetStencilFunc.appendCall(CodeNavServiceGetStencilFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetStencil method of
+// the parent MockCodeNavService instance is invoked and the hook queue is
+// empty.
+func (f *CodeNavServiceGetStencilFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetStencil method of the parent MockCodeNavService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetStencilFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetStencilFunc) SetDefaultReturn(r0 []types.Range, r1 error) {
 	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState) ([]types.Range, error) {
 		return r0, r1
 	})
@@ -2060,6 +2608,235 @@ func (c CodeNavServiceGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeNavServiceGetSubtypesFunc describes the behavior when the GetSubtypes
+// method of the parent MockCodeNavService instance is invoked.
+type CodeNavServiceGetSubtypesFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)
+	history     []CodeNavServiceGetSubtypesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSubtypes delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetSubtypes(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 int) ([]shared1.TypeHierarchyItem, error) {
+	r0, r1 := m.GetSubtypesFunc.nextHook()(v0, v1, v2, v3)
+	m.GetSubtypesFunc.appendCall(CodeNavServiceGetSubtypesFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetSubtypes method
+// of the parent MockCodeNavService instance is invoked and the hook queue
+// is empty.
+func (f *CodeNavServiceGetSubtypesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSubtypes method of the parent MockCodeNavService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetSubtypesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetSubtypesFunc) SetDefaultReturn(r0 []shared1.TypeHierarchyItem, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetSubtypesFunc) PushReturn(r0 []shared1.TypeHierarchyItem, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetSubtypesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetSubtypesFunc) appendCall(r0 CodeNavServiceGetSubtypesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetSubtypesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetSubtypesFunc) History() []CodeNavServiceGetSubtypesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetSubtypesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetSubtypesFuncCall is an object that describes an
+// invocation of method GetSubtypes on an instance of MockCodeNavService.
+type CodeNavServiceGetSubtypesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.TypeHierarchyItem
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetSubtypesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetSubtypesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetSupertypesFunc describes the behavior when the
+// GetSupertypes method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetSupertypesFunc struct {
+	defaultHook func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)
+	hooks       []func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)
+	history     []CodeNavServiceGetSupertypesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSupertypes delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetSupertypes(v0 context.Context, v1 shared1.RequestArgs, v2 codenav.RequestState, v3 int) ([]shared1.TypeHierarchyItem, error) {
+	r0, r1 := m.GetSupertypesFunc.nextHook()(v0, v1, v2, v3)
+	m.GetSupertypesFunc.appendCall(CodeNavServiceGetSupertypesFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetSupertypes method
+// of the parent MockCodeNavService instance is invoked and the hook queue
+// is empty.
+func (f *CodeNavServiceGetSupertypesFunc) SetDefaultHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSupertypes method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetSupertypesFunc) PushHook(hook func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetSupertypesFunc) SetDefaultReturn(r0 []shared1.TypeHierarchyItem, r1 error) {
+	f.SetDefaultHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetSupertypesFunc) PushReturn(r0 []shared1.TypeHierarchyItem, r1 error) {
+	f.PushHook(func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetSupertypesFunc) nextHook() func(context.Context, shared1.RequestArgs, codenav.RequestState, int) ([]shared1.TypeHierarchyItem, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetSupertypesFunc) appendCall(r0 CodeNavServiceGetSupertypesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetSupertypesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetSupertypesFunc) History() []CodeNavServiceGetSupertypesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetSupertypesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetSupertypesFuncCall is an object that describes an
+// invocation of method GetSupertypes on an instance of MockCodeNavService.
+type CodeNavServiceGetSupertypesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.RequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.TypeHierarchyItem
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetSupertypesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetSupertypesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeNavServiceGetUnsafeDBFunc describes the behavior when the GetUnsafeDB
 // method of the parent MockCodeNavService instance is invoked.
 type CodeNavServiceGetUnsafeDBFunc struct {
@@ -21,6 +21,12 @@ type operations struct {
 	diagnostics     *observation.Operation
 	stencil         *observation.Operation
 	ranges          *observation.Operation
+	incomingCalls   *observation.Operation
+	outgoingCalls   *observation.Operation
+	supertypes      *observation.Operation
+	subtypes        *observation.Operation
+	prototypes      *observation.Operation
+	documentSymbols *observation.Operation
 
 	gitBlobLsifData *observation.Operation
 }
@@ -49,6 +55,12 @@ func newOperations(observationContext *observation.Context) *operations {
 		diagnostics:     op("Diagnostics"),
 		stencil:         op("Stencil"),
 		ranges:          op("Ranges"),
+		incomingCalls:   op("IncomingCalls"),
+		outgoingCalls:   op("OutgoingCalls"),
+		supertypes:      op("Supertypes"),
+		subtypes:        op("Subtypes"),
+		prototypes:      op("Prototypes"),
+		documentSymbols: op("DocumentSymbols"),
 
 		gitBlobLsifData: op("GitBlobLsifData"),
 	}
@@ -0,0 +1,28 @@
+package graphql
+
+import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+)
+
+type typeHierarchyItemResolver struct {
+	item             shared.TypeHierarchyItem
+	locationResolver *sharedresolvers.CachedLocationResolver
+}
+
+func NewTypeHierarchyItemResolver(item shared.TypeHierarchyItem, locationResolver *sharedresolvers.CachedLocationResolver) resolverstubs.TypeHierarchyItemResolver {
+	return &typeHierarchyItemResolver{
+		item:             item,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *typeHierarchyItemResolver) Symbol() string { return r.item.Symbol }
+func (r *typeHierarchyItemResolver) Name() string   { return r.item.Name }
+func (r *typeHierarchyItemResolver) Parent() string { return r.item.Parent }
+func (r *typeHierarchyItemResolver) Depth() int32   { return int32(r.item.Depth) }
+
+func (r *typeHierarchyItemResolver) Definitions() resolverstubs.LocationConnectionResolver {
+	return NewLocationConnectionResolver(r.item.Definitions, nil, r.locationResolver)
+}
@@ -0,0 +1,456 @@
+package codenav
+
+import (
+	"context"
+	"sort"
+
+	traceLog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// MaximumTypeHierarchyDepth is the maximum number of implementation relationships followed from the
+// requested position when traversing a type hierarchy.
+const MaximumTypeHierarchyDepth = 10
+
+// GetSupertypes returns the types and methods that the type or method at the requested position implements,
+// transitively up to the given depth. Supertypes are resolved in the visible uploads as well as in uploads of
+// other repositories that export them via monikers. If the requested position is not a type or method, the
+// hierarchy of its type definition is returned.
+//
+// Type hierarchy is computed from SCIP relationship data, which is written for SCIP and LSIF uploads alike.
+// The relationships of an LSIF upload are recovered from its implementation results and monikers when its
+// monikers are SCIP symbols; uploads processed before SCIP data was written contribute no types.
+func (s *Service) GetSupertypes(ctx context.Context, args shared.RequestArgs, requestState RequestState, depth int) (_ []shared.TypeHierarchyItem, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getSupertypes, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+			traceLog.Int("depth", depth),
+		},
+	})
+	defer endObservation()
+
+	symbols := newSymbolTable()
+	visibleUploads, roots, err := s.getTypeHierarchyRoots(ctx, args, requestState, symbols)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numRoots", len(roots)))
+
+	hierarchy, err := s.traverseSupertypes(ctx, args, requestState, visibleUploads, roots, depth, symbols)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numSupertypes", len(hierarchy.items)))
+
+	return s.resolveTypeHierarchy(ctx, args, requestState, hierarchy, symbols)
+}
+
+// GetSubtypes returns the types and methods that implement the type or method at the requested position,
+// transitively up to the given depth. Subtypes are found in the visible uploads as well as in uploads of
+// other repositories that reference the target via monikers. If the requested position is not a type or
+// method, the hierarchy of its type definition is returned.
+//
+// Type hierarchy is computed from SCIP relationship data, which is written for SCIP and LSIF uploads alike.
+// The relationships of an LSIF upload are recovered from its implementation results and monikers when its
+// monikers are SCIP symbols; uploads processed before SCIP data was written contribute no types.
+func (s *Service) GetSubtypes(ctx context.Context, args shared.RequestArgs, requestState RequestState, depth int) (_ []shared.TypeHierarchyItem, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getSubtypes, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+			traceLog.Int("depth", depth),
+		},
+	})
+	defer endObservation()
+
+	symbols := newSymbolTable()
+	visibleUploads, roots, err := s.getTypeHierarchyRoots(ctx, args, requestState, symbols)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numRoots", len(roots)))
+
+	hierarchy, err := s.traverseSubtypes(ctx, args, requestState, visibleUploads, roots, depth, symbols)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(traceLog.Int("numSubtypes", len(hierarchy.items)))
+
+	return s.resolveTypeHierarchy(ctx, args, requestState, hierarchy, symbols)
+}
+
+// GetPrototypes returns the definitions of the interface methods satisfied by the method at the requested
+// position, including those satisfied transitively through other interface methods.
+func (s *Service) GetPrototypes(ctx context.Context, args shared.RequestArgs, requestState RequestState) (_ []types.UploadLocation, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getPrototypes, serviceObserverThreshold, observation.Args{
+		LogFields: []traceLog.Field{
+			traceLog.Int("repositoryID", args.RepositoryID),
+			traceLog.String("commit", args.Commit),
+			traceLog.String("path", args.Path),
+			traceLog.Int("numUploads", len(requestState.GetCacheUploads())),
+			traceLog.String("uploads", uploadIDsToString(requestState.GetCacheUploads())),
+			traceLog.Int("line", args.Line),
+			traceLog.Int("character", args.Character),
+		},
+	})
+	defer endObservation()
+
+	symbols := newSymbolTable()
+	visibleUploads, roots, err := s.getTypeHierarchyRoots(ctx, args, requestState, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := roots[:0]
+	for _, symbol := range roots {
+		if symbols.isCallable(symbol) {
+			methods = append(methods, symbol)
+		}
+	}
+	trace.Log(traceLog.Int("numMethods", len(methods)))
+
+	hierarchy, err := s.traverseSupertypes(ctx, args, requestState, visibleUploads, methods, MaximumTypeHierarchyDepth, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []shared.Location
+	for _, item := range hierarchy.items {
+		locations = append(locations, hierarchy.definitions[item.symbol]...)
+	}
+	trace.Log(traceLog.Int("numPrototypes", len(locations)))
+
+	return s.getUploadLocations(ctx, args, requestState, locations, true)
+}
+
+// getTypeHierarchyRoots returns the visible uploads and the global type and method symbols of the SCIP
+// occurrences enclosing the adjusted position within each of them. Symbols of other kinds (such as variables)
+// are replaced by the types declared by their type definition relationships.
+func (s *Service) getTypeHierarchyRoots(ctx context.Context, args shared.RequestArgs, requestState RequestState, symbols *symbolTable) ([]visibleUpload, []string, error) {
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roots []string
+	seen := map[string]struct{}{}
+	add := func(symbol string) {
+		if _, ok := seen[symbol]; !ok {
+			seen[symbol] = struct{}{}
+			roots = append(roots, symbol)
+		}
+	}
+
+	for i := range visibleUploads {
+		document, ok, err := s.lsifstore.GetSCIPDocument(ctx, visibleUploads[i].Upload.ID, visibleUploads[i].TargetPathWithoutRoot)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+		}
+		if !ok {
+			continue
+		}
+		symbolInformation := symbolInformationBySymbol(document)
+
+		occurrences := types.FindOccurrences(
+			document.Occurrences,
+			int32(visibleUploads[i].TargetPosition.Line),
+			int32(visibleUploads[i].TargetPosition.Character),
+		)
+		for _, occurrence := range occurrences {
+			if symbols.isType(occurrence.Symbol) || symbols.isCallable(occurrence.Symbol) {
+				add(occurrence.Symbol)
+				continue
+			}
+
+			if information, ok := symbolInformation[occurrence.Symbol]; ok {
+				for _, relationship := range information.Relationships {
+					if relationship.IsTypeDefinition && symbols.isType(relationship.Symbol) {
+						add(relationship.Symbol)
+					}
+				}
+			}
+		}
+	}
+
+	return visibleUploads, roots, nil
+}
+
+// traverseSupertypes follows the implementation relationships declared by the given root symbols (and by the
+// symbols reached from them) breadth-first up to the given depth.
+func (s *Service) traverseSupertypes(ctx context.Context, args shared.RequestArgs, requestState RequestState, visibleUploads []visibleUpload, roots []string, depth int, symbols *symbolTable) (*typeHierarchy, error) {
+	hierarchy := newTypeHierarchy(roots)
+	depth = clampTypeHierarchyDepth(depth)
+
+	frontier := roots
+	for level := 0; len(frontier) > 0; level++ {
+		// Supertypes may be defined in the visible uploads or in any upload that exports them
+		definitionUploads, err := s.getUploadsWithDefinitionsForMonikers(ctx, symbols.monikers(frontier, "export"), requestState)
+		if err != nil {
+			return nil, err
+		}
+
+		definitions, err := s.lsifstore.GetSymbolUsages(ctx, lsifstore.SymbolUsageDefinitions, mergeUploadIDs(visibleUploads, definitionUploads), frontier)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifstore.GetSymbolUsages")
+		}
+		if level > 0 {
+			for _, definition := range definitions {
+				hierarchy.addDefinition(definition.Symbol, definition.Location)
+			}
+		}
+		if level == depth {
+			break
+		}
+
+		var next []string
+		for _, group := range groupByDocument(definitions) {
+			document, ok, err := s.lsifstore.GetSCIPDocument(ctx, group[0].DumpID, group[0].Path)
+			if err != nil {
+				return nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+			}
+			if !ok {
+				continue
+			}
+			symbolInformation := symbolInformationBySymbol(document)
+
+			for _, definition := range group {
+				information, ok := symbolInformation[definition.Symbol]
+				if !ok {
+					continue
+				}
+
+				for _, relationship := range information.Relationships {
+					if !relationship.IsImplementation || symbols.get(relationship.Symbol) == nil {
+						continue
+					}
+					if hierarchy.add(relationship.Symbol, definition.Symbol, level+1, args.Limit) {
+						next = append(next, relationship.Symbol)
+					}
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return hierarchy, nil
+}
+
+// traverseSubtypes follows the implementation relationships declared towards the given root symbols (and
+// towards the symbols reached from them) breadth-first up to the given depth.
+func (s *Service) traverseSubtypes(ctx context.Context, args shared.RequestArgs, requestState RequestState, visibleUploads []visibleUpload, roots []string, depth int, symbols *symbolTable) (*typeHierarchy, error) {
+	hierarchy := newTypeHierarchy(roots)
+	depth = clampTypeHierarchyDepth(depth)
+
+	ignoreIDs := make([]int, 0, len(visibleUploads))
+	for i := range visibleUploads {
+		ignoreIDs = append(ignoreIDs, visibleUploads[i].Upload.ID)
+	}
+
+	frontier := roots
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		// Subtypes may be defined in the visible uploads or in any upload that imports the supertype
+		referenceUploadIDs, _, _, err := s.uploadSvc.GetUploadIDsWithReferences(
+			ctx,
+			symbols.monikers(frontier, "import"),
+			ignoreIDs,
+			args.RepositoryID,
+			args.Commit,
+			requestState.maximumIndexesPerMonikerSearch,
+			0,
+		)
+		if err != nil {
+			return nil, err
+		}
+		referenceUploads, err := s.getUploadsByIDs(ctx, referenceUploadIDs, requestState)
+		if err != nil {
+			return nil, err
+		}
+
+		implementations, err := s.lsifstore.GetSymbolUsages(ctx, lsifstore.SymbolUsageImplementations, mergeUploadIDs(visibleUploads, referenceUploads), frontier)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifstore.GetSymbolUsages")
+		}
+
+		var next []string
+		for _, group := range groupByDocument(implementations) {
+			document, ok, err := s.lsifstore.GetSCIPDocument(ctx, group[0].DumpID, group[0].Path)
+			if err != nil {
+				return nil, errors.Wrap(err, "lsifstore.GetSCIPDocument")
+			}
+			if !ok {
+				continue
+			}
+			symbolInformation := symbolInformationBySymbol(document)
+
+			for _, implementation := range group {
+				// Implementation ranges are the definition ranges of the implementing symbols
+				for _, occurrence := range document.Occurrences {
+					if !scip.SymbolRole_Definition.Matches(occurrence) || translateSCIPRange(occurrence.Range) != implementation.Range {
+						continue
+					}
+					if !implementsSymbol(symbolInformation[occurrence.Symbol], implementation.Symbol) {
+						continue
+					}
+
+					if hierarchy.add(occurrence.Symbol, implementation.Symbol, level+1, args.Limit) {
+						next = append(next, occurrence.Symbol)
+					}
+					hierarchy.addDefinition(occurrence.Symbol, implementation.Location)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return hierarchy, nil
+}
+
+// resolveTypeHierarchy converts the definitions of the given hierarchy into locations in the requested commit.
+// Items whose definitions are all filtered by sub-repo permissions are dropped.
+func (s *Service) resolveTypeHierarchy(ctx context.Context, args shared.RequestArgs, requestState RequestState, hierarchy *typeHierarchy, symbols *symbolTable) ([]shared.TypeHierarchyItem, error) {
+	items := make([]shared.TypeHierarchyItem, 0, len(hierarchy.items))
+	for _, item := range hierarchy.items {
+		locations := hierarchy.definitions[item.symbol]
+
+		definitions, err := s.getUploadLocations(ctx, args, requestState, locations, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(locations) > 0 && len(definitions) == 0 {
+			continue
+		}
+
+		items = append(items, shared.TypeHierarchyItem{
+			Symbol:      item.symbol,
+			Name:        symbols.displayName(item.symbol),
+			Parent:      item.parent,
+			Depth:       item.depth,
+			Definitions: definitions,
+		})
+	}
+
+	return items, nil
+}
+
+// typeHierarchy accumulates the symbols reached during a type hierarchy traversal in order of discovery.
+type typeHierarchy struct {
+	visited     map[string]struct{}
+	items       []typeHierarchyItem
+	definitions map[string][]shared.Location
+}
+
+type typeHierarchyItem struct {
+	symbol string
+	parent string
+	depth  int
+}
+
+func newTypeHierarchy(roots []string) *typeHierarchy {
+	visited := make(map[string]struct{}, len(roots))
+	for _, root := range roots {
+		visited[root] = struct{}{}
+	}
+
+	return &typeHierarchy{
+		visited:     visited,
+		definitions: map[string][]shared.Location{},
+	}
+}
+
+// add records the given symbol as reached through the given parent. This method returns false if the symbol
+// was already reached or if the hierarchy already holds the given (positive) number of items.
+func (h *typeHierarchy) add(symbol, parent string, depth, limit int) bool {
+	if _, ok := h.visited[symbol]; ok {
+		return false
+	}
+	if limit > 0 && len(h.items) >= limit {
+		return false
+	}
+
+	h.visited[symbol] = struct{}{}
+	h.items = append(h.items, typeHierarchyItem{symbol: symbol, parent: parent, depth: depth})
+	return true
+}
+
+func (h *typeHierarchy) addDefinition(symbol string, location shared.Location) {
+	for _, definition := range h.definitions[symbol] {
+		if definition == location {
+			return
+		}
+	}
+	h.definitions[symbol] = append(h.definitions[symbol], location)
+}
+
+func clampTypeHierarchyDepth(depth int) int {
+	if depth < 1 {
+		return 1
+	}
+	if depth > MaximumTypeHierarchyDepth {
+		return MaximumTypeHierarchyDepth
+	}
+
+	return depth
+}
+
+// implementsSymbol returns true if the given symbol information declares an implementation relationship
+// to the given symbol.
+func implementsSymbol(information *scip.SymbolInformation, symbol string) bool {
+	if information == nil {
+		return false
+	}
+
+	for _, relationship := range information.Relationships {
+		if relationship.IsImplementation && relationship.Symbol == symbol {
+			return true
+		}
+	}
+
+	return false
+}
+
+func symbolInformationBySymbol(document *scip.Document) map[string]*scip.SymbolInformation {
+	symbolInformation := make(map[string]*scip.SymbolInformation, len(document.Symbols))
+	for _, information := range document.Symbols {
+		symbolInformation[information.Symbol] = information
+	}
+
+	return symbolInformation
+}
+
+// mergeUploadIDs returns the sorted and deduplicated identifiers of the given visible and additional uploads.
+func mergeUploadIDs(visibleUploads []visibleUpload, uploads []types.Dump) []int {
+	idSet := make(map[int]struct{}, len(visibleUploads)+len(uploads))
+	for i := range visibleUploads {
+		idSet[visibleUploads[i].Upload.ID] = struct{}{}
+	}
+	for _, upload := range uploads {
+		idSet[upload.ID] = struct{}{}
+	}
+
+	ids := make([]int, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
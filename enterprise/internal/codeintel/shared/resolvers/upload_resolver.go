@@ -51,6 +51,7 @@ func (r *UploadResolver) ID() graphql.ID        { return marshalLSIFUploadGQLID(
 func (r *UploadResolver) InputCommit() string   { return r.upload.Commit }
 func (r *UploadResolver) InputRoot() string     { return r.upload.Root }
 func (r *UploadResolver) IsLatestForRepo() bool { return r.upload.VisibleAtTip }
+func (r *UploadResolver) Ephemeral() bool       { return r.upload.Ephemeral }
 func (r *UploadResolver) UploadedAt() gqlutil.DateTime {
 	return gqlutil.DateTime{Time: r.upload.UploadedAt}
 }
@@ -32,6 +32,7 @@ type Index struct {
 	Rank               *int                           `json:"placeInQueue"`
 	AssociatedUploadID *int                           `json:"associatedUpload"`
 	ShouldReindex      bool                           `json:"shouldReindex"`
+	Caches             []IndexCache                   `json:"caches"`
 }
 
 func (i Index) RecordID() int {
@@ -56,3 +57,24 @@ func (s *DockerStep) Scan(value any) error {
 func (s DockerStep) Value() (driver.Value, error) {
 	return json.Marshal(s)
 }
+
+// IndexCache is a set of directories persisted by the executor between index jobs of the
+// same repository. See config.Cache.
+type IndexCache struct {
+	Key      string   `json:"key"`
+	KeyFiles []string `json:"key_files"`
+	Paths    []string `json:"paths"`
+}
+
+func (c *IndexCache) Scan(value any) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.Errorf("value is not []byte: %T", value)
+	}
+
+	return json.Unmarshal(b, &c)
+}
+
+func (c IndexCache) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
@@ -78,6 +78,18 @@ var (
 		Name: "lsif-dotnet",
 		URN:  "github.com/tcz717/LsifDotnet",
 	}
+	scipDotnet = CodeIntelIndexer{
+		Name: "scip-dotnet",
+		URN:  "github.com/sourcegraph/scip-dotnet",
+	}
+	scipPHP = CodeIntelIndexer{
+		Name: "scip-php",
+		URN:  "github.com/sourcegraph/scip-php",
+	}
+	scipLSP = CodeIntelIndexer{
+		Name: "scip-lsp",
+		URN:  "github.com/sourcegraph/sourcegraph/tree/main/enterprise/cmd/scip-lsp",
+	}
 )
 
 var AllIndexers = []CodeIntelIndexer{
@@ -99,6 +111,9 @@ var AllIndexers = []CodeIntelIndexer{
 	lsifPHP,
 	lsifTerraform,
 	lsifDotnet,
+	scipDotnet,
+	scipPHP,
+	scipLSP,
 }
 
 // A map of file extension to a list of indexers in order of recommendation
@@ -124,9 +139,9 @@ var LanguageToIndexer = map[string][]CodeIntelIndexer{
 	".py":      {scipPython},
 	".ml":      {lsifOcaml},
 	".rs":      {rustAnalyzer},
-	".php":     {lsifPHP},
+	".php":     {scipPHP, lsifPHP},
 	".tf":      {lsifTerraform},
-	".cs":      {lsifDotnet},
+	".cs":      {scipDotnet, lsifDotnet},
 }
 
 var ImageToIndexer = map[string]CodeIntelIndexer{
@@ -138,4 +153,7 @@ var ImageToIndexer = map[string]CodeIntelIndexer{
 	"davidrjenni/lsif-php":        lsifPHP,
 	"sourcegraph/lsif-rust":       rustAnalyzer,
 	"sourcegraph/scip-python":     scipPython,
+	"sourcegraph/scip-dotnet":     scipDotnet,
+	"sourcegraph/scip-php":        scipPHP,
+	"sourcegraph/scip-lsp":        scipLSP,
 }
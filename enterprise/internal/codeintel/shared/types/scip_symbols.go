@@ -15,14 +15,17 @@ type InvertedRangeIndex struct {
 }
 
 // ExtractSymbolIndexes creates the inverse index of symbol uses to sets of ranges within the given
-// document.
+// document. The implementation ranges of a symbol are the definition ranges of the symbols in this
+// document that declare an implementation relationship to it.
 func ExtractSymbolIndexes(document *scip.Document) []InvertedRangeIndex {
-	rangesBySymbol := make(map[string]struct {
-		definitionRanges []*scip.Range
-		referenceRanges  []*scip.Range
-		// implementationRanges []*scip.Range // TODO
+	type rangeSet struct {
+		definitionRanges     []*scip.Range
+		referenceRanges      []*scip.Range
+		implementationRanges []*scip.Range
 		// typeDefinitionRanges []*scip.Range // TODO
-	}, len(document.Occurrences))
+	}
+
+	rangesBySymbol := make(map[string]rangeSet, len(document.Occurrences))
 	for _, occurrence := range document.Occurrences {
 		if occurrence.Symbol == "" || scip.IsLocalSymbol(occurrence.Symbol) {
 			continue
@@ -43,13 +46,31 @@ func ExtractSymbolIndexes(document *scip.Document) []InvertedRangeIndex {
 		rangesBySymbol[occurrence.Symbol] = rangeSet
 	}
 
+	for _, symbol := range document.Symbols {
+		definitionRanges := rangesBySymbol[symbol.Symbol].definitionRanges
+		if len(definitionRanges) == 0 {
+			continue
+		}
+
+		for _, relationship := range symbol.Relationships {
+			if !relationship.IsImplementation || relationship.Symbol == "" || scip.IsLocalSymbol(relationship.Symbol) {
+				continue
+			}
+
+			// The implemented symbol need not occur within this document
+			rangeSet := rangesBySymbol[relationship.Symbol]
+			rangeSet.implementationRanges = append(rangeSet.implementationRanges, definitionRanges...)
+			rangesBySymbol[relationship.Symbol] = rangeSet
+		}
+	}
+
 	invertedRangeIndexes := make([]InvertedRangeIndex, 0, len(rangesBySymbol))
 	for symbolName, rangeSet := range rangesBySymbol {
 		invertedRangeIndexes = append(invertedRangeIndexes, InvertedRangeIndex{
-			SymbolName:       symbolName,
-			DefinitionRanges: collapseRanges(rangeSet.definitionRanges),
-			ReferenceRanges:  collapseRanges(rangeSet.referenceRanges),
-			// ImplementationRanges: collapseRanges(rangeSet.implementationRanges), // TODO
+			SymbolName:           symbolName,
+			DefinitionRanges:     collapseRanges(rangeSet.definitionRanges),
+			ReferenceRanges:      collapseRanges(rangeSet.referenceRanges),
+			ImplementationRanges: collapseRanges(rangeSet.implementationRanges),
 			// TypeDefinitionRanges: collapseRanges(rangeSet.typeDefinitionRanges), // TODO
 		})
 	}
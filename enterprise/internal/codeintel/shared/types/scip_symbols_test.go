@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+)
+
+func TestExtractSymbolIndexes(t *testing.T) {
+	document := &scip.Document{
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{1, 5, 11}, Symbol: "scheme a v1 Dog#", SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{2, 5, 11}, Symbol: "scheme a v1 Dog#sound().", SymbolRoles: int32(scip.SymbolRole_Definition)},
+			{Range: []int32{4, 1, 4}, Symbol: "scheme a v1 Dog#"},
+			{Range: []int32{5, 1, 4}, Symbol: "local 0", SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+		Symbols: []*scip.SymbolInformation{
+			{
+				Symbol: "scheme a v1 Dog#",
+				Relationships: []*scip.Relationship{
+					{Symbol: "scheme b v1 Animal#", IsImplementation: true},
+					{Symbol: "scheme b v1 Named#", IsReference: true},
+				},
+			},
+			{
+				Symbol: "scheme a v1 Dog#sound().",
+				Relationships: []*scip.Relationship{
+					{Symbol: "scheme b v1 Animal#sound().", IsImplementation: true},
+				},
+			},
+		},
+	}
+
+	expected := []InvertedRangeIndex{
+		{SymbolName: "scheme a v1 Dog#", DefinitionRanges: []int32{1, 5, 1, 11}, ReferenceRanges: []int32{4, 1, 4, 4}},
+		{SymbolName: "scheme a v1 Dog#sound().", DefinitionRanges: []int32{2, 5, 2, 11}},
+		{SymbolName: "scheme b v1 Animal#", ImplementationRanges: []int32{1, 5, 1, 11}},
+		{SymbolName: "scheme b v1 Animal#sound().", ImplementationRanges: []int32{2, 5, 2, 11}},
+	}
+	if diff := cmp.Diff(expected, ExtractSymbolIndexes(document)); diff != "" {
+		t.Errorf("unexpected symbol indexes (-want +got):\n%s", diff)
+	}
+}
@@ -25,6 +25,8 @@ type Upload struct {
 	UncompressedSize  *int64
 	Rank              *int
 	AssociatedIndexID *int
+	Ephemeral         bool
+	BaseUploadID      *int
 }
 
 func (u Upload) RecordID() int {
@@ -0,0 +1,213 @@
+package uploads
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetDirectoryCoverage returns the uploads that cover the given directory at the given commit, as
+// well as the uploads covering each of its subdirectories up to the given depth. Uploads are selected
+// in the same way as they are when resolving code intelligence for a directory: an upload covers a
+// directory if it is visible from the commit and its root either encloses or lies within the directory.
+func (s *Service) GetDirectoryCoverage(ctx context.Context, repositoryID int, commit, path string, depth int) (_ *shared.DirectoryCoverage, err error) {
+	ctx, trace, endObservation := s.operations.getDirectoryCoverage.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+		log.String("path", path),
+		log.Int("depth", depth),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if path != "" && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	uploads, err := s.getUploadCoverage(ctx, repositoryID, commit, path)
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(log.Int("numUploads", len(uploads)))
+
+	root := &shared.DirectoryCoverage{Path: path}
+	if err := s.populateSubdirectories(ctx, repositoryID, commit, root, depth); err != nil {
+		return nil, err
+	}
+	assignUploadCoverage(root, uploads)
+
+	return root, nil
+}
+
+// GetRepositoryCoverage returns a page of repositories with uploads visible at the tip of their default
+// branch. Each upload is annotated with its distance from the current tip of the default branch.
+func (s *Service) GetRepositoryCoverage(ctx context.Context, opts shared.GetRepositoryCoverageOptions) (_ []shared.RepositoryCoverage, totalCount int, err error) {
+	ctx, _, endObservation := s.operations.getRepositoryCoverage.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("term", opts.Term),
+		log.Int("limit", opts.Limit),
+		log.Int("offset", opts.Offset),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repositories, totalCount, err := s.store.GetRepositoryCoverage(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, repository := range repositories {
+		commit, ok, err := s.gitserverClient.Head(ctx, repository.RepositoryID)
+		if err != nil {
+			if gitdomain.IsRepoNotExist(err) {
+				continue
+			}
+			return nil, 0, errors.Wrap(err, "gitserverClient.Head")
+		}
+		if !ok {
+			continue
+		}
+
+		distances, err := s.store.GetVisibleUploadDistances(ctx, repository.RepositoryID, commit)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "store.GetVisibleUploadDistances")
+		}
+
+		repositories[i].Commit = commit
+		for j, upload := range repository.Uploads {
+			repositories[i].Uploads[j].Distance = uploadDistance(commit, upload.Upload.ID, upload.Upload.Commit, distances)
+		}
+	}
+
+	return repositories, totalCount, nil
+}
+
+// getUploadCoverage returns the uploads intersecting the given directory at the given commit along with
+// their distance from the commit. Uploads for commits unknown to gitserver are discarded, as they are
+// when resolving code intelligence queries.
+func (s *Service) getUploadCoverage(ctx context.Context, repositoryID int, commit, path string) ([]shared.UploadCoverage, error) {
+	dumps, err := s.InferClosestUploads(ctx, repositoryID, commit, path, false, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(dumps) == 0 {
+		return nil, nil
+	}
+
+	distances, err := s.store.GetVisibleUploadDistances(ctx, repositoryID, commit)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.GetVisibleUploadDistances")
+	}
+
+	commitExists := map[string]bool{commit: true}
+	uploads := make([]shared.UploadCoverage, 0, len(dumps))
+	for _, dump := range dumps {
+		exists, ok := commitExists[dump.Commit]
+		if !ok {
+			if exists, err = s.gitserverClient.CommitExists(ctx, repositoryID, dump.Commit); err != nil {
+				return nil, errors.Wrap(err, "gitserverClient.CommitExists")
+			}
+			commitExists[dump.Commit] = exists
+		}
+		if !exists {
+			continue
+		}
+
+		uploads = append(uploads, shared.UploadCoverage{
+			Upload:   dump,
+			Distance: uploadDistance(commit, dump.ID, dump.Commit, distances),
+		})
+	}
+
+	sort.Slice(uploads, func(i, j int) bool {
+		if uploads[i].Upload.Root != uploads[j].Upload.Root {
+			return uploads[i].Upload.Root < uploads[j].Upload.Root
+		}
+		return uploads[i].Upload.Indexer < uploads[j].Upload.Indexer
+	})
+
+	return uploads, nil
+}
+
+// populateSubdirectories attaches the subdirectories of the given directory, up to the given depth.
+// Git does not distinguish files from directories when listing directory children, but only the
+// directories have children of their own. Listing the children of each candidate entry therefore
+// determines which entries are directories and provides the candidates of the following level.
+func (s *Service) populateSubdirectories(ctx context.Context, repositoryID int, commit string, root *shared.DirectoryCoverage, depth int) error {
+	if depth <= 0 {
+		return nil
+	}
+
+	children, err := s.gitserverClient.DirectoryChildren(ctx, repositoryID, commit, []string{root.Path})
+	if err != nil {
+		return errors.Wrap(err, "gitserverClient.DirectoryChildren")
+	}
+
+	directories := []*shared.DirectoryCoverage{root}
+	for level := 0; level < depth && len(directories) > 0; level++ {
+		var candidates []string
+		parents := map[string]*shared.DirectoryCoverage{}
+		for _, directory := range directories {
+			for _, child := range children[directory.Path] {
+				candidate := child + "/"
+				candidates = append(candidates, candidate)
+				parents[candidate] = directory
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		sort.Strings(candidates)
+
+		// Pass a copy as the client may reorder its input
+		children, err = s.gitserverClient.DirectoryChildren(ctx, repositoryID, commit, append([]string(nil), candidates...))
+		if err != nil {
+			return errors.Wrap(err, "gitserverClient.DirectoryChildren")
+		}
+
+		directories = nil
+		for _, candidate := range candidates {
+			if len(children[candidate]) == 0 {
+				continue
+			}
+
+			directory := &shared.DirectoryCoverage{Path: candidate}
+			parents[candidate].Children = append(parents[candidate].Children, directory)
+			directories = append(directories, directory)
+		}
+	}
+
+	return nil
+}
+
+// assignUploadCoverage attaches the given uploads to the directories they cover. The uploads covering a
+// directory are a subset of the uploads covering its parent.
+func assignUploadCoverage(directory *shared.DirectoryCoverage, uploads []shared.UploadCoverage) {
+	for _, upload := range uploads {
+		if strings.HasPrefix(directory.Path, upload.Upload.Root) || strings.HasPrefix(upload.Upload.Root, directory.Path) {
+			directory.Uploads = append(directory.Uploads, upload)
+		}
+	}
+
+	for _, child := range directory.Children {
+		assignUploadCoverage(child, directory.Uploads)
+	}
+}
+
+// uploadDistance returns the number of commits between the target commit and the commit of the given
+// upload, or nil if the distance is not known.
+func uploadDistance(commit string, uploadID int, uploadCommit string, distances map[int]int) *int {
+	if uploadCommit == commit {
+		distance := 0
+		return &distance
+	}
+	if distance, ok := distances[uploadID]; ok {
+		return &distance
+	}
+
+	return nil
+}
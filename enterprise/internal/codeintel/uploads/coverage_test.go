@@ -0,0 +1,182 @@
+package uploads
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestGetDirectoryCoverage(t *testing.T) {
+	mockStore := NewMockStore()
+	mockGitserverClient := NewMockGitserverClient()
+	svc := &Service{
+		store:           mockStore,
+		gitserverClient: mockGitserverClient,
+		operations:      newOperations(&observation.TestContext),
+	}
+
+	mockStore.FindClosestDumpsFunc.SetDefaultReturn([]types.Dump{
+		{ID: 1, Commit: "deadbeef", Root: "web/", Indexer: "scip-typescript"},
+		{ID: 2, Commit: "cafebabe", Root: "", Indexer: "scip-go"},
+		{ID: 3, Commit: "c0ffee", Root: "web/shared/", Indexer: "scip-typescript"},
+		{ID: 4, Commit: "f00d", Root: "cmd/", Indexer: "scip-go"},
+	}, nil)
+	mockStore.GetVisibleUploadDistancesFunc.SetDefaultReturn(map[int]int{2: 3, 4: 7}, nil)
+
+	// The commit of upload 4 no longer exists
+	mockGitserverClient.CommitExistsFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit string) (bool, error) {
+		return commit != "f00d", nil
+	})
+
+	tree := map[string][]string{
+		"":            {"README.md", "cmd", "web"},
+		"cmd/":        {"cmd/main.go"},
+		"web/":        {"web/package.json", "web/shared", "web/src"},
+		"web/shared/": {"web/shared/index.ts"},
+		"web/src/":    {"web/src/index.ts"},
+	}
+	mockGitserverClient.DirectoryChildrenFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit string, dirnames []string) (map[string][]string, error) {
+		// Mimic gitserver, which reorders its input
+		sort.Slice(dirnames, func(i, j int) bool { return len(dirnames[i]) > len(dirnames[j]) })
+
+		children := map[string][]string{}
+		for _, dirname := range dirnames {
+			children[dirname] = tree[dirname]
+		}
+		return children, nil
+	})
+
+	coverage, err := svc.GetDirectoryCoverage(context.Background(), 42, "deadbeef", "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	type directory struct {
+		Path      string
+		Uploads   []int
+		Distances []int
+	}
+	var directories []directory
+	var flatten func(d *shared.DirectoryCoverage)
+	flatten = func(d *shared.DirectoryCoverage) {
+		entry := directory{Path: d.Path}
+		for _, upload := range d.Uploads {
+			distance := -1
+			if upload.Distance != nil {
+				distance = *upload.Distance
+			}
+			entry.Uploads = append(entry.Uploads, upload.Upload.ID)
+			entry.Distances = append(entry.Distances, distance)
+		}
+		directories = append(directories, entry)
+
+		for _, child := range d.Children {
+			flatten(child)
+		}
+	}
+	flatten(coverage)
+
+	expected := []directory{
+		{Path: "", Uploads: []int{2, 1, 3}, Distances: []int{3, 0, -1}},
+		{Path: "cmd/", Uploads: []int{2}, Distances: []int{3}},
+		{Path: "web/", Uploads: []int{2, 1, 3}, Distances: []int{3, 0, -1}},
+		{Path: "web/shared/", Uploads: []int{2, 1, 3}, Distances: []int{3, 0, -1}},
+		{Path: "web/src/", Uploads: []int{2, 1}, Distances: []int{3, 0}},
+	}
+	if diff := cmp.Diff(expected, directories); diff != "" {
+		t.Errorf("unexpected coverage (-want +got):\n%s", diff)
+	}
+
+	if history := mockStore.FindClosestDumpsFunc.History(); len(history) != 1 || history[0].Arg3 != "" || history[0].Arg4 {
+		t.Errorf("unexpected closest dump queries: %v", history)
+	}
+}
+
+func TestGetDirectoryCoverageSubdirectory(t *testing.T) {
+	mockStore := NewMockStore()
+	mockGitserverClient := NewMockGitserverClient()
+	svc := &Service{
+		store:           mockStore,
+		gitserverClient: mockGitserverClient,
+		operations:      newOperations(&observation.TestContext),
+	}
+
+	mockStore.FindClosestDumpsFunc.SetDefaultReturn([]types.Dump{
+		{ID: 1, Commit: "deadbeef", Root: "web/", Indexer: "scip-typescript"},
+	}, nil)
+
+	coverage, err := svc.GetDirectoryCoverage(context.Background(), 42, "deadbeef", "web/src", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if coverage.Path != "web/src/" {
+		t.Errorf("unexpected path. want=%q have=%q", "web/src/", coverage.Path)
+	}
+	if len(coverage.Uploads) != 1 || len(coverage.Children) != 0 {
+		t.Errorf("unexpected coverage: %+v", coverage)
+	}
+	if history := mockStore.FindClosestDumpsFunc.History(); len(history) != 1 || history[0].Arg3 != "web/src/" {
+		t.Errorf("unexpected closest dump queries: %v", history)
+	}
+	if calls := len(mockGitserverClient.DirectoryChildrenFunc.History()); calls != 0 {
+		t.Errorf("unexpected number of directory children calls. want=%d have=%d", 0, calls)
+	}
+}
+
+func TestGetRepositoryCoverage(t *testing.T) {
+	mockStore := NewMockStore()
+	mockGitserverClient := NewMockGitserverClient()
+	svc := &Service{
+		store:           mockStore,
+		gitserverClient: mockGitserverClient,
+		operations:      newOperations(&observation.TestContext),
+	}
+
+	mockStore.GetRepositoryCoverageFunc.SetDefaultReturn([]shared.RepositoryCoverage{
+		{RepositoryID: 50, RepositoryName: "github.com/test/alpha", Uploads: []shared.UploadCoverage{
+			{Upload: types.Dump{ID: 1, Commit: "deadbeef", Root: "web/"}},
+			{Upload: types.Dump{ID: 2, Commit: "cafebabe", Root: "cmd/"}},
+		}},
+		{RepositoryID: 51, RepositoryName: "github.com/test/beta", Uploads: []shared.UploadCoverage{
+			{Upload: types.Dump{ID: 3, Commit: "c0ffee"}},
+		}},
+	}, 2, nil)
+	mockGitserverClient.HeadFunc.SetDefaultHook(func(ctx context.Context, repositoryID int) (string, bool, error) {
+		if repositoryID == 50 {
+			return "deadbeef", true, nil
+		}
+		return "", false, nil
+	})
+	mockStore.GetVisibleUploadDistancesFunc.SetDefaultReturn(map[int]int{2: 12}, nil)
+
+	repositories, totalCount, err := svc.GetRepositoryCoverage(context.Background(), shared.GetRepositoryCoverageOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if totalCount != 2 {
+		t.Errorf("unexpected total count. want=%d have=%d", 2, totalCount)
+	}
+
+	distance := 12
+	expected := []shared.RepositoryCoverage{
+		{RepositoryID: 50, RepositoryName: "github.com/test/alpha", Commit: "deadbeef", Uploads: []shared.UploadCoverage{
+			{Upload: types.Dump{ID: 1, Commit: "deadbeef", Root: "web/"}, Distance: new(int)},
+			{Upload: types.Dump{ID: 2, Commit: "cafebabe", Root: "cmd/"}, Distance: &distance},
+		}},
+		{RepositoryID: 51, RepositoryName: "github.com/test/beta", Uploads: []shared.UploadCoverage{
+			{Upload: types.Dump{ID: 3, Commit: "c0ffee"}},
+		}},
+	}
+	if diff := cmp.Diff(expected, repositories); diff != "" {
+		t.Errorf("unexpected coverage (-want +got):\n%s", diff)
+	}
+	if calls := len(mockStore.GetVisibleUploadDistancesFunc.History()); calls != 1 {
+		t.Errorf("unexpected number of distance queries. want=%d have=%d", 1, calls)
+	}
+}
@@ -237,6 +237,13 @@ func (s *expirer) isUploadProtectedByPolicy(
 ) (bool, error) {
 	metrics.NumUploadsScanned.Inc()
 
+	// Ephemeral uploads are not part of the commit graph and are not visible from any commit but
+	// their own, so they're protected only by the policies matching that commit.
+	if upload.Ephemeral {
+		metrics.NumCommitsScanned.Inc()
+		return isCommitProtectedByPolicy(commitMap, upload, upload.Commit, now), nil
+	}
+
 	var token *string
 
 	for first := true; first || token != nil; first = false {
@@ -259,15 +266,28 @@ func (s *expirer) isUploadProtectedByPolicy(
 		metrics.NumCommitsScanned.Add(float64(len(commits)))
 
 		for _, commit := range commits {
-			if policyMatches, ok := commitMap[commit]; ok {
-				for _, policyMatch := range policyMatches {
-					if policyMatch.PolicyDuration == nil || now.Sub(upload.UploadedAt) < *policyMatch.PolicyDuration {
-						return true, nil
-					}
-				}
+			if isCommitProtectedByPolicy(commitMap, upload, commit, now) {
+				return true, nil
 			}
 		}
 	}
 
 	return false, nil
 }
+
+// isCommitProtectedByPolicy returns true if a policy matching the given commit retains the
+// upload at the current time.
+func isCommitProtectedByPolicy(
+	commitMap map[string][]policiesEnterprise.PolicyMatch,
+	upload types.Upload,
+	commit string,
+	now time.Time,
+) bool {
+	for _, policyMatch := range commitMap[commit] {
+		if policyMatch.PolicyDuration == nil || now.Sub(upload.UploadedAt) < *policyMatch.PolicyDuration {
+			return true
+		}
+	}
+
+	return false
+}
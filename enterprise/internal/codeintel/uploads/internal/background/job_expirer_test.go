@@ -51,12 +51,12 @@ func TestUploadExpirer(t *testing.T) {
 	}
 	sort.Ints(expiredIDs)
 
-	expectedProtectedIDs := []int{12, 16, 18, 20, 25, 26, 27, 28}
+	expectedProtectedIDs := []int{12, 16, 18, 20, 25, 26, 27, 28, 31, 33}
 	if diff := cmp.Diff(expectedProtectedIDs, protectedIDs); diff != "" {
 		t.Errorf("unexpected protected upload identifiers (-want +got):\n%s", diff)
 	}
 
-	expectedExpiredIDs := []int{11, 13, 14, 15, 17, 19, 21, 22, 23, 24, 29, 30}
+	expectedExpiredIDs := []int{11, 13, 14, 15, 17, 19, 21, 22, 23, 24, 29, 30, 32}
 	if diff := cmp.Diff(expectedExpiredIDs, expiredIDs); diff != "" {
 		t.Errorf("unexpected expired upload identifiers (-want +got):\n%s", diff)
 	}
@@ -131,6 +131,9 @@ func setupMockUploadService(now time.Time) *MockStore {
 		{ID: 28, State: "completed", RepositoryID: 53, Commit: "deadbeef18", UploadedAt: daysAgo(now, 2)},
 		{ID: 29, State: "completed", RepositoryID: 53, Commit: "deadbeef19", UploadedAt: daysAgo(now, 1)},
 		{ID: 30, State: "completed", RepositoryID: 53, Commit: "deadbeef20", UploadedAt: daysAgo(now, 9)},
+		{ID: 31, State: "completed", RepositoryID: 53, Commit: "deadbeef21", UploadedAt: daysAgo(now, 2), Ephemeral: true},
+		{ID: 32, State: "completed", RepositoryID: 53, Commit: "deadbeef22", UploadedAt: daysAgo(now, 4), Ephemeral: true},
+		{ID: 33, State: "completed", RepositoryID: 53, Commit: "deadbeef23", UploadedAt: daysAgo(now, 9), Ephemeral: true},
 	}
 
 	repositoryIDMap := map[int]struct{}{}
@@ -243,6 +246,10 @@ func testUploadExpirerMockPolicyMatcher() *MockPolicyMatcher {
 			"deadbeef18": {{PolicyDuration: days(5)}}, // 5 > 2 (protected)
 			"deadbeef19": {},
 			"deadbeef20": {},
+			"deadbeef21": {{PolicyDuration: days(3)}}, // ephemeral: 3 > 2 (protected)
+			"deadbeef22": {{PolicyDuration: days(3)}}, // ephemeral: 3 < 4
+			"deadcafe22": {{PolicyDuration: nil}},     // ephemeral: not visible from other commits
+			"deadbeef23": {{PolicyDuration: nil}},     // ephemeral: catch-all (protected)
 		},
 	}
 
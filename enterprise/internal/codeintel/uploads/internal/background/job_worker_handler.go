@@ -216,37 +216,48 @@ func (s *handler) HandleRawUpload(ctx context.Context, logger log.Logger, upload
 		return directoryChildren, nil
 	}
 
+	if upload.BaseUploadID != nil {
+		return false, s.handleIncrementalUpload(ctx, logger, upload, repo, isDefaultBranch, getChildren, uploadStore, trace)
+	}
+
 	return false, withUploadData(ctx, logger, uploadStore, upload.ID, trace, func(r io.Reader) (err error) {
-		groupedBundleData, err := conversion.Correlate(ctx, r, upload.Root, getChildren)
+		r, isSCIP, err := detectSCIPIndex(r)
 		if err != nil {
-			return errors.Wrap(err, "conversion.Correlate")
+			return err
 		}
+		trace.Log(otlog.Bool("scip", isSCIP))
 
-		// Find the commit date for the commit attached to this upload record and insert it into the
-		// database (if not already present). We need to have the commit data of every processed upload
-		// for a repository when calculating the commit graph (triggered at the end of this handler).
+		if isSCIP {
+			index, err := readSCIPIndex(r)
+			if err != nil {
+				return err
+			}
 
-		_, commitDate, revisionExists, err := s.gitserverClient.CommitDate(ctx, upload.RepositoryID, upload.Commit)
+			return s.handleSCIPIndex(ctx, logger, upload, repo, isDefaultBranch, getChildren, index, nil, trace)
+		}
+
+		groupedBundleData, err := conversion.Correlate(ctx, r, upload.Root, getChildren)
 		if err != nil {
-			return errors.Wrap(err, "gitserverClient.CommitDate")
+			return errors.Wrap(err, "conversion.Correlate")
 		}
-		if !revisionExists {
-			return errCommitDoesNotExist
+
+		if err := s.updateCommittedAt(ctx, upload, trace); err != nil {
+			return err
 		}
-		trace.Log(otlog.String("commitDate", commitDate.String()))
-
-		// We do the update here outside of the transaction started below to reduce the long blocking
-		// behavior we see when multiple uploads are being processed for the same repository and commit.
-		// We do choose to perform this before this the following transaction rather than after so that
-		// we can guarantee the presence of the date for this commit by the time the repository is set
-		// as dirty.
-		if err := s.store.UpdateCommittedAt(ctx, upload.RepositoryID, upload.Commit, commitDate.Format(time.RFC3339)); err != nil {
-			return errors.Wrap(err, "store.CommitDate")
+
+		// Derive the SCIP documents of the upload from its correlated data as it is written, so
+		// that the code navigation features reading SCIP data also cover LSIF uploads.
+		scipDocuments := newSCIPDocumentCollector()
+		groupedBundleData.Documents = scipDocuments.observeDocuments(ctx, groupedBundleData.Documents)
+		groupedBundleData.ResultChunks = scipDocuments.observeResultChunks(ctx, groupedBundleData.ResultChunks)
+
+		writeRemainingData := func(tx lsifstore.LsifStore) error {
+			return writeSCIPDocuments(ctx, tx, upload.ID, scipDocuments.index(lsifUploadMetadata(upload)), nil, trace)
 		}
 
 		// Note: this is writing to a different database than the block below, so we need to use a
 		// different transaction context (managed by the writeData function).
-		if err := writeData(ctx, s.lsifstore, upload, repo, isDefaultBranch, groupedBundleData, trace); err != nil {
+		if err := writeData(ctx, s.lsifstore, upload, repo, isDefaultBranch, groupedBundleData, writeRemainingData, trace); err != nil {
 			if isUniqueConstraintViolation(err) {
 				// If this is a unique constraint violation, then we've previously processed this same
 				// upload record up to this point, but failed to perform the transaction below. We can
@@ -259,47 +270,87 @@ func (s *handler) HandleRawUpload(ctx context.Context, logger log.Logger, upload
 			}
 		}
 
-		// Start a nested transaction with Postgres savepoints. In the event that something after this
-		// point fails, we want to update the upload record with an error message but do not want to
-		// alter any other data in the database. Rolling back to this savepoint will allow us to discard
-		// any other changes but still commit the transaction as a whole.
-		return inTransaction(ctx, s.store, func(tx store.Store) error {
-			// Before we mark the upload as complete, we need to delete any existing completed uploads
-			// that have the same repository_id, commit, root, and indexer values. Otherwise the transaction
-			// will fail as these values form a unique constraint.
-			if err := tx.DeleteOverlappingDumps(ctx, upload.RepositoryID, upload.Commit, upload.Root, upload.Indexer); err != nil {
-				return errors.Wrap(err, "store.DeleteOverlappingDumps")
-			}
+		return s.markUploadAsProcessed(ctx, upload, nil, trace, groupedBundleData)
+	})
+}
 
-			trace.Log(otlog.Int("packages", len(groupedBundleData.Packages)))
-			// Update package and package reference data to support cross-repo queries.
-			if err := tx.UpdatePackages(ctx, upload.ID, groupedBundleData.Packages); err != nil {
-				return errors.Wrap(err, "store.UpdatePackages")
-			}
-			trace.Log(otlog.Int("packageReferences", len(groupedBundleData.Packages)))
-			if err := tx.UpdatePackageReferences(ctx, upload.ID, groupedBundleData.PackageReferences); err != nil {
-				return errors.Wrap(err, "store.UpdatePackageReferences")
-			}
+// updateCommittedAt records the commit date of the upload's commit.
+func (s *handler) updateCommittedAt(ctx context.Context, upload codeinteltypes.Upload, trace observation.TraceLogger) error {
+	// Find the commit date for the commit attached to this upload record and insert it into the
+	// database (if not already present). We need to have the commit data of every processed upload
+	// for a repository when calculating the commit graph (triggered at the end of this handler).
 
-			// Insert a companion record to this upload that will asynchronously trigger other workers to
-			// sync/create referenced dependency repositories and queue auto-index records for the monikers
-			// written into the lsif_references table attached by this index processing job.
-			if _, err := tx.InsertDependencySyncingJob(ctx, upload.ID); err != nil {
-				return errors.Wrap(err, "store.InsertDependencyIndexingJob")
-			}
+	_, commitDate, revisionExists, err := s.gitserverClient.CommitDate(ctx, upload.RepositoryID, upload.Commit)
+	if err != nil {
+		return errors.Wrap(err, "gitserverClient.CommitDate")
+	}
+	if !revisionExists {
+		return errCommitDoesNotExist
+	}
+	trace.Log(otlog.String("commitDate", commitDate.String()))
+
+	// We do the update here outside of the transaction started by markUploadAsProcessed to reduce the
+	// long blocking behavior we see when multiple uploads are being processed for the same repository
+	// and commit. We do choose to perform this before that transaction rather than after so that
+	// we can guarantee the presence of the date for this commit by the time the repository is set
+	// as dirty.
+	if err := s.store.UpdateCommittedAt(ctx, upload.RepositoryID, upload.Commit, commitDate.Format(time.RFC3339)); err != nil {
+		return errors.Wrap(err, "store.CommitDate")
+	}
+
+	return nil
+}
+
+// markUploadAsProcessed performs the bookkeeping that makes a processed upload visible once its data has
+// been written to the codeintel database, including writing the package and package reference data of the
+// given grouped bundle data. If a base upload identifier is given, the grouped bundle data covers only the
+// documents that changed since the base upload, and the packages of the base upload are carried forward.
+func (s *handler) markUploadAsProcessed(ctx context.Context, upload codeinteltypes.Upload, baseUploadID *int, trace observation.TraceLogger, groupedBundleData *precise.GroupedBundleDataChans) error {
+	// Start a nested transaction with Postgres savepoints. In the event that something after this
+	// point fails, we want to update the upload record with an error message but do not want to
+	// alter any other data in the database. Rolling back to this savepoint will allow us to discard
+	// any other changes but still commit the transaction as a whole.
+	return inTransaction(ctx, s.store, func(tx store.Store) error {
+		// Before we mark the upload as complete, we need to delete any existing completed uploads
+		// that have the same repository_id, commit, root, and indexer values. Otherwise the transaction
+		// will fail as these values form a unique constraint.
+		if err := tx.DeleteOverlappingDumps(ctx, upload.RepositoryID, upload.Commit, upload.Root, upload.Indexer, upload.Ephemeral); err != nil {
+			return errors.Wrap(err, "store.DeleteOverlappingDumps")
+		}
 
-			// Mark this repository so that the commit updater process will pull the full commit graph from
-			// gitserver and recalculate the nearest upload for each commit as well as which uploads are visible
-			// from the tip of the default branch. We don't do this inside of the transaction as we re-calcalute
-			// the entire set of data from scratch and we want to be able to coalesce requests for the same
-			// repository rather than having a set of uploads for the same repo re-calculate nearly identical
-			// data multiple times.
-			if err := tx.SetRepositoryAsDirty(ctx, upload.RepositoryID); err != nil {
-				return errors.Wrap(err, "store.MarkRepositoryAsDirty")
+		trace.Log(otlog.Int("packages", len(groupedBundleData.Packages)))
+		// Update package and package reference data to support cross-repo queries.
+		if err := tx.UpdatePackages(ctx, upload.ID, groupedBundleData.Packages); err != nil {
+			return errors.Wrap(err, "store.UpdatePackages")
+		}
+		trace.Log(otlog.Int("packageReferences", len(groupedBundleData.Packages)))
+		if err := tx.UpdatePackageReferences(ctx, upload.ID, groupedBundleData.PackageReferences); err != nil {
+			return errors.Wrap(err, "store.UpdatePackageReferences")
+		}
+		if baseUploadID != nil {
+			if err := tx.CopyPackagesFromUpload(ctx, upload.ID, *baseUploadID); err != nil {
+				return errors.Wrap(err, "store.CopyPackagesFromUpload")
 			}
+		}
 
-			return nil
-		})
+		// Insert a companion record to this upload that will asynchronously trigger other workers to
+		// sync/create referenced dependency repositories and queue auto-index records for the monikers
+		// written into the lsif_references table attached by this index processing job.
+		if _, err := tx.InsertDependencySyncingJob(ctx, upload.ID); err != nil {
+			return errors.Wrap(err, "store.InsertDependencyIndexingJob")
+		}
+
+		// Mark this repository so that the commit updater process will pull the full commit graph from
+		// gitserver and recalculate the nearest upload for each commit as well as which uploads are visible
+		// from the tip of the default branch. We don't do this inside of the transaction as we re-calcalute
+		// the entire set of data from scratch and we want to be able to coalesce requests for the same
+		// repository rather than having a set of uploads for the same repo re-calculate nearly identical
+		// data multiple times.
+		if err := tx.SetRepositoryAsDirty(ctx, upload.RepositoryID); err != nil {
+			return errors.Wrap(err, "store.MarkRepositoryAsDirty")
+		}
+
+		return nil
 	})
 }
 
@@ -383,18 +434,25 @@ func withUploadData(ctx context.Context, logger log.Logger, uploadStore uploadst
 	return nil
 }
 
-// writeData transactionally writes the given grouped bundle data into the given LSIF store.
-func writeData(ctx context.Context, lsifStore lsifstore.LsifStore, upload codeinteltypes.Upload, repo *types.Repo, isDefaultBranch bool, groupedBundleData *precise.GroupedBundleDataChans, trace observation.TraceLogger) (err error) {
+// writeData transactionally writes the given grouped bundle data into the given LSIF store. If the given
+// function is non-nil, it is invoked within the same transaction once the grouped bundle data has been
+// written, to write the rest of the upload's data: its SCIP documents and, for incremental uploads, the
+// data of the documents shared with the base upload.
+func writeData(ctx context.Context, lsifStore lsifstore.LsifStore, upload codeinteltypes.Upload, repo *types.Repo, isDefaultBranch bool, groupedBundleData *precise.GroupedBundleDataChans, writeRemainingData func(tx lsifstore.LsifStore) error, trace observation.TraceLogger) (err error) {
 	tx, err := lsifStore.Transact(ctx)
 	if err != nil {
 		return err
 	}
 	defer func() { err = tx.Done(err) }()
 
+	// Exported symbols are gathered from the documents and definitions as they are
+	// written so that they can be added to the index used by precise symbol search.
+	symbols := newSymbolIndexCollector(upload.Root)
+
 	if err := tx.WriteMeta(ctx, upload.ID, groupedBundleData.Meta); err != nil {
 		return errors.Wrap(err, "store.WriteMeta")
 	}
-	count, err := tx.WriteDocuments(ctx, upload.ID, groupedBundleData.Documents)
+	count, err := tx.WriteDocuments(ctx, upload.ID, symbols.observeDocuments(ctx, groupedBundleData.Documents))
 	if err != nil {
 		return errors.Wrap(err, "store.WriteDocuments")
 	}
@@ -406,7 +464,7 @@ func writeData(ctx context.Context, lsifStore lsifstore.LsifStore, upload codein
 	}
 	trace.Log(otlog.Uint32("numResultChunks", count))
 
-	count, err = tx.WriteDefinitions(ctx, upload.ID, groupedBundleData.Definitions)
+	count, err = tx.WriteDefinitions(ctx, upload.ID, symbols.observeDefinitions(ctx, groupedBundleData.Definitions))
 	if err != nil {
 		return errors.Wrap(err, "store.WriteDefinitions")
 	}
@@ -424,6 +482,18 @@ func writeData(ctx context.Context, lsifStore lsifstore.LsifStore, upload codein
 	}
 	trace.Log(otlog.Uint32("numImplementations", count))
 
+	count, err = tx.WriteSymbolIndex(ctx, upload.ID, symbols.indexedSymbols())
+	if err != nil {
+		return errors.Wrap(err, "store.WriteSymbolIndex")
+	}
+	trace.Log(otlog.Uint32("numIndexedSymbols", count))
+
+	if writeRemainingData != nil {
+		if err := writeRemainingData(tx); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
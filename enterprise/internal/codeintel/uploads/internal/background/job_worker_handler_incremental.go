@@ -0,0 +1,174 @@
+package background
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	codeinteltypes "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/pathexistence"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// handleIncrementalUpload converts an incremental upload into a dump. An incremental upload is a SCIP
+// index containing only the documents that changed relative to a completed base upload. Each document
+// in the upload replaces the base upload's document with the same path, and a document without any
+// occurrences or symbols removes it. Every other document of the base upload is shared by reference,
+// so that the resulting dump is a complete index for the upload's own commit.
+//
+// Only the changed documents are correlated. The LSIF data of the unchanged documents is copied from
+// the base upload in the database (see lsifstore.CopyLSIFDataFromUpload), so the cost of processing an
+// incremental upload grows with the size of the change rather than with the size of the base upload.
+func (s *handler) handleIncrementalUpload(
+	ctx context.Context,
+	logger log.Logger,
+	upload codeinteltypes.Upload,
+	repo *types.Repo,
+	isDefaultBranch bool,
+	getChildren pathexistence.GetChildrenFunc,
+	uploadStore uploadstore.Store,
+	trace observation.TraceLogger,
+) error {
+	baseUpload, ok, err := s.store.GetUploadByID(ctx, *upload.BaseUploadID)
+	if err != nil {
+		return errors.Wrap(err, "store.GetUploadByID")
+	}
+	if err := validateBaseUpload(upload, baseUpload, ok); err != nil {
+		return err
+	}
+	trace.Log(otlog.Int("baseUploadID", baseUpload.ID))
+
+	return withUploadData(ctx, logger, uploadStore, upload.ID, trace, func(r io.Reader) error {
+		index, err := readSCIPIndex(r)
+		if err != nil {
+			return err
+		}
+
+		return s.handleSCIPIndex(ctx, logger, upload, repo, isDefaultBranch, getChildren, index, &baseUpload.ID, trace)
+	})
+}
+
+// validateBaseUpload returns an error if the given base upload cannot be patched by the given upload.
+func validateBaseUpload(upload, baseUpload codeinteltypes.Upload, exists bool) error {
+	if !exists || baseUpload.State != "completed" {
+		return errors.Newf("base upload %d is not a completed upload", *upload.BaseUploadID)
+	}
+	if baseUpload.RepositoryID != upload.RepositoryID {
+		return errors.Newf("base upload %d belongs to a different repository", baseUpload.ID)
+	}
+	if baseUpload.Root != upload.Root || baseUpload.Indexer != upload.Indexer {
+		return errors.Newf("base upload %d has a different root or indexer", baseUpload.ID)
+	}
+
+	return nil
+}
+
+// isRemovedDocument returns true if the given document of an incremental upload marks a document of
+// the base upload as removed.
+func isRemovedDocument(document *scip.Document) bool {
+	return len(document.Occurrences) == 0 && len(document.Symbols) == 0
+}
+
+// changedDocumentsIndex returns the index made of the documents of the given incremental index that
+// were not removed since the base upload.
+func changedDocumentsIndex(index *scip.Index) *scip.Index {
+	documents := make([]*scip.Document, 0, len(index.Documents))
+	for _, document := range index.Documents {
+		if !isRemovedDocument(document) {
+			documents = append(documents, document)
+		}
+	}
+
+	return &scip.Index{
+		Metadata:        index.Metadata,
+		Documents:       documents,
+		ExternalSymbols: index.ExternalSymbols,
+	}
+}
+
+// namespaceIdentifiers prefixes the range, result, and document identifiers of the given grouped bundle
+// data with the given upload identifier as they are written. The result chunks of an incremental upload
+// are merged into the result chunks of its base upload, and the unchanged documents of the base upload
+// still refer to the ranges of their changed documents by identifier, so the identifiers assigned while
+// correlating the changed documents must not collide with the identifiers of the base upload.
+func namespaceIdentifiers(ctx context.Context, groupedBundleData *precise.GroupedBundleDataChans, uploadID int) {
+	prefix := strconv.Itoa(uploadID) + ":"
+	namespace := func(id precise.ID) precise.ID {
+		if id == "" {
+			return ""
+		}
+
+		return precise.ID(prefix + string(id))
+	}
+
+	sourceDocuments := groupedBundleData.Documents
+	sourceResultChunks := groupedBundleData.ResultChunks
+
+	documents := make(chan precise.KeyedDocumentData)
+	go func() {
+		defer close(documents)
+
+		for document := range sourceDocuments {
+			ranges := make(map[precise.ID]precise.RangeData, len(document.Document.Ranges))
+			for id, r := range document.Document.Ranges {
+				r.DefinitionResultID = namespace(r.DefinitionResultID)
+				r.ReferenceResultID = namespace(r.ReferenceResultID)
+				r.ImplementationResultID = namespace(r.ImplementationResultID)
+				ranges[namespace(id)] = r
+			}
+			document.Document.Ranges = ranges
+
+			select {
+			case documents <- document:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	resultChunks := make(chan precise.IndexedResultChunkData)
+	go func() {
+		defer close(resultChunks)
+
+		for resultChunk := range sourceResultChunks {
+			documentPaths := make(map[precise.ID]string, len(resultChunk.ResultChunk.DocumentPaths))
+			for id, path := range resultChunk.ResultChunk.DocumentPaths {
+				documentPaths[namespace(id)] = path
+			}
+
+			documentIDRangeIDs := make(map[precise.ID][]precise.DocumentIDRangeID, len(resultChunk.ResultChunk.DocumentIDRangeIDs))
+			for resultID, pairs := range resultChunk.ResultChunk.DocumentIDRangeIDs {
+				namespaced := make([]precise.DocumentIDRangeID, 0, len(pairs))
+				for _, pair := range pairs {
+					namespaced = append(namespaced, precise.DocumentIDRangeID{
+						DocumentID: namespace(pair.DocumentID),
+						RangeID:    namespace(pair.RangeID),
+					})
+				}
+				documentIDRangeIDs[namespace(resultID)] = namespaced
+			}
+
+			resultChunk.ResultChunk = precise.ResultChunkData{
+				DocumentPaths:      documentPaths,
+				DocumentIDRangeIDs: documentIDRangeIDs,
+			}
+
+			select {
+			case resultChunks <- resultChunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	groupedBundleData.Documents = documents
+	groupedBundleData.ResultChunks = resultChunks
+}
@@ -0,0 +1,242 @@
+package background
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"google.golang.org/protobuf/proto"
+
+	codeinteltypes "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/pathexistence"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// handleSCIPIndex converts the given SCIP index into a dump. The index is written to the SCIP tables of
+// the codeintel database, and is converted to LSIF and correlated so that it can be read by the code
+// navigation queries. If a base upload identifier is given, the index contains only the documents that
+// changed since the base upload. Only those documents are correlated, and the data of the remaining
+// documents is copied from the base upload within the same transaction.
+func (s *handler) handleSCIPIndex(
+	ctx context.Context,
+	logger log.Logger,
+	upload codeinteltypes.Upload,
+	repo *types.Repo,
+	isDefaultBranch bool,
+	getChildren pathexistence.GetChildrenFunc,
+	index *scip.Index,
+	baseUploadID *int,
+	trace observation.TraceLogger,
+) error {
+	correlatedIndex := index
+	if baseUploadID != nil {
+		correlatedIndex = changedDocumentsIndex(index)
+	}
+	trace.Log(otlog.Int("numCorrelatedDocuments", len(correlatedIndex.Documents)))
+
+	groupedBundleData, err := correlateSCIPIndex(ctx, correlatedIndex, upload.Root, getChildren)
+	if err != nil {
+		return err
+	}
+	if baseUploadID != nil {
+		namespaceIdentifiers(ctx, groupedBundleData, upload.ID)
+	}
+
+	if err := s.updateCommittedAt(ctx, upload, trace); err != nil {
+		return err
+	}
+
+	writeRemainingData := func(tx lsifstore.LsifStore) error {
+		if baseUploadID != nil {
+			count, err := tx.CopyLSIFDataFromUpload(ctx, upload.ID, *baseUploadID, upload.Root, documentPaths(index))
+			if err != nil {
+				return errors.Wrap(err, "store.CopyLSIFDataFromUpload")
+			}
+			trace.Log(otlog.Uint32("numSharedDocuments", count))
+		}
+
+		return writeSCIPDocuments(ctx, tx, upload.ID, index, baseUploadID, trace)
+	}
+
+	// Note: this is writing to a different database than markUploadAsProcessed, so we need to use a
+	// different transaction context (managed by the writeData function).
+	if err := writeData(ctx, s.lsifstore, upload, repo, isDefaultBranch, groupedBundleData, writeRemainingData, trace); err != nil {
+		if isUniqueConstraintViolation(err) {
+			// See the comment in HandleRawUpload; the SCIP data is written in the same transaction.
+			logger.Warn("SCIP data already exists for upload record")
+			trace.Log(otlog.Bool("rewriting", true))
+		} else {
+			return err
+		}
+	}
+
+	return s.markUploadAsProcessed(ctx, upload, baseUploadID, trace, groupedBundleData)
+}
+
+// detectSCIPIndex returns a reader with the same content as the given reader, along with a flag
+// indicating whether the content is a SCIP index. Raw LSIF uploads are newline-delimited JSON and
+// always begin with an object containing a key. A protobuf-encoded SCIP index may begin with bytes
+// that look like whitespace or an opening brace (field tags and lengths), but never with both an
+// opening brace and a string (or a closing brace) after optional whitespace.
+func detectSCIPIndex(r io.Reader) (io.Reader, bool, error) {
+	br := bufio.NewReader(r)
+	prefix, err := br.Peek(detectSCIPIndexPrefixSize)
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	if len(bytes.TrimSpace(prefix)) == 0 {
+		// Empty uploads are handled (and rejected) by the LSIF conversion
+		return br, false, nil
+	}
+
+	return br, !isJSONObjectPrefix(prefix), nil
+}
+
+const detectSCIPIndexPrefixSize = 512
+
+// isJSONObjectPrefix returns true if the given bytes begin with an empty object or an object key.
+func isJSONObjectPrefix(prefix []byte) bool {
+	prefix = bytes.TrimLeft(prefix, jsonWhitespace)
+	if len(prefix) == 0 || prefix[0] != '{' {
+		return false
+	}
+
+	prefix = bytes.TrimLeft(prefix[1:], jsonWhitespace)
+	return len(prefix) > 0 && (prefix[0] == '"' || prefix[0] == '}')
+}
+
+const jsonWhitespace = " \t\r\n"
+
+// readSCIPIndex reads the SCIP index from the given reader.
+func readSCIPIndex(r io.Reader) (*scip.Index, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "io.ReadAll")
+	}
+
+	var index scip.Index
+	if err := proto.Unmarshal(contents, &index); err != nil {
+		return nil, errors.Wrap(err, "proto.Unmarshal")
+	}
+
+	return &index, nil
+}
+
+// documentPaths returns the paths of the documents in the given index.
+func documentPaths(index *scip.Index) []string {
+	paths := make([]string, 0, len(index.Documents))
+	for _, document := range index.Documents {
+		paths = append(paths, document.RelativePath)
+	}
+
+	return paths
+}
+
+// correlateSCIPIndex converts the given SCIP index into LSIF and correlates it into the grouped bundle
+// data that is written to the LSIF tables read by the code navigation queries.
+func correlateSCIPIndex(ctx context.Context, index *scip.Index, root string, getChildren pathexistence.GetChildrenFunc) (*precise.GroupedBundleDataChans, error) {
+	elements, err := scip.ConvertSCIPToLSIF(index)
+	if err != nil {
+		return nil, errors.Wrap(err, "scip.ConvertSCIPToLSIF")
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		pw.CloseWithError(scip.WriteNDJSON(scip.ElementsToJsonElements(elements), pw))
+	}()
+
+	groupedBundleData, err := conversion.Correlate(ctx, pr, root, getChildren)
+	if err != nil {
+		return nil, errors.Wrap(err, "conversion.Correlate")
+	}
+
+	return groupedBundleData, nil
+}
+
+// writeSCIPDocuments writes the documents of the given SCIP index into the given LSIF store. If a base
+// upload identifier is given, the index contains only the documents that changed since the base upload,
+// and the unchanged documents of the base upload are added by reference.
+func writeSCIPDocuments(ctx context.Context, tx lsifstore.LsifStore, uploadID int, index *scip.Index, baseUploadID *int, trace observation.TraceLogger) error {
+	if err := tx.InsertMetadata(ctx, uploadID, processedMetadata(index.GetMetadata())); err != nil {
+		return errors.Wrap(err, "store.InsertMetadata")
+	}
+
+	symbolWriter, err := tx.NewSymbolWriter(ctx, uploadID)
+	if err != nil {
+		return errors.Wrap(err, "store.NewSymbolWriter")
+	}
+
+	var numDocuments uint32
+	for _, document := range index.Documents {
+		if isRemovedDocument(document) {
+			// Empty documents only carry meaning for incremental uploads, where the
+			// document was removed since the base upload
+			continue
+		}
+
+		payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(codeinteltypes.CanonicalizeDocument(document))
+		if err != nil {
+			return errors.Wrap(err, "proto.Marshal")
+		}
+		hash := sha256.Sum256(payload)
+
+		documentLookupID, err := tx.InsertSCIPDocument(ctx, uploadID, document.RelativePath, hash[:], payload)
+		if err != nil {
+			return errors.Wrap(err, "store.InsertSCIPDocument")
+		}
+		if err := symbolWriter.WriteSCIPSymbols(ctx, documentLookupID, codeinteltypes.ExtractSymbolIndexes(document)); err != nil {
+			return errors.Wrap(err, "symbolWriter.WriteSCIPSymbols")
+		}
+
+		numDocuments++
+	}
+	trace.Log(otlog.Uint32("numSCIPDocuments", numDocuments))
+
+	if baseUploadID != nil {
+		count, err := tx.CopySCIPDocumentsFromUpload(ctx, uploadID, *baseUploadID, documentPaths(index))
+		if err != nil {
+			return errors.Wrap(err, "store.CopySCIPDocumentsFromUpload")
+		}
+		trace.Log(otlog.Uint32("numSharedSCIPDocuments", count))
+	}
+
+	count, err := symbolWriter.Flush(ctx)
+	if err != nil {
+		return errors.Wrap(err, "symbolWriter.Flush")
+	}
+	trace.Log(otlog.Uint32("numSCIPSymbols", count))
+
+	return nil
+}
+
+// lsifUploadMetadata returns the SCIP metadata of the documents derived from the given LSIF upload.
+func lsifUploadMetadata(upload codeinteltypes.Upload) *scip.Metadata {
+	return &scip.Metadata{
+		ToolInfo: &scip.ToolInfo{
+			Name:    upload.Indexer,
+			Version: upload.IndexerVersion,
+		},
+	}
+}
+
+func processedMetadata(metadata *scip.Metadata) lsifstore.ProcessedMetadata {
+	return lsifstore.ProcessedMetadata{
+		TextDocumentEncoding: metadata.GetTextDocumentEncoding().String(),
+		ToolName:             metadata.GetToolInfo().GetName(),
+		ToolVersion:          metadata.GetToolInfo().GetVersion(),
+		ToolArguments:        metadata.GetToolInfo().GetArguments(),
+		ProtocolVersion:      int(metadata.GetVersion()),
+	}
+}
@@ -0,0 +1,316 @@
+package background
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	codeinteltypes "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// scipDocumentCollector derives the SCIP documents of an LSIF upload while its documents and result
+// chunks are written to the codeintel database. The derived documents are written to the SCIP tables
+// once both streams have been consumed, so that code navigation features that read SCIP data (such as
+// call hierarchies) also cover LSIF uploads.
+//
+// LSIF does not name symbols, so each range is given the SCIP symbol used as the identifier of one of
+// its import or export monikers. This is the case for every index converted from SCIP, which covers
+// the indexes produced by the SCIP indexers and uploaded as LSIF. Every other range is given a local
+// symbol shared by the ranges with the same definition (or reference) result.
+//
+// The implementation relationships of SCIP symbols are recovered from the implementation results of
+// the implemented symbols and from implementation monikers, which name implemented symbols that are
+// not defined by the upload.
+type scipDocumentCollector struct {
+	mu                      sync.Mutex
+	documents               map[string][]scipRange
+	definitionResultIDs     map[precise.ID]struct{}
+	definitionRangeIDs      map[precise.ID]struct{}
+	implementationResultIDs map[precise.ID]struct{}
+	implementationRangeIDs  map[precise.ID][]precise.ID
+}
+
+// scipRange is the part of an LSIF range used to derive a SCIP occurrence.
+type scipRange struct {
+	id                     precise.ID
+	rng                    []int32
+	symbol                 string
+	implementationResultID precise.ID
+	implementedSymbols     []string
+}
+
+func newSCIPDocumentCollector() *scipDocumentCollector {
+	return &scipDocumentCollector{
+		documents:               map[string][]scipRange{},
+		definitionResultIDs:     map[precise.ID]struct{}{},
+		definitionRangeIDs:      map[precise.ID]struct{}{},
+		implementationResultIDs: map[precise.ID]struct{}{},
+		implementationRangeIDs:  map[precise.ID][]precise.ID{},
+	}
+}
+
+// observeDocuments returns a channel that yields the same values as the given channel. The ranges
+// of each document are recorded before being passed along.
+func (c *scipDocumentCollector) observeDocuments(ctx context.Context, documents chan precise.KeyedDocumentData) chan precise.KeyedDocumentData {
+	ch := make(chan precise.KeyedDocumentData)
+
+	go func() {
+		defer close(ch)
+
+		for document := range documents {
+			c.addDocument(document.Path, document.Document)
+
+			select {
+			case ch <- document:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// observeResultChunks returns a channel that yields the same values as the given channel. The ranges
+// of each definition and implementation result are recorded before being passed along. This must be
+// called after the channel returned by observeDocuments has been drained.
+func (c *scipDocumentCollector) observeResultChunks(ctx context.Context, resultChunks chan precise.IndexedResultChunkData) chan precise.IndexedResultChunkData {
+	ch := make(chan precise.IndexedResultChunkData)
+
+	go func() {
+		defer close(ch)
+
+		for resultChunk := range resultChunks {
+			c.addResultChunk(resultChunk.ResultChunk)
+
+			select {
+			case ch <- resultChunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// index returns a SCIP index with the given metadata and the documents collected so far.
+func (c *scipDocumentCollector) index(metadata *scip.Metadata) *scip.Index {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	paths := make([]string, 0, len(c.documents))
+	for path := range c.documents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	relationships := c.implementationRelationships()
+
+	documents := make([]*scip.Document, 0, len(paths))
+	for _, path := range paths {
+		documents = append(documents, c.document(path, relationships))
+	}
+
+	return &scip.Index{
+		Metadata:  metadata,
+		Documents: documents,
+	}
+}
+
+func (c *scipDocumentCollector) document(path string, relationships map[string][]*scip.Relationship) *scip.Document {
+	document := &scip.Document{RelativePath: path}
+
+	defined := map[string]struct{}{}
+	for _, r := range c.documents[path] {
+		occurrence := &scip.Occurrence{Range: r.rng, Symbol: r.symbol}
+
+		if _, ok := c.definitionRangeIDs[r.id]; ok {
+			occurrence.SymbolRoles = int32(scip.SymbolRole_Definition)
+
+			if _, ok := defined[r.symbol]; !ok && !scip.IsLocalSymbol(r.symbol) {
+				defined[r.symbol] = struct{}{}
+				document.Symbols = append(document.Symbols, &scip.SymbolInformation{
+					Symbol:        r.symbol,
+					Relationships: relationships[r.symbol],
+				})
+			}
+		}
+
+		document.Occurrences = append(document.Occurrences, occurrence)
+	}
+
+	codeinteltypes.SortOccurrences(document.Occurrences)
+	codeinteltypes.SortSymbols(document.Symbols)
+	return document
+}
+
+// implementationRelationships returns the implementation relationships of the collected global symbols,
+// keyed by the implementing symbol and ordered by the implemented symbol.
+func (c *scipDocumentCollector) implementationRelationships() map[string][]*scip.Relationship {
+	symbols := map[precise.ID]string{}
+	for _, ranges := range c.documents {
+		for _, r := range ranges {
+			symbols[r.id] = r.symbol
+		}
+	}
+
+	implementedSymbols := map[string]map[string]struct{}{}
+	addRelationship := func(symbol, implementedSymbol string) {
+		if symbol == implementedSymbol || scip.IsLocalSymbol(symbol) || scip.IsLocalSymbol(implementedSymbol) {
+			return
+		}
+		if _, ok := implementedSymbols[symbol]; !ok {
+			implementedSymbols[symbol] = map[string]struct{}{}
+		}
+		implementedSymbols[symbol][implementedSymbol] = struct{}{}
+	}
+
+	visitedResultIDs := map[precise.ID]struct{}{}
+	for _, ranges := range c.documents {
+		for _, r := range ranges {
+			for _, implementedSymbol := range r.implementedSymbols {
+				addRelationship(r.symbol, implementedSymbol)
+			}
+
+			if _, ok := visitedResultIDs[r.implementationResultID]; ok || r.implementationResultID == "" {
+				continue
+			}
+			visitedResultIDs[r.implementationResultID] = struct{}{}
+
+			// The implementation result of a symbol holds the definitions of the symbols implementing it
+			for _, rangeID := range c.implementationRangeIDs[r.implementationResultID] {
+				if symbol, ok := symbols[rangeID]; ok {
+					addRelationship(symbol, r.symbol)
+				}
+			}
+		}
+	}
+
+	relationships := make(map[string][]*scip.Relationship, len(implementedSymbols))
+	for symbol, implemented := range implementedSymbols {
+		for implementedSymbol := range implemented {
+			relationships[symbol] = append(relationships[symbol], &scip.Relationship{
+				Symbol:           implementedSymbol,
+				IsImplementation: true,
+			})
+		}
+
+		sort.Slice(relationships[symbol], func(i, j int) bool {
+			return relationships[symbol][i].Symbol < relationships[symbol][j].Symbol
+		})
+	}
+
+	return relationships
+}
+
+func (c *scipDocumentCollector) addDocument(path string, document precise.DocumentData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Monikers are shared by many ranges of a document, so each is checked only once
+	globalSymbols := make(map[precise.ID]bool, len(document.Monikers))
+	isGlobalSymbol := func(monikerID precise.ID) bool {
+		if isGlobal, ok := globalSymbols[monikerID]; ok {
+			return isGlobal
+		}
+
+		isGlobal := isGlobalSCIPSymbol(document.Monikers[monikerID].Identifier)
+		globalSymbols[monikerID] = isGlobal
+		return isGlobal
+	}
+
+	ranges := make([]scipRange, 0, len(document.Ranges))
+	for id, r := range document.Ranges {
+		if r.DefinitionResultID != "" {
+			c.definitionResultIDs[r.DefinitionResultID] = struct{}{}
+		}
+		if r.ImplementationResultID != "" {
+			c.implementationResultIDs[r.ImplementationResultID] = struct{}{}
+		}
+
+		symbol := localSCIPSymbol(r)
+		var implementedSymbols []string
+		for _, monikerID := range r.MonikerIDs {
+			if !isGlobalSymbol(monikerID) {
+				continue
+			}
+
+			switch moniker := document.Monikers[monikerID]; moniker.Kind {
+			case precise.Import, precise.Export:
+				symbol = moniker.Identifier
+			case precise.Implementation:
+				implementedSymbols = append(implementedSymbols, moniker.Identifier)
+			}
+		}
+		if symbol == "" {
+			continue
+		}
+
+		ranges = append(ranges, scipRange{
+			id:                     id,
+			rng:                    scipRangeFromLSIF(r),
+			symbol:                 symbol,
+			implementationResultID: r.ImplementationResultID,
+			implementedSymbols:     implementedSymbols,
+		})
+	}
+
+	c.documents[path] = ranges
+}
+
+func (c *scipDocumentCollector) addResultChunk(resultChunk precise.ResultChunkData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Range identifiers are unique within an upload, not only within a document
+	for resultID, documentIDRangeIDs := range resultChunk.DocumentIDRangeIDs {
+		if _, ok := c.definitionResultIDs[resultID]; ok {
+			for _, documentIDRangeID := range documentIDRangeIDs {
+				c.definitionRangeIDs[documentIDRangeID.RangeID] = struct{}{}
+			}
+		}
+
+		if _, ok := c.implementationResultIDs[resultID]; ok {
+			for _, documentIDRangeID := range documentIDRangeIDs {
+				c.implementationRangeIDs[resultID] = append(c.implementationRangeIDs[resultID], documentIDRangeID.RangeID)
+			}
+		}
+	}
+}
+
+// isGlobalSCIPSymbol returns true if the given moniker identifier is a global SCIP symbol.
+func isGlobalSCIPSymbol(identifier string) bool {
+	if identifier == "" || scip.IsLocalSymbol(identifier) {
+		return false
+	}
+
+	_, err := scip.ParseSymbol(identifier)
+	return err == nil
+}
+
+// localSCIPSymbol returns the local symbol of the given range, which is shared by all ranges with
+// the same definition result, or the same reference result for ranges without a definition result.
+// Result identifiers are unique within an upload, so the two kinds of symbols never collide.
+func localSCIPSymbol(r precise.RangeData) string {
+	if r.DefinitionResultID != "" {
+		return "local " + string(r.DefinitionResultID)
+	}
+	if r.ReferenceResultID != "" {
+		return "local " + string(r.ReferenceResultID)
+	}
+
+	return ""
+}
+
+// scipRangeFromLSIF returns the SCIP encoding of the given LSIF range.
+func scipRangeFromLSIF(r precise.RangeData) []int32 {
+	if r.StartLine == r.EndLine {
+		return []int32{int32(r.StartLine), int32(r.StartCharacter), int32(r.EndCharacter)}
+	}
+
+	return []int32{int32(r.StartLine), int32(r.StartCharacter), int32(r.EndLine), int32(r.EndCharacter)}
+}
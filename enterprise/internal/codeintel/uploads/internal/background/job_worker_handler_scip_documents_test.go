@@ -0,0 +1,188 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestSCIPDocumentCollector(t *testing.T) {
+	ctx := context.Background()
+	collector := newSCIPDocumentCollector()
+
+	symbol := "scip-go gomod example v1 `example`/Widget#Render()."
+
+	documents := make(chan precise.KeyedDocumentData, 2)
+	documents <- precise.KeyedDocumentData{
+		Path: "widget.go",
+		Document: precise.DocumentData{
+			Ranges: map[precise.ID]precise.RangeData{
+				"1": {StartLine: 4, StartCharacter: 17, EndLine: 4, EndCharacter: 23, DefinitionResultID: "10", ReferenceResultID: "11", MonikerIDs: []precise.ID{"12"}},
+				"2": {StartLine: 9, StartCharacter: 1, EndLine: 9, EndCharacter: 7, DefinitionResultID: "10", ReferenceResultID: "11", MonikerIDs: []precise.ID{"12"}},
+				"3": {StartLine: 6, StartCharacter: 1, EndLine: 7, EndCharacter: 2, DefinitionResultID: "13", MonikerIDs: []precise.ID{"14"}},
+				"4": {StartLine: 8, StartCharacter: 1, EndLine: 8, EndCharacter: 2, ReferenceResultID: "15"},
+				"5": {StartLine: 8, StartCharacter: 4, EndLine: 8, EndCharacter: 5},
+			},
+			Monikers: map[precise.ID]precise.MonikerData{
+				"12": {Kind: precise.Export, Scheme: "scip-go", Identifier: symbol},
+				"14": {Kind: precise.Export, Scheme: "gomod", Identifier: "github.com/example/pkg:value"},
+			},
+		},
+	}
+	documents <- precise.KeyedDocumentData{
+		Path: "main.go",
+		Document: precise.DocumentData{
+			Ranges: map[precise.ID]precise.RangeData{
+				"6": {StartLine: 2, StartCharacter: 3, EndLine: 2, EndCharacter: 9, DefinitionResultID: "10", ReferenceResultID: "11", MonikerIDs: []precise.ID{"16"}},
+			},
+			Monikers: map[precise.ID]precise.MonikerData{
+				"16": {Kind: precise.Import, Scheme: "scip-go", Identifier: symbol},
+			},
+		},
+	}
+	close(documents)
+	for range collector.observeDocuments(ctx, documents) {
+	}
+
+	resultChunks := make(chan precise.IndexedResultChunkData, 1)
+	resultChunks <- precise.IndexedResultChunkData{
+		ResultChunk: precise.ResultChunkData{
+			DocumentPaths: map[precise.ID]string{"100": "widget.go", "101": "main.go"},
+			DocumentIDRangeIDs: map[precise.ID][]precise.DocumentIDRangeID{
+				"10": {{DocumentID: "100", RangeID: "1"}},
+				"11": {{DocumentID: "100", RangeID: "1"}, {DocumentID: "100", RangeID: "2"}, {DocumentID: "101", RangeID: "6"}},
+				"13": {{DocumentID: "100", RangeID: "3"}},
+				"15": {{DocumentID: "100", RangeID: "4"}},
+			},
+		},
+	}
+	close(resultChunks)
+	for range collector.observeResultChunks(ctx, resultChunks) {
+	}
+
+	metadata := &scip.Metadata{ToolInfo: &scip.ToolInfo{Name: "lsif-go"}}
+	expectedIndex := &scip.Index{
+		Metadata: metadata,
+		Documents: []*scip.Document{
+			{
+				RelativePath: "main.go",
+				Occurrences: []*scip.Occurrence{
+					{Range: []int32{2, 3, 9}, Symbol: symbol},
+				},
+			},
+			{
+				RelativePath: "widget.go",
+				Occurrences: []*scip.Occurrence{
+					{Range: []int32{4, 17, 23}, Symbol: symbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+					{Range: []int32{6, 1, 7, 2}, Symbol: "local 13", SymbolRoles: int32(scip.SymbolRole_Definition)},
+					{Range: []int32{8, 1, 2}, Symbol: "local 15"},
+					{Range: []int32{9, 1, 7}, Symbol: symbol},
+				},
+				Symbols: []*scip.SymbolInformation{
+					{Symbol: symbol},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedIndex, collector.index(metadata), cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("unexpected index (-want +got):\n%s", diff)
+	}
+}
+
+func TestSCIPDocumentCollectorImplementations(t *testing.T) {
+	ctx := context.Background()
+	collector := newSCIPDocumentCollector()
+
+	var (
+		greeter      = "scip-go gomod example v1 `example`/Greeter#"
+		greet        = "scip-go gomod example v1 `example`/Greeter#Greet()."
+		english      = "scip-go gomod example v1 `example`/English#"
+		englishGreet = "scip-go gomod example v1 `example`/English#Greet()."
+		writer       = "scip-go gomod std v1 `io`/Writer#"
+	)
+
+	documents := make(chan precise.KeyedDocumentData, 2)
+	documents <- precise.KeyedDocumentData{
+		Path: "greeter.go",
+		Document: precise.DocumentData{
+			Ranges: map[precise.ID]precise.RangeData{
+				"1": {StartLine: 2, StartCharacter: 5, EndLine: 2, EndCharacter: 12, DefinitionResultID: "10", ImplementationResultID: "11", MonikerIDs: []precise.ID{"12"}},
+				"2": {StartLine: 3, StartCharacter: 1, EndLine: 3, EndCharacter: 6, DefinitionResultID: "20", ImplementationResultID: "21", MonikerIDs: []precise.ID{"22"}},
+			},
+			Monikers: map[precise.ID]precise.MonikerData{
+				"12": {Kind: precise.Export, Scheme: "scip-go", Identifier: greeter},
+				"22": {Kind: precise.Export, Scheme: "scip-go", Identifier: greet},
+			},
+		},
+	}
+	documents <- precise.KeyedDocumentData{
+		Path: "english.go",
+		Document: precise.DocumentData{
+			Ranges: map[precise.ID]precise.RangeData{
+				"3": {StartLine: 2, StartCharacter: 5, EndLine: 2, EndCharacter: 12, DefinitionResultID: "30", MonikerIDs: []precise.ID{"32", "33"}},
+				"4": {StartLine: 4, StartCharacter: 17, EndLine: 4, EndCharacter: 22, DefinitionResultID: "40", MonikerIDs: []precise.ID{"42"}},
+			},
+			Monikers: map[precise.ID]precise.MonikerData{
+				"32": {Kind: precise.Export, Scheme: "scip-go", Identifier: english},
+				"33": {Kind: precise.Implementation, Scheme: "scip-go", Identifier: writer},
+				"42": {Kind: precise.Export, Scheme: "scip-go", Identifier: englishGreet},
+			},
+		},
+	}
+	close(documents)
+	for range collector.observeDocuments(ctx, documents) {
+	}
+
+	resultChunks := make(chan precise.IndexedResultChunkData, 1)
+	resultChunks <- precise.IndexedResultChunkData{
+		ResultChunk: precise.ResultChunkData{
+			DocumentPaths: map[precise.ID]string{"100": "greeter.go", "101": "english.go"},
+			DocumentIDRangeIDs: map[precise.ID][]precise.DocumentIDRangeID{
+				"10": {{DocumentID: "100", RangeID: "1"}},
+				"20": {{DocumentID: "100", RangeID: "2"}},
+				"30": {{DocumentID: "101", RangeID: "3"}},
+				"40": {{DocumentID: "101", RangeID: "4"}},
+				"11": {{DocumentID: "101", RangeID: "3"}},
+				"21": {{DocumentID: "101", RangeID: "4"}},
+			},
+		},
+	}
+	close(resultChunks)
+	for range collector.observeResultChunks(ctx, resultChunks) {
+	}
+
+	symbols := map[string][]*scip.SymbolInformation{}
+	for _, document := range collector.index(nil).Documents {
+		symbols[document.RelativePath] = document.Symbols
+	}
+
+	expectedSymbols := map[string][]*scip.SymbolInformation{
+		"english.go": {
+			{
+				Symbol: english,
+				Relationships: []*scip.Relationship{
+					{Symbol: greeter, IsImplementation: true},
+					{Symbol: writer, IsImplementation: true},
+				},
+			},
+			{
+				Symbol: englishGreet,
+				Relationships: []*scip.Relationship{
+					{Symbol: greet, IsImplementation: true},
+				},
+			},
+		},
+		"greeter.go": {
+			{Symbol: greeter},
+			{Symbol: greet},
+		},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols, cmp.Comparer(proto.Equal)); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,649 @@
+package background
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"google.golang.org/protobuf/proto"
+
+	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
+	codeinteltypes "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	uploadstoremocks "github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestHandleSCIPUpload(t *testing.T) {
+	upload := codeinteltypes.Upload{
+		ID:           42,
+		Root:         "root/",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "scip-go",
+	}
+
+	mockWorkerStore := NewMockWorkerStore[codeinteltypes.Upload]()
+	mockDBStore := NewMockStore()
+	mockRepoStore := NewMockRepoStore()
+	mockLSIFStore := NewMockLsifStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	gitserverClient := NewMockGitserverClient()
+	symbolWriter := &testSymbolWriter{}
+	documentPaths := captureDocumentPaths(mockLSIFStore)
+
+	// Set default transaction behavior
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.TransactFunc.SetDefaultReturn(mockLSIFStore, nil)
+	mockLSIFStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.NewSymbolWriterFunc.SetDefaultReturn(symbolWriter, nil)
+	mockLSIFStore.InsertSCIPDocumentFunc.SetDefaultReturn(1001, nil)
+
+	// Give the handler a complete SCIP index
+	mockUploadStore.GetFunc.SetDefaultHook(testSCIPIndex(t, &scip.Index{
+		Metadata: testSCIPMetadata(),
+		Documents: []*scip.Document{
+			testSCIPDocument("main.go", "Main", 1),
+			testSCIPDocument("util.go", "Util", 2),
+		},
+	}))
+
+	// Allowlist all files in the index
+	gitserverClient.DirectoryChildrenFunc.SetDefaultHook(testDirectoryChildren("root/main.go", "root/util.go"))
+	gitserverClient.CommitDateFunc.SetDefaultReturn("deadbeef", time.Unix(1587396557, 0).UTC(), true, nil)
+
+	svc := &handler{
+		store:           mockDBStore,
+		lsifstore:       mockLSIFStore,
+		gitserverClient: gitserverClient,
+		repoStore:       mockRepoStore,
+		workerStore:     mockWorkerStore,
+	}
+
+	requeued, err := svc.HandleRawUpload(context.Background(), logtest.Scoped(t), upload, mockUploadStore, observation.TestTraceLogger(logtest.Scoped(t)))
+	if err != nil {
+		t.Fatalf("unexpected error handling upload: %s", err)
+	} else if requeued {
+		t.Errorf("unexpected requeue")
+	}
+
+	if calls := mockLSIFStore.InsertMetadataFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of InsertMetadata calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 || calls[0].Arg2.ToolName != "scip-go" || calls[0].Arg2.TextDocumentEncoding != "UTF8" {
+		t.Errorf("unexpected InsertMetadata args. want=%d,%s,%s have=%d,%s,%s", 42, "scip-go", "UTF8", calls[0].Arg1, calls[0].Arg2.ToolName, calls[0].Arg2.TextDocumentEncoding)
+	}
+
+	var insertedPaths []string
+	for _, call := range mockLSIFStore.InsertSCIPDocumentFunc.History() {
+		insertedPaths = append(insertedPaths, call.Arg2)
+	}
+	if diff := cmp.Diff([]string{"main.go", "util.go"}, insertedPaths); diff != "" {
+		t.Errorf("unexpected SCIP documents (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"scip-go gomod example v1 `example`/Main().", "scip-go gomod example v1 `example`/Util()."}, symbolWriter.symbolNames()); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+	if !symbolWriter.flushed {
+		t.Errorf("expected symbol writer to be flushed")
+	}
+	if len(mockLSIFStore.CopyLSIFDataFromUploadFunc.History()) != 0 {
+		t.Errorf("unexpected number of CopyLSIFDataFromUpload calls. want=%d have=%d", 0, len(mockLSIFStore.CopyLSIFDataFromUploadFunc.History()))
+	}
+	if len(mockLSIFStore.CopySCIPDocumentsFromUploadFunc.History()) != 0 {
+		t.Errorf("unexpected number of CopySCIPDocumentsFromUpload calls. want=%d have=%d", 0, len(mockLSIFStore.CopySCIPDocumentsFromUploadFunc.History()))
+	}
+
+	// The index is also converted into LSIF data read by code navigation
+	if diff := cmp.Diff([]string{"main.go", "util.go"}, documentPaths()); diff != "" {
+		t.Errorf("unexpected LSIF documents (-want +got):\n%s", diff)
+	}
+
+	if calls := mockDBStore.UpdatePackagesFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of UpdatePackages calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 {
+		t.Errorf("unexpected UpdatePackages upload id. want=%d have=%d", 42, calls[0].Arg1)
+	}
+	if len(mockDBStore.CopyPackagesFromUploadFunc.History()) != 0 {
+		t.Errorf("unexpected number of CopyPackagesFromUpload calls. want=%d have=%d", 0, len(mockDBStore.CopyPackagesFromUploadFunc.History()))
+	}
+	if len(mockDBStore.DeleteOverlappingDumpsFunc.History()) != 1 {
+		t.Errorf("unexpected number of DeleteOverlappingDumps calls. want=%d have=%d", 1, len(mockDBStore.DeleteOverlappingDumpsFunc.History()))
+	}
+	if len(mockUploadStore.DeleteFunc.History()) != 1 {
+		t.Errorf("unexpected number of Delete calls. want=%d have=%d", 1, len(mockUploadStore.DeleteFunc.History()))
+	}
+}
+
+func TestHandleIncrementalUpload(t *testing.T) {
+	baseUploadID := 41
+	upload := codeinteltypes.Upload{
+		ID:           42,
+		Root:         "root/",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "scip-go",
+		BaseUploadID: &baseUploadID,
+	}
+
+	mockWorkerStore := NewMockWorkerStore[codeinteltypes.Upload]()
+	mockDBStore := NewMockStore()
+	mockRepoStore := NewMockRepoStore()
+	mockLSIFStore := NewMockLsifStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	gitserverClient := NewMockGitserverClient()
+	symbolWriter := &testSymbolWriter{}
+	documentPaths := captureDocumentPaths(mockLSIFStore)
+
+	var rangeIDs []precise.ID
+	mockLSIFStore.WriteResultChunksFunc.SetDefaultHook(func(ctx context.Context, bundleID int, resultChunks chan precise.IndexedResultChunkData) (uint32, error) {
+		for resultChunk := range resultChunks {
+			for _, documentIDRangeIDs := range resultChunk.ResultChunk.DocumentIDRangeIDs {
+				for _, documentIDRangeID := range documentIDRangeIDs {
+					rangeIDs = append(rangeIDs, documentIDRangeID.RangeID)
+				}
+			}
+		}
+		return 0, nil
+	})
+
+	// Set default transaction behavior
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.TransactFunc.SetDefaultReturn(mockLSIFStore, nil)
+	mockLSIFStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.NewSymbolWriterFunc.SetDefaultReturn(symbolWriter, nil)
+	mockLSIFStore.InsertSCIPDocumentFunc.SetDefaultReturn(1001, nil)
+	mockLSIFStore.CopySCIPDocumentsFromUploadFunc.SetDefaultReturn(1, nil)
+	mockLSIFStore.CopyLSIFDataFromUploadFunc.SetDefaultReturn(1, nil)
+
+	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(codeinteltypes.Upload{
+		ID:           41,
+		Root:         "root/",
+		Commit:       "cafebabe",
+		RepositoryID: 50,
+		Indexer:      "scip-go",
+		State:        "completed",
+	}, true, nil)
+
+	// Give the handler a partial index with one changed and one removed document
+	mockUploadStore.GetFunc.SetDefaultHook(testSCIPIndex(t, &scip.Index{
+		Metadata: testSCIPMetadata(),
+		Documents: []*scip.Document{
+			testSCIPDocument("changed.go", "Changed", 1),
+			{RelativePath: "removed.go"},
+		},
+	}))
+
+	// Allowlist all files at the upload's commit
+	gitserverClient.DirectoryChildrenFunc.SetDefaultHook(testDirectoryChildren("root/changed.go", "root/unchanged.go"))
+	gitserverClient.CommitDateFunc.SetDefaultReturn("deadbeef", time.Unix(1587396557, 0).UTC(), true, nil)
+
+	svc := &handler{
+		store:           mockDBStore,
+		lsifstore:       mockLSIFStore,
+		gitserverClient: gitserverClient,
+		repoStore:       mockRepoStore,
+		workerStore:     mockWorkerStore,
+	}
+
+	requeued, err := svc.HandleRawUpload(context.Background(), logtest.Scoped(t), upload, mockUploadStore, observation.TestTraceLogger(logtest.Scoped(t)))
+	if err != nil {
+		t.Fatalf("unexpected error handling upload: %s", err)
+	} else if requeued {
+		t.Errorf("unexpected requeue")
+	}
+
+	// Only the changed documents are correlated, and the remaining LSIF data is copied from the base upload
+	if diff := cmp.Diff([]string{"changed.go"}, documentPaths()); diff != "" {
+		t.Errorf("unexpected LSIF documents (-want +got):\n%s", diff)
+	}
+	if len(rangeIDs) == 0 {
+		t.Errorf("expected result chunks")
+	}
+	for _, rangeID := range rangeIDs {
+		if !strings.HasPrefix(string(rangeID), "42:") {
+			t.Errorf("unexpected range id %q. want prefix %q", rangeID, "42:")
+		}
+	}
+	if calls := mockLSIFStore.CopyLSIFDataFromUploadFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of CopyLSIFDataFromUpload calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 || calls[0].Arg2 != 41 || calls[0].Arg3 != "root/" {
+		t.Errorf("unexpected CopyLSIFDataFromUpload args. want=%d,%d,%s have=%d,%d,%s", 42, 41, "root/", calls[0].Arg1, calls[0].Arg2, calls[0].Arg3)
+	} else if diff := cmp.Diff([]string{"changed.go", "removed.go"}, calls[0].Arg4); diff != "" {
+		t.Errorf("unexpected excluded paths (-want +got):\n%s", diff)
+	}
+
+	if calls := mockLSIFStore.InsertMetadataFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of InsertMetadata calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg2.ToolName != "scip-go" {
+		t.Errorf("unexpected tool name. want=%s have=%s", "scip-go", calls[0].Arg2.ToolName)
+	}
+
+	if calls := mockLSIFStore.InsertSCIPDocumentFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of InsertSCIPDocument calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 || calls[0].Arg2 != "changed.go" {
+		t.Errorf("unexpected InsertSCIPDocument args. want=%d,%s have=%d,%s", 42, "changed.go", calls[0].Arg1, calls[0].Arg2)
+	} else if len(calls[0].Arg3) == 0 {
+		t.Errorf("expected document hash")
+	}
+
+	if calls := mockLSIFStore.CopySCIPDocumentsFromUploadFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of CopySCIPDocumentsFromUpload calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 || calls[0].Arg2 != 41 {
+		t.Errorf("unexpected CopySCIPDocumentsFromUpload ids. want=%d,%d have=%d,%d", 42, 41, calls[0].Arg1, calls[0].Arg2)
+	} else if diff := cmp.Diff([]string{"changed.go", "removed.go"}, calls[0].Arg3); diff != "" {
+		t.Errorf("unexpected excluded paths (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"scip-go gomod example v1 `example`/Changed()."}, symbolWriter.symbolNames()); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+	if !symbolWriter.flushed {
+		t.Errorf("expected symbol writer to be flushed")
+	}
+
+	if calls := mockDBStore.UpdatePackagesFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of UpdatePackages calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 {
+		t.Errorf("unexpected UpdatePackages upload id. want=%d have=%d", 42, calls[0].Arg1)
+	}
+	if calls := mockDBStore.CopyPackagesFromUploadFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of CopyPackagesFromUpload calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 || calls[0].Arg2 != 41 {
+		t.Errorf("unexpected CopyPackagesFromUpload ids. want=%d,%d have=%d,%d", 42, 41, calls[0].Arg1, calls[0].Arg2)
+	}
+
+	if len(mockDBStore.DeleteOverlappingDumpsFunc.History()) != 1 {
+		t.Errorf("unexpected number of DeleteOverlappingDumps calls. want=%d have=%d", 1, len(mockDBStore.DeleteOverlappingDumpsFunc.History()))
+	}
+	if len(mockDBStore.SetRepositoryAsDirtyFunc.History()) != 1 {
+		t.Errorf("unexpected number of MarkRepositoryAsDirty calls. want=%d have=%d", 1, len(mockDBStore.SetRepositoryAsDirtyFunc.History()))
+	}
+	if len(mockUploadStore.DeleteFunc.History()) != 1 {
+		t.Errorf("unexpected number of Delete calls. want=%d have=%d", 1, len(mockUploadStore.DeleteFunc.History()))
+	}
+}
+
+func TestHandleIncrementalUploadBaseNotLSIF(t *testing.T) {
+	baseUploadID := 41
+	upload := codeinteltypes.Upload{
+		ID:           42,
+		Root:         "root/",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "scip-go",
+		BaseUploadID: &baseUploadID,
+	}
+
+	mockDBStore := NewMockStore()
+	mockLSIFStore := NewMockLsifStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	gitserverClient := NewMockGitserverClient()
+	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(codeinteltypes.Upload{ID: 41, Root: "root/", RepositoryID: 50, Indexer: "scip-go", State: "completed"}, true, nil)
+	mockLSIFStore.TransactFunc.SetDefaultReturn(mockLSIFStore, nil)
+	mockLSIFStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.CopyLSIFDataFromUploadFunc.SetDefaultReturn(0, lsifstore.ErrBaseUploadNotLSIF)
+	gitserverClient.DirectoryChildrenFunc.SetDefaultHook(testDirectoryChildren("root/changed.go"))
+	gitserverClient.CommitDateFunc.SetDefaultReturn("deadbeef", time.Unix(1587396557, 0).UTC(), true, nil)
+	mockUploadStore.GetFunc.SetDefaultHook(testSCIPIndex(t, &scip.Index{
+		Metadata:  testSCIPMetadata(),
+		Documents: []*scip.Document{testSCIPDocument("changed.go", "Changed", 1)},
+	}))
+
+	svc := &handler{
+		store:           mockDBStore,
+		lsifstore:       mockLSIFStore,
+		gitserverClient: gitserverClient,
+		repoStore:       NewMockRepoStore(),
+		workerStore:     NewMockWorkerStore[codeinteltypes.Upload](),
+	}
+
+	if _, err := svc.HandleRawUpload(context.Background(), logtest.Scoped(t), upload, mockUploadStore, observation.TestTraceLogger(logtest.Scoped(t))); err == nil {
+		t.Fatalf("expected error handling upload")
+	} else if !strings.Contains(err.Error(), lsifstore.ErrBaseUploadNotLSIF.Error()) {
+		t.Fatalf("unexpected error. want=%q have=%q", lsifstore.ErrBaseUploadNotLSIF, err)
+	}
+
+	if len(mockLSIFStore.InsertSCIPDocumentFunc.History()) != 0 {
+		t.Errorf("unexpected number of InsertSCIPDocument calls. want=%d have=%d", 0, len(mockLSIFStore.InsertSCIPDocumentFunc.History()))
+	}
+	if len(mockDBStore.DeleteOverlappingDumpsFunc.History()) != 0 {
+		t.Errorf("unexpected number of DeleteOverlappingDumps calls. want=%d have=%d", 0, len(mockDBStore.DeleteOverlappingDumpsFunc.History()))
+	}
+}
+
+func TestHandleIncrementalUploadInvalidBase(t *testing.T) {
+	baseUploadID := 41
+	upload := codeinteltypes.Upload{
+		ID:           42,
+		Root:         "root/",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "scip-go",
+		BaseUploadID: &baseUploadID,
+	}
+
+	testCases := map[string]codeinteltypes.Upload{
+		"processing":       {ID: 41, Root: "root/", RepositoryID: 50, Indexer: "scip-go", State: "processing"},
+		"other repository": {ID: 41, Root: "root/", RepositoryID: 51, Indexer: "scip-go", State: "completed"},
+		"other root":       {ID: 41, Root: "other/", RepositoryID: 50, Indexer: "scip-go", State: "completed"},
+		"other indexer":    {ID: 41, Root: "root/", RepositoryID: 50, Indexer: "scip-java", State: "completed"},
+	}
+
+	for name, baseUpload := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockDBStore := NewMockStore()
+			mockLSIFStore := NewMockLsifStore()
+			mockUploadStore := uploadstoremocks.NewMockStore()
+			mockDBStore.GetUploadByIDFunc.SetDefaultReturn(baseUpload, true, nil)
+
+			svc := &handler{
+				store:           mockDBStore,
+				lsifstore:       mockLSIFStore,
+				gitserverClient: NewMockGitserverClient(),
+				repoStore:       NewMockRepoStore(),
+				workerStore:     NewMockWorkerStore[codeinteltypes.Upload](),
+			}
+
+			if _, err := svc.HandleRawUpload(context.Background(), logtest.Scoped(t), upload, mockUploadStore, observation.TestTraceLogger(logtest.Scoped(t))); err == nil {
+				t.Fatalf("expected error handling upload")
+			}
+
+			if len(mockUploadStore.GetFunc.History()) != 0 {
+				t.Errorf("unexpected number of Get calls. want=%d have=%d", 0, len(mockUploadStore.GetFunc.History()))
+			}
+			if len(mockLSIFStore.TransactFunc.History()) != 0 {
+				t.Errorf("unexpected number of Transact calls. want=%d have=%d", 0, len(mockLSIFStore.TransactFunc.History()))
+			}
+		})
+	}
+}
+
+func TestHandleIncrementalUploadAfterSCIPUpload(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	lsifStore := lsifstore.New(codeIntelDB, &observation.TestContext)
+	ctx := context.Background()
+
+	baseUploadID := 1
+	baseUpload := codeinteltypes.Upload{ID: 1, Root: "root/", Commit: "cafebabe", RepositoryID: 50, Indexer: "scip-go", State: "completed"}
+	upload := codeinteltypes.Upload{ID: 2, Root: "root/", Commit: "deadbeef", RepositoryID: 50, Indexer: "scip-go", BaseUploadID: &baseUploadID}
+
+	mockDBStore := NewMockStore()
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(baseUpload, true, nil)
+
+	gitserverClient := NewMockGitserverClient()
+	gitserverClient.CommitDateFunc.SetDefaultReturn("deadbeef", time.Unix(1587396557, 0).UTC(), true, nil)
+	gitserverClient.DirectoryChildrenFunc.SetDefaultHook(testDirectoryChildren("root/changed.go", "root/removed.go", "root/unchanged.go"))
+
+	svc := &handler{
+		store:           mockDBStore,
+		lsifstore:       lsifStore,
+		gitserverClient: gitserverClient,
+		repoStore:       NewMockRepoStore(),
+		workerStore:     NewMockWorkerStore[codeinteltypes.Upload](),
+	}
+
+	indexes := map[int]*scip.Index{
+		baseUpload.ID: {
+			Metadata: testSCIPMetadata(),
+			Documents: []*scip.Document{
+				testSCIPDocument("changed.go", "Original", 1),
+				testSCIPDocument("removed.go", "Removed", 2),
+				testSCIPDocument("unchanged.go", "Unchanged", 3),
+			},
+		},
+		upload.ID: {
+			Metadata: testSCIPMetadata(),
+			Documents: []*scip.Document{
+				testSCIPDocument("changed.go", "Changed", 5),
+				{RelativePath: "removed.go"},
+			},
+		},
+	}
+
+	for _, u := range []codeinteltypes.Upload{baseUpload, upload} {
+		mockUploadStore := uploadstoremocks.NewMockStore()
+		mockUploadStore.GetFunc.SetDefaultHook(testSCIPIndex(t, indexes[u.ID]))
+
+		if _, err := svc.HandleRawUpload(ctx, logger, u, mockUploadStore, observation.TestTraceLogger(logger)); err != nil {
+			t.Fatalf("unexpected error handling upload %d: %s", u.ID, err)
+		}
+	}
+
+	// The SCIP data of the incremental upload contains the changed and unchanged documents
+	payloads, err := basestore.ScanStrings(codeIntelDB.QueryContext(ctx, `
+		SELECT encode(sd.raw_scip_payload, 'base64')
+		FROM codeintel_scip_document_lookup dl
+		JOIN codeintel_scip_documents sd ON sd.id = dl.document_id
+		WHERE dl.upload_id = $1
+	`, upload.ID))
+	if err != nil {
+		t.Fatalf("unexpected error getting SCIP documents: %s", err)
+	}
+	scipSymbols := map[string][]string{}
+	for _, payload := range payloads {
+		raw, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			t.Fatalf("unexpected error decoding SCIP document: %s", err)
+		}
+		var document scip.Document
+		if err := proto.Unmarshal(raw, &document); err != nil {
+			t.Fatalf("unexpected error unmarshalling SCIP document: %s", err)
+		}
+		for _, occurrence := range document.Occurrences {
+			scipSymbols[document.RelativePath] = append(scipSymbols[document.RelativePath], occurrence.Symbol)
+		}
+	}
+	expectedSCIPSymbols := map[string][]string{
+		"changed.go":   {"scip-go gomod example v1 `example`/Changed()."},
+		"unchanged.go": {"scip-go gomod example v1 `example`/Unchanged()."},
+	}
+	if diff := cmp.Diff(expectedSCIPSymbols, scipSymbols); diff != "" {
+		t.Errorf("unexpected SCIP documents (-want +got):\n%s", diff)
+	}
+
+	// The LSIF data read by code navigation is complete for both uploads
+	expectedLines := map[int]map[string][]int{
+		baseUpload.ID: {"changed.go": {1}, "removed.go": {2}, "unchanged.go": {3}},
+		upload.ID:     {"changed.go": {5}, "unchanged.go": {3}},
+	}
+	for uploadID, expected := range expectedLines {
+		lines := map[string][]int{}
+		if err := lsifStore.ScanDocuments(ctx, uploadID, func(path string, ranges map[precise.ID]precise.RangeData) error {
+			for _, r := range ranges {
+				lines[path] = append(lines[path], r.StartLine)
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("unexpected error scanning documents of upload %d: %s", uploadID, err)
+		}
+
+		if diff := cmp.Diff(expected, lines); diff != "" {
+			t.Errorf("unexpected LSIF documents of upload %d (-want +got):\n%s", uploadID, diff)
+		}
+	}
+
+	// The definitions of the base upload within the changed and removed documents are replaced
+	identifiers, err := basestore.ScanStrings(codeIntelDB.QueryContext(ctx, `SELECT identifier FROM lsif_data_definitions WHERE dump_id = $1 ORDER BY identifier`, upload.ID))
+	if err != nil {
+		t.Fatalf("unexpected error getting definitions: %s", err)
+	}
+	expectedIdentifiers := []string{
+		"scip-go gomod example v1 `example`/Changed().",
+		"scip-go gomod example v1 `example`/Unchanged().",
+	}
+	if diff := cmp.Diff(expectedIdentifiers, identifiers); diff != "" {
+		t.Errorf("unexpected definition identifiers (-want +got):\n%s", diff)
+	}
+
+	if calls := mockDBStore.UpdatePackagesFunc.History(); len(calls) != 2 {
+		t.Errorf("unexpected number of UpdatePackages calls. want=%d have=%d", 2, len(calls))
+	}
+	if calls := mockDBStore.CopyPackagesFromUploadFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of CopyPackagesFromUpload calls. want=%d have=%d", 1, len(calls))
+	}
+}
+
+func TestDetectSCIPIndex(t *testing.T) {
+	payload, err := proto.Marshal(&scip.Index{Metadata: testSCIPMetadata()})
+	if err != nil {
+		t.Fatalf("unexpected error marshalling index: %s", err)
+	}
+
+	testCases := map[string]struct {
+		contents []byte
+		isSCIP   bool
+	}{
+		"lsif":               {[]byte(`{"id": 1, "type": "vertex", "label": "metaData"}`), false},
+		"lsif leading space": {[]byte("\n  {\"id\": 1}"), false},
+		"scip":               {payload, true},
+		"empty":              {nil, false},
+		"whitespace only":    {[]byte(" \n"), false},
+		"scip brace length":  {append([]byte{0x0a, '{', 0x20, 0x01}, payload...), true},
+		"scip space length":  {append([]byte{0x0a, ' ', '{', 0x08}, payload...), true},
+		"lsif empty object":  {[]byte("{}"), false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r, isSCIP, err := detectSCIPIndex(bytes.NewReader(testCase.contents))
+			if err != nil {
+				t.Fatalf("unexpected error detecting SCIP index: %s", err)
+			}
+			if isSCIP != testCase.isSCIP {
+				t.Errorf("unexpected result. want=%v have=%v", testCase.isSCIP, isSCIP)
+			}
+
+			contents, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("unexpected error reading contents: %s", err)
+			}
+			if !bytes.Equal(contents, testCase.contents) {
+				t.Errorf("unexpected contents. want=%q have=%q", testCase.contents, contents)
+			}
+		})
+	}
+}
+
+//
+//
+
+func testSCIPMetadata() *scip.Metadata {
+	return &scip.Metadata{
+		ToolInfo:             &scip.ToolInfo{Name: "scip-go", Version: "0.1.0"},
+		ProjectRoot:          "file:///sourcegraph/root",
+		TextDocumentEncoding: scip.TextEncoding_UTF8,
+	}
+}
+
+// testSCIPDocument returns a document with a single function defined on the given line.
+func testSCIPDocument(path, name string, line int32) *scip.Document {
+	symbol := "scip-go gomod example v1 `example`/" + name + "()."
+
+	return &scip.Document{
+		RelativePath: path,
+		Occurrences: []*scip.Occurrence{
+			{Range: []int32{line, 5, 5 + int32(len(name))}, Symbol: symbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		},
+		Symbols: []*scip.SymbolInformation{
+			{Symbol: symbol},
+		},
+	}
+}
+
+func testSCIPIndex(t *testing.T, index *scip.Index) func(ctx context.Context, key string) (io.ReadCloser, error) {
+	payload, err := proto.Marshal(index)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling index: %s", err)
+	}
+
+	return func(ctx context.Context, key string) (io.ReadCloser, error) {
+		var buf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buf)
+		if _, err := gzipWriter.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(&buf), nil
+	}
+}
+
+// testDirectoryChildren returns a gitserver DirectoryChildren hook for a commit containing the given paths.
+func testDirectoryChildren(paths ...string) func(ctx context.Context, repositoryID int, commit string, dirnames []string) (map[string][]string, error) {
+	return func(ctx context.Context, repositoryID int, commit string, dirnames []string) (map[string][]string, error) {
+		children := map[string][]string{}
+		for _, dirname := range dirnames {
+			for _, path := range paths {
+				if dir := filepath.Dir(path); dir == dirname || (dir == "." && dirname == "") {
+					children[dirname] = append(children[dirname], path)
+				}
+			}
+		}
+
+		return children, nil
+	}
+}
+
+// captureDocumentPaths drains the documents written to the given LSIF store and returns a function
+// that returns the sorted paths of the written documents.
+func captureDocumentPaths(mockLSIFStore *MockLsifStore) func() []string {
+	var paths []string
+	mockLSIFStore.WriteDocumentsFunc.SetDefaultHook(func(ctx context.Context, bundleID int, documents chan precise.KeyedDocumentData) (uint32, error) {
+		for document := range documents {
+			paths = append(paths, document.Path)
+		}
+		return uint32(len(paths)), nil
+	})
+
+	return func() []string {
+		sort.Strings(paths)
+		return paths
+	}
+}
+
+type testSymbolWriter struct {
+	symbols []codeinteltypes.InvertedRangeIndex
+	flushed bool
+}
+
+var _ lsifstore.SymbolWriter = &testSymbolWriter{}
+
+func (w *testSymbolWriter) WriteSCIPSymbols(ctx context.Context, documentLookupID int, symbols []codeinteltypes.InvertedRangeIndex) error {
+	w.symbols = append(w.symbols, symbols...)
+	return nil
+}
+
+func (w *testSymbolWriter) Flush(ctx context.Context) (uint32, error) {
+	w.flushed = true
+	return uint32(len(w.symbols)), nil
+}
+
+func (w *testSymbolWriter) symbolNames() []string {
+	names := make([]string, 0, len(w.symbols))
+	for _, symbol := range w.symbols {
+		names = append(names, symbol.SymbolName)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,201 @@
+package background
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// symbolIndexCollector gathers the exported symbols defined by an upload while its documents
+// and definitions are written to the codeintel database. The collected symbols are written to
+// the symbol index used by precise symbol search once both streams have been consumed.
+type symbolIndexCollector struct {
+	root     string
+	mu       sync.Mutex
+	metadata map[symbolKey]symbolMetadata
+	symbols  []lsifstore.IndexedSymbol
+}
+
+type symbolKey struct {
+	scheme     string
+	identifier string
+}
+
+type symbolMetadata struct {
+	documentation  string
+	packageManager string
+	packageName    string
+	packageVersion string
+}
+
+func newSymbolIndexCollector(root string) *symbolIndexCollector {
+	return &symbolIndexCollector{
+		root:     root,
+		metadata: map[symbolKey]symbolMetadata{},
+	}
+}
+
+// observeDocuments returns a channel that yields the same values as the given channel. Each
+// document is inspected for the hover text and package information of its exported monikers
+// before being passed along.
+func (c *symbolIndexCollector) observeDocuments(ctx context.Context, documents chan precise.KeyedDocumentData) chan precise.KeyedDocumentData {
+	ch := make(chan precise.KeyedDocumentData)
+
+	go func() {
+		defer close(ch)
+
+		for document := range documents {
+			c.addDocument(document.Document)
+
+			select {
+			case ch <- document:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// observeDefinitions returns a channel that yields the same values as the given channel. Each
+// definition location of an exported moniker is recorded as an indexed symbol before being
+// passed along. This must be called after the channel returned by observeDocuments has been
+// drained.
+func (c *symbolIndexCollector) observeDefinitions(ctx context.Context, monikerLocations chan precise.MonikerLocations) chan precise.MonikerLocations {
+	ch := make(chan precise.MonikerLocations)
+
+	go func() {
+		defer close(ch)
+
+		for v := range monikerLocations {
+			c.addDefinitions(v)
+
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// indexedSymbols returns a channel that yields the symbols collected so far.
+func (c *symbolIndexCollector) indexedSymbols() chan lsifstore.IndexedSymbol {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan lsifstore.IndexedSymbol, len(c.symbols))
+	for _, symbol := range c.symbols {
+		ch <- symbol
+	}
+	close(ch)
+
+	return ch
+}
+
+func (c *symbolIndexCollector) addDocument(document precise.DocumentData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, r := range document.Ranges {
+		for _, monikerID := range r.MonikerIDs {
+			moniker, ok := document.Monikers[monikerID]
+			if !ok || moniker.Kind != precise.Export {
+				continue
+			}
+
+			key := symbolKey{scheme: moniker.Scheme, identifier: moniker.Identifier}
+			if existing, ok := c.metadata[key]; ok && existing.documentation != "" {
+				continue
+			}
+
+			metadata := symbolMetadata{documentation: document.HoverResults[r.HoverResultID]}
+			if packageInformation, ok := document.PackageInformation[moniker.PackageInformationID]; ok {
+				metadata.packageManager = packageInformation.Manager
+				metadata.packageName = packageInformation.Name
+				metadata.packageVersion = packageInformation.Version
+			}
+
+			c.metadata[key] = metadata
+		}
+	}
+}
+
+func (c *symbolIndexCollector) addDefinitions(v precise.MonikerLocations) {
+	name, kind, ok := symbolNameAndKind(v.Identifier)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metadata := c.metadata[symbolKey{scheme: v.Scheme, identifier: v.Identifier}]
+
+	for _, location := range v.Locations {
+		if strings.HasPrefix(location.URI, "..") {
+			continue
+		}
+
+		c.symbols = append(c.symbols, lsifstore.IndexedSymbol{
+			Scheme:         v.Scheme,
+			Identifier:     v.Identifier,
+			Name:           name,
+			Kind:           kind,
+			PackageManager: metadata.packageManager,
+			PackageName:    metadata.packageName,
+			PackageVersion: metadata.packageVersion,
+			Path:           c.root + location.URI,
+			StartLine:      location.StartLine,
+			StartCharacter: location.StartCharacter,
+			EndLine:        location.EndLine,
+			EndCharacter:   location.EndCharacter,
+			Documentation:  metadata.documentation,
+		})
+	}
+}
+
+// symbolNameAndKind returns the display name and kind of the symbol with the given moniker
+// identifier. Identifiers that are SCIP symbols are parsed for their last descriptor; other
+// identifiers are assumed to be a sequence of names joined by common separators. The returned
+// flag is false for symbols that should not be searchable (e.g., parameters).
+func symbolNameAndKind(identifier string) (name, kind string, ok bool) {
+	if parsed, err := scip.ParseSymbol(identifier); err == nil && len(parsed.Descriptors) > 0 {
+		descriptor := parsed.Descriptors[len(parsed.Descriptors)-1]
+
+		switch descriptor.Suffix {
+		case scip.Descriptor_Namespace:
+			kind = "namespace"
+		case scip.Descriptor_Type:
+			kind = "type"
+		case scip.Descriptor_Method:
+			kind = "method"
+		case scip.Descriptor_Macro:
+			kind = "macro"
+		case scip.Descriptor_Term:
+			kind = "variable"
+			if len(parsed.Descriptors) > 1 && parsed.Descriptors[len(parsed.Descriptors)-2].Suffix == scip.Descriptor_Type {
+				kind = "field"
+			}
+		default:
+			return "", "", false
+		}
+
+		return descriptor.Name, kind, descriptor.Name != ""
+	}
+
+	name = strings.TrimRight(identifier, ".#:/()")
+	if i := strings.LastIndexAny(name, ".#:/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name, "", name != ""
+}
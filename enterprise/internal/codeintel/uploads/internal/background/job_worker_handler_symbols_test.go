@@ -0,0 +1,102 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestSymbolIndexCollector(t *testing.T) {
+	ctx := context.Background()
+	collector := newSymbolIndexCollector("sub/")
+
+	documents := make(chan precise.KeyedDocumentData, 1)
+	documents <- precise.KeyedDocumentData{
+		Path: "foo.go",
+		Document: precise.DocumentData{
+			Ranges: map[precise.ID]precise.RangeData{
+				"r1": {MonikerIDs: []precise.ID{"m1"}, HoverResultID: "h1"},
+				"r2": {MonikerIDs: []precise.ID{"m2"}},
+			},
+			HoverResults: map[precise.ID]string{
+				"h1": "func Bar()",
+			},
+			Monikers: map[precise.ID]precise.MonikerData{
+				"m1": {Kind: precise.Export, Scheme: "gomod", Identifier: "github.com/foo/pkg:Bar", PackageInformationID: "p1"},
+				"m2": {Kind: precise.Import, Scheme: "gomod", Identifier: "github.com/baz/pkg:Quux", PackageInformationID: "p1"},
+			},
+			PackageInformation: map[precise.ID]precise.PackageInformationData{
+				"p1": {Manager: "gomod", Name: "github.com/foo", Version: "v1.0.0"},
+			},
+		},
+	}
+	close(documents)
+	for range collector.observeDocuments(ctx, documents) {
+	}
+
+	definitions := make(chan precise.MonikerLocations, 3)
+	definitions <- precise.MonikerLocations{
+		Kind:       precise.Export,
+		Scheme:     "gomod",
+		Identifier: "github.com/foo/pkg:Bar",
+		Locations: []precise.LocationData{
+			{URI: "foo.go", StartLine: 1, StartCharacter: 5, EndLine: 1, EndCharacter: 8},
+			{URI: "../outside.go", StartLine: 2, StartCharacter: 5, EndLine: 2, EndCharacter: 8},
+		},
+	}
+	definitions <- precise.MonikerLocations{
+		Kind:       precise.Export,
+		Scheme:     "scip-typescript",
+		Identifier: "scip-typescript npm pkg 1.0.0 src/`index.ts`/Widget#render().",
+		Locations:  []precise.LocationData{{URI: "src/index.ts", StartLine: 10, StartCharacter: 2, EndLine: 10, EndCharacter: 8}},
+	}
+	definitions <- precise.MonikerLocations{
+		Kind:       precise.Export,
+		Scheme:     "scip-typescript",
+		Identifier: "scip-typescript npm pkg 1.0.0 src/`index.ts`/Widget#render().(props)",
+		Locations:  []precise.LocationData{{URI: "src/index.ts", StartLine: 10, StartCharacter: 9, EndLine: 10, EndCharacter: 14}},
+	}
+	close(definitions)
+	for range collector.observeDefinitions(ctx, definitions) {
+	}
+
+	var symbols []lsifstore.IndexedSymbol
+	for symbol := range collector.indexedSymbols() {
+		symbols = append(symbols, symbol)
+	}
+
+	expectedSymbols := []lsifstore.IndexedSymbol{
+		{
+			Scheme:         "gomod",
+			Identifier:     "github.com/foo/pkg:Bar",
+			Name:           "Bar",
+			PackageManager: "gomod",
+			PackageName:    "github.com/foo",
+			PackageVersion: "v1.0.0",
+			Path:           "sub/foo.go",
+			StartLine:      1,
+			StartCharacter: 5,
+			EndLine:        1,
+			EndCharacter:   8,
+			Documentation:  "func Bar()",
+		},
+		{
+			Scheme:         "scip-typescript",
+			Identifier:     "scip-typescript npm pkg 1.0.0 src/`index.ts`/Widget#render().",
+			Name:           "render",
+			Kind:           "method",
+			Path:           "sub/src/index.ts",
+			StartLine:      10,
+			StartCharacter: 2,
+			EndLine:        10,
+			EndCharacter:   8,
+		},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
@@ -47,6 +47,7 @@ func TestHandle(t *testing.T) {
 	// Set default transaction behavior
 	mockLSIFStore.TransactFunc.SetDefaultReturn(mockLSIFStore, nil)
 	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.NewSymbolWriterFunc.SetDefaultReturn(&testSymbolWriter{}, nil)
 
 	// Give correlation package a valid input dump
 	mockUploadStore.GetFunc.SetDefaultHook(copyTestDump)
@@ -143,6 +144,12 @@ func TestHandle(t *testing.T) {
 		t.Errorf("unexpected value for repository id. want=%d have=%d", 50, mockDBStore.SetRepositoryAsDirtyFunc.History()[0].Arg1)
 	}
 
+	if calls := mockLSIFStore.InsertMetadataFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of InsertMetadata calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 || calls[0].Arg2.ToolName != "lsif-go" {
+		t.Errorf("unexpected InsertMetadata args. want=%d,%s have=%d,%s", 42, "lsif-go", calls[0].Arg1, calls[0].Arg2.ToolName)
+	}
+
 	if len(mockUploadStore.DeleteFunc.History()) != 1 {
 		t.Errorf("unexpected number of Delete calls. want=%d have=%d", 1, len(mockUploadStore.DeleteFunc.History()))
 	}
@@ -173,6 +180,7 @@ func TestHandleError(t *testing.T) {
 	// Set default transaction behavior
 	mockLSIFStore.TransactFunc.SetDefaultReturn(mockLSIFStore, nil)
 	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.NewSymbolWriterFunc.SetDefaultReturn(&testSymbolWriter{}, nil)
 
 	// Give correlation package a valid input dump
 	mockUploadStore.GetFunc.SetDefaultHook(copyTestDump)
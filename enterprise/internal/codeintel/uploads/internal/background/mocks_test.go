@@ -2370,6 +2370,10 @@ type MockStore struct {
 	// AddUploadPartFunc is an instance of a mock function object
 	// controlling the behavior of the method AddUploadPart.
 	AddUploadPartFunc *StoreAddUploadPartFunc
+	// CopyPackagesFromUploadFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CopyPackagesFromUpload.
+	CopyPackagesFromUploadFunc *StoreCopyPackagesFromUploadFunc
 	// DeleteOldAuditLogsFunc is an instance of a mock function object
 	// controlling the behavior of the method DeleteOldAuditLogs.
 	DeleteOldAuditLogsFunc *StoreDeleteOldAuditLogsFunc
@@ -2403,6 +2407,9 @@ type MockStore struct {
 	// function object controlling the behavior of the method
 	// FindClosestDumpsFromGraphFragment.
 	FindClosestDumpsFromGraphFragmentFunc *StoreFindClosestDumpsFromGraphFragmentFunc
+	// FindEphemeralDumpsFunc is an instance of a mock function object
+	// controlling the behavior of the method FindEphemeralDumps.
+	FindEphemeralDumpsFunc *StoreFindEphemeralDumpsFunc
 	// GetAuditLogsForUploadFunc is an instance of a mock function object
 	// controlling the behavior of the method GetAuditLogsForUpload.
 	GetAuditLogsForUploadFunc *StoreGetAuditLogsForUploadFunc
@@ -2441,6 +2448,9 @@ type MockStore struct {
 	// object controlling the behavior of the method
 	// GetRepositoriesMaxStaleAge.
 	GetRepositoriesMaxStaleAgeFunc *StoreGetRepositoriesMaxStaleAgeFunc
+	// GetRepositoryCoverageFunc is an instance of a mock function object
+	// controlling the behavior of the method GetRepositoryCoverage.
+	GetRepositoryCoverageFunc *StoreGetRepositoryCoverageFunc
 	// GetStaleSourcedCommitsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetStaleSourcedCommits.
 	GetStaleSourcedCommitsFunc *StoreGetStaleSourcedCommitsFunc
@@ -2460,6 +2470,10 @@ type MockStore struct {
 	// GetUploadsForRankingFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadsForRanking.
 	GetUploadsForRankingFunc *StoreGetUploadsForRankingFunc
+	// GetVisibleUploadDistancesFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetVisibleUploadDistances.
+	GetVisibleUploadDistancesFunc *StoreGetVisibleUploadDistancesFunc
 	// GetVisibleUploadsMatchingMonikersFunc is an instance of a mock
 	// function object controlling the behavior of the method
 	// GetVisibleUploadsMatchingMonikers.
@@ -2560,13 +2574,18 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		CopyPackagesFromUploadFunc: &StoreCopyPackagesFromUploadFunc{
+			defaultHook: func(context.Context, int, int) (r0 error) {
+				return
+			},
+		},
 		DeleteOldAuditLogsFunc: &StoreDeleteOldAuditLogsFunc{
 			defaultHook: func(context.Context, time.Duration, time.Time) (r0 int, r1 error) {
 				return
 			},
 		},
 		DeleteOverlappingDumpsFunc: &StoreDeleteOverlappingDumpsFunc{
-			defaultHook: func(context.Context, int, string, string, string) (r0 error) {
+			defaultHook: func(context.Context, int, string, string, string, bool) (r0 error) {
 				return
 			},
 		},
@@ -2610,6 +2629,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		FindEphemeralDumpsFunc: &StoreFindEphemeralDumpsFunc{
+			defaultHook: func(context.Context, int, string, string, bool, string) (r0 []types.Dump, r1 error) {
+				return
+			},
+		},
 		GetAuditLogsForUploadFunc: &StoreGetAuditLogsForUploadFunc{
 			defaultHook: func(context.Context, int) (r0 []types.UploadLog, r1 error) {
 				return
@@ -2665,6 +2689,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		GetRepositoryCoverageFunc: &StoreGetRepositoryCoverageFunc{
+			defaultHook: func(context.Context, shared1.GetRepositoryCoverageOptions) (r0 []shared1.RepositoryCoverage, r1 int, r2 error) {
+				return
+			},
+		},
 		GetStaleSourcedCommitsFunc: &StoreGetStaleSourcedCommitsFunc{
 			defaultHook: func(context.Context, time.Duration, int, time.Time) (r0 []shared1.SourcedCommits, r1 error) {
 				return
@@ -2695,6 +2724,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		GetVisibleUploadDistancesFunc: &StoreGetVisibleUploadDistancesFunc{
+			defaultHook: func(context.Context, int, string) (r0 map[int]int, r1 error) {
+				return
+			},
+		},
 		GetVisibleUploadsMatchingMonikersFunc: &StoreGetVisibleUploadsMatchingMonikersFunc{
 			defaultHook: func(context.Context, int, string, []precise.QualifiedMonikerData, int, int) (r0 shared1.PackageReferenceScanner, r1 int, r2 error) {
 				return
@@ -2842,13 +2876,18 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.AddUploadPart")
 			},
 		},
+		CopyPackagesFromUploadFunc: &StoreCopyPackagesFromUploadFunc{
+			defaultHook: func(context.Context, int, int) error {
+				panic("unexpected invocation of MockStore.CopyPackagesFromUpload")
+			},
+		},
 		DeleteOldAuditLogsFunc: &StoreDeleteOldAuditLogsFunc{
 			defaultHook: func(context.Context, time.Duration, time.Time) (int, error) {
 				panic("unexpected invocation of MockStore.DeleteOldAuditLogs")
 			},
 		},
 		DeleteOverlappingDumpsFunc: &StoreDeleteOverlappingDumpsFunc{
-			defaultHook: func(context.Context, int, string, string, string) error {
+			defaultHook: func(context.Context, int, string, string, string, bool) error {
 				panic("unexpected invocation of MockStore.DeleteOverlappingDumps")
 			},
 		},
@@ -2892,6 +2931,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.FindClosestDumpsFromGraphFragment")
 			},
 		},
+		FindEphemeralDumpsFunc: &StoreFindEphemeralDumpsFunc{
+			defaultHook: func(context.Context, int, string, string, bool, string) ([]types.Dump, error) {
+				panic("unexpected invocation of MockStore.FindEphemeralDumps")
+			},
+		},
 		GetAuditLogsForUploadFunc: &StoreGetAuditLogsForUploadFunc{
 			defaultHook: func(context.Context, int) ([]types.UploadLog, error) {
 				panic("unexpected invocation of MockStore.GetAuditLogsForUpload")
@@ -2947,6 +2991,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.GetRepositoriesMaxStaleAge")
 			},
 		},
+		GetRepositoryCoverageFunc: &StoreGetRepositoryCoverageFunc{
+			defaultHook: func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+				panic("unexpected invocation of MockStore.GetRepositoryCoverage")
+			},
+		},
 		GetStaleSourcedCommitsFunc: &StoreGetStaleSourcedCommitsFunc{
 			defaultHook: func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
 				panic("unexpected invocation of MockStore.GetStaleSourcedCommits")
@@ -2977,6 +3026,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.GetUploadsForRanking")
 			},
 		},
+		GetVisibleUploadDistancesFunc: &StoreGetVisibleUploadDistancesFunc{
+			defaultHook: func(context.Context, int, string) (map[int]int, error) {
+				panic("unexpected invocation of MockStore.GetVisibleUploadDistances")
+			},
+		},
 		GetVisibleUploadsMatchingMonikersFunc: &StoreGetVisibleUploadsMatchingMonikersFunc{
 			defaultHook: func(context.Context, int, string, []precise.QualifiedMonikerData, int, int) (shared1.PackageReferenceScanner, int, error) {
 				panic("unexpected invocation of MockStore.GetVisibleUploadsMatchingMonikers")
@@ -3122,6 +3176,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		AddUploadPartFunc: &StoreAddUploadPartFunc{
 			defaultHook: i.AddUploadPart,
 		},
+		CopyPackagesFromUploadFunc: &StoreCopyPackagesFromUploadFunc{
+			defaultHook: i.CopyPackagesFromUpload,
+		},
 		DeleteOldAuditLogsFunc: &StoreDeleteOldAuditLogsFunc{
 			defaultHook: i.DeleteOldAuditLogs,
 		},
@@ -3152,6 +3209,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		FindClosestDumpsFromGraphFragmentFunc: &StoreFindClosestDumpsFromGraphFragmentFunc{
 			defaultHook: i.FindClosestDumpsFromGraphFragment,
 		},
+		FindEphemeralDumpsFunc: &StoreFindEphemeralDumpsFunc{
+			defaultHook: i.FindEphemeralDumps,
+		},
 		GetAuditLogsForUploadFunc: &StoreGetAuditLogsForUploadFunc{
 			defaultHook: i.GetAuditLogsForUpload,
 		},
@@ -3185,6 +3245,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		GetRepositoriesMaxStaleAgeFunc: &StoreGetRepositoriesMaxStaleAgeFunc{
 			defaultHook: i.GetRepositoriesMaxStaleAge,
 		},
+		GetRepositoryCoverageFunc: &StoreGetRepositoryCoverageFunc{
+			defaultHook: i.GetRepositoryCoverage,
+		},
 		GetStaleSourcedCommitsFunc: &StoreGetStaleSourcedCommitsFunc{
 			defaultHook: i.GetStaleSourcedCommits,
 		},
@@ -3203,6 +3266,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		GetUploadsForRankingFunc: &StoreGetUploadsForRankingFunc{
 			defaultHook: i.GetUploadsForRanking,
 		},
+		GetVisibleUploadDistancesFunc: &StoreGetVisibleUploadDistancesFunc{
+			defaultHook: i.GetVisibleUploadDistances,
+		},
 		GetVisibleUploadsMatchingMonikersFunc: &StoreGetVisibleUploadsMatchingMonikersFunc{
 			defaultHook: i.GetVisibleUploadsMatchingMonikers,
 		},
@@ -3394,6 +3460,115 @@ func (c StoreAddUploadPartFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// StoreCopyPackagesFromUploadFunc describes the behavior when the
+// CopyPackagesFromUpload method of the parent MockStore instance is
+// invoked.
+type StoreCopyPackagesFromUploadFunc struct {
+	defaultHook func(context.Context, int, int) error
+	hooks       []func(context.Context, int, int) error
+	history     []StoreCopyPackagesFromUploadFuncCall
+	mutex       sync.Mutex
+}
+
+// CopyPackagesFromUpload delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) CopyPackagesFromUpload(v0 context.Context, v1 int, v2 int) error {
+	r0 := m.CopyPackagesFromUploadFunc.nextHook()(v0, v1, v2)
+	m.CopyPackagesFromUploadFunc.appendCall(StoreCopyPackagesFromUploadFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// CopyPackagesFromUpload method of the parent MockStore instance is invoked
+// and the hook queue is empty.
+func (f *StoreCopyPackagesFromUploadFunc) SetDefaultHook(hook func(context.Context, int, int) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CopyPackagesFromUpload method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreCopyPackagesFromUploadFunc) PushHook(hook func(context.Context, int, int) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreCopyPackagesFromUploadFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, int) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreCopyPackagesFromUploadFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, int) error {
+		return r0
+	})
+}
+
+func (f *StoreCopyPackagesFromUploadFunc) nextHook() func(context.Context, int, int) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreCopyPackagesFromUploadFunc) appendCall(r0 StoreCopyPackagesFromUploadFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreCopyPackagesFromUploadFuncCall objects
+// describing the invocations of this function.
+func (f *StoreCopyPackagesFromUploadFunc) History() []StoreCopyPackagesFromUploadFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreCopyPackagesFromUploadFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreCopyPackagesFromUploadFuncCall is an object that describes an
+// invocation of method CopyPackagesFromUpload on an instance of MockStore.
+type StoreCopyPackagesFromUploadFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreCopyPackagesFromUploadFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreCopyPackagesFromUploadFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // StoreDeleteOldAuditLogsFunc describes the behavior when the
 // DeleteOldAuditLogs method of the parent MockStore instance is invoked.
 type StoreDeleteOldAuditLogsFunc struct {
@@ -3509,24 +3684,24 @@ func (c StoreDeleteOldAuditLogsFuncCall) Results() []interface{} {
 // DeleteOverlappingDumps method of the parent MockStore instance is
 // invoked.
 type StoreDeleteOverlappingDumpsFunc struct {
-	defaultHook func(context.Context, int, string, string, string) error
-	hooks       []func(context.Context, int, string, string, string) error
+	defaultHook func(context.Context, int, string, string, string, bool) error
+	hooks       []func(context.Context, int, string, string, string, bool) error
 	history     []StoreDeleteOverlappingDumpsFuncCall
 	mutex       sync.Mutex
 }
 
 // DeleteOverlappingDumps delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockStore) DeleteOverlappingDumps(v0 context.Context, v1 int, v2 string, v3 string, v4 string) error {
-	r0 := m.DeleteOverlappingDumpsFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.DeleteOverlappingDumpsFunc.appendCall(StoreDeleteOverlappingDumpsFuncCall{v0, v1, v2, v3, v4, r0})
+func (m *MockStore) DeleteOverlappingDumps(v0 context.Context, v1 int, v2 string, v3 string, v4 string, v5 bool) error {
+	r0 := m.DeleteOverlappingDumpsFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.DeleteOverlappingDumpsFunc.appendCall(StoreDeleteOverlappingDumpsFuncCall{v0, v1, v2, v3, v4, v5, r0})
 	return r0
 }
 
 // SetDefaultHook sets function that is called when the
 // DeleteOverlappingDumps method of the parent MockStore instance is invoked
 // and the hook queue is empty.
-func (f *StoreDeleteOverlappingDumpsFunc) SetDefaultHook(hook func(context.Context, int, string, string, string) error) {
+func (f *StoreDeleteOverlappingDumpsFunc) SetDefaultHook(hook func(context.Context, int, string, string, string, bool) error) {
 	f.defaultHook = hook
 }
 
@@ -3534,7 +3709,7 @@ func (f *StoreDeleteOverlappingDumpsFunc) SetDefaultHook(hook func(context.Conte
 // DeleteOverlappingDumps method of the parent MockStore instance invokes
 // the hook at the front of the queue and discards it. After the queue is
 // empty, the default hook function is invoked for any future action.
-func (f *StoreDeleteOverlappingDumpsFunc) PushHook(hook func(context.Context, int, string, string, string) error) {
+func (f *StoreDeleteOverlappingDumpsFunc) PushHook(hook func(context.Context, int, string, string, string, bool) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -3543,19 +3718,19 @@ func (f *StoreDeleteOverlappingDumpsFunc) PushHook(hook func(context.Context, in
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
 func (f *StoreDeleteOverlappingDumpsFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int, string, string, string) error {
+	f.SetDefaultHook(func(context.Context, int, string, string, string, bool) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
 func (f *StoreDeleteOverlappingDumpsFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int, string, string, string) error {
+	f.PushHook(func(context.Context, int, string, string, string, bool) error {
 		return r0
 	})
 }
 
-func (f *StoreDeleteOverlappingDumpsFunc) nextHook() func(context.Context, int, string, string, string) error {
+func (f *StoreDeleteOverlappingDumpsFunc) nextHook() func(context.Context, int, string, string, string, bool) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -3603,6 +3778,9 @@ type StoreDeleteOverlappingDumpsFuncCall struct {
 	// Arg4 is the value of the 5th argument passed to this method
 	// invocation.
 	Arg4 string
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 bool
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -3611,7 +3789,7 @@ type StoreDeleteOverlappingDumpsFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c StoreDeleteOverlappingDumpsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
 }
 
 // Results returns an interface slice containing the results of this
@@ -4522,6 +4700,126 @@ func (c StoreFindClosestDumpsFromGraphFragmentFuncCall) Results() []interface{}
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreFindEphemeralDumpsFunc describes the behavior when the
+// FindEphemeralDumps method of the parent MockStore instance is invoked.
+type StoreFindEphemeralDumpsFunc struct {
+	defaultHook func(context.Context, int, string, string, bool, string) ([]types.Dump, error)
+	hooks       []func(context.Context, int, string, string, bool, string) ([]types.Dump, error)
+	history     []StoreFindEphemeralDumpsFuncCall
+	mutex       sync.Mutex
+}
+
+// FindEphemeralDumps delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) FindEphemeralDumps(v0 context.Context, v1 int, v2 string, v3 string, v4 bool, v5 string) ([]types.Dump, error) {
+	r0, r1 := m.FindEphemeralDumpsFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.FindEphemeralDumpsFunc.appendCall(StoreFindEphemeralDumpsFuncCall{v0, v1, v2, v3, v4, v5, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the FindEphemeralDumps
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreFindEphemeralDumpsFunc) SetDefaultHook(hook func(context.Context, int, string, string, bool, string) ([]types.Dump, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// FindEphemeralDumps method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreFindEphemeralDumpsFunc) PushHook(hook func(context.Context, int, string, string, bool, string) ([]types.Dump, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreFindEphemeralDumpsFunc) SetDefaultReturn(r0 []types.Dump, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, bool, string) ([]types.Dump, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreFindEphemeralDumpsFunc) PushReturn(r0 []types.Dump, r1 error) {
+	f.PushHook(func(context.Context, int, string, string, bool, string) ([]types.Dump, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreFindEphemeralDumpsFunc) nextHook() func(context.Context, int, string, string, bool, string) ([]types.Dump, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreFindEphemeralDumpsFunc) appendCall(r0 StoreFindEphemeralDumpsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreFindEphemeralDumpsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreFindEphemeralDumpsFunc) History() []StoreFindEphemeralDumpsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreFindEphemeralDumpsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreFindEphemeralDumpsFuncCall is an object that describes an invocation
+// of method FindEphemeralDumps on an instance of MockStore.
+type StoreFindEphemeralDumpsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 bool
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.Dump
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreFindEphemeralDumpsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreFindEphemeralDumpsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetAuditLogsForUploadFunc describes the behavior when the
 // GetAuditLogsForUpload method of the parent MockStore instance is invoked.
 type StoreGetAuditLogsForUploadFunc struct {
@@ -5755,36 +6053,35 @@ func (c StoreGetRepositoriesMaxStaleAgeFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreGetStaleSourcedCommitsFunc describes the behavior when the
-// GetStaleSourcedCommits method of the parent MockStore instance is
-// invoked.
-type StoreGetStaleSourcedCommitsFunc struct {
-	defaultHook func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)
-	hooks       []func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)
-	history     []StoreGetStaleSourcedCommitsFuncCall
+// StoreGetRepositoryCoverageFunc describes the behavior when the
+// GetRepositoryCoverage method of the parent MockStore instance is invoked.
+type StoreGetRepositoryCoverageFunc struct {
+	defaultHook func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)
+	hooks       []func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)
+	history     []StoreGetRepositoryCoverageFuncCall
 	mutex       sync.Mutex
 }
 
-// GetStaleSourcedCommits delegates to the next hook function in the queue
+// GetRepositoryCoverage delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockStore) GetStaleSourcedCommits(v0 context.Context, v1 time.Duration, v2 int, v3 time.Time) ([]shared1.SourcedCommits, error) {
-	r0, r1 := m.GetStaleSourcedCommitsFunc.nextHook()(v0, v1, v2, v3)
-	m.GetStaleSourcedCommitsFunc.appendCall(StoreGetStaleSourcedCommitsFuncCall{v0, v1, v2, v3, r0, r1})
-	return r0, r1
+func (m *MockStore) GetRepositoryCoverage(v0 context.Context, v1 shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+	r0, r1, r2 := m.GetRepositoryCoverageFunc.nextHook()(v0, v1)
+	m.GetRepositoryCoverageFunc.appendCall(StoreGetRepositoryCoverageFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
 }
 
 // SetDefaultHook sets function that is called when the
-// GetStaleSourcedCommits method of the parent MockStore instance is invoked
+// GetRepositoryCoverage method of the parent MockStore instance is invoked
 // and the hook queue is empty.
-func (f *StoreGetStaleSourcedCommitsFunc) SetDefaultHook(hook func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)) {
+func (f *StoreGetRepositoryCoverageFunc) SetDefaultHook(hook func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetStaleSourcedCommits method of the parent MockStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *StoreGetStaleSourcedCommitsFunc) PushHook(hook func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)) {
+// GetRepositoryCoverage method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreGetRepositoryCoverageFunc) PushHook(hook func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -5792,20 +6089,20 @@ func (f *StoreGetStaleSourcedCommitsFunc) PushHook(hook func(context.Context, ti
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *StoreGetStaleSourcedCommitsFunc) SetDefaultReturn(r0 []shared1.SourcedCommits, r1 error) {
-	f.SetDefaultHook(func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
-		return r0, r1
+func (f *StoreGetRepositoryCoverageFunc) SetDefaultReturn(r0 []shared1.RepositoryCoverage, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *StoreGetStaleSourcedCommitsFunc) PushReturn(r0 []shared1.SourcedCommits, r1 error) {
-	f.PushHook(func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
-		return r0, r1
+func (f *StoreGetRepositoryCoverageFunc) PushReturn(r0 []shared1.RepositoryCoverage, r1 int, r2 error) {
+	f.PushHook(func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+		return r0, r1, r2
 	})
 }
 
-func (f *StoreGetStaleSourcedCommitsFunc) nextHook() func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
+func (f *StoreGetRepositoryCoverageFunc) nextHook() func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -5818,35 +6115,147 @@ func (f *StoreGetStaleSourcedCommitsFunc) nextHook() func(context.Context, time.
 	return hook
 }
 
-func (f *StoreGetStaleSourcedCommitsFunc) appendCall(r0 StoreGetStaleSourcedCommitsFuncCall) {
+func (f *StoreGetRepositoryCoverageFunc) appendCall(r0 StoreGetRepositoryCoverageFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreGetStaleSourcedCommitsFuncCall objects
+// History returns a sequence of StoreGetRepositoryCoverageFuncCall objects
 // describing the invocations of this function.
-func (f *StoreGetStaleSourcedCommitsFunc) History() []StoreGetStaleSourcedCommitsFuncCall {
+func (f *StoreGetRepositoryCoverageFunc) History() []StoreGetRepositoryCoverageFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreGetStaleSourcedCommitsFuncCall, len(f.history))
+	history := make([]StoreGetRepositoryCoverageFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreGetStaleSourcedCommitsFuncCall is an object that describes an
-// invocation of method GetStaleSourcedCommits on an instance of MockStore.
-type StoreGetStaleSourcedCommitsFuncCall struct {
+// StoreGetRepositoryCoverageFuncCall is an object that describes an
+// invocation of method GetRepositoryCoverage on an instance of MockStore.
+type StoreGetRepositoryCoverageFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 time.Duration
-	// Arg2 is the value of the 3rd argument passed to this method
+	Arg1 shared1.GetRepositoryCoverageOptions
+	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Arg2 int
+	Result0 []shared1.RepositoryCoverage
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetRepositoryCoverageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetRepositoryCoverageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreGetStaleSourcedCommitsFunc describes the behavior when the
+// GetStaleSourcedCommits method of the parent MockStore instance is
+// invoked.
+type StoreGetStaleSourcedCommitsFunc struct {
+	defaultHook func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)
+	hooks       []func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)
+	history     []StoreGetStaleSourcedCommitsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetStaleSourcedCommits delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) GetStaleSourcedCommits(v0 context.Context, v1 time.Duration, v2 int, v3 time.Time) ([]shared1.SourcedCommits, error) {
+	r0, r1 := m.GetStaleSourcedCommitsFunc.nextHook()(v0, v1, v2, v3)
+	m.GetStaleSourcedCommitsFunc.appendCall(StoreGetStaleSourcedCommitsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetStaleSourcedCommits method of the parent MockStore instance is invoked
+// and the hook queue is empty.
+func (f *StoreGetStaleSourcedCommitsFunc) SetDefaultHook(hook func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetStaleSourcedCommits method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetStaleSourcedCommitsFunc) PushHook(hook func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetStaleSourcedCommitsFunc) SetDefaultReturn(r0 []shared1.SourcedCommits, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetStaleSourcedCommitsFunc) PushReturn(r0 []shared1.SourcedCommits, r1 error) {
+	f.PushHook(func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetStaleSourcedCommitsFunc) nextHook() func(context.Context, time.Duration, int, time.Time) ([]shared1.SourcedCommits, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetStaleSourcedCommitsFunc) appendCall(r0 StoreGetStaleSourcedCommitsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetStaleSourcedCommitsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetStaleSourcedCommitsFunc) History() []StoreGetStaleSourcedCommitsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetStaleSourcedCommitsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetStaleSourcedCommitsFuncCall is an object that describes an
+// invocation of method GetStaleSourcedCommits on an instance of MockStore.
+type StoreGetStaleSourcedCommitsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Duration
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
 	// Arg3 is the value of the 4th argument passed to this method
 	// invocation.
 	Arg3 time.Time
@@ -6454,6 +6863,119 @@ func (c StoreGetUploadsForRankingFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreGetVisibleUploadDistancesFunc describes the behavior when the
+// GetVisibleUploadDistances method of the parent MockStore instance is
+// invoked.
+type StoreGetVisibleUploadDistancesFunc struct {
+	defaultHook func(context.Context, int, string) (map[int]int, error)
+	hooks       []func(context.Context, int, string) (map[int]int, error)
+	history     []StoreGetVisibleUploadDistancesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetVisibleUploadDistances delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockStore) GetVisibleUploadDistances(v0 context.Context, v1 int, v2 string) (map[int]int, error) {
+	r0, r1 := m.GetVisibleUploadDistancesFunc.nextHook()(v0, v1, v2)
+	m.GetVisibleUploadDistancesFunc.appendCall(StoreGetVisibleUploadDistancesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetVisibleUploadDistances method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreGetVisibleUploadDistancesFunc) SetDefaultHook(hook func(context.Context, int, string) (map[int]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetVisibleUploadDistances method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetVisibleUploadDistancesFunc) PushHook(hook func(context.Context, int, string) (map[int]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetVisibleUploadDistancesFunc) SetDefaultReturn(r0 map[int]int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) (map[int]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetVisibleUploadDistancesFunc) PushReturn(r0 map[int]int, r1 error) {
+	f.PushHook(func(context.Context, int, string) (map[int]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetVisibleUploadDistancesFunc) nextHook() func(context.Context, int, string) (map[int]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetVisibleUploadDistancesFunc) appendCall(r0 StoreGetVisibleUploadDistancesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetVisibleUploadDistancesFuncCall
+// objects describing the invocations of this function.
+func (f *StoreGetVisibleUploadDistancesFunc) History() []StoreGetVisibleUploadDistancesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetVisibleUploadDistancesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetVisibleUploadDistancesFuncCall is an object that describes an
+// invocation of method GetVisibleUploadDistances on an instance of
+// MockStore.
+type StoreGetVisibleUploadDistancesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[int]int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetVisibleUploadDistancesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetVisibleUploadDistancesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetVisibleUploadsMatchingMonikersFunc describes the behavior when
 // the GetVisibleUploadsMatchingMonikers method of the parent MockStore
 // instance is invoked.
@@ -9735,6 +10257,14 @@ func (c RepoStoreResolveRevFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/internal/lsifstore)
 // used for unit testing.
 type MockLsifStore struct {
+	// CopyLSIFDataFromUploadFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CopyLSIFDataFromUpload.
+	CopyLSIFDataFromUploadFunc *LsifStoreCopyLSIFDataFromUploadFunc
+	// CopySCIPDocumentsFromUploadFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CopySCIPDocumentsFromUpload.
+	CopySCIPDocumentsFromUploadFunc *LsifStoreCopySCIPDocumentsFromUploadFunc
 	// DeleteLsifDataByUploadIdsFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// DeleteLsifDataByUploadIds.
@@ -9791,12 +10321,25 @@ type MockLsifStore struct {
 	// WriteResultChunksFunc is an instance of a mock function object
 	// controlling the behavior of the method WriteResultChunks.
 	WriteResultChunksFunc *LsifStoreWriteResultChunksFunc
+	// WriteSymbolIndexFunc is an instance of a mock function object
+	// controlling the behavior of the method WriteSymbolIndex.
+	WriteSymbolIndexFunc *LsifStoreWriteSymbolIndexFunc
 }
 
 // NewMockLsifStore creates a new mock of the LsifStore interface. All
 // methods return zero values for all results, unless overwritten.
 func NewMockLsifStore() *MockLsifStore {
 	return &MockLsifStore{
+		CopyLSIFDataFromUploadFunc: &LsifStoreCopyLSIFDataFromUploadFunc{
+			defaultHook: func(context.Context, int, int, string, []string) (r0 uint32, r1 error) {
+				return
+			},
+		},
+		CopySCIPDocumentsFromUploadFunc: &LsifStoreCopySCIPDocumentsFromUploadFunc{
+			defaultHook: func(context.Context, int, int, []string) (r0 uint32, r1 error) {
+				return
+			},
+		},
 		DeleteLsifDataByUploadIdsFunc: &LsifStoreDeleteLsifDataByUploadIdsFunc{
 			defaultHook: func(context.Context, ...int) (r0 error) {
 				return
@@ -9887,6 +10430,11 @@ func NewMockLsifStore() *MockLsifStore {
 				return
 			},
 		},
+		WriteSymbolIndexFunc: &LsifStoreWriteSymbolIndexFunc{
+			defaultHook: func(context.Context, int, chan lsifstore.IndexedSymbol) (r0 uint32, r1 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -9894,6 +10442,16 @@ func NewMockLsifStore() *MockLsifStore {
 // methods panic on invocation, unless overwritten.
 func NewStrictMockLsifStore() *MockLsifStore {
 	return &MockLsifStore{
+		CopyLSIFDataFromUploadFunc: &LsifStoreCopyLSIFDataFromUploadFunc{
+			defaultHook: func(context.Context, int, int, string, []string) (uint32, error) {
+				panic("unexpected invocation of MockLsifStore.CopyLSIFDataFromUpload")
+			},
+		},
+		CopySCIPDocumentsFromUploadFunc: &LsifStoreCopySCIPDocumentsFromUploadFunc{
+			defaultHook: func(context.Context, int, int, []string) (uint32, error) {
+				panic("unexpected invocation of MockLsifStore.CopySCIPDocumentsFromUpload")
+			},
+		},
 		DeleteLsifDataByUploadIdsFunc: &LsifStoreDeleteLsifDataByUploadIdsFunc{
 			defaultHook: func(context.Context, ...int) error {
 				panic("unexpected invocation of MockLsifStore.DeleteLsifDataByUploadIds")
@@ -9984,6 +10542,11 @@ func NewStrictMockLsifStore() *MockLsifStore {
 				panic("unexpected invocation of MockLsifStore.WriteResultChunks")
 			},
 		},
+		WriteSymbolIndexFunc: &LsifStoreWriteSymbolIndexFunc{
+			defaultHook: func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error) {
+				panic("unexpected invocation of MockLsifStore.WriteSymbolIndex")
+			},
+		},
 	}
 }
 
@@ -9991,6 +10554,12 @@ func NewStrictMockLsifStore() *MockLsifStore {
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 	return &MockLsifStore{
+		CopyLSIFDataFromUploadFunc: &LsifStoreCopyLSIFDataFromUploadFunc{
+			defaultHook: i.CopyLSIFDataFromUpload,
+		},
+		CopySCIPDocumentsFromUploadFunc: &LsifStoreCopySCIPDocumentsFromUploadFunc{
+			defaultHook: i.CopySCIPDocumentsFromUpload,
+		},
 		DeleteLsifDataByUploadIdsFunc: &LsifStoreDeleteLsifDataByUploadIdsFunc{
 			defaultHook: i.DeleteLsifDataByUploadIds,
 		},
@@ -10045,9 +10614,250 @@ func NewMockLsifStoreFrom(i lsifstore.LsifStore) *MockLsifStore {
 		WriteResultChunksFunc: &LsifStoreWriteResultChunksFunc{
 			defaultHook: i.WriteResultChunks,
 		},
+		WriteSymbolIndexFunc: &LsifStoreWriteSymbolIndexFunc{
+			defaultHook: i.WriteSymbolIndex,
+		},
 	}
 }
 
+// LsifStoreCopyLSIFDataFromUploadFunc describes the behavior when the
+// CopyLSIFDataFromUpload method of the parent MockLsifStore instance is
+// invoked.
+type LsifStoreCopyLSIFDataFromUploadFunc struct {
+	defaultHook func(context.Context, int, int, string, []string) (uint32, error)
+	hooks       []func(context.Context, int, int, string, []string) (uint32, error)
+	history     []LsifStoreCopyLSIFDataFromUploadFuncCall
+	mutex       sync.Mutex
+}
+
+// CopyLSIFDataFromUpload delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockLsifStore) CopyLSIFDataFromUpload(v0 context.Context, v1 int, v2 int, v3 string, v4 []string) (uint32, error) {
+	r0, r1 := m.CopyLSIFDataFromUploadFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.CopyLSIFDataFromUploadFunc.appendCall(LsifStoreCopyLSIFDataFromUploadFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// CopyLSIFDataFromUpload method of the parent MockLsifStore instance is
+// invoked and the hook queue is empty.
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) SetDefaultHook(hook func(context.Context, int, int, string, []string) (uint32, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CopyLSIFDataFromUpload method of the parent MockLsifStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) PushHook(hook func(context.Context, int, int, string, []string) (uint32, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) SetDefaultReturn(r0 uint32, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int, string, []string) (uint32, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) PushReturn(r0 uint32, r1 error) {
+	f.PushHook(func(context.Context, int, int, string, []string) (uint32, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) nextHook() func(context.Context, int, int, string, []string) (uint32, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) appendCall(r0 LsifStoreCopyLSIFDataFromUploadFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreCopyLSIFDataFromUploadFuncCall
+// objects describing the invocations of this function.
+func (f *LsifStoreCopyLSIFDataFromUploadFunc) History() []LsifStoreCopyLSIFDataFromUploadFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreCopyLSIFDataFromUploadFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreCopyLSIFDataFromUploadFuncCall is an object that describes an
+// invocation of method CopyLSIFDataFromUpload on an instance of
+// MockLsifStore.
+type LsifStoreCopyLSIFDataFromUploadFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 uint32
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreCopyLSIFDataFromUploadFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreCopyLSIFDataFromUploadFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LsifStoreCopySCIPDocumentsFromUploadFunc describes the behavior when the
+// CopySCIPDocumentsFromUpload method of the parent MockLsifStore instance
+// is invoked.
+type LsifStoreCopySCIPDocumentsFromUploadFunc struct {
+	defaultHook func(context.Context, int, int, []string) (uint32, error)
+	hooks       []func(context.Context, int, int, []string) (uint32, error)
+	history     []LsifStoreCopySCIPDocumentsFromUploadFuncCall
+	mutex       sync.Mutex
+}
+
+// CopySCIPDocumentsFromUpload delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockLsifStore) CopySCIPDocumentsFromUpload(v0 context.Context, v1 int, v2 int, v3 []string) (uint32, error) {
+	r0, r1 := m.CopySCIPDocumentsFromUploadFunc.nextHook()(v0, v1, v2, v3)
+	m.CopySCIPDocumentsFromUploadFunc.appendCall(LsifStoreCopySCIPDocumentsFromUploadFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// CopySCIPDocumentsFromUpload method of the parent MockLsifStore instance
+// is invoked and the hook queue is empty.
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) SetDefaultHook(hook func(context.Context, int, int, []string) (uint32, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CopySCIPDocumentsFromUpload method of the parent MockLsifStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) PushHook(hook func(context.Context, int, int, []string) (uint32, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) SetDefaultReturn(r0 uint32, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int, []string) (uint32, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) PushReturn(r0 uint32, r1 error) {
+	f.PushHook(func(context.Context, int, int, []string) (uint32, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) nextHook() func(context.Context, int, int, []string) (uint32, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) appendCall(r0 LsifStoreCopySCIPDocumentsFromUploadFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// LsifStoreCopySCIPDocumentsFromUploadFuncCall objects describing the
+// invocations of this function.
+func (f *LsifStoreCopySCIPDocumentsFromUploadFunc) History() []LsifStoreCopySCIPDocumentsFromUploadFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreCopySCIPDocumentsFromUploadFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreCopySCIPDocumentsFromUploadFuncCall is an object that describes
+// an invocation of method CopySCIPDocumentsFromUpload on an instance of
+// MockLsifStore.
+type LsifStoreCopySCIPDocumentsFromUploadFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 uint32
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreCopySCIPDocumentsFromUploadFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreCopySCIPDocumentsFromUploadFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // LsifStoreDeleteLsifDataByUploadIdsFunc describes the behavior when the
 // DeleteLsifDataByUploadIds method of the parent MockLsifStore instance is
 // invoked.
@@ -12024,6 +12834,117 @@ func (c LsifStoreWriteResultChunksFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// LsifStoreWriteSymbolIndexFunc describes the behavior when the
+// WriteSymbolIndex method of the parent MockLsifStore instance is invoked.
+type LsifStoreWriteSymbolIndexFunc struct {
+	defaultHook func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error)
+	hooks       []func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error)
+	history     []LsifStoreWriteSymbolIndexFuncCall
+	mutex       sync.Mutex
+}
+
+// WriteSymbolIndex delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLsifStore) WriteSymbolIndex(v0 context.Context, v1 int, v2 chan lsifstore.IndexedSymbol) (uint32, error) {
+	r0, r1 := m.WriteSymbolIndexFunc.nextHook()(v0, v1, v2)
+	m.WriteSymbolIndexFunc.appendCall(LsifStoreWriteSymbolIndexFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the WriteSymbolIndex
+// method of the parent MockLsifStore instance is invoked and the hook queue
+// is empty.
+func (f *LsifStoreWriteSymbolIndexFunc) SetDefaultHook(hook func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// WriteSymbolIndex method of the parent MockLsifStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LsifStoreWriteSymbolIndexFunc) PushHook(hook func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LsifStoreWriteSymbolIndexFunc) SetDefaultReturn(r0 uint32, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LsifStoreWriteSymbolIndexFunc) PushReturn(r0 uint32, r1 error) {
+	f.PushHook(func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error) {
+		return r0, r1
+	})
+}
+
+func (f *LsifStoreWriteSymbolIndexFunc) nextHook() func(context.Context, int, chan lsifstore.IndexedSymbol) (uint32, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LsifStoreWriteSymbolIndexFunc) appendCall(r0 LsifStoreWriteSymbolIndexFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LsifStoreWriteSymbolIndexFuncCall objects
+// describing the invocations of this function.
+func (f *LsifStoreWriteSymbolIndexFunc) History() []LsifStoreWriteSymbolIndexFuncCall {
+	f.mutex.Lock()
+	history := make([]LsifStoreWriteSymbolIndexFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LsifStoreWriteSymbolIndexFuncCall is an object that describes an
+// invocation of method WriteSymbolIndex on an instance of MockLsifStore.
+type LsifStoreWriteSymbolIndexFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 chan lsifstore.IndexedSymbol
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 uint32
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LsifStoreWriteSymbolIndexFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LsifStoreWriteSymbolIndexFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockWorkerStore is a mock implementation of the Store interface (from the
 // package
 // github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store)
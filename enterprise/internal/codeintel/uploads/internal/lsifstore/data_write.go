@@ -219,6 +219,88 @@ func (s *store) WriteImplementations(ctx context.Context, bundleID int, monikerL
 	return s.writeMonikers(ctx, bundleID, "lsif_data_implementations", CurrentImplementationsSchemaVersion, monikerLocations, trace)
 }
 
+// IndexedSymbol is a symbol defined by an index that is made available to precise
+// symbol search via the codeintel_symbol_index table.
+type IndexedSymbol struct {
+	Scheme         string
+	Identifier     string
+	Name           string
+	Kind           string
+	PackageManager string
+	PackageName    string
+	PackageVersion string
+	Path           string
+	StartLine      int
+	StartCharacter int
+	EndLine        int
+	EndCharacter   int
+	Documentation  string
+}
+
+// WriteSymbolIndex is called (transactionally) from the precise-code-intel-worker.
+func (s *store) WriteSymbolIndex(ctx context.Context, bundleID int, symbols chan IndexedSymbol) (count uint32, err error) {
+	ctx, trace, endObservation := s.operations.writeSymbolIndex.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	inserter := func(inserter *batch.Inserter) error {
+		for v := range symbols {
+			if err := inserter.Insert(
+				ctx,
+				bundleID,
+				v.Scheme,
+				v.Identifier,
+				v.Name,
+				v.Kind,
+				v.PackageManager,
+				v.PackageName,
+				v.PackageVersion,
+				v.Path,
+				v.StartLine,
+				v.StartCharacter,
+				v.EndLine,
+				v.EndCharacter,
+				v.Documentation,
+			); err != nil {
+				return err
+			}
+
+			atomic.AddUint32(&count, 1)
+		}
+
+		return nil
+	}
+
+	if err := withBatchInserter(
+		ctx,
+		s.db.Handle(),
+		"codeintel_symbol_index",
+		[]string{
+			"upload_id",
+			"scheme",
+			"symbol_name",
+			"name",
+			"kind",
+			"package_manager",
+			"package_name",
+			"package_version",
+			"path",
+			"start_line",
+			"start_character",
+			"end_line",
+			"end_character",
+			"documentation",
+		},
+		inserter,
+	); err != nil {
+		return 0, err
+	}
+	trace.Log(log.Int("numSymbols", int(count)))
+
+	return count, nil
+}
+
 func (s *store) writeMonikers(ctx context.Context, bundleID int, tableName string, version int, monikerLocations chan precise.MonikerLocations, trace observation.TraceLogger) (count uint32, err error) {
 	tx, err := s.db.Transact(ctx)
 	if err != nil {
@@ -0,0 +1,457 @@
+package lsifstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrBaseUploadNotLSIF occurs when an incremental upload refers to a base upload without LSIF data.
+var ErrBaseUploadNotLSIF = errors.New("base upload has no LSIF data")
+
+// CopyLSIFDataFromUpload completes the LSIF data of the given upload, which has been written for the
+// documents with the given paths only, with the LSIF data of every other document of the given base
+// upload. This method must be called in the transaction that wrote the upload's own LSIF data.
+//
+// The documents, result chunks, moniker locations, and indexed symbols of the base upload are copied
+// in the database without being decoded, except for the rows that the upload's own data overlaps:
+//
+//   - The result chunks of the upload are re-hashed into the result chunks of the base upload and
+//     merged into them. Identifiers of the upload must not collide with identifiers of the base upload.
+//   - The moniker locations of the identifiers attached to the base upload's version of the given
+//     documents, and of the identifiers also written for the upload, are merged with the locations
+//     of the upload after dropping the base upload's locations within the given documents.
+//
+// The indexed symbols of the base upload are matched against the given paths prefixed by the given
+// root. This method returns the number of documents copied from the base upload.
+func (s *store) CopyLSIFDataFromUpload(ctx context.Context, uploadID, baseUploadID int, root string, excludedPaths []string) (_ uint32, err error) {
+	ctx, trace, endObservation := s.operations.copyLSIFDataFromUpload.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
+		otlog.Int("uploadID", uploadID),
+		otlog.Int("baseUploadID", baseUploadID),
+		otlog.Int("numExcludedPaths", len(excludedPaths)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	numResultChunks, ok, err := basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(numResultChunksQuery, baseUploadID)))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrBaseUploadNotLSIF
+	}
+
+	if excludedPaths == nil {
+		excludedPaths = []string{}
+	}
+	excludedSet := make(map[string]struct{}, len(excludedPaths))
+	for _, path := range excludedPaths {
+		excludedSet[path] = struct{}{}
+	}
+
+	count, _, err := basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(copyDocumentsFromUploadQuery, uploadID, baseUploadID, pq.Array(excludedPaths))))
+	if err != nil {
+		return 0, err
+	}
+	trace.Log(otlog.Int("numCopiedDocuments", count))
+
+	if err := s.mergeResultChunksFromUpload(ctx, uploadID, baseUploadID, numResultChunks); err != nil {
+		return 0, err
+	}
+
+	identifiers, err := s.documentMonikerIdentifiers(ctx, baseUploadID, excludedPaths)
+	if err != nil {
+		return 0, err
+	}
+	trace.Log(otlog.Int("numMergedIdentifiers", len(identifiers)))
+
+	for _, table := range []struct {
+		name    string
+		version int
+	}{
+		{"lsif_data_definitions", CurrentDefinitionsSchemaVersion},
+		{"lsif_data_references", CurrentReferencesSchemaVersion},
+		{"lsif_data_implementations", CurrentImplementationsSchemaVersion},
+	} {
+		if err := s.mergeMonikerLocationsFromUpload(ctx, uploadID, baseUploadID, table.name, table.version, identifiers, excludedSet, trace); err != nil {
+			return 0, err
+		}
+	}
+
+	excludedSymbolPaths := make([]string, 0, len(excludedPaths))
+	for _, path := range excludedPaths {
+		excludedSymbolPaths = append(excludedSymbolPaths, root+path)
+	}
+	if err := s.db.Exec(ctx, sqlf.Sprintf(copySymbolIndexFromUploadQuery, uploadID, baseUploadID, pq.Array(excludedSymbolPaths))); err != nil {
+		return 0, err
+	}
+
+	return uint32(count), nil
+}
+
+const numResultChunksQuery = `
+SELECT num_result_chunks FROM lsif_data_metadata WHERE dump_id = %s
+`
+
+const copyDocumentsFromUploadQuery = `
+WITH copied_documents AS (
+	INSERT INTO lsif_data_documents (dump_id, schema_version, path, data, ranges, hovers, monikers, packages, diagnostics, num_diagnostics)
+	SELECT %s, d.schema_version, d.path, d.data, d.ranges, d.hovers, d.monikers, d.packages, d.diagnostics, d.num_diagnostics
+	FROM lsif_data_documents d
+	WHERE
+		d.dump_id = %s AND
+		NOT (d.path = ANY(%s))
+	RETURNING 1
+)
+SELECT COUNT(*) FROM copied_documents
+`
+
+const copySymbolIndexFromUploadQuery = `
+INSERT INTO codeintel_symbol_index (
+	upload_id,
+	scheme,
+	symbol_name,
+	name,
+	kind,
+	package_manager,
+	package_name,
+	package_version,
+	path,
+	start_line,
+	start_character,
+	end_line,
+	end_character,
+	documentation
+)
+SELECT
+	%s,
+	si.scheme,
+	si.symbol_name,
+	si.name,
+	si.kind,
+	si.package_manager,
+	si.package_name,
+	si.package_version,
+	si.path,
+	si.start_line,
+	si.start_character,
+	si.end_line,
+	si.end_character,
+	si.documentation
+FROM codeintel_symbol_index si
+WHERE
+	si.upload_id = %s AND
+	NOT (si.path = ANY(%s))
+`
+
+// mergeResultChunksFromUpload re-hashes the result chunks of the given upload into the given number of
+// result chunks of the base upload, merges them with the base upload's result chunks at the same index,
+// and copies every other result chunk of the base upload.
+func (s *store) mergeResultChunksFromUpload(ctx context.Context, uploadID, baseUploadID, numResultChunks int) error {
+	resultChunks := map[int]precise.ResultChunkData{}
+	addResultChunk := func(resultChunk precise.ResultChunkData) {
+		for resultID, documentIDRangeIDs := range resultChunk.DocumentIDRangeIDs {
+			idx := precise.HashKey(resultID, numResultChunks)
+
+			target, ok := resultChunks[idx]
+			if !ok {
+				target = precise.ResultChunkData{
+					DocumentPaths:      map[precise.ID]string{},
+					DocumentIDRangeIDs: map[precise.ID][]precise.DocumentIDRangeID{},
+				}
+				resultChunks[idx] = target
+			}
+
+			target.DocumentIDRangeIDs[resultID] = append(target.DocumentIDRangeIDs[resultID], documentIDRangeIDs...)
+			for _, documentIDRangeID := range documentIDRangeIDs {
+				target.DocumentPaths[documentIDRangeID.DocumentID] = resultChunk.DocumentPaths[documentIDRangeID.DocumentID]
+			}
+		}
+	}
+
+	if err := runQuery(ctx, s.db, sqlf.Sprintf(resultChunksQuery, uploadID), s.resultChunkScanner(addResultChunk)); err != nil {
+		return err
+	}
+
+	indexes := make([]int, 0, len(resultChunks))
+	for idx := range resultChunks {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	if err := runQuery(ctx, s.db, sqlf.Sprintf(baseResultChunksQuery, baseUploadID, pq.Array(indexes)), s.resultChunkScanner(addResultChunk)); err != nil {
+		return err
+	}
+
+	if err := s.db.Exec(ctx, sqlf.Sprintf(deleteResultChunksQuery, uploadID)); err != nil {
+		return err
+	}
+
+	// The inserter function is invoked concurrently, so rows are distributed through a channel
+	ch := make(chan int, len(indexes))
+	for _, idx := range indexes {
+		ch <- idx
+	}
+	close(ch)
+
+	if err := withBatchInserter(ctx, s.db.Handle(), "lsif_data_result_chunks", []string{"dump_id", "idx", "data"}, func(inserter *batch.Inserter) error {
+		for idx := range ch {
+			data, err := s.serializer.MarshalResultChunkData(resultChunks[idx])
+			if err != nil {
+				return err
+			}
+
+			if err := inserter.Insert(ctx, uploadID, idx, data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := s.db.Exec(ctx, sqlf.Sprintf(copyResultChunksFromUploadQuery, uploadID, baseUploadID, pq.Array(indexes))); err != nil {
+		return err
+	}
+
+	return s.db.Exec(ctx, sqlf.Sprintf(updateNumResultChunksQuery, numResultChunks, uploadID))
+}
+
+// resultChunkScanner returns a function that decodes a result chunk row and invokes the given function
+// with the decoded result chunk.
+func (s *store) resultChunkScanner(f func(resultChunk precise.ResultChunkData)) func(dbs dbutil.Scanner) error {
+	return func(dbs dbutil.Scanner) error {
+		var rawData []byte
+		if err := dbs.Scan(&rawData); err != nil {
+			return err
+		}
+
+		resultChunk, err := s.serializer.UnmarshalResultChunkData(rawData)
+		if err != nil {
+			return err
+		}
+
+		f(resultChunk)
+		return nil
+	}
+}
+
+const resultChunksQuery = `
+SELECT data FROM lsif_data_result_chunks WHERE dump_id = %s
+`
+
+const baseResultChunksQuery = `
+SELECT data FROM lsif_data_result_chunks WHERE dump_id = %s AND idx = ANY(%s)
+`
+
+const deleteResultChunksQuery = `
+DELETE FROM lsif_data_result_chunks WHERE dump_id = %s
+`
+
+const copyResultChunksFromUploadQuery = `
+INSERT INTO lsif_data_result_chunks (dump_id, idx, data)
+SELECT %s, rc.idx, rc.data
+FROM lsif_data_result_chunks rc
+WHERE
+	rc.dump_id = %s AND
+	NOT (rc.idx = ANY(%s))
+`
+
+const updateNumResultChunksQuery = `
+UPDATE lsif_data_metadata SET num_result_chunks = %s WHERE dump_id = %s
+`
+
+// documentMonikerIdentifiers returns the identifiers of the monikers attached to the documents of the
+// given upload with the given paths.
+func (s *store) documentMonikerIdentifiers(ctx context.Context, uploadID int, paths []string) ([]string, error) {
+	identifiers := map[string]struct{}{}
+	if err := runQuery(ctx, s.db, sqlf.Sprintf(documentMonikersQuery, uploadID, pq.Array(paths)), func(dbs dbutil.Scanner) error {
+		var rawMonikers []byte
+		if err := dbs.Scan(&rawMonikers); err != nil {
+			return err
+		}
+
+		var monikers map[precise.ID]precise.MonikerData
+		if err := s.serializer.decode(rawMonikers, &monikers); err != nil {
+			return err
+		}
+
+		for _, moniker := range monikers {
+			identifiers[moniker.Identifier] = struct{}{}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	flattened := make([]string, 0, len(identifiers))
+	for identifier := range identifiers {
+		flattened = append(flattened, identifier)
+	}
+	sort.Strings(flattened)
+
+	return flattened, nil
+}
+
+const documentMonikersQuery = `
+SELECT monikers FROM lsif_data_documents WHERE dump_id = %s AND path = ANY(%s) AND monikers IS NOT NULL
+`
+
+// mergeMonikerLocationsFromUpload merges the moniker locations of the given base upload stored in the
+// given table with the given identifiers, or with an identifier also written for the given upload, into
+// the locations of the given upload. The locations of the base upload within the given excluded paths
+// are dropped. Every other row of the base upload is copied as-is.
+func (s *store) mergeMonikerLocationsFromUpload(
+	ctx context.Context,
+	uploadID, baseUploadID int,
+	tableName string,
+	version int,
+	identifiers []string,
+	excludedPaths map[string]struct{},
+	trace observation.TraceLogger,
+) error {
+	var merged []precise.MonikerLocations
+	if err := runQuery(ctx, s.db, sqlf.Sprintf(
+		overlappingMonikerLocationsQuery,
+		sqlf.Sprintf(tableName),
+		sqlf.Sprintf(tableName),
+		uploadID,
+		baseUploadID,
+		pq.Array(identifiers),
+	), func(dbs dbutil.Scanner) error {
+		var scheme, identifier string
+		var rawData, rawBaseData []byte
+		if err := dbs.Scan(&scheme, &identifier, &rawData, &rawBaseData); err != nil {
+			return err
+		}
+
+		var locations []precise.LocationData
+		if rawData != nil {
+			var err error
+			if locations, err = s.serializer.UnmarshalLocations(rawData); err != nil {
+				return err
+			}
+		}
+
+		baseLocations, err := s.serializer.UnmarshalLocations(rawBaseData)
+		if err != nil {
+			return err
+		}
+		for _, location := range baseLocations {
+			if _, ok := excludedPaths[location.URI]; !ok {
+				locations = append(locations, location)
+			}
+		}
+
+		sortLocations(locations)
+		merged = append(merged, precise.MonikerLocations{Scheme: scheme, Identifier: identifier, Locations: locations})
+		return nil
+	}); err != nil {
+		return err
+	}
+	trace.Log(otlog.Int("numMerged_"+tableName, len(merged)))
+
+	schemes := make([]string, 0, len(merged))
+	mergedIdentifiers := make([]string, 0, len(merged))
+	for _, v := range merged {
+		schemes = append(schemes, v.Scheme)
+		mergedIdentifiers = append(mergedIdentifiers, v.Identifier)
+	}
+	if err := s.db.Exec(ctx, sqlf.Sprintf(
+		deleteMonikerLocationsQuery,
+		sqlf.Sprintf(tableName),
+		uploadID,
+		pq.Array(schemes),
+		pq.Array(mergedIdentifiers),
+	)); err != nil {
+		return err
+	}
+
+	// The inserter function is invoked concurrently, so rows are distributed through a channel
+	ch := make(chan precise.MonikerLocations, len(merged))
+	for _, v := range merged {
+		if len(v.Locations) > 0 {
+			ch <- v
+		}
+	}
+	close(ch)
+
+	if err := withBatchInserter(ctx, s.db.Handle(), tableName, []string{"dump_id", "schema_version", "scheme", "identifier", "data", "num_locations"}, func(inserter *batch.Inserter) error {
+		for v := range ch {
+			data, err := s.serializer.MarshalLocations(v.Locations)
+			if err != nil {
+				return err
+			}
+
+			if err := inserter.Insert(ctx, uploadID, version, v.Scheme, v.Identifier, data, len(v.Locations)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Rows overlapping with the rows of the upload were merged above, so they are skipped on conflict
+	return s.db.Exec(ctx, sqlf.Sprintf(
+		copyMonikerLocationsFromUploadQuery,
+		sqlf.Sprintf(tableName),
+		uploadID,
+		sqlf.Sprintf(tableName),
+		baseUploadID,
+		pq.Array(identifiers),
+	))
+}
+
+const overlappingMonikerLocationsQuery = `
+SELECT b.scheme, b.identifier, u.data, b.data
+FROM %s b
+LEFT JOIN %s u ON u.dump_id = %s AND u.scheme = b.scheme AND u.identifier = b.identifier
+WHERE
+	b.dump_id = %s AND
+	(b.identifier = ANY(%s) OR u.dump_id IS NOT NULL)
+`
+
+const deleteMonikerLocationsQuery = `
+DELETE FROM %s
+WHERE
+	dump_id = %s AND
+	(scheme, identifier) IN (SELECT * FROM unnest(%s::text[], %s::text[]))
+`
+
+const copyMonikerLocationsFromUploadQuery = `
+INSERT INTO %s (dump_id, schema_version, scheme, identifier, data, num_locations)
+SELECT %s, b.schema_version, b.scheme, b.identifier, b.data, b.num_locations
+FROM %s b
+WHERE
+	b.dump_id = %s AND
+	NOT (b.identifier = ANY(%s))
+ON CONFLICT DO NOTHING
+`
+
+// sortLocations sorts the given locations by document path, then by their offset within the document.
+func sortLocations(locations []precise.LocationData) {
+	sort.Slice(locations, func(i, j int) bool {
+		if cmp := strings.Compare(locations[i].URI, locations[j].URI); cmp != 0 {
+			return cmp < 0
+		}
+		if locations[i].StartLine != locations[j].StartLine {
+			return locations[i].StartLine < locations[j].StartLine
+		}
+
+		return locations[i].StartCharacter < locations[j].StartCharacter
+	})
+}
@@ -0,0 +1,206 @@
+package lsifstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestCopyLSIFDataFromUpload(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	store := New(codeIntelDB, &observation.TestContext)
+	ctx := context.Background()
+
+	monikers := map[precise.ID]precise.MonikerData{
+		"10": {Kind: precise.Export, Scheme: "scip-go", Identifier: testIdentifier},
+	}
+
+	// The base upload defines the symbol in util.go, which is changed by the incremental upload
+	writeTestLSIFData(t, store, 24, 1,
+		[]precise.KeyedDocumentData{
+			{Path: "main.go", Document: precise.DocumentData{Ranges: map[precise.ID]precise.RangeData{"1": {StartLine: 5, ReferenceResultID: "3", MonikerIDs: []precise.ID{"10"}}}, Monikers: monikers}},
+			{Path: "util.go", Document: precise.DocumentData{Ranges: map[precise.ID]precise.RangeData{"2": {StartLine: 3, DefinitionResultID: "4", ReferenceResultID: "3", MonikerIDs: []precise.ID{"10"}}}, Monikers: monikers}},
+		},
+		precise.ResultChunkData{
+			DocumentPaths: map[precise.ID]string{"100": "main.go", "101": "util.go"},
+			DocumentIDRangeIDs: map[precise.ID][]precise.DocumentIDRangeID{
+				"3": {{DocumentID: "100", RangeID: "1"}, {DocumentID: "101", RangeID: "2"}},
+				"4": {{DocumentID: "101", RangeID: "2"}},
+			},
+		},
+		[]precise.LocationData{{URI: "util.go", StartLine: 3}},
+		[]precise.LocationData{{URI: "main.go", StartLine: 5}, {URI: "util.go", StartLine: 3}},
+	)
+
+	// The incremental upload moves the definition within util.go
+	writeTestLSIFData(t, store, 25, 1,
+		[]precise.KeyedDocumentData{
+			{Path: "util.go", Document: precise.DocumentData{Ranges: map[precise.ID]precise.RangeData{"25:2": {StartLine: 7, DefinitionResultID: "25:4", ReferenceResultID: "25:3", MonikerIDs: []precise.ID{"25:10"}}}, Monikers: map[precise.ID]precise.MonikerData{"25:10": monikers["10"]}}},
+		},
+		precise.ResultChunkData{
+			DocumentPaths: map[precise.ID]string{"25:101": "util.go"},
+			DocumentIDRangeIDs: map[precise.ID][]precise.DocumentIDRangeID{
+				"25:3": {{DocumentID: "25:101", RangeID: "25:2"}},
+				"25:4": {{DocumentID: "25:101", RangeID: "25:2"}},
+			},
+		},
+		[]precise.LocationData{{URI: "util.go", StartLine: 7}},
+		[]precise.LocationData{{URI: "util.go", StartLine: 7}},
+	)
+
+	if _, err := codeIntelDB.ExecContext(ctx, `
+		INSERT INTO codeintel_symbol_index (upload_id, scheme, symbol_name, name, kind, package_manager, package_name, package_version, path, start_line, start_character, end_line, end_character, documentation)
+		VALUES
+			(24, 'scip-go', 'main', 'main', 'method', '', '', '', 'src/main.go', 1, 0, 1, 4, ''),
+			(24, 'scip-go', 'Render', 'Render', 'method', '', '', '', 'src/util.go', 3, 0, 3, 6, '')
+	`); err != nil {
+		t.Fatalf("unexpected error setting up test: %s", err)
+	}
+
+	tx, err := store.Transact(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %s", err)
+	}
+	count, err := tx.CopyLSIFDataFromUpload(ctx, 25, 24, "src/", []string{"util.go"})
+	if err != nil {
+		t.Fatalf("failed to copy LSIF data: %s", err)
+	} else if expected := uint32(1); count != expected {
+		t.Fatalf("unexpected number of copied documents. want=%d have=%d", expected, count)
+	}
+	if err := tx.Done(nil); err != nil {
+		t.Fatalf("failed to commit transaction: %s", err)
+	}
+
+	paths, err := basestore.ScanStrings(codeIntelDB.QueryContext(ctx, `SELECT path FROM lsif_data_documents WHERE dump_id = 25 ORDER BY path`))
+	if err != nil {
+		t.Fatalf("failed to query documents: %s", err)
+	}
+	if diff := cmp.Diff([]string{"main.go", "util.go"}, paths); diff != "" {
+		t.Errorf("unexpected document paths (-want +got):\n%s", diff)
+	}
+
+	serializer := NewSerializer()
+
+	rawResultChunks, err := scanTestPayloads(codeIntelDB.QueryContext(ctx, `SELECT data FROM lsif_data_result_chunks WHERE dump_id = 25`))
+	if err != nil {
+		t.Fatalf("failed to query result chunks: %s", err)
+	} else if len(rawResultChunks) != 1 {
+		t.Fatalf("unexpected number of result chunks. want=%d have=%d", 1, len(rawResultChunks))
+	}
+	resultChunk, err := serializer.UnmarshalResultChunkData(rawResultChunks[0])
+	if err != nil {
+		t.Fatalf("failed to decode result chunk: %s", err)
+	}
+	expectedResultChunk := precise.ResultChunkData{
+		DocumentPaths: map[precise.ID]string{"100": "main.go", "101": "util.go", "25:101": "util.go"},
+		DocumentIDRangeIDs: map[precise.ID][]precise.DocumentIDRangeID{
+			"3":    {{DocumentID: "100", RangeID: "1"}, {DocumentID: "101", RangeID: "2"}},
+			"4":    {{DocumentID: "101", RangeID: "2"}},
+			"25:3": {{DocumentID: "25:101", RangeID: "25:2"}},
+			"25:4": {{DocumentID: "25:101", RangeID: "25:2"}},
+		},
+	}
+	if diff := cmp.Diff(expectedResultChunk, resultChunk); diff != "" {
+		t.Errorf("unexpected result chunk (-want +got):\n%s", diff)
+	}
+
+	for tableName, expectedLocations := range map[string][]precise.LocationData{
+		"lsif_data_definitions": {{URI: "util.go", StartLine: 7}},
+		"lsif_data_references":  {{URI: "main.go", StartLine: 5}, {URI: "util.go", StartLine: 7}},
+	} {
+		rawLocations, err := scanTestPayloads(codeIntelDB.QueryContext(ctx, `SELECT data FROM `+tableName+` WHERE dump_id = 25`))
+		if err != nil {
+			t.Fatalf("failed to query %s: %s", tableName, err)
+		} else if len(rawLocations) != 1 {
+			t.Fatalf("unexpected number of %s rows. want=%d have=%d", tableName, 1, len(rawLocations))
+		}
+		locations, err := serializer.UnmarshalLocations(rawLocations[0])
+		if err != nil {
+			t.Fatalf("failed to decode locations: %s", err)
+		}
+		if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+			t.Errorf("unexpected %s locations (-want +got):\n%s", tableName, diff)
+		}
+	}
+
+	symbolPaths, err := basestore.ScanStrings(codeIntelDB.QueryContext(ctx, `SELECT path FROM codeintel_symbol_index WHERE upload_id = 25`))
+	if err != nil {
+		t.Fatalf("failed to query indexed symbols: %s", err)
+	}
+	if diff := cmp.Diff([]string{"src/main.go"}, symbolPaths); diff != "" {
+		t.Errorf("unexpected indexed symbol paths (-want +got):\n%s", diff)
+	}
+}
+
+func TestCopyLSIFDataFromUploadNotLSIF(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	store := New(codeIntelDB, &observation.TestContext)
+
+	if _, err := store.CopyLSIFDataFromUpload(context.Background(), 25, 24, "", nil); !errors.Is(err, ErrBaseUploadNotLSIF) {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrBaseUploadNotLSIF, err)
+	}
+}
+
+const testIdentifier = "scip-go gomod example v1 `example`/Render()."
+
+var scanTestPayloads = basestore.NewSliceScanner(basestore.ScanAny[[]byte])
+
+// writeTestLSIFData writes the given documents, result chunk, and the definition and reference
+// locations of a single moniker as the LSIF data of the given upload.
+func writeTestLSIFData(
+	t *testing.T,
+	store LsifStore,
+	uploadID int,
+	numResultChunks int,
+	documents []precise.KeyedDocumentData,
+	resultChunk precise.ResultChunkData,
+	definitions []precise.LocationData,
+	references []precise.LocationData,
+) {
+	ctx := context.Background()
+
+	if err := store.WriteMeta(ctx, uploadID, precise.MetaData{NumResultChunks: numResultChunks}); err != nil {
+		t.Fatalf("failed to write meta: %s", err)
+	}
+
+	documentsCh := make(chan precise.KeyedDocumentData, len(documents))
+	for _, document := range documents {
+		documentsCh <- document
+	}
+	close(documentsCh)
+	if _, err := store.WriteDocuments(ctx, uploadID, documentsCh); err != nil {
+		t.Fatalf("failed to write documents: %s", err)
+	}
+
+	resultChunksCh := make(chan precise.IndexedResultChunkData, 1)
+	resultChunksCh <- precise.IndexedResultChunkData{Index: 0, ResultChunk: resultChunk}
+	close(resultChunksCh)
+	if _, err := store.WriteResultChunks(ctx, uploadID, resultChunksCh); err != nil {
+		t.Fatalf("failed to write result chunks: %s", err)
+	}
+
+	definitionsCh := make(chan precise.MonikerLocations, 1)
+	definitionsCh <- precise.MonikerLocations{Kind: "export", Scheme: "scip-go", Identifier: testIdentifier, Locations: definitions}
+	close(definitionsCh)
+	if _, err := store.WriteDefinitions(ctx, uploadID, definitionsCh); err != nil {
+		t.Fatalf("failed to write definitions: %s", err)
+	}
+
+	referencesCh := make(chan precise.MonikerLocations, 1)
+	referencesCh <- precise.MonikerLocations{Kind: "export", Scheme: "scip-go", Identifier: testIdentifier, Locations: references}
+	close(referencesCh)
+	if _, err := store.WriteReferences(ctx, uploadID, referencesCh); err != nil {
+		t.Fatalf("failed to write references: %s", err)
+	}
+}
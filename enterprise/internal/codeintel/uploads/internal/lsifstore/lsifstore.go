@@ -20,6 +20,8 @@ type LsifStore interface {
 	InsertMetadata(ctx context.Context, uploadID int, meta ProcessedMetadata) error
 	NewSymbolWriter(ctx context.Context, uploadID int) (SymbolWriter, error)
 	InsertSCIPDocument(ctx context.Context, uploadID int, documentPath string, hash []byte, rawSCIPPayload []byte) (int, error)
+	CopySCIPDocumentsFromUpload(ctx context.Context, uploadID, baseUploadID int, excludedPaths []string) (uint32, error)
+	CopyLSIFDataFromUpload(ctx context.Context, uploadID, baseUploadID int, root string, excludedPaths []string) (uint32, error)
 
 	WriteMeta(ctx context.Context, bundleID int, meta precise.MetaData) error
 	WriteDocuments(ctx context.Context, bundleID int, documents chan precise.KeyedDocumentData) (count uint32, err error)
@@ -27,6 +29,7 @@ type LsifStore interface {
 	WriteDefinitions(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) (count uint32, err error)
 	WriteReferences(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) (count uint32, err error)
 	WriteImplementations(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) (count uint32, err error)
+	WriteSymbolIndex(ctx context.Context, bundleID int, symbols chan IndexedSymbol) (count uint32, err error)
 
 	IDsWithMeta(ctx context.Context, ids []int) ([]int, error)
 	ReconcileCandidates(ctx context.Context, batchSize int) ([]int, error)
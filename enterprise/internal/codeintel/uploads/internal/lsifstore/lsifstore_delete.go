@@ -27,6 +27,7 @@ var tableNames = map[string]string{
 	"codeintel_scip_metadata":                   "upload_id",
 	"codeintel_scip_document_lookup":            "upload_id",
 	"codeintel_scip_symbols":                    "upload_id",
+	"codeintel_symbol_index":                    "upload_id",
 }
 
 // DeleteLsifDataByUploadIds deletes LSIF data by UploadIds from the lsif database.
@@ -8,21 +8,24 @@ import (
 )
 
 type operations struct {
-	deleteLsifDataByUploadIds *observation.Operation
-	idsWithMeta               *observation.Operation
-	reconcileCandidates       *observation.Operation
-	getUploadDocumentsForPath *observation.Operation
-	scanDocuments             *observation.Operation
-	scanResultChunks          *observation.Operation
-	scanLocations             *observation.Operation
-	insertMetadata            *observation.Operation
-	insertSCIPDocument        *observation.Operation
-	writeMeta                 *observation.Operation
-	writeDocuments            *observation.Operation
-	writeResultChunks         *observation.Operation
-	writeDefinitions          *observation.Operation
-	writeReferences           *observation.Operation
-	writeImplementations      *observation.Operation
+	deleteLsifDataByUploadIds   *observation.Operation
+	idsWithMeta                 *observation.Operation
+	reconcileCandidates         *observation.Operation
+	getUploadDocumentsForPath   *observation.Operation
+	scanDocuments               *observation.Operation
+	scanResultChunks            *observation.Operation
+	scanLocations               *observation.Operation
+	insertMetadata              *observation.Operation
+	insertSCIPDocument          *observation.Operation
+	copySCIPDocumentsFromUpload *observation.Operation
+	copyLSIFDataFromUpload      *observation.Operation
+	writeMeta                   *observation.Operation
+	writeDocuments              *observation.Operation
+	writeResultChunks           *observation.Operation
+	writeDefinitions            *observation.Operation
+	writeReferences             *observation.Operation
+	writeImplementations        *observation.Operation
+	writeSymbolIndex            *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -42,20 +45,23 @@ func newOperations(observationContext *observation.Context) *operations {
 	}
 
 	return &operations{
-		deleteLsifDataByUploadIds: op("DeleteLsifDataByUploadIds"),
-		idsWithMeta:               op("IDsWithMeta"),
-		reconcileCandidates:       op("ReconcileCandidates"),
-		getUploadDocumentsForPath: op("GetUploadDocumentsForPath"),
-		scanDocuments:             op("ScanDocuments"),
-		scanResultChunks:          op("ScanResultChunks"),
-		scanLocations:             op("ScanLocations"),
-		insertMetadata:            op("InsertMetadata"),
-		insertSCIPDocument:        op("InsertSCIPDocument"),
-		writeMeta:                 op("WriteMeta"),
-		writeDocuments:            op("WriteDocuments"),
-		writeResultChunks:         op("WriteResultChunks"),
-		writeDefinitions:          op("WriteDefinitions"),
-		writeReferences:           op("WriteReferences"),
-		writeImplementations:      op("WriteImplementations"),
+		deleteLsifDataByUploadIds:   op("DeleteLsifDataByUploadIds"),
+		idsWithMeta:                 op("IDsWithMeta"),
+		reconcileCandidates:         op("ReconcileCandidates"),
+		getUploadDocumentsForPath:   op("GetUploadDocumentsForPath"),
+		scanDocuments:               op("ScanDocuments"),
+		scanResultChunks:            op("ScanResultChunks"),
+		scanLocations:               op("ScanLocations"),
+		insertMetadata:              op("InsertMetadata"),
+		insertSCIPDocument:          op("InsertSCIPDocument"),
+		copySCIPDocumentsFromUpload: op("CopySCIPDocumentsFromUpload"),
+		copyLSIFDataFromUpload:      op("CopyLSIFDataFromUpload"),
+		writeMeta:                   op("WriteMeta"),
+		writeDocuments:              op("WriteDocuments"),
+		writeResultChunks:           op("WriteResultChunks"),
+		writeDefinitions:            op("WriteDefinitions"),
+		writeReferences:             op("WriteReferences"),
+		writeImplementations:        op("WriteImplementations"),
+		writeSymbolIndex:            op("WriteSymbolIndex"),
 	}
 }
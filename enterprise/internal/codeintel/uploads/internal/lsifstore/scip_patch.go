@@ -0,0 +1,99 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrBaseUploadNotSCIP occurs when an incremental upload refers to a base upload without SCIP data.
+var ErrBaseUploadNotSCIP = errors.New("base upload has no SCIP data")
+
+// CopySCIPDocumentsFromUpload adds every document of the given base upload, except for those with the
+// given paths, to the given upload. Document payloads are shared by reference (via their payload hash)
+// and are not duplicated; only the path lookup and symbol rows are written for the new upload. This
+// method returns the number of documents copied from the base upload.
+func (s *store) CopySCIPDocumentsFromUpload(ctx context.Context, uploadID, baseUploadID int, excludedPaths []string) (_ uint32, err error) {
+	ctx, _, endObservation := s.operations.copySCIPDocumentsFromUpload.With(ctx, &err, observation.Args{LogFields: []otlog.Field{
+		otlog.Int("uploadID", uploadID),
+		otlog.Int("baseUploadID", baseUploadID),
+		otlog.Int("numExcludedPaths", len(excludedPaths)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	isSCIP, _, err := basestore.ScanFirstBool(s.db.Query(ctx, sqlf.Sprintf(baseUploadIsSCIPQuery, baseUploadID)))
+	if err != nil {
+		return 0, err
+	}
+	if !isSCIP {
+		return 0, ErrBaseUploadNotSCIP
+	}
+
+	if excludedPaths == nil {
+		excludedPaths = []string{}
+	}
+
+	count, _, err := basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(
+		copySCIPDocumentsFromUploadQuery,
+		uploadID,
+		baseUploadID,
+		pq.Array(excludedPaths),
+		uploadID,
+		baseUploadID,
+		baseUploadID,
+	)))
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(count), nil
+}
+
+const baseUploadIsSCIPQuery = `
+SELECT EXISTS (SELECT 1 FROM codeintel_scip_metadata WHERE upload_id = %s)
+`
+
+const copySCIPDocumentsFromUploadQuery = `
+WITH
+copied_documents AS (
+	INSERT INTO codeintel_scip_document_lookup (upload_id, document_path, document_id)
+	SELECT %s, dl.document_path, dl.document_id
+	FROM codeintel_scip_document_lookup dl
+	WHERE
+		dl.upload_id = %s AND
+		NOT (dl.document_path = ANY(%s))
+	RETURNING id, document_path
+),
+copied_symbols AS (
+	INSERT INTO codeintel_scip_symbols (
+		upload_id,
+		symbol_name,
+		document_lookup_id,
+		schema_version,
+		definition_ranges,
+		reference_ranges,
+		implementation_ranges,
+		type_definition_ranges
+	)
+	SELECT
+		%s,
+		ss.symbol_name,
+		cd.id,
+		ss.schema_version,
+		ss.definition_ranges,
+		ss.reference_ranges,
+		ss.implementation_ranges,
+		ss.type_definition_ranges
+	FROM copied_documents cd
+	JOIN codeintel_scip_document_lookup dl ON dl.upload_id = %s AND dl.document_path = cd.document_path
+	JOIN codeintel_scip_symbols ss ON ss.upload_id = %s AND ss.document_lookup_id = dl.id
+	RETURNING 1
+)
+SELECT COUNT(*) FROM copied_documents
+`
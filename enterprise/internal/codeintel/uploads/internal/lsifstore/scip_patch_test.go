@@ -0,0 +1,92 @@
+package lsifstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	codeintelshared "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestCopySCIPDocumentsFromUpload(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	store := New(codeIntelDB, &observation.TestContext)
+	ctx := context.Background()
+
+	baseUploadID := 24
+	if err := store.InsertMetadata(ctx, baseUploadID, ProcessedMetadata{ToolName: "scip-test"}); err != nil {
+		t.Fatalf("failed to insert metadata: %s", err)
+	}
+
+	tx, err := store.Transact(ctx)
+	if err != nil {
+		t.Fatalf("failed to start transaction: %s", err)
+	}
+	symbolWriter, err := tx.NewSymbolWriter(ctx, baseUploadID)
+	if err != nil {
+		t.Fatalf("failed to write SCIP symbols: %s", err)
+	}
+	for path, hash := range map[string]string{
+		"internal/util.go":      "deadbeef",
+		"internal/util_test.go": "cafebabe",
+	} {
+		documentLookupID, err := tx.InsertSCIPDocument(ctx, baseUploadID, path, []byte(hash), []byte(path))
+		if err != nil {
+			t.Fatalf("failed to write SCIP document: %s", err)
+		}
+		if err := symbolWriter.WriteSCIPSymbols(ctx, documentLookupID, []types.InvertedRangeIndex{
+			{SymbolName: "foo.bar.ident", DefinitionRanges: []int32{3, 25, 3, 30}},
+		}); err != nil {
+			t.Fatalf("failed to write SCIP symbols: %s", err)
+		}
+	}
+	if _, err := symbolWriter.Flush(ctx); err != nil {
+		t.Fatalf("failed to write SCIP symbols: %s", err)
+	}
+	if err := tx.Done(nil); err != nil {
+		t.Fatalf("failed to commit transaction: %s", err)
+	}
+
+	count, err := store.CopySCIPDocumentsFromUpload(ctx, 25, baseUploadID, []string{"internal/util_test.go"})
+	if err != nil {
+		t.Fatalf("failed to copy SCIP documents: %s", err)
+	} else if expected := uint32(1); count != expected {
+		t.Fatalf("unexpected number of copied documents. want=%d have=%d", expected, count)
+	}
+
+	numDocuments, _, err := basestore.ScanFirstInt(codeIntelDB.Handle().QueryContext(ctx, `SELECT COUNT(*) FROM codeintel_scip_documents`))
+	if err != nil {
+		t.Fatalf("failed to query number of SCIP documents: %s", err)
+	} else if expected := 2; numDocuments != expected {
+		t.Fatalf("unexpected number of documents. want=%d have=%d", expected, numDocuments)
+	}
+
+	paths, err := basestore.ScanStrings(codeIntelDB.Handle().QueryContext(ctx, `
+		SELECT sid.document_path
+		FROM codeintel_scip_symbols ss
+		JOIN codeintel_scip_document_lookup sid ON sid.id = ss.document_lookup_id
+		WHERE ss.upload_id = 25
+	`))
+	if err != nil {
+		t.Fatalf("failed to query copied SCIP symbols: %s", err)
+	} else if len(paths) != 1 || paths[0] != "internal/util.go" {
+		t.Fatalf("unexpected paths of copied symbols. want=%v have=%v", []string{"internal/util.go"}, paths)
+	}
+}
+
+func TestCopySCIPDocumentsFromUploadNotSCIP(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(dbtest.NewDB(logger, t))
+	store := New(codeIntelDB, &observation.TestContext)
+
+	if _, err := store.CopySCIPDocumentsFromUpload(context.Background(), 25, 24, nil); !errors.Is(err, ErrBaseUploadNotSCIP) {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrBaseUploadNotSCIP, err)
+	}
+}
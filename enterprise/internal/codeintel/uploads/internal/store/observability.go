@@ -31,6 +31,7 @@ type operations struct {
 	repoName                                *observation.Operation
 	setRepositoriesForRetentionScan         *observation.Operation
 	hasRepository                           *observation.Operation
+	getRepositoryCoverage                   *observation.Operation
 
 	// Uploads
 	getUploads                           *observation.Operation
@@ -60,12 +61,15 @@ type operations struct {
 	// Dumps
 	findClosestDumps                   *observation.Operation
 	findClosestDumpsFromGraphFragment  *observation.Operation
+	findEphemeralDumps                 *observation.Operation
 	getDumpsWithDefinitionsForMonikers *observation.Operation
 	getDumpsByIDs                      *observation.Operation
 	deleteOverlappingDumps             *observation.Operation
+	getVisibleUploadDistances          *observation.Operation
 
 	// Packages
-	updatePackages *observation.Operation
+	updatePackages         *observation.Operation
+	copyPackagesFromUpload *observation.Operation
 
 	// References
 	updatePackageReferences *observation.Operation
@@ -118,6 +122,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		repoName:                                op("RepoName"),
 		setRepositoriesForRetentionScan:         op("SetRepositoriesForRetentionScan"),
 		hasRepository:                           op("HasRepository"),
+		getRepositoryCoverage:                   op("GetRepositoryCoverage"),
 
 		// Uploads
 		getUploads:                           op("GetUploads"),
@@ -148,12 +153,15 @@ func newOperations(observationContext *observation.Context) *operations {
 		// Dumps
 		findClosestDumps:                   op("FindClosestDumps"),
 		findClosestDumpsFromGraphFragment:  op("FindClosestDumpsFromGraphFragment"),
+		findEphemeralDumps:                 op("FindEphemeralDumps"),
 		getDumpsWithDefinitionsForMonikers: op("GetUploadsWithDefinitionsForMonikers"),
 		getDumpsByIDs:                      op("GetDumpsByIDs"),
 		deleteOverlappingDumps:             op("DeleteOverlappingDumps"),
+		getVisibleUploadDistances:          op("GetVisibleUploadDistances"),
 
 		// Packages
-		updatePackages: op("UpdatePackages"),
+		updatePackages:         op("UpdatePackages"),
+		copyPackagesFromUpload: op("CopyPackagesFromUpload"),
 
 		// References
 		updatePackageReferences: op("UpdatePackageReferences"),
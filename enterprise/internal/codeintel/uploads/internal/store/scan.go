@@ -39,6 +39,8 @@ func scanCompleteUpload(s dbutil.Scanner) (upload types.Upload, _ error) {
 		&upload.AssociatedIndexID,
 		&upload.Rank,
 		&upload.UncompressedSize,
+		&upload.Ephemeral,
+		&upload.BaseUploadID,
 	); err != nil {
 		return upload, err
 	}
@@ -83,6 +85,8 @@ func scanUploadWithCount(s dbutil.Scanner) (upload types.Upload, count int, err
 		&upload.AssociatedIndexID,
 		&upload.Rank,
 		&upload.UncompressedSize,
+		&upload.Ephemeral,
+		&upload.BaseUploadID,
 		&count,
 	); err != nil {
 		return upload, 0, err
@@ -41,6 +41,7 @@ type Store interface {
 	SetRepositoriesForRetentionScan(ctx context.Context, processDelay time.Duration, limit int) (_ []int, err error)
 	SetRepositoriesForRetentionScanWithTime(ctx context.Context, processDelay time.Duration, limit int, now time.Time) (_ []int, err error)
 	HasRepository(ctx context.Context, repositoryID int) (_ bool, err error)
+	GetRepositoryCoverage(ctx context.Context, opts shared.GetRepositoryCoverageOptions) (_ []shared.RepositoryCoverage, totalCount int, err error)
 
 	// Uploads
 	GetUploads(ctx context.Context, opts shared.GetUploadsOptions) (_ []types.Upload, _ int, err error)
@@ -71,12 +72,15 @@ type Store interface {
 	// Dumps
 	FindClosestDumps(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) (_ []types.Dump, err error)
 	FindClosestDumpsFromGraphFragment(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string, commitGraph *gitdomain.CommitGraph) (_ []types.Dump, err error)
+	FindEphemeralDumps(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) (_ []types.Dump, err error)
 	GetDumpsWithDefinitionsForMonikers(ctx context.Context, monikers []precise.QualifiedMonikerData) (_ []types.Dump, err error)
 	GetDumpsByIDs(ctx context.Context, ids []int) (_ []types.Dump, err error)
-	DeleteOverlappingDumps(ctx context.Context, repositoryID int, commit, root, indexer string) error
+	DeleteOverlappingDumps(ctx context.Context, repositoryID int, commit, root, indexer string, ephemeral bool) error
+	GetVisibleUploadDistances(ctx context.Context, repositoryID int, commit string) (_ map[int]int, err error)
 
 	// Packages
 	UpdatePackages(ctx context.Context, dumpID int, packages []precise.Package) (err error)
+	CopyPackagesFromUpload(ctx context.Context, uploadID, baseUploadID int) (err error)
 
 	// References
 	UpdatePackageReferences(ctx context.Context, dumpID int, references []precise.PackageReference) (err error)
@@ -183,6 +183,68 @@ FROM lsif_dumps_with_repository_name u
 WHERE u.id IN (%s) AND %s
 `
 
+// FindEphemeralDumps returns the set of ephemeral dumps uploaded for exactly the given repository and commit that can answer
+// queries for the given path and optional indexer. Ephemeral dumps do not participate in the commit graph, so they are never
+// returned by FindClosestDumps and are not visible from any other commit. See FindClosestDumps for the meaning of the remaining
+// parameters. The returned dumps are sorted in most-recently-finished order.
+func (s *store) FindEphemeralDumps(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) (_ []types.Dump, err error) {
+	ctx, trace, endObservation := s.operations.findEphemeralDumps.With(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", repositoryID),
+			log.String("commit", commit),
+			log.String("path", path),
+			log.Bool("rootMustEnclosePath", rootMustEnclosePath),
+			log.String("indexer", indexer),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	conds := makeFindClosestDumpConditions(path, rootMustEnclosePath, indexer)
+	query := sqlf.Sprintf(findEphemeralDumpsQuery, repositoryID, commit, sqlf.Join(conds, " AND "))
+
+	dumps, err := scanDumps(s.db.Query(ctx, query))
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(log.Int("numDumps", len(dumps)))
+
+	return dumps, nil
+}
+
+const findEphemeralDumpsQuery = `
+SELECT
+	u.id,
+	u.commit,
+	u.root,
+	EXISTS (` + visibleAtTipSubselectQuery + `) AS visible_at_tip,
+	u.uploaded_at,
+	u.state,
+	u.failure_message,
+	u.started_at,
+	u.finished_at,
+	u.process_after,
+	u.num_resets,
+	u.num_failures,
+	u.repository_id,
+	u.repository_name,
+	u.indexer,
+	u.indexer_version,
+	u.associated_index_id
+FROM lsif_dumps_with_repository_name u
+WHERE
+	u.id IN (
+		SELECT eu.id
+		FROM lsif_uploads eu
+		WHERE
+			eu.repository_id = %s AND
+			eu.commit = %s AND
+			eu.state = 'completed' AND
+			eu.ephemeral
+	) AND
+	%s
+ORDER BY u.finished_at DESC
+`
+
 // DefinitionDumpsLimit is the maximum number of records that can be returned from DefinitionDumps.
 var DefinitionDumpsLimit, _ = strconv.ParseInt(env.Get("PRECISE_CODE_INTEL_DEFINITION_DUMPS_LIMIT", "100", "The maximum number of dumps that can define the same package."), 10, 64)
 
@@ -234,6 +296,8 @@ ranked_uploads AS (
 	WHERE
 		-- Don't match deleted uploads
 		u.state = 'completed' AND
+		-- Ephemeral uploads only describe their own commit and never provide packages
+		NOT u.ephemeral AND
 		(p.scheme, p.manager, p.name, p.version) IN (%s) AND
 		%s -- authz conds
 ),
@@ -315,20 +379,22 @@ FROM lsif_dumps_with_repository_name u WHERE u.id IN (%s)
 `
 
 // DeleteOverlapapingDumps deletes all completed uploads for the given repository with the same
-// commit, root, and indexer. This is necessary to perform during conversions before changing
-// the state of a processing upload to completed as there is a unique index on these four columns.
-func (s *store) DeleteOverlappingDumps(ctx context.Context, repositoryID int, commit, root, indexer string) (err error) {
+// commit, root, indexer, and ephemerality. This is necessary to perform during conversions before
+// changing the state of a processing upload to completed as there is a unique index on these five
+// columns. An ephemeral upload never replaces a non-ephemeral upload (and vice versa).
+func (s *store) DeleteOverlappingDumps(ctx context.Context, repositoryID int, commit, root, indexer string, ephemeral bool) (err error) {
 	ctx, trace, endObservation := s.operations.deleteOverlappingDumps.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("repositoryID", repositoryID),
 		log.String("commit", commit),
 		log.String("root", root),
 		log.String("indexer", indexer),
+		log.Bool("ephemeral", ephemeral),
 	}})
 	defer endObservation(1, observation.Args{})
 
 	unset, _ := s.db.SetLocal(ctx, "codeintel.lsif_uploads_audit.reason", "upload overlapping with a newer upload")
 	defer unset(ctx)
-	count, _, err := basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(deleteOverlappingDumpsQuery, repositoryID, commit, root, indexer)))
+	count, _, err := basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(deleteOverlappingDumpsQuery, repositoryID, commit, root, indexer, ephemeral)))
 	if err != nil {
 		return err
 	}
@@ -347,7 +413,8 @@ candidates AS (
 		u.repository_id = %s AND
 		u.commit = %s AND
 		u.root = %s AND
-		u.indexer = %s
+		u.indexer = %s AND
+		u.ephemeral = %s
 
 	-- Lock these rows in a deterministic order so that we don't
 	-- deadlock with other processes updating the lsif_uploads table.
@@ -362,6 +429,31 @@ updated AS (
 SELECT COUNT(*) FROM updated
 `
 
+// GetVisibleUploadDistances returns a map from the identifier of each upload visible from the given
+// commit to the number of commits between the given commit and the upload's commit. The map is empty
+// if the commit is not (yet) known to the lsif_nearest_uploads tables.
+func (s *store) GetVisibleUploadDistances(ctx context.Context, repositoryID int, commit string) (_ map[int]int, err error) {
+	ctx, trace, endObservation := s.operations.getVisibleUploadDistances.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	distances, err := scanIntPairs(s.db.Query(ctx, sqlf.Sprintf(getVisibleUploadDistancesQuery, makeVisibleUploadCandidatesQuery(repositoryID, commit))))
+	if err != nil {
+		return nil, err
+	}
+	trace.Log(log.Int("numUploads", len(distances)))
+
+	return distances, nil
+}
+
+const getVisibleUploadDistancesQuery = `
+SELECT t.upload_id, MIN(t.distance)
+FROM (%s) t
+GROUP BY t.upload_id
+`
+
 func monikersToString(vs []precise.QualifiedMonikerData) string {
 	strs := make([]string, 0, len(vs))
 	for _, v := range vs {
@@ -162,6 +162,66 @@ func TestFindClosestDumps(t *testing.T) {
 	})
 }
 
+func TestFindEphemeralDumps(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(db, &observation.TestContext)
+
+	// This database has the following commit graph:
+	//
+	// [1] -- 2 -- [3]
+
+	insertUploads(t, db,
+		types.Upload{ID: 1, Commit: makeCommit(1)},
+		types.Upload{ID: 2, Commit: makeCommit(3), Ephemeral: true},
+		types.Upload{ID: 3, Commit: makeCommit(3), Root: "sub/", Ephemeral: true},
+		types.Upload{ID: 4, Commit: makeCommit(3), Ephemeral: true, State: "queued"},
+	)
+
+	graph := gitdomain.ParseCommitGraph([]string{
+		strings.Join([]string{makeCommit(3), makeCommit(2)}, " "),
+		strings.Join([]string{makeCommit(2), makeCommit(1)}, " "),
+		strings.Join([]string{makeCommit(1)}, " "),
+	})
+	if err := store.UpdateUploadsVisibleToCommits(context.Background(), 50, graph, nil, time.Hour, time.Hour, 0, time.Now()); err != nil {
+		t.Fatalf("unexpected error while updating visible uploads: %s", err)
+	}
+
+	// Ephemeral uploads are not part of the commit graph
+	testFindClosestDumps(t, store, []FindClosestDumpsTestCase{
+		{commit: makeCommit(2), file: "sub/file.ts", rootMustEnclosePath: true, anyOfIDs: []int{1}},
+		{commit: makeCommit(3), file: "sub/file.ts", rootMustEnclosePath: true, anyOfIDs: []int{1}},
+	})
+
+	testCases := []struct {
+		commit      string
+		path        string
+		expectedIDs []int
+	}{
+		{makeCommit(3), "sub/file.ts", []int{2, 3}},
+		{makeCommit(3), "file.ts", []int{2}},
+		{makeCommit(2), "sub/file.ts", nil},
+		{makeCommit(1), "sub/file.ts", nil},
+	}
+
+	for _, testCase := range testCases {
+		dumps, err := store.FindEphemeralDumps(context.Background(), 50, testCase.commit, testCase.path, true, "")
+		if err != nil {
+			t.Fatalf("unexpected error finding ephemeral dumps: %s", err)
+		}
+
+		var ids []int
+		for _, dump := range dumps {
+			ids = append(ids, dump.ID)
+		}
+		sort.Ints(ids)
+
+		if diff := cmp.Diff(testCase.expectedIDs, ids); diff != "" {
+			t.Errorf("unexpected dump ids for %s@%s (-want +got):\n%s", testCase.path, testCase.commit, diff)
+		}
+	}
+}
+
 func TestFindClosestDumpsAlternateCommitGraph(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := database.NewDB(logger, dbtest.NewDB(logger, t))
@@ -755,7 +815,7 @@ func TestDeleteOverlappingDumps(t *testing.T) {
 		Indexer: "lsif-go",
 	})
 
-	err := store.DeleteOverlappingDumps(context.Background(), 50, makeCommit(1), "cmd/", "lsif-go")
+	err := store.DeleteOverlappingDumps(context.Background(), 50, makeCommit(1), "cmd/", "lsif-go", false)
 	if err != nil {
 		t.Fatalf("unexpected error deleting dump: %s", err)
 	}
@@ -792,7 +852,7 @@ func TestDeleteOverlappingDumpsNoMatches(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		err := store.DeleteOverlappingDumps(context.Background(), 50, testCase.commit, testCase.root, testCase.indexer)
+		err := store.DeleteOverlappingDumps(context.Background(), 50, testCase.commit, testCase.root, testCase.indexer, false)
 		if err != nil {
 			t.Fatalf("unexpected error deleting dump: %s", err)
 		}
@@ -806,6 +866,30 @@ func TestDeleteOverlappingDumpsNoMatches(t *testing.T) {
 	}
 }
 
+func TestDeleteOverlappingDumpsEphemeral(t *testing.T) {
+	logger := logtest.Scoped(t)
+	sqlDB := dbtest.NewDB(logger, t)
+	db := database.NewDB(logger, sqlDB)
+	store := New(db, &observation.TestContext)
+
+	insertUploads(t, db,
+		types.Upload{ID: 1, Commit: makeCommit(1), Root: "cmd/", Indexer: "lsif-go"},
+		types.Upload{ID: 2, Commit: makeCommit(1), Root: "cmd/", Indexer: "lsif-go", Ephemeral: true},
+	)
+
+	err := store.DeleteOverlappingDumps(context.Background(), 50, makeCommit(1), "cmd/", "lsif-go", true)
+	if err != nil {
+		t.Fatalf("unexpected error deleting dump: %s", err)
+	}
+
+	// Only the ephemeral record was deleted
+	if states, err := getUploadStates(db, 1, 2); err != nil {
+		t.Fatalf("unexpected error getting states: %s", err)
+	} else if diff := cmp.Diff(map[int]string{1: "completed", 2: "deleting"}, states); diff != "" {
+		t.Errorf("unexpected dump (-want +got):\n%s", diff)
+	}
+}
+
 func TestDeleteOverlappingDumpsIgnoresIncompleteUploads(t *testing.T) {
 	logger := logtest.Scoped(t)
 	sqlDB := dbtest.NewDB(logger, t)
@@ -820,7 +904,7 @@ func TestDeleteOverlappingDumpsIgnoresIncompleteUploads(t *testing.T) {
 		State:   "queued",
 	})
 
-	err := store.DeleteOverlappingDumps(context.Background(), 50, makeCommit(1), "cmd/", "lsif-go")
+	err := store.DeleteOverlappingDumps(context.Background(), 50, makeCommit(1), "cmd/", "lsif-go", false)
 	if err != nil {
 		t.Fatalf("unexpected error deleting dump: %s", err)
 	}
@@ -874,6 +958,57 @@ func normalizeVisibleUploads(uploadMetas map[string][]commitgraph.UploadMeta) ma
 }
 
 // insertNearestUploads populates the lsif_nearest_uploads table with the given upload metadata.
+func TestGetVisibleUploadDistances(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(db, &observation.TestContext)
+
+	// This database has the following commit graph:
+	//
+	// [1] --+--- 2 --------+--5 -- 6
+	//       |              |
+	//       +-- [3] -- 4 --+
+
+	uploads := []types.Upload{
+		{ID: 1, Commit: makeCommit(1)},
+		{ID: 2, Commit: makeCommit(3), Root: "web/"},
+	}
+	insertUploads(t, db, uploads...)
+
+	graph := gitdomain.ParseCommitGraph([]string{
+		strings.Join([]string{makeCommit(6), makeCommit(5)}, " "),
+		strings.Join([]string{makeCommit(5), makeCommit(2), makeCommit(4)}, " "),
+		strings.Join([]string{makeCommit(4), makeCommit(3)}, " "),
+		strings.Join([]string{makeCommit(3), makeCommit(1)}, " "),
+		strings.Join([]string{makeCommit(2), makeCommit(1)}, " "),
+		strings.Join([]string{makeCommit(1)}, " "),
+	})
+
+	visibleUploads, links := commitgraph.NewGraph(graph, toCommitGraphView(uploads)).Gather()
+	insertNearestUploads(t, db, 50, visibleUploads)
+	insertLinks(t, db, 50, links)
+
+	testCases := []struct {
+		commit   string
+		expected map[int]int
+	}{
+		{commit: makeCommit(2), expected: map[int]int{1: 1}},
+		{commit: makeCommit(4), expected: map[int]int{1: 2, 2: 1}},
+		{commit: makeCommit(6), expected: map[int]int{1: 3, 2: 3}},
+		{commit: makeCommit(7), expected: map[int]int{}},
+	}
+
+	for _, testCase := range testCases {
+		distances, err := store.GetVisibleUploadDistances(context.Background(), 50, testCase.commit)
+		if err != nil {
+			t.Fatalf("unexpected error getting visible upload distances: %s", err)
+		}
+		if diff := cmp.Diff(testCase.expected, distances); diff != "" {
+			t.Errorf("unexpected distances for commit %s (-want +got):\n%s", testCase.commit, diff)
+		}
+	}
+}
+
 func insertNearestUploads(t testing.TB, db database.DB, repositoryID int, uploads map[string][]commitgraph.UploadMeta) {
 	var rows []*sqlf.Query
 	for commit, uploadMetas := range uploads {
@@ -78,3 +78,60 @@ func loadPackagesChannel(packages []precise.Package) <-chan []any {
 
 	return ch
 }
+
+// CopyPackagesFromUpload copies the packages provided and referenced by the given base upload so that
+// they are also provided and referenced by the given upload. Packages already recorded for the upload
+// are not duplicated. This is used to carry cross-repository data forward for incremental uploads, whose
+// correlated data covers only the documents that changed since their base upload.
+func (s *store) CopyPackagesFromUpload(ctx context.Context, uploadID, baseUploadID int) (err error) {
+	ctx, _, endObservation := s.operations.copyPackagesFromUpload.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("uploadID", uploadID),
+		log.Int("baseUploadID", baseUploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.db.Exec(ctx, sqlf.Sprintf(copyPackagesFromUploadQuery, uploadID, baseUploadID, uploadID, uploadID, baseUploadID, uploadID))
+}
+
+const copyPackagesFromUploadQuery = `
+WITH
+copied_packages AS (
+	INSERT INTO lsif_packages (dump_id, scheme, manager, name, version)
+	SELECT DISTINCT %s, p.scheme, p.manager, p.name, p.version
+	FROM lsif_packages p
+	WHERE
+		p.dump_id = %s AND
+		NOT EXISTS (
+			SELECT 1
+			FROM lsif_packages e
+			WHERE
+				e.dump_id = %s AND
+				e.scheme = p.scheme AND
+				e.manager = p.manager AND
+				e.name = p.name AND
+				e.version = p.version
+		)
+	RETURNING 1
+),
+copied_references AS (
+	INSERT INTO lsif_references (dump_id, scheme, manager, name, version)
+	SELECT DISTINCT %s, r.scheme, r.manager, r.name, r.version
+	FROM lsif_references r
+	WHERE
+		r.dump_id = %s AND
+		NOT EXISTS (
+			SELECT 1
+			FROM lsif_references e
+			WHERE
+				e.dump_id = %s AND
+				e.scheme = r.scheme AND
+				e.manager = r.manager AND
+				e.name = r.name AND
+				e.version = r.version
+		)
+	RETURNING 1
+)
+SELECT
+	(SELECT COUNT(*) FROM copied_packages),
+	(SELECT COUNT(*) FROM copied_references)
+`
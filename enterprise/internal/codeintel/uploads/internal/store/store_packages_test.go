@@ -65,6 +65,39 @@ func TestUpdatePackagesEmpty(t *testing.T) {
 	}
 }
 
+func TestCopyPackagesFromUpload(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(db, &observation.TestContext)
+	ctx := context.Background()
+
+	// for foreign key relation
+	insertUploads(t, db, types.Upload{ID: 41}, types.Upload{ID: 42})
+
+	insertPackages(t, store, []shared.Package{
+		{DumpID: 41, Scheme: "s0", Name: "n0", Version: "v0"},
+		{DumpID: 41, Scheme: "s1", Name: "n1", Version: "v1"},
+		{DumpID: 42, Scheme: "s1", Name: "n1", Version: "v1"},
+	})
+	insertPackageReferences(t, store, []shared.PackageReference{
+		{Package: shared.Package{DumpID: 41, Scheme: "s2", Name: "n2", Version: "v2"}},
+	})
+
+	if err := store.CopyPackagesFromUpload(ctx, 42, 41); err != nil {
+		t.Fatalf("unexpected error copying packages: %s", err)
+	}
+
+	for table, expected := range map[string]int{"lsif_packages": 2, "lsif_references": 1} {
+		count, _, err := basestore.ScanFirstInt(db.QueryContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE dump_id = 42"))
+		if err != nil {
+			t.Fatalf("unexpected error checking %s count: %s", table, err)
+		}
+		if count != expected {
+			t.Errorf("unexpected %s count. want=%d have=%d", table, expected, count)
+		}
+	}
+}
+
 // insertPackages populates the lsif_packages table with the given packages.
 func insertPackages(t testing.TB, store Store, packages []shared.Package) {
 	for _, pkg := range packages {
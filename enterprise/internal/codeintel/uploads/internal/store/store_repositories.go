@@ -10,7 +10,10 @@ import (
 	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go/log"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 )
@@ -365,3 +368,111 @@ func (s *store) HasRepository(ctx context.Context, repositoryID int) (_ bool, er
 const hasRepositoryQuery = `
 SELECT 1 FROM lsif_uploads WHERE state NOT IN ('deleted', 'deleting') AND repository_id = %s LIMIT 1
 `
+
+// GetRepositoryCoverage returns a page of repositories with at least one upload visible at the tip
+// of their default branch, ordered by name, along with the total number of such repositories. Each
+// repository is returned with the set of uploads visible at the tip of its default branch. The
+// distance of each upload is left unset.
+func (s *store) GetRepositoryCoverage(ctx context.Context, opts shared.GetRepositoryCoverageOptions) (_ []shared.RepositoryCoverage, totalCount int, err error) {
+	ctx, trace, endObservation := s.operations.getRepositoryCoverage.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("term", opts.Term),
+		log.Int("limit", opts.Limit),
+		log.Int("offset", opts.Offset),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	authzConds, err := database.AuthzQueryConds(ctx, database.NewDBWith(s.logger, s.db))
+	if err != nil {
+		return nil, 0, err
+	}
+	conds := []*sqlf.Query{authzConds}
+	if opts.Term != "" {
+		conds = append(conds, sqlf.Sprintf("repo.name ILIKE %s", "%"+opts.Term+"%"))
+	}
+
+	repositories, totalCount, err := scanRepositoryCoveragesWithCount(s.db.Query(ctx, sqlf.Sprintf(
+		getRepositoryCoverageQuery,
+		sqlf.Join(conds, " AND "),
+		opts.Limit,
+		opts.Offset,
+	)))
+	if err != nil {
+		return nil, 0, err
+	}
+	trace.Log(
+		log.Int("totalCount", totalCount),
+		log.Int("numRepositories", len(repositories)),
+	)
+	if len(repositories) == 0 {
+		return repositories, totalCount, nil
+	}
+
+	repositoryIDs := make([]int, 0, len(repositories))
+	indexes := make(map[int]int, len(repositories))
+	for i, repository := range repositories {
+		repositoryIDs = append(repositoryIDs, repository.RepositoryID)
+		indexes[repository.RepositoryID] = i
+	}
+
+	dumps, err := scanDumps(s.db.Query(ctx, sqlf.Sprintf(getRepositoryCoverageDumpsQuery, pq.Array(repositoryIDs))))
+	if err != nil {
+		return nil, 0, err
+	}
+	trace.Log(log.Int("numDumps", len(dumps)))
+
+	for _, dump := range dumps {
+		i := indexes[dump.RepositoryID]
+		repositories[i].Uploads = append(repositories[i].Uploads, shared.UploadCoverage{Upload: dump})
+	}
+
+	return repositories, totalCount, nil
+}
+
+const getRepositoryCoverageQuery = `
+SELECT repo.id, repo.name, COUNT(*) OVER() AS count
+FROM repo
+WHERE
+	repo.deleted_at IS NULL AND
+	repo.blocked IS NULL AND
+	EXISTS (
+		SELECT 1
+		FROM lsif_uploads_visible_at_tip uvt
+		WHERE uvt.repository_id = repo.id AND uvt.is_default_branch
+	) AND
+	%s
+ORDER BY repo.name
+LIMIT %d OFFSET %d
+`
+
+const getRepositoryCoverageDumpsQuery = `
+SELECT
+	u.id,
+	u.commit,
+	u.root,
+	true AS visible_at_tip,
+	u.uploaded_at,
+	u.state,
+	u.failure_message,
+	u.started_at,
+	u.finished_at,
+	u.process_after,
+	u.num_resets,
+	u.num_failures,
+	u.repository_id,
+	u.repository_name,
+	u.indexer,
+	u.indexer_version,
+	u.associated_index_id
+FROM lsif_dumps_with_repository_name u
+WHERE u.id IN (
+	SELECT uvt.upload_id
+	FROM lsif_uploads_visible_at_tip uvt
+	WHERE uvt.repository_id = ANY(%s) AND uvt.is_default_branch
+)
+ORDER BY u.repository_id, u.root, u.indexer
+`
+
+var scanRepositoryCoveragesWithCount = basestore.NewSliceWithCountScanner(func(s dbutil.Scanner) (coverage shared.RepositoryCoverage, count int, _ error) {
+	err := s.Scan(&coverage.RepositoryID, &coverage.RepositoryName, &count)
+	return coverage, count, err
+})
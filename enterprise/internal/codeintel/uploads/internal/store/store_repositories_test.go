@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/log/logtest"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -490,6 +491,88 @@ func deleteRepo(t testing.TB, db database.DB, id int, deleted_at time.Time) {
 	}
 }
 
+func TestGetRepositoryCoverage(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(logger, t))
+	store := New(db, &observation.TestContext)
+
+	insertUploads(t, db,
+		types.Upload{ID: 1, RepositoryID: 50, RepositoryName: "github.com/test/alpha", Root: "web/"},
+		types.Upload{ID: 2, RepositoryID: 50, RepositoryName: "github.com/test/alpha", Root: "cmd/"},
+		types.Upload{ID: 3, RepositoryID: 50, RepositoryName: "github.com/test/alpha", Root: "lib/"},
+		types.Upload{ID: 4, RepositoryID: 51, RepositoryName: "github.com/test/beta"},
+		types.Upload{ID: 5, RepositoryID: 52, RepositoryName: "github.com/test/gamma"},
+		types.Upload{ID: 6, RepositoryID: 53, RepositoryName: "github.com/other/delta"},
+	)
+	insertVisibleAtTip(t, db, 50, 1, 2)
+	insertVisibleAtTipInternal(t, db, 50, false, 3)
+	insertVisibleAtTipInternal(t, db, 51, false, 4)
+	insertVisibleAtTip(t, db, 52, 5)
+	insertVisibleAtTip(t, db, 53, 6)
+
+	type repositoryUploads struct {
+		Name      string
+		UploadIDs []int
+	}
+
+	testCases := []struct {
+		opts               shared.GetRepositoryCoverageOptions
+		expected           []repositoryUploads
+		expectedTotalCount int
+	}{
+		{
+			opts: shared.GetRepositoryCoverageOptions{Limit: 10},
+			expected: []repositoryUploads{
+				{Name: "github.com/other/delta", UploadIDs: []int{6}},
+				{Name: "github.com/test/alpha", UploadIDs: []int{2, 1}},
+				{Name: "github.com/test/gamma", UploadIDs: []int{5}},
+			},
+			expectedTotalCount: 3,
+		},
+		{
+			opts: shared.GetRepositoryCoverageOptions{Limit: 1, Offset: 1},
+			expected: []repositoryUploads{
+				{Name: "github.com/test/alpha", UploadIDs: []int{2, 1}},
+			},
+			expectedTotalCount: 3,
+		},
+		{
+			opts: shared.GetRepositoryCoverageOptions{Term: "test", Limit: 10},
+			expected: []repositoryUploads{
+				{Name: "github.com/test/alpha", UploadIDs: []int{2, 1}},
+				{Name: "github.com/test/gamma", UploadIDs: []int{5}},
+			},
+			expectedTotalCount: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		name := fmt.Sprintf("term=%q offset=%d", testCase.opts.Term, testCase.opts.Offset)
+
+		t.Run(name, func(t *testing.T) {
+			repositories, totalCount, err := store.GetRepositoryCoverage(context.Background(), testCase.opts)
+			if err != nil {
+				t.Fatalf("unexpected error getting repository coverage: %s", err)
+			}
+			if totalCount != testCase.expectedTotalCount {
+				t.Errorf("unexpected total count. want=%d have=%d", testCase.expectedTotalCount, totalCount)
+			}
+
+			var actual []repositoryUploads
+			for _, repository := range repositories {
+				var uploadIDs []int
+				for _, upload := range repository.Uploads {
+					uploadIDs = append(uploadIDs, upload.Upload.ID)
+				}
+				actual = append(actual, repositoryUploads{Name: repository.RepositoryName, UploadIDs: uploadIDs})
+			}
+			if diff := cmp.Diff(testCase.expected, actual); diff != "" {
+				t.Errorf("unexpected repository coverage (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func testStoreWithoutConfigurationPolicies(t *testing.T, db database.DB) Store {
 	if _, err := db.ExecContext(context.Background(), `TRUNCATE lsif_configuration_policies`); err != nil {
 		t.Fatalf("unexpected error while inserting configuration policies: %s", err)
@@ -91,6 +91,8 @@ SELECT
 	u.associated_index_id,
 	s.rank,
 	u.uncompressed_size,
+	u.ephemeral,
+	u.base_upload_id,
 	COUNT(*) OVER() AS count
 FROM %s
 LEFT JOIN (` + uploadRankQueryFragment + `) s
@@ -130,7 +132,9 @@ SELECT
 	NULL::integer[] as uploaded_parts,
 	au.upload_size, au.associated_index_id,
 	COALESCE((snapshot->'expired')::boolean, false) AS expired,
-	NULL::bigint AS uncompressed_size
+	NULL::bigint AS uncompressed_size,
+	false AS ephemeral,
+	NULL::integer AS base_upload_id
 FROM (
 	SELECT upload_id, snapshot_transition_columns(transition_columns ORDER BY sequence ASC) AS snapshot
 	FROM lsif_uploads_audit_logs
@@ -160,6 +164,8 @@ JOIN lsif_references r ON
 WHERE
 	-- Don't match deleted uploads
 	u.state = 'completed' AND
+	-- Ephemeral uploads never canonically provide a package
+	NOT u.ephemeral AND
 	%s
 `
 
@@ -198,6 +204,8 @@ JOIN lsif_packages p ON p.dump_id = u.id
 WHERE
 	-- Don't match deleted uploads
 	u.state = 'completed' AND
+	-- Ephemeral uploads never canonically provide a package
+	NOT u.ephemeral AND
 	%s
 `
 
@@ -237,7 +245,9 @@ SELECT
 	u.upload_size,
 	u.associated_index_id,
 	s.rank,
-	u.uncompressed_size
+	u.uncompressed_size,
+	u.ephemeral,
+	u.base_upload_id
 FROM lsif_uploads u
 LEFT JOIN (` + uploadRankQueryFragment + `) s
 ON u.id = s.id
@@ -293,7 +303,9 @@ SELECT
 	u.upload_size,
 	u.associated_index_id,
 	s.rank,
-	u.uncompressed_size
+	u.uncompressed_size,
+	u.ephemeral,
+	u.base_upload_id
 FROM lsif_uploads u
 LEFT JOIN (` + uploadRankQueryFragment + `) s
 ON u.id = s.id
@@ -395,7 +407,9 @@ SELECT
 	u.upload_size,
 	u.associated_index_id,
 	s.rank,
-	u.uncompressed_size
+	u.uncompressed_size,
+	u.ephemeral,
+	u.base_upload_id
 FROM lsif_uploads_with_repository_name u
 LEFT JOIN (` + uploadRankQueryFragment + `) s
 ON u.id = s.id
@@ -628,7 +642,10 @@ ranked_uploads_providing_packages AS (
 		) AND
 
 		-- Don't match deleted uploads
-		u.state = 'completed'
+		u.state = 'completed' AND
+
+		-- Ephemeral uploads never canonically provide a package
+		NOT u.ephemeral
 ),
 
 -- Filter the set of our original (expired) candidate uploads so that it includes only
@@ -768,7 +785,7 @@ root_upload_and_packages AS (
 			` + packageRankingQueryFragment + ` AS rank
 		FROM lsif_uploads u
 		LEFT JOIN lsif_packages p ON p.dump_id = u.id
-		WHERE u.state = 'completed' AND u.expired
+		WHERE u.state = 'completed' AND u.expired AND NOT u.ephemeral
 	) s
 
 	WHERE s.rank = 1 AND EXISTS (
@@ -1209,7 +1226,7 @@ func (s *store) UpdateUploadsVisibleToCommits(
 }
 
 const calculateVisibleUploadsCommitGraphQuery = `
-SELECT id, commit, md5(root || ':' || indexer) as token, 0 as distance FROM lsif_uploads WHERE state = 'completed' AND repository_id = %s
+SELECT id, commit, md5(root || ':' || indexer) as token, 0 as distance FROM lsif_uploads WHERE state = 'completed' AND NOT ephemeral AND repository_id = %s
 `
 
 const calculateVisibleUploadsDirtyRepositoryQuery = `
@@ -1846,6 +1863,8 @@ func (s *store) InsertUpload(ctx context.Context, upload types.Upload) (id int,
 			upload.UploadSize,
 			upload.AssociatedIndexID,
 			upload.UncompressedSize,
+			upload.Ephemeral,
+			upload.BaseUploadID,
 		),
 	))
 
@@ -1864,8 +1883,10 @@ INSERT INTO lsif_uploads (
 	uploaded_parts,
 	upload_size,
 	associated_index_id,
-	uncompressed_size
-) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+	uncompressed_size,
+	ephemeral,
+	base_upload_id
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id
 `
 
@@ -2045,7 +2066,9 @@ func buildGetConditionsAndCte(opts shared.GetUploadsOptions) (*sqlf.Query, []*sq
 				upload_size,
 				associated_index_id,
 				expired,
-				uncompressed_size
+				uncompressed_size,
+				ephemeral,
+				base_upload_id
 			FROM lsif_uploads
 			UNION ALL
 			SELECT *
@@ -44,6 +44,8 @@ var uploadColumnsWithNullRank = []*sqlf.Query{
 	sqlf.Sprintf("u.associated_index_id"),
 	sqlf.Sprintf("NULL"),
 	sqlf.Sprintf("u.uncompressed_size"),
+	sqlf.Sprintf("u.ephemeral"),
+	sqlf.Sprintf("u.base_upload_id"),
 }
 
 var UploadWorkerStoreOptions = dbworkerstore.Options[types.Upload]{
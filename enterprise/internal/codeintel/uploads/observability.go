@@ -50,6 +50,10 @@ type operations struct {
 	getDumpsWithDefinitionsForMonikers *observation.Operation
 	getDumpsByIDs                      *observation.Operation
 
+	// Coverage
+	getDirectoryCoverage  *observation.Operation
+	getRepositoryCoverage *observation.Operation
+
 	// References
 	referencesForUpload *observation.Operation
 
@@ -146,6 +150,10 @@ func newOperations(observationContext *observation.Context) *operations {
 		getDumpsWithDefinitionsForMonikers: op("GetDumpsWithDefinitionsForMonikers"),
 		getDumpsByIDs:                      op("GetDumpsByIDs"),
 
+		// Coverage
+		getDirectoryCoverage:  op("GetDirectoryCoverage"),
+		getRepositoryCoverage: op("GetRepositoryCoverage"),
+
 		// References
 		referencesForUpload: op("ReferencesForUpload"),
 
@@ -243,12 +243,30 @@ const numAncestors = 100
 // the graph. This will not always produce the full set of visible commits - some responses may not contain
 // all results while a subsequent request made after the lsif_nearest_uploads has been updated to include
 // this commit will.
+//
+// Ephemeral uploads for the given commit are overlaid on top of the uploads visible through the commit
+// graph: an ephemeral upload shadows any visible upload with the same root and indexer that was made for
+// a different commit. A non-ephemeral upload for the same commit always takes precedence.
 func (s *Service) InferClosestUploads(ctx context.Context, repositoryID int, commit, path string, exactPath bool, indexer string) (_ []types.Dump, err error) {
 	ctx, _, endObservation := s.operations.inferClosestUploads.With(ctx, &err, observation.Args{
 		LogFields: []log.Field{log.Int("repositoryID", repositoryID), log.String("commit", commit), log.String("path", path), log.Bool("exactPath", exactPath), log.String("indexer", indexer)},
 	})
 	defer endObservation(1, observation.Args{})
 
+	dumps, err := s.inferClosestUploads(ctx, repositoryID, commit, path, exactPath, indexer)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralDumps, err := s.store.FindEphemeralDumps(ctx, repositoryID, commit, path, exactPath, indexer)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.FindEphemeralDumps")
+	}
+
+	return overlayEphemeralDumps(commit, dumps, ephemeralDumps), nil
+}
+
+func (s *Service) inferClosestUploads(ctx context.Context, repositoryID int, commit, path string, exactPath bool, indexer string) ([]types.Dump, error) {
 	// The parameters exactPath and rootMustEnclosePath align here: if we're looking for dumps
 	// that can answer queries for a directory (e.g. diagnostics), we want any dump that happens
 	// to intersect the target directory. If we're looking for dumps that can answer queries for
@@ -298,6 +316,49 @@ func (s *Service) InferClosestUploads(ctx context.Context, repositoryID int, com
 	return dumps, nil
 }
 
+// overlayEphemeralDumps merges the ephemeral dumps of the given commit into the set of dumps visible from
+// the commit graph. Visible dumps that share a root and indexer with an ephemeral dump are replaced by the
+// ephemeral dump, unless the visible dump was uploaded for the same commit, in which case the ephemeral
+// dump is discarded. Ephemeral dumps are returned first.
+func overlayEphemeralDumps(commit string, dumps, ephemeralDumps []types.Dump) []types.Dump {
+	if len(ephemeralDumps) == 0 {
+		return dumps
+	}
+
+	type rootAndIndexer struct{ root, indexer string }
+
+	exact := map[rootAndIndexer]struct{}{}
+	for _, dump := range dumps {
+		if dump.Commit == commit {
+			exact[rootAndIndexer{dump.Root, dump.Indexer}] = struct{}{}
+		}
+	}
+
+	overlaid := map[rootAndIndexer]struct{}{}
+	merged := make([]types.Dump, 0, len(dumps)+len(ephemeralDumps))
+	for _, dump := range ephemeralDumps {
+		key := rootAndIndexer{dump.Root, dump.Indexer}
+		if _, ok := exact[key]; ok {
+			continue
+		}
+		if _, ok := overlaid[key]; ok {
+			continue
+		}
+
+		overlaid[key] = struct{}{}
+		merged = append(merged, dump)
+	}
+	for _, dump := range dumps {
+		if _, ok := overlaid[rootAndIndexer{dump.Root, dump.Indexer}]; ok {
+			continue
+		}
+
+		merged = append(merged, dump)
+	}
+
+	return merged
+}
+
 func (s *Service) GetDumpsWithDefinitionsForMonikers(ctx context.Context, monikers []precise.QualifiedMonikerData) (_ []types.Dump, err error) {
 	ctx, _, endObservation := s.operations.getDumpsWithDefinitionsForMonikers.With(ctx, &err, observation.Args{
 		LogFields: []log.Field{log.String("monikers", fmt.Sprintf("%v", monikers))},
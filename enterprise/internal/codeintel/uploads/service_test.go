@@ -0,0 +1,39 @@
+package uploads
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+)
+
+func TestOverlayEphemeralDumps(t *testing.T) {
+	dumps := []types.Dump{
+		{ID: 1, Commit: "deadbeef", Root: "a/", Indexer: "scip-go"},
+		{ID: 2, Commit: "deadbeef", Root: "b/", Indexer: "scip-go"},
+		{ID: 3, Commit: "cafebabe", Root: "c/", Indexer: "scip-go"},
+		{ID: 4, Commit: "deadbeef", Root: "a/", Indexer: "scip-typescript"},
+	}
+	ephemeralDumps := []types.Dump{
+		{ID: 10, Commit: "cafebabe", Root: "a/", Indexer: "scip-go"},
+		{ID: 11, Commit: "cafebabe", Root: "c/", Indexer: "scip-go"},
+		{ID: 12, Commit: "cafebabe", Root: "d/", Indexer: "scip-go"},
+	}
+
+	ids := func(dumps []types.Dump) (ids []int) {
+		for _, dump := range dumps {
+			ids = append(ids, dump.ID)
+		}
+		return ids
+	}
+
+	// Ephemeral dumps shadow dumps for other commits, but not dumps for the same commit
+	if diff := cmp.Diff([]int{10, 12, 2, 3, 4}, ids(overlayEphemeralDumps("cafebabe", dumps, ephemeralDumps))); diff != "" {
+		t.Errorf("unexpected dumps (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]int{1, 2, 3, 4}, ids(overlayEphemeralDumps("cafebabe", dumps, nil))); diff != "" {
+		t.Errorf("unexpected dumps (-want +got):\n%s", diff)
+	}
+}
@@ -69,6 +69,37 @@ type AdjustedUpload struct {
 	AdjustedPathInBundle string
 }
 
+// UploadCoverage pairs an upload with the number of commits between the upload's commit
+// and the commit from which it is visible. Distance is nil when the commit graph does not
+// (yet) contain the target commit.
+type UploadCoverage struct {
+	Upload   types.Dump
+	Distance *int
+}
+
+// DirectoryCoverage describes the uploads that cover a directory. Uploads either have a
+// root enclosing the directory or a root nested within it.
+type DirectoryCoverage struct {
+	Path     string
+	Uploads  []UploadCoverage
+	Children []*DirectoryCoverage
+}
+
+// RepositoryCoverage describes the uploads visible at the tip of a repository's default
+// branch. Commit is the tip of the default branch and is empty if it cannot be resolved.
+type RepositoryCoverage struct {
+	RepositoryID   int
+	RepositoryName string
+	Commit         string
+	Uploads        []UploadCoverage
+}
+
+type GetRepositoryCoverageOptions struct {
+	Term   string
+	Limit  int
+	Offset int
+}
+
 // Range is an inclusive bounds within a file.
 type Range struct {
 	Start Position
@@ -0,0 +1,164 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+const DefaultRepositoryCoveragePageSize = 20
+
+type DirectoryCoverageResolver struct {
+	coverage *shared.DirectoryCoverage
+	factory  *uploadCoverageResolverFactory
+}
+
+func NewDirectoryCoverageResolver(coverage *shared.DirectoryCoverage, factory *uploadCoverageResolverFactory) resolverstubs.CodeIntelDirectoryCoverageResolver {
+	return &DirectoryCoverageResolver{
+		coverage: coverage,
+		factory:  factory,
+	}
+}
+
+func (r *DirectoryCoverageResolver) Path() string {
+	return r.coverage.Path
+}
+
+func (r *DirectoryCoverageResolver) Uploads() []resolverstubs.CodeIntelUploadCoverageResolver {
+	return r.factory.resolve(r.coverage.Uploads)
+}
+
+func (r *DirectoryCoverageResolver) Children() []resolverstubs.CodeIntelDirectoryCoverageResolver {
+	resolvers := make([]resolverstubs.CodeIntelDirectoryCoverageResolver, 0, len(r.coverage.Children))
+	for _, child := range r.coverage.Children {
+		resolvers = append(resolvers, NewDirectoryCoverageResolver(child, r.factory))
+	}
+
+	return resolvers
+}
+
+type RepositoryCoverageConnectionResolver struct {
+	repositories     []shared.RepositoryCoverage
+	totalCount       int
+	offset           int
+	locationResolver *sharedresolvers.CachedLocationResolver
+	factory          *uploadCoverageResolverFactory
+}
+
+func NewRepositoryCoverageConnectionResolver(db database.DB, repositories []shared.RepositoryCoverage, totalCount, offset int, factory *uploadCoverageResolverFactory) resolverstubs.CodeIntelRepositoryCoverageConnectionResolver {
+	return &RepositoryCoverageConnectionResolver{
+		repositories:     repositories,
+		totalCount:       totalCount,
+		offset:           offset,
+		locationResolver: sharedresolvers.NewCachedLocationResolver(db, gitserver.NewClient(db)),
+		factory:          factory,
+	}
+}
+
+func (r *RepositoryCoverageConnectionResolver) Nodes(ctx context.Context) ([]resolverstubs.CodeIntelRepositoryCoverageResolver, error) {
+	resolvers := make([]resolverstubs.CodeIntelRepositoryCoverageResolver, 0, len(r.repositories))
+	for _, repository := range r.repositories {
+		resolvers = append(resolvers, &RepositoryCoverageResolver{
+			coverage:         repository,
+			locationResolver: r.locationResolver,
+			factory:          r.factory,
+		})
+	}
+
+	return resolvers, nil
+}
+
+func (r *RepositoryCoverageConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	return int32(r.totalCount), nil
+}
+
+func (r *RepositoryCoverageConnectionResolver) PageInfo(ctx context.Context) (resolverstubs.PageInfo, error) {
+	if next := r.offset + len(r.repositories); next < r.totalCount {
+		cursor := strconv.Itoa(next)
+		return EncodeCursor(&cursor), nil
+	}
+
+	return EncodeCursor(nil), nil
+}
+
+type RepositoryCoverageResolver struct {
+	coverage         shared.RepositoryCoverage
+	locationResolver *sharedresolvers.CachedLocationResolver
+	factory          *uploadCoverageResolverFactory
+}
+
+func (r *RepositoryCoverageResolver) Repository(ctx context.Context) (resolverstubs.RepositoryResolver, error) {
+	repository, err := r.locationResolver.Repository(ctx, api.RepoID(r.coverage.RepositoryID))
+	if err != nil || repository == nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func (r *RepositoryCoverageResolver) Commit() *string {
+	return strPtr(r.coverage.Commit)
+}
+
+func (r *RepositoryCoverageResolver) Uploads() []resolverstubs.CodeIntelUploadCoverageResolver {
+	return r.factory.resolve(r.coverage.Uploads)
+}
+
+type UploadCoverageResolver struct {
+	upload   resolverstubs.LSIFUploadResolver
+	distance *int
+}
+
+func (r *UploadCoverageResolver) Upload() resolverstubs.LSIFUploadResolver {
+	return r.upload
+}
+
+func (r *UploadCoverageResolver) CommitsBehind() *int32 {
+	if r.distance == nil {
+		return nil
+	}
+
+	return intPtr(int32(*r.distance))
+}
+
+// uploadCoverageResolverFactory creates upload coverage resolvers. The same upload can cover many
+// directories, so a single upload resolver is shared by all coverage resolvers of the same upload.
+type uploadCoverageResolverFactory struct {
+	uploadSvc    UploadService
+	autoindexSvc AutoIndexingService
+	policySvc    PolicyService
+	prefetcher   *sharedresolvers.Prefetcher
+	traceErrs    *observation.ErrCollector
+
+	mu        sync.Mutex
+	resolvers map[int]resolverstubs.LSIFUploadResolver
+}
+
+func (f *uploadCoverageResolverFactory) resolve(uploads []shared.UploadCoverage) []resolverstubs.CodeIntelUploadCoverageResolver {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resolvers := make([]resolverstubs.CodeIntelUploadCoverageResolver, 0, len(uploads))
+	for _, upload := range uploads {
+		uploadResolver, ok := f.resolvers[upload.Upload.ID]
+		if !ok {
+			uploadResolver = sharedresolvers.NewUploadResolver(f.uploadSvc, f.autoindexSvc, f.policySvc, dumpToUpload(upload.Upload), f.prefetcher, f.traceErrs)
+			f.resolvers[upload.Upload.ID] = uploadResolver
+		}
+
+		resolvers = append(resolvers, &UploadCoverageResolver{
+			upload:   uploadResolver,
+			distance: upload.Distance,
+		})
+	}
+
+	return resolvers
+}
@@ -21,6 +21,8 @@ type UploadService interface {
 	GetUploadsByIDs(ctx context.Context, ids ...int) (_ []types.Upload, err error)
 	DeleteUploadByID(ctx context.Context, id int) (_ bool, err error)
 	DeleteUploads(ctx context.Context, opts uploadsshared.DeleteUploadsOptions) (err error)
+	GetDirectoryCoverage(ctx context.Context, repositoryID int, commit, path string, depth int) (_ *uploadsshared.DirectoryCoverage, err error)
+	GetRepositoryCoverage(ctx context.Context, opts uploadsshared.GetRepositoryCoverageOptions) (_ []uploadsshared.RepositoryCoverage, totalCount int, err error)
 }
 
 type AutoIndexingService interface {
@@ -889,9 +889,15 @@ type MockUploadService struct {
 	// GetCommitGraphMetadataFunc is an instance of a mock function object
 	// controlling the behavior of the method GetCommitGraphMetadata.
 	GetCommitGraphMetadataFunc *UploadServiceGetCommitGraphMetadataFunc
+	// GetDirectoryCoverageFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDirectoryCoverage.
+	GetDirectoryCoverageFunc *UploadServiceGetDirectoryCoverageFunc
 	// GetListTagsFunc is an instance of a mock function object controlling
 	// the behavior of the method GetListTags.
 	GetListTagsFunc *UploadServiceGetListTagsFunc
+	// GetRepositoryCoverageFunc is an instance of a mock function object
+	// controlling the behavior of the method GetRepositoryCoverage.
+	GetRepositoryCoverageFunc *UploadServiceGetRepositoryCoverageFunc
 	// GetUploadDocumentsForPathFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// GetUploadDocumentsForPath.
@@ -928,11 +934,21 @@ func NewMockUploadService() *MockUploadService {
 				return
 			},
 		},
+		GetDirectoryCoverageFunc: &UploadServiceGetDirectoryCoverageFunc{
+			defaultHook: func(context.Context, int, string, string, int) (r0 *shared1.DirectoryCoverage, r1 error) {
+				return
+			},
+		},
 		GetListTagsFunc: &UploadServiceGetListTagsFunc{
 			defaultHook: func(context.Context, api.RepoName, ...string) (r0 []*gitdomain.Tag, r1 error) {
 				return
 			},
 		},
+		GetRepositoryCoverageFunc: &UploadServiceGetRepositoryCoverageFunc{
+			defaultHook: func(context.Context, shared1.GetRepositoryCoverageOptions) (r0 []shared1.RepositoryCoverage, r1 int, r2 error) {
+				return
+			},
+		},
 		GetUploadDocumentsForPathFunc: &UploadServiceGetUploadDocumentsForPathFunc{
 			defaultHook: func(context.Context, int, string) (r0 []string, r1 int, r2 error) {
 				return
@@ -975,11 +991,21 @@ func NewStrictMockUploadService() *MockUploadService {
 				panic("unexpected invocation of MockUploadService.GetCommitGraphMetadata")
 			},
 		},
+		GetDirectoryCoverageFunc: &UploadServiceGetDirectoryCoverageFunc{
+			defaultHook: func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error) {
+				panic("unexpected invocation of MockUploadService.GetDirectoryCoverage")
+			},
+		},
 		GetListTagsFunc: &UploadServiceGetListTagsFunc{
 			defaultHook: func(context.Context, api.RepoName, ...string) ([]*gitdomain.Tag, error) {
 				panic("unexpected invocation of MockUploadService.GetListTags")
 			},
 		},
+		GetRepositoryCoverageFunc: &UploadServiceGetRepositoryCoverageFunc{
+			defaultHook: func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+				panic("unexpected invocation of MockUploadService.GetRepositoryCoverage")
+			},
+		},
 		GetUploadDocumentsForPathFunc: &UploadServiceGetUploadDocumentsForPathFunc{
 			defaultHook: func(context.Context, int, string) ([]string, int, error) {
 				panic("unexpected invocation of MockUploadService.GetUploadDocumentsForPath")
@@ -1015,9 +1041,15 @@ func NewMockUploadServiceFrom(i UploadService) *MockUploadService {
 		GetCommitGraphMetadataFunc: &UploadServiceGetCommitGraphMetadataFunc{
 			defaultHook: i.GetCommitGraphMetadata,
 		},
+		GetDirectoryCoverageFunc: &UploadServiceGetDirectoryCoverageFunc{
+			defaultHook: i.GetDirectoryCoverage,
+		},
 		GetListTagsFunc: &UploadServiceGetListTagsFunc{
 			defaultHook: i.GetListTags,
 		},
+		GetRepositoryCoverageFunc: &UploadServiceGetRepositoryCoverageFunc{
+			defaultHook: i.GetRepositoryCoverage,
+		},
 		GetUploadDocumentsForPathFunc: &UploadServiceGetUploadDocumentsForPathFunc{
 			defaultHook: i.GetUploadDocumentsForPath,
 		},
@@ -1470,6 +1502,126 @@ func (c UploadServiceGetCommitGraphMetadataFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// UploadServiceGetDirectoryCoverageFunc describes the behavior when the
+// GetDirectoryCoverage method of the parent MockUploadService instance is
+// invoked.
+type UploadServiceGetDirectoryCoverageFunc struct {
+	defaultHook func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error)
+	hooks       []func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error)
+	history     []UploadServiceGetDirectoryCoverageFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDirectoryCoverage delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockUploadService) GetDirectoryCoverage(v0 context.Context, v1 int, v2 string, v3 string, v4 int) (*shared1.DirectoryCoverage, error) {
+	r0, r1 := m.GetDirectoryCoverageFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetDirectoryCoverageFunc.appendCall(UploadServiceGetDirectoryCoverageFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDirectoryCoverage
+// method of the parent MockUploadService instance is invoked and the hook
+// queue is empty.
+func (f *UploadServiceGetDirectoryCoverageFunc) SetDefaultHook(hook func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDirectoryCoverage method of the parent MockUploadService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *UploadServiceGetDirectoryCoverageFunc) PushHook(hook func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UploadServiceGetDirectoryCoverageFunc) SetDefaultReturn(r0 *shared1.DirectoryCoverage, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UploadServiceGetDirectoryCoverageFunc) PushReturn(r0 *shared1.DirectoryCoverage, r1 error) {
+	f.PushHook(func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error) {
+		return r0, r1
+	})
+}
+
+func (f *UploadServiceGetDirectoryCoverageFunc) nextHook() func(context.Context, int, string, string, int) (*shared1.DirectoryCoverage, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UploadServiceGetDirectoryCoverageFunc) appendCall(r0 UploadServiceGetDirectoryCoverageFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UploadServiceGetDirectoryCoverageFuncCall
+// objects describing the invocations of this function.
+func (f *UploadServiceGetDirectoryCoverageFunc) History() []UploadServiceGetDirectoryCoverageFuncCall {
+	f.mutex.Lock()
+	history := make([]UploadServiceGetDirectoryCoverageFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UploadServiceGetDirectoryCoverageFuncCall is an object that describes an
+// invocation of method GetDirectoryCoverage on an instance of
+// MockUploadService.
+type UploadServiceGetDirectoryCoverageFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *shared1.DirectoryCoverage
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UploadServiceGetDirectoryCoverageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UploadServiceGetDirectoryCoverageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // UploadServiceGetListTagsFunc describes the behavior when the GetListTags
 // method of the parent MockUploadService instance is invoked.
 type UploadServiceGetListTagsFunc struct {
@@ -1588,6 +1740,120 @@ func (c UploadServiceGetListTagsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// UploadServiceGetRepositoryCoverageFunc describes the behavior when the
+// GetRepositoryCoverage method of the parent MockUploadService instance is
+// invoked.
+type UploadServiceGetRepositoryCoverageFunc struct {
+	defaultHook func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)
+	hooks       []func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)
+	history     []UploadServiceGetRepositoryCoverageFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRepositoryCoverage delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockUploadService) GetRepositoryCoverage(v0 context.Context, v1 shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+	r0, r1, r2 := m.GetRepositoryCoverageFunc.nextHook()(v0, v1)
+	m.GetRepositoryCoverageFunc.appendCall(UploadServiceGetRepositoryCoverageFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetRepositoryCoverage method of the parent MockUploadService instance is
+// invoked and the hook queue is empty.
+func (f *UploadServiceGetRepositoryCoverageFunc) SetDefaultHook(hook func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRepositoryCoverage method of the parent MockUploadService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *UploadServiceGetRepositoryCoverageFunc) PushHook(hook func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UploadServiceGetRepositoryCoverageFunc) SetDefaultReturn(r0 []shared1.RepositoryCoverage, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UploadServiceGetRepositoryCoverageFunc) PushReturn(r0 []shared1.RepositoryCoverage, r1 int, r2 error) {
+	f.PushHook(func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *UploadServiceGetRepositoryCoverageFunc) nextHook() func(context.Context, shared1.GetRepositoryCoverageOptions) ([]shared1.RepositoryCoverage, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UploadServiceGetRepositoryCoverageFunc) appendCall(r0 UploadServiceGetRepositoryCoverageFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UploadServiceGetRepositoryCoverageFuncCall
+// objects describing the invocations of this function.
+func (f *UploadServiceGetRepositoryCoverageFunc) History() []UploadServiceGetRepositoryCoverageFuncCall {
+	f.mutex.Lock()
+	history := make([]UploadServiceGetRepositoryCoverageFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UploadServiceGetRepositoryCoverageFuncCall is an object that describes an
+// invocation of method GetRepositoryCoverage on an instance of
+// MockUploadService.
+type UploadServiceGetRepositoryCoverageFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.GetRepositoryCoverageOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.RepositoryCoverage
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UploadServiceGetRepositoryCoverageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UploadServiceGetRepositoryCoverageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // UploadServiceGetUploadDocumentsForPathFunc describes the behavior when
 // the GetUploadDocumentsForPath method of the parent MockUploadService
 // instance is invoked.
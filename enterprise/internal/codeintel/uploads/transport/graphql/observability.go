@@ -16,6 +16,10 @@ type operations struct {
 
 	// Commit Graph
 	commitGraph *observation.Operation
+
+	// Coverage
+	gitTreeCodeIntelCoverage *observation.Operation
+	codeIntelCoverageSummary *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -43,5 +47,9 @@ func newOperations(observationContext *observation.Context) *operations {
 
 		// Commit Graph
 		commitGraph: op("CommitGraph"),
+
+		// Coverage
+		gitTreeCodeIntelCoverage: op("GitTreeCodeIntelCoverage"),
+		codeIntelCoverageSummary: op("CodeIntelCoverageSummary"),
 	}
 }
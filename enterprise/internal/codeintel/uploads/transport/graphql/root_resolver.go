@@ -7,6 +7,7 @@ import (
 	"github.com/opentracing/opentracing-go/log"
 
 	sharedresolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/resolvers"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -141,3 +142,70 @@ func (r *rootResolver) DeleteLSIFUploads(ctx context.Context, args *resolverstub
 
 	return &resolverstubs.EmptyResponse{}, nil
 }
+
+// maxCoverageDepth is the maximum number of levels of subdirectories returned by GitTreeCodeIntelCoverage.
+const maxCoverageDepth = 3
+
+// 🚨 SECURITY: Only entrypoint is within the git tree resolver so the user is already authenticated
+func (r *rootResolver) GitTreeCodeIntelCoverage(ctx context.Context, args *resolverstubs.GitTreeCodeIntelCoverageArgs) (_ resolverstubs.CodeIntelDirectoryCoverageResolver, err error) {
+	ctx, traceErrs, endObservation := r.operations.gitTreeCodeIntelCoverage.WithErrors(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repoID", int(args.Repo.ID)),
+		log.String("commit", args.Commit),
+		log.String("path", args.Path),
+		log.Int("depth", int(args.Depth)),
+	}})
+	endObservation.OnCancel(ctx, 1, observation.Args{})
+
+	depth := int(args.Depth)
+	if depth < 0 {
+		depth = 0
+	} else if depth > maxCoverageDepth {
+		depth = maxCoverageDepth
+	}
+
+	coverage, err := r.uploadSvc.GetDirectoryCoverage(ctx, int(args.Repo.ID), args.Commit, args.Path, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDirectoryCoverageResolver(coverage, r.newUploadCoverageResolverFactory(traceErrs)), nil
+}
+
+// 🚨 SECURITY: Only site admins may view the code intelligence coverage of all repositories
+func (r *rootResolver) CodeIntelCoverageSummary(ctx context.Context, args *resolverstubs.CodeIntelCoverageSummaryArgs) (_ resolverstubs.CodeIntelRepositoryCoverageConnectionResolver, err error) {
+	ctx, traceErrs, endObservation := r.operations.codeIntelCoverageSummary.WithErrors(ctx, &err, observation.Args{})
+	endObservation.OnCancel(ctx, 1, observation.Args{})
+
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.autoindexSvc.GetUnsafeDB()); err != nil {
+		return nil, err
+	}
+
+	offset, err := decodeIntCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	repositories, totalCount, err := r.uploadSvc.GetRepositoryCoverage(ctx, shared.GetRepositoryCoverageOptions{
+		Term:   derefString(args.Query, ""),
+		Limit:  derefInt32(args.First, DefaultRepositoryCoveragePageSize),
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRepositoryCoverageConnectionResolver(r.autoindexSvc.GetUnsafeDB(), repositories, totalCount, offset, r.newUploadCoverageResolverFactory(traceErrs)), nil
+}
+
+func (r *rootResolver) newUploadCoverageResolverFactory(traceErrs *observation.ErrCollector) *uploadCoverageResolverFactory {
+	return &uploadCoverageResolverFactory{
+		uploadSvc:    r.uploadSvc,
+		autoindexSvc: r.autoindexSvc,
+		policySvc:    r.policySvc,
+		// Create a new prefetcher here as we only want to cache upload and index records in
+		// the same graphQL request, not across different request.
+		prefetcher: sharedresolvers.NewPrefetcher(r.autoindexSvc, r.uploadSvc),
+		traceErrs:  traceErrs,
+		resolvers:  map[int]resolverstubs.LSIFUploadResolver{},
+	}
+}
@@ -8,7 +8,11 @@ import (
 	"github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/log/logtest"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	uploadstypes "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -59,3 +63,134 @@ func TestDeleteLSIFUploadUnauthenticated(t *testing.T) {
 		t.Errorf("unexpected error. want=%q have=%q", auth.ErrNotAuthenticated, err)
 	}
 }
+
+func TestGitTreeCodeIntelCoverage(t *testing.T) {
+	mockUploadService := NewMockUploadService()
+	mockPolicyService := NewMockPolicyService()
+	mockAutoIndexingService := NewMockAutoIndexingService()
+
+	distance := 3
+	mockUploadService.GetDirectoryCoverageFunc.SetDefaultReturn(&shared.DirectoryCoverage{
+		Path: "",
+		Uploads: []shared.UploadCoverage{
+			{Upload: uploadstypes.Dump{ID: 1, Root: "web/"}, Distance: &distance},
+		},
+		Children: []*shared.DirectoryCoverage{
+			{Path: "web/", Uploads: []shared.UploadCoverage{{Upload: uploadstypes.Dump{ID: 1, Root: "web/"}, Distance: &distance}}},
+			{Path: "cmd/"},
+		},
+	}, nil)
+
+	rootResolver := NewRootResolver(mockUploadService, mockAutoIndexingService, mockPolicyService, &observation.TestContext)
+
+	resolver, err := rootResolver.GitTreeCodeIntelCoverage(context.Background(), &resolverstubs.GitTreeCodeIntelCoverageArgs{
+		Repo:   &types.Repo{ID: 50},
+		Commit: "deadbeef",
+		Path:   "",
+		Depth:  10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if history := mockUploadService.GetDirectoryCoverageFunc.History(); len(history) != 1 || history[0].Arg4 != maxCoverageDepth {
+		t.Fatalf("expected depth to be clamped to %d", maxCoverageDepth)
+	}
+
+	children := resolver.Children()
+	if len(children) != 2 || children[0].Path() != "web/" || children[1].Path() != "cmd/" {
+		t.Fatalf("unexpected children")
+	}
+	if uploads := children[1].Uploads(); len(uploads) != 0 {
+		t.Errorf("unexpected uploads for uncovered directory. want=%d have=%d", 0, len(uploads))
+	}
+
+	rootUploads := resolver.Uploads()
+	childUploads := children[0].Uploads()
+	if len(rootUploads) != 1 || len(childUploads) != 1 {
+		t.Fatalf("unexpected uploads")
+	}
+	if commitsBehind := rootUploads[0].CommitsBehind(); commitsBehind == nil || *commitsBehind != 3 {
+		t.Errorf("unexpected commits behind. want=%d have=%v", 3, commitsBehind)
+	}
+	if rootUploads[0].Upload() != childUploads[0].Upload() {
+		t.Errorf("expected upload resolver to be shared between directories")
+	}
+}
+
+func TestCodeIntelCoverageSummary(t *testing.T) {
+	users := database.NewStrictMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true}, nil)
+
+	db := database.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	mockUploadService := NewMockUploadService()
+	mockPolicyService := NewMockPolicyService()
+	mockAutoIndexingService := NewMockAutoIndexingService()
+	mockAutoIndexingService.GetUnsafeDBFunc.SetDefaultReturn(db)
+
+	mockUploadService.GetRepositoryCoverageFunc.SetDefaultReturn([]shared.RepositoryCoverage{
+		{RepositoryID: 50, Commit: "deadbeef"},
+		{RepositoryID: 51},
+	}, 5, nil)
+
+	rootResolver := NewRootResolver(mockUploadService, mockAutoIndexingService, mockPolicyService, &observation.TestContext)
+
+	query := "test"
+	after := base64.StdEncoding.EncodeToString([]byte("2"))
+	resolver, err := rootResolver.CodeIntelCoverageSummary(context.Background(), &resolverstubs.CodeIntelCoverageSummaryArgs{
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: intPtr(2)},
+		Query:          &query,
+		After:          &after,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedOpts := shared.GetRepositoryCoverageOptions{Term: "test", Limit: 2, Offset: 2}
+	if history := mockUploadService.GetRepositoryCoverageFunc.History(); len(history) != 1 || history[0].Arg1 != expectedOpts {
+		t.Fatalf("unexpected options. want=%+v", expectedOpts)
+	}
+
+	nodes, err := resolver.Nodes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("unexpected number of nodes. want=%d have=%d", 2, len(nodes))
+	}
+	if commit := nodes[0].Commit(); commit == nil || *commit != "deadbeef" {
+		t.Errorf("unexpected commit. want=%q have=%v", "deadbeef", commit)
+	}
+	if commit := nodes[1].Commit(); commit != nil {
+		t.Errorf("unexpected commit. want=nil have=%q", *commit)
+	}
+
+	pageInfo, err := resolver.PageInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !pageInfo.HasNextPage() || pageInfo.EndCursor() == nil || *pageInfo.EndCursor() != base64.StdEncoding.EncodeToString([]byte("4")) {
+		t.Errorf("unexpected page info")
+	}
+}
+
+func TestCodeIntelCoverageSummaryUnauthenticated(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, nil)
+
+	mockUploadService := NewMockUploadService()
+	mockPolicyService := NewMockPolicyService()
+	mockAutoIndexingService := NewMockAutoIndexingService()
+	mockAutoIndexingService.GetUnsafeDBFunc.SetDefaultReturn(db)
+
+	rootResolver := NewRootResolver(mockUploadService, mockAutoIndexingService, mockPolicyService, &observation.TestContext)
+
+	if _, err := rootResolver.CodeIntelCoverageSummary(context.Background(), &resolverstubs.CodeIntelCoverageSummaryArgs{}); err != auth.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", auth.ErrNotAuthenticated, err)
+	}
+	if len(mockUploadService.GetRepositoryCoverageFunc.History()) != 0 {
+		t.Errorf("unexpected call to GetRepositoryCoverage")
+	}
+}
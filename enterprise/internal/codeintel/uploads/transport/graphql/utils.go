@@ -8,6 +8,7 @@ import (
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/shared/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
@@ -205,6 +206,30 @@ func decodeIntCursor(val *string) (int, error) {
 	return strconv.Atoi(cursor)
 }
 
+// dumpToUpload converts a dump into an upload so that it can be rendered by an upload resolver.
+func dumpToUpload(dump types.Dump) types.Upload {
+	return types.Upload{
+		ID:                dump.ID,
+		Commit:            dump.Commit,
+		Root:              dump.Root,
+		VisibleAtTip:      dump.VisibleAtTip,
+		UploadedAt:        dump.UploadedAt,
+		State:             dump.State,
+		FailureMessage:    dump.FailureMessage,
+		StartedAt:         dump.StartedAt,
+		FinishedAt:        dump.FinishedAt,
+		ProcessAfter:      dump.ProcessAfter,
+		NumResets:         dump.NumResets,
+		NumFailures:       dump.NumFailures,
+		RepositoryID:      dump.RepositoryID,
+		RepositoryName:    dump.RepositoryName,
+		Indexer:           dump.Indexer,
+		IndexerVersion:    dump.IndexerVersion,
+		UploadedParts:     []int{},
+		AssociatedIndexID: dump.AssociatedIndexID,
+	}
+}
+
 // strPtr creates a pointer to the given value. If the value is an
 // empty string, a nil pointer is returned.
 func strPtr(val string) *string {
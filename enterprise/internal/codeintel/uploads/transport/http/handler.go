@@ -48,6 +48,8 @@ func newHandler(
 			Indexer:           getQuery(r, "indexerName"),
 			IndexerVersion:    getQuery(r, "indexerVersion"),
 			AssociatedIndexID: getQueryInt(r, "associatedIndexId"),
+			Ephemeral:         getQueryBool(r, "ephemeral"),
+			BaseUploadID:      getQueryInt(r, "baseUploadId"),
 		}, 0, nil
 	}
 
@@ -15,6 +15,8 @@ type UploadMetadata struct {
 	Indexer           string
 	IndexerVersion    string
 	AssociatedIndexID int
+	Ephemeral         bool
+	BaseUploadID      int
 }
 
 type uploadHandlerShim struct {
@@ -40,6 +42,11 @@ func (s *uploadHandlerShim) InsertUpload(ctx context.Context, upload uploadhandl
 		associatedIndexID = &upload.Metadata.AssociatedIndexID
 	}
 
+	var baseUploadID *int
+	if upload.Metadata.BaseUploadID != 0 {
+		baseUploadID = &upload.Metadata.BaseUploadID
+	}
+
 	return s.Store.InsertUpload(ctx, types.Upload{
 		ID:                upload.ID,
 		State:             upload.State,
@@ -53,6 +60,8 @@ func (s *uploadHandlerShim) InsertUpload(ctx context.Context, upload uploadhandl
 		Indexer:           upload.Metadata.Indexer,
 		IndexerVersion:    upload.Metadata.IndexerVersion,
 		AssociatedIndexID: associatedIndexID,
+		Ephemeral:         upload.Metadata.Ephemeral,
+		BaseUploadID:      baseUploadID,
 	})
 }
 
@@ -78,12 +87,16 @@ func (s *uploadHandlerShim) GetUploadByID(ctx context.Context, uploadID int) (up
 			Root:           upload.Root,
 			Indexer:        upload.Indexer,
 			IndexerVersion: upload.IndexerVersion,
+			Ephemeral:      upload.Ephemeral,
 		},
 	}
 
 	if upload.AssociatedIndexID != nil {
 		u.Metadata.AssociatedIndexID = *upload.AssociatedIndexID
 	}
+	if upload.BaseUploadID != nil {
+		u.Metadata.BaseUploadID = *upload.BaseUploadID
+	}
 
 	return u, true, nil
 }
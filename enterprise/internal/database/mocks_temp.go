@@ -6847,6 +6847,10 @@ type MockEnterpriseDB struct {
 	// ExecutorsFunc is an instance of a mock function object controlling
 	// the behavior of the method Executors.
 	ExecutorsFunc *EnterpriseDBExecutorsFunc
+	// ExplicitPermissionsBulkImportJobsFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// ExplicitPermissionsBulkImportJobs.
+	ExplicitPermissionsBulkImportJobsFunc *EnterpriseDBExplicitPermissionsBulkImportJobsFunc
 	// ExternalServicesFunc is an instance of a mock function object
 	// controlling the behavior of the method ExternalServices.
 	ExternalServicesFunc *EnterpriseDBExternalServicesFunc
@@ -6895,12 +6899,18 @@ type MockEnterpriseDB struct {
 	// RepoKVPsFunc is an instance of a mock function object controlling the
 	// behavior of the method RepoKVPs.
 	RepoKVPsFunc *EnterpriseDBRepoKVPsFunc
+	// RepoNameHistoryFunc is an instance of a mock function object
+	// controlling the behavior of the method RepoNameHistory.
+	RepoNameHistoryFunc *EnterpriseDBRepoNameHistoryFunc
 	// RepoStatisticsFunc is an instance of a mock function object
 	// controlling the behavior of the method RepoStatistics.
 	RepoStatisticsFunc *EnterpriseDBRepoStatisticsFunc
 	// ReposFunc is an instance of a mock function object controlling the
 	// behavior of the method Repos.
 	ReposFunc *EnterpriseDBReposFunc
+	// RolesFunc is an instance of a mock function object controlling the
+	// behavior of the method Roles.
+	RolesFunc *EnterpriseDBRolesFunc
 	// SavedSearchesFunc is an instance of a mock function object
 	// controlling the behavior of the method SavedSearches.
 	SavedSearchesFunc *EnterpriseDBSavedSearchesFunc
@@ -6931,9 +6941,15 @@ type MockEnterpriseDB struct {
 	// UserExternalAccountsFunc is an instance of a mock function object
 	// controlling the behavior of the method UserExternalAccounts.
 	UserExternalAccountsFunc *EnterpriseDBUserExternalAccountsFunc
+	// UserSessionsFunc is an instance of a mock function object controlling
+	// the behavior of the method UserSessions.
+	UserSessionsFunc *EnterpriseDBUserSessionsFunc
 	// UsersFunc is an instance of a mock function object controlling the
 	// behavior of the method Users.
 	UsersFunc *EnterpriseDBUsersFunc
+	// VirtualReposFunc is an instance of a mock function object controlling
+	// the behavior of the method VirtualRepos.
+	VirtualReposFunc *EnterpriseDBVirtualReposFunc
 	// WebhookLogsFunc is an instance of a mock function object controlling
 	// the behavior of the method WebhookLogs.
 	WebhookLogsFunc *EnterpriseDBWebhookLogsFunc
@@ -7004,6 +7020,11 @@ func NewMockEnterpriseDB() *MockEnterpriseDB {
 				return
 			},
 		},
+		ExplicitPermissionsBulkImportJobsFunc: &EnterpriseDBExplicitPermissionsBulkImportJobsFunc{
+			defaultHook: func() (r0 database.ExplicitPermissionsBulkImportJobsStore) {
+				return
+			},
+		},
 		ExternalServicesFunc: &EnterpriseDBExternalServicesFunc{
 			defaultHook: func() (r0 database.ExternalServiceStore) {
 				return
@@ -7084,6 +7105,11 @@ func NewMockEnterpriseDB() *MockEnterpriseDB {
 				return
 			},
 		},
+		RepoNameHistoryFunc: &EnterpriseDBRepoNameHistoryFunc{
+			defaultHook: func() (r0 database.RepoNameHistoryStore) {
+				return
+			},
+		},
 		RepoStatisticsFunc: &EnterpriseDBRepoStatisticsFunc{
 			defaultHook: func() (r0 database.RepoStatisticsStore) {
 				return
@@ -7094,6 +7120,11 @@ func NewMockEnterpriseDB() *MockEnterpriseDB {
 				return
 			},
 		},
+		RolesFunc: &EnterpriseDBRolesFunc{
+			defaultHook: func() (r0 database.RoleStore) {
+				return
+			},
+		},
 		SavedSearchesFunc: &EnterpriseDBSavedSearchesFunc{
 			defaultHook: func() (r0 database.SavedSearchStore) {
 				return
@@ -7144,11 +7175,21 @@ func NewMockEnterpriseDB() *MockEnterpriseDB {
 				return
 			},
 		},
+		UserSessionsFunc: &EnterpriseDBUserSessionsFunc{
+			defaultHook: func() (r0 database.UserSessionStore) {
+				return
+			},
+		},
 		UsersFunc: &EnterpriseDBUsersFunc{
 			defaultHook: func() (r0 database.UserStore) {
 				return
 			},
 		},
+		VirtualReposFunc: &EnterpriseDBVirtualReposFunc{
+			defaultHook: func() (r0 database.VirtualReposStore) {
+				return
+			},
+		},
 		WebhookLogsFunc: &EnterpriseDBWebhookLogsFunc{
 			defaultHook: func(encryption.Key) (r0 database.WebhookLogStore) {
 				return
@@ -7226,6 +7267,11 @@ func NewStrictMockEnterpriseDB() *MockEnterpriseDB {
 				panic("unexpected invocation of MockEnterpriseDB.Executors")
 			},
 		},
+		ExplicitPermissionsBulkImportJobsFunc: &EnterpriseDBExplicitPermissionsBulkImportJobsFunc{
+			defaultHook: func() database.ExplicitPermissionsBulkImportJobsStore {
+				panic("unexpected invocation of MockEnterpriseDB.ExplicitPermissionsBulkImportJobs")
+			},
+		},
 		ExternalServicesFunc: &EnterpriseDBExternalServicesFunc{
 			defaultHook: func() database.ExternalServiceStore {
 				panic("unexpected invocation of MockEnterpriseDB.ExternalServices")
@@ -7306,6 +7352,11 @@ func NewStrictMockEnterpriseDB() *MockEnterpriseDB {
 				panic("unexpected invocation of MockEnterpriseDB.RepoKVPs")
 			},
 		},
+		RepoNameHistoryFunc: &EnterpriseDBRepoNameHistoryFunc{
+			defaultHook: func() database.RepoNameHistoryStore {
+				panic("unexpected invocation of MockEnterpriseDB.RepoNameHistory")
+			},
+		},
 		RepoStatisticsFunc: &EnterpriseDBRepoStatisticsFunc{
 			defaultHook: func() database.RepoStatisticsStore {
 				panic("unexpected invocation of MockEnterpriseDB.RepoStatistics")
@@ -7316,6 +7367,11 @@ func NewStrictMockEnterpriseDB() *MockEnterpriseDB {
 				panic("unexpected invocation of MockEnterpriseDB.Repos")
 			},
 		},
+		RolesFunc: &EnterpriseDBRolesFunc{
+			defaultHook: func() database.RoleStore {
+				panic("unexpected invocation of MockEnterpriseDB.Roles")
+			},
+		},
 		SavedSearchesFunc: &EnterpriseDBSavedSearchesFunc{
 			defaultHook: func() database.SavedSearchStore {
 				panic("unexpected invocation of MockEnterpriseDB.SavedSearches")
@@ -7366,11 +7422,21 @@ func NewStrictMockEnterpriseDB() *MockEnterpriseDB {
 				panic("unexpected invocation of MockEnterpriseDB.UserExternalAccounts")
 			},
 		},
+		UserSessionsFunc: &EnterpriseDBUserSessionsFunc{
+			defaultHook: func() database.UserSessionStore {
+				panic("unexpected invocation of MockEnterpriseDB.UserSessions")
+			},
+		},
 		UsersFunc: &EnterpriseDBUsersFunc{
 			defaultHook: func() database.UserStore {
 				panic("unexpected invocation of MockEnterpriseDB.Users")
 			},
 		},
+		VirtualReposFunc: &EnterpriseDBVirtualReposFunc{
+			defaultHook: func() database.VirtualReposStore {
+				panic("unexpected invocation of MockEnterpriseDB.VirtualRepos")
+			},
+		},
 		WebhookLogsFunc: &EnterpriseDBWebhookLogsFunc{
 			defaultHook: func(encryption.Key) database.WebhookLogStore {
 				panic("unexpected invocation of MockEnterpriseDB.WebhookLogs")
@@ -7427,6 +7493,9 @@ func NewMockEnterpriseDBFrom(i EnterpriseDB) *MockEnterpriseDB {
 		ExecutorsFunc: &EnterpriseDBExecutorsFunc{
 			defaultHook: i.Executors,
 		},
+		ExplicitPermissionsBulkImportJobsFunc: &EnterpriseDBExplicitPermissionsBulkImportJobsFunc{
+			defaultHook: i.ExplicitPermissionsBulkImportJobs,
+		},
 		ExternalServicesFunc: &EnterpriseDBExternalServicesFunc{
 			defaultHook: i.ExternalServices,
 		},
@@ -7475,12 +7544,18 @@ func NewMockEnterpriseDBFrom(i EnterpriseDB) *MockEnterpriseDB {
 		RepoKVPsFunc: &EnterpriseDBRepoKVPsFunc{
 			defaultHook: i.RepoKVPs,
 		},
+		RepoNameHistoryFunc: &EnterpriseDBRepoNameHistoryFunc{
+			defaultHook: i.RepoNameHistory,
+		},
 		RepoStatisticsFunc: &EnterpriseDBRepoStatisticsFunc{
 			defaultHook: i.RepoStatistics,
 		},
 		ReposFunc: &EnterpriseDBReposFunc{
 			defaultHook: i.Repos,
 		},
+		RolesFunc: &EnterpriseDBRolesFunc{
+			defaultHook: i.Roles,
+		},
 		SavedSearchesFunc: &EnterpriseDBSavedSearchesFunc{
 			defaultHook: i.SavedSearches,
 		},
@@ -7511,9 +7586,15 @@ func NewMockEnterpriseDBFrom(i EnterpriseDB) *MockEnterpriseDB {
 		UserExternalAccountsFunc: &EnterpriseDBUserExternalAccountsFunc{
 			defaultHook: i.UserExternalAccounts,
 		},
+		UserSessionsFunc: &EnterpriseDBUserSessionsFunc{
+			defaultHook: i.UserSessions,
+		},
 		UsersFunc: &EnterpriseDBUsersFunc{
 			defaultHook: i.Users,
 		},
+		VirtualReposFunc: &EnterpriseDBVirtualReposFunc{
+			defaultHook: i.VirtualRepos,
+		},
 		WebhookLogsFunc: &EnterpriseDBWebhookLogsFunc{
 			defaultHook: i.WebhookLogs,
 		},
@@ -8646,6 +8727,109 @@ func (c EnterpriseDBExecutorsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnterpriseDBExplicitPermissionsBulkImportJobsFunc describes the behavior
+// when the ExplicitPermissionsBulkImportJobs method of the parent
+// MockEnterpriseDB instance is invoked.
+type EnterpriseDBExplicitPermissionsBulkImportJobsFunc struct {
+	defaultHook func() database.ExplicitPermissionsBulkImportJobsStore
+	hooks       []func() database.ExplicitPermissionsBulkImportJobsStore
+	history     []EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall
+	mutex       sync.Mutex
+}
+
+// ExplicitPermissionsBulkImportJobs delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockEnterpriseDB) ExplicitPermissionsBulkImportJobs() database.ExplicitPermissionsBulkImportJobsStore {
+	r0 := m.ExplicitPermissionsBulkImportJobsFunc.nextHook()()
+	m.ExplicitPermissionsBulkImportJobsFunc.appendCall(EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// ExplicitPermissionsBulkImportJobs method of the parent MockEnterpriseDB
+// instance is invoked and the hook queue is empty.
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) SetDefaultHook(hook func() database.ExplicitPermissionsBulkImportJobsStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ExplicitPermissionsBulkImportJobs method of the parent MockEnterpriseDB
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) PushHook(hook func() database.ExplicitPermissionsBulkImportJobsStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) SetDefaultReturn(r0 database.ExplicitPermissionsBulkImportJobsStore) {
+	f.SetDefaultHook(func() database.ExplicitPermissionsBulkImportJobsStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) PushReturn(r0 database.ExplicitPermissionsBulkImportJobsStore) {
+	f.PushHook(func() database.ExplicitPermissionsBulkImportJobsStore {
+		return r0
+	})
+}
+
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) nextHook() func() database.ExplicitPermissionsBulkImportJobsStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) appendCall(r0 EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall objects describing
+// the invocations of this function.
+func (f *EnterpriseDBExplicitPermissionsBulkImportJobsFunc) History() []EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall {
+	f.mutex.Lock()
+	history := make([]EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall is an object that
+// describes an invocation of method ExplicitPermissionsBulkImportJobs on an
+// instance of MockEnterpriseDB.
+type EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.ExplicitPermissionsBulkImportJobsStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnterpriseDBExplicitPermissionsBulkImportJobsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnterpriseDBExternalServicesFunc describes the behavior when the
 // ExternalServices method of the parent MockEnterpriseDB instance is
 // invoked.
@@ -10267,6 +10451,106 @@ func (c EnterpriseDBRepoKVPsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnterpriseDBRepoNameHistoryFunc describes the behavior when the
+// RepoNameHistory method of the parent MockEnterpriseDB instance is
+// invoked.
+type EnterpriseDBRepoNameHistoryFunc struct {
+	defaultHook func() database.RepoNameHistoryStore
+	hooks       []func() database.RepoNameHistoryStore
+	history     []EnterpriseDBRepoNameHistoryFuncCall
+	mutex       sync.Mutex
+}
+
+// RepoNameHistory delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockEnterpriseDB) RepoNameHistory() database.RepoNameHistoryStore {
+	r0 := m.RepoNameHistoryFunc.nextHook()()
+	m.RepoNameHistoryFunc.appendCall(EnterpriseDBRepoNameHistoryFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the RepoNameHistory
+// method of the parent MockEnterpriseDB instance is invoked and the hook
+// queue is empty.
+func (f *EnterpriseDBRepoNameHistoryFunc) SetDefaultHook(hook func() database.RepoNameHistoryStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RepoNameHistory method of the parent MockEnterpriseDB instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *EnterpriseDBRepoNameHistoryFunc) PushHook(hook func() database.RepoNameHistoryStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnterpriseDBRepoNameHistoryFunc) SetDefaultReturn(r0 database.RepoNameHistoryStore) {
+	f.SetDefaultHook(func() database.RepoNameHistoryStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnterpriseDBRepoNameHistoryFunc) PushReturn(r0 database.RepoNameHistoryStore) {
+	f.PushHook(func() database.RepoNameHistoryStore {
+		return r0
+	})
+}
+
+func (f *EnterpriseDBRepoNameHistoryFunc) nextHook() func() database.RepoNameHistoryStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnterpriseDBRepoNameHistoryFunc) appendCall(r0 EnterpriseDBRepoNameHistoryFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnterpriseDBRepoNameHistoryFuncCall objects
+// describing the invocations of this function.
+func (f *EnterpriseDBRepoNameHistoryFunc) History() []EnterpriseDBRepoNameHistoryFuncCall {
+	f.mutex.Lock()
+	history := make([]EnterpriseDBRepoNameHistoryFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnterpriseDBRepoNameHistoryFuncCall is an object that describes an
+// invocation of method RepoNameHistory on an instance of MockEnterpriseDB.
+type EnterpriseDBRepoNameHistoryFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.RepoNameHistoryStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnterpriseDBRepoNameHistoryFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnterpriseDBRepoNameHistoryFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnterpriseDBRepoStatisticsFunc describes the behavior when the
 // RepoStatistics method of the parent MockEnterpriseDB instance is invoked.
 type EnterpriseDBRepoStatisticsFunc struct {
@@ -10464,6 +10748,104 @@ func (c EnterpriseDBReposFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnterpriseDBRolesFunc describes the behavior when the Roles method of the
+// parent MockEnterpriseDB instance is invoked.
+type EnterpriseDBRolesFunc struct {
+	defaultHook func() database.RoleStore
+	hooks       []func() database.RoleStore
+	history     []EnterpriseDBRolesFuncCall
+	mutex       sync.Mutex
+}
+
+// Roles delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockEnterpriseDB) Roles() database.RoleStore {
+	r0 := m.RolesFunc.nextHook()()
+	m.RolesFunc.appendCall(EnterpriseDBRolesFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Roles method of the
+// parent MockEnterpriseDB instance is invoked and the hook queue is empty.
+func (f *EnterpriseDBRolesFunc) SetDefaultHook(hook func() database.RoleStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Roles method of the parent MockEnterpriseDB instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *EnterpriseDBRolesFunc) PushHook(hook func() database.RoleStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnterpriseDBRolesFunc) SetDefaultReturn(r0 database.RoleStore) {
+	f.SetDefaultHook(func() database.RoleStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnterpriseDBRolesFunc) PushReturn(r0 database.RoleStore) {
+	f.PushHook(func() database.RoleStore {
+		return r0
+	})
+}
+
+func (f *EnterpriseDBRolesFunc) nextHook() func() database.RoleStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnterpriseDBRolesFunc) appendCall(r0 EnterpriseDBRolesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnterpriseDBRolesFuncCall objects
+// describing the invocations of this function.
+func (f *EnterpriseDBRolesFunc) History() []EnterpriseDBRolesFuncCall {
+	f.mutex.Lock()
+	history := make([]EnterpriseDBRolesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnterpriseDBRolesFuncCall is an object that describes an invocation of
+// method Roles on an instance of MockEnterpriseDB.
+type EnterpriseDBRolesFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.RoleStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnterpriseDBRolesFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnterpriseDBRolesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnterpriseDBSavedSearchesFunc describes the behavior when the
 // SavedSearches method of the parent MockEnterpriseDB instance is invoked.
 type EnterpriseDBSavedSearchesFunc struct {
@@ -11471,6 +11853,105 @@ func (c EnterpriseDBUserExternalAccountsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnterpriseDBUserSessionsFunc describes the behavior when the UserSessions
+// method of the parent MockEnterpriseDB instance is invoked.
+type EnterpriseDBUserSessionsFunc struct {
+	defaultHook func() database.UserSessionStore
+	hooks       []func() database.UserSessionStore
+	history     []EnterpriseDBUserSessionsFuncCall
+	mutex       sync.Mutex
+}
+
+// UserSessions delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockEnterpriseDB) UserSessions() database.UserSessionStore {
+	r0 := m.UserSessionsFunc.nextHook()()
+	m.UserSessionsFunc.appendCall(EnterpriseDBUserSessionsFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the UserSessions method
+// of the parent MockEnterpriseDB instance is invoked and the hook queue is
+// empty.
+func (f *EnterpriseDBUserSessionsFunc) SetDefaultHook(hook func() database.UserSessionStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UserSessions method of the parent MockEnterpriseDB instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *EnterpriseDBUserSessionsFunc) PushHook(hook func() database.UserSessionStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnterpriseDBUserSessionsFunc) SetDefaultReturn(r0 database.UserSessionStore) {
+	f.SetDefaultHook(func() database.UserSessionStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnterpriseDBUserSessionsFunc) PushReturn(r0 database.UserSessionStore) {
+	f.PushHook(func() database.UserSessionStore {
+		return r0
+	})
+}
+
+func (f *EnterpriseDBUserSessionsFunc) nextHook() func() database.UserSessionStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnterpriseDBUserSessionsFunc) appendCall(r0 EnterpriseDBUserSessionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnterpriseDBUserSessionsFuncCall objects
+// describing the invocations of this function.
+func (f *EnterpriseDBUserSessionsFunc) History() []EnterpriseDBUserSessionsFuncCall {
+	f.mutex.Lock()
+	history := make([]EnterpriseDBUserSessionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnterpriseDBUserSessionsFuncCall is an object that describes an
+// invocation of method UserSessions on an instance of MockEnterpriseDB.
+type EnterpriseDBUserSessionsFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.UserSessionStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnterpriseDBUserSessionsFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnterpriseDBUserSessionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnterpriseDBUsersFunc describes the behavior when the Users method of the
 // parent MockEnterpriseDB instance is invoked.
 type EnterpriseDBUsersFunc struct {
@@ -11569,6 +12050,105 @@ func (c EnterpriseDBUsersFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnterpriseDBVirtualReposFunc describes the behavior when the VirtualRepos
+// method of the parent MockEnterpriseDB instance is invoked.
+type EnterpriseDBVirtualReposFunc struct {
+	defaultHook func() database.VirtualReposStore
+	hooks       []func() database.VirtualReposStore
+	history     []EnterpriseDBVirtualReposFuncCall
+	mutex       sync.Mutex
+}
+
+// VirtualRepos delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockEnterpriseDB) VirtualRepos() database.VirtualReposStore {
+	r0 := m.VirtualReposFunc.nextHook()()
+	m.VirtualReposFunc.appendCall(EnterpriseDBVirtualReposFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the VirtualRepos method
+// of the parent MockEnterpriseDB instance is invoked and the hook queue is
+// empty.
+func (f *EnterpriseDBVirtualReposFunc) SetDefaultHook(hook func() database.VirtualReposStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// VirtualRepos method of the parent MockEnterpriseDB instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *EnterpriseDBVirtualReposFunc) PushHook(hook func() database.VirtualReposStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *EnterpriseDBVirtualReposFunc) SetDefaultReturn(r0 database.VirtualReposStore) {
+	f.SetDefaultHook(func() database.VirtualReposStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *EnterpriseDBVirtualReposFunc) PushReturn(r0 database.VirtualReposStore) {
+	f.PushHook(func() database.VirtualReposStore {
+		return r0
+	})
+}
+
+func (f *EnterpriseDBVirtualReposFunc) nextHook() func() database.VirtualReposStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnterpriseDBVirtualReposFunc) appendCall(r0 EnterpriseDBVirtualReposFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnterpriseDBVirtualReposFuncCall objects
+// describing the invocations of this function.
+func (f *EnterpriseDBVirtualReposFunc) History() []EnterpriseDBVirtualReposFuncCall {
+	f.mutex.Lock()
+	history := make([]EnterpriseDBVirtualReposFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnterpriseDBVirtualReposFuncCall is an object that describes an
+// invocation of method VirtualRepos on an instance of MockEnterpriseDB.
+type EnterpriseDBVirtualReposFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.VirtualReposStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnterpriseDBVirtualReposFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnterpriseDBVirtualReposFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // EnterpriseDBWebhookLogsFunc describes the behavior when the WebhookLogs
 // method of the parent MockEnterpriseDB instance is invoked.
 type EnterpriseDBWebhookLogsFunc struct {
@@ -55,6 +55,11 @@ type Job struct {
 	// may be done inside or outside of a Firecracker virtual machine.
 	CliSteps []CliStep `json:"cliSteps"`
 
+	// Caches describe directories that are restored from the file store into
+	// the workspace before the steps are invoked, and saved back to the file
+	// store after all steps have completed successfully.
+	Caches []Cache `json:"caches,omitempty"`
+
 	// RedactedValues is a map from strings to replace to their replacement in the command
 	// output before sending it to the underlying job store. This should contain all worker
 	// environment variables, as well as secret values passed along with the dequeued job
@@ -75,6 +80,7 @@ func (j Job) MarshalJSON() ([]byte, error) {
 			SparseCheckout:      j.SparseCheckout,
 			DockerSteps:         j.DockerSteps,
 			CliSteps:            j.CliSteps,
+			Caches:              j.Caches,
 			RedactedValues:      j.RedactedValues,
 		}
 		v2.VirtualMachineFiles = make(map[string]v2VirtualMachineFile, len(j.VirtualMachineFiles))
@@ -93,6 +99,7 @@ func (j Job) MarshalJSON() ([]byte, error) {
 		SparseCheckout:      j.SparseCheckout,
 		DockerSteps:         j.DockerSteps,
 		CliSteps:            j.CliSteps,
+		Caches:              j.Caches,
 		RedactedValues:      j.RedactedValues,
 	}
 	v1.VirtualMachineFiles = make(map[string]v1VirtualMachineFile, len(j.VirtualMachineFiles))
@@ -131,6 +138,7 @@ func (j *Job) UnmarshalJSON(data []byte) error {
 		}
 		j.DockerSteps = v2.DockerSteps
 		j.CliSteps = v2.CliSteps
+		j.Caches = v2.Caches
 		j.RedactedValues = v2.RedactedValues
 		return nil
 	}
@@ -156,6 +164,7 @@ func (j *Job) UnmarshalJSON(data []byte) error {
 	}
 	j.DockerSteps = v1.DockerSteps
 	j.CliSteps = v1.CliSteps
+	j.Caches = v1.Caches
 	j.RedactedValues = v1.RedactedValues
 	return nil
 }
@@ -176,6 +185,7 @@ type v2Job struct {
 	VirtualMachineFiles map[string]v2VirtualMachineFile `json:"files"`
 	DockerSteps         []DockerStep                    `json:"dockerSteps"`
 	CliSteps            []CliStep                       `json:"cliSteps"`
+	Caches              []Cache                         `json:"caches,omitempty"`
 	RedactedValues      map[string]string               `json:"redactedValues"`
 }
 
@@ -190,6 +200,7 @@ type v1Job struct {
 	VirtualMachineFiles map[string]v1VirtualMachineFile `json:"files"`
 	DockerSteps         []DockerStep                    `json:"dockerSteps"`
 	CliSteps            []CliStep                       `json:"cliSteps"`
+	Caches              []Cache                         `json:"caches,omitempty"`
 	RedactedValues      map[string]string               `json:"redactedValues"`
 }
 
@@ -247,6 +258,25 @@ type DockerStep struct {
 	Env []string `json:"env"`
 }
 
+// Cache describes a set of directories that are persisted in the file store
+// between jobs sharing the same cache key.
+type Cache struct {
+	// Bucket is the bucket in the files store the cache archives belong to.
+	Bucket string `json:"bucket"`
+
+	// Key is the prefix of the cache archive within the bucket. The final key
+	// is derived from this prefix and the contents of the files in KeyFiles.
+	Key string `json:"key"`
+
+	// KeyFiles are paths relative to the workspace root whose contents are
+	// hashed into the cache key.
+	KeyFiles []string `json:"keyFiles,omitempty"`
+
+	// Paths are the absolute directories inside the step containers that are
+	// backed by the cache.
+	Paths []string `json:"paths"`
+}
+
 type CliStep struct {
 	// Key is a unique identifier of the step. It can be used to retrieve the
 	// associated log entry.
@@ -54,6 +54,14 @@ func TestJob_MarshalJSON(t *testing.T) {
 						Env:      []string{"BAZ=FAZ"},
 					},
 				},
+				Caches: []Cache{
+					{
+						Bucket:   "my-caches",
+						Key:      "42/go",
+						KeyFiles: []string{"go.sum"},
+						Paths:    []string{"/root/go/pkg/mod"},
+					},
+				},
 				RedactedValues: map[string]string{
 					"password": "foo",
 				},
@@ -89,6 +97,12 @@ func TestJob_MarshalJSON(t *testing.T) {
 			"dir": "raz/daz",
 			"env": ["BAZ=FAZ"]
 		}],
+		"caches": [{
+			"bucket": "my-caches",
+			"key": "42/go",
+			"keyFiles": ["go.sum"],
+			"paths": ["/root/go/pkg/mod"]
+		}],
 		"redactedValues": {
 			"password": "foo"
 		}
@@ -218,6 +232,12 @@ func TestJob_UnmarshalJSON(t *testing.T) {
 		"dir": "raz/daz",
 		"env": ["BAZ=FAZ"]
 	}],
+	"caches": [{
+		"bucket": "my-caches",
+		"key": "42/go",
+		"keyFiles": ["go.sum"],
+		"paths": ["/root/go/pkg/mod"]
+	}],
 	"redactedValues": {
 		"password": "foo"
 	}
@@ -256,6 +276,14 @@ func TestJob_UnmarshalJSON(t *testing.T) {
 						Env:      []string{"BAZ=FAZ"},
 					},
 				},
+				Caches: []Cache{
+					{
+						Bucket:   "my-caches",
+						Key:      "42/go",
+						KeyFiles: []string{"go.sum"},
+						Paths:    []string{"/root/go/pkg/mod"},
+					},
+				},
 				RedactedValues: map[string]string{
 					"password": "foo",
 				},
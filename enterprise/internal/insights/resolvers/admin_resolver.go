@@ -11,7 +11,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/scheduler"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
-	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -22,8 +21,7 @@ var _ graphqlbackend.InsightSeriesMetadataResolver = &insightSeriesMetadataResol
 var _ graphqlbackend.InsightSeriesQueryStatusResolver = &insightSeriesQueryStatusResolver{}
 
 func (r *Resolver) UpdateInsightSeries(ctx context.Context, args *graphqlbackend.UpdateInsightSeriesArgs) (graphqlbackend.InsightSeriesMetadataPayloadResolver, error) {
-	actr := actor.FromContext(ctx)
-	if err := auth.CheckUserIsSiteAdmin(ctx, r.postgresDB, actr.UID); err != nil {
+	if err := auth.CheckCurrentUserPermission(ctx, r.postgresDB, auth.PermissionCodeInsightsEdit); err != nil {
 		return nil, err
 	}
 
@@ -45,8 +43,7 @@ func (r *Resolver) UpdateInsightSeries(ctx context.Context, args *graphqlbackend
 }
 
 func (r *Resolver) InsightSeriesQueryStatus(ctx context.Context) ([]graphqlbackend.InsightSeriesQueryStatusResolver, error) {
-	actr := actor.FromContext(ctx)
-	if err := auth.CheckUserIsSiteAdmin(ctx, r.postgresDB, actr.UID); err != nil {
+	if err := auth.CheckCurrentUserPermission(ctx, r.postgresDB, auth.PermissionCodeInsightsEdit); err != nil {
 		return nil, err
 	}
 
@@ -81,8 +78,7 @@ func (r *Resolver) InsightSeriesQueryStatus(ctx context.Context) ([]graphqlbacke
 }
 
 func (r *Resolver) InsightViewDebug(ctx context.Context, args graphqlbackend.InsightViewDebugArgs) (graphqlbackend.InsightViewDebugResolver, error) {
-	actr := actor.FromContext(ctx)
-	if err := auth.CheckUserIsSiteAdmin(ctx, r.postgresDB, actr.UID); err != nil {
+	if err := auth.CheckCurrentUserPermission(ctx, r.postgresDB, auth.PermissionCodeInsightsEdit); err != nil {
 		return nil, err
 	}
 	var viewId string
@@ -44,6 +44,11 @@ type Actor struct {
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
 
+	// AccessTokenScopes are the scopes of the access token used to authenticate the
+	// actor. It is nil if the actor wasn't authenticated with an access token, in
+	// which case the actor isn't restricted to any scopes.
+	AccessTokenScopes []string `json:",omitempty"`
+
 	// user is populated lazily by (*Actor).User()
 	user     *types.User
 	userErr  error
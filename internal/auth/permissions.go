@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+// Permission is an action on a site-level feature that can be granted to users
+// that are not site admins through roles.
+type Permission string
+
+const (
+	// PermissionBatchChangesAdmin allows managing the site-wide configuration
+	// of batch changes, such as global code host credentials.
+	PermissionBatchChangesAdmin Permission = "batch_changes:admin"
+	// PermissionCodeInsightsEdit allows managing and debugging the series of
+	// all code insights.
+	PermissionCodeInsightsEdit Permission = "code_insights:edit"
+	// PermissionExecutorSecretsManage allows managing global executor secrets.
+	PermissionExecutorSecretsManage Permission = "executor_secrets:manage"
+)
+
+// CheckPermission returns an error if the actor is NOT granted the permission
+// by one of their roles. Site admins and internal actors have all permissions.
+func CheckPermission(ctx context.Context, db database.DB, a *actor.Actor, permission Permission) error {
+	if a.IsInternal() {
+		return nil
+	}
+	if !a.IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+
+	user, err := a.User(ctx, db.Users())
+	if err != nil {
+		return err
+	}
+	if user.SiteAdmin {
+		return nil
+	}
+
+	ok, err := db.Roles().UserHasPermission(ctx, a.UID, string(permission))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &InsufficientAuthorizationError{Message: fmt.Sprintf("must be site admin or have the %q permission", permission)}
+	}
+	return nil
+}
+
+// CheckCurrentUserPermission returns an error if the current user is NOT
+// granted the permission. See CheckPermission.
+func CheckCurrentUserPermission(ctx context.Context, db database.DB, permission Permission) error {
+	return CheckPermission(ctx, db, actor.FromContext(ctx), permission)
+}
@@ -45,8 +45,14 @@ import (
 // library does not provide.
 //
 // Paths are relative to the root of the repo.
+//
+// PathRules are the path rules defined by site admins, in the same syntax. They
+// are evaluated separately from Paths and can only narrow the access granted by
+// Paths: a path is readable if Paths grants it and the last of PathRules that
+// matches it, if any, is not an exclusion.
 type SubRepoPermissions struct {
-	Paths []string
+	Paths     []string
+	PathRules []string
 }
 
 // ExternalUserPermissions is a collection of accessible repository/project IDs
@@ -138,6 +144,15 @@ type Provider interface {
 	ValidateConnection(ctx context.Context) (warnings []string)
 }
 
+// GroupsCacheInvalidator is implemented by authz providers that cache the members
+// and repositories of groups on the code host, such as organizations and teams,
+// so that code host events can invalidate the cache entries they affect.
+type GroupsCacheInvalidator interface {
+	// InvalidateGroupsCache deletes the cache entry of the given team of the given
+	// organization, or of the organization itself if team is empty.
+	InvalidateGroupsCache(org, team string)
+}
+
 // ErrUnauthenticated indicates an unauthenticated request.
 type ErrUnauthenticated struct{}
 
@@ -1,13 +1,106 @@
 package authz
 
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
 const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	ScopeSearchRead      = "search:read"      // Run searches and read their results.
+	ScopeRepoRead        = "repo:read"        // Read repositories and their contents.
+	ScopeCodeIntelUpload = "codeintel:upload" // Upload precise code intelligence indexes.
+	ScopeBatchesWrite    = "batches:write"    // Create, apply and manage batch changes.
+	ScopeSettingsWrite   = "settings:write"   // Modify user, organization and global settings.
+	ScopeSCIMProvision   = "scim:provision"   // Provision users and groups with the SCIM API.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeCodeIntelUpload,
+	ScopeBatchesWrite,
+	ScopeSettingsWrite,
+	ScopeSCIMProvision,
+}
+
+// ScopesGranting returns the scopes that grant the given scope. Every scope
+// grants itself, and ScopeUserAll grants all scopes except ScopeSiteAdminSudo
+// and ScopeSCIMProvision, which must be granted explicitly.
+func ScopesGranting(scope string) []string {
+	if scope == ScopeUserAll || scope == ScopeSiteAdminSudo || scope == ScopeSCIMProvision {
+		return []string{scope}
+	}
+	return []string{scope, ScopeUserAll}
+}
+
+// HasScope returns true if any of the given scopes grants the required scope.
+func HasScope(scopes []string, required string) bool {
+	for _, granting := range ScopesGranting(required) {
+		for _, scope := range scopes {
+			if scope == granting {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ErrScopeRequired is returned when an access token is used for an action that
+// it does not have the scope for.
+type ErrScopeRequired struct {
+	Scope string
+	// AnyOf, if set, lists the scopes of which any one would have been
+	// sufficient. Scope is then the first of them.
+	AnyOf []string
+}
+
+func (e *ErrScopeRequired) Error() string {
+	if len(e.AnyOf) > 1 {
+		quoted := make([]string, 0, len(e.AnyOf))
+		for _, scope := range e.AnyOf {
+			quoted = append(quoted, fmt.Sprintf("%q", scope))
+		}
+		return fmt.Sprintf("access token does not have any of the scopes %s required for this action", strings.Join(quoted, ", "))
+	}
+	return fmt.Sprintf("access token does not have the scope %q required for this action", e.Scope)
+}
+
+func (e *ErrScopeRequired) Forbidden() bool { return true }
+
+// CheckScope returns an *ErrScopeRequired if the actor in the context is
+// authenticated with an access token that doesn't grant the given scope.
+// Actors that aren't authenticated with an access token are not restricted.
+func CheckScope(ctx context.Context, scope string) error {
+	a := actor.FromContext(ctx)
+	if a.AccessTokenScopes == nil || HasScope(a.AccessTokenScopes, scope) {
+		return nil
+	}
+	return &ErrScopeRequired{Scope: scope}
+}
+
+// CheckAnyScope returns an *ErrScopeRequired if the actor in the context is
+// authenticated with an access token that doesn't grant any of the given
+// scopes. Actors that aren't authenticated with an access token are not
+// restricted.
+func CheckAnyScope(ctx context.Context, scopes ...string) error {
+	a := actor.FromContext(ctx)
+	if a.AccessTokenScopes == nil {
+		return nil
+	}
+	for _, scope := range scopes {
+		if HasScope(a.AccessTokenScopes, scope) {
+			return nil
+		}
+	}
+	return &ErrScopeRequired{Scope: scopes[0], AnyOf: scopes}
 }
@@ -0,0 +1,76 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestHasScope(t *testing.T) {
+	for _, tc := range []struct {
+		scopes   []string
+		required string
+		want     bool
+	}{
+		{scopes: []string{ScopeSearchRead}, required: ScopeSearchRead, want: true},
+		{scopes: []string{ScopeSearchRead}, required: ScopeRepoRead, want: false},
+		{scopes: []string{ScopeUserAll}, required: ScopeBatchesWrite, want: true},
+		{scopes: []string{ScopeUserAll}, required: ScopeSiteAdminSudo, want: false},
+		{scopes: []string{ScopeSiteAdminSudo}, required: ScopeUserAll, want: false},
+		{scopes: []string{ScopeUserAll, ScopeSiteAdminSudo}, required: ScopeSiteAdminSudo, want: true},
+		{scopes: []string{ScopeUserAll}, required: ScopeSCIMProvision, want: false},
+		{scopes: []string{ScopeSCIMProvision}, required: ScopeSCIMProvision, want: true},
+		{scopes: []string{ScopeSCIMProvision}, required: ScopeUserAll, want: false},
+		{scopes: nil, required: ScopeSearchRead, want: false},
+	} {
+		if have := HasScope(tc.scopes, tc.required); have != tc.want {
+			t.Errorf("HasScope(%q, %q): want %t, have %t", tc.scopes, tc.required, tc.want, have)
+		}
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	t.Run("not authenticated with an access token", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+		if err := CheckScope(ctx, ScopeSettingsWrite); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: []string{ScopeRepoRead}})
+		if err := CheckScope(ctx, ScopeRepoRead); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		err := CheckScope(ctx, ScopeSettingsWrite)
+		if want := `access token does not have the scope "settings:write" required for this action`; err == nil || err.Error() != want {
+			t.Fatalf("want error %q, have %v", want, err)
+		}
+	})
+}
+
+func TestCheckAnyScope(t *testing.T) {
+	t.Run("not authenticated with an access token", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+		if err := CheckAnyScope(ctx, ScopeSearchRead, ScopeSettingsWrite); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		for _, scopes := range [][]string{{ScopeSearchRead}, {ScopeSettingsWrite}, {ScopeUserAll}} {
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: scopes})
+			if err := CheckAnyScope(ctx, ScopeSearchRead, ScopeSettingsWrite); err != nil {
+				t.Fatalf("unexpected error for scopes %q: %s", scopes, err)
+			}
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: []string{ScopeBatchesWrite}})
+		err := CheckAnyScope(ctx, ScopeSearchRead, ScopeSettingsWrite)
+		if want := `access token does not have any of the scopes "search:read", "settings:write" required for this action`; err == nil || err.Error() != want {
+			t.Fatalf("want error %q, have %v", want, err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+// PermsDecisionEffect is the effect of a single decision made by an authz provider
+// while fetching permissions.
+type PermsDecisionEffect string
+
+const (
+	// PermsDecisionGrant means the decision grants the user access to the repository.
+	PermsDecisionGrant PermsDecisionEffect = "GRANT"
+	// PermsDecisionDeny means the decision denies the user access to the repository.
+	PermsDecisionDeny PermsDecisionEffect = "DENY"
+	// PermsDecisionInfo is a decision that does not affect access on its own, but
+	// helps to explain the outcome.
+	PermsDecisionInfo PermsDecisionEffect = "INFO"
+)
+
+// PermsDecision is a single step on the path that leads an authz provider to grant
+// or deny a user access to a repository, e.g. a team membership or a protects line.
+type PermsDecision struct {
+	Effect PermsDecisionEffect
+	Reason string
+}
+
+// PermsSimulation records the decisions made by authz providers when fetching the
+// permissions of a single user on a single repository. Providers find the
+// simulation in the context of FetchUserPerms and FetchRepoPerms, and must
+// neither persist anything nor read from their caches while a simulation is in
+// progress, so that decisions reflect the current state of the code host.
+//
+// All methods are safe to call on a nil *PermsSimulation, so providers can record
+// decisions unconditionally.
+type PermsSimulation struct {
+	// Repo is the ID of the simulated repository on the code host.
+	Repo extsvc.RepoID
+	// Account is the ID of the simulated user's account on the code host. It may
+	// be empty if the user has no account on the code host.
+	Account extsvc.AccountID
+
+	mu        sync.Mutex
+	decisions []PermsDecision
+}
+
+type permsSimulationKey struct{}
+
+// WithPermsSimulation returns a copy of ctx carrying the given simulation.
+func WithPermsSimulation(ctx context.Context, s *PermsSimulation) context.Context {
+	return context.WithValue(ctx, permsSimulationKey{}, s)
+}
+
+// PermsSimulationFromContext returns the simulation carried by ctx, or nil if
+// permissions are fetched for real.
+func PermsSimulationFromContext(ctx context.Context) *PermsSimulation {
+	s, _ := ctx.Value(permsSimulationKey{}).(*PermsSimulation)
+	return s
+}
+
+// IsPermsSimulation returns true if permissions are fetched as part of a simulation,
+// in which case caches must be bypassed and nothing must be persisted.
+func IsPermsSimulation(ctx context.Context) bool {
+	return PermsSimulationFromContext(ctx) != nil
+}
+
+// IsRepo returns true if id is the simulated repository.
+func (s *PermsSimulation) IsRepo(id extsvc.RepoID) bool {
+	return s != nil && s.Repo == id
+}
+
+// IsAccount returns true if id is the simulated user's account.
+func (s *PermsSimulation) IsAccount(id extsvc.AccountID) bool {
+	return s != nil && s.Account != "" && s.Account == id
+}
+
+// Grant records a decision that grants access.
+func (s *PermsSimulation) Grant(format string, args ...any) {
+	s.record(PermsDecisionGrant, format, args...)
+}
+
+// Deny records a decision that denies access.
+func (s *PermsSimulation) Deny(format string, args ...any) {
+	s.record(PermsDecisionDeny, format, args...)
+}
+
+// Info records a decision that explains the outcome without affecting access.
+func (s *PermsSimulation) Info(format string, args ...any) {
+	s.record(PermsDecisionInfo, format, args...)
+}
+
+func (s *PermsSimulation) record(effect PermsDecisionEffect, format string, args ...any) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decisions = append(s.decisions, PermsDecision{
+		Effect: effect,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// Decisions returns the decisions recorded so far, in order.
+func (s *PermsSimulation) Decisions() []PermsDecision {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]PermsDecision(nil), s.decisions...)
+}
@@ -0,0 +1,62 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPermsSimulation(t *testing.T) {
+	t.Run("not simulating", func(t *testing.T) {
+		ctx := context.Background()
+		if IsPermsSimulation(ctx) {
+			t.Fatal("want no simulation")
+		}
+
+		// Recording on a nil simulation is a no-op.
+		sim := PermsSimulationFromContext(ctx)
+		sim.Grant("granted")
+		if sim.IsRepo("") || sim.IsAccount("") {
+			t.Fatal("nil simulation must not match anything")
+		}
+		if got := sim.Decisions(); got != nil {
+			t.Fatalf("want no decisions but got %v", got)
+		}
+	})
+
+	t.Run("simulating", func(t *testing.T) {
+		ctx := WithPermsSimulation(context.Background(), &PermsSimulation{Repo: "repo", Account: "alice"})
+		if !IsPermsSimulation(ctx) {
+			t.Fatal("want simulation")
+		}
+
+		sim := PermsSimulationFromContext(ctx)
+		if !sim.IsRepo("repo") || sim.IsRepo("other") {
+			t.Fatal("IsRepo mismatch")
+		}
+		if !sim.IsAccount("alice") || sim.IsAccount("bob") {
+			t.Fatal("IsAccount mismatch")
+		}
+
+		sim.Info("user is %q", "alice")
+		sim.Grant("member of team %q", "backend")
+		sim.Deny("excluded by line %d", 3)
+
+		want := []PermsDecision{
+			{Effect: PermsDecisionInfo, Reason: `user is "alice"`},
+			{Effect: PermsDecisionGrant, Reason: `member of team "backend"`},
+			{Effect: PermsDecisionDeny, Reason: "excluded by line 3"},
+		}
+		if diff := cmp.Diff(want, sim.Decisions()); diff != "" {
+			t.Fatalf("Mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("empty account never matches", func(t *testing.T) {
+		sim := &PermsSimulation{Repo: "repo"}
+		if sim.IsAccount("") {
+			t.Fatal("empty account must not match")
+		}
+	})
+}
@@ -134,24 +134,40 @@ type path struct {
 
 type compiledRules struct {
 	paths []path
+	// pathRules are the path rules defined by site admins, which can only
+	// narrow the access granted by paths.
+	pathRules []path
 }
 
 // GetPermissionsForPath tries to match a given path to a list of rules.
 // Since the last applicable rule is the one that applies, the list is
 // traversed in reverse, and the function returns as soon as a match is found.
 // If no match is found, None is returned.
+//
+// 🚨 SECURITY: The path rules of site admins are evaluated as a separate layer
+// so that they can only deny access to paths, never grant access to paths that
+// the code host excludes.
 func (rules compiledRules) GetPermissionsForPath(path string) Perms {
-	for i := len(rules.paths) - 1; i >= 0; i-- {
-		if rules.paths[i].globPath.Match(path) {
-			if rules.paths[i].exclusion {
+	if lastMatch(rules.paths, path, None) == None {
+		return None
+	}
+
+	// Paths that no path rule matches keep the access granted by the code host.
+	return lastMatch(rules.pathRules, path, Read)
+}
+
+// lastMatch returns the permissions granted by the last of paths matching the
+// given path, or unmatched if none of them matches.
+func lastMatch(paths []path, p string, unmatched Perms) Perms {
+	for i := len(paths) - 1; i >= 0; i-- {
+		if paths[i].globPath.Match(p) {
+			if paths[i].exclusion {
 				return None
 			}
 			return Read
 		}
 	}
-
-	// Return None if no rule matches
-	return None
+	return unmatched
 }
 
 // NewSubRepoPermsClient instantiates an instance of authz.SubRepoPermsClient
@@ -338,62 +354,18 @@ func (s *SubRepoPermsClient) getCompiledRules(ctx context.Context, userID int32)
 			rules: make(map[api.RepoName]compiledRules, len(repoPerms)),
 		}
 		for repo, perms := range repoPerms {
-			paths := make([]path, 0, len(perms.Paths))
-			for _, rule := range perms.Paths {
-				exclusion := strings.HasPrefix(rule, "-")
-				rule = strings.TrimPrefix(rule, "-")
-
-				if !strings.HasPrefix(rule, "/") {
-					rule = "/" + rule
-				}
-
-				g, err := glob.Compile(rule, '/')
-				if err != nil {
-					return nil, errors.Wrap(err, "building include matcher")
-				}
-
-				paths = append(paths, path{globPath: g, exclusion: exclusion, original: rule})
-
-				// Special case. Our glob package does not handle rules starting with a double
-				// wildcard correctly. For example, we would expect `/**/*.java` to match all
-				// java files, but it does not match files at the root, eg `/foo.java`. To get
-				// around this we add an extra rule to cover this case.
-				if strings.HasPrefix(rule, "/**/") {
-					trimmed := rule
-					for {
-						trimmed = strings.TrimPrefix(trimmed, "/**")
-						if strings.HasPrefix(trimmed, "/**/") {
-							// Keep trimming
-							continue
-						}
-						g, err := glob.Compile(trimmed, '/')
-						if err != nil {
-							return nil, errors.Wrap(err, "building include matcher")
-						}
-						paths = append(paths, path{globPath: g, exclusion: exclusion, original: trimmed})
-						break
-					}
-				}
-
-				// We should include all directories above an include rule so that we can browse
-				// to the included items.
-				if exclusion {
-					// Not required for an exclude rule
-					continue
-				}
-
-				dirs := expandDirs(rule)
-				for _, dir := range dirs {
-					g, err := glob.Compile(dir, '/')
-					if err != nil {
-						return nil, errors.Wrap(err, "building include matcher for dir")
-					}
-					paths = append(paths, path{globPath: g, exclusion: false, original: dir})
-				}
+			paths, err := compilePaths(perms.Paths)
+			if err != nil {
+				return nil, err
+			}
+			pathRules, err := compilePaths(perms.PathRules)
+			if err != nil {
+				return nil, err
 			}
 
 			toCache.rules[repo] = compiledRules{
-				paths: paths,
+				paths:     paths,
+				pathRules: pathRules,
 			}
 		}
 		toCache.timestamp = s.clock()
@@ -408,6 +380,64 @@ func (s *SubRepoPermsClient) getCompiledRules(ctx context.Context, userID int32)
 	return compiled, nil
 }
 
+// compilePaths compiles the given rules into glob matchers, in the same order.
+func compilePaths(rules []string) ([]path, error) {
+	paths := make([]path, 0, len(rules))
+	for _, rule := range rules {
+		exclusion := strings.HasPrefix(rule, "-")
+		rule = strings.TrimPrefix(rule, "-")
+
+		if !strings.HasPrefix(rule, "/") {
+			rule = "/" + rule
+		}
+
+		g, err := glob.Compile(rule, '/')
+		if err != nil {
+			return nil, errors.Wrap(err, "building include matcher")
+		}
+
+		paths = append(paths, path{globPath: g, exclusion: exclusion, original: rule})
+
+		// Special case. Our glob package does not handle rules starting with a double
+		// wildcard correctly. For example, we would expect `/**/*.java` to match all
+		// java files, but it does not match files at the root, eg `/foo.java`. To get
+		// around this we add an extra rule to cover this case.
+		if strings.HasPrefix(rule, "/**/") {
+			trimmed := rule
+			for {
+				trimmed = strings.TrimPrefix(trimmed, "/**")
+				if strings.HasPrefix(trimmed, "/**/") {
+					// Keep trimming
+					continue
+				}
+				g, err := glob.Compile(trimmed, '/')
+				if err != nil {
+					return nil, errors.Wrap(err, "building include matcher")
+				}
+				paths = append(paths, path{globPath: g, exclusion: exclusion, original: trimmed})
+				break
+			}
+		}
+
+		// We should include all directories above an include rule so that we can browse
+		// to the included items.
+		if exclusion {
+			// Not required for an exclude rule
+			continue
+		}
+
+		dirs := expandDirs(rule)
+		for _, dir := range dirs {
+			g, err := glob.Compile(dir, '/')
+			if err != nil {
+				return nil, errors.Wrap(err, "building include matcher for dir")
+			}
+			paths = append(paths, path{globPath: g, exclusion: false, original: dir})
+		}
+	}
+	return paths, nil
+}
+
 func (s *SubRepoPermsClient) Enabled() bool {
 	return s.enabled.Load()
 }
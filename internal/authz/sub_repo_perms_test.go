@@ -150,6 +150,90 @@ func TestSubRepoPermsPermissions(t *testing.T) {
 			},
 			want: Read,
 		},
+		{
+			name:   "Path rule excludes a path granted by the code host",
+			userID: 1,
+			content: RepoContent{
+				Repo: "sample",
+				Path: "/dev/thing",
+			},
+			clientFn: func() (*SubRepoPermsClient, error) {
+				getter := NewMockSubRepoPermissionsGetter()
+				getter.GetByUserFunc.SetDefaultHook(func(ctx context.Context, i int32) (map[api.RepoName]SubRepoPermissions, error) {
+					return map[api.RepoName]SubRepoPermissions{
+						"sample": {
+							Paths:     []string{"/**"},
+							PathRules: []string{"-/dev/*"},
+						},
+					}, nil
+				})
+				return NewSubRepoPermsClient(getter)
+			},
+			want: None,
+		},
+		{
+			name:   "Path rule cannot include a path excluded by the code host",
+			userID: 1,
+			content: RepoContent{
+				Repo: "sample",
+				Path: "/dev/thing",
+			},
+			clientFn: func() (*SubRepoPermsClient, error) {
+				getter := NewMockSubRepoPermissionsGetter()
+				getter.GetByUserFunc.SetDefaultHook(func(ctx context.Context, i int32) (map[api.RepoName]SubRepoPermissions, error) {
+					return map[api.RepoName]SubRepoPermissions{
+						"sample": {
+							Paths:     []string{"/**", "-/dev/*"},
+							PathRules: []string{"/dev/**"},
+						},
+					}, nil
+				})
+				return NewSubRepoPermsClient(getter)
+			},
+			want: None,
+		},
+		{
+			name:   "Path rules keep paths they don't match",
+			userID: 1,
+			content: RepoContent{
+				Repo: "sample",
+				Path: "/dev/thing",
+			},
+			clientFn: func() (*SubRepoPermsClient, error) {
+				getter := NewMockSubRepoPermissionsGetter()
+				getter.GetByUserFunc.SetDefaultHook(func(ctx context.Context, i int32) (map[api.RepoName]SubRepoPermissions, error) {
+					return map[api.RepoName]SubRepoPermissions{
+						"sample": {
+							Paths:     []string{"/**"},
+							PathRules: []string{"-/prod/**"},
+						},
+					}, nil
+				})
+				return NewSubRepoPermsClient(getter)
+			},
+			want: Read,
+		},
+		{
+			name:   "Last path rule takes precedence",
+			userID: 1,
+			content: RepoContent{
+				Repo: "sample",
+				Path: "/dev/thing",
+			},
+			clientFn: func() (*SubRepoPermsClient, error) {
+				getter := NewMockSubRepoPermissionsGetter()
+				getter.GetByUserFunc.SetDefaultHook(func(ctx context.Context, i int32) (map[api.RepoName]SubRepoPermissions, error) {
+					return map[api.RepoName]SubRepoPermissions{
+						"sample": {
+							Paths:     []string{"/**"},
+							PathRules: []string{"-/dev/**", "/dev/thing"},
+						},
+					}, nil
+				})
+				return NewSubRepoPermsClient(getter)
+			},
+			want: Read,
+		},
 	}
 
 	for _, tc := range testCases {
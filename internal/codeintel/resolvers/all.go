@@ -55,6 +55,8 @@ type UploadsServiceResolver interface {
 	LSIFUploadsByRepo(ctx context.Context, args *LSIFRepositoryUploadsQueryArgs) (LSIFUploadConnectionResolver, error)
 	DeleteLSIFUpload(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error)
 	DeleteLSIFUploads(ctx context.Context, args *DeleteLSIFUploadsArgs) (*EmptyResponse, error)
+	GitTreeCodeIntelCoverage(ctx context.Context, args *GitTreeCodeIntelCoverageArgs) (CodeIntelDirectoryCoverageResolver, error)
+	CodeIntelCoverageSummary(ctx context.Context, args *CodeIntelCoverageSummaryArgs) (CodeIntelRepositoryCoverageConnectionResolver, error)
 }
 type PoliciesServiceResolver interface {
 	CodeIntelligenceConfigurationPolicies(ctx context.Context, args *CodeIntelligenceConfigurationPoliciesArgs) (CodeIntelligenceConfigurationPolicyConnectionResolver, error)
@@ -157,6 +159,29 @@ type CodeIntelligenceCommitGraphResolver interface {
 	UpdatedAt(ctx context.Context) (*gqlutil.DateTime, error)
 }
 
+type CodeIntelDirectoryCoverageResolver interface {
+	Path() string
+	Uploads() []CodeIntelUploadCoverageResolver
+	Children() []CodeIntelDirectoryCoverageResolver
+}
+
+type CodeIntelUploadCoverageResolver interface {
+	Upload() LSIFUploadResolver
+	CommitsBehind() *int32
+}
+
+type CodeIntelRepositoryCoverageConnectionResolver interface {
+	Nodes(ctx context.Context) ([]CodeIntelRepositoryCoverageResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (PageInfo, error)
+}
+
+type CodeIntelRepositoryCoverageResolver interface {
+	Repository(ctx context.Context) (RepositoryResolver, error)
+	Commit() *string
+	Uploads() []CodeIntelUploadCoverageResolver
+}
+
 type GitObjectFilterPreviewResolver interface {
 	Name() string
 	Rev() string
@@ -213,6 +238,12 @@ type GitBlobLSIFDataResolver interface {
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Implementations(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
+	IncomingCalls(ctx context.Context, args *LSIFCallHierarchyArgs) ([]CallHierarchyCallResolver, error)
+	OutgoingCalls(ctx context.Context, args *LSIFCallHierarchyArgs) ([]CallHierarchyCallResolver, error)
+	Supertypes(ctx context.Context, args *LSIFTypeHierarchyArgs) ([]TypeHierarchyItemResolver, error)
+	Subtypes(ctx context.Context, args *LSIFTypeHierarchyArgs) ([]TypeHierarchyItemResolver, error)
+	Prototypes(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
+	DocumentSymbols(ctx context.Context, args *LSIFDocumentSymbolsArgs) ([]DocumentSymbolResolver, error)
 }
 
 type GitTreeLSIFDataResolver interface {
@@ -251,6 +282,47 @@ type LSIFQueryPositionArgs struct {
 	Filter    *string
 }
 
+type LSIFCallHierarchyArgs struct {
+	Line      int32
+	Character int32
+	First     *int32
+}
+
+type CallHierarchyCallResolver interface {
+	Symbol() string
+	Name() string
+	Definitions() LocationConnectionResolver
+	CallSites() LocationConnectionResolver
+}
+
+type LSIFTypeHierarchyArgs struct {
+	Line      int32
+	Character int32
+	Depth     *int32
+	First     *int32
+}
+
+type TypeHierarchyItemResolver interface {
+	Symbol() string
+	Name() string
+	Parent() string
+	Depth() int32
+	Definitions() LocationConnectionResolver
+}
+
+type LSIFDocumentSymbolsArgs struct {
+	First *int32
+}
+
+type DocumentSymbolResolver interface {
+	Name() string
+	Kind() string
+	Symbol() *string
+	Range() RangeResolver
+	Provenance() string
+	Children() []DocumentSymbolResolver
+}
+
 type RangeResolver interface {
 	Start() PositionResolver
 	End() PositionResolver
@@ -431,6 +503,7 @@ type LSIFUploadResolver interface {
 	Tags(ctx context.Context) ([]string, error)
 	InputRoot() string
 	IsLatestForRepo() bool
+	Ephemeral() bool
 	UploadedAt() gqlutil.DateTime
 	State() string
 	Failure() *string
@@ -520,6 +593,19 @@ type GitTreeEntryCodeIntelInfoArgs struct {
 	Commit string
 }
 
+type GitTreeCodeIntelCoverageArgs struct {
+	Repo   *types.Repo
+	Path   string
+	Commit string
+	Depth  int32
+}
+
+type CodeIntelCoverageSummaryArgs struct {
+	graphqlutil.ConnectionArgs
+	Query *string
+	After *string
+}
+
 type RequestLanguageSupportArgs struct {
 	Language string
 }
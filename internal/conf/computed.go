@@ -103,6 +103,26 @@ func AccessTokensAllow() AccessTokenAllow {
 	}
 }
 
+// AccessTokensMaxLifetime returns the maximum lifetime of new access tokens, or 0 if access tokens
+// may be created without an expiry date.
+func AccessTokensMaxLifetime() time.Duration {
+	cfg := Get().AuthAccessTokens
+	if cfg == nil || cfg.MaxLifetimeDays <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.MaxLifetimeDays) * 24 * time.Hour
+}
+
+// AccessTokensExpiryNotificationPeriod returns how long before an access token expires its owner
+// is notified.
+func AccessTokensExpiryNotificationPeriod() time.Duration {
+	days := 7
+	if cfg := Get().AuthAccessTokens; cfg != nil && cfg.ExpiryNotificationDays > 0 {
+		days = cfg.ExpiryNotificationDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 // EmailVerificationRequired returns whether users must verify an email address before they
 // can perform most actions on this site.
 //
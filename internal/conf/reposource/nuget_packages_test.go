@@ -0,0 +1,59 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseNuGetDependency(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantRepoName string
+		wantVersion  string
+		wantTag      string
+	}{
+		{
+			name:         "Newtonsoft.Json",
+			wantRepoName: "nuget/newtonsoft.json",
+			wantVersion:  "",
+			wantTag:      "v",
+		},
+		{
+			name:         "Newtonsoft.Json@13.0.1",
+			wantRepoName: "nuget/newtonsoft.json",
+			wantVersion:  "13.0.1",
+			wantTag:      "v13.0.1",
+		},
+		{
+			name:         "Serilog@3.0.0-Dev-01958",
+			wantRepoName: "nuget/serilog",
+			wantVersion:  "3.0.0-dev-01958",
+			wantTag:      "v3.0.0-dev-01958",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dep, err := ParseNuGetVersionedPackage(test.name)
+			require.NoError(t, err)
+			assert.Equal(t, api.RepoName(test.wantRepoName), dep.RepoName())
+			assert.Equal(t, test.wantVersion, dep.PackageVersion())
+			assert.Equal(t, test.wantTag, dep.GitTagFromVersion())
+		})
+	}
+
+	_, err := ParseNuGetVersionedPackage("@1.0.0")
+	assert.Error(t, err)
+}
+
+func TestParseNuGetDependencyFromRepoName(t *testing.T) {
+	dep, err := ParseNuGetPackageFromRepoName("nuget/Newtonsoft.Json")
+	require.NoError(t, err)
+	assert.Equal(t, PackageName("newtonsoft.json"), dep.PackageSyntax())
+
+	_, err = ParseNuGetPackageFromRepoName("rubygems/rails")
+	assert.Error(t, err)
+}
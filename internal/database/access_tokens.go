@@ -15,6 +15,8 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -32,6 +34,9 @@ type AccessToken struct {
 	Internal   bool
 	CreatedAt  time.Time
 	LastUsedAt *time.Time
+	// ExpiresAt is the time after which the token can no longer be used. Tokens without an
+	// expiry date never expire.
+	ExpiresAt *time.Time
 }
 
 // ErrAccessTokenNotFound occurs when a database operation expects a specific access token to exist
@@ -68,9 +73,11 @@ type AccessTokenStore interface {
 	// space; also bcrypt is slow and would add noticeable latency to each request that supplied a
 	// token.
 	//
+	// If expiresAt is nil, the token never expires.
+	//
 	// 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
 	// specified user (i.e., that the actor is either the user or a site admin).
-	Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
+	Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time) (id int64, token string, err error)
 
 	// CreateInternal creates an *internal* access token for the specified user. An
 	// internal access token will be used by Sourcegraph to talk to its API from
@@ -106,21 +113,48 @@ type AccessTokenStore interface {
 	// 🚨 SECURITY: The caller must ensure that the actor is permitted to delete the token.
 	HardDeleteByID(context.Context, int64) error
 
+	// ListExpiring lists the access tokens, except internal tokens, that expire within the given
+	// period and whose subject has not been notified yet.
+	ListExpiring(ctx context.Context, within time.Duration) ([]*AccessToken, error)
+
+	// MarkExpiryNotified records that the subject of the access token was notified that it is
+	// about to expire.
+	MarkExpiryNotified(ctx context.Context, id int64) error
+
 	// List lists all access tokens that satisfy the options, except internal tokens.
 	//
 	// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
 	// options.
 	List(context.Context, AccessTokensListOptions) ([]*AccessToken, error)
 
-	// Lookup looks up the access token. If it's valid and contains the required scope, it returns the
-	// subject's user ID. Otherwise ErrAccessTokenNotFound is returned.
+	// Lookup looks up the access token. If it's valid and grants the required scope (see
+	// authz.HasScope), it returns the subject's user ID. If it's valid but doesn't grant the
+	// required scope, an *authz.ErrScopeRequired is returned. Otherwise ErrAccessTokenNotFound is
+	// returned.
 	//
 	// Calling Lookup also updates the access token's last-used-at date.
 	//
 	// 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
-	// non-deleted access token.
+	// non-deleted, non-expired access token that grants the required scope.
 	Lookup(ctx context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
 
+	// LookupScopes looks up the access token. If it's valid, it returns the subject's user ID and
+	// the scopes of the access token. Otherwise ErrAccessTokenNotFound is returned.
+	//
+	// Calling LookupScopes also updates the access token's last-used-at date.
+	//
+	// 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
+	// non-deleted, non-expired access token. The caller must ensure that the returned scopes are
+	// enforced.
+	LookupScopes(ctx context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error)
+
+	// Rotate atomically deletes the access token and creates a replacement with the same subject,
+	// scopes and note. The secret token value of the replacement is returned, as with Create.
+	//
+	// 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
+	// subject of the access token.
+	Rotate(ctx context.Context, id int64, creatorUserID int32, expiresAt *time.Time) (newID int64, token string, err error)
+
 	Transact(context.Context) (AccessTokenStore, error)
 	With(basestore.ShareableStore) AccessTokenStore
 	basestore.ShareableStore
@@ -143,19 +177,18 @@ func (s *accessTokenStore) With(other basestore.ShareableStore) AccessTokenStore
 }
 
 func (s *accessTokenStore) Transact(ctx context.Context) (AccessTokenStore, error) {
-	txBase, err := s.Store.Transact(ctx)
-	return &accessTokenStore{Store: txBase, logger: s.logger}, err
+	return s.transact(ctx)
 }
 
-func (s *accessTokenStore) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
-	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, false)
+func (s *accessTokenStore) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time) (id int64, token string, err error) {
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, false, expiresAt)
 }
 
 func (s *accessTokenStore) CreateInternal(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
-	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, true)
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, true, nil)
 }
 
-func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, internal bool) (id int64, token string, err error) {
+func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, internal bool, expiresAt *time.Time) (id int64, token string, err error) {
 	var b [20]byte
 	if _, err := rand.Read(b[:]); err != nil {
 		return 0, "", err
@@ -179,12 +212,12 @@ creator_user AS (
   SELECT id FROM users WHERE id=$5 AND deleted_at IS NULL FOR UPDATE
 ),
 insert_values AS (
-  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::boolean AS internal
+  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::boolean AS internal, $7::timestamptz AS expires_at
   FROM subject_user, creator_user
 )
-INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, internal) SELECT * FROM insert_values RETURNING id
+INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, internal, expires_at) SELECT * FROM insert_values RETURNING id
 `,
-		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID, internal,
+		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID, internal, expiresAt,
 	).Scan(&id); err != nil {
 		return 0, "", err
 	}
@@ -192,15 +225,17 @@ INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_u
 	// only log access tokens created by users
 	if !internal {
 		arg, err := json.Marshal(struct {
-			SubjectUserId int32    `json:"subject_user_id"`
-			CreatorUserId int32    `json:"creator_user_id"`
-			Scopes        []string `json:"scopes"`
-			Note          string   `json:"note"`
+			SubjectUserId int32      `json:"subject_user_id"`
+			CreatorUserId int32      `json:"creator_user_id"`
+			Scopes        []string   `json:"scopes"`
+			Note          string     `json:"note"`
+			ExpiresAt     *time.Time `json:"expires_at,omitempty"`
 		}{
 			SubjectUserId: subjectUserID,
 			CreatorUserId: creatorUserID,
 			Scopes:        scopes,
 			Note:          note,
+			ExpiresAt:     expiresAt,
 		})
 		if err != nil {
 			s.logger.Error("failed to marshall the access token log argument")
@@ -224,11 +259,28 @@ func (s *accessTokenStore) Lookup(ctx context.Context, tokenHexEncoded, required
 		return 0, errors.New("no scope provided in access token lookup")
 	}
 
+	subjectUserID, scopes, err := s.LookupScopes(ctx, tokenHexEncoded)
+	if err != nil {
+		return 0, err
+	}
+
+	if !authz.HasScope(scopes, requiredScope) {
+		return 0, &authz.ErrScopeRequired{Scope: requiredScope}
+	}
+	return subjectUserID, nil
+}
+
+func (s *accessTokenStore) LookupScopes(ctx context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 	token, err := decodeToken(tokenHexEncoded)
 	if err != nil {
-		return 0, errors.Wrap(err, "AccessTokens.Lookup")
+		return 0, nil, errors.Wrap(err, "AccessTokens.LookupScopes")
 	}
 
+	// 🚨 SECURITY: Tokens created before a maximum lifetime was configured have
+	// no expiry date. They expire once they are older than the maximum lifetime,
+	// except internal tokens, which are never created with an expiry date.
+	maxLifetime := int64(conf.AccessTokensMaxLifetime().Seconds())
+
 	if err := s.Handle().QueryRowContext(ctx,
 		// Ensure that subject and creator users still exist.
 		`
@@ -237,19 +289,21 @@ WHERE t.id IN (
 	SELECT t2.id FROM access_tokens t2
 	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL
 	JOIN users creator_user ON t2.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
-	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND
-	$2 = ANY (t2.scopes)
+	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND (
+		t2.expires_at > now() OR
+		(t2.expires_at IS NULL AND (t2.internal OR $2::bigint = 0 OR t2.created_at + $2::bigint * interval '1 second' > now()))
+	)
 )
-RETURNING t.subject_user_id
+RETURNING t.subject_user_id, t.scopes
 `,
-		toSHA256Bytes(token), requiredScope,
-	).Scan(&subjectUserID); err != nil {
+		toSHA256Bytes(token), maxLifetime,
+	).Scan(&subjectUserID, pq.Array(&scopes)); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrAccessTokenNotFound
+			return 0, nil, ErrAccessTokenNotFound
 		}
-		return 0, err
+		return 0, nil, err
 	}
-	return subjectUserID, nil
+	return subjectUserID, scopes, nil
 }
 
 func (s *accessTokenStore) GetByID(ctx context.Context, id int64) (*AccessToken, error) {
@@ -281,6 +335,9 @@ type AccessTokensListOptions struct {
 	SubjectUserID  int32 // only list access tokens with this user as the subject
 	LastUsedAfter  *time.Time
 	LastUsedBefore *time.Time
+	// UnusedSince only lists access tokens that have not been used since the given time,
+	// including tokens created before it that were never used.
+	UnusedSince *time.Time
 	*LimitOffset
 }
 
@@ -299,6 +356,9 @@ func (o AccessTokensListOptions) sqlConditions() []*sqlf.Query {
 	if o.LastUsedBefore != nil {
 		conds = append(conds, sqlf.Sprintf("last_used_at<%d", o.LastUsedBefore))
 	}
+	if o.UnusedSince != nil {
+		conds = append(conds, sqlf.Sprintf("COALESCE(last_used_at, created_at)<%s", o.UnusedSince))
+	}
 	return conds
 }
 
@@ -308,7 +368,7 @@ func (s *accessTokenStore) List(ctx context.Context, opt AccessTokensListOptions
 
 func (s *accessTokenStore) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*AccessToken, error) {
 	q := sqlf.Sprintf(`
-SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at FROM access_tokens
+SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at, expires_at FROM access_tokens
 WHERE (%s)
 ORDER BY now() - created_at < interval '5 minutes' DESC, -- show recently created tokens first
 last_used_at DESC NULLS FIRST, -- ensure newly created tokens show first
@@ -327,7 +387,7 @@ created_at DESC
 	var results []*AccessToken
 	for rows.Next() {
 		var t AccessToken
-		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt); err != nil {
 			return nil, err
 		}
 		results = append(results, &t)
@@ -339,6 +399,46 @@ created_at DESC
 	return results, nil
 }
 
+func (s *accessTokenStore) ListExpiring(ctx context.Context, within time.Duration) ([]*AccessToken, error) {
+	return s.list(ctx, []*sqlf.Query{
+		sqlf.Sprintf("deleted_at IS NULL"),
+		sqlf.Sprintf("internal IS FALSE"),
+		sqlf.Sprintf("expiry_notified_at IS NULL"),
+		sqlf.Sprintf("expires_at > now()"),
+		sqlf.Sprintf("expires_at < now() + %s * interval '1 second'", within.Seconds()),
+	}, nil)
+}
+
+func (s *accessTokenStore) MarkExpiryNotified(ctx context.Context, id int64) error {
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE access_tokens SET expiry_notified_at=now() WHERE id=%s", id))
+}
+
+func (s *accessTokenStore) Rotate(ctx context.Context, id int64, creatorUserID int32, expiresAt *time.Time) (newID int64, token string, err error) {
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	old, err := tx.get(ctx, []*sqlf.Query{
+		sqlf.Sprintf("id=%d", id),
+		sqlf.Sprintf("deleted_at IS NULL"),
+		sqlf.Sprintf("internal IS FALSE"),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if err := tx.DeleteByID(ctx, old.ID); err != nil {
+		return 0, "", err
+	}
+	return tx.Create(ctx, old.SubjectUserID, old.Scopes, old.Note, creatorUserID, expiresAt)
+}
+
+func (s *accessTokenStore) transact(ctx context.Context) (*accessTokenStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &accessTokenStore{Store: txBase, logger: s.logger}, err
+}
+
 func (s *accessTokenStore) Count(ctx context.Context, opt AccessTokensListOptions) (int, error) {
 	q := sqlf.Sprintf("SELECT COUNT(*) FROM access_tokens WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
 	var count int
@@ -4,12 +4,15 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // 🚨 SECURITY: This tests the routine that creates access tokens and returns the token secret value
@@ -41,7 +44,7 @@ func TestAccessTokens_Create(t *testing.T) {
 	}
 
 	assertSecurityEventCount(t, db, SecurityEventAccessTokenCreated, 0)
-	tid0, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -121,15 +124,15 @@ func TestAccessTokens_Delete(t *testing.T) {
 	subjectActor := actor.FromUser(subject.ID)
 	ctxWithActor := actor.WithActor(context.Background(), subjectActor)
 
-	tid0, _, err := db.AccessTokens().Create(ctxWithActor, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, _, err := db.AccessTokens().Create(ctxWithActor, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, tv1, err := db.AccessTokens().Create(ctxWithActor, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	_, tv1, err := db.AccessTokens().Create(ctxWithActor, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	tid2, _, err := db.AccessTokens().Create(ctxWithActor, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid2, _, err := db.AccessTokens().Create(ctxWithActor, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -228,11 +231,11 @@ func TestAccessTokens_List(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, _, err = db.AccessTokens().Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID)
+	_, _, err = db.AccessTokens().Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, _, err = db.AccessTokens().Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID)
+	_, _, err = db.AccessTokens().Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -309,7 +312,7 @@ func TestAccessTokens_Lookup(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -379,7 +382,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -390,7 +393,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal("Lookup: want error looking up token for deleted subject user")
 		}
 
-		if _, _, err := db.AccessTokens().Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := db.AccessTokens().Create(ctx, subject.ID, nil, "n0", creator.ID, nil); err == nil {
 			t.Fatal("Create: want error creating token for deleted subject user")
 		}
 	})
@@ -415,7 +418,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -426,8 +429,195 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal("Lookup: want error looking up token for deleted creator user")
 		}
 
-		if _, _, err := db.AccessTokens().Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := db.AccessTokens().Create(ctx, subject.ID, nil, "n0", creator.ID, nil); err == nil {
 			t.Fatal("Create: want error creating token for deleted creator user")
 		}
 	})
 }
+
+// 🚨 SECURITY: This tests that expired access tokens can't be used.
+func TestAccessTokens_Expiry(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	t.Parallel()
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	subject, err := db.Users().Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired := time.Now().Add(-time.Hour)
+	_, tvExpired, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "expired", subject.ID, &expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AccessTokens().Lookup(ctx, tvExpired, "a"); err != ErrAccessTokenNotFound {
+		t.Fatalf("Lookup: got error %v, want ErrAccessTokenNotFound", err)
+	}
+
+	soon := time.Now().Add(24 * time.Hour)
+	tidSoon, tvSoon, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "soon", subject.ID, &soon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AccessTokens().Lookup(ctx, tvSoon, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(30 * 24 * time.Hour)
+	if _, _, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "later", subject.ID, &later); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "never", subject.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := db.AccessTokens().ListExpiring(ctx, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != 1 || ts[0].ID != tidSoon {
+		t.Fatalf("ListExpiring: got %+v, want only token %d", ts, tidSoon)
+	}
+	if ts[0].ExpiresAt == nil || !ts[0].ExpiresAt.Equal(soon.Truncate(time.Microsecond)) {
+		t.Errorf("got expires at %v, want %v", ts[0].ExpiresAt, soon)
+	}
+
+	if err := db.AccessTokens().MarkExpiryNotified(ctx, tidSoon); err != nil {
+		t.Fatal(err)
+	}
+	ts, err = db.AccessTokens().ListExpiring(ctx, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != 0 {
+		t.Errorf("ListExpiring: got %d access tokens after notifying, want 0", len(ts))
+	}
+}
+
+// 🚨 SECURITY: This tests that access tokens without an expiry date are rejected
+// once they are older than the maximum lifetime.
+func TestAccessTokens_Lookup_maxLifetime(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	subject, err := db.Users().Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tidOld, tvOld, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "old", subject.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, tvRecent, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "recent", subject.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tidInternal, tvInternal, err := db.AccessTokens().CreateInternal(ctx, subject.ID, []string{"a"}, "internal", subject.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "UPDATE access_tokens SET created_at = now() - interval '100 days' WHERE id IN ($1, $2)", tidOld, tidInternal); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a maximum lifetime, tokens without an expiry date never expire.
+	for _, tv := range []string{tvOld, tvRecent, tvInternal} {
+		if _, err := db.AccessTokens().Lookup(ctx, tv, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		AuthAccessTokens: &schema.AuthAccessTokens{MaxLifetimeDays: 90},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	if _, err := db.AccessTokens().Lookup(ctx, tvOld, "a"); err != ErrAccessTokenNotFound {
+		t.Fatalf("Lookup: got error %v, want ErrAccessTokenNotFound", err)
+	}
+	for _, tv := range []string{tvRecent, tvInternal} {
+		if _, err := db.AccessTokens().Lookup(ctx, tv, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// 🚨 SECURITY: This tests that rotating an access token revokes the old token.
+func TestAccessTokens_Rotate(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	t.Parallel()
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+
+	subject, err := db.Users().Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tid0, tv0, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a", "b"}, "n0", subject.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	tid1, tv1, err := db.AccessTokens().Rotate(ctx, tid0, subject.ID, &expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tid1 == tid0 || tv1 == tv0 {
+		t.Fatal("Rotate: want a new access token")
+	}
+
+	if _, err := db.AccessTokens().Lookup(ctx, tv0, "a"); err != ErrAccessTokenNotFound {
+		t.Errorf("Lookup old token: got error %v, want ErrAccessTokenNotFound", err)
+	}
+	if _, err := db.AccessTokens().Lookup(ctx, tv1, "a"); err != nil {
+		t.Errorf("Lookup new token: %v", err)
+	}
+
+	got, err := db.AccessTokens().GetByID(ctx, tid1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got.Scopes, want) {
+		t.Errorf("got scopes %q, want %q", got.Scopes, want)
+	}
+	if want := "n0"; got.Note != want {
+		t.Errorf("got note %q, want %q", got.Note, want)
+	}
+	if got.ExpiresAt == nil {
+		t.Error("got no expiry date, want one")
+	}
+
+	if _, _, err := db.AccessTokens().Rotate(ctx, tid0, subject.ID, nil); err != ErrAccessTokenNotFound {
+		t.Errorf("Rotate deleted token: got error %v, want ErrAccessTokenNotFound", err)
+	}
+}
@@ -25,6 +25,7 @@ type DB interface {
 	BitbucketProjectPermissions() BitbucketProjectPermissionsStore
 	Conf() ConfStore
 	EventLogs() EventLogStore
+	ExplicitPermissionsBulkImportJobs() ExplicitPermissionsBulkImportJobsStore
 	SecurityEventLogs() SecurityEventLogsStore
 	ExternalServices() ExternalServiceStore
 	FeatureFlags() FeatureFlagStore
@@ -39,6 +40,8 @@ type DB interface {
 	Phabricator() PhabricatorStore
 	Repos() RepoStore
 	RepoKVPs() RepoKVPStore
+	RepoNameHistory() RepoNameHistoryStore
+	Roles() RoleStore
 	SavedSearches() SavedSearchStore
 	SearchContexts() SearchContextsStore
 	Settings() SettingsStore
@@ -48,12 +51,14 @@ type DB interface {
 	UserEmails() UserEmailsStore
 	UserExternalAccounts() UserExternalAccountsStore
 	Users() UserStore
+	UserSessions() UserSessionStore
 	WebhookLogs(encryption.Key) WebhookLogStore
 	Webhooks(encryption.Key) WebhookStore
 	RepoStatistics() RepoStatisticsStore
 	Executors() ExecutorStore
 	ExecutorSecrets(encryption.Key) ExecutorSecretStore
 	ExecutorSecretAccessLogs() ExecutorSecretAccessLogStore
+	VirtualRepos() VirtualReposStore
 	ZoektRepos() ZoektReposStore
 
 	Transact(context.Context) (DB, error)
@@ -121,6 +126,10 @@ func (d *db) EventLogs() EventLogStore {
 	return EventLogsWith(d.Store)
 }
 
+func (d *db) ExplicitPermissionsBulkImportJobs() ExplicitPermissionsBulkImportJobsStore {
+	return ExplicitPermissionsBulkImportJobsWith(d.Store)
+}
+
 func (d *db) SecurityEventLogs() SecurityEventLogsStore {
 	return SecurityEventLogsWith(d.logger, d.Store)
 }
@@ -177,6 +186,14 @@ func (d *db) RepoKVPs() RepoKVPStore {
 	return &repoKVPStore{d.Store}
 }
 
+func (d *db) RepoNameHistory() RepoNameHistoryStore {
+	return RepoNameHistoryWith(d.Store)
+}
+
+func (d *db) Roles() RoleStore {
+	return RolesWith(d.Store)
+}
+
 func (d *db) SavedSearches() SavedSearchStore {
 	return SavedSearchesWith(d.Store)
 }
@@ -213,6 +230,10 @@ func (d *db) Users() UserStore {
 	return UsersWith(d.logger, d.Store)
 }
 
+func (d *db) UserSessions() UserSessionStore {
+	return UserSessionsWith(d.Store)
+}
+
 func (d *db) WebhookLogs(key encryption.Key) WebhookLogStore {
 	return WebhookLogsWith(d.Store, key)
 }
@@ -237,6 +258,10 @@ func (d *db) ExecutorSecretAccessLogs() ExecutorSecretAccessLogStore {
 	return ExecutorSecretAccessLogsWith(d.Store)
 }
 
+func (d *db) VirtualRepos() VirtualReposStore {
+	return VirtualReposWith(d.Store)
+}
+
 func (d *db) ZoektRepos() ZoektReposStore {
 	return ZoektReposWith(d.Store)
 }
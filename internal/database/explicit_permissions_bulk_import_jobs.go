@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// ExplicitPermissionsBulkImportJobNotFoundErr is returned when an explicit
+// permissions bulk import job cannot be found.
+type ExplicitPermissionsBulkImportJobNotFoundErr struct {
+	ID int
+}
+
+func (err *ExplicitPermissionsBulkImportJobNotFoundErr) Error() string {
+	return fmt.Sprintf("explicit permissions bulk import job not found: id=%d", err.ID)
+}
+
+func (*ExplicitPermissionsBulkImportJobNotFoundErr) NotFound() bool {
+	return true
+}
+
+// ExplicitPermissionsBulkImportJobsStore is used by the explicit permissions
+// bulk import worker to apply uploaded permissions asynchronously.
+type ExplicitPermissionsBulkImportJobsStore interface {
+	basestore.ShareableStore
+	With(other basestore.ShareableStore) ExplicitPermissionsBulkImportJobsStore
+
+	// Enqueue creates a job that applies the permissions listed in the document
+	// stored at payloadKey in the upload store.
+	Enqueue(ctx context.Context, format types.ExplicitPermissionsBulkImportFormat, mode types.ExplicitPermissionsBulkImportMode, payloadKey string, creatorUserID int32) (int, error)
+	// GetByID returns the job with the given ID. A
+	// *ExplicitPermissionsBulkImportJobNotFoundErr is returned if no such job
+	// exists.
+	GetByID(ctx context.Context, id int) (*types.ExplicitPermissionsBulkImportJob, error)
+	// ListErrors returns the entries of the job that could not be applied,
+	// ordered by line.
+	ListErrors(ctx context.Context, jobID int) ([]*types.ExplicitPermissionsBulkImportError, error)
+	// RecordResult stores the number of processed entries and the errors of the
+	// job, replacing the result of previous attempts.
+	RecordResult(ctx context.Context, jobID int, processedLines int, errs []*types.ExplicitPermissionsBulkImportError) error
+}
+
+var _ ExplicitPermissionsBulkImportJobsStore = (*explicitPermissionsBulkImportJobsStore)(nil)
+
+type explicitPermissionsBulkImportJobsStore struct {
+	*basestore.Store
+}
+
+// ExplicitPermissionsBulkImportJobsWith instantiates and returns a new
+// ExplicitPermissionsBulkImportJobsStore using the other store handle.
+func ExplicitPermissionsBulkImportJobsWith(other basestore.ShareableStore) ExplicitPermissionsBulkImportJobsStore {
+	return &explicitPermissionsBulkImportJobsStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *explicitPermissionsBulkImportJobsStore) With(other basestore.ShareableStore) ExplicitPermissionsBulkImportJobsStore {
+	return &explicitPermissionsBulkImportJobsStore{Store: s.Store.With(other)}
+}
+
+func (s *explicitPermissionsBulkImportJobsStore) Enqueue(ctx context.Context, format types.ExplicitPermissionsBulkImportFormat, mode types.ExplicitPermissionsBulkImportMode, payloadKey string, creatorUserID int32) (int, error) {
+	id, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(enqueueExplicitPermissionsBulkImportJobQueryFmtstr, format, mode, payloadKey, creatorUserID)))
+	return id, err
+}
+
+const enqueueExplicitPermissionsBulkImportJobQueryFmtstr = `
+-- source: internal/database/explicit_permissions_bulk_import_jobs.go:explicitPermissionsBulkImportJobsStore.Enqueue
+INSERT INTO explicit_permissions_bulk_import_jobs (format, mode, payload_key, creator_user_id)
+VALUES (%s, %s, %s, %s)
+RETURNING id
+`
+
+func (s *explicitPermissionsBulkImportJobsStore) GetByID(ctx context.Context, id int) (*types.ExplicitPermissionsBulkImportJob, error) {
+	job, ok, err := scanFirstExplicitPermissionsBulkImportJob(s.Query(ctx, sqlf.Sprintf(
+		getExplicitPermissionsBulkImportJobQueryFmtstr,
+		sqlf.Join(ExplicitPermissionsBulkImportJobColumnExpressions, ", "),
+		id,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &ExplicitPermissionsBulkImportJobNotFoundErr{ID: id}
+	}
+	return job, nil
+}
+
+const getExplicitPermissionsBulkImportJobQueryFmtstr = `
+-- source: internal/database/explicit_permissions_bulk_import_jobs.go:explicitPermissionsBulkImportJobsStore.GetByID
+SELECT %s
+FROM explicit_permissions_bulk_import_jobs
+WHERE id = %s
+`
+
+func (s *explicitPermissionsBulkImportJobsStore) ListErrors(ctx context.Context, jobID int) ([]*types.ExplicitPermissionsBulkImportError, error) {
+	return scanExplicitPermissionsBulkImportErrors(s.Query(ctx, sqlf.Sprintf(listExplicitPermissionsBulkImportErrorsQueryFmtstr, jobID)))
+}
+
+const listExplicitPermissionsBulkImportErrorsQueryFmtstr = `
+-- source: internal/database/explicit_permissions_bulk_import_jobs.go:explicitPermissionsBulkImportJobsStore.ListErrors
+SELECT line, message
+FROM explicit_permissions_bulk_import_errors
+WHERE job_id = %s
+ORDER BY line ASC, id ASC
+`
+
+func (s *explicitPermissionsBulkImportJobsStore) RecordResult(ctx context.Context, jobID int, processedLines int, errs []*types.ExplicitPermissionsBulkImportError) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteExplicitPermissionsBulkImportErrorsQueryFmtstr, jobID)); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		values := make([]*sqlf.Query, 0, len(errs))
+		for _, e := range errs {
+			values = append(values, sqlf.Sprintf("(%s, %s, %s)", jobID, e.Line, e.Message))
+		}
+		if err := tx.Exec(ctx, sqlf.Sprintf(insertExplicitPermissionsBulkImportErrorsQueryFmtstr, sqlf.Join(values, ", "))); err != nil {
+			return err
+		}
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(updateExplicitPermissionsBulkImportJobResultQueryFmtstr, processedLines, len(errs), jobID))
+}
+
+const deleteExplicitPermissionsBulkImportErrorsQueryFmtstr = `
+-- source: internal/database/explicit_permissions_bulk_import_jobs.go:explicitPermissionsBulkImportJobsStore.RecordResult
+DELETE FROM explicit_permissions_bulk_import_errors WHERE job_id = %s
+`
+
+const insertExplicitPermissionsBulkImportErrorsQueryFmtstr = `
+-- source: internal/database/explicit_permissions_bulk_import_jobs.go:explicitPermissionsBulkImportJobsStore.RecordResult
+INSERT INTO explicit_permissions_bulk_import_errors (job_id, line, message)
+VALUES %s
+`
+
+const updateExplicitPermissionsBulkImportJobResultQueryFmtstr = `
+-- source: internal/database/explicit_permissions_bulk_import_jobs.go:explicitPermissionsBulkImportJobsStore.RecordResult
+UPDATE explicit_permissions_bulk_import_jobs
+SET processed_lines = %s, failed_lines = %s
+WHERE id = %s
+`
+
+var ExplicitPermissionsBulkImportJobColumnExpressions = []*sqlf.Query{
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.id"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.state"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.failure_message"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.queued_at"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.started_at"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.finished_at"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.process_after"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.num_resets"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.num_failures"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.last_heartbeat_at"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.execution_logs"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.worker_hostname"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.format"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.mode"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.payload_key"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.creator_user_id"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.processed_lines"),
+	sqlf.Sprintf("explicit_permissions_bulk_import_jobs.failed_lines"),
+}
+
+var scanFirstExplicitPermissionsBulkImportJob = basestore.NewFirstScanner(ScanExplicitPermissionsBulkImportJob)
+
+func ScanExplicitPermissionsBulkImportJob(s dbutil.Scanner) (*types.ExplicitPermissionsBulkImportJob, error) {
+	var job types.ExplicitPermissionsBulkImportJob
+	var executionLogs []dbworkerstore.ExecutionLogEntry
+
+	if err := s.Scan(
+		&job.ID,
+		&job.State,
+		&job.FailureMessage,
+		&job.QueuedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.ProcessAfter,
+		&job.NumResets,
+		&job.NumFailures,
+		&dbutil.NullTime{Time: &job.LastHeartbeatAt},
+		pq.Array(&executionLogs),
+		&job.WorkerHostname,
+		&job.Format,
+		&job.Mode,
+		&job.PayloadKey,
+		&dbutil.NullInt32{N: &job.CreatorUserID},
+		&job.ProcessedLines,
+		&job.FailedLines,
+	); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range executionLogs {
+		job.ExecutionLogs = append(job.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
+	}
+	return &job, nil
+}
+
+var scanExplicitPermissionsBulkImportErrors = basestore.NewSliceScanner(func(s dbutil.Scanner) (*types.ExplicitPermissionsBulkImportError, error) {
+	var e types.ExplicitPermissionsBulkImportError
+	err := s.Scan(&e.Line, &e.Message)
+	return &e, err
+})
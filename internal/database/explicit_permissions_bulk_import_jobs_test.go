@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestExplicitPermissionsBulkImportJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	s := db.ExplicitPermissionsBulkImportJobs()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "u"})
+	require.NoError(t, err)
+
+	id, err := s.Enqueue(ctx, types.ExplicitPermissionsBulkImportFormatJSONL, types.ExplicitPermissionsBulkImportModeReplace, "explicit-permissions-bulk-imports/key", user.ID)
+	require.NoError(t, err)
+
+	job, err := s.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", job.State)
+	assert.Equal(t, types.ExplicitPermissionsBulkImportFormatJSONL, job.Format)
+	assert.Equal(t, types.ExplicitPermissionsBulkImportModeReplace, job.Mode)
+	assert.Equal(t, "explicit-permissions-bulk-imports/key", job.PayloadKey)
+	assert.Equal(t, user.ID, job.CreatorUserID)
+
+	_, err = s.GetByID(ctx, id+1)
+	assert.True(t, errcode.IsNotFound(err))
+
+	t.Run("RecordResult", func(t *testing.T) {
+		require.NoError(t, s.RecordResult(ctx, id, 3, []*types.ExplicitPermissionsBulkImportError{
+			{Line: 3, Message: "second"},
+			{Line: 1, Message: "first"},
+		}))
+		// Recording the result of a retry replaces the previous result.
+		require.NoError(t, s.RecordResult(ctx, id, 3, []*types.ExplicitPermissionsBulkImportError{
+			{Line: 3, Message: "retried"},
+		}))
+
+		job, err := s.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 3, job.ProcessedLines)
+		assert.Equal(t, 1, job.FailedLines)
+
+		errs, err := s.ListErrors(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, []*types.ExplicitPermissionsBulkImportError{{Line: 3, Message: "retried"}}, errs)
+	})
+}
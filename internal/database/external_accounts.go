@@ -377,6 +377,9 @@ type ExternalAccountsListOptions struct {
 	ExcludeExpired bool
 	OnlyExpired    bool
 
+	// IncludeDeleted includes soft-deleted accounts.
+	IncludeDeleted bool
+
 	*LimitOffset
 }
 
@@ -454,8 +457,11 @@ func (s *userExternalAccountsStore) ListBySQL(ctx context.Context, querySuffix *
 }
 
 func (s *userExternalAccountsStore) listSQL(opt ExternalAccountsListOptions) (conds []*sqlf.Query) {
-	conds = []*sqlf.Query{sqlf.Sprintf("deleted_at IS NULL")}
+	conds = []*sqlf.Query{sqlf.Sprintf("TRUE")}
 
+	if !opt.IncludeDeleted {
+		conds = append(conds, sqlf.Sprintf("deleted_at IS NULL"))
+	}
 	if opt.UserID != 0 {
 		conds = append(conds, sqlf.Sprintf("user_id=%d", opt.UserID))
 	}
@@ -222,6 +222,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindPythonPackages:  {CodeHost: true, JSONSchema: schema.PythonPackagesSchemaJSON},
 	extsvc.KindRustPackages:    {CodeHost: true, JSONSchema: schema.RustPackagesSchemaJSON},
 	extsvc.KindRubyPackages:    {CodeHost: true, JSONSchema: schema.RubyPackagesSchemaJSON},
+	extsvc.KindNuGetPackages:   {CodeHost: true, JSONSchema: schema.NuGetPackagesSchemaJSON},
 }
 
 // ExternalServiceKind describes a kind of external service.
@@ -1018,6 +1019,17 @@ CREATE TEMPORARY TABLE IF NOT EXISTS
 		return errors.Wrap(err, "populating temporary table")
 	}
 
+	// Record the removal of each repo from the external service
+	if err := tx.Exec(ctx, sqlf.Sprintf(`
+	INSERT INTO repo_name_history (repo_id, external_service_id, event, from_name)
+	SELECT repo.id, %s, %s, repo.name
+	FROM repo
+	JOIN deleted_repos_temp ON deleted_repos_temp.repo_id = repo.id
+	WHERE repo.deleted_at IS NULL
+`, id, RepoNameHistoryDeleted)); err != nil {
+		return errors.Wrap(err, "recording repo deletions")
+	}
+
 	// Soft delete orphaned repos
 	if err := tx.Exec(ctx, sqlf.Sprintf(`
 	UPDATE repo
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// RepoNameHistoryEventType is the kind of change recorded in repo_name_history.
+type RepoNameHistoryEventType string
+
+const (
+	RepoNameHistoryRenamed    RepoNameHistoryEventType = "renamed"
+	RepoNameHistoryArchived   RepoNameHistoryEventType = "archived"
+	RepoNameHistoryUnarchived RepoNameHistoryEventType = "unarchived"
+	RepoNameHistoryDeleted    RepoNameHistoryEventType = "deleted"
+)
+
+// RepoNameHistoryEvent is a single rename, archival or deletion of a
+// repository as observed by the repo syncer.
+type RepoNameHistoryEvent struct {
+	ID                int64
+	RepoID            api.RepoID
+	ExternalServiceID int64
+	Event             RepoNameHistoryEventType
+	FromName          api.RepoName
+	// ToName is only set for RepoNameHistoryRenamed events.
+	ToName    api.RepoName
+	CreatedAt time.Time
+}
+
+// RepoNameHistoryListOptions contains options for listing repo_name_history
+// entries.
+type RepoNameHistoryListOptions struct {
+	// When specified, only include events recorded for this external service.
+	ExternalServiceID int64
+	// When specified, only include events of this repository.
+	RepoID api.RepoID
+	// When specified, only include events of the given types.
+	Events []RepoNameHistoryEventType
+	// When specified, only include events recorded on or after this time.
+	Since *time.Time
+
+	*LimitOffset
+}
+
+type RepoNameHistoryStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) RepoNameHistoryStore
+
+	// Create records the given events. The ID and CreatedAt fields of each event
+	// are populated on success.
+	Create(ctx context.Context, events ...*RepoNameHistoryEvent) error
+	// List returns the events matching the given options, most recent first.
+	List(ctx context.Context, opts RepoNameHistoryListOptions) ([]*RepoNameHistoryEvent, error)
+	// Count returns the number of events matching the given options.
+	Count(ctx context.Context, opts RepoNameHistoryListOptions) (int, error)
+	// GetRepoIDByPreviousName returns the ID of the non-deleted repository that
+	// was most recently renamed away from the given name. A *RepoNotFoundErr is
+	// returned if no such repository exists.
+	GetRepoIDByPreviousName(ctx context.Context, name api.RepoName) (api.RepoID, error)
+}
+
+var _ RepoNameHistoryStore = (*repoNameHistoryStore)(nil)
+
+// repoNameHistoryStore is responsible for data stored in the repo_name_history table.
+type repoNameHistoryStore struct {
+	*basestore.Store
+}
+
+// RepoNameHistoryWith instantiates and returns a new RepoNameHistoryStore using
+// the other store handle.
+func RepoNameHistoryWith(other basestore.ShareableStore) RepoNameHistoryStore {
+	return &repoNameHistoryStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *repoNameHistoryStore) With(other basestore.ShareableStore) RepoNameHistoryStore {
+	return &repoNameHistoryStore{Store: s.Store.With(other)}
+}
+
+func (s *repoNameHistoryStore) Create(ctx context.Context, events ...*RepoNameHistoryEvent) error {
+	for _, e := range events {
+		row := s.QueryRow(ctx, sqlf.Sprintf(
+			createRepoNameHistoryEventQueryFmtstr,
+			e.RepoID,
+			dbutil.NewNullInt64(e.ExternalServiceID),
+			e.Event,
+			e.FromName,
+			dbutil.NewNullString(string(e.ToName)),
+		))
+		if err := row.Scan(&e.ID, &e.CreatedAt); err != nil {
+			return errors.Wrap(err, "creating repo name history event")
+		}
+	}
+	return nil
+}
+
+const createRepoNameHistoryEventQueryFmtstr = `
+-- source: internal/database/repo_name_history.go:repoNameHistoryStore.Create
+INSERT INTO repo_name_history (repo_id, external_service_id, event, from_name, to_name)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING id, created_at
+`
+
+func (s *repoNameHistoryStore) List(ctx context.Context, opts RepoNameHistoryListOptions) ([]*RepoNameHistoryEvent, error) {
+	q := sqlf.Sprintf(listRepoNameHistoryQueryFmtstr, sqlf.Join(opts.sqlConds(), "AND"), opts.LimitOffset.SQL())
+	return scanRepoNameHistoryEvents(s.Query(ctx, q))
+}
+
+const listRepoNameHistoryQueryFmtstr = `
+-- source: internal/database/repo_name_history.go:repoNameHistoryStore.List
+SELECT
+	id,
+	repo_id,
+	external_service_id,
+	event,
+	from_name,
+	to_name,
+	created_at
+FROM repo_name_history
+WHERE %s
+ORDER BY created_at DESC, id DESC
+%s
+`
+
+func (s *repoNameHistoryStore) Count(ctx context.Context, opts RepoNameHistoryListOptions) (int, error) {
+	q := sqlf.Sprintf(countRepoNameHistoryQueryFmtstr, sqlf.Join(opts.sqlConds(), "AND"))
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	return count, err
+}
+
+const countRepoNameHistoryQueryFmtstr = `
+-- source: internal/database/repo_name_history.go:repoNameHistoryStore.Count
+SELECT COUNT(*) FROM repo_name_history WHERE %s
+`
+
+func (s *repoNameHistoryStore) GetRepoIDByPreviousName(ctx context.Context, name api.RepoName) (api.RepoID, error) {
+	id, ok, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(getRepoIDByPreviousNameQueryFmtstr, name, RepoNameHistoryRenamed)))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, &RepoNotFoundErr{Name: name}
+	}
+	return api.RepoID(id), nil
+}
+
+const getRepoIDByPreviousNameQueryFmtstr = `
+-- source: internal/database/repo_name_history.go:repoNameHistoryStore.GetRepoIDByPreviousName
+SELECT h.repo_id
+FROM repo_name_history h
+JOIN repo r ON r.id = h.repo_id
+WHERE
+	h.from_name = %s AND
+	h.event = %s AND
+	r.deleted_at IS NULL AND
+	r.blocked IS NULL
+ORDER BY h.created_at DESC, h.id DESC
+LIMIT 1
+`
+
+func (o RepoNameHistoryListOptions) sqlConds() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ExternalServiceID != 0 {
+		conds = append(conds, sqlf.Sprintf("external_service_id = %s", o.ExternalServiceID))
+	}
+	if o.RepoID != 0 {
+		conds = append(conds, sqlf.Sprintf("repo_id = %s", o.RepoID))
+	}
+	if len(o.Events) > 0 {
+		events := make([]*sqlf.Query, 0, len(o.Events))
+		for _, e := range o.Events {
+			events = append(events, sqlf.Sprintf("%s", e))
+		}
+		conds = append(conds, sqlf.Sprintf("event IN (%s)", sqlf.Join(events, ",")))
+	}
+	if o.Since != nil {
+		conds = append(conds, sqlf.Sprintf("created_at >= %s", *o.Since))
+	}
+	return conds
+}
+
+var scanRepoNameHistoryEvents = basestore.NewSliceScanner(scanRepoNameHistoryEvent)
+
+func scanRepoNameHistoryEvent(sc dbutil.Scanner) (*RepoNameHistoryEvent, error) {
+	var e RepoNameHistoryEvent
+	err := sc.Scan(
+		&e.ID,
+		&e.RepoID,
+		&dbutil.NullInt64{N: &e.ExternalServiceID},
+		&e.Event,
+		&e.FromName,
+		&dbutil.NullString{S: (*string)(&e.ToName)},
+		&e.CreatedAt,
+	)
+	return &e, err
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestRepoNameHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	s := db.RepoNameHistory()
+
+	repo, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/new"})
+	other, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/other"})
+
+	err := s.Create(ctx,
+		&RepoNameHistoryEvent{RepoID: repo.ID, Event: RepoNameHistoryRenamed, FromName: "github.com/sourcegraph/old", ToName: repo.Name},
+		&RepoNameHistoryEvent{RepoID: repo.ID, Event: RepoNameHistoryArchived, FromName: repo.Name},
+		&RepoNameHistoryEvent{RepoID: other.ID, Event: RepoNameHistoryUnarchived, FromName: other.Name},
+	)
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		events, err := s.List(ctx, RepoNameHistoryListOptions{RepoID: repo.ID})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, RepoNameHistoryArchived, events[0].Event)
+		assert.Equal(t, RepoNameHistoryRenamed, events[1].Event)
+		assert.Equal(t, api.RepoName("github.com/sourcegraph/old"), events[1].FromName)
+		assert.Equal(t, repo.Name, events[1].ToName)
+
+		events, err = s.List(ctx, RepoNameHistoryListOptions{Events: []RepoNameHistoryEventType{RepoNameHistoryUnarchived}})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, other.ID, events[0].RepoID)
+
+		events, err = s.List(ctx, RepoNameHistoryListOptions{LimitOffset: &LimitOffset{Limit: 1, Offset: 2}})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := s.Count(ctx, RepoNameHistoryListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("GetRepoIDByPreviousName", func(t *testing.T) {
+		id, err := s.GetRepoIDByPreviousName(ctx, "github.com/sourcegraph/old")
+		require.NoError(t, err)
+		assert.Equal(t, repo.ID, id)
+
+		_, err = s.GetRepoIDByPreviousName(ctx, "github.com/sourcegraph/unknown")
+		assert.True(t, errcode.IsNotFound(err))
+
+		// Renames of deleted repos do not resolve.
+		require.NoError(t, db.Repos().Delete(ctx, repo.ID))
+		_, err = s.GetRepoIDByPreviousName(ctx, "github.com/sourcegraph/old")
+		assert.True(t, errcode.IsNotFound(err))
+	})
+}
@@ -572,6 +572,8 @@ func scanRepo(logger log.Logger, rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = &struct{}{}
 	case extsvc.TypeRubyPackages:
 		r.Metadata = &struct{}{}
+	case extsvc.TypeNuGetPackages:
+		r.Metadata = &struct{}{}
 	default:
 		logger.Warn("unknown service type", log.String("type", typ))
 		return nil
@@ -621,6 +623,11 @@ type ReposListOptions struct {
 	// and this may be replaced by the version context name.
 	Names []string
 
+	// IncludeVirtualRepos, if true, also matches Query and Names against the
+	// names of the virtual repositories of each repository, so that the parent
+	// repositories of matching virtual repositories are returned.
+	IncludeVirtualRepos bool
+
 	// HashedName is a repository hashed name used to limit the results to that repository.
 	HashedName string
 
@@ -943,7 +950,12 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 	}
 
 	if opt.Query != "" {
-		where = append(where, sqlf.Sprintf("lower(name) LIKE %s", "%"+strings.ToLower(opt.Query)+"%"))
+		like := "%" + strings.ToLower(opt.Query) + "%"
+		if opt.IncludeVirtualRepos {
+			where = append(where, sqlf.Sprintf("(lower(name) LIKE %s OR EXISTS (SELECT 1 FROM virtual_repos vr WHERE vr.repo_id = repo.id AND lower(vr.name) LIKE %s))", like, like))
+		} else {
+			where = append(where, sqlf.Sprintf("lower(name) LIKE %s", like))
+		}
 	}
 
 	for _, includePattern := range opt.IncludePatterns {
@@ -1067,7 +1079,11 @@ func (s *repoStore) listSQL(ctx context.Context, tr *trace.Trace, opt ReposListO
 		// (lower(name::text) COLLATE "C"). This is a MUCH faster comparison as it does not
 		// need to fold the casing of either the input value nor the value in the index.
 
-		where = append(where, sqlf.Sprintf(`lower(name::text) COLLATE "C" = ANY (%s::text[])`, pq.Array(lowerNames)))
+		if opt.IncludeVirtualRepos {
+			where = append(where, sqlf.Sprintf(`(lower(name::text) COLLATE "C" = ANY (%s::text[]) OR EXISTS (SELECT 1 FROM virtual_repos vr WHERE vr.repo_id = repo.id AND lower(vr.name) = ANY (%s::text[])))`, pq.Array(lowerNames), pq.Array(lowerNames)))
+		} else {
+			where = append(where, sqlf.Sprintf(`lower(name::text) COLLATE "C" = ANY (%s::text[])`, pq.Array(lowerNames)))
+		}
 	}
 
 	if opt.HashedName != "" {
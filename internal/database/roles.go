@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// RoleNotFoundErr is returned when a role cannot be found.
+type RoleNotFoundErr struct {
+	ID   int32
+	Name string
+}
+
+func (err *RoleNotFoundErr) Error() string {
+	if err.Name != "" {
+		return fmt.Sprintf("role not found: name=%q", err.Name)
+	}
+	return fmt.Sprintf("role not found: id=%d", err.ID)
+}
+
+func (*RoleNotFoundErr) NotFound() bool {
+	return true
+}
+
+// RoleStore provides access to the `roles` and `user_roles` tables.
+type RoleStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) RoleStore
+
+	// List returns all roles, ordered by name.
+	List(ctx context.Context) ([]*types.Role, error)
+	// GetByID returns the role with the given ID. A *RoleNotFoundErr is returned
+	// if no such role exists.
+	GetByID(ctx context.Context, id int32) (*types.Role, error)
+	// GetByName returns the role with the given name. A *RoleNotFoundErr is
+	// returned if no such role exists.
+	GetByName(ctx context.Context, name string) (*types.Role, error)
+
+	// ListForUser returns the roles assigned to the given user, ordered by name.
+	ListForUser(ctx context.Context, userID int32) ([]*types.Role, error)
+	// AssignToUser assigns the role to the user on behalf of a site admin. Roles
+	// assigned this way are never removed by SetUserRolesForSource.
+	AssignToUser(ctx context.Context, userID, roleID int32) error
+	// RemoveFromUser removes the role from the user, regardless of how it was
+	// assigned.
+	RemoveFromUser(ctx context.Context, userID, roleID int32) error
+	// SetUserRolesForSource makes the roles with the given names the only roles
+	// assigned to the user by the given source, such as an authentication
+	// provider. Unknown role names are ignored.
+	SetUserRolesForSource(ctx context.Context, userID int32, source string, roleNames []string) error
+
+	// UserHasPermission returns true if any of the roles assigned to the user
+	// grants the given permission.
+	UserHasPermission(ctx context.Context, userID int32, permission string) (bool, error)
+}
+
+var _ RoleStore = (*roleStore)(nil)
+
+type roleStore struct {
+	*basestore.Store
+}
+
+// RolesWith instantiates and returns a new RoleStore using the other store
+// handle.
+func RolesWith(other basestore.ShareableStore) RoleStore {
+	return &roleStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *roleStore) With(other basestore.ShareableStore) RoleStore {
+	return &roleStore{Store: s.Store.With(other)}
+}
+
+func (s *roleStore) List(ctx context.Context) ([]*types.Role, error) {
+	return scanRoles(s.Query(ctx, sqlf.Sprintf(listRolesQueryFmtstr, sqlf.Sprintf("TRUE"))))
+}
+
+const listRolesQueryFmtstr = `
+-- source: internal/database/roles.go:roleStore.List
+SELECT id, name, permissions, system, created_at
+FROM roles
+WHERE %s
+ORDER BY name ASC
+`
+
+func (s *roleStore) GetByID(ctx context.Context, id int32) (*types.Role, error) {
+	role, ok, err := scanFirstRole(s.Query(ctx, sqlf.Sprintf(listRolesQueryFmtstr, sqlf.Sprintf("id = %s", id))))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &RoleNotFoundErr{ID: id}
+	}
+	return role, nil
+}
+
+func (s *roleStore) GetByName(ctx context.Context, name string) (*types.Role, error) {
+	role, ok, err := scanFirstRole(s.Query(ctx, sqlf.Sprintf(listRolesQueryFmtstr, sqlf.Sprintf("name = %s", name))))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &RoleNotFoundErr{Name: name}
+	}
+	return role, nil
+}
+
+func (s *roleStore) ListForUser(ctx context.Context, userID int32) ([]*types.Role, error) {
+	return scanRoles(s.Query(ctx, sqlf.Sprintf(listRolesForUserQueryFmtstr, userID)))
+}
+
+const listRolesForUserQueryFmtstr = `
+-- source: internal/database/roles.go:roleStore.ListForUser
+SELECT r.id, r.name, r.permissions, r.system, r.created_at
+FROM roles r
+JOIN user_roles ur ON ur.role_id = r.id
+WHERE ur.user_id = %s
+ORDER BY r.name ASC
+`
+
+func (s *roleStore) AssignToUser(ctx context.Context, userID, roleID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf(assignRoleToUserQueryFmtstr, userID, roleID))
+}
+
+const assignRoleToUserQueryFmtstr = `
+-- source: internal/database/roles.go:roleStore.AssignToUser
+INSERT INTO user_roles (user_id, role_id, source)
+VALUES (%s, %s, NULL)
+ON CONFLICT (user_id, role_id) DO UPDATE SET source = NULL
+`
+
+func (s *roleStore) RemoveFromUser(ctx context.Context, userID, roleID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf(removeRoleFromUserQueryFmtstr, userID, roleID))
+}
+
+const removeRoleFromUserQueryFmtstr = `
+-- source: internal/database/roles.go:roleStore.RemoveFromUser
+DELETE FROM user_roles WHERE user_id = %s AND role_id = %s
+`
+
+func (s *roleStore) SetUserRolesForSource(ctx context.Context, userID int32, source string, roleNames []string) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		setUserRolesForSourceQueryFmtstr,
+		pq.Array(roleNames),
+		userID,
+		source,
+		userID,
+		source,
+	))
+}
+
+const setUserRolesForSourceQueryFmtstr = `
+-- source: internal/database/roles.go:roleStore.SetUserRolesForSource
+WITH desired AS (
+	SELECT id FROM roles WHERE name = ANY(%s)
+),
+removed AS (
+	DELETE FROM user_roles
+	WHERE user_id = %s AND source = %s AND role_id NOT IN (SELECT id FROM desired)
+)
+INSERT INTO user_roles (user_id, role_id, source)
+SELECT %s, id, %s FROM desired
+ON CONFLICT (user_id, role_id) DO NOTHING
+`
+
+func (s *roleStore) UserHasPermission(ctx context.Context, userID int32, permission string) (bool, error) {
+	ok, _, err := basestore.ScanFirstBool(s.Query(ctx, sqlf.Sprintf(userHasPermissionQueryFmtstr, userID, permission)))
+	return ok, err
+}
+
+const userHasPermissionQueryFmtstr = `
+-- source: internal/database/roles.go:roleStore.UserHasPermission
+SELECT EXISTS (
+	SELECT 1
+	FROM user_roles ur
+	JOIN roles r ON r.id = ur.role_id
+	WHERE ur.user_id = %s AND %s = ANY(r.permissions)
+)
+`
+
+var (
+	scanRoles     = basestore.NewSliceScanner(scanRole)
+	scanFirstRole = basestore.NewFirstScanner(scanRole)
+)
+
+func scanRole(sc dbutil.Scanner) (*types.Role, error) {
+	var r types.Role
+	err := sc.Scan(
+		&r.ID,
+		&r.Name,
+		pq.Array(&r.Permissions),
+		&r.System,
+		&r.CreatedAt,
+	)
+	return &r, err
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRoles(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	s := db.Roles()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "u"})
+	require.NoError(t, err)
+
+	roleNames := func(roles []*types.Role) []string {
+		names := make([]string, 0, len(roles))
+		for _, r := range roles {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	t.Run("List", func(t *testing.T) {
+		roles, err := s.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch-changes-admin", "code-insights-editor", "executor-secret-manager"}, roleNames(roles))
+		for _, r := range roles {
+			assert.True(t, r.System)
+		}
+	})
+
+	batchChangesAdmin, err := s.GetByName(ctx, "batch-changes-admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch_changes:admin"}, batchChangesAdmin.Permissions)
+
+	t.Run("GetByID", func(t *testing.T) {
+		role, err := s.GetByID(ctx, batchChangesAdmin.ID)
+		require.NoError(t, err)
+		assert.Equal(t, batchChangesAdmin, role)
+
+		_, err = s.GetByID(ctx, 1234)
+		assert.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("AssignToUser", func(t *testing.T) {
+		ok, err := s.UserHasPermission(ctx, user.ID, "batch_changes:admin")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, s.AssignToUser(ctx, user.ID, batchChangesAdmin.ID))
+		// Assigning a role twice is a no-op.
+		require.NoError(t, s.AssignToUser(ctx, user.ID, batchChangesAdmin.ID))
+
+		ok, err = s.UserHasPermission(ctx, user.ID, "batch_changes:admin")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		roles, err := s.ListForUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch-changes-admin"}, roleNames(roles))
+	})
+
+	t.Run("SetUserRolesForSource", func(t *testing.T) {
+		err := s.SetUserRolesForSource(ctx, user.ID, "saml:idp", []string{"batch-changes-admin", "code-insights-editor", "unknown"})
+		require.NoError(t, err)
+
+		roles, err := s.ListForUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch-changes-admin", "code-insights-editor"}, roleNames(roles))
+
+		// Roles assigned by a site admin are kept, roles assigned by the source are
+		// removed.
+		require.NoError(t, s.SetUserRolesForSource(ctx, user.ID, "saml:idp", nil))
+
+		roles, err = s.ListForUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch-changes-admin"}, roleNames(roles))
+	})
+
+	t.Run("RemoveFromUser", func(t *testing.T) {
+		require.NoError(t, s.RemoveFromUser(ctx, user.ID, batchChangesAdmin.ID))
+
+		roles, err := s.ListForUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+	})
+}
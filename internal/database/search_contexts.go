@@ -375,14 +375,22 @@ func (s *searchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Co
 	if err != nil {
 		return err
 	}
-
-	if len(repositoryRevisions) == 0 {
-		return nil
+	err = tx.Exec(ctx, sqlf.Sprintf("DELETE FROM search_context_virtual_repos WHERE search_context_id = %d", searchContextID))
+	if err != nil {
+		return err
 	}
 
 	values := []*sqlf.Query{}
+	virtualValues := []*sqlf.Query{}
 	for _, repoRev := range repositoryRevisions {
 		for _, revision := range repoRev.Revisions {
+			if repoRev.VirtualRepo != nil {
+				virtualValues = append(virtualValues, sqlf.Sprintf(
+					"(%s, %s, %s)",
+					searchContextID, repoRev.VirtualRepo.ID, revision,
+				))
+				continue
+			}
 			values = append(values, sqlf.Sprintf(
 				"(%s, %s, %s)",
 				searchContextID, repoRev.Repo.ID, revision,
@@ -390,10 +398,22 @@ func (s *searchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Co
 		}
 	}
 
-	return tx.Exec(ctx, sqlf.Sprintf(
-		"INSERT INTO search_context_repos (search_context_id, repo_id, revision) VALUES %s",
-		sqlf.Join(values, ","),
-	))
+	if len(values) > 0 {
+		err = tx.Exec(ctx, sqlf.Sprintf(
+			"INSERT INTO search_context_repos (search_context_id, repo_id, revision) VALUES %s",
+			sqlf.Join(values, ","),
+		))
+		if err != nil {
+			return err
+		}
+	}
+	if len(virtualValues) > 0 {
+		err = tx.Exec(ctx, sqlf.Sprintf(
+			"INSERT INTO search_context_virtual_repos (search_context_id, virtual_repo_id, revision) VALUES %s",
+			sqlf.Join(virtualValues, ","),
+		))
+	}
+	return err
 }
 
 func createSearchContext(ctx context.Context, s SearchContextsStore, searchContext *types.SearchContext) (*types.SearchContext, error) {
@@ -473,26 +493,41 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 }
 
 var getSearchContextRepositoryRevisionsFmtStr = `
+WITH r AS (
+	SELECT
+		id,
+		name
+	FROM repo
+	WHERE
+		deleted_at IS NULL
+		AND
+		blocked IS NULL
+		AND (%s) -- populates authzConds
+)
 SELECT
 	sc.repo_id,
 	sc.revision,
-	r.name
+	r.name,
+	NULL,
+	NULL,
+	NULL
 FROM
 	search_context_repos sc
-JOIN
-	(
-		SELECT
-			id,
-			name
-		FROM repo
-		WHERE
-			deleted_at IS NULL
-			AND
-			blocked IS NULL
-			AND (%s) -- populates authzConds
-	) r
-	ON r.id = sc.repo_id
+JOIN r ON r.id = sc.repo_id
 WHERE sc.search_context_id = %d
+UNION ALL
+SELECT
+	vr.repo_id,
+	scvr.revision,
+	r.name,
+	vr.id,
+	vr.name,
+	vr.path_prefix
+FROM
+	search_context_virtual_repos scvr
+JOIN virtual_repos vr ON vr.id = scvr.virtual_repo_id
+JOIN r ON r.id = vr.repo_id
+WHERE scvr.search_context_id = %d
 `
 
 func (s *searchContextsStore) GetSearchContextRepositoryRevisions(ctx context.Context, searchContextID int64) ([]*types.SearchContextRepositoryRevisions, error) {
@@ -505,6 +540,7 @@ func (s *searchContextsStore) GetSearchContextRepositoryRevisions(ctx context.Co
 		getSearchContextRepositoryRevisionsFmtStr,
 		authzConds,
 		searchContextID,
+		searchContextID,
 	))
 	if err != nil {
 		return nil, err
@@ -514,47 +550,88 @@ func (s *searchContextsStore) GetSearchContextRepositoryRevisions(ctx context.Co
 		err = basestore.CloseRows(rows, err)
 	}()
 
-	repositoryIDsToRevisions := map[int32][]string{}
-	repositoryIDsToName := map[int32]string{}
+	// Revisions are grouped by repository, and by virtual repository for
+	// the virtual repositories included in the search context.
+	type key struct {
+		repoID        int32
+		virtualRepoID int32
+	}
+	keysToRepositoryRevisions := map[key]*types.SearchContextRepositoryRevisions{}
 	for rows.Next() {
 		var repoID int32
 		var repoName, revision string
-		err = rows.Scan(&repoID, &revision, &repoName)
+		var virtualRepoID dbutil.NullInt32
+		var virtualRepoName, virtualRepoPathPrefix dbutil.NullString
+		err = rows.Scan(&repoID, &revision, &repoName, &virtualRepoID, &virtualRepoName, &virtualRepoPathPrefix)
 		if err != nil {
 			return nil, err
 		}
-		repositoryIDsToRevisions[repoID] = append(repositoryIDsToRevisions[repoID], revision)
-		repositoryIDsToName[repoID] = repoName
-	}
 
-	out := make([]*types.SearchContextRepositoryRevisions, 0, len(repositoryIDsToRevisions))
-	for repoID, revisions := range repositoryIDsToRevisions {
-		sort.Strings(revisions)
+		var vrID int32
+		if virtualRepoID.N != nil {
+			vrID = *virtualRepoID.N
+		}
+		k := key{repoID: repoID, virtualRepoID: vrID}
+		repoRev, ok := keysToRepositoryRevisions[k]
+		if !ok {
+			repoRev = &types.SearchContextRepositoryRevisions{
+				Repo: types.MinimalRepo{
+					ID:   api.RepoID(repoID),
+					Name: api.RepoName(repoName),
+				},
+			}
+			if vrID != 0 {
+				repoRev.VirtualRepo = &types.VirtualRepo{
+					ID:         vrID,
+					Name:       api.RepoName(*virtualRepoName.S),
+					Parent:     repoRev.Repo,
+					PathPrefix: *virtualRepoPathPrefix.S,
+				}
+			}
+			keysToRepositoryRevisions[k] = repoRev
+		}
+		repoRev.Revisions = append(repoRev.Revisions, revision)
+	}
 
-		out = append(out, &types.SearchContextRepositoryRevisions{
-			Repo: types.MinimalRepo{
-				ID:   api.RepoID(repoID),
-				Name: api.RepoName(repositoryIDsToName[repoID]),
-			},
-			Revisions: revisions,
-		})
+	out := make([]*types.SearchContextRepositoryRevisions, 0, len(keysToRepositoryRevisions))
+	for _, repoRev := range keysToRepositoryRevisions {
+		sort.Strings(repoRev.Revisions)
+		out = append(out, repoRev)
 	}
 
-	sort.Slice(out, func(i, j int) bool { return out[i].Repo.ID < out[j].Repo.ID })
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Repo.ID != out[j].Repo.ID {
+			return out[i].Repo.ID < out[j].Repo.ID
+		}
+		return virtualRepoID(out[i]) < virtualRepoID(out[j])
+	})
 
 	return out, nil
 }
 
+func virtualRepoID(repoRev *types.SearchContextRepositoryRevisions) int32 {
+	if repoRev.VirtualRepo == nil {
+		return 0
+	}
+	return repoRev.VirtualRepo.ID
+}
+
 var getAllRevisionsForReposFmtStr = `
 SELECT DISTINCT
-	scr.repo_id,
-	scr.revision
-FROM
-	search_context_repos scr
+	u.repo_id,
+	u.revision
+FROM (
+	SELECT scr.repo_id, scr.revision
+	FROM search_context_repos scr
+	UNION ALL
+	SELECT vr.repo_id, scvr.revision
+	FROM search_context_virtual_repos scvr
+	JOIN virtual_repos vr ON vr.id = scvr.virtual_repo_id
+) u
 WHERE
-	scr.repo_id = ANY (%s)
+	u.repo_id = ANY (%s)
 ORDER BY
-	scr.revision
+	u.revision
 `
 
 // GetAllRevisionsForRepos returns the list of revisions that are used in search
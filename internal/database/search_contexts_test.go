@@ -427,6 +427,58 @@ func TestSearchContexts_CreateAndSetRepositoryRevisions(t *testing.T) {
 	}
 }
 
+func TestSearchContexts_VirtualRepoRevisions(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	t.Parallel()
+	ctx := actor.WithInternalActor(context.Background())
+	sc := db.SearchContexts()
+	r := db.Repos()
+
+	if err := r.Create(ctx, &types.Repo{Name: "mono", URI: "https://example.com/mono"}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	mono, err := r.GetByName(ctx, "mono")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	monoName := types.MinimalRepo{ID: mono.ID, Name: mono.Name}
+
+	payments := &types.VirtualRepo{Name: "mono/services/payments", Parent: monoName, PathPrefix: "services/payments"}
+	if err := db.VirtualRepos().Create(ctx, payments); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	repositoryRevisions := []*types.SearchContextRepositoryRevisions{
+		{Repo: monoName, Revisions: []string{"main"}},
+		{Repo: monoName, Revisions: []string{"release"}, VirtualRepo: &types.VirtualRepo{ID: payments.ID, Name: payments.Name, Parent: monoName, PathPrefix: payments.PathPrefix}},
+	}
+	searchContext, err := sc.CreateSearchContextWithRepositoryRevisions(
+		ctx,
+		&types.SearchContext{Name: "sc", Description: "sc", Public: true},
+		repositoryRevisions,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	gotRepositoryRevisions, err := sc.GetSearchContextRepositoryRevisions(ctx, searchContext.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff(repositoryRevisions, gotRepositoryRevisions); diff != "" {
+		t.Fatalf("unexpected repository revisions (-want +got):\n%s", diff)
+	}
+
+	revs, err := sc.GetAllRevisionsForRepos(ctx, []api.RepoID{mono.ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff(map[api.RepoID][]string{mono.ID: {"main", "release"}}, revs); diff != "" {
+		t.Fatalf("unexpected revisions (-want +got):\n%s", diff)
+	}
+}
+
 func TestSearchContexts_Permissions(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := NewDB(logger, dbtest.NewDB(logger, t))
@@ -29,9 +29,10 @@ const (
 	SecurityEventNameSignInFailed    SecurityEventName = "SignInFailed"
 	SecurityEventNameSignInSucceeded SecurityEventName = "SignInSucceeded"
 
-	SecurityEventNameAccountCreated SecurityEventName = "AccountCreated"
-	SecurityEventNameAccountDeleted SecurityEventName = "AccountDeleted"
-	SecurityEventNameAccountNuked   SecurityEventName = "AccountNuked"
+	SecurityEventNameAccountCreated   SecurityEventName = "AccountCreated"
+	SecurityEventNameAccountDeleted   SecurityEventName = "AccountDeleted"
+	SecurityEventNameAccountNuked     SecurityEventName = "AccountNuked"
+	SecurityEventNameAccountRecovered SecurityEventName = "AccountRecovered"
 
 	SecurityEventNamPasswordResetRequested SecurityEventName = "PasswordResetRequested"
 	SecurityEventNamPasswordRandomized     SecurityEventName = "PasswordRandomized"
@@ -59,6 +60,15 @@ const (
 
 	SecurityEventOIDCLoginSucceeded SecurityEventName = "SecurityEventOIDCLoginSucceeded"
 	SecurityEventOIDCLoginFailed    SecurityEventName = "SecurityEventOIDCLoginFailed"
+
+	SecurityEventSCIMUserCreated     SecurityEventName = "SCIMUserCreated"
+	SecurityEventSCIMUserUpdated     SecurityEventName = "SCIMUserUpdated"
+	SecurityEventSCIMUserDeactivated SecurityEventName = "SCIMUserDeactivated"
+	SecurityEventSCIMUserReactivated SecurityEventName = "SCIMUserReactivated"
+	SecurityEventSCIMUserDeleted     SecurityEventName = "SCIMUserDeleted"
+	SecurityEventSCIMGroupCreated    SecurityEventName = "SCIMGroupCreated"
+	SecurityEventSCIMGroupUpdated    SecurityEventName = "SCIMGroupUpdated"
+	SecurityEventSCIMGroupDeleted    SecurityEventName = "SCIMGroupDeleted"
 )
 
 // SecurityEvent contains information needed for logging a security-relevant event.
@@ -10,7 +10,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -42,6 +44,11 @@ type SubRepoPermsStore interface {
 	GetByUserAndService(ctx context.Context, userID int32, serviceType string, serviceID string) (map[api.ExternalRepoSpec]authz.SubRepoPermissions, error)
 	RepoIDSupported(ctx context.Context, repoID api.RepoID) (bool, error)
 	RepoSupported(ctx context.Context, repo api.RepoName) (bool, error)
+	// UpsertPathRule creates or replaces the path rule of the subject of the given
+	// rule on its repository.
+	UpsertPathRule(ctx context.Context, rule *types.SubRepoPathRule) error
+	DeletePathRule(ctx context.Context, id int32) error
+	ListPathRules(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error)
 }
 
 // subRepoPermsStore is the unified interface for managing sub repository
@@ -141,22 +148,61 @@ WHERE repo_id = %s
 	return perms, nil
 }
 
-// GetByUser fetches all sub repo perms for a user keyed by repo.
+// GetByUser fetches all sub repo perms for a user keyed by repo. The rules synced
+// from the code host are returned as Paths. The path rules that apply to the
+// user through their organizations, their roles and then the user themselves are
+// returned as PathRules in that order, so that the most specific rule wins. Path
+// rules are evaluated separately and can only narrow the access granted by the
+// code host.
 func (s *subRepoPermsStore) GetByUser(ctx context.Context, userID int32) (map[api.RepoName]authz.SubRepoPermissions, error) {
 	enforceForSiteAdmins := conf.Get().AuthzEnforceForSiteAdmins
 
 	q := sqlf.Sprintf(`
-	SELECT r.name, paths
-	FROM sub_repo_permissions
-	JOIN repo r on r.id = repo_id
-	JOIN users u on u.id = user_id
-	WHERE user_id = %s
-	AND version = %s
+	WITH path_rules AS (
+		SELECT
+			repo_id,
+			paths,
+			CASE
+				WHEN org_id IS NOT NULL THEN 1
+				WHEN role_id IS NOT NULL THEN 2
+				ELSE 3
+			END AS precedence,
+			id
+		FROM sub_repo_path_rules
+		WHERE user_id = %s
+		OR org_id IN (SELECT org_id FROM org_members WHERE user_id = %s)
+		OR role_id IN (SELECT role_id FROM user_roles WHERE user_id = %s)
+	),
+	rules AS (
+		SELECT repo_id, paths, 0 AS precedence, 0 AS id
+		FROM sub_repo_permissions
+		WHERE user_id = %s
+		AND version = %s
+		UNION ALL
+		-- Repos without synced rules are fully visible, so path rules,
+		-- which can only narrow access, start from the whole repo.
+		SELECT DISTINCT repo_id, ARRAY['/**'], 0, 0
+		FROM path_rules pr
+		WHERE NOT EXISTS (
+			SELECT FROM sub_repo_permissions srp
+			WHERE srp.repo_id = pr.repo_id
+			AND srp.user_id = %s
+			AND srp.version = %s
+		)
+		UNION ALL
+		SELECT repo_id, paths, precedence, id
+		FROM path_rules
+	)
+	SELECT r.name, rules.paths, rules.precedence > 0
+	FROM rules
+	JOIN repo r on r.id = rules.repo_id
+	JOIN users u on u.id = %s
 	-- When user is a site admin and AuthzEnforceForSiteAdmins is FALSE
 	-- we want to return zero results. This causes us to fall back to
 	-- repo level checks and allows access to all paths in all repos.
-	AND NOT (u.site_admin AND NOT %t)
-	`, userID, SubRepoPermsVersion, enforceForSiteAdmins)
+	WHERE NOT (u.site_admin AND NOT %t)
+	ORDER BY rules.repo_id, rules.precedence, rules.id
+	`, userID, userID, userID, userID, SubRepoPermsVersion, userID, SubRepoPermsVersion, userID, enforceForSiteAdmins)
 
 	rows, err := s.Query(ctx, q)
 	if err != nil {
@@ -165,11 +211,18 @@ func (s *subRepoPermsStore) GetByUser(ctx context.Context, userID int32) (map[ap
 
 	result := make(map[api.RepoName]authz.SubRepoPermissions)
 	for rows.Next() {
-		var perms authz.SubRepoPermissions
+		var paths []string
 		var repoName api.RepoName
-		if err := rows.Scan(&repoName, pq.Array(&perms.Paths)); err != nil {
+		var isPathRule bool
+		if err := rows.Scan(&repoName, pq.Array(&paths), &isPathRule); err != nil {
 			return nil, errors.Wrap(err, "scanning row")
 		}
+		perms := result[repoName]
+		if isPathRule {
+			perms.PathRules = append(perms.PathRules, paths...)
+		} else {
+			perms.Paths = append(perms.Paths, paths...)
+		}
 		result[repoName] = perms
 	}
 
@@ -217,15 +270,18 @@ WHERE user_id = %s
 }
 
 // RepoIDSupported returns true if repo with the given ID has sub-repo permissions
-// (i.e. it is private and its type is one of the SubRepoSupportedCodeHostTypes)
+// (i.e. it is private and its type is one of the SubRepoSupportedCodeHostTypes,
+// or it has path rules)
 func (s *subRepoPermsStore) RepoIDSupported(ctx context.Context, repoID api.RepoID) (bool, error) {
 	q := sqlf.Sprintf(`
 SELECT EXISTS(
 SELECT
 FROM repo
 WHERE id = %s
-AND private = TRUE
-AND external_service_type IN (%s)
+AND (
+	(private = TRUE AND external_service_type IN (%s))
+	OR EXISTS (SELECT FROM sub_repo_path_rules WHERE repo_id = repo.id)
+)
 )
 `, repoID, sqlf.Join(supportedTypesQuery, ","))
 
@@ -237,15 +293,18 @@ AND external_service_type IN (%s)
 }
 
 // RepoSupported returns true if repo has sub-repo permissions
-// (i.e. it is private and its type is one of the SubRepoSupportedCodeHostTypes)
+// (i.e. it is private and its type is one of the SubRepoSupportedCodeHostTypes,
+// or it has path rules)
 func (s *subRepoPermsStore) RepoSupported(ctx context.Context, repo api.RepoName) (bool, error) {
 	q := sqlf.Sprintf(`
 SELECT EXISTS(
 SELECT
 FROM repo
 WHERE name = %s
-AND private = TRUE
-AND external_service_type IN (%s)
+AND (
+	(private = TRUE AND external_service_type IN (%s))
+	OR EXISTS (SELECT FROM sub_repo_path_rules WHERE repo_id = repo.id)
+)
 )
 `, repo, sqlf.Join(supportedTypesQuery, ","))
 
@@ -255,3 +314,78 @@ AND external_service_type IN (%s)
 	}
 	return exists, nil
 }
+
+// UpsertPathRule creates or replaces the path rule of the subject of the given
+// rule on its repository, and sets the ID and timestamps of the rule.
+func (s *subRepoPermsStore) UpsertPathRule(ctx context.Context, rule *types.SubRepoPathRule) error {
+	var subject string
+	switch {
+	case rule.UserID != 0 && rule.OrgID == 0 && rule.RoleID == 0:
+		subject = "user_id"
+	case rule.OrgID != 0 && rule.UserID == 0 && rule.RoleID == 0:
+		subject = "org_id"
+	case rule.RoleID != 0 && rule.UserID == 0 && rule.OrgID == 0:
+		subject = "role_id"
+	default:
+		return errors.New("exactly one of user, organization and role must be set on a path rule")
+	}
+
+	q := sqlf.Sprintf(`
+INSERT INTO sub_repo_path_rules (repo_id, user_id, org_id, role_id, paths)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (repo_id, `+subject+`) WHERE `+subject+` IS NOT NULL
+DO UPDATE
+SET
+  paths = EXCLUDED.paths,
+  updated_at = now()
+RETURNING id, created_at, updated_at
+`, rule.RepoID, dbutil.NullInt32Column(rule.UserID), dbutil.NullInt32Column(rule.OrgID), dbutil.NullInt32Column(rule.RoleID), pq.Array(rule.Paths))
+
+	err := s.QueryRow(ctx, q).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	return errors.Wrap(err, "upserting sub repo path rule")
+}
+
+// DeletePathRule deletes the path rule with the given ID.
+func (s *subRepoPermsStore) DeletePathRule(ctx context.Context, id int32) error {
+	q := sqlf.Sprintf(`DELETE FROM sub_repo_path_rules WHERE id = %s`, id)
+	return errors.Wrap(s.Exec(ctx, q), "deleting sub repo path rule")
+}
+
+// ListPathRules returns the path rules of the given repo.
+func (s *subRepoPermsStore) ListPathRules(ctx context.Context, repoID api.RepoID) ([]*types.SubRepoPathRule, error) {
+	q := sqlf.Sprintf(`
+SELECT id, repo_id, user_id, org_id, role_id, paths, created_at, updated_at
+FROM sub_repo_path_rules
+WHERE repo_id = %s
+ORDER BY id
+`, repoID)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing sub repo path rules")
+	}
+
+	var rules []*types.SubRepoPathRule
+	for rows.Next() {
+		var rule types.SubRepoPathRule
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.RepoID,
+			&dbutil.NullInt32{N: &rule.UserID},
+			&dbutil.NullInt32{N: &rule.OrgID},
+			&dbutil.NullInt32{N: &rule.RoleID},
+			pq.Array(&rule.Paths),
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "scanning row")
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing rows")
+	}
+
+	return rules, nil
+}
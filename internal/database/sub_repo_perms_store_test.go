@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -221,6 +222,102 @@ func TestSubRepoPermsGetByUser(t *testing.T) {
 	}
 }
 
+func TestSubRepoPermsPathRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+
+	ctx := context.Background()
+	s := db.SubRepoPerms()
+	prepareSubRepoTestData(ctx, t, db)
+
+	for _, q := range []string{
+		`INSERT INTO orgs(id, name) VALUES (1, 'acme')`,
+		`INSERT INTO org_members(org_id, user_id) VALUES (1, 1)`,
+		`INSERT INTO roles(id, name) VALUES (100, 'contractor')`,
+		`INSERT INTO user_roles(user_id, role_id) VALUES (1, 100)`,
+	} {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	userID := int32(1)
+
+	// Repo 5 is a private GitHub repo, which does not support sub-repo permissions
+	// until it has path rules.
+	testSubRepoNotSupportedForRepo(ctx, t, s, 5, "github.com/foo/qux", "Repo has no path rules, therefore sub-repo perms are not supported")
+
+	orgRule := &types.SubRepoPathRule{RepoID: 5, OrgID: 1, Paths: []string{"-/secrets/**"}}
+	roleRule := &types.SubRepoPathRule{RepoID: 5, RoleID: 100, Paths: []string{"-/docs/**"}}
+	userRule := &types.SubRepoPathRule{RepoID: 5, UserID: userID, Paths: []string{"/docs/public/**"}}
+	for _, rule := range []*types.SubRepoPathRule{userRule, roleRule, orgRule} {
+		if err := s.UpsertPathRule(ctx, rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testSubRepoSupportedForRepo(ctx, t, s, 5, "github.com/foo/qux", "Repo has path rules, therefore sub-repo perms are supported")
+
+	// Path rules are returned separately from synced rules, ordered from the least
+	// to the most specific subject.
+	if err := s.Upsert(ctx, userID, api.RepoID(1), authz.SubRepoPermissions{Paths: []string{"/src/**"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertPathRule(ctx, &types.SubRepoPathRule{RepoID: 1, UserID: userID, Paths: []string{"-/src/internal/**"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := s.GetByUser(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[api.RepoName]authz.SubRepoPermissions{
+		"github.com/foo/bar": {
+			Paths:     []string{"/src/**"},
+			PathRules: []string{"-/src/internal/**"},
+		},
+		"github.com/foo/qux": {
+			Paths:     []string{"/**"},
+			PathRules: []string{"-/secrets/**", "-/docs/**", "/docs/public/**"},
+		},
+	}
+	assert.Equal(t, want, have)
+
+	// Upserting the rule of the same subject replaces its paths.
+	userRule.Paths = []string{"/docs/internal/**"}
+	if err := s.UpsertPathRule(ctx, &types.SubRepoPathRule{RepoID: 5, UserID: userID, Paths: userRule.Paths}); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := s.ListPathRules(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("want 3 rules but got %d", len(rules))
+	}
+	assert.Equal(t, userRule.ID, rules[0].ID)
+	assert.Equal(t, userRule.Paths, rules[0].Paths)
+	assert.Equal(t, userID, rules[0].UserID)
+	assert.Equal(t, int32(100), rules[1].RoleID)
+	assert.Equal(t, int32(1), rules[2].OrgID)
+
+	for _, rule := range rules {
+		if err := s.DeletePathRule(ctx, rule.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testSubRepoNotSupportedForRepo(ctx, t, s, 5, "github.com/foo/qux", "Repo has no path rules, therefore sub-repo perms are not supported")
+
+	if err := s.UpsertPathRule(ctx, &types.SubRepoPathRule{RepoID: 5, UserID: userID, OrgID: 1}); err == nil {
+		t.Fatal("want error for a rule with several subjects")
+	}
+}
+
 func TestSubRepoPermsGetByUserAndService(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
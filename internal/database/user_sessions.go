@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// UserSessionNotFoundErr is returned when a user session cannot be found.
+type UserSessionNotFoundErr struct {
+	ID int32
+}
+
+func (err *UserSessionNotFoundErr) Error() string {
+	if err.ID == 0 {
+		return "user session not found"
+	}
+	return fmt.Sprintf("user session not found: id=%d", err.ID)
+}
+
+func (*UserSessionNotFoundErr) NotFound() bool {
+	return true
+}
+
+// UserSessionStore provides access to the `user_sessions` table, which records
+// the metadata of the cookie-based sessions of users.
+type UserSessionStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) UserSessionStore
+
+	// Create records a new session. Expired sessions of the same user are
+	// deleted at the same time. If a session with the same session ID already
+	// exists, it is returned instead.
+	Create(ctx context.Context, session *types.UserSession) (*types.UserSession, error)
+	// GetByID returns the session with the given ID, regardless of whether it is
+	// revoked or expired. A *UserSessionNotFoundErr is returned if no such
+	// session exists.
+	GetByID(ctx context.Context, id int32) (*types.UserSession, error)
+	// GetBySessionID returns the session with the given session ID, regardless
+	// of whether it is revoked or expired. A *UserSessionNotFoundErr is returned
+	// if no such session exists.
+	GetBySessionID(ctx context.Context, sessionID string) (*types.UserSession, error)
+	// ListByUser returns the active sessions of the given user, most recently
+	// seen first.
+	ListByUser(ctx context.Context, userID int32) ([]*types.UserSession, error)
+	// Touch records that the session was seen from the given IP address and user
+	// agent, and extends it until the given time.
+	Touch(ctx context.Context, sessionID, ipAddress, userAgent string, expiresAt time.Time) error
+	// Revoke revokes the session with the given ID.
+	Revoke(ctx context.Context, id int32) error
+	// RevokeByUserIDs revokes all active sessions of the given users.
+	RevokeByUserIDs(ctx context.Context, userIDs []int32) error
+}
+
+var _ UserSessionStore = (*userSessionStore)(nil)
+
+type userSessionStore struct {
+	*basestore.Store
+}
+
+// UserSessionsWith instantiates and returns a new UserSessionStore using the
+// other store handle.
+func UserSessionsWith(other basestore.ShareableStore) UserSessionStore {
+	return &userSessionStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *userSessionStore) With(other basestore.ShareableStore) UserSessionStore {
+	return &userSessionStore{Store: s.Store.With(other)}
+}
+
+func (s *userSessionStore) Create(ctx context.Context, session *types.UserSession) (*types.UserSession, error) {
+	created, ok, err := scanFirstUserSession(s.Query(ctx, sqlf.Sprintf(
+		createUserSessionQueryFmtstr,
+		session.UserID,
+		session.SessionID,
+		session.UserID,
+		session.SessionID,
+		session.AuthProvider,
+		dbutil.NewNullString(session.IPAddress),
+		dbutil.NewNullString(session.UserAgent),
+		session.ExpiresAt,
+		session.SessionID,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &UserSessionNotFoundErr{}
+	}
+	return created, nil
+}
+
+const createUserSessionQueryFmtstr = `
+-- source: internal/database/user_sessions.go:userSessionStore.Create
+WITH expired AS (
+	DELETE FROM user_sessions
+	WHERE user_id = %s AND expires_at < NOW() AND session_id <> %s
+),
+inserted AS (
+	INSERT INTO user_sessions (user_id, session_id, auth_provider, ip_address, user_agent, expires_at)
+	VALUES (%s, %s, %s, %s, %s, %s)
+	ON CONFLICT (session_id) DO NOTHING
+	RETURNING ` + userSessionColumns + `
+)
+SELECT ` + userSessionColumns + ` FROM inserted
+UNION ALL
+SELECT ` + userSessionColumns + ` FROM user_sessions WHERE session_id = %s AND NOT EXISTS (SELECT 1 FROM inserted)
+`
+
+func (s *userSessionStore) GetByID(ctx context.Context, id int32) (*types.UserSession, error) {
+	session, ok, err := scanFirstUserSession(s.Query(ctx, sqlf.Sprintf(getUserSessionQueryFmtstr, sqlf.Sprintf("id = %s", id))))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &UserSessionNotFoundErr{ID: id}
+	}
+	return session, nil
+}
+
+func (s *userSessionStore) GetBySessionID(ctx context.Context, sessionID string) (*types.UserSession, error) {
+	session, ok, err := scanFirstUserSession(s.Query(ctx, sqlf.Sprintf(getUserSessionQueryFmtstr, sqlf.Sprintf("session_id = %s", sessionID))))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &UserSessionNotFoundErr{}
+	}
+	return session, nil
+}
+
+const getUserSessionQueryFmtstr = `
+-- source: internal/database/user_sessions.go:userSessionStore.GetByID
+SELECT ` + userSessionColumns + `
+FROM user_sessions
+WHERE %s
+`
+
+func (s *userSessionStore) ListByUser(ctx context.Context, userID int32) ([]*types.UserSession, error) {
+	return scanUserSessions(s.Query(ctx, sqlf.Sprintf(listUserSessionsByUserQueryFmtstr, userID)))
+}
+
+const listUserSessionsByUserQueryFmtstr = `
+-- source: internal/database/user_sessions.go:userSessionStore.ListByUser
+SELECT ` + userSessionColumns + `
+FROM user_sessions
+WHERE user_id = %s AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY last_seen_at DESC, id DESC
+`
+
+func (s *userSessionStore) Touch(ctx context.Context, sessionID, ipAddress, userAgent string, expiresAt time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		touchUserSessionQueryFmtstr,
+		dbutil.NewNullString(ipAddress),
+		dbutil.NewNullString(userAgent),
+		expiresAt,
+		sessionID,
+	))
+}
+
+const touchUserSessionQueryFmtstr = `
+-- source: internal/database/user_sessions.go:userSessionStore.Touch
+UPDATE user_sessions
+SET
+	last_seen_at = NOW(),
+	ip_address = COALESCE(%s, ip_address),
+	user_agent = COALESCE(%s, user_agent),
+	expires_at = %s
+WHERE session_id = %s AND revoked_at IS NULL
+`
+
+func (s *userSessionStore) Revoke(ctx context.Context, id int32) error {
+	return s.Exec(ctx, sqlf.Sprintf(revokeUserSessionsQueryFmtstr, sqlf.Sprintf("id = %s", id)))
+}
+
+func (s *userSessionStore) RevokeByUserIDs(ctx context.Context, userIDs []int32) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return s.Exec(ctx, sqlf.Sprintf(revokeUserSessionsQueryFmtstr, sqlf.Sprintf("user_id = ANY(%s)", pq.Array(userIDs))))
+}
+
+const revokeUserSessionsQueryFmtstr = `
+-- source: internal/database/user_sessions.go:userSessionStore.Revoke
+UPDATE user_sessions
+SET revoked_at = NOW()
+WHERE %s AND revoked_at IS NULL
+`
+
+const userSessionColumns = `id, user_id, session_id, auth_provider, ip_address, user_agent, created_at, last_seen_at, expires_at, revoked_at`
+
+var (
+	scanUserSessions     = basestore.NewSliceScanner(scanUserSession)
+	scanFirstUserSession = basestore.NewFirstScanner(scanUserSession)
+)
+
+func scanUserSession(sc dbutil.Scanner) (*types.UserSession, error) {
+	var s types.UserSession
+	err := sc.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.SessionID,
+		&s.AuthProvider,
+		&dbutil.NullString{S: &s.IPAddress},
+		&dbutil.NullString{S: &s.UserAgent},
+		&s.CreatedAt,
+		&s.LastSeenAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+	)
+	return &s, err
+}
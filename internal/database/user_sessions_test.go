@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestUserSessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	s := db.UserSessions()
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "u"})
+	require.NoError(t, err)
+	other, err := db.Users().Create(ctx, NewUser{Username: "other"})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+
+	first, err := s.Create(ctx, &types.UserSession{
+		UserID:       user.ID,
+		SessionID:    "first",
+		AuthProvider: "builtin",
+		IPAddress:    "10.0.0.1",
+		UserAgent:    "curl/7.79.1",
+		ExpiresAt:    expiresAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "builtin", first.AuthProvider)
+	assert.Equal(t, "10.0.0.1", first.IPAddress)
+	assert.Nil(t, first.RevokedAt)
+
+	second, err := s.Create(ctx, &types.UserSession{UserID: user.ID, SessionID: "second", ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	assert.Empty(t, second.IPAddress)
+
+	_, err = s.Create(ctx, &types.UserSession{UserID: other.ID, SessionID: "other", ExpiresAt: expiresAt})
+	require.NoError(t, err)
+
+	t.Run("Create is idempotent", func(t *testing.T) {
+		again, err := s.Create(ctx, &types.UserSession{UserID: user.ID, SessionID: "first", AuthProvider: "saml", ExpiresAt: expiresAt})
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	})
+
+	t.Run("Create deletes expired sessions", func(t *testing.T) {
+		_, err := s.Create(ctx, &types.UserSession{UserID: user.ID, SessionID: "expired", ExpiresAt: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		_, err = s.Create(ctx, &types.UserSession{UserID: user.ID, SessionID: "third", ExpiresAt: expiresAt})
+		require.NoError(t, err)
+
+		_, err = s.GetBySessionID(ctx, "expired")
+		assert.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		session, err := s.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first, session)
+
+		_, err = s.GetByID(ctx, 1234)
+		assert.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		later := expiresAt.Add(time.Hour)
+		require.NoError(t, s.Touch(ctx, "second", "10.0.0.2", "", later))
+
+		session, err := s.GetBySessionID(ctx, "second")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.2", session.IPAddress)
+		assert.Equal(t, later, session.ExpiresAt.UTC())
+		assert.True(t, session.LastSeenAt.After(second.LastSeenAt))
+	})
+
+	sessionIDs := func(sessions []*types.UserSession) []string {
+		ids := make([]string, 0, len(sessions))
+		for _, s := range sessions {
+			ids = append(ids, s.SessionID)
+		}
+		return ids
+	}
+
+	t.Run("ListByUser", func(t *testing.T) {
+		sessions, err := s.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"second", "third", "first"}, sessionIDs(sessions))
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		require.NoError(t, s.Revoke(ctx, first.ID))
+
+		session, err := s.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, session.RevokedAt)
+
+		sessions, err := s.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"second", "third"}, sessionIDs(sessions))
+	})
+
+	t.Run("RevokeByUserIDs", func(t *testing.T) {
+		require.NoError(t, s.RevokeByUserIDs(ctx, []int32{user.ID}))
+
+		sessions, err := s.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+
+		sessions, err = s.ListByUser(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other"}, sessionIDs(sessions))
+	})
+}
@@ -78,6 +78,7 @@ type UserStore interface {
 	List(context.Context, *UsersListOptions) (_ []*types.User, err error)
 	ListDates(context.Context) ([]types.UserDates, error)
 	RandomizePasswordAndClearPasswordResetRateLimit(context.Context, int32) error
+	RecoverUsersList(context.Context, []int32) (_ []int32, err error)
 	RenewPasswordResetCode(context.Context, int32) (string, error)
 	SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool) error
 	SetPassword(ctx context.Context, id int32, resetCode, newPassword string) (bool, error)
@@ -559,6 +560,60 @@ func (u *userStore) DeleteList(ctx context.Context, ids []int32) (err error) {
 	return nil
 }
 
+// RecoverUsersList restores the soft-deleted users with the given IDs, along with
+// their usernames and external accounts, and returns the IDs of the users that
+// were restored. Emails and access tokens of the users are not restored.
+func (u *userStore) RecoverUsersList(ctx context.Context, ids []int32) (_ []int32, err error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	txBase, err := u.Store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx := &userStore{logger: u.logger, Store: txBase}
+	defer func() { err = tx.Done(err) }()
+
+	userIDs := make([]*sqlf.Query, len(ids))
+	for i := range ids {
+		userIDs[i] = sqlf.Sprintf("%d", ids[i])
+	}
+
+	idsCond := sqlf.Join(userIDs, ",")
+
+	rows, err := tx.Query(ctx, sqlf.Sprintf("UPDATE users SET deleted_at=NULL, updated_at=now() WHERE id IN (%s) AND deleted_at IS NOT NULL RETURNING id", idsCond))
+	recovered, err := basestore.ScanInt32s(rows, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(recovered) == 0 {
+		return nil, nil
+	}
+
+	recoveredIDs := make([]*sqlf.Query, len(recovered))
+	for i := range recovered {
+		recoveredIDs[i] = sqlf.Sprintf("%d", recovered[i])
+	}
+	recoveredCond := sqlf.Join(recoveredIDs, ",")
+
+	// Claim the username again, which fails if it has been taken by another user or org.
+	if err := tx.Exec(ctx, sqlf.Sprintf("INSERT INTO names(name, user_id) SELECT username, id FROM users WHERE id IN (%s)", recoveredCond)); err != nil {
+		var e *pgconn.PgError
+		if errors.As(err, &e) && e.ConstraintName == "names_pkey" {
+			return nil, errCannotCreateUser{errorCodeUsernameExists}
+		}
+		return nil, err
+	}
+	if err := tx.Exec(ctx, sqlf.Sprintf("UPDATE user_external_accounts SET deleted_at=NULL WHERE user_id IN (%s) AND deleted_at IS NOT NULL", recoveredCond)); err != nil {
+		return nil, err
+	}
+
+	logUserDeletionEvents(ctx, NewDBWith(u.logger, u), recovered, SecurityEventNameAccountRecovered)
+
+	return recovered, nil
+}
+
 // HardDelete removes the user and all resources associated with this user.
 func (u *userStore) HardDelete(ctx context.Context, id int32) (err error) {
 	return u.HardDeleteList(ctx, []int32{id})
@@ -822,6 +877,9 @@ type UsersListOptions struct {
 	// user accounts.
 	ExcludeSourcegraphOperators bool
 
+	// IncludeDeleted includes soft-deleted users.
+	IncludeDeleted bool
+
 	*LimitOffset
 }
 
@@ -892,7 +950,9 @@ EXISTS (
 
 func (*userStore) listSQL(opt UsersListOptions) (conds []*sqlf.Query) {
 	conds = []*sqlf.Query{sqlf.Sprintf("TRUE")}
-	conds = append(conds, sqlf.Sprintf("deleted_at IS NULL"))
+	if !opt.IncludeDeleted {
+		conds = append(conds, sqlf.Sprintf("deleted_at IS NULL"))
+	}
 	if opt.Query != "" {
 		query := "%" + opt.Query + "%"
 		conds = append(conds, sqlf.Sprintf("(username ILIKE %s OR display_name ILIKE %s)", query, query))
@@ -752,6 +752,72 @@ func TestUsers_Delete(t *testing.T) {
 	}
 }
 
+func TestUsers_RecoverUsersList(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Internal: true})
+
+	userID, err := db.UserExternalAccounts().CreateUserAndSave(ctx, NewUser{Username: "u"}, extsvc.AccountSpec{
+		ServiceType: "scim",
+		ServiceID:   "scim",
+		AccountID:   "u",
+	}, extsvc.AccountData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Users().Delete(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Soft-deleted users are only listed on request.
+	users, err := db.Users().List(ctx, &UsersListOptions{UserIDs: []int32{userID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 0 {
+		t.Fatalf("got %d users, want 0", len(users))
+	}
+	users, err = db.Users().List(ctx, &UsersListOptions{UserIDs: []int32{userID}, IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("got %d users, want 1", len(users))
+	}
+
+	recovered, err := db.Users().RecoverUsersList(ctx, []int32{userID, 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int32{userID}, recovered); diff != "" {
+		t.Fatalf("recovered users mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := db.Users().GetByUsername(ctx, "u"); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := db.UserExternalAccounts().List(ctx, ExternalAccountsListOptions{UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 {
+		t.Fatalf("got %d external accounts, want 1", len(accounts))
+	}
+
+	// Recovering active users is a no-op.
+	recovered, err = db.Users().RecoverUsersList(ctx, []int32{userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovered) != 0 {
+		t.Fatalf("got %d recovered users, want 0", len(recovered))
+	}
+}
+
 func TestUsers_HasTag(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -0,0 +1,234 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// VirtualRepoNotFoundErr is returned when a virtual repository does not exist.
+type VirtualRepoNotFoundErr struct {
+	ID   int32
+	Name api.RepoName
+}
+
+func (e *VirtualRepoNotFoundErr) Error() string {
+	if e.Name != "" {
+		return "virtual repo not found: name=" + string(e.Name)
+	}
+	return "virtual repo not found"
+}
+
+func (e *VirtualRepoNotFoundErr) NotFound() bool {
+	return true
+}
+
+// VirtualReposListOptions contains options for listing virtual repositories.
+type VirtualReposListOptions struct {
+	// When specified, only include virtual repositories of these parent repositories.
+	RepoIDs []api.RepoID
+	// When specified, only include virtual repositories with these names.
+	Names []api.RepoName
+	// When specified, only include virtual repositories whose names contain
+	// this string, case-insensitively.
+	Query string
+
+	*LimitOffset
+}
+
+type VirtualReposStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) VirtualReposStore
+
+	// Create creates the given virtual repository. Leading and trailing
+	// slashes are removed from its path prefix.
+	Create(ctx context.Context, repo *types.VirtualRepo) error
+	// Delete deletes the virtual repository with the given ID.
+	Delete(ctx context.Context, id int32) error
+	// GetByID returns the virtual repository with the given ID.
+	GetByID(ctx context.Context, id int32) (*types.VirtualRepo, error)
+	// GetByName returns the virtual repository with the given name.
+	GetByName(ctx context.Context, name api.RepoName) (*types.VirtualRepo, error)
+	// List returns the virtual repositories matching the given options,
+	// ordered by name.
+	List(ctx context.Context, opts VirtualReposListOptions) ([]*types.VirtualRepo, error)
+	// Count returns the number of virtual repositories matching the given
+	// options.
+	Count(ctx context.Context, opts VirtualReposListOptions) (int, error)
+}
+
+var _ VirtualReposStore = (*virtualReposStore)(nil)
+
+// virtualReposStore is responsible for data stored in the virtual_repos table.
+type virtualReposStore struct {
+	*basestore.Store
+}
+
+// VirtualReposWith instantiates and returns a new VirtualReposStore using the
+// other store handle.
+func VirtualReposWith(other basestore.ShareableStore) VirtualReposStore {
+	return &virtualReposStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *virtualReposStore) With(other basestore.ShareableStore) VirtualReposStore {
+	return &virtualReposStore{Store: s.Store.With(other)}
+}
+
+func (s *virtualReposStore) Create(ctx context.Context, repo *types.VirtualRepo) error {
+	repo.PathPrefix = strings.Trim(repo.PathPrefix, "/")
+	if repo.PathPrefix == "" {
+		return errors.New("virtual repo path prefix must not be empty")
+	}
+	if repo.Name == "" {
+		return errors.New("virtual repo name must not be empty")
+	}
+
+	row := s.QueryRow(ctx, sqlf.Sprintf(createVirtualRepoQueryFmtstr, repo.Parent.ID, repo.Name, repo.PathPrefix))
+	return row.Scan(&repo.ID, &repo.Parent.Name, &repo.CreatedAt, &repo.UpdatedAt)
+}
+
+const createVirtualRepoQueryFmtstr = `
+-- source: internal/database/virtual_repos.go:virtualReposStore.Create
+WITH inserted AS (
+	INSERT INTO virtual_repos (repo_id, name, path_prefix)
+	VALUES (%s, %s, %s)
+	RETURNING id, repo_id, created_at, updated_at
+)
+SELECT inserted.id, repo.name, inserted.created_at, inserted.updated_at
+FROM inserted
+JOIN repo ON repo.id = inserted.repo_id
+`
+
+func (s *virtualReposStore) Delete(ctx context.Context, id int32) error {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(deleteVirtualRepoQueryFmtstr, id))
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &VirtualRepoNotFoundErr{ID: id}
+	}
+	return nil
+}
+
+const deleteVirtualRepoQueryFmtstr = `
+-- source: internal/database/virtual_repos.go:virtualReposStore.Delete
+DELETE FROM virtual_repos WHERE id = %s
+`
+
+func (s *virtualReposStore) GetByID(ctx context.Context, id int32) (*types.VirtualRepo, error) {
+	repo, ok, err := scanFirstVirtualRepo(s.Query(ctx, sqlf.Sprintf(listVirtualReposQueryFmtstr, sqlf.Sprintf("vr.id = %s", id), (*LimitOffset)(nil).SQL())))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &VirtualRepoNotFoundErr{ID: id}
+	}
+	return repo, nil
+}
+
+func (s *virtualReposStore) GetByName(ctx context.Context, name api.RepoName) (*types.VirtualRepo, error) {
+	repo, ok, err := scanFirstVirtualRepo(s.Query(ctx, sqlf.Sprintf(listVirtualReposQueryFmtstr, sqlf.Sprintf("vr.name = %s", name), (*LimitOffset)(nil).SQL())))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &VirtualRepoNotFoundErr{Name: name}
+	}
+	return repo, nil
+}
+
+func (s *virtualReposStore) List(ctx context.Context, opts VirtualReposListOptions) ([]*types.VirtualRepo, error) {
+	q := sqlf.Sprintf(listVirtualReposQueryFmtstr, sqlf.Join(opts.sqlConds(), "AND"), opts.LimitOffset.SQL())
+	return scanVirtualRepos(s.Query(ctx, q))
+}
+
+const listVirtualReposQueryFmtstr = `
+-- source: internal/database/virtual_repos.go:virtualReposStore.List
+SELECT
+	vr.id,
+	vr.name,
+	vr.repo_id,
+	repo.name,
+	repo.stars,
+	vr.path_prefix,
+	vr.created_at,
+	vr.updated_at
+FROM virtual_repos vr
+JOIN repo ON repo.id = vr.repo_id
+WHERE
+	repo.deleted_at IS NULL AND
+	repo.blocked IS NULL AND
+	%s
+ORDER BY vr.name
+%s
+`
+
+func (s *virtualReposStore) Count(ctx context.Context, opts VirtualReposListOptions) (int, error) {
+	q := sqlf.Sprintf(countVirtualReposQueryFmtstr, sqlf.Join(opts.sqlConds(), "AND"))
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	return count, err
+}
+
+const countVirtualReposQueryFmtstr = `
+-- source: internal/database/virtual_repos.go:virtualReposStore.Count
+SELECT COUNT(*)
+FROM virtual_repos vr
+JOIN repo ON repo.id = vr.repo_id
+WHERE
+	repo.deleted_at IS NULL AND
+	repo.blocked IS NULL AND
+	%s
+`
+
+func (o VirtualReposListOptions) sqlConds() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if len(o.RepoIDs) > 0 {
+		ids := make([]*sqlf.Query, 0, len(o.RepoIDs))
+		for _, id := range o.RepoIDs {
+			ids = append(ids, sqlf.Sprintf("%s", id))
+		}
+		conds = append(conds, sqlf.Sprintf("vr.repo_id IN (%s)", sqlf.Join(ids, ",")))
+	}
+	if len(o.Names) > 0 {
+		names := make([]*sqlf.Query, 0, len(o.Names))
+		for _, name := range o.Names {
+			names = append(names, sqlf.Sprintf("%s", name))
+		}
+		conds = append(conds, sqlf.Sprintf("vr.name IN (%s)", sqlf.Join(names, ",")))
+	}
+	if o.Query != "" {
+		conds = append(conds, sqlf.Sprintf("lower(vr.name) LIKE %s", "%"+strings.ToLower(o.Query)+"%"))
+	}
+	return conds
+}
+
+var (
+	scanVirtualRepos     = basestore.NewSliceScanner(scanVirtualRepo)
+	scanFirstVirtualRepo = basestore.NewFirstScanner(scanVirtualRepo)
+)
+
+func scanVirtualRepo(sc dbutil.Scanner) (*types.VirtualRepo, error) {
+	var r types.VirtualRepo
+	err := sc.Scan(
+		&r.ID,
+		&r.Name,
+		&r.Parent.ID,
+		&r.Parent.Name,
+		&r.Parent.Stars,
+		&r.PathPrefix,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+	return &r, err
+}
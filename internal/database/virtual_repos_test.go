@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestVirtualRepos(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(logger, t))
+	ctx := context.Background()
+	s := db.VirtualRepos()
+
+	repo, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/monorepo"})
+
+	payments := &types.VirtualRepo{
+		Name:       "github.com/sourcegraph/payments",
+		Parent:     types.MinimalRepo{ID: repo.ID},
+		PathPrefix: "/services/payments/",
+	}
+	require.NoError(t, s.Create(ctx, payments))
+	assert.Equal(t, "services/payments", payments.PathPrefix)
+	assert.Equal(t, repo.Name, payments.Parent.Name)
+
+	billing := &types.VirtualRepo{
+		Name:       "github.com/sourcegraph/billing",
+		Parent:     types.MinimalRepo{ID: repo.ID},
+		PathPrefix: "services/billing",
+	}
+	require.NoError(t, s.Create(ctx, billing))
+
+	t.Run("Create rejects empty path prefix", func(t *testing.T) {
+		err := s.Create(ctx, &types.VirtualRepo{Name: "github.com/sourcegraph/root", Parent: types.MinimalRepo{ID: repo.ID}, PathPrefix: "/"})
+		assert.Error(t, err)
+	})
+
+	t.Run("GetByName", func(t *testing.T) {
+		vr, err := s.GetByName(ctx, payments.Name)
+		require.NoError(t, err)
+		assert.Equal(t, payments.ID, vr.ID)
+		assert.Equal(t, repo.ID, vr.Parent.ID)
+		assert.Equal(t, "services/payments", vr.PathPrefix)
+
+		_, err = s.GetByName(ctx, "github.com/sourcegraph/unknown")
+		assert.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		vrs, err := s.List(ctx, VirtualReposListOptions{RepoIDs: []api.RepoID{repo.ID}})
+		require.NoError(t, err)
+		require.Len(t, vrs, 2)
+		assert.Equal(t, billing.Name, vrs[0].Name)
+		assert.Equal(t, payments.Name, vrs[1].Name)
+
+		count, err := s.Count(ctx, VirtualReposListOptions{Names: []api.RepoName{payments.Name}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = s.Count(ctx, VirtualReposListOptions{Query: "PAYMENTS"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		repos, err := db.Repos().List(ctx, ReposListOptions{Query: "payments", IncludeVirtualRepos: true})
+		require.NoError(t, err)
+		require.Len(t, repos, 1)
+		assert.Equal(t, repo.ID, repos[0].ID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, billing.ID))
+		_, err := s.GetByID(ctx, billing.ID)
+		assert.True(t, errcode.IsNotFound(err))
+
+		err = s.Delete(ctx, billing.ID)
+		assert.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("deleted parents are hidden", func(t *testing.T) {
+		require.NoError(t, db.Repos().Delete(ctx, repo.ID))
+		_, err := s.GetByName(ctx, payments.Name)
+		assert.True(t, errcode.IsNotFound(err))
+	})
+}
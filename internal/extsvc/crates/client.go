@@ -5,11 +5,25 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 )
 
+// DefaultRegistryURL is the URL from which crates are downloaded if no registry
+// is configured.
+const DefaultRegistryURL = "https://static.crates.io/crates"
+
+// RegistryURL returns the configured registry URL, or DefaultRegistryURL if
+// none is configured.
+func RegistryURL(registry string) string {
+	if registry == "" {
+		return DefaultRegistryURL
+	}
+	return strings.TrimSuffix(registry, "/")
+}
+
 type Client struct {
 	cli httpcli.Doer
 
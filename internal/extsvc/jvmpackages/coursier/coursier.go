@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path"
@@ -167,9 +168,23 @@ func runCoursierCommand(ctx context.Context, config *schema.JVMPackagesConnectio
 	}})
 	defer endObservation(1, observation.Args{})
 
+	if config.Maven.Certificate != "" {
+		// Coursier does its own HTTP requests, so the certificate is passed to it as
+		// a trust store rather than through an httpcli client.
+		dir := coursierCacheDir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "coursier")
+		}
+		trustStoreArgs, err := trustStoreArgs(dir, config.Maven.Certificate)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to write trust store for maven.certificate")
+		}
+		args = append(trustStoreArgs, args...)
+	}
+
 	cmd := exec.CommandContext(ctx, CoursierBinary, args...)
-	if config.Maven.Credentials != "" {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("COURSIER_CREDENTIALS=%v", config.Maven.Credentials))
+	if credentials := coursierCredentials(config.Maven.Credentials, config.Maven.Repositories); credentials != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("COURSIER_CREDENTIALS=%v", credentials))
 	}
 	if len(config.Maven.Repositories) > 0 {
 		cmd.Env = append(
@@ -195,3 +210,22 @@ func runCoursierCommand(ctx context.Context, config *schema.JVMPackagesConnectio
 
 	return strings.Split(strings.TrimSpace(stdout.String()), "\n"), nil
 }
+
+// coursierCredentials returns the given credentials in the format coursier
+// expects. Credentials of the form "username:password", like those of other
+// package hosts, apply to the hosts of all configured repositories. Anything
+// else is already in the coursier credentials format and returned unchanged.
+func coursierCredentials(credentials string, repositories []string) string {
+	credentials = strings.TrimSpace(credentials)
+	if credentials == "" || strings.ContainsAny(credentials, " \t\n=") || !strings.Contains(credentials, ":") {
+		return credentials
+	}
+
+	var lines []string
+	for _, repository := range repositories {
+		if u, err := url.Parse(repository); err == nil && u.Host != "" {
+			lines = append(lines, u.Host+" "+credentials)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,22 @@
+package coursier
+
+import (
+	"testing"
+)
+
+func TestCoursierCredentials(t *testing.T) {
+	repositories := []string{"https://artifactory.example.com/maven", "central", "https://nexus.example.com:8443"}
+
+	testCases := map[string]string{
+		"":                "",
+		"alice:secret":    "artifactory.example.com alice:secret\nnexus.example.com:8443 alice:secret",
+		"token":           "token",
+		"example.com a:b": "example.com a:b",
+		"example.com.username=a\nexample.com.password=b": "example.com.username=a\nexample.com.password=b",
+	}
+	for credentials, want := range testCases {
+		if have := coursierCredentials(credentials, repositories); have != want {
+			t.Errorf("unexpected credentials for %q. want=%q have=%q", credentials, want, have)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package coursier
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// trustStorePassword protects the integrity of the trust stores written by
+// writeTrustStore. They only contain public certificates, so it is not a secret.
+const trustStorePassword = "changeit"
+
+// systemCertFiles are the CA bundles of common Linux distributions. The first
+// one that exists is added to each trust store, since a trust store replaces
+// the default CA certificates of the JVM.
+var systemCertFiles = []string{
+	"/etc/ssl/certs/ca-certificates.crt", // Debian, Ubuntu, Alpine
+	"/etc/pki/tls/certs/ca-bundle.crt",   // Fedora, RHEL
+	"/etc/ssl/ca-bundle.pem",             // OpenSUSE
+	"/etc/ssl/cert.pem",                  // Alpine, macOS
+}
+
+// trustStoreArgs returns the JVM system properties that make coursier trust
+// the given PEM certificates in addition to the system CA certificates. The
+// trust store is written to dir once per distinct set of certificates.
+func trustStoreArgs(dir, certificates string) ([]string, error) {
+	sum := sha256.Sum256([]byte(certificates))
+	path := filepath.Join(dir, "truststore-"+hex.EncodeToString(sum[:8])+".jks")
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		system, err := readSystemCerts()
+		if err != nil {
+			return nil, err
+		}
+		if err := writeTrustStore(path, system+"\n"+certificates); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"-Djavax.net.ssl.trustStore=" + path,
+		"-Djavax.net.ssl.trustStoreType=JKS",
+		"-Djavax.net.ssl.trustStorePassword=" + trustStorePassword,
+	}, nil
+}
+
+func readSystemCerts() (string, error) {
+	if file := os.Getenv("SSL_CERT_FILE"); file != "" {
+		contents, err := os.ReadFile(file)
+		return string(contents), err
+	}
+	for _, file := range systemCertFiles {
+		contents, err := os.ReadFile(file)
+		if err == nil {
+			return string(contents), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// writeTrustStore writes the certificates of the given PEM data to a Java
+// KeyStore (JKS) file at path, as trusted certificate entries. The file is
+// written to a temporary file first, so concurrent coursier invocations never
+// read a partial trust store.
+func writeTrustStore(path, certificates string) error {
+	contents, err := encodeTrustStore(certificates, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// encodeTrustStore encodes the certificates of the given PEM data in the JKS
+// format: a header, one trusted certificate entry per certificate, and a SHA-1
+// digest of the password, a fixed salt and all preceding bytes.
+func encodeTrustStore(certificates string, now time.Time) ([]byte, error) {
+	var ders [][]byte
+	rest := []byte(certificates)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 && strings.TrimSpace(certificates) != "" {
+		return nil, errors.New("no PEM certificates found in maven.certificate")
+	}
+
+	var buf bytes.Buffer
+	write := func(v any) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	writeUTF := func(s string) {
+		write(uint16(len(s)))
+		buf.WriteString(s)
+	}
+
+	write(uint32(0xfeedfeed)) // magic
+	write(uint32(2))          // version
+	write(uint32(len(ders)))
+	for i, der := range ders {
+		write(uint32(2)) // trusted certificate entry
+		writeUTF(fmt.Sprintf("cert-%d", i))
+		write(uint64(now.UnixMilli()))
+		writeUTF("X.509")
+		write(uint32(len(der)))
+		buf.Write(der)
+	}
+
+	digest := sha1.New()
+	for _, c := range utf16.Encode([]rune(trustStorePassword)) {
+		_ = binary.Write(digest, binary.BigEndian, c)
+	}
+	digest.Write([]byte("Mighty Aphrodite"))
+	digest.Write(buf.Bytes())
+	buf.Write(digest.Sum(nil))
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+package coursier
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeTrustStore(t *testing.T) {
+	first, second := generateCertificate(t, "first"), generateCertificate(t, "second")
+	contents, err := encodeTrustStore(string(pemEncode(first))+"\n"+string(pemEncode(second)), time.Unix(1, 0))
+	require.NoError(t, err)
+
+	// The digest covers the password, a fixed salt and the keystore
+	data, digest := contents[:len(contents)-sha1.Size], contents[len(contents)-sha1.Size:]
+	h := sha1.New()
+	for _, c := range utf16.Encode([]rune(trustStorePassword)) {
+		require.NoError(t, binary.Write(h, binary.BigEndian, c))
+	}
+	h.Write([]byte("Mighty Aphrodite"))
+	h.Write(data)
+	require.Equal(t, h.Sum(nil), digest)
+
+	r := bytes.NewReader(data)
+	readUint32 := func() uint32 {
+		var v uint32
+		require.NoError(t, binary.Read(r, binary.BigEndian, &v))
+		return v
+	}
+	readUTF := func() string {
+		var n uint16
+		require.NoError(t, binary.Read(r, binary.BigEndian, &n))
+		b := make([]byte, n)
+		_, err := r.Read(b)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	require.Equal(t, uint32(0xfeedfeed), readUint32())
+	require.Equal(t, uint32(2), readUint32())
+	require.Equal(t, uint32(2), readUint32())
+	for _, want := range [][]byte{first, second} {
+		require.Equal(t, uint32(2), readUint32())
+		readUTF()
+		var timestamp uint64
+		require.NoError(t, binary.Read(r, binary.BigEndian, &timestamp))
+		require.Equal(t, uint64(1000), timestamp)
+		require.Equal(t, "X.509", readUTF())
+		der := make([]byte, readUint32())
+		_, err := r.Read(der)
+		require.NoError(t, err)
+		require.Equal(t, want, der)
+	}
+	require.Zero(t, r.Len())
+
+	_, err = encodeTrustStore("not a certificate", time.Now())
+	require.Error(t, err)
+}
+
+func TestTrustStoreArgs(t *testing.T) {
+	systemCerts := filepath.Join(t.TempDir(), "ca-certificates.crt")
+	require.NoError(t, os.WriteFile(systemCerts, pemEncode(generateCertificate(t, "system")), 0o644))
+	t.Setenv("SSL_CERT_FILE", systemCerts)
+
+	dir := t.TempDir()
+	certificate := string(pemEncode(generateCertificate(t, "internal")))
+	args, err := trustStoreArgs(dir, certificate)
+	require.NoError(t, err)
+	require.Len(t, args, 3)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "truststore-*.jks"))
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	require.Equal(t, "-Djavax.net.ssl.trustStore="+paths[0], args[0])
+
+	// The trust store holds the system and the configured certificate
+	contents, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), binary.BigEndian.Uint32(contents[8:12]))
+
+	// The same certificate reuses the trust store
+	again, err := trustStoreArgs(dir, certificate)
+	require.NoError(t, err)
+	require.Equal(t, args, again)
+}
+
+func generateCertificate(t *testing.T, name string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
+
+func pemEncode(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,196 @@
+// Package nuget
+//
+// A client for the NuGet V3 API as described in
+// https://learn.microsoft.com/en-us/nuget/api/overview.
+//
+// A NuGet package source is described by its service index, a JSON document
+// listing the resources the source provides. The client only relies on the
+// PackageBaseAddress resource, which serves the list of versions of a package
+// and the .nupkg archives themselves.
+//
+// Package IDs and versions are case-insensitive and the PackageBaseAddress
+// resource requires both to be lowercased.
+package nuget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultRegistryURL is the service index of nuget.org, which is used if no
+// registry is configured.
+const DefaultRegistryURL = "https://api.nuget.org/v3/index.json"
+
+// RegistryURL returns the configured service index URL, or DefaultRegistryURL
+// if none is configured.
+func RegistryURL(registry string) string {
+	if registry == "" {
+		return DefaultRegistryURL
+	}
+	return registry
+}
+
+type Client struct {
+	// The URL of the service index of the package source, for example
+	// https://api.nuget.org/v3/index.json.
+	registryURL string
+
+	// credentials are sent to the host of the service index and to the host
+	// of its PackageBaseAddress resource, which may differ.
+	credentials string
+
+	cli httpcli.Doer
+
+	// Self-imposed rate-limiter.
+	limiter *ratelimit.InstrumentedLimiter
+
+	mu sync.Mutex
+	// baseAddress is the PackageBaseAddress resource of the service index,
+	// resolved on first use.
+	baseAddress string
+}
+
+func NewClient(urn string, registryURL, credentials string, cli httpcli.Doer) *Client {
+	return &Client{
+		registryURL: RegistryURL(registryURL),
+		credentials: credentials,
+		cli:         cli,
+		limiter:     ratelimit.DefaultRegistry.Get(urn),
+	}
+}
+
+// Versions returns all versions of the package with the given ID, in the order
+// returned by the package source.
+func (c *Client) Versions(ctx context.Context, name reposource.PackageName) ([]string, error) {
+	baseAddress, err := c.packageBaseAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strings.ToLower(string(name))
+	body, err := c.get(ctx, fmt.Sprintf("%s%s/index.json", baseAddress, id))
+	if err != nil {
+		return nil, errors.Wrap(err, "NuGet")
+	}
+	defer body.Close()
+
+	var index struct {
+		Versions []string `json:"versions"`
+	}
+	if err := json.NewDecoder(body).Decode(&index); err != nil {
+		return nil, errors.Wrapf(err, "decoding versions of NuGet package %q", name)
+	}
+	return index.Versions, nil
+}
+
+// GetPackageContents returns the .nupkg archive of the given package version,
+// along with the URL it was downloaded from.
+func (c *Client) GetPackageContents(ctx context.Context, dep reposource.VersionedPackage) (body io.ReadCloser, url string, err error) {
+	baseAddress, err := c.packageBaseAddress(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	id := strings.ToLower(string(dep.PackageSyntax()))
+	version := strings.ToLower(dep.PackageVersion())
+	url = fmt.Sprintf("%s%s/%s/%s.%s.nupkg", baseAddress, id, version, id, version)
+
+	body, err = c.get(ctx, url)
+	if err != nil {
+		return nil, url, errors.Wrap(err, "NuGet")
+	}
+	return body, url, nil
+}
+
+// packageBaseAddress returns the URL of the PackageBaseAddress resource of the
+// service index, with a trailing slash.
+func (c *Client) packageBaseAddress(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.baseAddress != "" {
+		return c.baseAddress, nil
+	}
+
+	body, err := c.get(ctx, c.registryURL)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching NuGet service index")
+	}
+	defer body.Close()
+
+	var index struct {
+		Resources []struct {
+			ID   string `json:"@id"`
+			Type string `json:"@type"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(body).Decode(&index); err != nil {
+		return "", errors.Wrap(err, "decoding NuGet service index")
+	}
+
+	for _, r := range index.Resources {
+		if r.Type == "PackageBaseAddress/3.0.0" {
+			c.baseAddress = strings.TrimSuffix(r.ID, "/") + "/"
+			return c.baseAddress, nil
+		}
+	}
+	return "", errors.Newf("NuGet service index %s has no PackageBaseAddress/3.0.0 resource", c.registryURL)
+}
+
+func (c *Client) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", "sourcegraph-nuget-syncer (sourcegraph.com)")
+
+	return c.do(req)
+}
+
+type Error struct {
+	path    string
+	code    int
+	message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bad response with status code %d for %s: %s", e.code, e.path, e.message)
+}
+
+func (e *Error) NotFound() bool {
+	return e.code == http.StatusNotFound
+}
+
+func (c *Client) do(req *http.Request) (io.ReadCloser, error) {
+	// The base address is only written once, while resolving it, before any
+	// request to it is made.
+	cli := httpcli.NewCredentialsMiddleware(c.credentials, c.registryURL, c.baseAddress)(c.cli)
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		bs, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			bs = []byte(errors.Wrap(err, "failed to read body").Error())
+		}
+		return nil, &Error{path: req.URL.Path, code: resp.StatusCode, message: string(bs)}
+	}
+	return resp.Body, nil
+}
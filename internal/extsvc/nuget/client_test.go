@@ -0,0 +1,113 @@
+package nuget
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/index.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+  "version": "3.0.0",
+  "resources": [
+    {"@id": "%[1]s/query", "@type": "SearchQueryService"},
+    {"@id": "%[1]s/v3-flatcontainer", "@type": "PackageBaseAddress/3.0.0"}
+  ]
+}`, srv.URL)
+	})
+	mux.HandleFunc("/v3-flatcontainer/newtonsoft.json/index.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versions": ["12.0.3", "13.0.1"]}`)
+	})
+	mux.HandleFunc("/v3-flatcontainer/newtonsoft.json/13.0.1/newtonsoft.json.13.0.1.nupkg", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "nupkg")
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	client := NewClient("urn", srv.URL+"/v3/index.json", "", httpcli.ExternalDoer)
+
+	t.Run("Versions", func(t *testing.T) {
+		versions, err := client.Versions(ctx, "Newtonsoft.Json")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"12.0.3", "13.0.1"}, versions)
+
+		_, err = client.Versions(ctx, "Unknown")
+		assert.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("GetPackageContents", func(t *testing.T) {
+		dep := reposource.NewNuGetVersionedPackage("Newtonsoft.Json", "13.0.1")
+		body, url, err := client.GetPackageContents(ctx, dep)
+		require.NoError(t, err)
+		defer body.Close()
+
+		assert.Equal(t, srv.URL+"/v3-flatcontainer/newtonsoft.json/13.0.1/newtonsoft.json.13.0.1.nupkg", url)
+		b, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "nupkg", string(b))
+	})
+}
+
+func TestClient_MissingPackageBaseAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version": "3.0.0", "resources": []}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("urn", srv.URL, "", httpcli.ExternalDoer)
+	_, err := client.Versions(context.Background(), "Newtonsoft.Json")
+	assert.ErrorContains(t, err, "no PackageBaseAddress/3.0.0 resource")
+}
+
+func TestClient_Credentials(t *testing.T) {
+	authorized := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+
+	// The package contents are served from a different host than the
+	// service index, as is common for private feeds.
+	contents := httptest.NewServer(authorized(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versions": ["13.0.1"]}`)
+	}))
+	t.Cleanup(contents.Close)
+
+	index := httptest.NewServer(authorized(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"version": "3.0.0", "resources": [{"@id": "%s/v3-flatcontainer", "@type": "PackageBaseAddress/3.0.0"}]}`, contents.URL)
+	}))
+	t.Cleanup(index.Close)
+
+	client := NewClient("urn", index.URL+"/v3/index.json", "user:pass", httpcli.ExternalDoer)
+	versions, err := client.Versions(context.Background(), "Newtonsoft.Json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"13.0.1"}, versions)
+
+	client = NewClient("urn", index.URL+"/v3/index.json", "", httpcli.ExternalDoer)
+	_, err = client.Versions(context.Background(), "Newtonsoft.Json")
+	assert.ErrorContains(t, err, "status code 401")
+}
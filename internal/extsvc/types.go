@@ -126,6 +126,7 @@ const (
 	KindPythonPackages  = "PYTHONPACKAGES"
 	KindRustPackages    = "RUSTPACKAGES"
 	KindRubyPackages    = "RUBYPACKAGES"
+	KindNuGetPackages   = "NUGETPACKAGES"
 	KindNpmPackages     = "NPMPACKAGES"
 	KindPagure          = "PAGURE"
 	KindOther           = "OTHER"
@@ -188,6 +189,9 @@ const (
 	// TypeRubyPackages is the (api.ExternalRepoSpec).ServiceType value for Ruby packages.
 	TypeRubyPackages = "rubyPackages"
 
+	// TypeNuGetPackages is the (api.ExternalRepoSpec).ServiceType value for NuGet packages (.NET ecosystem libraries).
+	TypeNuGetPackages = "nugetPackages"
+
 	// TypeOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	TypeOther = "other"
 )
@@ -222,6 +226,8 @@ func KindToType(kind string) string {
 		return TypeRustPackages
 	case KindRubyPackages:
 		return TypeRubyPackages
+	case KindNuGetPackages:
+		return TypeNuGetPackages
 	case KindNpmPackages:
 		return TypeNpmPackages
 	case KindGoPackages:
@@ -267,6 +273,8 @@ func TypeToKind(t string) string {
 		return KindRustPackages
 	case TypeRubyPackages:
 		return KindRubyPackages
+	case TypeNuGetPackages:
+		return KindNuGetPackages
 	case TypeGoModules:
 		return KindGoPackages
 	case TypePagure:
@@ -288,6 +296,7 @@ var (
 	pythonLower = strings.ToLower(TypePythonPackages)
 	rustLower   = strings.ToLower(TypeRustPackages)
 	rubyLower   = strings.ToLower(TypeRubyPackages)
+	nugetLower  = strings.ToLower(TypeNuGetPackages)
 )
 
 // ParseServiceType will return a ServiceType constant after doing a case insensitive match on s.
@@ -324,6 +333,8 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeRustPackages, true
 	case rubyLower:
 		return TypeRubyPackages, true
+	case nugetLower:
+		return TypeNuGetPackages, true
 	case TypePagure:
 		return TypePagure, true
 	case TypeOther:
@@ -365,6 +376,8 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindRustPackages, true
 	case KindRubyPackages:
 		return KindRubyPackages, true
+	case KindNuGetPackages:
+		return KindNuGetPackages, true
 	case KindPagure:
 		return KindPagure, true
 	case KindOther:
@@ -448,6 +461,8 @@ func getConfigPrototype(kind string) (any, error) {
 		return &schema.RustPackagesConnection{}, nil
 	case KindRubyPackages:
 		return &schema.RubyPackagesConnection{}, nil
+	case KindNuGetPackages:
+		return &schema.NuGetPackagesConnection{}, nil
 	case KindOther:
 		return &schema.OtherExternalServiceConnection{}, nil
 	default:
@@ -634,6 +649,12 @@ func GetLimitFromConfig(kind string, config any) (rate.Limit, error) {
 		if c != nil && c.RateLimit != nil {
 			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
 		}
+	case *schema.NuGetPackagesConnection:
+		// The nuget.org API doesn't document an enforced req/s rate limit.
+		limit = rate.Limit(57600.0 / 3600.0) // 16/second same as default in nuget-packages.schema.json
+		if c != nil && c.RateLimit != nil {
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
 	default:
 		return limit, ErrRateLimitUnsupported{codehostKind: kind}
 	}
@@ -756,6 +777,8 @@ func uniqueCodeHostIdentifier(kind string, cfg any) (string, error) {
 		return KindRustPackages, nil
 	case *schema.RubyPackagesConnection:
 		return KindRubyPackages, nil
+	case *schema.NuGetPackagesConnection:
+		return KindNuGetPackages, nil
 	case *schema.PagureConnection:
 		rawURL = c.Url
 	default:
@@ -283,6 +283,37 @@ func GerritUnauthenticateMiddleware(cli Doer) Doer {
 	})
 }
 
+// NewCredentialsMiddleware returns a middleware that authenticates requests to
+// the hosts of the given URLs. Credentials of the form "username:password" are
+// sent using HTTP basic authentication, any other credentials are sent as a
+// bearer token. Requests to other hosts, such as storage backends a package
+// host redirects to, and requests that already carry an Authorization header
+// are left unchanged.
+func NewCredentialsMiddleware(credentials string, urls ...string) Middleware {
+	hosts := make(map[string]struct{}, len(urls))
+	for _, rawURL := range urls {
+		if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+			hosts[u.Host] = struct{}{}
+		}
+	}
+
+	return func(cli Doer) Doer {
+		if credentials == "" {
+			return cli
+		}
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if _, ok := hosts[req.URL.Host]; ok && req.Header.Get("Authorization") == "" {
+				if username, password, ok := strings.Cut(credentials, ":"); ok {
+					req.SetBasicAuth(username, password)
+				} else {
+					req.Header.Set("Authorization", "Bearer "+credentials)
+				}
+			}
+			return cli.Do(req)
+		})
+	}
+}
+
 // requestContextKey is used to denote keys to fields that should be logged by the logging
 // middleware. They should be set to the request context associated with a response.
 type requestContextKey int
@@ -139,6 +139,64 @@ func TestContextErrorMiddleware(t *testing.T) {
 	}
 }
 
+func TestCredentialsMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		credentials string
+		url         string
+		header      string
+		want        string
+	}{
+		{
+			name:        "basic auth",
+			credentials: "admin:secret",
+			url:         "https://registry.example.com/simple/numpy/",
+			want:        "Basic YWRtaW46c2VjcmV0",
+		},
+		{
+			name:        "bearer token",
+			credentials: "token",
+			url:         "https://registry.example.com/simple/numpy/",
+			want:        "Bearer token",
+		},
+		{
+			name:        "other host",
+			credentials: "token",
+			url:         "https://storage.example.com/numpy.tar.gz",
+			want:        "",
+		},
+		{
+			name:        "existing header",
+			credentials: "token",
+			url:         "https://registry.example.com/simple/numpy/",
+			header:      "Bearer other",
+			want:        "Bearer other",
+		},
+		{
+			name: "no credentials",
+			url:  "https://registry.example.com/simple/numpy/",
+			want: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := NewCredentialsMiddleware(tc.credentials, "https://registry.example.com/simple")(
+				DoerFunc(func(r *http.Request) (*http.Response, error) {
+					if have := r.Header.Get("Authorization"); have != tc.want {
+						t.Errorf("Authorization header: have %q, want %q", have, tc.want)
+					}
+					return nil, nil
+				}),
+			)
+
+			req, _ := http.NewRequest("GET", tc.url, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			_, _ = cli.Do(req)
+		})
+	}
+}
+
 func genCert(subject string) (string, error) {
 	priv, err := rsa.GenerateKey(rand.Reader, 1024)
 	if err != nil {
@@ -25,7 +25,7 @@ func NewGoPackagesSource(ctx context.Context, svc *types.ExternalService, cf *ht
 		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
 	}
 
-	cli, err := cf.Doer()
+	cli, err := NewPackagesDoer(cf, c.Certificate, c.Credentials, c.Urls...)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +54,9 @@ type MockStore struct {
 	// ListSyncJobsFunc is an instance of a mock function object controlling
 	// the behavior of the method ListSyncJobs.
 	ListSyncJobsFunc *StoreListSyncJobsFunc
+	// RepoNameHistoryStoreFunc is an instance of a mock function object
+	// controlling the behavior of the method RepoNameHistoryStore.
+	RepoNameHistoryStoreFunc *StoreRepoNameHistoryStoreFunc
 	// RepoStoreFunc is an instance of a mock function object controlling
 	// the behavior of the method RepoStore.
 	RepoStoreFunc *StoreRepoStoreFunc
@@ -132,6 +135,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		RepoNameHistoryStoreFunc: &StoreRepoNameHistoryStoreFunc{
+			defaultHook: func() (r0 database.RepoNameHistoryStore) {
+				return
+			},
+		},
 		RepoStoreFunc: &StoreRepoStoreFunc{
 			defaultHook: func() (r0 database.RepoStore) {
 				return
@@ -224,6 +232,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.ListSyncJobs")
 			},
 		},
+		RepoNameHistoryStoreFunc: &StoreRepoNameHistoryStoreFunc{
+			defaultHook: func() database.RepoNameHistoryStore {
+				panic("unexpected invocation of MockStore.RepoNameHistoryStore")
+			},
+		},
 		RepoStoreFunc: &StoreRepoStoreFunc{
 			defaultHook: func() database.RepoStore {
 				panic("unexpected invocation of MockStore.RepoStore")
@@ -296,6 +309,9 @@ func NewMockStoreFrom(i Store) *MockStore {
 		ListSyncJobsFunc: &StoreListSyncJobsFunc{
 			defaultHook: i.ListSyncJobs,
 		},
+		RepoNameHistoryStoreFunc: &StoreRepoNameHistoryStoreFunc{
+			defaultHook: i.RepoNameHistoryStore,
+		},
 		RepoStoreFunc: &StoreRepoStoreFunc{
 			defaultHook: i.RepoStore,
 		},
@@ -1366,6 +1382,105 @@ func (c StoreListSyncJobsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreRepoNameHistoryStoreFunc describes the behavior when the
+// RepoNameHistoryStore method of the parent MockStore instance is invoked.
+type StoreRepoNameHistoryStoreFunc struct {
+	defaultHook func() database.RepoNameHistoryStore
+	hooks       []func() database.RepoNameHistoryStore
+	history     []StoreRepoNameHistoryStoreFuncCall
+	mutex       sync.Mutex
+}
+
+// RepoNameHistoryStore delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) RepoNameHistoryStore() database.RepoNameHistoryStore {
+	r0 := m.RepoNameHistoryStoreFunc.nextHook()()
+	m.RepoNameHistoryStoreFunc.appendCall(StoreRepoNameHistoryStoreFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the RepoNameHistoryStore
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreRepoNameHistoryStoreFunc) SetDefaultHook(hook func() database.RepoNameHistoryStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RepoNameHistoryStore method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreRepoNameHistoryStoreFunc) PushHook(hook func() database.RepoNameHistoryStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreRepoNameHistoryStoreFunc) SetDefaultReturn(r0 database.RepoNameHistoryStore) {
+	f.SetDefaultHook(func() database.RepoNameHistoryStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreRepoNameHistoryStoreFunc) PushReturn(r0 database.RepoNameHistoryStore) {
+	f.PushHook(func() database.RepoNameHistoryStore {
+		return r0
+	})
+}
+
+func (f *StoreRepoNameHistoryStoreFunc) nextHook() func() database.RepoNameHistoryStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreRepoNameHistoryStoreFunc) appendCall(r0 StoreRepoNameHistoryStoreFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreRepoNameHistoryStoreFuncCall objects
+// describing the invocations of this function.
+func (f *StoreRepoNameHistoryStoreFunc) History() []StoreRepoNameHistoryStoreFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreRepoNameHistoryStoreFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreRepoNameHistoryStoreFuncCall is an object that describes an
+// invocation of method RepoNameHistoryStore on an instance of MockStore.
+type StoreRepoNameHistoryStoreFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.RepoNameHistoryStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreRepoNameHistoryStoreFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreRepoNameHistoryStoreFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // StoreRepoStoreFunc describes the behavior when the RepoStore method of
 // the parent MockStore instance is invoked.
 type StoreRepoStoreFunc struct {
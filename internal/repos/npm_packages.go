@@ -27,7 +27,7 @@ func NewNpmPackagesSource(ctx context.Context, svc *types.ExternalService, cf *h
 		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
 	}
 
-	cli, err := cf.Doer()
+	cli, err := NewPackagesDoer(cf, c.Certificate, "", c.Registry)
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -46,6 +47,22 @@ type packagesDownloadSource interface {
 
 var _ Source = &PackagesSource{}
 
+// NewPackagesDoer returns a Doer for requests to the package host at the given
+// URLs. The TLS certificate and credentials of the connection are optional,
+// credentials are only sent to the hosts of urls.
+func NewPackagesDoer(cf *httpcli.Factory, certificate, credentials string, urls ...string) (httpcli.Doer, error) {
+	var opts []httpcli.Opt
+	if certificate != "" {
+		opts = append(opts, httpcli.NewCertPoolOpt(certificate))
+	}
+
+	cli, err := cf.Doer(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return httpcli.NewCredentialsMiddleware(credentials, urls...)(cli), nil
+}
+
 func (s *PackagesSource) ListRepos(ctx context.Context, results chan SourceResult) {
 	deps, err := s.configDependencies(s.configDeps)
 	if err != nil {
@@ -25,7 +25,7 @@ func NewRustPackagesSource(ctx context.Context, svc *types.ExternalService, cf *
 		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
 	}
 
-	cli, err := cf.Doer()
+	cli, err := NewPackagesDoer(cf, c.Certificate, c.Credentials, crates.RegistryURL(c.Registry))
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +32,9 @@ type Store interface {
 	// ExternalServiceStore returns a database.ExternalServiceStore using the same
 	// database handle.
 	ExternalServiceStore() database.ExternalServiceStore
+	// RepoNameHistoryStore returns a database.RepoNameHistoryStore using the
+	// same database handle.
+	RepoNameHistoryStore() database.RepoNameHistoryStore
 
 	// SetMetrics updates metrics for the store in place.
 	SetMetrics(m StoreMetrics)
@@ -128,6 +131,10 @@ func (s *store) ExternalServiceStore() database.ExternalServiceStore {
 	return database.ExternalServicesWith(s.Logger, s)
 }
 
+func (s *store) RepoNameHistoryStore() database.RepoNameHistoryStore {
+	return database.RepoNameHistoryWith(s)
+}
+
 func (s *store) SetMetrics(m StoreMetrics) { s.Metrics = m }
 func (s *store) SetTracer(t trace.Tracer)  { s.Tracer = t }
 
@@ -293,7 +300,7 @@ func (s *store) DeleteExternalServiceRepo(ctx context.Context, svc *types.Extern
 		defer func() { err = s.Done(err) }()
 	}
 
-	err = s.Exec(ctx, sqlf.Sprintf(deleteExternalServiceRepoQuery, svc.ID, id))
+	err = s.Exec(ctx, sqlf.Sprintf(deleteExternalServiceRepoQuery, svc.ID, id, svc.ID, database.RepoNameHistoryDeleted))
 	if err != nil {
 		return errors.Wrap(err, "failed to delete external service repo")
 	}
@@ -306,9 +313,19 @@ func (s *store) DeleteExternalServiceRepo(ctx context.Context, svc *types.Extern
 	return nil
 }
 
+// deleteExternalServiceRepoQuery removes the repo from the external service and
+// records the deletion for that external service, whether or not the repo is
+// still associated with other external services.
 const deleteExternalServiceRepoQuery = `
-DELETE FROM external_service_repos
-WHERE external_service_id = %s AND repo_id = %s
+WITH deleted AS (
+	DELETE FROM external_service_repos
+	WHERE external_service_id = %s AND repo_id = %s
+	RETURNING repo_id
+)
+INSERT INTO repo_name_history (repo_id, external_service_id, event, from_name)
+SELECT r.id, %s, %s, r.name
+FROM repo r
+JOIN deleted d ON d.repo_id = r.id
 `
 
 const deleteRepoIfOrphanQuery = `
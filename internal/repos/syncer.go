@@ -567,16 +567,6 @@ func (s *Syncer) SyncExternalService(
 
 	seen := make(map[api.RepoID]struct{})
 	var errs error
-	fatal := func(err error) bool {
-		// If the error is just a warning, then it is not fatal.
-		if errors.IsWarning(err) && !errcode.IsAccountSuspended(err) {
-			return false
-		}
-
-		return errcode.IsUnauthorized(err) ||
-			errcode.IsForbidden(err) ||
-			errcode.IsAccountSuspended(err)
-	}
 
 	logger = s.Logger.With(log.Object("svc", log.String("name", svc.DisplayName), log.Int64("id", svc.ID)))
 
@@ -604,7 +594,7 @@ func (s *Syncer) SyncExternalService(
 			logger.Error("error from codehost", log.Int("seen", len(seen)), log.Error(err))
 
 			errs = errors.Append(errs, errors.Wrapf(err, "fetching from code host %s", svc.DisplayName))
-			if fatal(err) {
+			if isFatalSourceError(err) {
 				// Delete all external service repos of this external service
 				logger.Error("stopping external service sync due to fatal error from codehost", log.Error(err))
 				seen = map[api.RepoID]struct{}{}
@@ -665,29 +655,7 @@ func (s *Syncer) SyncExternalService(
 	// It's preferable to have them fix any invalidated token manually rather than deleting the repos automatically.
 	deleted := 0
 
-	// If all of our errors are warnings and either Forbidden or Unauthorized,
-	// we want to proceed with the deletion. This is to be able to properly sync
-	// repos (by removing ones if code-host permissions have changed).
-	abortDeletion := false
-	if errs != nil {
-		var ref errors.MultiError
-		if errors.As(errs, &ref) {
-			for _, e := range ref.Errors() {
-				if errors.IsWarning(e) {
-					baseError := errors.Unwrap(e)
-					if !errcode.IsForbidden(baseError) && !errcode.IsUnauthorized(baseError) {
-						abortDeletion = true
-						break
-					}
-					continue
-				}
-				abortDeletion = true
-				break
-			}
-		}
-	}
-
-	if !abortDeletion {
+	if !abortDeletion(errs) {
 		// Remove associations and any repos that are no longer associated with any
 		// external service.
 		//
@@ -723,6 +691,184 @@ func (s *Syncer) SyncExternalService(
 	return errs
 }
 
+// isFatalSourceError returns true if the given error from a source means that
+// none of the repos of the external service can be synced.
+func isFatalSourceError(err error) bool {
+	// If the error is just a warning, then it is not fatal.
+	if errors.IsWarning(err) && !errcode.IsAccountSuspended(err) {
+		return false
+	}
+
+	return errcode.IsUnauthorized(err) ||
+		errcode.IsForbidden(err) ||
+		errcode.IsAccountSuspended(err)
+}
+
+// abortDeletion returns true if repos that weren't sourced must not be deleted
+// because of the given errors encountered during a sync.
+//
+// If all of our errors are warnings and either Forbidden or Unauthorized,
+// we want to proceed with the deletion. This is to be able to properly sync
+// repos (by removing ones if code-host permissions have changed).
+func abortDeletion(errs error) bool {
+	if errs == nil {
+		return false
+	}
+
+	var ref errors.MultiError
+	if !errors.As(errs, &ref) {
+		return false
+	}
+
+	for _, e := range ref.Errors() {
+		if errors.IsWarning(e) {
+			baseError := errors.Unwrap(e)
+			if !errcode.IsForbidden(baseError) && !errcode.IsUnauthorized(baseError) {
+				return true
+			}
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// DryRunExternalService sources the repos of the given external service, whose
+// configuration may not have been saved yet, and returns the Diff that syncing
+// it would result in. Nothing is persisted.
+//
+// Deleted contains the repos that the external service currently syncs but
+// wouldn't anymore and that no other external service syncs, as well as repos
+// that would be deleted because of a naming conflict with a sourced repo. Like
+// SyncExternalService, no repos are reported as deleted if sourcing failed with
+// non-fatal errors.
+//
+// The returned error contains all the errors encountered while sourcing, the
+// Diff is computed from the repos that were sourced successfully regardless.
+func (s *Syncer) DryRunExternalService(ctx context.Context, svc *types.ExternalService) (d Diff, err error) {
+	if svc.CloudDefault {
+		return Diff{}, ErrCloudDefaultSync
+	}
+	if svc.NamespaceUserID != 0 {
+		return Diff{}, errors.New("syncing user owned external service not allowed")
+	}
+	if svc.NamespaceOrgID != 0 {
+		return Diff{}, errors.New("syncing organisation owned external service not allowed")
+	}
+
+	src, err := s.Sourcer(ctx, svc)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan SourceResult)
+
+	defer func() {
+		cancel()
+
+		// We need to drain the rest of the results to not leak a blocked goroutine.
+		for range results {
+		}
+	}()
+
+	go func() {
+		src.ListRepos(ctx, results)
+		close(results)
+	}()
+
+	seen := make(map[api.RepoID]struct{})
+	deleted := make(map[api.RepoID]struct{})
+	var errs error
+	for res := range results {
+		if err := res.Err; err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "fetching from code host %s", svc.DisplayName))
+			if isFatalSourceError(err) {
+				seen = map[api.RepoID]struct{}{}
+				break
+			}
+
+			continue
+		}
+
+		sourced := res.Repo
+
+		stored, err := s.Store.RepoStore().List(ctx, database.ReposListOptions{
+			Names:          []string{string(sourced.Name)},
+			ExternalRepos:  []api.ExternalRepoSpec{sourced.ExternalRepo},
+			IncludeBlocked: true,
+			IncludeDeleted: true,
+			UseOr:          true,
+		})
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "syncer: getting repo from the database"))
+			continue
+		}
+
+		switch len(stored) {
+		case 2: // Existing repo with a naming conflict, the other repo would be deleted.
+			var existing *types.Repo
+			for _, r := range stored {
+				if r.ExternalRepo.Equal(&sourced.ExternalRepo) {
+					existing = r
+				} else if _, ok := deleted[r.ID]; !ok {
+					deleted[r.ID] = struct{}{}
+					d.Deleted = append(d.Deleted, r)
+				}
+			}
+			stored = types.Repos{existing}
+			fallthrough
+		case 1: // Existing repo, would be updated.
+			seen[stored[0].ID] = struct{}{}
+			if modified := stored[0].Update(sourced); modified == types.RepoUnmodified {
+				d.Unmodified = append(d.Unmodified, stored[0])
+			} else {
+				d.Modified = append(d.Modified, RepoModified{Repo: stored[0], Modified: modified})
+			}
+		case 0: // New repo, would be created.
+			d.Added = append(d.Added, sourced)
+		default: // Impossible since we have two separate unique constraints on name and external repo spec
+			panic("unreachable")
+		}
+	}
+
+	// A new external service doesn't sync any repos yet.
+	if svc.ID == 0 || abortDeletion(errs) {
+		return d, errs
+	}
+
+	current, err := s.Store.RepoStore().List(ctx, database.ReposListOptions{
+		ExternalServiceIDs: []int64{svc.ID},
+	})
+	if err != nil {
+		return d, errors.Append(errs, errors.Wrap(err, "syncer: listing external service repos"))
+	}
+
+	// Like DeleteExternalServiceReposNotIn, repos that aren't seen anymore are
+	// only deleted if no other external service syncs them.
+	urn := svc.URN()
+	for _, r := range current {
+		if _, ok := seen[r.ID]; ok {
+			continue
+		}
+		if _, conflicting := deleted[r.ID]; conflicting {
+			continue
+		}
+		orphaned := true
+		for sourceURN := range r.Sources {
+			if sourceURN != urn {
+				orphaned = false
+				break
+			}
+		}
+		if orphaned {
+			d.Deleted = append(d.Deleted, r)
+		}
+	}
+
+	return d, errs
+}
+
 // syncs a sourced repo of a given external service, returning a diff with a single repo.
 func (s *Syncer) sync(ctx context.Context, svc *types.ExternalService, sourced *types.Repo) (d Diff, err error) {
 	tx, err := s.Store.Transact(ctx)
@@ -790,6 +936,7 @@ func (s *Syncer) sync(ctx context.Context, svc *types.ExternalService, sourced *
 		fallthrough
 	case 1: // Existing repo, update.
 		s.Logger.Debug("existing repo")
+		before := *stored[0]
 		modified := stored[0].Update(sourced)
 		if modified == types.RepoUnmodified {
 			d.Unmodified = append(d.Unmodified, stored[0])
@@ -800,6 +947,12 @@ func (s *Syncer) sync(ctx context.Context, svc *types.ExternalService, sourced *
 			return Diff{}, errors.Wrap(err, "syncer: failed to update external service repo")
 		}
 
+		if events := repoNameHistoryEvents(svc, &before, stored[0], modified); len(events) > 0 {
+			if err = tx.RepoNameHistoryStore().Create(ctx, events...); err != nil {
+				return Diff{}, errors.Wrap(err, "syncer: failed to record repo name history")
+			}
+		}
+
 		*sourced = *stored[0]
 		d.Modified = append(d.Modified, RepoModified{Repo: stored[0], Modified: modified})
 		s.Logger.Debug("appended to modified repos")
@@ -819,6 +972,35 @@ func (s *Syncer) sync(ctx context.Context, svc *types.ExternalService, sourced *
 	return d, nil
 }
 
+// repoNameHistoryEvents returns the renames and archive state changes between
+// the stored and updated versions of a repo.
+func repoNameHistoryEvents(svc *types.ExternalService, before, after *types.Repo, modified types.RepoModified) (events []*database.RepoNameHistoryEvent) {
+	if modified&types.RepoModifiedName == types.RepoModifiedName {
+		events = append(events, &database.RepoNameHistoryEvent{
+			RepoID:            after.ID,
+			ExternalServiceID: svc.ID,
+			Event:             database.RepoNameHistoryRenamed,
+			FromName:          before.Name,
+			ToName:            after.Name,
+		})
+	}
+
+	if modified&types.RepoModifiedArchived == types.RepoModifiedArchived {
+		event := database.RepoNameHistoryUnarchived
+		if after.Archived {
+			event = database.RepoNameHistoryArchived
+		}
+		events = append(events, &database.RepoNameHistoryEvent{
+			RepoID:            after.ID,
+			ExternalServiceID: svc.ID,
+			Event:             event,
+			FromName:          after.Name,
+		})
+	}
+
+	return events
+}
+
 func (s *Syncer) delete(ctx context.Context, svc *types.ExternalService, seen map[api.RepoID]struct{}) (int, error) {
 	// We do deletion in a best effort manner, returning any errors for individual repos that failed to be deleted.
 	deleted, err := s.Store.DeleteExternalServiceReposNotIn(ctx, svc, seen)
@@ -1312,6 +1312,110 @@ func TestOrphanedRepo(t *testing.T) {
 	assertDeletedRepoCount(ctx, t, store, 1)
 }
 
+func TestSyncerRepoNameHistory(t *testing.T) {
+	t.Parallel()
+	store := getTestRepoStore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now()
+
+	svc1 := &types.ExternalService{
+		Kind:        extsvc.KindGitHub,
+		DisplayName: "Github - Test1",
+		Config:      extsvc.NewUnencryptedConfig(basicGitHubConfig),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	svc2 := &types.ExternalService{
+		Kind:        extsvc.KindGitHub,
+		DisplayName: "Github - Test2",
+		Config:      extsvc.NewUnencryptedConfig(basicGitHubConfig),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := store.ExternalServiceStore().Upsert(ctx, svc1, svc2); err != nil {
+		t.Fatal(err)
+	}
+
+	githubRepo := func(name api.RepoName, archived bool) *types.Repo {
+		return &types.Repo{
+			Name:     name,
+			Archived: archived,
+			Metadata: &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "foo-external-12345",
+				ServiceID:   "https://github.com/",
+				ServiceType: extsvc.TypeGitHub,
+			},
+		}
+	}
+
+	syncer := &repos.Syncer{
+		Logger: logtest.Scoped(t),
+		Store:  store,
+		Now:    time.Now,
+	}
+	sync := func(svc *types.ExternalService, rs ...*types.Repo) {
+		t.Helper()
+
+		syncer.Sourcer = func(ctx context.Context, service *types.ExternalService) (repos.Source, error) {
+			return repos.NewFakeSource(svc, nil, rs...), nil
+		}
+		if err := syncer.SyncExternalService(ctx, svc.ID, 10*time.Second, noopProgressRecorder); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertHistory := func(svc *types.ExternalService, want []database.RepoNameHistoryEvent) {
+		t.Helper()
+
+		events, err := store.RepoNameHistoryStore().List(ctx, database.RepoNameHistoryListOptions{ExternalServiceID: svc.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have := make([]database.RepoNameHistoryEvent, 0, len(events))
+		for i := len(events) - 1; i >= 0; i-- {
+			have = append(have, database.RepoNameHistoryEvent{
+				Event:    events[i].Event,
+				FromName: events[i].FromName,
+				ToName:   events[i].ToName,
+			})
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("unexpected history of external service %d (-want +got):\n%s", svc.ID, diff)
+		}
+	}
+
+	sync(svc1, githubRepo("github.com/org/foo", false))
+	sync(svc2, githubRepo("github.com/org/foo", false))
+
+	// Rename and archive the repo on the code host
+	sync(svc1, githubRepo("github.com/org/bar", false))
+	sync(svc1, githubRepo("github.com/org/bar", true))
+
+	// Stop syncing the repo with the first service; it is still synced by the second
+	sync(svc1)
+	assertDeletedRepoCount(ctx, t, store, 0)
+
+	assertHistory(svc1, []database.RepoNameHistoryEvent{
+		{Event: database.RepoNameHistoryRenamed, FromName: "github.com/org/foo", ToName: "github.com/org/bar"},
+		{Event: database.RepoNameHistoryArchived, FromName: "github.com/org/bar"},
+		{Event: database.RepoNameHistoryDeleted, FromName: "github.com/org/bar"},
+	})
+
+	// Deleting the second service deletes the now orphaned repo
+	if err := store.ExternalServiceStore().Delete(ctx, svc2.ID); err != nil {
+		t.Fatal(err)
+	}
+	assertDeletedRepoCount(ctx, t, store, 1)
+
+	assertHistory(svc2, []database.RepoNameHistoryEvent{
+		{Event: database.RepoNameHistoryDeleted, FromName: "github.com/org/bar"},
+	})
+}
+
 func TestCloudDefaultExternalServicesDontSync(t *testing.T) {
 	t.Parallel()
 	store := getTestRepoStore(t)
@@ -263,6 +263,38 @@ func (c *Client) SyncExternalService(ctx context.Context, externalServiceID int6
 	return &result, nil
 }
 
+// DryRunExternalService returns the repos that syncing the given external
+// service with the proposed config would add, modify and delete, without
+// persisting anything. A zero externalServiceID previews a new external
+// service of the given kind.
+func (c *Client) DryRunExternalService(ctx context.Context, externalServiceID int64, kind, config string) (*protocol.ExternalServiceDryRunResult, error) {
+	req := &protocol.ExternalServiceDryRunRequest{
+		ExternalServiceID: externalServiceID,
+		Kind:              kind,
+		Config:            config,
+	}
+	resp, err := c.httpPost(ctx, "dry-run-external-service", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	}
+
+	var result protocol.ExternalServiceDryRunResult
+	if err = json.Unmarshal(bs, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (c *Client) httpPost(ctx context.Context, method string, payload any) (resp *http.Response, err error) {
 	reqBody, err := json.Marshal(payload)
 	if err != nil {
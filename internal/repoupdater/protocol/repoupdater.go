@@ -268,3 +268,34 @@ type ExternalServiceSyncRequest struct {
 type ExternalServiceSyncResult struct {
 	Error string
 }
+
+// ExternalServiceDryRunRequest is a request to compute the changes that syncing
+// an external service with a proposed configuration would make, without
+// persisting anything.
+//
+// The FrontendAPI issues this request to preview the effects of an external
+// service configuration before it is saved.
+type ExternalServiceDryRunRequest struct {
+	// ExternalServiceID is the ID of the external service whose configuration is
+	// being edited, or zero for an external service that doesn't exist yet.
+	ExternalServiceID int64
+	// Kind is the kind of the external service. It is required if
+	// ExternalServiceID is zero and ignored otherwise.
+	Kind string
+	// Config is the proposed configuration. Redacted fields are replaced with
+	// the values of the current configuration of ExternalServiceID.
+	Config string
+}
+
+// ExternalServiceDryRunResult is a result type of an external service's dry-run
+// request. It contains the names of the repos that would be added, modified and
+// deleted by a sync.
+type ExternalServiceDryRunResult struct {
+	Added    []api.RepoName
+	Modified []api.RepoName
+	Deleted  []api.RepoName
+
+	// Error is set if sourcing the repos failed, in which case the lists above
+	// may be incomplete.
+	Error string
+}
@@ -19,9 +19,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"github.com/sourcegraph/zoekt"
@@ -90,21 +92,103 @@ func (s *searchClient) Plan(
 		return nil, errors.New("Structural search is disabled in the site configuration.")
 	}
 
+	var (
+		allVirtualRepos       []*types.VirtualRepo
+		allVirtualReposLoaded bool
+		virtualRepos          []*types.VirtualRepo
+	)
+	// loadVirtualRepos returns every virtual repository. Virtual repositories are rare, so their
+	// existence is checked before any of them are listed or search contexts are expanded.
+	loadVirtualRepos := func() ([]*types.VirtualRepo, error) {
+		if allVirtualReposLoaded {
+			return allVirtualRepos, nil
+		}
+
+		count, err := s.db.VirtualRepos().Count(ctx, database.VirtualReposListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			if allVirtualRepos, err = s.db.VirtualRepos().List(ctx, database.VirtualReposListOptions{}); err != nil {
+				return nil, err
+			}
+		}
+		allVirtualReposLoaded = true
+		return allVirtualRepos, nil
+	}
+	addVirtualRepo := func(vr *types.VirtualRepo) {
+		for _, other := range virtualRepos {
+			if other.ID == vr.ID {
+				return
+			}
+		}
+		virtualRepos = append(virtualRepos, vr)
+	}
+
 	// Beta: create a step to replace each context in the query with its repository query if any.
+	// Contexts that include virtual repositories are replaced with a query that also covers
+	// their directories.
 	searchContextsQueryEnabled := settings.ExperimentalFeatures != nil && getBoolPtr(settings.ExperimentalFeatures.SearchContextsQuery, true)
 	substituteContextsStep := query.SubstituteSearchContexts(func(context string) (string, error) {
 		sc, err := searchcontexts.ResolveSearchContextSpec(ctx, s.db, context)
 		if err != nil {
 			return "", err
 		}
-		tr.LazyPrintf("substitute query %s for context %s", sc.Query, context)
-		return sc.Query, nil
+		if sc.Query != "" {
+			tr.LazyPrintf("substitute query %s for context %s", sc.Query, context)
+			return sc.Query, nil
+		}
+		if searchcontexts.IsAutoDefinedSearchContext(sc) {
+			return "", nil
+		}
+		if vrs, err := loadVirtualRepos(); err != nil || len(vrs) == 0 {
+			return "", err
+		}
+
+		repoRevs, err := s.db.SearchContexts().GetSearchContextRepositoryRevisions(ctx, sc.ID)
+		if err != nil {
+			return "", err
+		}
+		q, vrs := searchcontexts.VirtualRepoQuery(context, repoRevs)
+		for _, vr := range vrs {
+			addVirtualRepo(vr)
+		}
+		if q != "" {
+			tr.LazyPrintf("substitute query %s for context %s", q, context)
+		}
+		return q, nil
+	})
+
+	// Replace each virtual repository in the query with its parent repository and directory.
+	substituteVirtualReposStep := query.SubstituteVirtualRepos(func(pattern string) ([]query.VirtualRepoScope, error) {
+		vrs, err := loadVirtualRepos()
+		if err != nil || len(vrs) == 0 {
+			return nil, err
+		}
+
+		scopes := make([]query.VirtualRepoScope, 0, len(vrs))
+		byName := make(map[string]*types.VirtualRepo, len(vrs))
+		for _, vr := range vrs {
+			scopes = append(scopes, query.VirtualRepoScope{Name: string(vr.Name), Parent: string(vr.Parent.Name), PathPrefix: vr.PathPrefix})
+			byName[string(vr.Name)] = vr
+		}
+		matched, err := query.MatchVirtualRepos(pattern, scopes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, scope := range matched {
+			tr.LazyPrintf("substitute repo %s and path %s for virtual repo %s", scope.Parent, scope.PathPrefix, scope.Name)
+			addVirtualRepo(byName[scope.Name])
+		}
+		return matched, nil
 	})
 
 	var plan query.Plan
 	plan, err = query.Pipeline(
 		query.Init(searchQuery, searchType),
 		query.With(searchContextsQueryEnabled, substituteContextsStep),
+		substituteVirtualReposStep,
 	)
 	if err != nil {
 		return nil, &QueryError{Query: searchQuery, Err: err}
@@ -122,6 +206,7 @@ func (s *searchClient) Plan(
 		PatternType:            searchType,
 		Protocol:               protocol,
 		SanitizeSearchPatterns: sanitizeSearchPatterns(ctx, s.db, s.logger), // Experimental: check site config to see if search sanitization is enabled
+		VirtualRepos:           virtualRepos,
 	}
 
 	tr.LazyPrintf("Parsed query: %s", inputs.Query)
@@ -145,6 +230,14 @@ func (s *searchClient) Execute(
 		return nil, err
 	}
 
+	if len(inputs.VirtualRepos) > 0 {
+		parent := stream
+		stream = streaming.StreamFunc(func(event streaming.SearchEvent) {
+			event.Results = result.RewriteVirtualRepos(event.Results, inputs.VirtualRepos)
+			parent.Send(event)
+		})
+	}
+
 	return planJob.Run(ctx, s.JobClients(), stream)
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -176,3 +177,43 @@ func TestSanitizeSearchPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanVirtualRepos(t *testing.T) {
+	virtualRepo := &types.VirtualRepo{
+		ID:         1,
+		Name:       "github.com/org/mono/payments",
+		Parent:     types.MinimalRepo{ID: 2, Name: "github.com/org/mono"},
+		PathPrefix: "services/payments",
+	}
+
+	plan := func(t *testing.T, count int) (*search.Inputs, *database.MockVirtualReposStore) {
+		t.Helper()
+
+		virtualRepos := database.NewMockVirtualReposStore()
+		virtualRepos.CountFunc.SetDefaultReturn(count, nil)
+		virtualRepos.ListFunc.SetDefaultReturn([]*types.VirtualRepo{virtualRepo}, nil)
+		db := database.NewMockDB()
+		db.VirtualReposFunc.SetDefaultReturn(virtualRepos)
+
+		cli := NewSearchClient(logtest.Scoped(t), db, nil, nil)
+		inputs, err := cli.Plan(context.Background(), "V3", nil, "repo:payments foo repo:mono bar", search.Precise, search.Streaming, &schema.Settings{}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return inputs, virtualRepos
+	}
+
+	t.Run("no virtual repos", func(t *testing.T) {
+		inputs, virtualRepos := plan(t, 0)
+		require.Empty(t, inputs.VirtualRepos)
+		require.Len(t, virtualRepos.CountFunc.History(), 1)
+		require.Empty(t, virtualRepos.ListFunc.History())
+	})
+
+	t.Run("virtual repos", func(t *testing.T) {
+		inputs, virtualRepos := plan(t, 1)
+		require.Equal(t, []*types.VirtualRepo{virtualRepo}, inputs.VirtualRepos)
+		require.Len(t, virtualRepos.CountFunc.History(), 1)
+		require.Len(t, virtualRepos.ListFunc.History(), 1)
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
 	"github.com/sourcegraph/sourcegraph/internal/search/smartsearch"
 	"github.com/sourcegraph/sourcegraph/internal/search/structural"
+	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -120,7 +121,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 			}
 		}
 
-		if resultTypes.Has(result.TypeSymbol) {
+		if resultTypes.Has(result.TypeSymbol) && b.Precise() != query.Only {
 			// Create Global Symbol Search jobs.
 			if repoUniverseSearch {
 				job, err := builder.newZoektGlobalSearch(search.SymbolRequest)
@@ -331,8 +332,10 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 
 		// Create Symbol Search Jobs
 		if resultTypes.Has(result.TypeSymbol) {
+			precise := f.Precise()
+
 			// Create Symbol Search jobs over repo set.
-			if !skipRepoSubsetSearch {
+			if !skipRepoSubsetSearch && precise != query.Only {
 				symbolSearchJob := &searcher.SymbolSearchJob{
 					PatternInfo: patternInfo,
 					Limit:       maxResults,
@@ -344,6 +347,24 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 					containsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
 				})
 			}
+
+			// Create Precise Symbol Search jobs over repo set. Precise symbols are
+			// read from code intelligence indexes rather than Zoekt or searcher, so
+			// these run over all repos regardless of whether they are indexed. As
+			// with unindexed symbol search, they don't run over the unbounded repo
+			// set of global searches on Sourcegraph.com.
+			if !skipRepoSubsetSearch && precise != query.No {
+				preciseSymbolSearchJob := &symbol.PreciseSymbolSearchJob{
+					PatternInfo: patternInfo,
+					Limit:       maxResults,
+				}
+
+				addJob(&repoPagerJob{
+					child:            &reposPartialJob{preciseSymbolSearchJob},
+					repoOpts:         repoOptions,
+					containsRefGlobs: query.ContainsRefGlobs(f.ToBasic().ToParseTree()),
+				})
+			}
 		}
 
 		if resultTypes.Has(result.TypeStructural) {
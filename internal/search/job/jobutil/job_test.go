@@ -284,6 +284,59 @@ func TestNewPlanJob(t *testing.T) {
         (REPOSCOMPUTEEXCLUDED
           )
         NoopJob))))`),
+	}, {
+		query:      `type:symbol precise:only test`,
+		protocol:   search.Streaming,
+		searchType: query.SearchTypeRegex,
+		want: autogold.Want("precise symbol only", `
+(ALERT
+  (query . )
+  (originalQuery . )
+  (patternType . regex)
+  (TIMEOUT
+    (timeout . 20s)
+    (LIMIT
+      (limit . 500)
+      (PARALLEL
+        (REPOSCOMPUTEEXCLUDED
+          )
+        NoopJob))))`),
+	}, {
+		query:      `repo:sourcegraph type:symbol precise:yes test`,
+		protocol:   search.Streaming,
+		searchType: query.SearchTypeRegex,
+		want: autogold.Want("precise symbol", `
+(ALERT
+  (query . )
+  (originalQuery . )
+  (patternType . regex)
+  (TIMEOUT
+    (timeout . 20s)
+    (LIMIT
+      (limit . 500)
+      (PARALLEL
+        (REPOPAGER
+          (repoOpts.repoFilters.0 . sourcegraph)
+          (PARTIALREPOS
+            (ZOEKTSYMBOLSEARCH
+              (query . sym:substr:"test"))))
+        (REPOSCOMPUTEEXCLUDED
+          (repoOpts.repoFilters.0 . sourcegraph))
+        (PARALLEL
+          (REPOPAGER
+            (repoOpts.repoFilters.0 . sourcegraph)
+            (PARTIALREPOS
+              (SEARCHERSYMBOLSEARCH
+                (patternInfo.pattern . test)(patternInfo.isRegexp . true)(patternInfo.fileMatchLimit . 500)
+                (numRepos . 0)
+                (limit . 500))))
+          (REPOPAGER
+            (repoOpts.repoFilters.0 . sourcegraph)
+            (PARTIALREPOS
+              (PRECISESYMBOLSEARCH
+                (patternInfo.pattern . test)(patternInfo.isRegexp . true)(patternInfo.fileMatchLimit . 500)
+                (numRepos . 0)
+                (limit . 500)))))))))`),
 	}, {
 		query:      `type:commit test`,
 		protocol:   search.Streaming,
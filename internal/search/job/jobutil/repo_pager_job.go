@@ -2,6 +2,7 @@ package jobutil
 
 import (
 	"context"
+	"sort"
 
 	otlog "github.com/opentracing/opentracing-go/log"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
@@ -71,12 +73,31 @@ func setRepos(j job.Job, indexed *zoekt.IndexedRepoRevs, unindexed []*search.Rep
 			cp := *v
 			cp.Repos = unindexed
 			return &cp
+		case *symbol.PreciseSymbolSearchJob:
+			cp := *v
+			cp.Repos = allRepoRevs(indexed, unindexed)
+			return &cp
 		default:
 			return j
 		}
 	})
 }
 
+// allRepoRevs returns the union of the given indexed and unindexed repository
+// revisions, ordered by repository identifier.
+func allRepoRevs(indexed *zoekt.IndexedRepoRevs, unindexed []*search.RepositoryRevisions) []*search.RepositoryRevisions {
+	repoRevs := make([]*search.RepositoryRevisions, 0, len(unindexed))
+	if indexed != nil {
+		for _, rr := range indexed.RepoRevs {
+			repoRevs = append(repoRevs, rr)
+		}
+	}
+	repoRevs = append(repoRevs, unindexed...)
+
+	sort.SliceStable(repoRevs, func(i, j int) bool { return repoRevs[i].Repo.ID < repoRevs[j].Repo.ID })
+	return repoRevs
+}
+
 func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
 	_, ctx, stream, finish := job.StartSpan(ctx, stream, p)
 	defer func() { finish(alert, err) }()
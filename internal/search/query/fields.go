@@ -29,7 +29,8 @@ const (
 
 	// Temporary experimental fields:
 	FieldIndex     = "index"
-	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
+	FieldPrecise   = "precise" // Searches that specify `precise:` include symbols from precise code intelligence indexes
+	FieldCount     = "count"   // Searches that specify `count:` will fetch at least that number of results, or the full result set
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
 	FieldSelect    = "select"
@@ -65,6 +66,7 @@ var allFields = map[string]struct{}{
 	"m":                     empty,
 	"msg":                   empty,
 	FieldIndex:              empty,
+	FieldPrecise:            empty,
 	FieldCount:              empty,
 	FieldTimeout:            empty,
 	FieldCombyRule:          empty,
@@ -1,6 +1,11 @@
 package query
 
-import "github.com/sourcegraph/sourcegraph/lib/errors"
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
 
 /*
 Query processing involves multiple steps to produce a query to evaluate.
@@ -88,6 +93,89 @@ func SubstituteSearchContexts(lookupQueryString func(contextValue string) (strin
 	}
 }
 
+// VirtualRepoScope is a virtual repository together with the parent
+// repository and directory it is scoped to.
+type VirtualRepoScope struct {
+	Name       string
+	Parent     string
+	PathPrefix string
+}
+
+// SubstituteVirtualRepos expands `repo:` filters that match virtual
+// repositories. A filter that names a single virtual repository, like
+// repo:^github\.com/org/mono/services/payments$, is substituted for a query
+// scoped to the directory of its parent repository, like
+// (repo:^github\.com/org/mono$ file:^services/payments/). Any other filter
+// matching virtual repositories, like repo:services/, is kept as an
+// alternative to the scoped queries of each of them. It relies on a lookup
+// function, which should return the virtual repositories matched by a repo:
+// pattern. Negated filters and predicates are left unchanged.
+func SubstituteVirtualRepos(lookup func(pattern string) ([]VirtualRepoScope, error)) step {
+	return func(nodes []Node) ([]Node, error) {
+		var errs error
+		substituted := MapField(nodes, FieldRepo, func(value string, negated bool, ann Annotation) Node {
+			original := Parameter{
+				Value:      value,
+				Field:      FieldRepo,
+				Negated:    negated,
+				Annotation: ann,
+			}
+			if negated || ann.Labels.IsSet(IsPredicate) {
+				return original
+			}
+
+			pattern, revs, _ := strings.Cut(value, "@")
+			scopes, err := lookup(pattern)
+			if err != nil {
+				errs = errors.Append(errs, err)
+				return nil
+			}
+			if len(scopes) == 0 {
+				return original
+			}
+
+			operands := make([]Node, 0, len(scopes)+1)
+			for _, scope := range scopes {
+				repoValue := "^" + regexp.QuoteMeta(scope.Parent) + "$"
+				if revs != "" {
+					repoValue += "@" + revs
+				}
+				operands = append(operands, Operator{Kind: And, Operands: []Node{
+					Parameter{Value: repoValue, Field: FieldRepo, Annotation: ann},
+					Parameter{Value: "^" + regexp.QuoteMeta(scope.PathPrefix) + "/", Field: FieldFile},
+				}})
+			}
+
+			name := strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$"), `\.`, ".")
+			if len(scopes) == 1 && scopes[0].Name == name {
+				return operands[0]
+			}
+			return Operator{Kind: Or, Operands: append([]Node{original}, operands...)}
+		})
+
+		return substituted, errs
+	}
+}
+
+// MatchVirtualRepos returns the virtual repositories whose names match the
+// given repo: pattern. Virtual repositories whose parent repository matches
+// the pattern as well are omitted, since the parent already covers their
+// directory.
+func MatchVirtualRepos(pattern string, virtualRepos []VirtualRepoScope) ([]VirtualRepoScope, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []VirtualRepoScope
+	for _, vr := range virtualRepos {
+		if re.MatchString(vr.Name) && !re.MatchString(vr.Parent) {
+			matched = append(matched, vr)
+		}
+	}
+	return matched, nil
+}
+
 // For runs processing steps for a given search type. This includes
 // normalization, substitution for whitespace, and pattern labeling.
 func For(searchType SearchType) step {
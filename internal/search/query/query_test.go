@@ -44,3 +44,45 @@ func TestSubstituteSearchContexts(t *testing.T) {
 		autogold.Equal(t, autogold.Raw(test("context:gordo repo:contains.path(gordo)", true)))
 	})
 }
+
+func TestSubstituteVirtualRepos(t *testing.T) {
+	virtualRepos := []VirtualRepoScope{
+		{Name: "github.com/org/mono/services/payments", Parent: "github.com/org/mono", PathPrefix: "services/payments"},
+		{Name: "github.com/org/mono/services/billing", Parent: "github.com/org/mono", PathPrefix: "services/billing"},
+	}
+
+	test := func(input string) string {
+		lookup := func(pattern string) ([]VirtualRepoScope, error) {
+			return MatchVirtualRepos(pattern, virtualRepos)
+		}
+		plan, err := Pipeline(InitLiteral(input), SubstituteVirtualRepos(lookup))
+		if err != nil {
+			return err.Error()
+		}
+		return plan.ToQ().String()
+	}
+
+	t.Run("anchored name", func(t *testing.T) {
+		autogold.Equal(t, autogold.Raw(test(`repo:^github\.com/org/mono/services/payments$ charge`)))
+	})
+
+	t.Run("unescaped name with revision", func(t *testing.T) {
+		autogold.Equal(t, autogold.Raw(test(`repo:github.com/org/mono/services/payments@main charge`)))
+	})
+
+	t.Run("other repo unchanged", func(t *testing.T) {
+		autogold.Equal(t, autogold.Raw(test(`repo:github.com/org/mono charge`)))
+	})
+
+	t.Run("negated unchanged", func(t *testing.T) {
+		autogold.Equal(t, autogold.Raw(test(`-repo:github.com/org/mono/services/payments charge`)))
+	})
+
+	t.Run("pattern matching several virtual repos", func(t *testing.T) {
+		autogold.Equal(t, autogold.Raw(test(`repo:mono/services/ charge`)))
+	})
+
+	t.Run("prefix of a virtual repo name", func(t *testing.T) {
+		autogold.Equal(t, autogold.Raw(test(`repo:^github\.com/org/mono/services/pay charge`)))
+	})
+}
@@ -547,6 +547,16 @@ func (p Parameters) Index() YesNoOnly {
 	return *v
 }
 
+// Precise returns whether symbol searches should include (yes), exclude (no),
+// or be limited to (only) symbols from precise code intelligence indexes.
+func (p Parameters) Precise() YesNoOnly {
+	v := p.yesNoOnlyValue(FieldPrecise)
+	if v == nil {
+		return No
+	}
+	return *v
+}
+
 func (p Parameters) Fork() *YesNoOnly {
 	return p.yesNoOnlyValue(FieldFork)
 }
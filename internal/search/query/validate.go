@@ -274,6 +274,7 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return satisfies(isValidRegexp)
 	case
 		FieldIndex,
+		FieldPrecise,
 		FieldFork,
 		FieldArchived:
 		return satisfies(isSingular, isNotNegated, isYesNoOnly)
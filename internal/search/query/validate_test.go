@@ -17,6 +17,10 @@ func TestValidation(t *testing.T) {
 			input: "index:foo",
 			want:  `invalid value "foo" for field "index". Valid values are: yes, only, no`,
 		},
+		{
+			input: "precise:maybe",
+			want:  `invalid value "maybe" for field "precise". Valid values are: yes, only, no`,
+		},
 		{
 			input: "case:yes case:no",
 			want:  `field "case" may not be used more than once`,
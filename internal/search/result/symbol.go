@@ -26,6 +26,12 @@ type Symbol struct {
 	Signature  string
 
 	FileLimited bool
+
+	// FullyQualifiedName, Package, and Documentation are only populated for
+	// symbols read from precise code intelligence indexes.
+	FullyQualifiedName string
+	Package            string
+	Documentation      string
 }
 
 // NewSymbolMatch returns a new SymbolMatch. Passing -1 as the character will make NewSymbolMatch infer
@@ -0,0 +1,50 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// RewriteVirtualRepos attributes matches in the parent repository of a virtual
+// repository to the virtual repository, so that they are presented under the
+// name the query used. File matches are only rewritten if they are inside the
+// directory of the virtual repository. A repository match of a parent is
+// rewritten into one match per virtual repository of that parent. Paths are
+// left unchanged, since the virtual repository shares the tree of its parent.
+func RewriteVirtualRepos(matches Matches, virtualRepos []*types.VirtualRepo) Matches {
+	if len(virtualRepos) == 0 {
+		return matches
+	}
+
+	rewritten := make(Matches, 0, len(matches))
+	for _, match := range matches {
+		switch m := match.(type) {
+		case *FileMatch:
+			for _, vr := range virtualRepos {
+				if m.Repo.ID == vr.Parent.ID && vr.Contains(m.Path) {
+					fm := *m
+					fm.Repo.Name = vr.Name
+					match = &fm
+					break
+				}
+			}
+			rewritten = append(rewritten, match)
+		case *RepoMatch:
+			n := len(rewritten)
+			for _, vr := range virtualRepos {
+				if m.ID == vr.Parent.ID {
+					rm := *m
+					rm.Name = vr.Name
+					rm.RepoNameMatches = nil
+					rewritten = append(rewritten, &rm)
+				}
+			}
+			if len(rewritten) == n {
+				rewritten = append(rewritten, match)
+			}
+		default:
+			rewritten = append(rewritten, match)
+		}
+	}
+
+	return rewritten
+}
@@ -0,0 +1,39 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRewriteVirtualRepos(t *testing.T) {
+	mono := types.MinimalRepo{ID: 1, Name: "github.com/org/mono"}
+	other := types.MinimalRepo{ID: 2, Name: "github.com/org/other"}
+	payments := &types.VirtualRepo{Name: "github.com/org/mono/services/payments", Parent: mono, PathPrefix: "services/payments"}
+	billing := &types.VirtualRepo{Name: "github.com/org/mono/services/billing", Parent: mono, PathPrefix: "services/billing"}
+
+	matches := Matches{
+		&FileMatch{File: File{Repo: mono, Path: "services/payments/charge.go"}},
+		&FileMatch{File: File{Repo: mono, Path: "services/paymentsv2/charge.go"}},
+		&FileMatch{File: File{Repo: other, Path: "services/payments/charge.go"}},
+		&FileMatch{File: File{Repo: mono, Path: "services/billing/invoice.go"}},
+		&RepoMatch{ID: mono.ID, Name: mono.Name},
+		&RepoMatch{ID: other.ID, Name: other.Name},
+		&CommitMatch{Repo: mono},
+	}
+
+	got := RewriteVirtualRepos(matches, []*types.VirtualRepo{payments, billing})
+	require.Len(t, got, 8)
+
+	require.Equal(t, payments.Name, got[0].RepoName().Name)
+	require.Equal(t, "services/payments/charge.go", got[0].(*FileMatch).Path)
+	require.Equal(t, mono.Name, got[1].RepoName().Name)
+	require.Equal(t, other.Name, got[2].RepoName().Name)
+	require.Equal(t, billing.Name, got[3].RepoName().Name)
+	require.Equal(t, payments.Name, got[4].RepoName().Name)
+	require.Equal(t, billing.Name, got[5].RepoName().Name)
+	require.Equal(t, other.Name, got[6].RepoName().Name)
+	require.Equal(t, mono.Name, got[7].RepoName().Name)
+}
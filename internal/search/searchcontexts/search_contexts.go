@@ -3,6 +3,7 @@ package searchcontexts
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -535,6 +536,9 @@ func ParseRepoOpts(contextQuery string) ([]RepoOpts, error) {
 	return qs, nil
 }
 
+// GetRepositoryRevisions returns the repositories and revisions included in
+// the search context. Virtual repositories are not included, since they only
+// cover a directory of their parent repository; see VirtualRepoQuery.
 func GetRepositoryRevisions(ctx context.Context, db database.DB, searchContextID int64) ([]search.RepositoryRevisions, error) {
 	searchContextRepositoryRevisions, err := db.SearchContexts().GetSearchContextRepositoryRevisions(ctx, searchContextID)
 	if err != nil {
@@ -543,6 +547,9 @@ func GetRepositoryRevisions(ctx context.Context, db database.DB, searchContextID
 
 	repositoryRevisions := make([]search.RepositoryRevisions, 0, len(searchContextRepositoryRevisions))
 	for _, searchContextRepositoryRevision := range searchContextRepositoryRevisions {
+		if searchContextRepositoryRevision.VirtualRepo != nil {
+			continue
+		}
 		repositoryRevisions = append(repositoryRevisions, search.RepositoryRevisions{
 			Repo: searchContextRepositoryRevision.Repo,
 			Revs: searchContextRepositoryRevision.Revisions,
@@ -551,6 +558,43 @@ func GetRepositoryRevisions(ctx context.Context, db database.DB, searchContextID
 	return repositoryRevisions, nil
 }
 
+// VirtualRepoQuery returns a query for the search context with the given spec
+// and repository revisions that also matches the directories of the virtual
+// repositories it includes, like
+// (context:spec OR (repo:^github\.com/org/mono$@main file:^services/payments/)).
+// The context: term is omitted if the search context includes only virtual
+// repositories. It returns the virtual repositories, or an empty query and no
+// virtual repositories if it includes none.
+func VirtualRepoQuery(searchContextSpec string, repositoryRevisions []*types.SearchContextRepositoryRevisions) (string, []*types.VirtualRepo) {
+	var (
+		terms        []string
+		virtualRepos []*types.VirtualRepo
+		hasRepos     bool
+	)
+	for _, repoRev := range repositoryRevisions {
+		if repoRev.VirtualRepo == nil {
+			hasRepos = true
+			continue
+		}
+
+		repo := "^" + regexp.QuoteMeta(string(repoRev.Repo.Name)) + "$"
+		if len(repoRev.Revisions) > 0 {
+			repo += "@" + strings.Join(repoRev.Revisions, ":")
+		}
+		file := "^" + regexp.QuoteMeta(repoRev.VirtualRepo.PathPrefix) + "/"
+		terms = append(terms, fmt.Sprintf("(repo:%s file:%s)", strconv.Quote(repo), strconv.Quote(file)))
+		virtualRepos = append(virtualRepos, repoRev.VirtualRepo)
+	}
+	if len(terms) == 0 {
+		return "", nil
+	}
+
+	if hasRepos {
+		terms = append([]string{"context:" + searchContextSpec}, terms...)
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", virtualRepos
+}
+
 func IsAutoDefinedSearchContext(searchContext *types.SearchContext) bool {
 	return searchContext.ID == 0
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -269,6 +270,40 @@ func TestResolvingSearchContextRepoNames(t *testing.T) {
 	}
 }
 
+func TestVirtualRepoQuery(t *testing.T) {
+	mono := types.MinimalRepo{ID: 1, Name: "github.com/org/mono"}
+	other := types.MinimalRepo{ID: 2, Name: "github.com/org/other"}
+	payments := &types.VirtualRepo{ID: 1, Name: "github.com/org/mono/services/payments", Parent: mono, PathPrefix: "services/payments"}
+
+	q, vrs := VirtualRepoQuery("ctx", []*types.SearchContextRepositoryRevisions{{Repo: other, Revisions: []string{"HEAD"}}})
+	if q != "" || len(vrs) != 0 {
+		t.Fatalf("expected no query without virtual repositories, got %q", q)
+	}
+
+	lookup := func(repositoryRevisions []*types.SearchContextRepositoryRevisions) func(string) (string, error) {
+		return func(spec string) (string, error) {
+			q, _ := VirtualRepoQuery(spec, repositoryRevisions)
+			return q, nil
+		}
+	}
+	test := func(repositoryRevisions []*types.SearchContextRepositoryRevisions) string {
+		plan, err := query.Pipeline(query.InitLiteral("context:ctx charge"), query.SubstituteSearchContexts(lookup(repositoryRevisions)))
+		if err != nil {
+			return err.Error()
+		}
+		return plan.ToQ().String()
+	}
+
+	virtualOnly := []*types.SearchContextRepositoryRevisions{{Repo: mono, Revisions: []string{"main", "dev"}, VirtualRepo: payments}}
+	require.Equal(t, `(and "repo:^github\\.com/org/mono$@main:dev" "file:^services/payments/" "charge")`, test(virtualOnly))
+
+	mixed := append([]*types.SearchContextRepositoryRevisions{{Repo: other, Revisions: []string{"HEAD"}}}, virtualOnly...)
+	require.Equal(t, `(or (and "context:ctx" "charge") (and "repo:^github\\.com/org/mono$@main:dev" "file:^services/payments/" "charge"))`, test(mixed))
+
+	_, vrs = VirtualRepoQuery("ctx", mixed)
+	require.Equal(t, []*types.VirtualRepo{payments}, vrs)
+}
+
 func TestSearchContextWriteAccessValidation(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
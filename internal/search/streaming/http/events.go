@@ -136,6 +136,11 @@ type Symbol struct {
 	ContainerName string `json:"containerName"`
 	Kind          string `json:"kind"`
 	Line          int32  `json:"line"`
+
+	// Only set for symbols read from precise code intelligence indexes.
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Package            string `json:"package,omitempty"`
+	Documentation      string `json:"documentation,omitempty"`
 }
 
 // EventCommitMatch is the generic results interface from GQL. There is a lot
@@ -0,0 +1,169 @@
+package symbol
+
+import (
+	"context"
+	"sort"
+
+	"github.com/neelance/parallel"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// PreciseSearcher searches the index of symbols defined by precise code intelligence uploads.
+// Returned symbols have the fully qualified name, package, and documentation fields populated.
+type PreciseSearcher interface {
+	SearchPreciseSymbols(ctx context.Context, repositoryID int, args search.SymbolsParameters) ([]result.Symbol, error)
+}
+
+// DefaultPreciseSearcher is the precise symbol searcher used by symbol searches that specify
+// `precise:yes` or `precise:only`. It is nil unless precise code intelligence is enabled
+// (enterprise only), in which case these searches return no precise results.
+var DefaultPreciseSearcher PreciseSearcher
+
+// PreciseSymbolSearchJob searches for symbols defined by precise code intelligence uploads
+// visible from the searched revision of each repository.
+type PreciseSymbolSearchJob struct {
+	PatternInfo *search.TextPatternInfo
+	Repos       []*search.RepositoryRevisions // the set of repositories to search.
+	Limit       int
+}
+
+// Run calls the precise symbol searcher for each repository revision.
+func (s *PreciseSymbolSearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	tr, ctx, stream, finish := job.StartSpan(ctx, stream, s)
+	defer func() { finish(alert, err) }()
+
+	searcher := DefaultPreciseSearcher
+	if searcher == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	run := parallel.NewRun(conf.SearchSymbolsParallelism())
+
+	for _, repoRevs := range s.Repos {
+		repoRevs := repoRevs
+		if ctx.Err() != nil {
+			break
+		}
+		if len(repoRevs.Revs) == 0 {
+			continue
+		}
+		run.Acquire()
+		goroutine.Go(func() {
+			defer run.Release()
+
+			matches, err := searchPreciseInRepo(ctx, clients.Gitserver, searcher, repoRevs, s.PatternInfo, s.Limit)
+			status, limitHit, err := search.HandleRepoSearchResult(repoRevs.Repo.ID, repoRevs.Revs, len(matches) > s.Limit, false, err)
+			stream.Send(streaming.SearchEvent{
+				Results: matches,
+				Stats: streaming.Stats{
+					Status:     status,
+					IsLimitHit: limitHit,
+				},
+			})
+			if err != nil {
+				tr.LogFields(log.String("repo", string(repoRevs.Repo.Name)), log.Error(err))
+				// Only record error if we haven't timed out.
+				if ctx.Err() == nil {
+					cancel()
+					run.Error(err)
+				}
+			}
+		})
+	}
+
+	return nil, run.Wait()
+}
+
+func (s *PreciseSymbolSearchJob) Name() string {
+	return "PreciseSymbolSearchJob"
+}
+
+func (s *PreciseSymbolSearchJob) Fields(v job.Verbosity) (res []log.Field) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			trace.Scoped("patternInfo", s.PatternInfo.Fields()...),
+			log.Int("numRepos", len(s.Repos)),
+			log.Int("limit", s.Limit),
+		)
+	}
+	return res
+}
+
+func (s *PreciseSymbolSearchJob) Children() []job.Describer       { return nil }
+func (s *PreciseSymbolSearchJob) MapChildren(job.MapFunc) job.Job { return s }
+
+func searchPreciseInRepo(ctx context.Context, gitserverClient gitserver.Client, searcher PreciseSearcher, repoRevs *search.RepositoryRevisions, patternInfo *search.TextPatternInfo, limit int) (result.Matches, error) {
+	inputRev := repoRevs.Revs[0]
+	commitID, err := gitserverClient.ResolveRevision(ctx, repoRevs.GitserverRepo(), inputRev, gitserver.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, err := searcher.SearchPreciseSymbols(ctx, int(repoRevs.Repo.ID), search.SymbolsParameters{
+		Repo:            repoRevs.Repo.Name,
+		CommitID:        commitID,
+		Query:           patternInfo.Pattern,
+		IsCaseSensitive: patternInfo.IsCaseSensitive,
+		IsRegExp:        patternInfo.IsRegExp,
+		IncludePatterns: patternInfo.IncludePatterns,
+		ExcludePattern:  patternInfo.ExcludePattern,
+		// Ask for limit + 1 so we can detect whether there are more results than the limit.
+		First: limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return preciseSymbolsToMatches(symbols, repoRevs.Repo, commitID, inputRev), nil
+}
+
+func preciseSymbolsToMatches(symbols []result.Symbol, repo types.MinimalRepo, commitID api.CommitID, inputRev string) result.Matches {
+	symbolsByPath := make(map[string][]result.Symbol)
+	for _, symbol := range symbols {
+		symbolsByPath[symbol.Path] = append(symbolsByPath[symbol.Path], symbol)
+	}
+
+	matches := make(result.Matches, 0, len(symbolsByPath))
+	for path, symbols := range symbolsByPath {
+		file := result.File{
+			Path:     path,
+			Repo:     repo,
+			CommitID: commitID,
+			InputRev: &inputRev,
+		}
+
+		symbolMatches := make([]*result.SymbolMatch, 0, len(symbols))
+		for _, symbol := range symbols {
+			symbolMatches = append(symbolMatches, &result.SymbolMatch{
+				File:   &file,
+				Symbol: symbol,
+			})
+		}
+
+		matches = append(matches, &result.FileMatch{
+			Symbols: symbolMatches,
+			File:    file,
+		})
+	}
+
+	// Make the results deterministic
+	sort.Sort(matches)
+	return matches
+}
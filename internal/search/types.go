@@ -32,6 +32,7 @@ type Inputs struct {
 	Features               *Features
 	Protocol               Protocol
 	SanitizeSearchPatterns []*regexp.Regexp
+	VirtualRepos           []*types.VirtualRepo // virtual repositories substituted in the query
 }
 
 // MaxResults computes the limit for the query.
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// ExplicitPermissionsBulkImportFormat is the format of the data of an explicit
+// permissions bulk import.
+type ExplicitPermissionsBulkImportFormat string
+
+const (
+	// ExplicitPermissionsBulkImportFormatCSV is a CSV document with the columns
+	// user, repo and perm, and an optional header row.
+	ExplicitPermissionsBulkImportFormatCSV ExplicitPermissionsBulkImportFormat = "csv"
+	// ExplicitPermissionsBulkImportFormatJSONL is a document with one JSON object
+	// with the fields user, repo and perm per line.
+	ExplicitPermissionsBulkImportFormatJSONL ExplicitPermissionsBulkImportFormat = "jsonl"
+)
+
+// ExplicitPermissionsBulkImportMode determines how the permissions of an
+// explicit permissions bulk import are combined with the existing permissions
+// of the repositories it matches.
+type ExplicitPermissionsBulkImportMode string
+
+const (
+	// ExplicitPermissionsBulkImportModeMerge adds the users of the document to
+	// the users that already have access to each matched repository.
+	ExplicitPermissionsBulkImportModeMerge ExplicitPermissionsBulkImportMode = "merge"
+	// ExplicitPermissionsBulkImportModeReplace replaces the users that have
+	// access to each matched repository with the users of the document.
+	ExplicitPermissionsBulkImportModeReplace ExplicitPermissionsBulkImportMode = "replace"
+)
+
+// ExplicitPermissionsBulkImportJob represents a task to apply the explicit
+// repository permissions listed in an uploaded document.
+type ExplicitPermissionsBulkImportJob struct {
+	ID              int
+	State           string
+	FailureMessage  *string
+	QueuedAt        time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	ProcessAfter    *time.Time
+	NumResets       int
+	NumFailures     int
+	LastHeartbeatAt time.Time
+	ExecutionLogs   []workerutil.ExecutionLogEntry
+	WorkerHostname  string
+
+	// Format of the payload
+	Format ExplicitPermissionsBulkImportFormat
+	// Whether the permissions are merged with or replace existing permissions
+	Mode ExplicitPermissionsBulkImportMode
+	// Key of the uploaded document in the upload store
+	PayloadKey string
+	// ID of the user that uploaded the document
+	CreatorUserID int32
+	// Number of entries processed, including the ones that failed
+	ProcessedLines int
+	// Number of entries that could not be applied
+	FailedLines int
+}
+
+// RecordID implements workerutil.Record.
+func (j *ExplicitPermissionsBulkImportJob) RecordID() int {
+	return j.ID
+}
+
+// ExplicitPermissionsBulkImportError describes why an entry of an explicit
+// permissions bulk import could not be applied.
+type ExplicitPermissionsBulkImportError struct {
+	// Line is the 1-based line number of the entry in the payload.
+	Line    int
+	Message string
+}
@@ -68,6 +68,7 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 				return "", err
 			}
 		}
+		es.redactString(c.Credentials, "credentials")
 	case *schema.PythonPackagesConnection:
 		for i := range c.Urls {
 			err = es.redactURL(c.Urls[i], "urls", i)
@@ -75,10 +76,18 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 				return "", err
 			}
 		}
+		es.redactString(c.Credentials, "credentials")
 	case *schema.RustPackagesConnection:
-		// Nothing to redact
+		es.redactString(c.Credentials, "credentials")
 	case *schema.RubyPackagesConnection:
 		es.redactString(c.Repository, "repository")
+		es.redactString(c.Credentials, "credentials")
+	case *schema.NuGetPackagesConnection:
+		err = es.redactURL(c.Registry, "registry")
+		if err != nil {
+			return "", err
+		}
+		es.redactString(c.Credentials, "credentials")
 	case *schema.JVMPackagesConnection:
 		if c.Maven != nil {
 			es.redactString(c.Maven.Credentials, "maven", "credentials")
@@ -165,20 +174,33 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
-		err = es.unredactURLs(c.Urls, oldCfg.(*schema.GoModulesConnection).Urls)
+		o := oldCfg.(*schema.GoModulesConnection)
+		err = es.unredactURLs(c.Urls, o.Urls)
 		if err != nil {
 			return err
 		}
+		es.unredactString(c.Credentials, o.Credentials, "credentials")
 	case *schema.PythonPackagesConnection:
-		err = es.unredactURLs(c.Urls, oldCfg.(*schema.PythonPackagesConnection).Urls)
+		o := oldCfg.(*schema.PythonPackagesConnection)
+		err = es.unredactURLs(c.Urls, o.Urls)
 		if err != nil {
 			return err
 		}
+		es.unredactString(c.Credentials, o.Credentials, "credentials")
 	case *schema.RustPackagesConnection:
-		// Nothing to unredact
+		o := oldCfg.(*schema.RustPackagesConnection)
+		es.unredactString(c.Credentials, o.Credentials, "credentials")
 	case *schema.RubyPackagesConnection:
 		o := oldCfg.(*schema.RubyPackagesConnection)
 		es.unredactString(c.Repository, o.Repository, "repository")
+		es.unredactString(c.Credentials, o.Credentials, "credentials")
+	case *schema.NuGetPackagesConnection:
+		o := oldCfg.(*schema.NuGetPackagesConnection)
+		err = es.unredactURL(c.Registry, o.Registry, "registry")
+		if err != nil {
+			return err
+		}
+		es.unredactString(c.Credentials, o.Credentials, "credentials")
 	case *schema.JVMPackagesConnection:
 		o := oldCfg.(*schema.JVMPackagesConnection)
 		if c.Maven != nil && o.Maven != nil {
@@ -499,6 +499,28 @@ func (rs MinimalRepos) Len() int           { return len(rs) }
 func (rs MinimalRepos) Less(i, j int) bool { return rs[i].ID < rs[j].ID }
 func (rs MinimalRepos) Swap(i, j int)      { rs[i], rs[j] = rs[j], rs[i] }
 
+// VirtualRepo is a directory of a repository that is presented as a repository
+// of its own. It shares the gitserver clone of its parent repository.
+type VirtualRepo struct {
+	ID int32
+	// Name is the name under which the virtual repository is listed and searched.
+	Name api.RepoName
+	// Parent is the repository containing the virtual repository.
+	Parent MinimalRepo
+	// PathPrefix is the directory of the parent repository, without leading or
+	// trailing slashes.
+	PathPrefix string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Contains reports whether the given path of the parent repository is part of
+// the virtual repository.
+func (v *VirtualRepo) Contains(path string) bool {
+	path = strings.TrimPrefix(path, "/")
+	return path == v.PathPrefix || strings.HasPrefix(path, v.PathPrefix+"/")
+}
+
 type CodeHostRepository struct {
 	Name       string
 	CodeHostID int64
@@ -788,6 +810,53 @@ type User struct {
 	Searchable            bool
 }
 
+// Role is a named set of permissions on site-level features that can be
+// assigned to users.
+type Role struct {
+	ID          int32
+	Name        string
+	Permissions []string
+	// System is true for the roles that are built into Sourcegraph.
+	System    bool
+	CreatedAt time.Time
+}
+
+// UserSession is the metadata of a cookie-based session of a user.
+type UserSession struct {
+	ID     int32
+	UserID int32
+	// SessionID is the random identifier stored in the session data. It must
+	// never be exposed to users.
+	SessionID string
+	// AuthProvider is the type of the authentication provider the user signed
+	// in with, e.g. "builtin" or "github". It is empty for sessions created
+	// before session metadata was recorded.
+	AuthProvider string
+	IPAddress    string
+	UserAgent    string
+	CreatedAt    time.Time
+	LastSeenAt   time.Time
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+}
+
+// SubRepoPathRule is a set of sub-repository permissions rules defined by a site
+// admin for a repository, which applies to a single user, the members of an
+// organization or the users with a role, regardless of the code host.
+type SubRepoPathRule struct {
+	ID     int32
+	RepoID api.RepoID
+	// Exactly one of UserID, OrgID and RoleID is set.
+	UserID int32
+	OrgID  int32
+	RoleID int32
+	// Paths are glob patterns relative to the root of the repository. Paths that
+	// begin with a minus sign (-) are exclusion paths.
+	Paths     []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type OrgMemberAutocompleteSearchItem struct {
 	ID          int32
 	Username    string
@@ -1663,6 +1732,9 @@ type SearchContext struct {
 type SearchContextRepositoryRevisions struct {
 	Repo      MinimalRepo
 	Revisions []string
+	// VirtualRepo is set if the search context includes only the directory of
+	// a virtual repository of Repo, rather than all of Repo.
+	VirtualRepo *VirtualRepo
 }
 
 type EncryptableSecret = encryption.Encryptable
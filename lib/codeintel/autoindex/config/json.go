@@ -38,6 +38,17 @@ func MarshalJSON(config IndexConfiguration) ([]byte, error) {
 				nonNil.IndexJobs[idx].Steps[stepIdx].Commands = []string{}
 			}
 		}
+		if nonNil.IndexJobs[idx].Caches == nil {
+			nonNil.IndexJobs[idx].Caches = []Cache{}
+		}
+		for cacheIdx := range nonNil.IndexJobs[idx].Caches {
+			if nonNil.IndexJobs[idx].Caches[cacheIdx].KeyFiles == nil {
+				nonNil.IndexJobs[idx].Caches[cacheIdx].KeyFiles = []string{}
+			}
+			if nonNil.IndexJobs[idx].Caches[cacheIdx].Paths == nil {
+				nonNil.IndexJobs[idx].Caches[cacheIdx].Paths = []string{}
+			}
+		}
 	}
 
 	return json.MarshalIndent(nonNil, "", "    ")
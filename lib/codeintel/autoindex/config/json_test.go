@@ -28,6 +28,13 @@ const jsonTestInput = `
 			],
 			"indexer": "lsif-go",
 			"indexer_args": ["--no-animation"],
+			"caches": [
+				{
+					"key": "go-mod",
+					"key_files": ["go.sum"],
+					"paths": ["/root/go/pkg/mod"],
+				},
+			],
 		},
 		{
 			"root": "web/",
@@ -64,6 +71,13 @@ func TestUnmarshalJSON(t *testing.T) {
 				},
 				Indexer:     "lsif-go",
 				IndexerArgs: []string{"--no-animation"},
+				Caches: []Cache{
+					{
+						Key:      "go-mod",
+						KeyFiles: []string{"go.sum"},
+						Paths:    []string{"/root/go/pkg/mod"},
+					},
+				},
 			},
 			{
 				Steps:       nil,
@@ -12,6 +12,7 @@ type IndexJob struct {
 	Indexer     string       `json:"indexer" yaml:"indexer"`
 	IndexerArgs []string     `json:"indexer_args" yaml:"indexer_args"`
 	Outfile     string       `json:"outfile" yaml:"outfile"`
+	Caches      []Cache      `json:"caches" yaml:"caches"`
 }
 
 type DockerStep struct {
@@ -20,6 +21,22 @@ type DockerStep struct {
 	Commands []string `json:"commands" yaml:"commands"`
 }
 
+// Cache declares a set of directories that are persisted by the executor between index jobs of
+// the same repository. The cache entry is identified by the key and the content of the key files
+// (e.g. lockfiles), so that it is invalidated whenever the dependencies of the project change.
+type Cache struct {
+	// Key is a name that distinguishes this cache from the others of the same repository.
+	Key string `json:"key" yaml:"key"`
+
+	// KeyFiles is a list of paths relative to the index job root whose contents are hashed
+	// into the cache key.
+	KeyFiles []string `json:"key_files" yaml:"key_files"`
+
+	// Paths is a list of absolute directory paths within the step and indexer containers that
+	// are restored before the index job runs and saved after it succeeds.
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
 type HintConfidence int
 
 const (
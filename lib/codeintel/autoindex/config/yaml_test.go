@@ -22,6 +22,12 @@ index_jobs:
     indexer: lsif-go
     indexer_args:
       - --no-animation
+    caches:
+      - key: go-mod
+        key_files:
+          - go.sum
+        paths:
+          - /root/go/pkg/mod
   -
     root: web/
     indexer: scip-typescript
@@ -54,6 +60,13 @@ func TestUnmarshalYAML(t *testing.T) {
 				},
 				Indexer:     "lsif-go",
 				IndexerArgs: []string{"--no-animation"},
+				Caches: []Cache{
+					{
+						Key:      "go-mod",
+						KeyFiles: []string{"go.sum"},
+						Paths:    []string{"/root/go/pkg/mod"},
+					},
+				},
 			},
 			{
 				Steps:       nil,
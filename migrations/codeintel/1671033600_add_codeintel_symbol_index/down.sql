@@ -0,0 +1 @@
+DROP TABLE IF EXISTS codeintel_symbol_index;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS codeintel_symbol_index (
+    upload_id integer NOT NULL,
+    scheme text NOT NULL,
+    symbol_name text NOT NULL,
+    name text NOT NULL,
+    kind text NOT NULL,
+    package_manager text NOT NULL,
+    package_name text NOT NULL,
+    package_version text NOT NULL,
+    path text NOT NULL,
+    start_line integer NOT NULL,
+    start_character integer NOT NULL,
+    end_line integer NOT NULL,
+    end_character integer NOT NULL,
+    documentation text NOT NULL
+);
+
+COMMENT ON TABLE codeintel_symbol_index IS 'A searchable index of the symbols defined (and exported) by a particular precise code intelligence index. Rows are written by the precise-code-intel-worker and are used by precise symbol search (`type:symbol precise:yes`).';
+COMMENT ON COLUMN codeintel_symbol_index.upload_id IS 'The identifier of the upload that provided this symbol.';
+COMMENT ON COLUMN codeintel_symbol_index.scheme IS 'The moniker scheme of the symbol (e.g., `gomod` or `scip-typescript`).';
+COMMENT ON COLUMN codeintel_symbol_index.symbol_name IS 'The fully qualified symbol name (the moniker identifier).';
+COMMENT ON COLUMN codeintel_symbol_index.name IS 'The display name of the symbol, which is matched by symbol search queries.';
+COMMENT ON COLUMN codeintel_symbol_index.kind IS 'The kind of the symbol derived from its fully qualified name, or an empty string if it cannot be determined.';
+COMMENT ON COLUMN codeintel_symbol_index.package_manager IS 'The manager of the package providing this symbol.';
+COMMENT ON COLUMN codeintel_symbol_index.package_name IS 'The name of the package providing this symbol.';
+COMMENT ON COLUMN codeintel_symbol_index.package_version IS 'The version of the package providing this symbol.';
+COMMENT ON COLUMN codeintel_symbol_index.path IS 'The path of the document defining this symbol, relative to the repository root.';
+COMMENT ON COLUMN codeintel_symbol_index.documentation IS 'The hover text attached to the symbol definition.';
+
+CREATE INDEX IF NOT EXISTS codeintel_symbol_index_upload_id ON codeintel_symbol_index(upload_id);
+CREATE INDEX IF NOT EXISTS codeintel_symbol_index_name_trgm ON codeintel_symbol_index USING gin (name gin_trgm_ops);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS repo_name_history (
+    id SERIAL PRIMARY KEY,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    external_service_id bigint REFERENCES external_services(id) ON DELETE SET NULL,
+    event text NOT NULL,
+    from_name citext NOT NULL,
+    to_name citext,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS repo_name_history_from_name ON repo_name_history USING btree (from_name);
+CREATE INDEX IF NOT EXISTS repo_name_history_external_service_id_created_at ON repo_name_history USING btree (external_service_id, created_at);
+
+COMMENT ON TABLE repo_name_history IS 'Renames, archivals and deletions of repositories observed by the repo syncer.';
+COMMENT ON COLUMN repo_name_history.event IS 'One of renamed, archived, unarchived or deleted.';
+COMMENT ON COLUMN repo_name_history.from_name IS 'The name of the repository before the event.';
+COMMENT ON COLUMN repo_name_history.to_name IS 'The name of the repository after a rename. Null for all other events.';
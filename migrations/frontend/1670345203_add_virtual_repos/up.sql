@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS virtual_repos (
+    id SERIAL PRIMARY KEY,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    name citext NOT NULL,
+    path_prefix text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT virtual_repos_name_unique UNIQUE (name),
+    CONSTRAINT virtual_repos_repo_id_path_prefix_unique UNIQUE (repo_id, path_prefix)
+);
+
+COMMENT ON TABLE virtual_repos IS 'Path prefixes of repositories that are presented as repositories of their own, sharing the clone of their parent repository.';
+COMMENT ON COLUMN virtual_repos.repo_id IS 'The repository containing the virtual repository.';
+COMMENT ON COLUMN virtual_repos.path_prefix IS 'The directory of the parent repository the virtual repository consists of, without leading or trailing slashes.';
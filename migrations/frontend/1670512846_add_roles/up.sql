@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name text NOT NULL,
+    permissions text[] DEFAULT '{}'::text[] NOT NULL,
+    system boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS roles_name_unique ON roles USING btree (name);
+
+COMMENT ON TABLE roles IS 'Roles grant permissions on site-level features to users that are not site admins.';
+COMMENT ON COLUMN roles.permissions IS 'The permissions granted by the role, such as batch_changes:admin.';
+COMMENT ON COLUMN roles.system IS 'Whether the role is built into Sourcegraph and cannot be modified.';
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id integer NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    source text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_roles_role_id ON user_roles USING btree (role_id);
+
+COMMENT ON COLUMN user_roles.source IS 'The authentication provider that assigned the role based on group claims, or null if it was assigned by a site admin.';
+
+INSERT INTO roles (name, permissions, system)
+VALUES
+    ('batch-changes-admin', '{batch_changes:admin}', true),
+    ('code-insights-editor', '{code_insights:edit}', true),
+    ('executor-secret-manager', '{executor_secrets:manage}', true)
+ON CONFLICT (name) DO NOTHING;
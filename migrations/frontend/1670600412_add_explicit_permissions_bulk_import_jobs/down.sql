@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS explicit_permissions_bulk_import_errors;
+DROP TABLE IF EXISTS explicit_permissions_bulk_import_jobs;
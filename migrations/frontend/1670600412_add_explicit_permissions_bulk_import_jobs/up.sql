@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS explicit_permissions_bulk_import_jobs (
+    id SERIAL PRIMARY KEY,
+    state text DEFAULT 'queued'::text,
+    failure_message text,
+    queued_at timestamp with time zone DEFAULT now(),
+    started_at timestamp with time zone,
+    finished_at timestamp with time zone,
+    process_after timestamp with time zone,
+    num_resets integer DEFAULT 0 NOT NULL,
+    num_failures integer DEFAULT 0 NOT NULL,
+    last_heartbeat_at timestamp with time zone,
+    execution_logs json[],
+    worker_hostname text DEFAULT ''::text NOT NULL,
+    cancel boolean DEFAULT false NOT NULL,
+    format text NOT NULL,
+    mode text DEFAULT 'merge'::text NOT NULL,
+    payload_key text NOT NULL,
+    creator_user_id integer REFERENCES users(id) ON DELETE SET NULL,
+    processed_lines integer DEFAULT 0 NOT NULL,
+    failed_lines integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS explicit_permissions_bulk_import_jobs_state_idx ON explicit_permissions_bulk_import_jobs USING btree (state);
+
+COMMENT ON TABLE explicit_permissions_bulk_import_jobs IS 'Uploaded documents of explicit repository permissions that are applied by the worker.';
+COMMENT ON COLUMN explicit_permissions_bulk_import_jobs.format IS 'The format of the payload, either csv or jsonl.';
+COMMENT ON COLUMN explicit_permissions_bulk_import_jobs.mode IS 'Whether the permissions of the document are merged with the existing permissions of the repositories (merge) or replace them (replace).';
+COMMENT ON COLUMN explicit_permissions_bulk_import_jobs.payload_key IS 'The key of the uploaded document in the upload store. The document has one (user, repo pattern, permission) entry per line.';
+
+CREATE TABLE IF NOT EXISTS explicit_permissions_bulk_import_errors (
+    id SERIAL PRIMARY KEY,
+    job_id integer NOT NULL REFERENCES explicit_permissions_bulk_import_jobs(id) ON DELETE CASCADE,
+    line integer NOT NULL,
+    message text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS explicit_permissions_bulk_import_errors_job_id_idx ON explicit_permissions_bulk_import_errors USING btree (job_id);
+
+COMMENT ON TABLE explicit_permissions_bulk_import_errors IS 'Entries of explicit permissions bulk imports that could not be applied.';
+COMMENT ON COLUMN explicit_permissions_bulk_import_errors.line IS 'The 1-based line number of the entry in the payload.';
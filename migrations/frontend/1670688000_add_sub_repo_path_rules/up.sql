@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS sub_repo_path_rules (
+    id SERIAL PRIMARY KEY,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    user_id integer REFERENCES users(id) ON DELETE CASCADE,
+    org_id integer REFERENCES orgs(id) ON DELETE CASCADE,
+    role_id integer REFERENCES roles(id) ON DELETE CASCADE,
+    paths text[] NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT sub_repo_path_rules_single_subject CHECK (num_nonnulls(user_id, org_id, role_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sub_repo_path_rules_repo_id_user_id ON sub_repo_path_rules USING btree (repo_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS sub_repo_path_rules_repo_id_org_id ON sub_repo_path_rules USING btree (repo_id, org_id) WHERE org_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS sub_repo_path_rules_repo_id_role_id ON sub_repo_path_rules USING btree (repo_id, role_id) WHERE role_id IS NOT NULL;
+
+COMMENT ON TABLE sub_repo_path_rules IS 'Sub-repository permissions defined by site admins for a user, an organization or the users with a role, independently of the code host.';
+COMMENT ON COLUMN sub_repo_path_rules.paths IS 'Paths that begin with a minus sign (-) are exclusion paths.';
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id SERIAL PRIMARY KEY,
+    session_id text NOT NULL,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    auth_provider text DEFAULT ''::text NOT NULL,
+    ip_address text,
+    user_agent text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    last_seen_at timestamp with time zone DEFAULT now() NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_sessions_session_id ON user_sessions USING btree (session_id);
+CREATE INDEX IF NOT EXISTS user_sessions_user_id ON user_sessions USING btree (user_id);
+
+COMMENT ON TABLE user_sessions IS 'Metadata of the cookie-based sessions of users. The session data itself is stored in Redis.';
+COMMENT ON COLUMN user_sessions.session_id IS 'Random identifier stored in the session data. It is never exposed through the API.';
+COMMENT ON COLUMN user_sessions.revoked_at IS 'Revoked sessions are kept until they expire, so that they cannot be registered again.';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS access_tokens_expires_at;
+
+ALTER TABLE access_tokens
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS expiry_notified_at;
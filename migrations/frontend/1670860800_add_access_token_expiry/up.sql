@@ -0,0 +1,9 @@
+ALTER TABLE access_tokens
+    ADD COLUMN IF NOT EXISTS expires_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS expiry_notified_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS access_tokens_expires_at ON access_tokens USING btree (expires_at) WHERE deleted_at IS NULL AND expires_at IS NOT NULL;
+
+COMMENT ON COLUMN access_tokens.expires_at IS 'The time after which the token can no longer be used. Tokens without an expiry date never expire.';
+
+COMMENT ON COLUMN access_tokens.expiry_notified_at IS 'The time the subject user was notified that the token is about to expire.';
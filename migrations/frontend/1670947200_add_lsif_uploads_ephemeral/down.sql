@@ -0,0 +1,39 @@
+DROP VIEW IF EXISTS lsif_uploads_with_repository_name;
+
+CREATE VIEW lsif_uploads_with_repository_name AS
+SELECT u.id,
+    u.commit,
+    u.root,
+    u.queued_at,
+    u.uploaded_at,
+    u.state,
+    u.failure_message,
+    u.started_at,
+    u.finished_at,
+    u.repository_id,
+    u.indexer,
+    u.indexer_version,
+    u.num_parts,
+    u.uploaded_parts,
+    u.process_after,
+    u.num_resets,
+    u.upload_size,
+    u.num_failures,
+    u.associated_index_id,
+    u.expired,
+    u.last_retention_scan_at,
+    r.name AS repository_name,
+    u.uncompressed_size
+FROM lsif_uploads u
+JOIN repo r ON r.id = u.repository_id
+WHERE r.deleted_at IS NULL;
+
+-- Ephemeral uploads cannot be represented without the column; queue them for deletion so
+-- they do not collide with the restored unique index.
+UPDATE lsif_uploads SET state = 'deleting' WHERE ephemeral AND state = 'completed';
+
+DROP INDEX IF EXISTS lsif_uploads_repository_id_commit_root_indexer;
+CREATE UNIQUE INDEX IF NOT EXISTS lsif_uploads_repository_id_commit_root_indexer ON lsif_uploads USING btree (repository_id, commit, root, indexer) WHERE state = 'completed'::text;
+
+ALTER TABLE lsif_uploads
+DROP COLUMN IF EXISTS ephemeral;
@@ -0,0 +1,39 @@
+ALTER TABLE lsif_uploads
+ADD COLUMN IF NOT EXISTS ephemeral boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN lsif_uploads.ephemeral IS 'Whether or not this upload overlays a single commit (e.g. a pull request head). Ephemeral uploads are excluded from the commit graph, are only visible from their own commit, and are only protected by the data retention policies matching their own commit.';
+
+-- Ephemeral and non-ephemeral uploads for the same commit, root, and indexer may coexist
+DROP INDEX IF EXISTS lsif_uploads_repository_id_commit_root_indexer;
+CREATE UNIQUE INDEX IF NOT EXISTS lsif_uploads_repository_id_commit_root_indexer ON lsif_uploads USING btree (repository_id, commit, root, indexer, ephemeral) WHERE state = 'completed'::text;
+
+DROP VIEW IF EXISTS lsif_uploads_with_repository_name;
+
+CREATE VIEW lsif_uploads_with_repository_name AS
+SELECT u.id,
+    u.commit,
+    u.root,
+    u.queued_at,
+    u.uploaded_at,
+    u.state,
+    u.failure_message,
+    u.started_at,
+    u.finished_at,
+    u.repository_id,
+    u.indexer,
+    u.indexer_version,
+    u.num_parts,
+    u.uploaded_parts,
+    u.process_after,
+    u.num_resets,
+    u.upload_size,
+    u.num_failures,
+    u.associated_index_id,
+    u.expired,
+    u.last_retention_scan_at,
+    r.name AS repository_name,
+    u.uncompressed_size,
+    u.ephemeral
+FROM lsif_uploads u
+JOIN repo r ON r.id = u.repository_id
+WHERE r.deleted_at IS NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS search_context_virtual_repos (
+    search_context_id bigint NOT NULL REFERENCES search_contexts(id) ON DELETE CASCADE,
+    virtual_repo_id integer NOT NULL REFERENCES virtual_repos(id) ON DELETE CASCADE,
+    revision text NOT NULL,
+    CONSTRAINT search_context_virtual_repos_unique UNIQUE (virtual_repo_id, search_context_id, revision)
+);
+
+COMMENT ON TABLE search_context_virtual_repos IS 'Virtual repositories and revisions included in search contexts. The search context only covers the directory of the parent repository the virtual repository consists of.';
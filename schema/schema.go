@@ -82,6 +82,10 @@ type AuditLog struct {
 type AuthAccessTokens struct {
 	// Allow description: Allow or restrict the use of access tokens. The default is "all-users-create", which enables all users to create access tokens. Use "none" to disable access tokens entirely. Use "site-admin-create" to restrict creation of new tokens to admin users (existing tokens will still work until revoked).
 	Allow string `json:"allow,omitempty"`
+	// ExpiryNotificationDays description: The number of days before an access token expires that its owner is notified by email.
+	ExpiryNotificationDays int `json:"expiryNotificationDays,omitempty"`
+	// MaxLifetimeDays description: The maximum number of days an access token is valid for. Tokens are created with this lifetime unless a shorter one is requested, and cannot be created without an expiry date. Existing tokens without an expiry date are rejected once they are older than this. By default, tokens never expire.
+	MaxLifetimeDays int `json:"maxLifetimeDays,omitempty"`
 }
 
 // AuthLockout description: The config options for account lockout
@@ -649,6 +653,8 @@ type ExperimentalFeatures struct {
 	JvmPackages string `json:"jvmPackages,omitempty"`
 	// NpmPackages description: Allow adding npm package code host connections
 	NpmPackages string `json:"npmPackages,omitempty"`
+	// NugetPackages description: Allow adding NuGet package host connections
+	NugetPackages string `json:"nugetPackages,omitempty"`
 	// Pagure description: Allow adding Pagure code host connections
 	Pagure string `json:"pagure,omitempty"`
 	// PasswordPolicy description: DEPRECATED: this is now a standard feature see: auth.passwordPolicy
@@ -1026,6 +1032,10 @@ type GitoliteConnection struct {
 
 // GoModulesConnection description: Configuration for a connection to Go module proxies
 type GoModulesConnection struct {
+	// Certificate description: TLS certificate of the Go module proxies. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// Credentials description: Credentials for the Go module proxies. Credentials of the form "username:password" are sent using HTTP basic authentication, any other value is sent as a bearer token. Credentials are only sent to the hosts of the configured URLs.
+	Credentials string `json:"credentials,omitempty"`
 	// Dependencies description: An array of strings specifying Go modules to mirror in Sourcegraph.
 	Dependencies []string `json:"dependencies,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to the configured Go module proxies.
@@ -1147,7 +1157,9 @@ type Log struct {
 
 // Maven description: Configuration for resolving from Maven repositories.
 type Maven struct {
-	// Credentials description: Contents of a coursier.credentials file needed for accessing the Maven repositories.
+	// Certificate description: TLS certificate of the Maven repositories. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// Credentials description: Contents of a coursier.credentials file needed for accessing the Maven repositories. Each line holds the credentials of one repository host, e.g. `artifactory.mycompany.com username:password`. A single `username:password` value applies to all repositories.
 	Credentials string `json:"credentials,omitempty"`
 	// Dependencies description: An array of artifact "groupID:artifactID:version" strings specifying which Maven artifacts to mirror on Sourcegraph.
 	Dependencies []string `json:"dependencies,omitempty"`
@@ -1296,6 +1308,8 @@ type NotifierWebhook struct {
 
 // NpmPackagesConnection description: Configuration for a connection to an npm packages repository.
 type NpmPackagesConnection struct {
+	// Certificate description: TLS certificate of the npm registry. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
 	// Credentials description: Access token for logging into the npm registry.
 	Credentials string `json:"credentials,omitempty"`
 	// Dependencies description: An array of "(@scope/)?packageName@version" strings specifying which npm packages to mirror on Sourcegraph.
@@ -1313,6 +1327,28 @@ type NpmRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+
+// NuGetPackagesConnection description: Configuration for a connection to NuGet package sources implementing the NuGet V3 API
+type NuGetPackagesConnection struct {
+	// Certificate description: TLS certificate of the NuGet package source. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// Credentials description: Credentials for the NuGet package source. Credentials of the form "username:password" are sent using HTTP basic authentication, any other value is sent as a bearer token. Credentials are only sent to the host of the service index and the host of its PackageBaseAddress resource.
+	Credentials string `json:"credentials,omitempty"`
+	// Dependencies description: An array of "packageID@version" strings specifying which NuGet packages to mirror on Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the NuGet package source.
+	RateLimit *NuGetRateLimit `json:"rateLimit,omitempty"`
+	// Registry description: The URL of the service index of the NuGet V3 package source.
+	Registry string `json:"registry,omitempty"`
+}
+
+// NuGetRateLimit description: Rate limit applied when making background API requests to the NuGet package source.
+type NuGetRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
 type OAuthIdentity struct {
 	Type string `json:"type"`
 }
@@ -1375,13 +1411,17 @@ type OpenIDConnectAuthProvider struct {
 	// ConfigID description: An identifier that can be used to reference this authentication provider in other parts of the config. For example, in configuration for a code host, you may want to designate this authentication provider as the identity provider for the code host.
 	ConfigID    string `json:"configID,omitempty"`
 	DisplayName string `json:"displayName,omitempty"`
+	// GroupsClaimName description: Name of the claim that holds the groups the user belongs to, used for the roleGroups setting.
+	GroupsClaimName string `json:"groupsClaimName,omitempty"`
 	// Issuer description: The URL of the OpenID Connect issuer.
 	//
 	// For Google Apps: https://accounts.google.com
 	Issuer string `json:"issuer"`
 	// RequireEmailDomain description: Only allow users to authenticate if their email domain is equal to this value (example: mycompany.com). Do not include a leading "@". If not set, all users on this OpenID Connect provider can authenticate to Sourcegraph.
 	RequireEmailDomain string `json:"requireEmailDomain,omitempty"`
-	Type               string `json:"type"`
+	// RoleGroups description: Assigns roles to users based on the groups in the `groupsClaimName` claim. Each key is the name of a role and the value the list of groups whose members are assigned the role. Roles are updated every time a user signs in.
+	RoleGroups map[string][]string `json:"roleGroups,omitempty"`
+	Type       string              `json:"type"`
 }
 
 // OpenTelemetry description: Configuration for the client OpenTelemetry exporter
@@ -1474,6 +1514,8 @@ type PasswordPolicy struct {
 
 // PerforceAuthorization description: If non-null, enforces Perforce depot permissions.
 type PerforceAuthorization struct {
+	// ClientHost description: The IP address of the client host that protection rules restricted to specific hosts are evaluated against, typically the address Sourcegraph connects to the Perforce Server from. If unset, the host field of protection rules is ignored and every rule applies.
+	ClientHost string `json:"clientHost,omitempty"`
 	// SubRepoPermissions description: Experimental: infer sub-repository permissions from protection rules.
 	SubRepoPermissions bool `json:"subRepoPermissions,omitempty"`
 }
@@ -1542,6 +1584,10 @@ type PhabricatorConnection struct {
 
 // PythonPackagesConnection description: Configuration for a connection to Python simple repository APIs compatible with PEP 503
 type PythonPackagesConnection struct {
+	// Certificate description: TLS certificate of the Python simple repositories. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// Credentials description: Credentials for the Python simple repositories. Credentials of the form "username:password" are sent using HTTP basic authentication, any other value is sent as a bearer token. Credentials are only sent to the hosts of the configured URLs.
+	Credentials string `json:"credentials,omitempty"`
 	// Dependencies description: An array of strings specifying Python packages to mirror in Sourcegraph.
 	Dependencies []string `json:"dependencies,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to the configured Python simple repository APIs.
@@ -1610,6 +1656,10 @@ type Responders struct {
 
 // RubyPackagesConnection description: Configuration for a connection to Ruby packages
 type RubyPackagesConnection struct {
+	// Certificate description: TLS certificate of the Ruby repository. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// Credentials description: Credentials for the Ruby repository. Credentials of the form "username:password" are sent using HTTP basic authentication, any other value is sent as a bearer token. Credentials are only sent to the hosts of the configured URLs.
+	Credentials string `json:"credentials,omitempty"`
 	// Dependencies description: An array of strings specifying Ruby packages to mirror in Sourcegraph.
 	Dependencies []string `json:"dependencies,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to the configured Ruby repository APIs.
@@ -1628,6 +1678,10 @@ type RubyRateLimit struct {
 
 // RustPackagesConnection description: Configuration for a connection to Rust packages
 type RustPackagesConnection struct {
+	// Certificate description: TLS certificate of the Rust registry. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	// Credentials description: Credentials for the Rust registry. Credentials of the form "username:password" are sent using HTTP basic authentication, any other value is sent as a bearer token. Credentials are only sent to the hosts of the configured URLs.
+	Credentials string `json:"credentials,omitempty"`
 	// Dependencies description: An array of strings specifying Rust packages to mirror in Sourcegraph.
 	Dependencies []string `json:"dependencies,omitempty"`
 	// IndexRepositoryName description: Name of the git repository containing the crates.io index. Empty by default, which means no syncing happens. Updating this setting does not trigger a sync immediately, you must wait until the next scheduled sync for the value to get picked up.
@@ -1636,6 +1690,8 @@ type RustPackagesConnection struct {
 	IndexRepositorySyncInterval string `json:"indexRepositorySyncInterval,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to the configured Rust repository APIs.
 	RateLimit *RustRateLimit `json:"rateLimit,omitempty"`
+	// Registry description: The URL from which crates are downloaded.
+	Registry string `json:"registry,omitempty"`
 }
 
 // RustRateLimit description: Rate limit applied when making background API requests to the configured Rust repository APIs.
@@ -1657,7 +1713,7 @@ type SAMLAuthProvider struct {
 	// ConfigID description: An identifier that can be used to reference this authentication provider in other parts of the config. For example, in configuration for a code host, you may want to designate this authentication provider as the identity provider for the code host.
 	ConfigID    string `json:"configID,omitempty"`
 	DisplayName string `json:"displayName,omitempty"`
-	// GroupsAttributeName description: Name of the SAML assertion attribute that holds group membership for allowGroups setting
+	// GroupsAttributeName description: Name of the SAML assertion attribute that holds group membership for allowGroups and roleGroups settings
 	GroupsAttributeName string `json:"groupsAttributeName,omitempty"`
 	// IdentityProviderMetadata description: The SAML Identity Provider metadata XML contents (for static configuration of the SAML Service Provider). The value of this field should be an XML document whose root element is `<EntityDescriptor>` or `<EntityDescriptors>`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	IdentityProviderMetadata string `json:"identityProviderMetadata,omitempty"`
@@ -1667,6 +1723,8 @@ type SAMLAuthProvider struct {
 	InsecureSkipAssertionSignatureValidation bool `json:"insecureSkipAssertionSignatureValidation,omitempty"`
 	// NameIDFormat description: The SAML NameID format to use when performing user authentication.
 	NameIDFormat string `json:"nameIDFormat,omitempty"`
+	// RoleGroups description: Assigns roles to users based on the groups in the `groupsAttributeName` attribute. Each key is the name of a role and the value the list of groups whose members are assigned the role. Roles are updated every time a user signs in.
+	RoleGroups map[string][]string `json:"roleGroups,omitempty"`
 	// ServiceProviderCertificate description: The SAML Service Provider certificate in X.509 encoding (begins with "-----BEGIN CERTIFICATE-----"). This certificate is used by the Identity Provider to validate the Service Provider's AuthnRequests and LogoutRequests. It corresponds to the Service Provider's private key (`serviceProviderPrivateKey`). To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	ServiceProviderCertificate string `json:"serviceProviderCertificate,omitempty"`
 	// ServiceProviderIssuer description: The SAML Service Provider name, used to identify this Service Provider. This is required if the "externalURL" field is not set (as the SAML metadata endpoint is computed as "<externalURL>.auth/saml/metadata"), or when using multiple SAML authentication providers.